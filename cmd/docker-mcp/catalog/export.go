@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
@@ -15,7 +16,14 @@ import (
 
 // Export exports a configured catalog to a file
 // This function only allows exporting user-managed catalogs, not the Docker catalog
-func Export(_ context.Context, catalogName, outputPath string) error {
+// When only is not empty, only the servers whose name matches this glob pattern are exported
+func Export(_ context.Context, catalogName, outputPath, only string) error {
+	if only != "" {
+		if _, err := path.Match(only, ""); err != nil {
+			return fmt.Errorf("invalid server name pattern %q: %w", only, err)
+		}
+	}
+
 	// Validate that we're not trying to export the Docker catalog
 	if catalogName == DockerCatalogName || catalogName == DockerCatalogFilename {
 		return fmt.Errorf("cannot export the Docker MCP catalog as it is managed by Docker")
@@ -59,6 +67,13 @@ func Export(_ context.Context, catalogName, outputPath string) error {
 		return fmt.Errorf("failed to parse catalog: %w", err)
 	}
 
+	if only != "" {
+		catalogData, err = filterCatalogServers(catalogData, only)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create output directory if it doesn't exist
 	outputDir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(outputDir, 0o755); err != nil {
@@ -74,6 +89,46 @@ func Export(_ context.Context, catalogName, outputPath string) error {
 	return nil
 }
 
+// filterCatalogServers keeps only the servers of a catalog file whose name matches
+// the pattern. The other fields of the catalog and of the servers, including the
+// secrets they reference, are kept untouched so that the subset is self-contained.
+func filterCatalogServers(catalogData []byte, pattern string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(catalogData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("no servers match %q", pattern)
+	}
+
+	matched := 0
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "registry" || root.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+
+		registry := root.Content[i+1]
+		var kept []*yaml.Node
+		for j := 0; j+1 < len(registry.Content); j += 2 {
+			if ok, _ := path.Match(pattern, registry.Content[j].Value); ok {
+				kept = append(kept, registry.Content[j], registry.Content[j+1])
+			}
+		}
+		registry.Content = kept
+		matched += len(kept) / 2
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("no servers match %q", pattern)
+	}
+
+	filtered, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	return filtered, nil
+}
+
 // Helper function to get configured catalogs (same logic as in internal/catalog)
 func getConfiguredCatalogs() ([]string, error) {
 	homeDir, err := user.HomeDir()
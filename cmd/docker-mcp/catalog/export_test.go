@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestExportOnly(t *testing.T) {
+	catalogsDir := setupTestCatalogForExport(t)
+	outputPath := filepath.Join(catalogsDir, "db-servers.yaml")
+
+	err := Export(t.Context(), "my-catalog", outputPath, "db-*")
+	require.NoError(t, err)
+
+	exported, name, displayName, err := catalog.ReadOne(t.Context(), outputPath)
+	require.NoError(t, err)
+	assert.Len(t, exported.Servers, 2)
+	assert.Contains(t, exported.Servers, "db-postgres")
+	assert.Contains(t, exported.Servers, "db-redis")
+	assert.NotContains(t, exported.Servers, "web-fetch")
+
+	// The secrets referenced by the exported servers are kept
+	require.Len(t, exported.Servers["db-postgres"].Secrets, 1)
+	assert.Equal(t, "db-postgres.password", exported.Servers["db-postgres"].Secrets[0].Name)
+
+	// The catalog metadata is kept
+	assert.Equal(t, "my-catalog", name)
+	assert.Equal(t, "My Custom Catalog", displayName)
+}
+
+func TestExportAll(t *testing.T) {
+	catalogsDir := setupTestCatalogForExport(t)
+	outputPath := filepath.Join(catalogsDir, "all-servers.yaml")
+
+	err := Export(t.Context(), "my-catalog", outputPath, "")
+	require.NoError(t, err)
+
+	exported, _, _, err := catalog.ReadOne(t.Context(), outputPath)
+	require.NoError(t, err)
+	assert.Len(t, exported.Servers, 3)
+}
+
+func TestExportOnlyNoMatch(t *testing.T) {
+	catalogsDir := setupTestCatalogForExport(t)
+	outputPath := filepath.Join(catalogsDir, "none.yaml")
+
+	err := Export(t.Context(), "my-catalog", outputPath, "cache-*")
+	require.ErrorContains(t, err, `no servers match "cache-*"`)
+	assert.NoFileExists(t, outputPath)
+}
+
+func TestExportOnlyInvalidPattern(t *testing.T) {
+	catalogsDir := setupTestCatalogForExport(t)
+	outputPath := filepath.Join(catalogsDir, "invalid.yaml")
+
+	err := Export(t.Context(), "my-catalog", outputPath, "db-[")
+	require.ErrorContains(t, err, "invalid server name pattern")
+	assert.NoFileExists(t, outputPath)
+}
+
+// Helper function to set up a user catalog for export testing, returning the catalogs directory
+func setupTestCatalogForExport(t *testing.T) string {
+	t.Helper()
+
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+
+	mcpDir := filepath.Join(tempHome, ".docker", "mcp")
+	catalogsDir := filepath.Join(mcpDir, "catalogs")
+	require.NoError(t, os.MkdirAll(catalogsDir, 0o755))
+
+	catalogRegistry := `{
+  "catalogs": {
+    "my-catalog": {
+      "displayName": "My Custom Catalog",
+      "url": "",
+      "lastUpdate": "2025-08-01T00:00:00Z"
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(mcpDir, "catalog.json"), []byte(catalogRegistry), 0o644))
+
+	customCatalog := `name: my-catalog
+displayName: My Custom Catalog
+registry:
+  db-postgres:
+    image: mcp/postgres
+    secrets:
+      - name: db-postgres.password
+        env: POSTGRES_PASSWORD
+  db-redis:
+    image: mcp/redis
+  web-fetch:
+    image: mcp/fetch
+    secrets:
+      - name: web-fetch.token
+        env: FETCH_TOKEN`
+	require.NoError(t, os.WriteFile(filepath.Join(catalogsDir, "my-catalog.yaml"), []byte(customCatalog), 0o644))
+
+	return catalogsDir
+}
@@ -73,17 +73,27 @@ command will import servers from the MCP registry URL into that catalog.`,
 }
 
 func exportCatalogCommand() *cobra.Command {
-	return &cobra.Command{
+	var only string
+	cmd := &cobra.Command{
 		Use:   "export <catalog-name> <file-path>",
 		Short: "Export a configured catalog to a file",
 		Long: `Export a user-managed catalog to a file. This command only works with catalogs
 that have been imported or configured manually. The canonical Docker MCP catalog
-cannot be exported as it is managed by Docker.`,
+cannot be exported as it is managed by Docker.
+
+Use --only to export only the servers whose name matches a glob pattern.`,
+		Example: `  # Export a catalog
+  docker mcp catalog export my-catalog ./my-catalog.yaml
+
+  # Export only the database servers of a catalog
+  docker mcp catalog export my-catalog ./db-servers.yaml --only 'db-*'`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return catalog.Export(cmd.Context(), args[0], args[1])
+			return catalog.Export(cmd.Context(), args[0], args[1], only)
 		},
 	}
+	cmd.Flags().StringVar(&only, "only", "", "Only export the servers whose name matches this glob pattern (e.g. 'db-*')")
+	return cmd
 }
 
 func lsCatalogCommand(dockerCli command.Cli) *cobra.Command {
@@ -7,14 +7,17 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
 	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/db"
+	dockerpkg "github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/registryapi"
 	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func catalogNextCommand() *cobra.Command {
+func catalogNextCommand(docker dockerpkg.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "catalog",
 		Aliases: []string{"catalogs", "catalog-next"},
@@ -23,12 +26,18 @@ func catalogNextCommand() *cobra.Command {
 
 	cmd.AddCommand(createCatalogNextCommand())
 	cmd.AddCommand(showCatalogNextCommand())
+	cmd.AddCommand(statsCatalogNextCommand())
+	cmd.AddCommand(lintCatalogNextCommand())
+	cmd.AddCommand(pinCatalogNextCommand())
 	cmd.AddCommand(listCatalogNextCommand())
 	cmd.AddCommand(removeCatalogNextCommand())
 	cmd.AddCommand(pushCatalogNextCommand())
 	cmd.AddCommand(pullCatalogNextCommand())
 	cmd.AddCommand(tagCatalogNextCommand())
-	cmd.AddCommand(catalogNextServerCommand())
+	cmd.AddCommand(overlayCatalogNextCommand())
+	cmd.AddCommand(catalogNextServerCommand(docker))
+	cmd.AddCommand(catalogNextAliasCommand())
+	addRegistryAuthFlag(cmd)
 
 	return cmd
 }
@@ -43,6 +52,18 @@ func createCatalogNextCommand() *cobra.Command {
 		Exclude               []string
 		IncludePyPI           bool
 		IncludeNPM            bool
+		Strict                bool
+		ResolveSnapshots      bool
+		Base                  string
+		MaxServers            int
+		Truncate              bool
+		TransformCache        string
+		RegistryCache         string
+		RegistryCachePull     string
+		GroupedConfig         bool
+		NameCollisions        string
+		Format                string
+		Quiet                 bool
 	}
 
 	cmd := &cobra.Command{
@@ -72,7 +93,12 @@ When using --server without --from-profile, --from-legacy-catalog, or --from-com
   docker mcp catalog create docker-mcp-catalog --from-legacy-catalog https://desktop.docker.com/mcp/catalog/v3/catalog.json
 
   # Create from a community registry
-  docker mcp catalog create my-catalog --from-community-registry registry.modelcontextprotocol.io --title "Community Servers"`,
+  docker mcp catalog create my-catalog --from-community-registry registry.modelcontextprotocol.io --title "Community Servers"
+
+  # Derive a catalog from a base catalog, adding a server to the base's servers
+  docker mcp catalog create myorg/team-catalog:latest --title "Team Catalog" \
+    --base myorg/base-catalog:latest \
+    --server docker://mcp/custom-tool:latest`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sourceCount := 0
@@ -101,6 +127,41 @@ When using --server without --from-profile, --from-legacy-catalog, or --from-com
 				return fmt.Errorf("--exclude can only be used when creating a catalog from a community registry")
 			}
 
+			if opts.GroupedConfig && opts.FromCommunityRegistry == "" {
+				return fmt.Errorf("--grouped-config can only be used when creating a catalog from a community registry")
+			}
+
+			if cmd.Flags().Changed("name-collisions") && opts.FromCommunityRegistry == "" {
+				return fmt.Errorf("--name-collisions can only be used when creating a catalog from a community registry")
+			}
+
+			if opts.TransformCache != "" && opts.FromCommunityRegistry == "" {
+				return fmt.Errorf("--transform-cache can only be used when creating a catalog from a community registry")
+			}
+
+			if opts.RegistryCache != "" && opts.FromCommunityRegistry == "" {
+				return fmt.Errorf("--registry-cache can only be used when creating a catalog from a community registry")
+			}
+
+			if _, err := catalognext.NewPullOptionEvaluator(opts.RegistryCachePull, false); err != nil {
+				return fmt.Errorf("invalid --registry-cache-pull: %w", err)
+			}
+
+			if opts.ResolveSnapshots && opts.FromLegacyCatalog == "" {
+				return fmt.Errorf("--resolve-snapshots can only be used when creating a catalog from a legacy catalog")
+			}
+
+			if opts.MaxServers < 0 {
+				return fmt.Errorf("--max-catalog-servers must not be negative")
+			}
+			if opts.Truncate && opts.MaxServers == 0 {
+				return fmt.Errorf("--truncate can only be used with --max-catalog-servers")
+			}
+			output, err := commandOutput(opts.Format, opts.Quiet)
+			if err != nil {
+				return err
+			}
+
 			dao, err := db.New()
 			if err != nil {
 				return err
@@ -116,32 +177,58 @@ When using --server without --from-profile, --from-legacy-catalog, or --from-com
 				IncludePyPI:          opts.IncludePyPI,
 				IncludeNPM:           opts.IncludeNPM,
 				ExcludeServers:       opts.Exclude,
+				Strict:               opts.Strict,
+				GroupedConfig:        opts.GroupedConfig,
+				NameCollisions:       opts.NameCollisions,
+				ResolveSnapshots:     opts.ResolveSnapshots,
+				Base:                 opts.Base,
+				MaxServers:           opts.MaxServers,
+				TruncateToMaxServers: opts.Truncate,
+				TransformCacheDir:    opts.TransformCache,
+				RegistryCacheDir:     opts.RegistryCache,
+				RegistryCachePull:    opts.RegistryCachePull,
+				Output:               output,
 			})
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringArrayVar(&opts.Servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs). Can be specified multiple times.")
+	flags.StringArrayVar(&opts.Servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs) or openapi:// (OpenAPI document URL without https://, wrapped with the mcp/openapi-proxy:v1 image). Can be specified multiple times.")
 	flags.StringVar(&opts.FromWorkingSet, "from-profile", "", "Profile ID to create the catalog from")
-	flags.StringVar(&opts.FromLegacyCatalog, "from-legacy-catalog", "", "Legacy catalog URL to create the catalog from")
-	flags.StringVar(&opts.FromCommunityRegistry, "from-community-registry", "", "Community registry hostname to fetch servers from (e.g. registry.modelcontextprotocol.io)")
+	flags.StringVar(&opts.FromLegacyCatalog, "from-legacy-catalog", "", "Legacy catalog file or http(s) URL to create the catalog from")
+	flags.StringVar(&opts.FromCommunityRegistry, "from-community-registry", "", "Community registry hostname, or base URL of a self-hosted registry, to fetch servers from (e.g. registry.modelcontextprotocol.io or https://mcp.example.com/registry). Credentials in the URL are only used to list the servers")
 	flags.StringVar(&opts.Title, "title", "", "Title of the catalog")
+	flags.StringVar(&opts.Base, "base", "", "Catalog whose servers the new catalog inherits, overriding those with the same name as its own servers (e.g. myorg/base-catalog:latest)")
 
 	flags.StringArrayVar(&opts.Exclude, "exclude", []string{}, "Server name to exclude from the catalog (can be specified multiple times, only valid with --from-community-registry)")
 	flags.BoolVar(&opts.IncludePyPI, "include-pypi", false, "Include PyPI servers when creating a catalog from a community registry")
 	cmd.Flags().MarkHidden("include-pypi") //nolint:errcheck
 	flags.BoolVar(&opts.IncludeNPM, "include-npm", false, "Include npm servers when creating a catalog from a community registry")
 	cmd.Flags().MarkHidden("include-npm") //nolint:errcheck
+	flags.BoolVar(&opts.GroupedConfig, "grouped-config", false, "Keep one config item per environment variable grouping config variables, instead of a single config item per server (only valid with --from-community-registry)")
+	flags.StringVar(&opts.NameCollisions, "name-collisions", catalognext.NameCollisionsSkip, "How to handle servers whose names normalize to the same catalog name: 'skip' or 'suffix' to rename them, e.g. server-2. The server whose registry name sorts first keeps the name (only valid with --from-community-registry)")
+	flags.StringVar(&opts.TransformCache, "transform-cache", "", "Directory caching the servers transformed from the community registry, so that re-imports skip the servers that didn't change (only valid with --from-community-registry)")
+	flags.StringVar(&opts.RegistryCache, "registry-cache", "", "Directory caching the listing of the servers of the community registry, so that repeated imports don't download it again (only valid with --from-community-registry)")
+	flags.StringVar(&opts.RegistryCachePull, "registry-cache-pull", catalognext.DefaultRegistryCachePull, "When to download the cached registry listing again: a pull option such as 'always', 'missing' or a duration like '1h'")
+	flags.BoolVar(&opts.ResolveSnapshots, "resolve-snapshots", false, "Fill the tools of image servers that don't list any from the metadata of their image (only valid with --from-legacy-catalog)")
+	flags.BoolVar(&opts.Strict, "strict", false, "Fail instead of warning when a server declares config fields that look like secrets (e.g. a password)")
+	flags.IntVar(&opts.MaxServers, "max-catalog-servers", 0, "Fail when the catalog would have more servers than this, e.g. when importing a large community registry (0 for no limit)")
+	flags.BoolVar(&opts.Truncate, "truncate", false, "Keep the first --max-catalog-servers servers, with a warning, instead of failing")
+	addOutputFlags(flags, &opts.Format, &opts.Quiet)
 
 	return cmd
 }
 
 func tagCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
+	var move bool
+	var quiet bool
+
+	cmd := &cobra.Command{
 		Use:   "tag SOURCE_IMAGE[:TAG] TARGET_IMAGE[:TAG]",
 		Short: "Create a tagged copy of a catalog",
 		Long: `Create a new catalog by tagging an existing catalog with a new name or version.
-This creates a copy of the source catalog with a new reference, similar to Docker image tagging.`,
+This creates a copy of the source catalog with a new reference, similar to Docker image tagging.
+With --move, the source reference is removed and its aliases point to the new reference, e.g. to promote a catalog.`,
 		Args: cobra.ExactArgs(2),
 		Example: `  # Tag a catalog with a new version
   docker mcp catalog tag mcp/my-catalog:v1 mcp/my-catalog:v2
@@ -150,15 +237,62 @@ This creates a copy of the source catalog with a new reference, similar to Docke
   docker mcp catalog tag mcp/team-catalog:latest mcp/prod-catalog:v1.0
 
   # Tag without explicit version (uses latest)
-  docker mcp catalog tag mcp/my-catalog mcp/my-catalog:backup`,
+  docker mcp catalog tag mcp/my-catalog mcp/my-catalog:backup
+
+  # Promote a catalog, removing the old tag
+  docker mcp catalog tag mcp/my-catalog:staging mcp/my-catalog:latest --move`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
-			return catalognext.Tag(cmd.Context(), dao, args[0], args[1])
+			if move {
+				return catalognext.Retag(cmd.Context(), dao, args[0], args[1], true, workingset.Output{Quiet: quiet})
+			}
+			return catalognext.Tag(cmd.Context(), dao, args[0], args[1], workingset.Output{Quiet: quiet})
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&move, "move", false, "Remove the source reference once the catalog is tagged")
+
+	addQuietFlag(flags, &quiet)
+
+	return cmd
+}
+
+func overlayCatalogNextCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "overlay <base-oci-reference> <overlay-file> <oci-reference>",
+		Short: "Create a catalog from a base catalog with an environment-specific overlay",
+		Long: `Create a new catalog from a base catalog, with the overrides of an overlay file applied to its servers.
+The overlay file maps server names to the fields to override, in the same format as a server of a legacy catalog.
+Objects are merged, while lists and other values are replaced. For example:
+
+  title: Team Catalog (production)
+  servers:
+    github:
+      image: myorg/github-mcp:1.4.2
+      env:
+        - name: LOG_LEVEL
+          value: warn`,
+		Example: `  # Create the production catalog from the team catalog
+  docker mcp catalog overlay myorg/team-catalog:latest prod.yaml myorg/team-catalog:prod`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.Overlay(cmd.Context(), dao, args[0], args[1], args[2], workingset.Output{Quiet: quiet})
+		},
+	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
 }
 
 func showCatalogNextCommand() *cobra.Command {
@@ -201,6 +335,115 @@ func showCatalogNextCommand() *cobra.Command {
 	return cmd
 }
 
+func statsCatalogNextCommand() *cobra.Command {
+	format := string(workingset.OutputFormatHumanReadable)
+
+	cmd := &cobra.Command{
+		Use:   "stats <oci-reference>",
+		Short: "Show a summary of the servers and tools in a catalog",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supported := slices.Contains(workingset.SupportedFormats(), format)
+			if !supported {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.Stats(cmd.Context(), dao, args[0], workingset.OutputFormat(format))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
+	return cmd
+}
+
+func lintCatalogNextCommand() *cobra.Command {
+	format := string(workingset.OutputFormatHumanReadable)
+
+	cmd := &cobra.Command{
+		Use:   "lint <oci-reference>",
+		Short: "Check a catalog for common authoring mistakes",
+		Long: `Check the servers of a catalog for common authoring mistakes: servers with no tools listed,
+images using the mutable latest tag, remote servers over http, secrets that are never used
+and config placeholders that don't match any config property.
+Exits with an error when any finding has the error severity.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supported := slices.Contains(workingset.SupportedFormats(), format)
+			if !supported {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			findings, err := catalognext.Lint(cmd.Context(), dao, args[0])
+			if err != nil {
+				return err
+			}
+			if err := catalognext.PrintLintFindings(findings, workingset.OutputFormat(format)); err != nil {
+				return err
+			}
+			if count := catalognext.LintErrors(findings); count > 0 {
+				return fmt.Errorf("catalog %s has %d error(s)", args[0], count)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
+	return cmd
+}
+
+func pinCatalogNextCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "pin <oci-reference>",
+		Short: "Pin the images of a catalog to their digests",
+		Long: `Resolve the tag of every image server of a catalog, e.g. latest, to the digest it currently
+points to in the registry and rewrite the catalog to reference that digest.
+Images that are already pinned are left unchanged.`,
+		Example: `  # Show the images that would be pinned
+  docker mcp catalog pin mcp/my-catalog:latest --dry-run
+
+  # Pin the images
+  docker mcp catalog pin mcp/my-catalog:latest`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			pinned, err := catalognext.PinImages(cmd.Context(), dao, oci.NewService(), args[0], dryRun)
+			if err != nil {
+				return err
+			}
+			if len(pinned) == 0 {
+				fmt.Println("All images are already pinned")
+				return nil
+			}
+			for _, image := range pinned {
+				fmt.Printf("%s: %s -> %s\n", image.Server, image.From, image.To)
+			}
+			if dryRun {
+				fmt.Printf("Would pin %d image(s), run without --dry-run to update the catalog\n", len(pinned))
+			} else {
+				fmt.Printf("Pinned %d image(s)\n", len(pinned))
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&dryRun, "dry-run", false, "Show the images that would be pinned without updating the catalog")
+	return cmd
+}
+
 func listCatalogNextCommand() *cobra.Command {
 	format := string(workingset.OutputFormatHumanReadable)
 
@@ -229,7 +472,9 @@ func listCatalogNextCommand() *cobra.Command {
 }
 
 func removeCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
+	var quiet bool
+
+	cmd := &cobra.Command{
 		Use:     "remove <oci-reference>",
 		Aliases: []string{"rm"},
 		Short:   "Remove a catalog",
@@ -244,13 +489,19 @@ func removeCatalogNextCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return catalognext.Remove(cmd.Context(), dao, args[0])
+			return catalognext.Remove(cmd.Context(), dao, args[0], workingset.Output{Quiet: quiet})
 		},
 	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
 }
 
 func pushCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
+	var quiet bool
+
+	cmd := &cobra.Command{
 		Use:   "push <oci-reference>",
 		Short: "Push a catalog to an OCI registry",
 		Args:  cobra.ExactArgs(1),
@@ -259,13 +510,19 @@ func pushCatalogNextCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return catalognext.Push(cmd.Context(), dao, args[0])
+			return catalognext.Push(cmd.Context(), dao, args[0], workingset.Output{Quiet: quiet})
 		},
 	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
 }
 
 func pullCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
+	var quiet bool
+
+	cmd := &cobra.Command{
 		Use:   "pull <oci-reference>",
 		Short: "Pull and import an MCP server catalog from an OCI registry",
 		Long: `Pull and import an MCP server catalog from an OCI registry.
@@ -286,12 +543,16 @@ Available Catalogs:
 				return err
 			}
 			ociService := oci.NewService()
-			return catalognext.Pull(cmd.Context(), dao, ociService, args[0])
+			return catalognext.Pull(cmd.Context(), dao, ociService, args[0], workingset.Output{Quiet: quiet})
 		},
 	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
 }
 
-func catalogNextServerCommand() *cobra.Command {
+func catalogNextServerCommand(docker dockerpkg.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "server",
 		Short: "Manage servers in catalogs",
@@ -301,13 +562,16 @@ func catalogNextServerCommand() *cobra.Command {
 	cmd.AddCommand(inspectServerCatalogNextCommand())
 	cmd.AddCommand(addCatalogNextServersCommand())
 	cmd.AddCommand(removeCatalogNextServersCommand())
+	cmd.AddCommand(moveCatalogNextServerCommand())
+	cmd.AddCommand(testCatalogNextServerCommand(docker))
 
 	return cmd
 }
 
 func inspectServerCatalogNextCommand() *cobra.Command {
 	var opts struct {
-		Format string
+		Format  string
+		Compact bool
 	}
 
 	cmd := &cobra.Command{
@@ -319,34 +583,44 @@ func inspectServerCatalogNextCommand() *cobra.Command {
 			if !supported {
 				return fmt.Errorf("unsupported format: %s", opts.Format)
 			}
+			if err := validateCompactFormat(opts.Compact, opts.Format); err != nil {
+				return err
+			}
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
 
-			return catalognext.InspectServer(cmd.Context(), dao, args[0], args[1], workingset.OutputFormat(opts.Format))
+			return catalognext.InspectServer(cmd.Context(), dao, args[0], args[1], workingset.OutputFormat(opts.Format), opts.Compact)
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
+	flags.BoolVar(&opts.Compact, "compact", false, "Print JSON on a single line (requires --format json)")
 	return cmd
 }
 
 func listCatalogNextServersCommand() *cobra.Command {
 	var opts struct {
-		Filters []string
-		Format  string
+		Filters              []string
+		Format               string
+		Compact              bool
+		IncludeUnsnapshotted bool
+		All                  bool
 	}
 
 	cmd := &cobra.Command{
-		Use:     "ls <oci-reference>",
+		Use:     "ls <oci-reference> | --all",
 		Aliases: []string{"list"},
 		Short:   "List servers in a catalog",
 		Long: `List all servers in a catalog.
 
 Use --filter to search for servers matching a query (case-insensitive substring matching on server names).
-Filters use key=value format (e.g., name=github).`,
+Filters use key=value format (e.g., name=github).
+
+Use --all to list the servers of every catalog, with the catalog each belongs to,
+e.g. to find servers that are in more than one catalog.`,
 		Example: `  # List all servers in a catalog
   docker mcp catalog server ls mcp/docker-mcp-catalog:latest
 
@@ -357,32 +631,62 @@ Filters use key=value format (e.g., name=github).`,
   docker mcp catalog server ls mcp/docker-mcp-catalog:latest -f name=slack -f name=github
 
   # Output in JSON format
-  docker mcp catalog server ls mcp/docker-mcp-catalog:latest --format json`,
-		Args: cobra.ExactArgs(1),
+  docker mcp catalog server ls mcp/docker-mcp-catalog:latest --format json
+
+  # Output single-line JSON, e.g. to pipe it into other tools
+  docker mcp catalog server ls mcp/docker-mcp-catalog:latest --format json --compact
+
+  # Include the servers stored without a snapshot in JSON output
+  docker mcp catalog server ls my-catalog --format json --include-unsnapshotted
+
+  # List the servers of all catalogs
+  docker mcp catalog server ls --all --filter name=github`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.All != (len(args) == 0) {
+				return fmt.Errorf("specify either a catalog or --all")
+			}
 			supported := slices.Contains(workingset.SupportedFormats(), opts.Format)
 			if !supported {
 				return fmt.Errorf("unsupported format: %s", opts.Format)
 			}
+			if err := validateCompactFormat(opts.Compact, opts.Format); err != nil {
+				return err
+			}
 
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
 
-			return catalognext.ListServers(cmd.Context(), dao, args[0], opts.Filters, workingset.OutputFormat(opts.Format))
+			if opts.All {
+				return catalognext.ListAllServers(cmd.Context(), dao, opts.Filters, workingset.OutputFormat(opts.Format), opts.Compact, opts.IncludeUnsnapshotted)
+			}
+			return catalognext.ListServers(cmd.Context(), dao, args[0], opts.Filters, workingset.OutputFormat(opts.Format), opts.Compact, opts.IncludeUnsnapshotted)
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringArrayVarP(&opts.Filters, "filter", "f", []string{}, "Filter output (e.g., name=github)")
 	flags.StringVar(&opts.Format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
+	flags.BoolVar(&opts.Compact, "compact", false, "Print JSON on a single line (requires --format json)")
+	flags.BoolVar(&opts.IncludeUnsnapshotted, "include-unsnapshotted", false, "Include the servers without a snapshot, and thus without a name, in JSON and YAML output. Name filters match their image, source or endpoint")
+	flags.BoolVar(&opts.All, "all", false, "List the servers of all catalogs")
 
 	return cmd
 }
 
+func validateCompactFormat(compact bool, format string) error {
+	if compact && format != string(workingset.OutputFormatJSON) {
+		return fmt.Errorf("--compact requires --format %s", workingset.OutputFormatJSON)
+	}
+	return nil
+}
+
 func addCatalogNextServersCommand() *cobra.Command {
 	var servers []string
+	var format string
+	var quiet bool
 
 	cmd := &cobra.Command{
 		Use:   "add <oci-reference> [--server <ref1> --server <ref2> ...]",
@@ -404,24 +708,32 @@ func addCatalogNextServersCommand() *cobra.Command {
   docker mcp catalog server add mcp/my-catalog:latest --server catalog://mcp/docker-mcp-catalog:latest/github --server docker://my-server:latest`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := commandOutput(format, quiet)
+			if err != nil {
+				return err
+			}
+
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
 			registryClient := registryapi.NewClient()
 			ociService := oci.NewService()
-			return catalognext.AddServers(cmd.Context(), dao, registryClient, ociService, args[0], servers)
+			return catalognext.AddServers(cmd.Context(), dao, registryClient, ociService, args[0], servers, output)
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringArrayVar(&servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs). Can be specified multiple times.")
+	flags.StringArrayVar(&servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs) or openapi:// (OpenAPI document URL without https://, wrapped with the mcp/openapi-proxy:v1 image). Can be specified multiple times.")
+	addOutputFlags(flags, &format, &quiet)
 
 	return cmd
 }
 
 func removeCatalogNextServersCommand() *cobra.Command {
 	var names []string
+	var strict bool
+	var quiet bool
 
 	cmd := &cobra.Command{
 		Use:     "remove <oci-reference> [<name1> <name2> ...] [--name <name>]",
@@ -435,7 +747,10 @@ func removeCatalogNextServersCommand() *cobra.Command {
   docker mcp catalog server remove mcp/my-catalog:latest github
 
   # Remove servers using --name flag
-  docker mcp catalog server remove mcp/my-catalog:latest --name github --name slack`,
+  docker mcp catalog server remove mcp/my-catalog:latest --name github --name slack
+
+  # Fail instead of warning when a name doesn't match any server
+  docker mcp catalog server remove mcp/my-catalog:latest github slack --strict`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			allNames := make([]string, 0, len(args)-1+len(names))
@@ -445,12 +760,196 @@ func removeCatalogNextServersCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return catalognext.RemoveServers(cmd.Context(), dao, args[0], allNames)
+			return catalognext.RemoveServers(cmd.Context(), dao, args[0], allNames, strict, workingset.Output{Quiet: quiet})
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringArrayVar(&names, "name", []string{}, "Server name to remove (can be specified multiple times)")
+	flags.BoolVar(&strict, "strict", false, "Fail without removing anything if a name doesn't match any server in the catalog")
+
+	addQuietFlag(flags, &quiet)
+
+	return cmd
+}
+
+func moveCatalogNextServerCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:     "move <source-oci-reference> <destination-oci-reference> <server-name>",
+		Aliases: []string{"mv"},
+		Short:   "Move an MCP server from one catalog to another",
+		Long:    "Move an MCP server, with its snapshot, from one catalog to another. The server must not already exist in the destination catalog.",
+		Example: `  # Move the github server to a team catalog
+  docker mcp catalog server move mcp/my-catalog:latest mcp/team-catalog:latest github`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.MoveServer(cmd.Context(), dao, args[0], args[1], args[2], workingset.Output{Quiet: quiet})
+		},
+	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
+}
+
+func testCatalogNextServerCommand(docker dockerpkg.Client) *cobra.Command {
+	var config []string
+	var verbose bool
+	var reportPath string
+	verifySignatures := true
+
+	cmd := &cobra.Command{
+		Use:   "test <oci-reference> <server-name> <tool> [<key>=<value> ...]",
+		Short: "Start a server of a catalog, call one of its tools and stop it",
+		Long: `Test a server of a catalog in isolation: start it the way the gateway does, list its tools,
+call a tool with the given arguments, print the result and stop the server.
+
+Arguments are given as key=value. A key given several times has a list of values.
+The config values of the server are given the same way with --config.`,
+		Example: `  # Call the fetch tool of the fetch server
+  docker mcp catalog server test mcp/docker-mcp-catalog:latest fetch fetch url=https://example.com
+
+  # Give a config value to the server
+  docker mcp catalog server test mcp/docker-mcp-catalog:latest filesystem list_directory path=/tmp --config paths=/tmp
+
+  # Show the logs of the server while testing it
+  docker mcp catalog server test mcp/docker-mcp-catalog:latest fetch fetch url=https://example.com --verbose
+
+  # Write the outcome of the test to a JSON file in CI
+  docker mcp catalog server test mcp/docker-mcp-catalog:latest fetch fetch url=https://example.com --report test.json`,
+		Args: cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			server, err := catalognext.GetServer(cmd.Context(), dao, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			serverConfig := gateway.NewServerConfig(cmd.Context(), server, tools.ParseArgs(config))
+			run, err := gateway.RunServerTool(cmd.Context(), docker, gateway.Options{
+				Verbose:          verbose,
+				VerifySignatures: verifySignatures,
+			}, serverConfig, args[2], tools.ParseArgs(args[3:]))
+			if reportPath != "" {
+				if err := gateway.WriteServerToolReport(reportPath, args[1], args[2], run, err); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Tools: %s\n", strings.Join(run.Tools, ", "))
+			if run.Result.IsError {
+				return fmt.Errorf("error calling tool %s: %s", args[2], tools.ToText(run.Result))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), tools.ToText(run.Result))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&config, "config", nil, "Config value of the server, as key=value")
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", verifySignatures, "Verify signatures of the server's image")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the outcome of the test to a JSON file, e.g. for CI. The command exits with a non-zero code if the test failed")
+
+	return cmd
+}
+
+func catalogNextAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage short aliases for catalog references",
+	}
+
+	cmd.AddCommand(setCatalogNextAliasCommand())
+	cmd.AddCommand(removeCatalogNextAliasCommand())
+	cmd.AddCommand(listCatalogNextAliasesCommand())
+
+	return cmd
+}
+
+func setCatalogNextAliasCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "set <alias> <oci-reference>",
+		Short: "Create or update an alias for a catalog reference",
+		Example: `  # Refer to a catalog as "team" instead of its full reference
+  docker mcp catalog alias set team registry.example.com/team/catalog:latest
+  docker mcp catalog server ls team
+  docker mcp catalog pull team
+  docker mcp profile create --name dev --server catalog://team/github`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.SetAlias(cmd.Context(), dao, args[0], args[1], workingset.Output{Quiet: quiet})
+		},
+	}
 
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
+}
+
+func removeCatalogNextAliasCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:     "rm <alias>",
+		Aliases: []string{"remove"},
+		Short:   "Remove a catalog alias",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.RemoveAlias(cmd.Context(), dao, args[0], workingset.Output{Quiet: quiet})
+		},
+	}
+
+	addQuietFlag(cmd.Flags(), &quiet)
+
+	return cmd
+}
+
+func listCatalogNextAliasesCommand() *cobra.Command {
+	var opts struct {
+		Format string
+	}
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List catalog aliases",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			supported := slices.Contains(workingset.SupportedFormats(), opts.Format)
+			if !supported {
+				return fmt.Errorf("unsupported format: %s", opts.Format)
+			}
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return catalognext.ListAliases(cmd.Context(), dao, workingset.OutputFormat(opts.Format))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
 	return cmd
 }
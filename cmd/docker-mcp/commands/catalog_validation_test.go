@@ -100,14 +100,14 @@ registry:
 
 	t.Run("TestExportDockerCatalogPrevented", func(t *testing.T) {
 		outputPath := filepath.Join(tempHome, "exported-docker.yaml")
-		err := catalog.Export(ctx, catalog.DockerCatalogName, outputPath)
+		err := catalog.Export(ctx, catalog.DockerCatalogName, outputPath, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot export the Docker MCP catalog as it is managed by Docker")
 	})
 
 	t.Run("TestExportDockerCatalogFilenamePrevented", func(t *testing.T) {
 		outputPath := filepath.Join(tempHome, "exported-docker.yaml")
-		err := catalog.Export(ctx, catalog.DockerCatalogFilename, outputPath)
+		err := catalog.Export(ctx, catalog.DockerCatalogFilename, outputPath, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot export the Docker MCP catalog as it is managed by Docker")
 	})
@@ -147,7 +147,7 @@ registry:
 
 		// Export it
 		outputPath := filepath.Join(tempHome, "exported-normal.yaml")
-		err = catalog.Export(ctx, "normal-catalog", outputPath)
+		err = catalog.Export(ctx, "normal-catalog", outputPath, "")
 		require.NoError(t, err)
 
 		// Verify export file exists
@@ -3,20 +3,35 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
-	"github.com/docker/mcp-gateway/pkg/docker"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	dockerpkg "github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/features"
 	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
 )
 
-func gatewayCommand(docker docker.Client, dockerCli command.Cli, features features.Features) *cobra.Command {
+// Resources allocated by default to the container of each server, also used
+// to render the run plans of profiles.
+const (
+	defaultServerCpus   = 1
+	defaultServerMemory = "2Gb"
+)
+
+func gatewayCommand(docker dockerpkg.Client, dockerCli command.Cli, features features.Features) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "gateway",
 		Short: "Manage the MCP Server gateway",
@@ -36,8 +51,8 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli, features featur
 		options = gateway.Config{
 			SecretsPath: "docker-desktop:/run/secrets/mcp_secret:/.env",
 			Options: gateway.Options{
-				Cpus:             1,
-				Memory:           "2Gb",
+				Cpus:             defaultServerCpus,
+				Memory:           defaultServerMemory,
 				Transport:        "stdio",
 				LogCalls:         true,
 				BlockSecrets:     true,
@@ -50,8 +65,8 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli, features featur
 		options = gateway.Config{
 			SecretsPath: "docker-desktop",
 			Options: gateway.Options{
-				Cpus:             1,
-				Memory:           "2Gb",
+				Cpus:             defaultServerCpus,
+				Memory:           defaultServerMemory,
 				Transport:        "stdio",
 				LogCalls:         true,
 				BlockSecrets:     true,
@@ -116,6 +131,16 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli, features featur
 				options.Watch = false
 			}
 
+			// Safe mode has its own resource limits, unless they are set explicitly.
+			if options.SafeMode {
+				if !cmd.Flags().Changed("cpus") {
+					options.Cpus = 0
+				}
+				if !cmd.Flags().Changed("memory") {
+					options.Memory = ""
+				}
+			}
+
 			if options.Transport == "stdio" {
 				if options.Port != 0 {
 					return errors.New("cannot use --port with --transport=stdio")
@@ -182,13 +207,24 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli, features featur
 				}
 			}
 
+			if options.DockerHost != "" || options.DockerContext != "" {
+				targetCli, err := dockerpkg.NewCliForTarget(options.DockerHost, options.DockerContext)
+				if err != nil {
+					return fmt.Errorf("cannot use --docker-host/--docker-context: %w", err)
+				}
+				docker = dockerpkg.NewClient(targetCli)
+			}
+
 			return gateway.NewGateway(options, docker).Run(cmd.Context())
 		},
 	}
 
-	runCmd.Flags().StringSliceVar(&options.ServerNames, "servers", nil, "Names of the servers to enable (if non empty, ignore --registry flag)")
+	runCmd.Flags().StringSliceVar(&options.ServerNames, "servers", nil, "Names or stable IDs of the servers to enable (if non empty, ignore --registry flag)")
 	if features.IsProfilesFeatureEnabled() {
 		runCmd.Flags().StringVar(&options.WorkingSet, "profile", "", "Profile ID to use (mutually exclusive with --servers and --enable-all-servers)")
+		runCmd.Flags().StringVar(&options.CatalogRefresh, "catalog-refresh", "", fmt.Sprintf("Refresh catalogs in the background when due according to this pull option (e.g. 'exists@6h'). Supported: %s, or duration (e.g. '1h', '1d').", strings.Join(catalognext.SupportedPullOptions(), ", ")))
+		runCmd.Flags().StringVar(&options.PreloadCatalog, "preload-catalog", "", "Legacy catalog file (under ~/.docker/mcp/catalogs/) to import into the database before serving, if the database doesn't have it yet")
+		runCmd.Flags().BoolVar(&options.SnapshotWarmup, "snapshot-warmup", false, "Resolve the snapshots of the profile servers in the background after startup instead of before serving")
 	}
 	runCmd.Flags().BoolVar(&enableAllServers, "enable-all-servers", false, "Enable all servers in the catalog (instead of using individual --servers options)")
 	runCmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Catalog paths must resolve under ~/.docker/mcp/catalogs/")
@@ -208,28 +244,107 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli, features featur
 	runCmd.Flags().StringVar(&options.Host, "host", options.Host, "Host or IP address to bind TCP transports to")
 	runCmd.Flags().StringVar(&options.Transport, "transport", options.Transport, "stdio, sse or streaming. Uses MCP_GATEWAY_AUTH_TOKEN environment variable for localhost authentication to prevent dns rebinding attacks.")
 	runCmd.Flags().BoolVar(&options.AllowUnauthenticated, "allow-unauthenticated", options.AllowUnauthenticated, "Allow unauthenticated HTTP/SSE gateway requests")
+	runCmd.Flags().StringVar(&options.AnnounceCapabilities, "announce-capabilities", gateway.AnnounceCapabilitiesAll, "Which capabilities to advertise to clients: 'all' or 'present' (only those provided by the active servers)")
+	runCmd.Flags().StringVar(&options.DuplicateCapabilities, "duplicate-capabilities", gateway.DuplicateCapabilitiesError, "How to handle prompts and resources exposed by several servers: 'error', 'prefix' or 'first-wins'")
+	runCmd.Flags().StringSliceVar(&options.DisabledCapabilities, "disable", options.DisabledCapabilities, "Capability types to neither discover nor advertise: 'prompts', 'resources' and/or 'resource-templates'")
+	runCmd.Flags().StringSliceVar(&options.ServerConcurrency, "server-concurrency", options.ServerConcurrency, "Maximum number of concurrent tool calls per server, as <server>:<limit> (e.g. 'postgres:1'). Can be repeated. Waiting calls are dispatched round-robin across clients. Servers without a limit are not throttled")
+	runCmd.Flags().StringArrayVar(&options.ContainerLabels, "container-label", options.ContainerLabels, "Label to add to server containers, as <key>=<value> (e.g. 'team=platform'). Can be repeated. Servers can override values with their own labels")
+	runCmd.Flags().StringArrayVar(&options.RemoteHeaders, "remote-header", options.RemoteHeaders, "Header to add to the requests to a remote server, as <server>:<name>=<value> (e.g. 'notion:X-Tenant-ID={{notion.tenant}}'). Can be repeated. Values can reference the server's config with {{...}} and its secrets with ${ENV}. Servers can override values with their own headers")
+	runCmd.Flags().StringArrayVar(&options.ConfigFileDirs, "config-file-dir", options.ConfigFileDirs, "Directory that file://<path> config values can be read from. Can be repeated")
+	runCmd.Flags().StringArrayVar(&options.RedactPatterns, "redact-pattern", options.RedactPatterns, "Regular expression whose matches are replaced with [REDACTED] in the text content of tool results (e.g. '[\\w.+-]+@[\\w-]+\\.[\\w.]+' for emails). Can be repeated")
+	runCmd.Flags().StringArrayVar(&options.ToolResultTransforms, "tool-result-transform", options.ToolResultTransforms, "YQ/JQ-style expression applied to the structured results of a tool, as <server>:<tool>=<expression> (e.g. 'github:search_issues=.items | map({\"title\": .title})'). Can be repeated. Invalid expressions are rejected at startup")
+	runCmd.Flags().StringArrayVar(&options.AllowImages, "allow-image", options.AllowImages, "Only run servers whose image matches this pattern (e.g. 'mcp/*', 'registry.internal/*'). Can be repeated")
+	runCmd.Flags().StringArrayVar(&options.DenyImages, "deny-image", options.DenyImages, "Don't run servers whose image matches this pattern, even if allowed. Can be repeated")
+	runCmd.Flags().StringArrayVar(&options.ServerEnv, "server-env", options.ServerEnv, "Environment variable to set in server containers, as <key>=<value> (e.g. 'TZ=UTC'). Can be repeated. Servers can override values with their own env. Not for secrets")
+	runCmd.Flags().StringArrayVar(&options.HookCommands, "hook-command", options.HookCommands, "Command, with its arguments, that servers' preStart and postStop hooks are allowed to run (e.g. '/usr/local/bin/seed-db --reset'). Hooks must run exactly one of these commands, once their templates are evaluated. Can be repeated. Hooks running anything else fail")
+	runCmd.Flags().BoolVar(&options.GatewayTools, "gateway-tools", options.GatewayTools, "Expose gateway__list-servers, gateway__list-tools, gateway__reload and gateway__version tools to describe and manage the gateway itself")
+	runCmd.Flags().IntVar(&options.MaxToolResponseBytes, "truncate-results", options.MaxToolResponseBytes, "Truncate the text content of tool results beyond this many bytes, appending a truncation marker. Structured content is left intact. 0 disables truncation")
+	runCmd.Flags().IntVar(&options.PageSize, "page-size", options.PageSize, "Maximum number of tools, prompts, resources or resource templates returned per page of list responses. Clients follow the cursor to get the next page. 0 uses the default of 1000")
+	runCmd.Flags().IntVar(&options.MaxResourcesPerServer, "max-resources-per-server", options.MaxResourcesPerServer, "Maximum number of resources listed from each server, with a warning for the servers that have more (0 means no limit)")
 	runCmd.Flags().BoolVar(&options.LogCalls, "log-calls", options.LogCalls, "Log calls to the tools")
+	runCmd.Flags().StringVar(&options.RecordCallsPath, "record-calls", options.RecordCallsPath, "Append every tool call (server, tool and arguments, with secrets redacted) to this JSON Lines file, for replaying with --replay-calls")
+	runCmd.Flags().StringVar(&options.ReplayCallsPath, "replay-calls", options.ReplayCallsPath, "Replay the tool calls recorded with --record-calls in this file against the gateway, print their results and exit")
+	runCmd.Flags().StringVar(&options.MetricsSnapshotPath, "metrics-snapshot", options.MetricsSnapshotPath, "Write the value of every metric (counters, gauges and histogram summaries) to this JSON file on exit, instead of exporting metrics")
 	runCmd.Flags().BoolVar(&options.BlockSecrets, "block-secrets", options.BlockSecrets, "Block secrets from being/received sent to/from tools")
 	runCmd.Flags().BoolVar(&options.BlockNetwork, "block-network", options.BlockNetwork, "Block tools from accessing forbidden network resources")
+	runCmd.Flags().BoolVar(&options.SafeMode, "safe-mode", options.SafeMode, "Disable mutating dynamic tools, harden containers (no capabilities, read-only root filesystem, 1 CPU, 1Gb), block network and secrets, cap tool responses to 1MiB and time out tool calls after 2m")
 	runCmd.Flags().BoolVar(&options.VerifySignatures, "verify-signatures", options.VerifySignatures, "Verify signatures of Docker MCP server images")
 	runCmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "Start the gateway but do not listen for connections (useful for testing the configuration)")
+	runCmd.Flags().StringVar(&options.ExplainServer, "explain-server", options.ExplainServer, "Print which profile or catalog the definition of this server comes from, the catalogs it shadows, its resolved image or endpoint and the overrides applied, then exit")
+	runCmd.Flags().BoolVar(&options.PrintToolSchemas, "print-tool-schemas", options.PrintToolSchemas, "Print the name and JSON input schema of each exposed tool after discovery (combine with --dry-run to exit afterwards)")
+	runCmd.Flags().BoolVar(&options.StartupSummary, "startup-summary", options.StartupSummary, "Print a single JSON line summarizing the gateway after initialization (to stderr with the stdio transport, stdout otherwise)")
+	runCmd.Flags().BoolVar(&options.FailOnEmpty, "fail-on-empty", options.FailOnEmpty, "Refuse to start when no enabled server can be used, e.g. because they were all filtered out, are missing from the catalogs or failed to start")
 	runCmd.Flags().BoolVar(&options.Verbose, "verbose", options.Verbose, "Verbose output")
 	runCmd.Flags().BoolVar(&options.LongLived, "long-lived", options.LongLived, "Containers are long-lived and will not be removed until the gateway is stopped, useful for stateful servers")
 	runCmd.Flags().BoolVar(&options.DebugDNS, "debug-dns", options.DebugDNS, "Debug DNS resolution")
 	runCmd.Flags().BoolVar(&options.Watch, "watch", options.Watch, "Watch for changes and reconfigure the gateway")
-	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, "CPUs allocated to each MCP Server (default is 1)")
-	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, "Memory allocated to each MCP Server (default is 2Gb)")
+	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, fmt.Sprintf("CPUs allocated to each MCP Server (default is %d)", defaultServerCpus))
+	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, fmt.Sprintf("Memory allocated to each MCP Server (default is %s)", defaultServerMemory))
 	runCmd.Flags().BoolVar(&options.Static, "static", options.Static, "Enable static mode (aka pre-started servers)")
+	runCmd.Flags().DurationVar(&options.DiscoveryTimeout, "discovery-timeout", options.DiscoveryTimeout, "Time each server has to start and list its tools before it's skipped as unavailable, so that a hanging server doesn't stall the others (0 means no timeout)")
+	runCmd.Flags().IntVar(&options.RemoteRetries, "remote-retries", 2, "Number of times to retry connecting to a remote server after a network failure (authentication failures are not retried)")
+	runCmd.Flags().DurationVar(&options.RemoteRetryBackoff, "remote-retry-backoff", time.Second, "Delay before the first retry to connect to a remote server, doubled on every retry")
+	runCmd.Flags().DurationVar(&options.RestartBackoff, "restart-backoff", time.Second, "Delay before restarting a server that failed to start, doubled on every rapid failure (0 restarts it right away)")
+	runCmd.Flags().IntVar(&options.MaxRestartFailures, "max-restart-failures", 5, "Number of rapid failures to start a server after which it's marked unavailable instead of being restarted (0 means no limit)")
+	runCmd.Flags().BoolVar(&options.ListInactiveServers, "list-inactive-servers", false, "Also list the catalog servers that are not enabled as inactive in gateway__list-servers, /servers and gateway__status, without starting them, so that clients can offer to enable them (e.g. with mcp-add)")
+	runCmd.Flags().IntVar(&options.RemoteMaxIdleConns, "remote-max-idle-conns", 4, "Number of idle keep-alive connections kept open to each remote server, to be reused by the next tool calls. 0 opens new connections for every client")
+	runCmd.Flags().StringVar(&options.DockerHost, "docker-host", options.DockerHost, "Docker daemon socket to run the servers on (e.g. 'tcp://10.0.0.2:2375' or 'ssh://user@host'), instead of the current docker context")
+	runCmd.Flags().StringVar(&options.DockerContext, "docker-context", options.DockerContext, "Name of the docker context to run the servers on, instead of the current one (mutually exclusive with --docker-host)")
 	runCmd.Flags().StringVar(&options.LogFilePath, "log", options.LogFilePath, "Path to log file for stderr output (relative or absolute)")
 
 	// Very experimental features
 	_ = runCmd.Flags().MarkHidden("log")
 
 	cmd.AddCommand(runCmd)
+	cmd.AddCommand(gatewayDoctorCommand(docker, features))
 
 	return cmd
 }
 
+func gatewayDoctorCommand(docker dockerpkg.Client, features features.Features) *cobra.Command {
+	var reportPath string
+	var registryURL string
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the environment the gateway runs in (Docker, Docker Desktop, database, catalogs, registry)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			checks := gateway.Doctor(cmd.Context(), gateway.DoctorOptions{
+				Docker:         docker,
+				DesktopRunning: desktop.CheckDesktopIsRunning,
+				OpenDB:         func() (db.DAO, error) { return db.New(db.WithReadOnly()) },
+				UseProfiles:    features.IsProfilesFeatureEnabled(),
+				CatalogPaths:   buildUniqueCatalogPaths([]string{catalog.DockerCatalogFilename}, getConfiguredCatalogPaths(), nil),
+				RegistryURL:    registryURL,
+				HTTPClient:     remoteurl.NewDirectHTTPClient(10 * time.Second),
+			})
+			printDoctorChecks(cmd.OutOrStdout(), checks)
+
+			if reportPath != "" {
+				if err := gateway.WriteDoctorReport(reportPath, checks); err != nil {
+					return err
+				}
+			}
+			if gateway.DoctorFailed(checks) {
+				return cli.StatusError{StatusCode: 1, Status: "some checks failed"}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&registryURL, "registry-url", registryapi.CommunityRegistryBaseURL, "Base URL of the MCP registry to check, e.g. a self-hosted registry")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the results of the checks to a JSON file, e.g. for CI. The command exits with a non-zero code if any check failed")
+	return cmd
+}
+
+func printDoctorChecks(w io.Writer, checks []gateway.DoctorCheck) {
+	for _, check := range checks {
+		fmt.Fprintf(w, "[%s] %s: %s\n", check.Status, check.Name, check.Detail)
+		if check.Hint != "" {
+			fmt.Fprintf(w, "       %s\n", check.Hint)
+		}
+	}
+}
+
 // getConfiguredCatalogPaths returns the file paths of all configured catalogs
 func getConfiguredCatalogPaths() []string {
 	cfg, err := catalog.ReadConfig()
@@ -1,13 +1,25 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	dockerpkg "github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/features"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
 )
 
 func TestGatewayRunCommandContainerAuthDefaults(t *testing.T) {
@@ -291,3 +303,47 @@ func TestConditionalConfiguredCatalogPaths(t *testing.T) {
 		assert.False(t, shouldExclude, "should include configured catalogs when single non-Docker catalog")
 	})
 }
+
+type unreachableDocker struct {
+	dockerpkg.Client
+}
+
+func (unreachableDocker) Ping(context.Context) error {
+	return errors.New("Cannot connect to the Docker daemon")
+}
+
+func TestGatewayDoctorReportAndExitCode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"servers":[]}`))
+	}))
+	t.Cleanup(registry.Close)
+
+	// The registry can be reached, but not the Docker daemon.
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	cmd := gatewayDoctorCommand(unreachableDocker{}, features.AllDisabled())
+	cmd.SetArgs([]string{"--report", reportPath, "--registry-url", registry.URL})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.ExecuteContext(t.Context())
+
+	var statusErr cli.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, 1, statusErr.StatusCode)
+	assert.Equal(t, "some checks failed", statusErr.Error())
+
+	buf, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	var report gateway.DoctorReport
+	require.NoError(t, json.Unmarshal(buf, &report))
+	assert.False(t, report.Passed)
+
+	statuses := map[string]gateway.DoctorStatus{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, gateway.DoctorFail, statuses["Docker daemon"])
+	assert.Equal(t, gateway.DoctorPass, statuses["MCP registry"])
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+const registryAuthFlag = "registry-auth"
+
+// addRegistryAuthFlag adds --registry-auth to a command and its subcommands,
+// which pull or push catalogs, profiles or images from OCI registries.
+func addRegistryAuthFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(registryAuthFlag, "", "Docker config file, or its directory, with the credentials of private registries (defaults to the Docker config and its credential helpers)")
+}
+
+// applyRegistryAuthFlag authenticates to registries with the Docker config
+// given with --registry-auth, if any. Run by the root command, since a
+// PersistentPreRunE of the command adding the flag would override the root's.
+func applyRegistryAuthFlag(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup(registryAuthFlag)
+	if flag == nil || flag.Value.String() == "" {
+		return nil
+	}
+	return oci.SetRegistryAuthConfig(flag.Value.String())
+}
@@ -57,6 +57,10 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli, features featu
 				return features.InitError()
 			}
 
+			if err := applyRegistryAuthFlag(cmd); err != nil {
+				return err
+			}
+
 			// Note: Using PersistentPreRunE in secretCommand would override this parent hook
 			if isSubcommandOf(cmd, []string{"secret"}) {
 				if err := desktop.CheckHasDockerPass(cmd.Context()); err != nil {
@@ -101,7 +105,7 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli, features featu
 	if features.IsProfilesFeatureEnabled() {
 		cmd.AddCommand(workingSetCommand(cwd))
 		cmd.AddCommand(templateCommand())
-		cmd.AddCommand(catalogNextCommand())
+		cmd.AddCommand(catalogNextCommand(dockerClient))
 		cmd.AddCommand(obsoleteCommand("config", "See `docker mcp profile config --help` instead."))
 	} else {
 		cmd.AddCommand(catalogCommand(dockerCli))
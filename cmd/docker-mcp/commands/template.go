@@ -98,7 +98,7 @@ Use 'docker mcp template list' to see available templates.`,
 			registryClient := registryapi.NewClient()
 			servers := []string{tmpl.CatalogServerRef()}
 
-			if err := workingset.Create(cmd.Context(), dao, registryClient, ociService, "", name, servers, opts.Connect); err != nil {
+			if err := workingset.Create(cmd.Context(), dao, registryClient, ociService, "", name, servers, opts.Connect, workingset.Output{}); err != nil {
 				return err
 			}
 
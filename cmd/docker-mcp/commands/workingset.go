@@ -1,14 +1,19 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/client"
 	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/registryapi"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
@@ -35,7 +40,9 @@ func workingSetCommand(cwd string) *cobra.Command {
 	cmd.AddCommand(workingsetServerCommand())
 	cmd.AddCommand(configWorkingSetCommand())
 	cmd.AddCommand(toolsWorkingSetCommand())
+	cmd.AddCommand(toolSecretsWorkingSetCommand())
 	cmd.AddCommand(manualInstructionsCommand())
+	addRegistryAuthFlag(cmd)
 	return cmd
 }
 
@@ -128,6 +135,43 @@ To view enabled tools, use: docker mcp profile show <profile-id>`,
 	return cmd
 }
 
+func toolSecretsWorkingSetCommand() *cobra.Command {
+	format := string(workingset.OutputFormatHumanReadable)
+	var secrets []string
+
+	cmd := &cobra.Command{
+		Use:   "tool-secrets <profile-id> [--secret <name> ...]",
+		Short: "List the enabled tools of a profile with the secrets they could use",
+		Long: `List the enabled tools of a profile with the secrets required by the server
+providing each of them, i.e. the credentials a tool could use if called.
+
+Use --secret to only list the tools whose server requires one of these secrets.`,
+		Example: `  # List the tools of a profile with the secrets of their servers
+  docker mcp profile tool-secrets my-profile
+
+  # List the tools that could use the GitHub personal access token
+  docker mcp profile tool-secrets my-profile --secret github.personal_access_token --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supported := slices.Contains(workingset.SupportedFormats(), format)
+			if !supported {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.ShowToolSecrets(cmd.Context(), dao, oci.NewService(), args[0], secrets, workingset.OutputFormat(format))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&secrets, "secret", []string{}, "Only list the tools whose server requires this secret (repeatable)")
+	flags.StringVar(&format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
+
+	return cmd
+}
+
 func createWorkingSetCommand(cfg *client.Config) *cobra.Command {
 	var opts struct {
 		ID           string
@@ -135,6 +179,9 @@ func createWorkingSetCommand(cfg *client.Config) *cobra.Command {
 		Servers      []string
 		Connect      []string
 		FromTemplate string
+		Format       string
+		Quiet        bool
+		ImagePolicy  imagepolicy.Policy
 	}
 
 	cmd := &cobra.Command{
@@ -146,8 +193,14 @@ Profiles are decoupled from catalogs. Servers can be:
   - MCP Registry references (e.g. http://registry.modelcontextprotocol.io/v0/servers/312e45a4-2216-4b21-b9a8-0f1a51425073)
   - OCI image references with docker:// prefix (e.g., "docker://my-server:latest"). Images must be self-describing.
   - Catalog references with catalog:// prefix (e.g., "catalog://mcp/docker-mcp-catalog/github+obsidian").
+    Pin the catalog to a digest with "catalog://<ref>@sha256:<digest>/<servers>".
+    Exclude servers with "!" patterns, e.g. "catalog://mcp/docker-mcp-catalog/*+!deprecated-*".
   - Local file references with file:// prefix (e.g., "file://./server.yaml").
 
+Use --allow-image and --deny-image to restrict the images of the servers. The
+policy is stored with the profile: it also applies to the servers added later
+on, and the gateway enforces it when it runs the profile.
+
 Alternatively, use --from-template to create a profile from a starter template.
 Use 'docker mcp template list' to see available templates.`,
 		Example: `  # Create a profile from a starter template
@@ -172,6 +225,14 @@ Use 'docker mcp template list' to see available templates.`,
 			if opts.FromTemplate != "" && len(opts.Servers) > 0 {
 				return fmt.Errorf("--from-template and --server are mutually exclusive")
 			}
+			output, err := commandOutput(opts.Format, opts.Quiet)
+			if err != nil {
+				return err
+			}
+			if err := opts.ImagePolicy.Validate(); err != nil {
+				return err
+			}
+			imagePolicy := workingset.WithImagePolicy(opts.ImagePolicy)
 
 			if opts.FromTemplate != "" {
 				tmpl := template.FindByID(opts.FromTemplate)
@@ -194,7 +255,7 @@ Use 'docker mcp template list' to see available templates.`,
 				}
 
 				registryClient := registryapi.NewClient()
-				if err := workingset.Create(cmd.Context(), dao, registryClient, ociService, opts.ID, opts.Name, opts.Servers, opts.Connect); err != nil {
+				if err := workingset.Create(cmd.Context(), dao, registryClient, ociService, opts.ID, opts.Name, opts.Servers, opts.Connect, output, imagePolicy); err != nil {
 					return err
 				}
 
@@ -213,16 +274,18 @@ Use 'docker mcp template list' to see available templates.`,
 			}
 			registryClient := registryapi.NewClient()
 			ociService := oci.NewService()
-			return workingset.Create(cmd.Context(), dao, registryClient, ociService, opts.ID, opts.Name, opts.Servers, opts.Connect)
+			return workingset.Create(cmd.Context(), dao, registryClient, ociService, opts.ID, opts.Name, opts.Servers, opts.Connect, output, imagePolicy)
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Name, "name", "", "Name of the profile (required unless --from-template is used)")
 	flags.StringVar(&opts.ID, "id", "", "ID of the profile (defaults to a slugified version of the name)")
-	flags.StringArrayVar(&opts.Servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs). Can be specified multiple times.")
+	flags.StringArrayVar(&opts.Servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs) or openapi:// (OpenAPI document URL without https://, wrapped with the mcp/openapi-proxy:v1 image). Can be specified multiple times.")
 	flags.StringArrayVar(&opts.Connect, "connect", []string{}, fmt.Sprintf("Clients to connect to: mcp-client (can be specified multiple times). Supported clients: %s", client.GetSupportedMCPClients(*cfg)))
 	flags.StringVar(&opts.FromTemplate, "from-template", "", "Create profile from a starter template (use `docker mcp template list` to see options)")
+	addImagePolicyFlags(flags, &opts.ImagePolicy)
+	addOutputFlags(flags, &opts.Format, &opts.Quiet)
 
 	return cmd
 }
@@ -257,6 +320,7 @@ func listWorkingSetsCommand() *cobra.Command {
 func showWorkingSetCommand() *cobra.Command {
 	format := string(workingset.OutputFormatHumanReadable)
 	var showClients bool
+	var showRun bool
 	var yqExpr string
 
 	cmd := &cobra.Command{
@@ -268,10 +332,16 @@ func showWorkingSetCommand() *cobra.Command {
 			if !supported {
 				return fmt.Errorf("unsupported format: %s", format)
 			}
+			if showRun && (showClients || yqExpr != "") {
+				return fmt.Errorf("--run cannot be combined with --clients or --yq")
+			}
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
+			if showRun {
+				return workingset.ShowRunPlan(cmd.Context(), dao, oci.NewService(), args[0], runPlanArgs(), workingset.OutputFormat(format))
+			}
 			return workingset.Show(cmd.Context(), dao, args[0], workingset.OutputFormat(format), showClients, yqExpr)
 		},
 	}
@@ -279,6 +349,7 @@ func showWorkingSetCommand() *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&format, "format", string(workingset.OutputFormatHumanReadable), fmt.Sprintf("Supported: %s.", strings.Join(workingset.SupportedFormats(), ", ")))
 	flags.BoolVar(&showClients, "clients", false, "Include client information in output")
+	flags.BoolVar(&showRun, "run", false, "Show the effective config and run command of each server")
 	flags.StringVar(&yqExpr, "yq", "", "YQ expression to apply to the output")
 	return cmd
 }
@@ -315,34 +386,73 @@ func pushWorkingSetCommand() *cobra.Command {
 }
 
 func exportWorkingSetCommand() *cobra.Command {
-	return &cobra.Command{
+	var opts workingset.BundleOptions
+
+	cmd := &cobra.Command{
 		Use:   "export <profile-id> <output-file>",
 		Short: "Export profile to file",
-		Args:  cobra.ExactArgs(2),
+		Long: `Export a profile to a .yaml or .json file.
+
+Export to a .tar file to bundle the profile with its catalogs and the arguments
+to run the gateway with, to move the setup to another machine. Config values
+that look like secrets are left out of the bundle.`,
+		Example: `  # Export a profile
+  docker mcp profile export my-profile my-profile.yaml
+
+  # Bundle a profile with its catalogs and gateway arguments
+  docker mcp profile export my-profile setup.tar --gateway-arg=--long-lived`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			isBundle := strings.HasSuffix(strings.ToLower(args[1]), ".tar")
+			if !isBundle && (len(opts.Catalogs) > 0 || len(opts.GatewayArgs) > 0) {
+				return fmt.Errorf("--catalog and --gateway-arg can only be used when exporting to a .tar bundle")
+			}
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
+			if isBundle {
+				return workingset.ExportBundle(cmd.Context(), dao, args[0], args[1], opts)
+			}
 			return workingset.Export(cmd.Context(), dao, args[0], args[1])
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&opts.Catalogs, "catalog", nil, "Catalog to add to the .tar bundle (defaults to the catalogs the profile's servers were added from). Can be repeated")
+	flags.StringArrayVar(&opts.GatewayArgs, "gateway-arg", nil, "Argument to run the gateway with, recorded in the .tar bundle (e.g. '--gateway-arg=--long-lived'). Can be repeated")
+
+	return cmd
 }
 
 func importWorkingSetCommand() *cobra.Command {
-	return &cobra.Command{
+	var replaceCatalogs bool
+
+	cmd := &cobra.Command{
 		Use:   "import <input-file>",
 		Short: "Import profile from file",
-		Args:  cobra.ExactArgs(1),
+		Long: `Import a profile from a .yaml or .json file, or a profile and its catalogs
+from a .tar bundle created with 'docker mcp profile export'.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
 			ociService := oci.NewService()
+			if strings.HasSuffix(strings.ToLower(args[0]), ".tar") {
+				return workingset.ImportBundle(cmd.Context(), dao, ociService, args[0], replaceCatalogs)
+			}
+			if replaceCatalogs {
+				return fmt.Errorf("--replace-catalogs can only be used when importing a .tar bundle")
+			}
 			return workingset.Import(cmd.Context(), dao, ociService, args[0])
 		},
 	}
+
+	cmd.Flags().BoolVar(&replaceCatalogs, "replace-catalogs", false, "Replace the catalogs with the same reference as the catalogs of the .tar bundle but a different content, instead of failing")
+
+	return cmd
 }
 
 func removeWorkingSetCommand(cwd string) *cobra.Command {
@@ -431,6 +541,9 @@ func workingsetServerCommand() *cobra.Command {
 
 func addServerCommand() *cobra.Command {
 	var servers []string
+	var format string
+	var quiet bool
+	var imagePolicy imagepolicy.Policy
 
 	cmd := &cobra.Command{
 		Use:   "add <profile-id> [--server <ref1> --server <ref2> ...]",
@@ -455,22 +568,72 @@ func addServerCommand() *cobra.Command {
   docker mcp profile server add dev-tools --server catalog://mcp/docker-mcp-catalog/github+obsidian --server docker://my-server:latest`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := commandOutput(format, quiet)
+			if err != nil {
+				return err
+			}
+			if err := imagePolicy.Validate(); err != nil {
+				return err
+			}
+
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
 			registryClient := registryapi.NewClient()
 			ociService := oci.NewService()
-			return workingset.AddServers(cmd.Context(), dao, registryClient, ociService, args[0], servers)
+			return workingset.AddServers(cmd.Context(), dao, registryClient, ociService, args[0], servers, output, workingset.WithImagePolicy(imagePolicy))
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringArrayVar(&servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs). Can be specified multiple times.")
+	flags.StringArrayVar(&servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference) or file:// (Local file path that resolves under ~/.docker/mcp/catalogs) or openapi:// (OpenAPI document URL without https://, wrapped with the mcp/openapi-proxy:v1 image). Can be specified multiple times.")
+	addImagePolicyFlags(flags, &imagePolicy)
+	addOutputFlags(flags, &format, &quiet)
 
 	return cmd
 }
 
+// addProfileOutputFlags registers --format and --quiet for the commands
+// changing a profile.
+func addOutputFlags(flags *pflag.FlagSet, format *string, quiet *bool) {
+	flags.StringVar(format, "format", string(workingset.OutputFormatHumanReadable), "Supported: human, json. With json, only the result is printed")
+	addQuietFlag(flags, quiet)
+}
+
+// addImagePolicyFlags registers --allow-image and --deny-image for the
+// commands adding servers to a profile.
+func addImagePolicyFlags(flags *pflag.FlagSet, policy *imagepolicy.Policy) {
+	flags.StringArrayVar(&policy.Allow, "allow-image", nil, "Only allow servers whose image matches this pattern (e.g. 'mcp/*', 'registry.internal/*'). Can be specified multiple times.")
+	flags.StringArrayVar(&policy.Deny, "deny-image", nil, "Reject servers whose image matches this pattern, even if allowed. Can be specified multiple times.")
+}
+
+func commandOutput(format string, quiet bool) (workingset.Output, error) {
+	switch workingset.OutputFormat(format) {
+	case workingset.OutputFormatHumanReadable, workingset.OutputFormatJSON:
+	default:
+		return workingset.Output{}, fmt.Errorf("unsupported format: %s", format)
+	}
+	return workingset.Output{Format: workingset.OutputFormat(format), Quiet: quiet}, nil
+}
+
+// runPlanArgs computes the run commands of servers with the default options
+// of `docker mcp gateway run`.
+func runPlanArgs() workingset.RunArgs {
+	options := gateway.Options{
+		Cpus:   defaultServerCpus,
+		Memory: defaultServerMemory,
+	}
+	return workingset.RunArgs{
+		Container: func(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+			return gateway.ContainerRunArgs(ctx, options, serverConfig)
+		},
+		LocalCommand: func(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+			return gateway.LocalCommandRunArgs(ctx, options, serverConfig)
+		},
+	}
+}
+
 func removeServerCommand() *cobra.Command {
 	var names []string
 
@@ -36,7 +36,7 @@ func Call(ctx context.Context, version string, gatewayArgs []string, debug bool,
 
 	params := &mcp.CallToolParams{
 		Name:      toolName,
-		Arguments: parseArgs(args[1:]),
+		Arguments: ParseArgs(args[1:]),
 	}
 
 	start := time.Now()
@@ -62,15 +62,16 @@ func Call(ctx context.Context, version string, gatewayArgs []string, debug bool,
 	fmt.Println("Tool call took:", duration)
 
 	if response.IsError {
-		return fmt.Errorf("error calling tool %s: %s", toolName, toText(response))
+		return fmt.Errorf("error calling tool %s: %s", toolName, ToText(response))
 	}
 
-	fmt.Println(toText(response))
+	fmt.Println(ToText(response))
 
 	return nil
 }
 
-func toText(response *mcp.CallToolResult) string {
+// ToText returns the text content of a tool result, one item per line.
+func ToText(response *mcp.CallToolResult) string {
 	var contents []string
 
 	for _, content := range response.Content {
@@ -84,7 +85,9 @@ func toText(response *mcp.CallToolResult) string {
 	return strings.Join(contents, "\n")
 }
 
-func parseArgs(args []string) map[string]any {
+// ParseArgs parses tool arguments given as <key>=<value>. Keys given several
+// times have a list of values.
+func ParseArgs(args []string) map[string]any {
 	parsed := map[string]any{}
 
 	for _, arg := range args {
@@ -336,18 +336,18 @@ func TestToText(t *testing.T) {
 			&mcp.TextContent{Text: "Second"},
 		},
 	}
-	result := toText(response)
+	result := ToText(response)
 	assert.Equal(t, "First\nSecond", result)
 }
 
 func TestParseArgs(t *testing.T) {
 	// Test key=value parsing
-	result := parseArgs([]string{"key1=value1", "key2=value2"})
+	result := ParseArgs([]string{"key1=value1", "key2=value2"})
 	expected := map[string]any{"key1": "value1", "key2": "value2"}
 	assert.Equal(t, expected, result)
 
 	// Test duplicate keys become arrays
-	result = parseArgs([]string{"tag=red", "tag=blue"})
+	result = ParseArgs([]string{"tag=red", "tag=blue"})
 	expected = map[string]any{"tag": []any{"red", "blue"}}
 	assert.Equal(t, expected, result)
 }
@@ -62,6 +62,26 @@ func ReadOne(ctx context.Context, fileOrURL string) (Catalog, string, string, er
 	}, name, displayName, nil
 }
 
+// ReadURL reads a catalog from an http(s) URL with client, or with a guarded
+// direct client if client is nil. It returns the catalog, its name and its
+// display name.
+func ReadURL(ctx context.Context, client *http.Client, rawURL string) (Catalog, string, string, error) {
+	if !isURL(rawURL) {
+		return Catalog{}, "", "", fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+	buf, err := fetchURL(ctx, client, rawURL)
+	if err != nil {
+		return Catalog{}, "", "", err
+	}
+	servers, name, displayName, err := parseMCPServers(buf)
+	if err != nil {
+		return Catalog{}, "", "", err
+	}
+	return Catalog{
+		Servers: servers,
+	}, name, displayName, nil
+}
+
 func readMCPServers(ctx context.Context, fileOrURL string) (map[string]Server, string, string, error) {
 	buf, err := readFileOrURL(ctx, fileOrURL)
 	if err != nil {
@@ -71,6 +91,10 @@ func readMCPServers(ctx context.Context, fileOrURL string) (map[string]Server, s
 		return nil, "", "", err
 	}
 
+	return parseMCPServers(buf)
+}
+
+func parseMCPServers(buf []byte) (map[string]Server, string, string, error) {
 	var topLevel topLevel
 	if err := yaml.Unmarshal(buf, &topLevel); err != nil {
 		return nil, "", "", err
@@ -82,26 +106,7 @@ func readMCPServers(ctx context.Context, fileOrURL string) (map[string]Server, s
 func readFileOrURL(ctx context.Context, fileOrURL string) ([]byte, error) {
 	switch {
 	case isURL(fileOrURL):
-		fileOrURL = remoteurl.UpgradeKnownHTTPURLToHTTPS(fileOrURL)
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileOrURL, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		client := remoteurl.NewDirectHTTPClient(30 * time.Second)
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("failed to fetch URL: %s, status: %s", fileOrURL, resp.Status)
-		}
-
-		return io.ReadAll(resp.Body)
+		return fetchURL(ctx, nil, fileOrURL)
 
 	default:
 		path, err := ResolveLocalCatalogPath(fileOrURL)
@@ -120,6 +125,31 @@ func readFileOrURL(ctx context.Context, fileOrURL string) ([]byte, error) {
 	}
 }
 
+func fetchURL(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	rawURL = remoteurl.UpgradeKnownHTTPURLToHTTPS(rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = remoteurl.NewDirectHTTPClient(30 * time.Second)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch URL: %s, status: %s", rawURL, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
 func ResolveLocalCatalogPath(path string) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("local file path is required")
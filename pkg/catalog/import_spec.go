@@ -9,7 +9,7 @@ package catalog
 // configured catalog sources. Image-label content originates from
 // whoever published the image and goes through this narrower type,
 // which omits runtime-shaping fields (Command, Volumes, User,
-// ExtraHosts, AllowHosts, DisableNetwork, Remote endpoint config,
+// ExtraHosts, Labels, AllowHosts, DisableNetwork, Remote endpoint config,
 // SSE endpoint, OAuth providers, LongLived, Policy, Secrets, and
 // Env values).
 //
@@ -33,6 +33,9 @@ type ImportedServer struct {
 	Config      []any          `yaml:"config,omitempty" json:"config,omitempty"`
 	Prefix      string         `yaml:"prefix,omitempty" json:"prefix,omitempty"`
 	Metadata    *Metadata      `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	// Capabilities are the capabilities the server advertises besides its
+	// tools, which are implied by Tools.
+	Capabilities *Capabilities `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
 }
 
 // ImportedEnv is the env declaration schema for imported sources. Only the
@@ -86,5 +89,13 @@ func (i ImportedServer) ToServer() Server {
 			}
 		}
 	}
+	if i.Capabilities != nil || len(i.Tools) > 0 {
+		capabilities := Capabilities{}
+		if i.Capabilities != nil {
+			capabilities = *i.Capabilities
+		}
+		capabilities.Tools = capabilities.Tools || len(i.Tools) > 0
+		s.Capabilities = &capabilities
+	}
 	return s
 }
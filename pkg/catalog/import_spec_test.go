@@ -153,3 +153,36 @@ func TestImportedServer_DropsRuntimeFields_JSON(t *testing.T) {
 	assert.Equal(t, "DOCKER_HOST", srv.Env[0].Name)
 	assert.Empty(t, srv.Env[0].Value)
 }
+
+func TestImportedServer_Capabilities(t *testing.T) {
+	var imported ImportedServer
+	require.NoError(t, yaml.Unmarshal([]byte(`
+name: tools-only
+tools:
+  - name: search
+`), &imported))
+
+	srv := imported.ToServer()
+	require.NotNil(t, srv.Capabilities)
+	assert.Equal(t, Capabilities{Tools: true}, *srv.Capabilities)
+
+	// Capabilities besides tools are taken as advertised.
+	var withPrompts ImportedServer
+	require.NoError(t, yaml.Unmarshal([]byte(`
+name: prompts
+capabilities:
+  prompts: true
+`), &withPrompts))
+
+	srv = withPrompts.ToServer()
+	require.NotNil(t, srv.Capabilities)
+	assert.Equal(t, Capabilities{Prompts: true}, *srv.Capabilities)
+
+	// Nothing is known about a server without tools or capabilities.
+	var unknown ImportedServer
+	require.NoError(t, yaml.Unmarshal([]byte(`
+name: unknown
+`), &unknown))
+
+	assert.Nil(t, unknown.ToServer().Capabilities)
+}
@@ -0,0 +1,319 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIProxyImage is the generic image that exposes the operations of an
+// OpenAPI document as MCP tools. It reads the document from OPENAPI_SPEC_URL
+// and calls the API at OPENAPI_BASE_URL, or at the first server of the
+// document. It's pinned, so that servers don't change behavior when the image
+// is updated.
+const OpenAPIProxyImage = "mcp/openapi-proxy:v1"
+
+// ErrNotOpenAPI is returned by FromOpenAPI for documents that are not OpenAPI 3
+// documents.
+var ErrNotOpenAPI = errors.New("not an OpenAPI 3 document")
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type openAPIDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Info    struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Components struct {
+		Parameters      map[string]openAPIParameter      `yaml:"parameters"`
+		SecuritySchemes map[string]openAPISecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `yaml:"operationId"`
+	Summary     string             `yaml:"summary"`
+	Description string             `yaml:"description"`
+	Parameters  []openAPIParameter `yaml:"parameters"`
+	RequestBody *struct {
+		Description string `yaml:"description"`
+		Required    bool   `yaml:"required"`
+	} `yaml:"requestBody"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Type  string         `yaml:"type"`
+	Items *openAPISchema `yaml:"items"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+	Name   string `yaml:"name"`
+	In     string `yaml:"in"`
+}
+
+// FromOpenAPI builds a catalog server that runs OpenAPIProxyImage for the
+// OpenAPI 3 document (JSON or YAML) served at specURL. Each operation of the
+// document becomes a tool, named after its operationId, or after its method
+// and path when it has none. The base URL of the API is a config property,
+// and the credentials of the security schemes are secrets.
+func FromOpenAPI(doc []byte, specURL string) (Server, error) {
+	var spec openAPIDocument
+	if err := yaml.Unmarshal(doc, &spec); err != nil {
+		return Server{}, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return Server{}, ErrNotOpenAPI
+	}
+	if spec.Info.Title == "" {
+		return Server{}, fmt.Errorf("OpenAPI document has no info.title")
+	}
+
+	serverName := openAPIServerName(spec.Info.Title)
+	server := Server{
+		Name:        serverName,
+		Type:        "server",
+		Image:       OpenAPIProxyImage,
+		Title:       spec.Info.Title,
+		Description: spec.Info.Description,
+		Env: []Env{
+			{Name: "OPENAPI_SPEC_URL", Value: specURL},
+			{Name: "OPENAPI_BASE_URL", Value: fmt.Sprintf("{{%s.base_url}}", serverName)},
+		},
+		Metadata: &Metadata{Tags: []string{"openapi"}},
+	}
+
+	baseURL := map[string]any{
+		"type":        "string",
+		"description": "Base URL of the API. Defaults to the first server of the OpenAPI document",
+	}
+	if len(spec.Servers) > 0 {
+		baseURL["default"] = spec.Servers[0].URL
+	}
+	properties := map[string]any{"base_url": baseURL}
+
+	for _, schemeName := range slices.Sorted(maps.Keys(spec.Components.SecuritySchemes)) {
+		scheme := spec.Components.SecuritySchemes[schemeName]
+		switch {
+		case scheme.Type == "apiKey":
+			server.Secrets = appendSecret(server.Secrets, Secret{Name: serverName + ".api_key", Env: "OPENAPI_API_KEY"})
+			server.Env = append(server.Env,
+				Env{Name: "OPENAPI_API_KEY_NAME", Value: scheme.Name},
+				Env{Name: "OPENAPI_API_KEY_IN", Value: scheme.In},
+			)
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			server.Secrets = appendSecret(server.Secrets, Secret{Name: serverName + ".password", Env: "OPENAPI_PASSWORD"})
+			server.Env = append(server.Env, Env{Name: "OPENAPI_USERNAME", Value: fmt.Sprintf("{{%s.username}}", serverName)})
+			properties["username"] = map[string]any{
+				"type":        "string",
+				"description": "Username for HTTP basic authentication",
+			}
+		default:
+			// http bearer, oauth2 and openIdConnect all end up as a bearer token.
+			server.Secrets = appendSecret(server.Secrets, Secret{Name: serverName + ".bearer_token", Env: "OPENAPI_BEARER_TOKEN"})
+		}
+	}
+
+	server.Config = []any{map[string]any{
+		"name":        serverName,
+		"type":        "object",
+		"description": fmt.Sprintf("Configuration for %s", spec.Info.Title),
+		"properties":  properties,
+	}}
+
+	tools, err := openAPITools(spec)
+	if err != nil {
+		return Server{}, err
+	}
+	server.Tools = tools
+
+	return server, nil
+}
+
+func appendSecret(secrets []Secret, secret Secret) []Secret {
+	if slices.Contains(secrets, secret) {
+		return secrets
+	}
+	return append(secrets, secret)
+}
+
+// openAPITools returns one tool per operation, sorted by path then method.
+func openAPITools(spec openAPIDocument) ([]Tool, error) {
+	var tools []Tool
+	seen := map[string]bool{}
+
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		pathItem := spec.Paths[path]
+
+		var pathParameters []openAPIParameter
+		if node, ok := pathItem["parameters"]; ok {
+			if err := node.Decode(&pathParameters); err != nil {
+				return nil, fmt.Errorf("invalid parameters for %s: %w", path, err)
+			}
+		}
+
+		for _, method := range openAPIMethods {
+			node, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+			var operation openAPIOperation
+			if err := node.Decode(&operation); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			name := openAPIToolName(operation.OperationID, method, path)
+			if seen[name] {
+				return nil, fmt.Errorf("duplicate tool name %s for %s %s", name, strings.ToUpper(method), path)
+			}
+			seen[name] = true
+
+			description := operation.Summary
+			if description == "" {
+				description = operation.Description
+			}
+			if description == "" {
+				description = fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+			}
+
+			arguments, err := openAPIArguments(spec, append(slices.Clone(pathParameters), operation.Parameters...))
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameters for %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if operation.RequestBody != nil {
+				arguments = append(arguments, ToolArgument{
+					Name:        "body",
+					Type:        "object",
+					Description: operation.RequestBody.Description,
+					Optional:    !operation.RequestBody.Required,
+				})
+			}
+
+			tool := Tool{
+				Name:        name,
+				Description: description,
+				Annotations: openAPIAnnotations(method),
+			}
+			if len(arguments) > 0 {
+				tool.Arguments = &arguments
+			}
+			tools = append(tools, tool)
+		}
+	}
+
+	return tools, nil
+}
+
+// openAPIArguments converts the parameters of an operation to tool arguments.
+// Operation parameters override path parameters with the same name and
+// location.
+func openAPIArguments(spec openAPIDocument, parameters []openAPIParameter) ([]ToolArgument, error) {
+	var arguments []ToolArgument
+	index := map[string]int{}
+
+	for _, parameter := range parameters {
+		if parameter.Ref != "" {
+			resolved, ok := spec.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+			if !ok {
+				return nil, fmt.Errorf("unresolved parameter reference %s", parameter.Ref)
+			}
+			parameter = resolved
+		}
+		if parameter.In == "cookie" {
+			continue
+		}
+
+		argument := ToolArgument{
+			Name:        parameter.Name,
+			Type:        "string",
+			Description: parameter.Description,
+			Optional:    !parameter.Required && parameter.In != "path",
+		}
+		if parameter.Schema != nil && parameter.Schema.Type != "" {
+			argument.Type = parameter.Schema.Type
+			if parameter.Schema.Items != nil && parameter.Schema.Items.Type != "" {
+				argument.Items = &Items{Type: parameter.Schema.Items.Type}
+			}
+		}
+
+		key := parameter.In + ":" + parameter.Name
+		if i, ok := index[key]; ok {
+			arguments[i] = argument
+			continue
+		}
+		index[key] = len(arguments)
+		arguments = append(arguments, argument)
+	}
+
+	return arguments, nil
+}
+
+func openAPIAnnotations(method string) *ToolAnnotations {
+	readOnly := method == "get" || method == "head" || method == "options"
+	annotations := &ToolAnnotations{ReadOnlyHint: &readOnly}
+	if method == "delete" {
+		destructive := true
+		annotations.DestructiveHint = &destructive
+	}
+	if method == "put" || method == "delete" {
+		idempotent := true
+		annotations.IdempotentHint = &idempotent
+	}
+	return annotations
+}
+
+// openAPIToolName returns the operationId, or method_path when there is none,
+// with any character not allowed in tool names replaced by '_'.
+func openAPIToolName(operationID, method, path string) string {
+	name := operationID
+	if name == "" {
+		name = method + "_" + path
+	}
+
+	// Runs of other characters, e.g. the slashes and braces of a path,
+	// become a single '_'.
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else if !strings.HasSuffix(b.String(), "_") {
+			b.WriteRune('_')
+		}
+	}
+	name = strings.Trim(b.String(), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// openAPIServerName returns a server name derived from the title of the
+// document, e.g. "Pet Store API" becomes "pet-store-api".
+func openAPIServerName(title string) string {
+	parts := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	if len(parts) == 0 {
+		return "openapi"
+	}
+	return strings.Join(parts, "-")
+}
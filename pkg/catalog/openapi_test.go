@@ -0,0 +1,195 @@
+package catalog
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+const petStoreOpenAPI = `openapi: 3.0.3
+info:
+  title: Pet Store API
+  description: Manage the pets of the store
+servers:
+  - url: https://petstore.example.com/v1
+components:
+  parameters:
+    limit:
+      name: limit
+      in: query
+      description: Maximum number of pets to return
+      schema:
+        type: integer
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - $ref: '#/components/parameters/limit'
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        description: The pet to create
+        required: true
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        description: ID of the pet
+        schema:
+          type: string
+    get:
+      description: Get a pet by ID
+    delete:
+      operationId: deletePet
+      summary: Delete a pet
+`
+
+func TestFromOpenAPI(t *testing.T) {
+	server, err := FromOpenAPI([]byte(petStoreOpenAPI), "https://petstore.example.com/openapi.yaml")
+	if err != nil {
+		t.Fatalf("FromOpenAPI failed: %v", err)
+	}
+
+	if server.Name != "pet-store-api" {
+		t.Errorf("Expected name pet-store-api, got %s", server.Name)
+	}
+	if server.Type != "server" || server.Image != "mcp/openapi-proxy:v1" {
+		t.Errorf("Expected an image server running the pinned mcp/openapi-proxy:v1, got %s %s", server.Type, server.Image)
+	}
+	if server.Title != "Pet Store API" || server.Description != "Manage the pets of the store" {
+		t.Errorf("Unexpected title %q or description %q", server.Title, server.Description)
+	}
+
+	expectedEnv := []Env{
+		{Name: "OPENAPI_SPEC_URL", Value: "https://petstore.example.com/openapi.yaml"},
+		{Name: "OPENAPI_BASE_URL", Value: "{{pet-store-api.base_url}}"},
+		{Name: "OPENAPI_API_KEY_NAME", Value: "X-API-Key"},
+		{Name: "OPENAPI_API_KEY_IN", Value: "header"},
+	}
+	if !slices.Equal(server.Env, expectedEnv) {
+		t.Errorf("Expected env %v, got %v", expectedEnv, server.Env)
+	}
+	expectedSecrets := []Secret{{Name: "pet-store-api.api_key", Env: "OPENAPI_API_KEY"}}
+	if !slices.Equal(server.Secrets, expectedSecrets) {
+		t.Errorf("Expected secrets %v, got %v", expectedSecrets, server.Secrets)
+	}
+
+	if len(server.Config) != 1 {
+		t.Fatalf("Expected 1 config item, got %d", len(server.Config))
+	}
+	properties := server.Config[0].(map[string]any)["properties"].(map[string]any)
+	if baseURL := properties["base_url"].(map[string]any)["default"]; baseURL != "https://petstore.example.com/v1" {
+		t.Errorf("Expected base_url to default to the first server, got %v", baseURL)
+	}
+	if fields := SecretLikeConfigFields(server); len(fields) != 0 {
+		t.Errorf("Expected no secret-like config fields, got %v", fields)
+	}
+
+	var names []string
+	for _, tool := range server.Tools {
+		names = append(names, tool.Name)
+	}
+	expectedNames := []string{"listPets", "createPet", "get_pets_petId", "deletePet"}
+	if !slices.Equal(names, expectedNames) {
+		t.Fatalf("Expected tools %v, got %v", expectedNames, names)
+	}
+
+	listPets := server.Tools[0]
+	if listPets.Description != "List pets" || !*listPets.Annotations.ReadOnlyHint {
+		t.Errorf("Expected a read-only listPets tool, got %+v", listPets)
+	}
+	expectedArgs := []ToolArgument{
+		{Name: "limit", Type: "integer", Description: "Maximum number of pets to return", Optional: true},
+		{Name: "tags", Type: "array", Items: &Items{Type: "string"}, Optional: true},
+	}
+	if len(*listPets.Arguments) != 2 || (*listPets.Arguments)[0] != expectedArgs[0] || (*listPets.Arguments)[1].Items.Type != "string" {
+		t.Errorf("Expected arguments %v, got %v", expectedArgs, *listPets.Arguments)
+	}
+
+	createPet := server.Tools[1]
+	if *createPet.Annotations.ReadOnlyHint {
+		t.Errorf("Expected createPet not to be read-only")
+	}
+	if args := *createPet.Arguments; len(args) != 1 || args[0] != (ToolArgument{Name: "body", Type: "object", Description: "The pet to create"}) {
+		t.Errorf("Expected a required body argument, got %v", args)
+	}
+
+	getPet := server.Tools[2]
+	if getPet.Description != "Get a pet by ID" {
+		t.Errorf("Expected the description to fall back to the operation description, got %q", getPet.Description)
+	}
+	if args := *getPet.Arguments; len(args) != 1 || args[0] != (ToolArgument{Name: "petId", Type: "string", Description: "ID of the pet"}) {
+		t.Errorf("Expected the required path parameter, got %v", args)
+	}
+
+	deletePet := server.Tools[3]
+	if deletePet.Annotations.DestructiveHint == nil || !*deletePet.Annotations.DestructiveHint {
+		t.Errorf("Expected deletePet to be destructive")
+	}
+}
+
+func TestFromOpenAPIAuth(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Internal API
+components:
+  securitySchemes:
+    basic:
+      type: http
+      scheme: basic
+    bearer:
+      type: http
+      scheme: bearer
+    oauth:
+      type: oauth2
+paths: {}
+`
+	server, err := FromOpenAPI([]byte(spec), "https://internal.example.com/openapi.yaml")
+	if err != nil {
+		t.Fatalf("FromOpenAPI failed: %v", err)
+	}
+
+	expectedSecrets := []Secret{
+		{Name: "internal-api.password", Env: "OPENAPI_PASSWORD"},
+		{Name: "internal-api.bearer_token", Env: "OPENAPI_BEARER_TOKEN"},
+	}
+	if !slices.Equal(server.Secrets, expectedSecrets) {
+		t.Errorf("Expected secrets %v, got %v", expectedSecrets, server.Secrets)
+	}
+	if !slices.Contains(server.Env, Env{Name: "OPENAPI_USERNAME", Value: "{{internal-api.username}}"}) {
+		t.Errorf("Expected the username to come from config, got %v", server.Env)
+	}
+	if len(server.Tools) != 0 {
+		t.Errorf("Expected no tools, got %v", server.Tools)
+	}
+}
+
+func TestFromOpenAPIRejectsOtherDocuments(t *testing.T) {
+	for _, doc := range []string{
+		"swagger: '2.0'\ninfo:\n  title: Old API\n",
+		"name: github\ntype: server\n",
+	} {
+		if _, err := FromOpenAPI([]byte(doc), "https://example.com/spec"); !errors.Is(err, ErrNotOpenAPI) {
+			t.Errorf("Expected ErrNotOpenAPI for %q, got %v", doc, err)
+		}
+	}
+
+	if _, err := FromOpenAPI([]byte("openapi: 3.0.0\ninfo: {}\n"), "https://example.com/spec"); err == nil {
+		t.Errorf("Expected an error for a document without a title")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"maps"
 	"strings"
 
 	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
@@ -25,16 +27,23 @@ const (
 	TransformSourcePyPI   TransformSource = "pypi"
 	TransformSourceNPM    TransformSource = "npm"
 	TransformSourceRemote TransformSource = "remote"
+	// TransformSourcePOCI is used for servers without a package or remote
+	// whose publisher describes tools that each run their own container.
+	TransformSourcePOCI TransformSource = "poci"
 )
 
 // TransformOption configures the behavior of TransformToDocker.
 type TransformOption func(*transformOptions)
 
 type transformOptions struct {
-	allowPyPI    bool
-	pypiResolver PyPIVersionResolver
-	allowNPM     bool
-	npmResolver  NPMVersionResolver
+	allowPyPI     bool
+	pypiResolver  PyPIVersionResolver
+	allowNPM      bool
+	npmResolver   NPMVersionResolver
+	groupedConfig bool
+	strictConfig  bool
+	serverName    string
+	cache         *TransformCache
 }
 
 // WithAllowPyPI controls whether PyPI packages are considered during transformation.
@@ -69,6 +78,35 @@ func WithNPMResolver(resolver NPMVersionResolver) TransformOption {
 	}
 }
 
+// WithGroupedConfig controls whether config variables are kept in one config
+// item per environment variable that groups them (through nested variables),
+// instead of being merged into a single config item named after the server.
+// Variables that don't belong to a group stay in the server config item.
+// By default, all config variables are merged.
+func WithGroupedConfig(grouped bool) TransformOption {
+	return func(o *transformOptions) {
+		o.groupedConfig = grouped
+	}
+}
+
+// WithStrictConfig controls whether config variables that look like secrets
+// (e.g. a password that isn't flagged as secret) fail the transformation with
+// ErrSecretLikeConfig. By default, they are only reported as a warning.
+func WithStrictConfig(strict bool) TransformOption {
+	return func(o *transformOptions) {
+		o.strictConfig = strict
+	}
+}
+
+// WithServerName sets the catalog name of the server, used as well to name its
+// config, secrets and volumes. By default, it's derived from the registry name
+// of the server.
+func WithServerName(name string) TransformOption {
+	return func(o *transformOptions) {
+		o.serverName = name
+	}
+}
+
 // Type aliases for imported types from the registry package
 type (
 	ServerDetail  = v0.ServerJSON
@@ -166,6 +204,40 @@ func buildConfigSchema(configVars map[string]model.Input, serverName string) []a
 		return nil
 	}
 
+	return []any{buildConfigItem(configVars, serverName, fmt.Sprintf("Configuration for %s", serverName))}
+}
+
+// buildGroupedConfigSchema builds one config item per environment variable
+// with nested config variables, named after the environment variable, plus a
+// config item named after the server for the remaining config variables.
+// A variable nested in several environment variables goes to the first group.
+func buildGroupedConfigSchema(configVars map[string]model.Input, envVars []model.KeyValueInput, serverName string) []any {
+	remaining := maps.Clone(configVars)
+
+	var groups []any
+	for _, ev := range envVars {
+		groupVars := make(map[string]model.Input)
+		for varName := range ev.Variables {
+			if varDef, ok := remaining[varName]; ok {
+				groupVars[varName] = varDef
+				delete(remaining, varName)
+			}
+		}
+		if len(groupVars) == 0 {
+			continue
+		}
+
+		description := ev.Description
+		if description == "" {
+			description = fmt.Sprintf("Configuration for %s", ev.Name)
+		}
+		groups = append(groups, buildConfigItem(groupVars, ev.Name, description))
+	}
+
+	return append(buildConfigSchema(remaining, serverName), groups...)
+}
+
+func buildConfigItem(configVars map[string]model.Input, name string, description string) map[string]any {
 	properties := make(map[string]any)
 	var required []string
 
@@ -199,15 +271,15 @@ func buildConfigSchema(configVars map[string]model.Input, serverName string) []a
 	}
 
 	result := map[string]any{
-		"name":        serverName,
+		"name":        name,
 		"type":        "object",
-		"description": fmt.Sprintf("Configuration for %s", serverName),
+		"description": description,
 		"properties":  properties,
 	}
 	if len(required) > 0 {
 		result["required"] = required
 	}
-	return []any{result}
+	return result
 }
 
 func buildSecrets(serverName string, secretVars map[string]model.Input) []Secret {
@@ -472,8 +544,33 @@ func getPublisherProvidedMeta(meta *v0.ServerMeta) map[string]any {
 	return meta.PublisherProvided
 }
 
+// getPublisherProvidedTools returns the tools listed under the "tools" key of
+// the publisher-provided metadata that have a container image to run.
+func getPublisherProvidedTools(meta *v0.ServerMeta) []Tool {
+	toolsData, ok := getPublisherProvidedMeta(meta)["tools"]
+	if !ok {
+		return nil
+	}
+	toolsJSON, err := json.Marshal(toolsData)
+	if err != nil {
+		return nil
+	}
+	var tools []Tool
+	if err := json.Unmarshal(toolsJSON, &tools); err != nil {
+		return nil
+	}
+
+	var runnable []Tool
+	for _, tool := range tools {
+		if tool.Name != "" && tool.Container.Image != "" {
+			runnable = append(runnable, tool)
+		}
+	}
+	return runnable
+}
+
 // TransformToDocker transforms a ServerDetail (community format) to Server (catalog format).
-// The returned TransformSource indicates which package type was used (oci, pypi, or remote).
+// The returned TransformSource indicates which package type was used (oci, pypi, npm, remote or poci).
 func TransformToDocker(ctx context.Context, serverDetail ServerDetail, opts ...TransformOption) (*Server, TransformSource, error) {
 	options := transformOptions{
 		allowPyPI: true,
@@ -483,7 +580,32 @@ func TransformToDocker(ctx context.Context, serverDetail ServerDetail, opts ...T
 		opt(&options)
 	}
 
+	if options.cache == nil {
+		return transformToDocker(ctx, serverDetail, options)
+	}
+
+	key, err := options.cache.key(serverDetail, options)
+	if err != nil {
+		return transformToDocker(ctx, serverDetail, options)
+	}
+	if server, source, ok := options.cache.get(key); ok {
+		return server, source, nil
+	}
+	server, source, err := transformToDocker(ctx, serverDetail, options)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := options.cache.put(key, server, source); err != nil {
+		log.Printf("Warning: failed to cache the transformation of server %s: %v", serverDetail.Name, err)
+	}
+	return server, source, nil
+}
+
+func transformToDocker(ctx context.Context, serverDetail ServerDetail, options transformOptions) (*Server, TransformSource, error) {
 	serverName := extractServerName(serverDetail.Name)
+	if options.serverName != "" {
+		serverName = options.serverName
+	}
 
 	// Find first OCI or PyPI package with stdio transport, preferring OCI
 	var pkg *model.Package
@@ -587,14 +709,32 @@ func TransformToDocker(ctx context.Context, serverDetail ServerDetail, opts ...T
 		source = TransformSourceRemote
 	}
 
+	// Without a package or remote, fall back to the tools described by the
+	// publisher, which run their own containers
+	if server.Type == "" {
+		if tools := getPublisherProvidedTools(serverDetail.Meta); len(tools) > 0 {
+			server.Tools = tools
+			server.Type = ServerTypePOCI
+			source = TransformSourcePOCI
+		}
+	}
+
 	// Validate that we have at least one way to run the server
-	if server.Image == "" && server.Remote.URL == "" {
+	if server.Image == "" && server.Remote.URL == "" && server.Type != ServerTypePOCI {
 		return nil, "", fmt.Errorf("%w: no compatible packages for %s", ErrIncompatibleServer, serverDetail.Name)
 	}
 
 	// Add config schema if we have config variables
 	if len(configVars) > 0 {
-		server.Config = buildConfigSchema(configVars, serverName)
+		if options.groupedConfig && pkg != nil {
+			server.Config = buildGroupedConfigSchema(configVars, pkg.EnvironmentVariables, serverName)
+		} else {
+			server.Config = buildConfigSchema(configVars, serverName)
+		}
+	}
+
+	if err := CheckSecretLikeConfig(*server, options.strictConfig); err != nil {
+		return nil, "", err
 	}
 
 	// Add secrets if we have secret variables
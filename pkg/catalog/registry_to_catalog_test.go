@@ -1114,6 +1114,114 @@ func TestTransformOCIWithOptionalConfig(t *testing.T) {
 	t.Logf("Catalog JSON:\n%s", catalogJSON)
 }
 
+func TestTransformOCIWithGroupedConfig(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.example/grouped-config-server",
+			"description": "Server with config variables grouped by environment variable",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/server",
+					"version": "sha256:abc123",
+					"transport": { "type": "stdio" },
+					"environmentVariables": [
+						{
+							"name": "DATABASE_URL",
+							"description": "Database connection",
+							"value": "postgres://{db_host}:{db_port}/app",
+							"variables": {
+								"db_host": { "description": "Database host", "isRequired": true },
+								"db_port": { "description": "Database port", "format": "number", "default": "5432" }
+							}
+						},
+						{
+							"name": "CACHE_URL",
+							"value": "redis://{cache_host}",
+							"variables": {
+								"cache_host": { "description": "Cache host" }
+							}
+						},
+						{
+							"name": "LOG_LEVEL",
+							"description": "Logging level"
+						}
+					]
+				}
+			]
+		}
+	}`
+
+	var serverResponse v0.ServerResponse
+	if err := json.Unmarshal([]byte(registryJSON), &serverResponse); err != nil {
+		t.Fatalf("Failed to parse registry JSON: %v", err)
+	}
+
+	// Merged into a single config item by default.
+	merged, _, err := TransformToDocker(t.Context(), serverResponse.Server)
+	if err != nil {
+		t.Fatalf("TransformToDocker failed: %v", err)
+	}
+	if len(merged.Config) != 1 {
+		t.Fatalf("Expected 1 config item by default, got %d", len(merged.Config))
+	}
+
+	result, _, err := TransformToDocker(t.Context(), serverResponse.Server, WithGroupedConfig(true))
+	if err != nil {
+		t.Fatalf("TransformToDocker failed: %v", err)
+	}
+	if len(result.Config) != 3 {
+		t.Fatalf("Expected 3 config items, got %d: %v", len(result.Config), result.Config)
+	}
+
+	expected := []struct {
+		name        string
+		description string
+		properties  []string
+	}{
+		{name: "io-example-grouped-config-server", description: "Configuration for io-example-grouped-config-server", properties: []string{"LOG_LEVEL"}},
+		{name: "DATABASE_URL", description: "Database connection", properties: []string{"db_host", "db_port"}},
+		{name: "CACHE_URL", description: "Configuration for CACHE_URL", properties: []string{"cache_host"}},
+	}
+	for i, want := range expected {
+		item, ok := result.Config[i].(map[string]any)
+		if !ok {
+			t.Fatalf("Expected config[%d] to be a map[string]any", i)
+		}
+		if item["name"] != want.name {
+			t.Errorf("Expected config[%d] name %q, got %q", i, want.name, item["name"])
+		}
+		if item["description"] != want.description {
+			t.Errorf("Expected config[%d] description %q, got %q", i, want.description, item["description"])
+		}
+		properties, ok := item["properties"].(map[string]any)
+		if !ok {
+			t.Fatalf("Expected properties in config[%d]", i)
+		}
+		if len(properties) != len(want.properties) {
+			t.Errorf("Expected %d properties in config[%d], got %d", len(want.properties), i, len(properties))
+		}
+		for _, property := range want.properties {
+			if _, ok := properties[property]; !ok {
+				t.Errorf("Expected %s in config[%d] properties", property, i)
+			}
+		}
+	}
+
+	databaseItem := result.Config[1].(map[string]any)
+	if required, _ := databaseItem["required"].([]string); len(required) != 1 || required[0] != "db_host" {
+		t.Errorf("Expected db_host to be required, got %v", databaseItem["required"])
+	}
+
+	// Values still reference the server's config, whatever the item.
+	for _, env := range result.Env {
+		if env.Name == "DATABASE_URL" && env.Value != "postgres://{{io-example-grouped-config-server.db_host}}:{{io-example-grouped-config-server.db_port}}/app" {
+			t.Errorf("Unexpected DATABASE_URL value %q", env.Value)
+		}
+	}
+}
+
 func TestTransformPyPIDisallowed(t *testing.T) {
 	// PyPI-only server should be rejected when WithAllowPyPI(false) is set
 	registryJSON := `{
@@ -1851,3 +1959,106 @@ func TestTransformNPMNilResolver(t *testing.T) {
 
 	t.Logf("Catalog JSON:\n%s", catalogJSON)
 }
+
+func TestTransformPOCIFromPublisherProvidedTools(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/my-tools",
+			"title": "My Tools",
+			"description": "Tools that each run their own container",
+			"version": "1.0.0",
+			"_meta": {
+				"io.modelcontextprotocol.registry/publisher-provided": {
+					"tools": [
+						{
+							"name": "curl",
+							"description": "Fetch a URL",
+							"container": {
+								"image": "curlimages/curl:8.10.1",
+								"command": ["{{url}}"]
+							},
+							"parameters": {
+								"type": "object",
+								"properties": {
+									"url": {"type": "string", "description": "The URL to fetch"}
+								},
+								"required": ["url"]
+							}
+						},
+						{
+							"name": "no-container",
+							"description": "Has nothing to run"
+						}
+					]
+				}
+			}
+		}
+	}`
+
+	var serverResponse v0.ServerResponse
+	if err := json.Unmarshal([]byte(registryJSON), &serverResponse); err != nil {
+		t.Fatalf("Failed to parse registry JSON: %v", err)
+	}
+
+	result, source, err := TransformToDocker(t.Context(), serverResponse.Server)
+	if err != nil {
+		t.Fatalf("TransformToDocker failed: %v", err)
+	}
+	if source != TransformSourcePOCI {
+		t.Errorf("Expected source %q, got %q", TransformSourcePOCI, source)
+	}
+	if result.Type != ServerTypePOCI {
+		t.Errorf("Expected type %q, got %q", ServerTypePOCI, result.Type)
+	}
+	if result.Image != "" {
+		t.Errorf("Expected no image, got %q", result.Image)
+	}
+	if len(result.Tools) != 1 {
+		t.Fatalf("Expected 1 runnable tool, got %d", len(result.Tools))
+	}
+	tool := result.Tools[0]
+	if tool.Name != "curl" || tool.Container.Image != "curlimages/curl:8.10.1" {
+		t.Errorf("Unexpected tool: %+v", tool)
+	}
+	if len(tool.Container.Command) != 1 || tool.Container.Command[0] != "{{url}}" {
+		t.Errorf("Unexpected tool command: %v", tool.Container.Command)
+	}
+	if len(tool.Parameters.Required) != 1 || tool.Parameters.Properties["url"].Type != "string" {
+		t.Errorf("Unexpected tool parameters: %+v", tool.Parameters)
+	}
+}
+
+func TestTransformPOCIIgnoredWithPackage(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/server",
+			"version": "1.0.0",
+			"packages": [{
+				"registryType": "oci",
+				"identifier": "docker.io/example/server:1.0.0",
+				"transport": {"type": "stdio"}
+			}],
+			"_meta": {
+				"io.modelcontextprotocol.registry/publisher-provided": {
+					"tools": [{"name": "curl", "container": {"image": "curlimages/curl:8.10.1"}}]
+				}
+			}
+		}
+	}`
+
+	var serverResponse v0.ServerResponse
+	if err := json.Unmarshal([]byte(registryJSON), &serverResponse); err != nil {
+		t.Fatalf("Failed to parse registry JSON: %v", err)
+	}
+
+	result, source, err := TransformToDocker(t.Context(), serverResponse.Server)
+	if err != nil {
+		t.Fatalf("TransformToDocker failed: %v", err)
+	}
+	if source != TransformSourceOCI || result.Type != "server" {
+		t.Errorf("Expected an oci server, got type %q from %q", result.Type, source)
+	}
+	if len(result.Tools) != 0 {
+		t.Errorf("Expected no tools, got %d", len(result.Tools))
+	}
+}
@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrSecretLikeConfig is returned in strict mode when a server declares config
+// fields that look like secrets.
+var ErrSecretLikeConfig = errors.New("config fields look like secrets")
+
+// SecretLikeConfigFields returns the names of the config fields of a server
+// that look like secrets (e.g. api_token, password, clientSecret, apiKey),
+// which should be declared as secrets instead, sorted.
+func SecretLikeConfigFields(server Server) []string {
+	var fields []string
+	for _, item := range server.Config {
+		config, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		properties, ok := config["properties"].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range slices.Sorted(maps.Keys(properties)) {
+			if LooksLikeSecret(field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
+
+// LooksLikeSecret reports whether the name of a config field suggests that
+// its value is a secret.
+func LooksLikeSecret(name string) bool {
+	name = strings.ToLower(name)
+	if strings.Contains(name, "token") || strings.Contains(name, "password") || strings.Contains(name, "secret") {
+		return true
+	}
+	_, afterAPI, ok := strings.Cut(name, "api")
+	return ok && strings.Contains(afterAPI, "key")
+}
+
+// CheckSecretLikeConfig warns about the config fields of a server that look
+// like secrets. In strict mode, it returns an ErrSecretLikeConfig error instead.
+func CheckSecretLikeConfig(server Server, strict bool) error {
+	fields := SecretLikeConfigFields(server)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if strict {
+		return fmt.Errorf("server %s: %w: %s (declare them as secrets instead)", server.Name, ErrSecretLikeConfig, strings.Join(fields, ", "))
+	}
+	log.Printf("Warning: server %s declares config fields that look like secrets: %s. Declare them as secrets instead", server.Name, strings.Join(fields, ", "))
+	return nil
+}
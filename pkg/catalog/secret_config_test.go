@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestSecretLikeConfigFields(t *testing.T) {
+	server := Server{
+		Name: "example",
+		Config: []any{
+			map[string]any{
+				"name": "example",
+				"properties": map[string]any{
+					"password":      map[string]any{"type": "string"},
+					"GITHUB_TOKEN":  map[string]any{"type": "string"},
+					"clientSecret":  map[string]any{"type": "string"},
+					"api_key":       map[string]any{"type": "string"},
+					"openaiApiKey":  map[string]any{"type": "string"},
+					"host":          map[string]any{"type": "string"},
+					"keyboard_api":  map[string]any{"type": "string"},
+					"max_tokens_ok": map[string]any{"type": "number"},
+				},
+			},
+			map[string]any{
+				"name": "other",
+				"properties": map[string]any{
+					"password": map[string]any{"type": "string"},
+				},
+			},
+			"not a config item",
+		},
+	}
+
+	expected := []string{"GITHUB_TOKEN", "api_key", "clientSecret", "max_tokens_ok", "openaiApiKey", "password"}
+	if fields := SecretLikeConfigFields(server); !slices.Equal(fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, fields)
+	}
+
+	if fields := SecretLikeConfigFields(Server{Name: "empty"}); len(fields) != 0 {
+		t.Errorf("Expected no fields for a server without config, got %v", fields)
+	}
+}
+
+func TestTransformWithPasswordConfig(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.example/database",
+			"description": "Server with a password declared as plain config",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/database",
+					"version": "1.0.0",
+					"transport": { "type": "stdio" },
+					"environmentVariables": [
+						{ "name": "DB_HOST", "description": "Database host" },
+						{ "name": "DB_PASSWORD", "description": "Database password" }
+					]
+				}
+			]
+		}
+	}`
+
+	var serverResponse v0.ServerResponse
+	if err := json.Unmarshal([]byte(registryJSON), &serverResponse); err != nil {
+		t.Fatalf("Failed to parse registry JSON: %v", err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	// Only a warning by default.
+	result, _, err := TransformToDocker(t.Context(), serverResponse.Server)
+	if err != nil {
+		t.Fatalf("TransformToDocker failed: %v", err)
+	}
+	if len(result.Config) != 1 {
+		t.Fatalf("Expected 1 config item, got %d", len(result.Config))
+	}
+	if !strings.Contains(logs.String(), "Warning: server io-example-database declares config fields that look like secrets: DB_PASSWORD") {
+		t.Errorf("Expected a warning about DB_PASSWORD, got %q", logs.String())
+	}
+
+	_, _, err = TransformToDocker(t.Context(), serverResponse.Server, WithStrictConfig(true))
+	if !errors.Is(err, ErrSecretLikeConfig) {
+		t.Fatalf("Expected ErrSecretLikeConfig in strict mode, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "DB_PASSWORD") {
+		t.Errorf("Expected the error to name DB_PASSWORD, got %q", err.Error())
+	}
+}
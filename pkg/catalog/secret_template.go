@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var secretTemplatePlaceholder = regexp.MustCompile(`{{(.*?)}}`)
+
+// Parts returns the names of the placeholders of the template, in order and
+// without duplicates.
+func (t SecretTemplate) Parts() []string {
+	var parts []string
+	for _, match := range secretTemplatePlaceholder.FindAllStringSubmatch(t.Template, -1) {
+		part := strings.TrimSpace(match[1])
+		if part != "" && !slices.Contains(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// Expand replaces the placeholders of the template with the values returned
+// by resolve. It returns the parts that couldn't be resolved.
+func (t SecretTemplate) Expand(resolve func(part string) (string, bool)) (string, []string) {
+	var missing []string
+	value := secretTemplatePlaceholder.ReplaceAllStringFunc(t.Template, func(placeholder string) string {
+		part := strings.TrimSpace(placeholder[2 : len(placeholder)-2])
+		if value, ok := resolve(part); ok {
+			return value
+		}
+		if !slices.Contains(missing, part) {
+			missing = append(missing, part)
+		}
+		return ""
+	})
+	return value, missing
+}
+
+// StoredSecrets returns the secrets the server reads from the secret store:
+// its declared secrets and the parts of its secret templates, which have no
+// env variable of their own.
+func (s *Server) StoredSecrets() []Secret {
+	secrets := slices.Clone(s.Secrets)
+	for _, template := range s.SecretTemplates {
+		for _, part := range template.Parts() {
+			if !slices.ContainsFunc(secrets, func(secret Secret) bool { return secret.Name == part }) {
+				secrets = append(secrets, Secret{Name: part})
+			}
+		}
+	}
+	return secrets
+}
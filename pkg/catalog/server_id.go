@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// stableIDPrefix starts the stable IDs of servers, so that they're not
+// mistaken for server names.
+const stableIDPrefix = "srv-"
+
+// Kinds of anchors the stable IDs of servers are derived from.
+const (
+	stableIDImage    = "image"
+	stableIDRegistry = "registry"
+	stableIDRemote   = "remote"
+	stableIDPOCI     = "poci"
+	stableIDCommand  = "command"
+)
+
+// ImageServerID returns the stable ID of a server running an image: the
+// digest of the image when it's pinned to one, or else its reference.
+func ImageServerID(image string) string {
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		return stableServerID(stableIDImage, digest)
+	}
+	return stableServerID(stableIDImage, image)
+}
+
+// RegistryServerID returns the stable ID of a server from an MCP registry,
+// from its registry URL.
+func RegistryServerID(source string) string {
+	return stableServerID(stableIDRegistry, source)
+}
+
+// RemoteServerID returns the stable ID of a remote server, from its endpoint.
+func RemoteServerID(endpoint string) string {
+	return stableServerID(stableIDRemote, endpoint)
+}
+
+// StableID returns the identity of the server, derived from what it runs
+// rather than from its name, so that it survives a rename. It's empty for
+// servers that don't run anything yet.
+func (s *Server) StableID() string {
+	switch {
+	case s.Type == ServerTypePOCI:
+		tools, _ := json.Marshal(s.Tools)
+		return stableServerID(stableIDPOCI, string(tools))
+	case s.Type == ServerTypeCommand:
+		command, _ := json.Marshal(s.Command)
+		return stableServerID(stableIDCommand, string(command))
+	case s.Image != "":
+		return ImageServerID(s.Image)
+	case s.Remote.URL != "":
+		return RemoteServerID(s.Remote.URL)
+	case s.SSEEndpoint != "":
+		return RemoteServerID(s.SSEEndpoint)
+	}
+	return ""
+}
+
+func stableServerID(kind, anchor string) string {
+	if anchor == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(kind + "\x00" + anchor))
+	return stableIDPrefix + hex.EncodeToString(sum[:8])
+}
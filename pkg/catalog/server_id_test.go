@@ -0,0 +1,22 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerStableID(t *testing.T) {
+	pinned := Server{Name: "github", Image: "mcp/github:latest@sha256:0123456789abcdef"}
+	renamed := Server{Name: "github-official", Image: "ghcr.io/mirror/github@sha256:0123456789abcdef"}
+	assert.Regexp(t, `^srv-[0-9a-f]{16}$`, pinned.StableID())
+	assert.Equal(t, pinned.StableID(), renamed.StableID(), "the digest identifies the server")
+
+	remote := Server{Name: "docs", Remote: Remote{URL: "https://docs.example.com/mcp"}}
+	assert.Equal(t, RemoteServerID("https://docs.example.com/mcp"), remote.StableID())
+	assert.NotEqual(t, pinned.StableID(), remote.StableID())
+
+	assert.NotEqual(t, ImageServerID("mcp/github:latest"), ImageServerID("mcp/github:v2"))
+	assert.NotEqual(t, ImageServerID("x"), RemoteServerID("x"), "kinds of anchors don't collide")
+	assert.Empty(t, (&Server{Name: "empty"}).StableID())
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// transformVersion is the version of the logic of TransformToDocker. Bump it
+// whenever the servers it produces change, so that the servers cached by
+// older versions are transformed again.
+const transformVersion = 1
+
+// TransformCache is an on-disk cache of the servers produced by
+// TransformToDocker, keyed by the content of the registry server and the
+// options of the transformation. Re-importing an unchanged server reads it
+// from the cache instead of resolving its packages again.
+type TransformCache struct {
+	dir string
+}
+
+// NewTransformCache returns a cache storing its entries in dir, which is
+// created on the first write.
+func NewTransformCache(dir string) *TransformCache {
+	return &TransformCache{dir: dir}
+}
+
+// WithTransformCache reads the transformed servers from the cache, and
+// stores the ones that aren't cached yet. Failed transformations are not
+// cached. By default, no cache is used.
+func WithTransformCache(cache *TransformCache) TransformOption {
+	return func(o *transformOptions) {
+		o.cache = cache
+	}
+}
+
+type transformCacheEntry struct {
+	Server Server          `json:"server"`
+	Source TransformSource `json:"source"`
+}
+
+// key hashes everything the transformation of a server depends on.
+func (c *TransformCache) key(serverDetail ServerDetail, options transformOptions) (string, error) {
+	content, err := json.Marshal(struct {
+		Version       int          `json:"version"`
+		Server        ServerDetail `json:"server"`
+		AllowPyPI     bool         `json:"allowPyPI"`
+		AllowNPM      bool         `json:"allowNPM"`
+		GroupedConfig bool         `json:"groupedConfig"`
+		StrictConfig  bool         `json:"strictConfig"`
+		ServerName    string       `json:"serverName,omitempty"`
+	}{
+		Version:       transformVersion,
+		Server:        serverDetail,
+		AllowPyPI:     options.allowPyPI,
+		AllowNPM:      options.allowNPM,
+		GroupedConfig: options.groupedConfig,
+		StrictConfig:  options.strictConfig,
+		ServerName:    options.serverName,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *TransformCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns the cached server for a key. Unreadable entries are misses.
+func (c *TransformCache) get(key string) (*Server, TransformSource, bool) {
+	buf, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, "", false
+	}
+	var entry transformCacheEntry
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return nil, "", false
+	}
+	return &entry.Server, entry.Source, true
+}
+
+// put stores a server in the cache. The entry is written to a temporary file
+// first, so that concurrent imports never read a partial entry.
+func (c *TransformCache) put(key string, server *Server, source TransformSource) error {
+	buf, err := json.Marshal(transformCacheEntry{Server: *server, Source: source})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("creating transform cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing transform cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing transform cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing transform cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
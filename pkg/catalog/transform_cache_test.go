@@ -0,0 +1,105 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pypiServerJSON = `{
+	"server": {
+		"name": "io.github.example/weather",
+		"description": "Weather forecasts",
+		"version": "1.0.0",
+		"packages": [{
+			"registryType": "pypi",
+			"registryBaseUrl": "https://pypi.org",
+			"identifier": "weather-mcp",
+			"version": "1.0.0",
+			"transport": {"type": "stdio"}
+		}]
+	}
+}`
+
+func TestTransformCacheHitSkipsTransformation(t *testing.T) {
+	var serverResponse v0.ServerResponse
+	require.NoError(t, json.Unmarshal([]byte(pypiServerJSON), &serverResponse))
+
+	var resolved int
+	resolver := func(_ context.Context, _, _, _ string) (string, bool) {
+		resolved++
+		return "3.12", true
+	}
+	cache := NewTransformCache(t.TempDir())
+
+	first, firstSource, err := TransformToDocker(t.Context(), serverResponse.Server, WithPyPIResolver(resolver), WithTransformCache(cache))
+	require.NoError(t, err)
+	require.Equal(t, 1, resolved)
+
+	second, secondSource, err := TransformToDocker(t.Context(), serverResponse.Server, WithPyPIResolver(resolver), WithTransformCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, 1, resolved, "an unchanged server must be read from the cache")
+	assert.Equal(t, TransformSourcePyPI, firstSource)
+	assert.Equal(t, firstSource, secondSource)
+	assert.Equal(t, first.Image, second.Image)
+	assert.Equal(t, first.Command, second.Command)
+	assert.Equal(t, first.Volumes, second.Volumes)
+
+	// A changed server is transformed again.
+	serverResponse.Server.Packages[0].Version = "1.1.0"
+	_, _, err = TransformToDocker(t.Context(), serverResponse.Server, WithPyPIResolver(resolver), WithTransformCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, 2, resolved)
+
+	// So is a server transformed with different options.
+	_, _, err = TransformToDocker(t.Context(), serverResponse.Server, WithPyPIResolver(resolver), WithTransformCache(cache), WithGroupedConfig(true))
+	require.NoError(t, err)
+	assert.Equal(t, 3, resolved)
+}
+
+func TestTransformCacheDoesNotCacheFailures(t *testing.T) {
+	var serverResponse v0.ServerResponse
+	require.NoError(t, json.Unmarshal([]byte(pypiServerJSON), &serverResponse))
+
+	var resolved int
+	notFoundResolver := func(_ context.Context, _, _, _ string) (string, bool) {
+		resolved++
+		return "", false
+	}
+	dir := t.TempDir()
+	cache := NewTransformCache(dir)
+
+	for range 2 {
+		_, _, err := TransformToDocker(t.Context(), serverResponse.Server, WithPyPIResolver(notFoundResolver), WithTransformCache(cache))
+		require.ErrorContains(t, err, "was not found")
+	}
+	assert.Equal(t, 2, resolved)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestTransformCacheIgnoresCorruptEntries(t *testing.T) {
+	var serverResponse v0.ServerResponse
+	require.NoError(t, json.Unmarshal([]byte(pypiServerJSON), &serverResponse))
+
+	cache := NewTransformCache(t.TempDir())
+	key, err := cache.key(serverResponse.Server, transformOptions{allowPyPI: true, allowNPM: true})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cache.path(key), []byte("not json"), 0o644))
+
+	server, source, err := TransformToDocker(t.Context(), serverResponse.Server, WithTransformCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, TransformSourcePyPI, source)
+	assert.NotEmpty(t, server.Image)
+
+	cached, _, ok := cache.get(key)
+	require.True(t, ok)
+	assert.Equal(t, server.Image, cached.Image)
+}
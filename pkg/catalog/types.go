@@ -22,9 +22,18 @@ type topLevel struct {
 
 // MCP Servers
 
+// ServerTypeCommand is the type of servers that run a local command over
+// stdio instead of a container. Only profiles can declare them.
+const ServerTypeCommand = "command"
+
+// ServerTypePOCI is the type of servers that don't run an MCP server image
+// but expose tools, each running its own container. Their image, if any, is
+// only the image the tools were built from.
+const ServerTypePOCI = "poci"
+
 type Server struct {
 	Name           string    `yaml:"name,omitempty" json:"name,omitempty" validate:"required,min=1"`
-	Type           string    `yaml:"type" json:"type" validate:"required,oneof=server remote poci"`
+	Type           string    `yaml:"type" json:"type" validate:"required,oneof=server remote poci command"`
 	Image          string    `yaml:"image" json:"image"`
 	Description    string    `yaml:"description,omitempty" json:"description,omitempty"`
 	Title          string    `yaml:"title,omitempty" json:"title,omitempty"`
@@ -48,6 +57,33 @@ type Server struct {
 	Metadata       *Metadata `yaml:"metadata,omitempty" json:"metadata,omitempty"`
 	// Policy describes the policy decision for this server.
 	Policy *policy.Decision `yaml:"policy,omitempty" json:"policy,omitempty"`
+	// Labels are added to the server's container, overriding the gateway's --container-label values.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Hooks are commands the gateway runs around the server's container.
+	Hooks *Hooks `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// Priority orders the start of the servers: servers with a higher
+	// priority are up before the others start. Defaults to 0.
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty"`
+	// PullPolicy is when the server's image is pulled as its container
+	// starts: "missing" if it isn't there, "always" on every start, "never"
+	// to use the local image. By default, the image is pulled, if missing,
+	// when the gateway starts.
+	PullPolicy string `yaml:"pullPolicy,omitempty" json:"pullPolicy,omitempty" validate:"omitempty,oneof=missing never always"`
+	// SecretTemplates compose several secrets into one env variable, e.g. a
+	// connection string assembled from a user, a password and a host.
+	SecretTemplates []SecretTemplate `yaml:"secretTemplates,omitempty" json:"secretTemplates,omitempty"`
+	// Capabilities summarizes what the server advertises. Set when the
+	// snapshot of the server is resolved.
+	Capabilities *Capabilities `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+}
+
+// Capabilities are the kinds of MCP capabilities a server supports.
+type Capabilities struct {
+	Tools             bool `yaml:"tools" json:"tools"`
+	Prompts           bool `yaml:"prompts" json:"prompts"`
+	Resources         bool `yaml:"resources" json:"resources"`
+	ResourceTemplates bool `yaml:"resourceTemplates" json:"resourceTemplates"`
+	Sampling          bool `yaml:"sampling" json:"sampling"`
 }
 
 type Metadata struct {
@@ -89,6 +125,15 @@ type Secret struct {
 	Env  string `yaml:"env" json:"env"`
 }
 
+// SecretTemplate sets an env variable to a template whose {{name}}
+// placeholders are replaced, when the server starts, with the values of the
+// secrets with these names or, failing that, of the server's config, e.g.
+// postgres://{{postgres.user}}:{{postgres.password}}@{{postgres.host}}/db.
+type SecretTemplate struct {
+	Env      string `yaml:"env" json:"env"`
+	Template string `yaml:"template" json:"template"`
+}
+
 type Env struct {
 	Name  string `yaml:"name" json:"name"`
 	Value string `yaml:"value" json:"value"`
@@ -111,6 +156,29 @@ type OAuthProvider struct {
 	Env      string `json:"env,omitempty" yaml:"env,omitempty"`
 }
 
+// Hook failure policies.
+const (
+	// HookOnFailureAbort fails the start of the server when its preStart hook
+	// fails. This is the default.
+	HookOnFailureAbort = "abort"
+	// HookOnFailureIgnore logs the failure of a hook and carries on.
+	HookOnFailureIgnore = "ignore"
+)
+
+type Hooks struct {
+	// PreStart runs before the server's container is started.
+	PreStart *Hook `yaml:"preStart,omitempty" json:"preStart,omitempty"`
+	// PostStop runs after the server's container is stopped.
+	PostStop *Hook `yaml:"postStop,omitempty" json:"postStop,omitempty"`
+}
+
+type Hook struct {
+	// Command is run without a shell. The whole command, once its templates
+	// are evaluated, must be allowed with the gateway's --hook-command flag.
+	Command   []string `yaml:"command" json:"command"`
+	OnFailure string   `yaml:"onFailure,omitempty" json:"onFailure,omitempty" validate:"omitempty,oneof=abort ignore"`
+}
+
 // POCI tools
 
 type Items struct {
@@ -214,6 +282,8 @@ type ServerConfig struct {
 	Spec    Server
 	Config  map[string]any
 	Secrets map[string]string
+	// LongLivedOverride takes precedence over Spec.LongLived when set.
+	LongLivedOverride *bool
 }
 
 // IsRemote returns true if this server is a remote MCP server (not a Docker container)
@@ -0,0 +1,116 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+type AliasSummary struct {
+	Alias string `yaml:"alias" json:"alias"`
+	Ref   string `yaml:"ref" json:"ref"`
+}
+
+// SetAlias registers a short alias for a catalog reference. Aliases can then
+// be used wherever a catalog reference is expected.
+func SetAlias(ctx context.Context, dao db.DAO, alias string, refStr string, output workingset.Output) error {
+	if !db.ValidCatalogAlias(alias) {
+		return fmt.Errorf("invalid alias %q: must contain only lowercase letters, digits, '-' or '_'", alias)
+	}
+
+	refStr, err := oci.NormalizeCatalogRef(refStr)
+	if err != nil {
+		return err
+	}
+
+	// The alias would hide the catalog with the same short reference.
+	aliasRef, err := oci.NormalizeCatalogRef(alias)
+	if err != nil {
+		return err
+	}
+	if _, err := dao.GetCatalog(ctx, aliasRef); err == nil {
+		return fmt.Errorf("alias %s collides with the catalog %s", alias, aliasRef)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get catalog %s: %w", aliasRef, err)
+	}
+
+	if err := dao.SetCatalogAlias(ctx, db.CatalogAlias{Alias: alias, Ref: refStr}); err != nil {
+		return fmt.Errorf("failed to set alias %s: %w", alias, err)
+	}
+
+	output.Printf("Alias %s now refers to %s\n", alias, refStr)
+	return nil
+}
+
+func RemoveAlias(ctx context.Context, dao db.DAO, alias string, output workingset.Output) error {
+	if _, err := dao.GetCatalogAlias(ctx, alias); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("alias %s not found", alias)
+		}
+		return fmt.Errorf("failed to get alias %s: %w", alias, err)
+	}
+
+	if err := dao.DeleteCatalogAlias(ctx, alias); err != nil {
+		return fmt.Errorf("failed to remove alias %s: %w", alias, err)
+	}
+
+	output.Printf("Removed alias %s\n", alias)
+	return nil
+}
+
+func ListAliases(ctx context.Context, dao db.DAO, format workingset.OutputFormat) error {
+	dbAliases, err := dao.ListCatalogAliases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list aliases: %w", err)
+	}
+
+	summaries := make([]AliasSummary, len(dbAliases))
+	for i, dbAlias := range dbAliases {
+		summaries[i] = AliasSummary{Alias: dbAlias.Alias, Ref: dbAlias.Ref}
+	}
+
+	var data []byte
+	switch format {
+	case workingset.OutputFormatHumanReadable:
+		if len(summaries) == 0 {
+			return workingset.Output{}.Print([]byte("No aliases found. Use `docker mcp catalog alias set <alias> <oci-reference>` to create one."))
+		}
+		lines := make([]string, 0, len(summaries)+1)
+		lines = append(lines, "Alias | Reference")
+		for _, summary := range summaries {
+			lines = append(lines, fmt.Sprintf("%s\t| %s", summary.Alias, summary.Ref))
+		}
+		data = []byte(strings.Join(lines, "\n"))
+	case workingset.OutputFormatJSON:
+		data, err = json.MarshalIndent(summaries, "", "  ")
+	case workingset.OutputFormatYAML:
+		data, err = yaml.Marshal(summaries)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases: %w", err)
+	}
+
+	return workingset.Output{}.Print(data)
+}
+
+// resolveCatalogRef resolves aliases and normalizes a catalog reference
+// into the form used as the catalog key in the database.
+func resolveCatalogRef(ctx context.Context, dao db.DAO, refStr string) (string, error) {
+	refStr, err := db.ResolveCatalogAlias(ctx, dao, refStr)
+	if err != nil {
+		return "", err
+	}
+
+	return oci.NormalizeCatalogRef(refStr)
+}
@@ -0,0 +1,169 @@
+package catalognext
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestSetAlias(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "team", "test/catalog:latest", workingset.Output{}))
+	})
+
+	alias, err := dao.GetCatalogAlias(ctx, "team")
+	require.NoError(t, err)
+	assert.Equal(t, "test/catalog:latest", alias.Ref)
+
+	// Updating an alias replaces its target
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "team", "test/other:v1", workingset.Output{}))
+	})
+
+	alias, err = dao.GetCatalogAlias(ctx, "team")
+	require.NoError(t, err)
+	assert.Equal(t, "test/other:v1", alias.Ref)
+}
+
+func TestSetAliasInvalid(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := SetAlias(ctx, dao, "Team/Catalog", "test/catalog:latest", workingset.Output{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid alias")
+
+	err = SetAlias(ctx, dao, "team", "test/catalog@sha256:0000000000000000000000000000000000000000000000000000000000000000", workingset.Output{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be a valid OCI reference without a digest")
+}
+
+func TestSetAliasCollidingWithCatalog(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	dbCat, err := Catalog{Ref: "team:latest", CatalogArtifact: CatalogArtifact{Title: "Team"}}.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	err = SetAlias(ctx, dao, "team", "test/catalog:latest", workingset.Output{})
+	require.EqualError(t, err, "alias team collides with the catalog team:latest")
+
+	_, err = dao.GetCatalogAlias(ctx, "team")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestResolveCatalogRef(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "team", "docker.io/test/catalog", workingset.Output{}))
+	})
+
+	for ref, expected := range map[string]string{
+		"team":                          "test/catalog:latest",
+		"docker.io/test/catalog:latest": "test/catalog:latest",
+		"test/catalog:v1":               "test/catalog:v1",
+		"registry.example.com/catalog":  "registry.example.com/catalog:latest",
+	} {
+		resolved, err := resolveCatalogRef(ctx, dao, ref)
+		require.NoError(t, err)
+		assert.Equal(t, expected, resolved, ref)
+	}
+
+	_, err := resolveCatalogRef(ctx, dao, "test/catalog@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	require.ErrorContains(t, err, "must be a valid OCI reference without a digest")
+}
+
+func TestRemoveAlias(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "team", "test/catalog:latest", workingset.Output{}))
+		require.NoError(t, RemoveAlias(ctx, dao, "team", workingset.Output{}))
+	})
+
+	aliases, err := dao.ListCatalogAliases(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	err = RemoveAlias(ctx, dao, "team", workingset.Output{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alias team not found")
+}
+
+func TestListAliases(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "b-team", "test/b:latest", workingset.Output{}))
+		require.NoError(t, SetAlias(ctx, dao, "a-team", "test/a:latest", workingset.Output{}))
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, ListAliases(ctx, dao, workingset.OutputFormatJSON))
+	})
+
+	var aliases []AliasSummary
+	require.NoError(t, json.Unmarshal([]byte(output), &aliases))
+	assert.Equal(t, []AliasSummary{
+		{Alias: "a-team", Ref: "test/a:latest"},
+		{Alias: "b-team", Ref: "test/b:latest"},
+	}, aliases)
+}
+
+func TestAliasResolvesInServerCommands(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/server1:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "my-server"},
+					},
+				},
+			},
+		},
+	}
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	captureStdout(t, func() {
+		require.NoError(t, SetAlias(ctx, dao, "team", catalogObj.Ref, workingset.Output{}))
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, ListServers(ctx, dao, "team", nil, workingset.OutputFormatJSON, false, false))
+	})
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, catalogObj.Ref, result["catalog"])
+
+	output = captureStdout(t, func() {
+		require.NoError(t, InspectServer(ctx, dao, "team", "my-server", workingset.OutputFormatJSON, false))
+	})
+
+	var server InspectResult
+	require.NoError(t, json.Unmarshal([]byte(output), &server))
+	assert.Equal(t, "my-server", server.Snapshot.Server.Name)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/policy"
@@ -14,7 +15,11 @@ import (
 )
 
 type CatalogArtifact struct {
-	Title   string   `yaml:"title" json:"title" validate:"required,min=1"`
+	Title string `yaml:"title" json:"title" validate:"required,min=1"`
+	// Base is the OCI reference of a catalog whose servers this catalog
+	// inherits. Servers of this catalog override the base servers with the
+	// same name. Resolved when the catalog is read, see db.ResolveCatalogBase.
+	Base    string   `yaml:"base,omitempty" json:"base,omitempty"`
 	Servers []Server `yaml:"servers" json:"servers" validate:"dive"`
 }
 
@@ -41,18 +46,36 @@ type CatalogSummary struct {
 
 // Source prefixes must be of the form "<prefix>:"
 const (
-	SourcePrefixWorkingSet    = "profile:"
-	SourcePrefixLegacyCatalog = "legacy-catalog:"
-	SourcePrefixOCI           = "oci:"
-	SourcePrefixUser          = "user:"
-	SourcePrefixRegistry      = "registry:"
+	SourcePrefixWorkingSet       = "profile:"
+	SourcePrefixLegacyCatalog    = "legacy-catalog:"
+	SourcePrefixLegacyCatalogURL = "legacy-catalog-url:"
+	SourcePrefixOCI              = "oci:"
+	SourcePrefixUser             = "user:"
+	SourcePrefixRegistry         = "registry:"
+	SourcePrefixOverlay          = "overlay:"
 )
 
 // CommunityRegistryCatalogRef is the OCI reference for the community MCP server catalog.
 const CommunityRegistryCatalogRef = "mcp/community-registry:latest"
 
+// isCommunityRegistryRef reports whether refStr is the community MCP registry,
+// e.g. registry.modelcontextprotocol.io, rather than an OCI catalog reference.
+func isCommunityRegistryRef(refStr string) bool {
+	return oci.IsCommunityRegistryRef(strings.TrimPrefix(refStr, SourcePrefixRegistry))
+}
+
+// requireOCICatalogRef guards the operations that only make sense for OCI
+// catalogs, e.g. push or pin, against community registry references, which
+// would otherwise fail deep in OCI parsing or target the wrong repository.
+func requireOCICatalogRef(refStr string, operation string) error {
+	if isCommunityRegistryRef(refStr) {
+		return fmt.Errorf("cannot %s %s: it is a community registry, not an OCI catalog reference; create a catalog from it with --from-community-registry first", operation, refStr)
+	}
+	return nil
+}
+
 type Server struct {
-	Type  workingset.ServerType `yaml:"type" json:"type" validate:"required,oneof=registry image remote"`
+	Type  workingset.ServerType `yaml:"type" json:"type" validate:"required,oneof=registry image remote poci"`
 	Tools []string              `yaml:"tools,omitempty" json:"tools,omitempty"`
 	// Policy describes the policy decision for this server.
 	Policy *policy.Decision `yaml:"policy,omitempty" json:"policy,omitempty"`
@@ -66,15 +89,60 @@ type Server struct {
 	// ServerTypeRemote only
 	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required_if=Type remote"`
 
+	// LongLivedOverride is copied to the profile servers added from this
+	// catalog server. See workingset.Server.LongLivedOverride.
+	LongLivedOverride *bool `yaml:"longLivedOverride,omitempty" json:"longLivedOverride,omitempty"`
+
+	// AddedFrom records where the server was added from: the server reference
+	// it was resolved from (e.g. docker://mcp/fetch, catalog://..., a registry
+	// URL or file://...), or the source of the catalog it was created from
+	// (e.g. profile:my-profile, legacy-catalog:./catalog.yaml,
+	// legacy-catalog-url:https://example.com/catalog.yaml or
+	// registry:registry.modelcontextprotocol.io).
+	AddedFrom string `yaml:"addedFrom,omitempty" json:"addedFrom,omitempty"`
+
+	// Annotations are free-form annotations for tooling, e.g. an owner or a
+	// ticket. The gateway doesn't interpret them.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
 	Snapshot *workingset.ServerSnapshot `yaml:"snapshot,omitempty" json:"snapshot,omitempty"`
+
+	// ID is the stable ID of the server stored in the database, empty for
+	// servers that weren't read from it. It's not part of the catalog
+	// artifact, so that it doesn't change catalog digests. See StableID.
+	ID string `yaml:"-" json:"-"`
+}
+
+// BasicName identifies a server that may not have a snapshot, and thus a
+// name, by its image, source or endpoint. Poci servers always have one.
+func (server *Server) BasicName() string {
+	switch server.Type {
+	case workingset.ServerTypeImage:
+		return server.Image
+	case workingset.ServerTypeRegistry:
+		return server.Source
+	case workingset.ServerTypeRemote:
+		return server.Endpoint
+	case workingset.ServerTypePOCI:
+		if server.Snapshot != nil {
+			return server.Snapshot.Server.Name
+		}
+	}
+	return "unknown"
 }
 
 func NewFromDb(dbCatalog *db.Catalog) CatalogWithDigest {
 	servers := make([]Server, len(dbCatalog.Servers))
 	for i, server := range dbCatalog.Servers {
 		servers[i] = Server{
-			Type:  workingset.ServerType(server.ServerType),
-			Tools: server.Tools,
+			Type:              workingset.ServerType(server.ServerType),
+			Tools:             server.Tools,
+			LongLivedOverride: server.LongLivedOverride,
+			AddedFrom:         server.AddedFrom,
+			ID:                server.StableID,
+		}
+		if len(server.Annotations) > 0 {
+			servers[i].Annotations = server.Annotations
 		}
 		if server.ServerType == "registry" {
 			servers[i].Source = server.Source
@@ -98,6 +166,7 @@ func NewFromDb(dbCatalog *db.Catalog) CatalogWithDigest {
 			Source: dbCatalog.Source,
 			CatalogArtifact: CatalogArtifact{
 				Title:   dbCatalog.Title,
+				Base:    dbCatalog.Base,
 				Servers: servers,
 			},
 		},
@@ -110,24 +179,7 @@ func NewFromDb(dbCatalog *db.Catalog) CatalogWithDigest {
 func (catalog Catalog) ToDb() (db.Catalog, error) {
 	dbServers := make([]db.CatalogServer, len(catalog.Servers))
 	for i, server := range catalog.Servers {
-		dbServers[i] = db.CatalogServer{
-			ServerType: string(server.Type),
-			Tools:      server.Tools,
-		}
-		if server.Type == workingset.ServerTypeRegistry {
-			dbServers[i].Source = server.Source
-		}
-		if server.Type == workingset.ServerTypeImage {
-			dbServers[i].Image = server.Image
-		}
-		if server.Type == workingset.ServerTypeRemote {
-			dbServers[i].Endpoint = server.Endpoint
-		}
-		if server.Snapshot != nil {
-			dbServers[i].Snapshot = &db.ServerSnapshot{
-				Server: server.Snapshot.Server,
-			}
-		}
+		dbServers[i] = server.toDb()
 	}
 
 	digest, err := catalog.Digest()
@@ -135,15 +187,61 @@ func (catalog Catalog) ToDb() (db.Catalog, error) {
 		return db.Catalog{}, fmt.Errorf("failed to get catalog digest: %w", err)
 	}
 
+	var base string
+	if catalog.Base != "" {
+		base, err = oci.NormalizeCatalogRef(catalog.Base)
+		if err != nil {
+			return db.Catalog{}, fmt.Errorf("invalid base catalog: %w", err)
+		}
+	}
+
 	return db.Catalog{
 		Ref:     catalog.Ref,
 		Digest:  digest,
 		Title:   catalog.Title,
 		Source:  catalog.Source,
+		Base:    base,
 		Servers: dbServers,
 	}, nil
 }
 
+func (server Server) toDb() db.CatalogServer {
+	dbServer := db.CatalogServer{
+		ServerType:        string(server.Type),
+		Tools:             server.Tools,
+		LongLivedOverride: server.LongLivedOverride,
+		AddedFrom:         server.AddedFrom,
+		Annotations:       server.Annotations,
+		StableID:          server.ID,
+	}
+	if server.Type == workingset.ServerTypeRegistry {
+		dbServer.Source = server.Source
+	}
+	if server.Type == workingset.ServerTypeImage {
+		dbServer.Image = server.Image
+	}
+	if server.Type == workingset.ServerTypeRemote {
+		dbServer.Endpoint = server.Endpoint
+	}
+	if server.Snapshot != nil {
+		dbServer.Snapshot = &db.ServerSnapshot{
+			Server: server.Snapshot.Server,
+		}
+	}
+	return dbServer
+}
+
+// StableID returns the identity of the server, so that it can be selected
+// with catalog://<catalog>/<id> even after a rename: the ID stored in the
+// database, or else the one derived from its image digest, registry URL or
+// endpoint rather than from its name.
+func (server Server) StableID() string {
+	if server.ID != "" {
+		return server.ID
+	}
+	return server.toDb().DeriveStableID()
+}
+
 func (catalogArtifact *CatalogArtifact) Digest() (string, error) {
 	return oci.GetArtifactDigest(MCPCatalogArtifactType, catalogArtifact)
 }
@@ -171,6 +269,20 @@ func (catalog *Catalog) Validate() error {
 	return catalog.validateServerSnapshots()
 }
 
+// CheckSecretLikeConfig warns about the servers declaring config fields that
+// look like secrets. In strict mode, it returns an error instead.
+func (catalog *Catalog) CheckSecretLikeConfig(strict bool) error {
+	for _, server := range catalog.Servers {
+		if server.Snapshot == nil {
+			continue
+		}
+		if err := legacycatalog.CheckSecretLikeConfig(server.Snapshot.Server, strict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (catalog *Catalog) validateServerSnapshots() error {
 	for _, server := range catalog.Servers {
 		if err := server.Snapshot.ValidateInnerConfig(); err != nil {
@@ -294,9 +406,18 @@ func (evaluator *PullOptionEvaluator) IsAlways() bool {
 }
 
 func (evaluator *PullOptionEvaluator) Evaluate(dbCatalog *db.Catalog) bool {
+	if dbCatalog == nil {
+		return evaluator.evaluate(false, nil)
+	}
+	return evaluator.evaluate(true, dbCatalog.LastUpdated)
+}
+
+// evaluate tells whether to pull something that was last updated at
+// lastUpdated, if found, e.g. a catalog or a cached registry listing.
+func (evaluator *PullOptionEvaluator) evaluate(found bool, lastUpdated *time.Time) bool {
 	for _, pullOption := range evaluator.pullOptions {
 		// If any pull option is true, return true
-		if pullOption.Evaluate(dbCatalog, evaluator.pulledPreviously) {
+		if pullOption.evaluate(found, lastUpdated, evaluator.pulledPreviously) {
 			return true
 		}
 	}
@@ -305,6 +426,13 @@ func (evaluator *PullOptionEvaluator) Evaluate(dbCatalog *db.Catalog) bool {
 
 func (pullOptionConfig PullOptionConfig) Evaluate(dbCatalog *db.Catalog, pulledPreviously bool) bool {
 	if dbCatalog == nil {
+		return pullOptionConfig.evaluate(false, nil, pulledPreviously)
+	}
+	return pullOptionConfig.evaluate(true, dbCatalog.LastUpdated, pulledPreviously)
+}
+
+func (pullOptionConfig PullOptionConfig) evaluate(found bool, lastUpdated *time.Time, pulledPreviously bool) bool {
+	if !found {
 		switch pullOptionConfig.PullOption {
 		case PullOptionMissing, PullOptionAlways, PullOptionDuration:
 			return true // Always pull immediately when not found, even if there's a duration
@@ -319,20 +447,20 @@ func (pullOptionConfig PullOptionConfig) Evaluate(dbCatalog *db.Catalog, pulledP
 	case PullOptionMissing, PullOptionNever, PullOptionInitial:
 		return false
 	case PullOptionDuration, PullOptionExists, PullOptionAlways:
-		return pullOptionConfig.intervalPassed(dbCatalog)
+		return pullOptionConfig.intervalPassed(lastUpdated)
 	default:
 		return false
 	}
 }
 
-func (pullOptionConfig PullOptionConfig) intervalPassed(dbCatalog *db.Catalog) bool {
+func (pullOptionConfig PullOptionConfig) intervalPassed(lastUpdated *time.Time) bool {
 	// Special case: no interval means always past
 	if pullOptionConfig.Interval == 0 {
 		return true
 	}
 
 	// Only if last updated was longer ago than the interval
-	if dbCatalog != nil && dbCatalog.LastUpdated != nil && time.Since(*dbCatalog.LastUpdated) > pullOptionConfig.Interval {
+	if lastUpdated != nil && time.Since(*lastUpdated) > pullOptionConfig.Interval {
 		return true
 	}
 
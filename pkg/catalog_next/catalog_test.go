@@ -193,6 +193,34 @@ func TestCatalogDigestWithTools(t *testing.T) {
 	assert.NotEqual(t, digest1, digest2)
 }
 
+func TestCatalogDigestWithAnnotations(t *testing.T) {
+	newCatalog := func(annotations map[string]string) Catalog {
+		return Catalog{
+			CatalogArtifact: CatalogArtifact{
+				Title: "test",
+				Servers: []Server{
+					{
+						Type:        workingset.ServerTypeImage,
+						Image:       "docker/test:latest",
+						Annotations: annotations,
+					},
+				},
+			},
+		}
+	}
+
+	digestOf := func(annotations map[string]string) string {
+		catalog := newCatalog(annotations)
+		digest, err := catalog.Digest()
+		require.NoError(t, err)
+		return digest
+	}
+
+	assert.NotEqual(t, digestOf(nil), digestOf(map[string]string{"owner": "team-a"}))
+	assert.NotEqual(t, digestOf(map[string]string{"owner": "team-a"}), digestOf(map[string]string{"owner": "team-b"}))
+	assert.Equal(t, digestOf(map[string]string{"owner": "team-a", "ticket": "MCP-42"}), digestOf(map[string]string{"ticket": "MCP-42", "owner": "team-a"}))
+}
+
 // Test Catalog.Validate()
 func TestCatalogValidateSuccess(t *testing.T) {
 	tests := []struct {
@@ -338,6 +366,10 @@ func TestCatalogToDbAndFromDb(t *testing.T) {
 					Type:   workingset.ServerTypeRegistry,
 					Source: "https://example.com",
 					Tools:  []string{"tool3"},
+					Annotations: map[string]string{
+						"owner":  "team-a",
+						"ticket": "MCP-42",
+					},
 				},
 			},
 		},
@@ -365,6 +397,7 @@ func TestCatalogToDbAndFromDb(t *testing.T) {
 	assert.Equal(t, string(workingset.ServerTypeRegistry), dbCatalog.Servers[1].ServerType)
 	assert.Equal(t, "https://example.com", dbCatalog.Servers[1].Source)
 	assert.Equal(t, []string{"tool3"}, []string(dbCatalog.Servers[1].Tools))
+	assert.Equal(t, db.Annotations{"owner": "team-a", "ticket": "MCP-42"}, dbCatalog.Servers[1].Annotations)
 
 	// Convert back from DB
 	catalogWithDigest := NewFromDb(&dbCatalog)
@@ -385,6 +418,39 @@ func TestCatalogToDbAndFromDb(t *testing.T) {
 	assert.Equal(t, catalog.Servers[1].Type, catalogWithDigest.Servers[1].Type)
 	assert.Equal(t, catalog.Servers[1].Source, catalogWithDigest.Servers[1].Source)
 	assert.Equal(t, catalog.Servers[1].Tools, catalogWithDigest.Servers[1].Tools)
+	assert.Equal(t, catalog.Servers[1].Annotations, catalogWithDigest.Servers[1].Annotations)
+	assert.Nil(t, catalogWithDigest.Servers[0].Annotations)
+}
+
+func TestServerStableIDIsReadFromDb(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := Server{
+		Type:     workingset.ServerTypeImage,
+		Image:    "docker/test:latest",
+		Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "test-server"}},
+	}
+	derived := server.StableID()
+	require.NotEmpty(t, derived)
+
+	// A server stored with an ID keeps it, rather than getting the one
+	// derived from what it runs.
+	dbCatalog, err := Catalog{Ref: "test/catalog:latest", CatalogArtifact: CatalogArtifact{Servers: []Server{server}}}.ToDb()
+	require.NoError(t, err)
+	dbCatalog.Servers[0].StableID = "srv-0123456789abcdef"
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCatalog))
+
+	stored, err := dao.GetCatalog(ctx, "test/catalog:latest")
+	require.NoError(t, err)
+	catalogWithDigest := NewFromDb(stored)
+	assert.Equal(t, "srv-0123456789abcdef", catalogWithDigest.Servers[0].StableID())
+
+	// The ID is written back, but isn't part of the digest.
+	roundTripped, err := catalogWithDigest.ToDb()
+	require.NoError(t, err)
+	assert.Equal(t, "srv-0123456789abcdef", roundTripped.Servers[0].StableID)
+	assert.Equal(t, dbCatalog.Digest, roundTripped.Digest)
 }
 
 // Test NewPullOptionEvaluator
@@ -906,3 +972,40 @@ func TestCommunityRegistryCatalogRefMatchesNormalizedOCI(t *testing.T) {
 			"CommunityRegistryCatalogRef should match normalized form of %q", input)
 	}
 }
+
+func TestOCIOnlyOperationsRejectCommunityRegistryRef(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	for _, ref := range []string{"registry.modelcontextprotocol.io", "https://registry.modelcontextprotocol.io/"} {
+		err := Push(ctx, dao, ref, workingset.Output{})
+		require.ErrorContains(t, err, "cannot push "+ref+": it is a community registry, not an OCI catalog reference")
+
+		err = Pull(ctx, dao, mocks.NewMockOCIService(), ref, workingset.Output{})
+		require.ErrorContains(t, err, "cannot pull "+ref+": it is a community registry")
+
+		_, err = PinImages(ctx, dao, mocks.NewMockOCIService(), ref, false)
+		require.ErrorContains(t, err, "cannot pin "+ref+": it is a community registry")
+	}
+}
+
+func TestIsCommunityRegistryRef(t *testing.T) {
+	assert.True(t, isCommunityRegistryRef("registry.modelcontextprotocol.io"))
+	assert.True(t, isCommunityRegistryRef("https://registry.modelcontextprotocol.io"))
+	assert.True(t, isCommunityRegistryRef("registry:registry.modelcontextprotocol.io"))
+	assert.False(t, isCommunityRegistryRef(CommunityRegistryCatalogRef))
+	assert.False(t, isCommunityRegistryRef("docker/mcp-catalog:latest"))
+}
+
+func TestPushResolvesAlias(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	require.NoError(t, SetAlias(ctx, dao, "team", "test/catalog:latest", workingset.Output{}))
+
+	// The alias is resolved to the catalog it stands for, which must exist.
+	err := Push(ctx, dao, "team", workingset.Output{})
+	require.EqualError(t, err, "catalog test/catalog:latest not found")
+
+	err = Push(ctx, dao, "test/catalog@sha256:0000000000000000000000000000000000000000000000000000000000000000", workingset.Output{})
+	require.ErrorContains(t, err, "must be a valid OCI reference without a digest")
+}
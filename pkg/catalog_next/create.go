@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"slices"
 	"sort"
@@ -12,7 +14,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/name"
 	"golang.org/x/sync/errgroup"
 
 	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
@@ -34,6 +35,52 @@ type CreateOptions struct {
 	IncludePyPI          bool
 	IncludeNPM           bool
 	ExcludeServers       []string
+	// Strict fails the creation, instead of warning, when a server declares
+	// config fields that look like secrets.
+	Strict bool
+	// GroupedConfig keeps one config item per environment variable grouping
+	// config variables, instead of one per server, for the servers of a
+	// community registry.
+	GroupedConfig bool
+	// NameCollisions is how the servers of a community registry whose names
+	// collide are handled: NameCollisionsSkip (the default) or
+	// NameCollisionsSuffix.
+	NameCollisions string
+	// ResolveSnapshots fills the tools of the image servers of a legacy
+	// catalog that don't list any, from the metadata of their image.
+	ResolveSnapshots bool
+	// Base is the reference of a catalog whose servers the new catalog
+	// inherits. It must have been pulled or created already.
+	Base string
+	// HTTPClient fetches LegacyCatalogURL when it's an http(s) URL. Defaults
+	// to a guarded direct client.
+	HTTPClient *http.Client
+	// MaxServers fails the creation when the catalog would have more servers,
+	// e.g. when importing a large community registry. 0 means no limit.
+	MaxServers int
+	// TruncateToMaxServers keeps the first MaxServers servers, with a
+	// warning, instead of failing.
+	TruncateToMaxServers bool
+	// TransformCacheDir is the directory caching the servers transformed
+	// from the community registry. Empty disables the cache.
+	TransformCacheDir string
+	// RegistryCacheDir is the directory caching the listing of the servers
+	// of the community registry. Empty disables the cache.
+	RegistryCacheDir string
+	// RegistryCachePull is the pull option of the cached listing, e.g. "1h"
+	// to download it again once it's older than an hour. Defaults to
+	// DefaultRegistryCachePull.
+	RegistryCachePull string
+	// Output chooses what's printed on stdout: prose, nothing, or the
+	// created catalog as JSON.
+	Output workingset.Output
+}
+
+// CreateResult is the JSON result of Create.
+type CreateResult struct {
+	Ref     string   `json:"ref"`
+	Title   string   `json:"title"`
+	Servers []string `json:"servers"`
 }
 
 func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, refStr string, opts CreateOptions) error {
@@ -44,12 +91,9 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		duration := time.Since(start)
 		telemetry.RecordCatalogOperation(ctx, "create", refStr, float64(duration.Milliseconds()), success)
 	}()
-	ref, err := name.ParseReference(refStr)
+	catalogRef, err := oci.NormalizeCatalogRef(refStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
-	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference must be a valid OCI reference without a digest")
+		return err
 	}
 
 	var catalog Catalog
@@ -58,13 +102,24 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		if err != nil {
 			return fmt.Errorf("failed to create catalog from profile: %w", err)
 		}
+		if err := catalog.CheckSecretLikeConfig(opts.Strict); err != nil {
+			return err
+		}
 	} else if opts.LegacyCatalogURL != "" {
-		catalog, err = createCatalogFromLegacyCatalog(ctx, opts.LegacyCatalogURL)
+		catalog, err = createCatalogFromLegacyCatalog(ctx, opts.HTTPClient, opts.LegacyCatalogURL)
 		if err != nil {
 			return fmt.Errorf("failed to create catalog from legacy catalog: %w", err)
 		}
+		if opts.ResolveSnapshots {
+			if err := resolveMissingTools(ctx, ociService, &catalog); err != nil {
+				return err
+			}
+		}
+		if err := catalog.CheckSecretLikeConfig(opts.Strict); err != nil {
+			return err
+		}
 	} else if opts.CommunityRegistryRef != "" {
-		catalog, err = createCatalogFromCommunityRegistry(ctx, registryClient, opts.CommunityRegistryRef, opts.IncludePyPI, opts.IncludeNPM, opts.ExcludeServers)
+		catalog, err = createCatalogFromCommunityRegistry(ctx, registryClient, opts.CommunityRegistryRef, opts.IncludePyPI, opts.IncludeNPM, opts.ExcludeServers, opts.Strict, opts.GroupedConfig, opts.NameCollisions, opts.TransformCacheDir, opts.RegistryCacheDir, opts.RegistryCachePull)
 		if err != nil {
 			return fmt.Errorf("failed to create catalog from community registry: %w", err)
 		}
@@ -82,16 +137,23 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		}
 	}
 
-	catalog.Ref = oci.FullNameWithoutDigest(ref)
+	catalog.Ref = catalogRef
 
 	if opts.Title != "" {
 		catalog.Title = opts.Title
 	}
+	if opts.Base != "" {
+		catalog.Base = opts.Base
+	}
 
 	if err := addServersToCatalog(ctx, dao, registryClient, ociService, &catalog, opts.Servers); err != nil {
 		return err
 	}
 
+	if err := checkMaxServers(&catalog, opts.MaxServers, opts.TruncateToMaxServers); err != nil {
+		return err
+	}
+
 	if err := catalog.Validate(); err != nil {
 		return fmt.Errorf("invalid catalog: %w", err)
 	}
@@ -101,14 +163,47 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		return fmt.Errorf("failed to convert catalog to db: %w", err)
 	}
 
+	// Fail early on a missing base catalog or a cycle of base catalogs.
+	if _, err := db.ResolveCatalogBase(ctx, dao, &dbCatalog); err != nil {
+		return err
+	}
+
 	err = dao.UpsertCatalog(ctx, dbCatalog)
 	if err != nil {
 		return fmt.Errorf("failed to create catalog: %w", err)
 	}
 
-	fmt.Printf("Catalog %s created\n", catalog.Ref)
+	opts.Output.Printf("Catalog %s created\n", catalog.Ref)
 
 	success = true
+	return opts.Output.Result(CreateResult{
+		Ref:     catalog.Ref,
+		Title:   catalog.Title,
+		Servers: serverDisplayNames(catalog.Servers),
+	})
+}
+
+// serverDisplayNames returns the display names of servers.
+func serverDisplayNames(servers []Server) []string {
+	names := make([]string, 0, len(servers))
+	for _, server := range servers {
+		names = append(names, serverDisplayName(server))
+	}
+	return names
+}
+
+// checkMaxServers fails when the catalog has more than maxServers servers, or
+// drops the extra servers with a warning when truncate is set.
+func checkMaxServers(catalog *Catalog, maxServers int, truncate bool) error {
+	if maxServers <= 0 || len(catalog.Servers) <= maxServers {
+		return nil
+	}
+	if !truncate {
+		return fmt.Errorf("catalog would have %d servers, more than the maximum of %d", len(catalog.Servers), maxServers)
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: catalog truncated to the first %d of its %d servers\n", maxServers, len(catalog.Servers))
+	catalog.Servers = catalog.Servers[:maxServers]
 	return nil
 }
 
@@ -123,7 +218,9 @@ func addServersToCatalog(ctx context.Context, dao db.DAO, registryClient registr
 			return err
 		}
 		for _, s := range ss {
-			catalog.Servers = append(catalog.Servers, workingSetServerToCatalogServer(s))
+			catalogServer := workingSetServerToCatalogServer(s)
+			catalogServer.AddedFrom = server
+			catalog.Servers = append(catalog.Servers, catalogServer)
 		}
 	}
 
@@ -141,9 +238,12 @@ func createCatalogFromWorkingSet(ctx context.Context, dao db.DAO, workingSetID s
 
 	workingSet := workingset.NewFromDb(dbWorkingSet)
 
+	source := SourcePrefixWorkingSet + workingSet.ID
+
 	servers := make([]Server, len(workingSet.Servers))
 	for i, server := range workingSet.Servers {
 		servers[i] = workingSetServerToCatalogServer(server)
+		servers[i].AddedFrom = source
 	}
 
 	return Catalog{
@@ -151,12 +251,25 @@ func createCatalogFromWorkingSet(ctx context.Context, dao db.DAO, workingSetID s
 			Title:   workingSet.Name,
 			Servers: servers,
 		},
-		Source: SourcePrefixWorkingSet + workingSet.ID,
+		Source: source,
 	}, nil
 }
 
-func createCatalogFromLegacyCatalog(ctx context.Context, legacyCatalogURL string) (Catalog, error) {
-	legacyCatalog, name, displayName, err := legacycatalog.ReadOne(ctx, legacyCatalogURL)
+// createCatalogFromLegacyCatalog reads a legacy catalog from a local file or,
+// with httpClient, from an http(s) URL.
+func createCatalogFromLegacyCatalog(ctx context.Context, httpClient *http.Client, legacyCatalogURL string) (Catalog, error) {
+	var (
+		legacyCatalog     legacycatalog.Catalog
+		name, displayName string
+		err               error
+	)
+	addedFrom := SourcePrefixLegacyCatalog + legacyCatalogURL
+	if isHTTPURL(legacyCatalogURL) {
+		legacyCatalog, name, displayName, err = legacycatalog.ReadURL(ctx, httpClient, legacyCatalogURL)
+		addedFrom = SourcePrefixLegacyCatalogURL + legacyCatalogURL
+	} else {
+		legacyCatalog, name, displayName, err = legacycatalog.ReadOne(ctx, legacyCatalogURL)
+	}
 	if err != nil {
 		return Catalog{}, fmt.Errorf("failed to read legacy catalog: %w", err)
 	}
@@ -166,8 +279,9 @@ func createCatalogFromLegacyCatalog(ctx context.Context, legacyCatalogURL string
 		server = normalizeCatalogServerURLs(server)
 		if server.Type == "server" && server.Image != "" {
 			s := Server{
-				Type:  workingset.ServerTypeImage,
-				Image: server.Image,
+				Type:      workingset.ServerTypeImage,
+				Image:     server.Image,
+				AddedFrom: addedFrom,
 				Snapshot: &workingset.ServerSnapshot{
 					Server: server,
 				},
@@ -176,8 +290,19 @@ func createCatalogFromLegacyCatalog(ctx context.Context, legacyCatalogURL string
 			servers = append(servers, s)
 		} else if server.Type == "remote" {
 			s := Server{
-				Type:     workingset.ServerTypeRemote,
-				Endpoint: server.Remote.URL,
+				Type:      workingset.ServerTypeRemote,
+				Endpoint:  server.Remote.URL,
+				AddedFrom: addedFrom,
+				Snapshot: &workingset.ServerSnapshot{
+					Server: server,
+				},
+			}
+			s.Snapshot.Server.Name = name
+			servers = append(servers, s)
+		} else if server.Type == legacycatalog.ServerTypePOCI && hasToolContainers(server) {
+			s := Server{
+				Type:      workingset.ServerTypePOCI,
+				AddedFrom: addedFrom,
 				Snapshot: &workingset.ServerSnapshot{
 					Server: server,
 				},
@@ -195,15 +320,54 @@ func createCatalogFromLegacyCatalog(ctx context.Context, legacyCatalogURL string
 		displayName = "Legacy Catalog"
 	}
 
+	source := SourcePrefixLegacyCatalog + name
+	if isHTTPURL(legacyCatalogURL) {
+		source = addedFrom
+	}
+
 	return Catalog{
 		CatalogArtifact: CatalogArtifact{
 			Title:   displayName,
 			Servers: servers,
 		},
-		Source: SourcePrefixLegacyCatalog + name,
+		Source: source,
 	}, nil
 }
 
+// hasToolContainers reports whether a poci server has tools that the gateway
+// can run, i.e. with a container image.
+func hasToolContainers(server legacycatalog.Server) bool {
+	for _, tool := range server.Tools {
+		if tool.Container.Image != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// resolveMissingTools fills the tools of the image servers that don't list
+// any with the tools of the snapshot resolved from their image. The rest of
+// the snapshot is left as is.
+func resolveMissingTools(ctx context.Context, ociService oci.Service, catalog *Catalog) error {
+	for i := range catalog.Servers {
+		server := &catalog.Servers[i]
+		if server.Type != workingset.ServerTypeImage || server.Snapshot == nil || len(server.Snapshot.Server.Tools) > 0 {
+			continue
+		}
+
+		snapshot, err := workingset.ResolveImageSnapshot(ctx, ociService, server.Image)
+		if err != nil {
+			return fmt.Errorf("failed to resolve snapshot of server %s: %w", server.Snapshot.Server.Name, err)
+		}
+		server.Snapshot.Server.Tools = snapshot.Server.Tools
+	}
+	return nil
+}
+
 func workingSetServerToCatalogServer(server workingset.Server) Server {
 	snapshot := server.Snapshot
 	if snapshot != nil {
@@ -213,12 +377,13 @@ func workingSetServerToCatalogServer(server workingset.Server) Server {
 	}
 
 	return Server{
-		Type:     server.Type,
-		Tools:    server.Tools,
-		Source:   server.Source,
-		Image:    server.Image,
-		Endpoint: server.Endpoint,
-		Snapshot: snapshot,
+		Type:              server.Type,
+		Tools:             server.Tools,
+		Source:            server.Source,
+		Image:             server.Image,
+		Endpoint:          server.Endpoint,
+		LongLivedOverride: server.LongLivedOverride,
+		Snapshot:          snapshot,
 	}
 }
 
@@ -233,14 +398,48 @@ type communityRegistryResult struct {
 	serversPyPI    int
 	serversNPM     int
 	serversRemote  int
+	serversPOCI    int
 	serversSkipped int
 	totalServers   int
 	skippedByType  map[string]int
+	// nameCollisions lists the registry servers whose name normalizes to the
+	// catalog name of another server, by catalog name. Renamed servers are
+	// listed with their new name.
+	nameCollisions map[string][]string
 }
 
-func createCatalogFromCommunityRegistry(ctx context.Context, registryClient registryapi.Client, registryRef string, includePyPI bool, includeNPM bool, excludeServers []string) (Catalog, error) {
-	baseURL := "https://" + registryRef
-	servers, err := registryClient.ListServers(ctx, baseURL, "")
+// skippedNameCollision is the skip reason of servers whose catalog name
+// collides with the name of another server of the registry.
+const skippedNameCollision = "name collision"
+
+// Values accepted by CreateOptions.NameCollisions.
+const (
+	// NameCollisionsSkip keeps the colliding server whose registry name sorts
+	// first and skips the others.
+	NameCollisionsSkip = "skip"
+	// NameCollisionsSuffix keeps the colliding server whose registry name
+	// sorts first and renames the others with a numeric suffix, e.g.
+	// io-example-server-2.
+	NameCollisionsSuffix = "suffix"
+)
+
+func createCatalogFromCommunityRegistry(ctx context.Context, registryClient registryapi.Client, registryRef string, includePyPI bool, includeNPM bool, excludeServers []string, strict bool, groupedConfig bool, nameCollisionPolicy string, transformCacheDir string, registryCacheDir string, registryCachePull string) (Catalog, error) {
+	switch nameCollisionPolicy {
+	case "", NameCollisionsSkip, NameCollisionsSuffix:
+	default:
+		return Catalog{}, fmt.Errorf("unknown name collision policy %q, expected '%s' or '%s'", nameCollisionPolicy, NameCollisionsSkip, NameCollisionsSuffix)
+	}
+
+	// The credentials of the registry, if any, are only used to list its
+	// servers, never recorded
+	baseURL, registryRef, err := registryapi.ParseRegistryBaseURL(registryRef)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("invalid community registry: %w", err)
+	}
+	if registryCachePull == "" {
+		registryCachePull = DefaultRegistryCachePull
+	}
+	servers, err := listRegistryServers(ctx, registryClient, baseURL, registryRef, registryCacheDir, registryCachePull)
 	if err != nil {
 		return Catalog{}, fmt.Errorf("failed to fetch servers from community registry: %w", err)
 	}
@@ -254,11 +453,18 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 		legacycatalog.WithPyPIResolver(pypiResolver),
 		legacycatalog.WithAllowNPM(includeNPM),
 		legacycatalog.WithNPMResolver(npmResolver),
+		legacycatalog.WithStrictConfig(strict),
+		legacycatalog.WithGroupedConfig(groupedConfig),
+	}
+	if transformCacheDir != "" {
+		transformOpts = append(transformOpts, legacycatalog.WithTransformCache(legacycatalog.NewTransformCache(transformCacheDir)))
 	}
 
 	type transformResult struct {
-		server Server
-		source legacycatalog.TransformSource
+		registryName string
+		detail       legacycatalog.ServerDetail
+		server       Server
+		source       legacycatalog.TransformSource
 	}
 
 	results := make([]transformResult, len(servers))
@@ -266,7 +472,6 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 
 	var mu sync.Mutex
 	skippedByType := make(map[string]int)
-	var ociCount, remoteCount, pypiCount, npmCount int
 
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(12)
@@ -281,6 +486,9 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 
 		g.Go(func() error {
 			catalogServer, transformSource, err := legacycatalog.TransformToDocker(gctx, serverResp.Server, transformOpts...)
+			if errors.Is(err, legacycatalog.ErrSecretLikeConfig) {
+				return err
+			}
 			if err != nil {
 				mu.Lock()
 				if !errors.Is(err, legacycatalog.ErrIncompatibleServer) {
@@ -295,47 +503,12 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 				return nil
 			}
 
-			if catalogServer.Metadata == nil {
-				catalogServer.Metadata = &legacycatalog.Metadata{}
-			}
-			catalogServer.Metadata.Tags = appendIfMissing(catalogServer.Metadata.Tags, "community")
-
-			var s Server
-			switch catalogServer.Type {
-			case "server":
-				mu.Lock()
-				switch transformSource {
-				case legacycatalog.TransformSourcePyPI:
-					pypiCount++
-				case legacycatalog.TransformSourceNPM:
-					npmCount++
-				default:
-					ociCount++
-				}
-				mu.Unlock()
-				s = Server{
-					Type:  workingset.ServerTypeImage,
-					Image: catalogServer.Image,
-					Snapshot: &workingset.ServerSnapshot{
-						Server: *catalogServer,
-					},
-				}
-			case "remote":
-				mu.Lock()
-				remoteCount++
-				mu.Unlock()
-				s = Server{
-					Type:     workingset.ServerTypeRemote,
-					Endpoint: catalogServer.Remote.URL,
-					Snapshot: &workingset.ServerSnapshot{
-						Server: *catalogServer,
-					},
-				}
-			default:
+			s, ok := communityRegistryServer(catalogServer, registryRef)
+			if !ok {
 				return nil
 			}
 
-			results[i] = transformResult{server: s, source: transformSource}
+			results[i] = transformResult{registryName: serverResp.Server.Name, detail: serverResp.Server, server: s, source: transformSource}
 			resultValid[i] = true
 			return nil
 		})
@@ -345,11 +518,70 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 		return Catalog{}, fmt.Errorf("failed to transform servers: %w", err)
 	}
 
-	catalogServers := make([]Server, 0, len(servers))
+	valid := make([]transformResult, 0, len(servers))
 	for i := range results {
 		if resultValid[i] {
-			catalogServers = append(catalogServers, results[i].server)
+			valid = append(valid, results[i])
+		}
+	}
+
+	// Different registry names can normalize to the same catalog name, e.g.
+	// io.example/server and io-example/server. Keep the server whose registry
+	// name sorts first, so that the result doesn't depend on the order of the
+	// registry, and skip or rename the others.
+	slices.SortStableFunc(valid, func(a, b transformResult) int {
+		return strings.Compare(a.registryName, b.registryName)
+	})
+	names := make(map[string]bool)
+	for _, result := range valid {
+		names[result.server.Snapshot.Server.Name] = true
+	}
+	keptBy := make(map[string]string)
+	nameCollisions := make(map[string][]string)
+	catalogServers := make([]Server, 0, len(valid))
+	var ociCount, remoteCount, pypiCount, npmCount, pociCount int
+	for _, result := range valid {
+		name := result.server.Snapshot.Server.Name
+		if kept, ok := keptBy[name]; ok {
+			renamed, ok := Server{}, false
+			if nameCollisionPolicy == NameCollisionsSuffix {
+				// The name is used in the config, secrets and volumes of the
+				// server: transform it again rather than renaming its snapshot.
+				newName := suffixedServerName(name, names)
+				catalogServer, _, err := legacycatalog.TransformToDocker(ctx, result.detail, append(slices.Clone(transformOpts), legacycatalog.WithServerName(newName))...)
+				if err == nil {
+					renamed, ok = communityRegistryServer(catalogServer, registryRef)
+				}
+			}
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Warning: skipping server %q: its name %q collides with server %q\n", result.registryName, name, kept)
+				nameCollisions[name] = append(nameCollisions[name], result.registryName)
+				skippedByType[skippedNameCollision]++
+				continue
+			}
+
+			newName := renamed.Snapshot.Server.Name
+			fmt.Fprintf(os.Stderr, "Warning: renaming server %q to %q: its name %q collides with server %q\n", result.registryName, newName, name, kept)
+			nameCollisions[name] = append(nameCollisions[name], fmt.Sprintf("%s (renamed to %s)", result.registryName, newName))
+			names[newName] = true
+			result.server = renamed
+			name = newName
+		}
+		keptBy[name] = result.registryName
+
+		switch {
+		case result.server.Type == workingset.ServerTypeRemote:
+			remoteCount++
+		case result.server.Type == workingset.ServerTypePOCI:
+			pociCount++
+		case result.source == legacycatalog.TransformSourcePyPI:
+			pypiCount++
+		case result.source == legacycatalog.TransformSourceNPM:
+			npmCount++
+		default:
+			ociCount++
 		}
+		catalogServers = append(catalogServers, result.server)
 	}
 
 	slices.SortStableFunc(catalogServers, func(a, b Server) int {
@@ -362,9 +594,11 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 		serversPyPI:    pypiCount,
 		serversNPM:     npmCount,
 		serversRemote:  remoteCount,
+		serversPOCI:    pociCount,
 		serversSkipped: totalSkipped(skippedByType),
 		totalServers:   len(servers),
 		skippedByType:  skippedByType,
+		nameCollisions: nameCollisions,
 	}
 	printCommunityRegistryResult(registryRef, result, includePyPI, includeNPM)
 
@@ -377,6 +611,56 @@ func createCatalogFromCommunityRegistry(ctx context.Context, registryClient regi
 	}, nil
 }
 
+// communityRegistryServer returns the catalog server of a server transformed
+// from a community registry, if its type is supported.
+func communityRegistryServer(catalogServer *legacycatalog.Server, registryRef string) (Server, bool) {
+	if catalogServer.Metadata == nil {
+		catalogServer.Metadata = &legacycatalog.Metadata{}
+	}
+	catalogServer.Metadata.Tags = appendIfMissing(catalogServer.Metadata.Tags, "community")
+
+	var s Server
+	switch catalogServer.Type {
+	case "server":
+		s = Server{
+			Type:  workingset.ServerTypeImage,
+			Image: catalogServer.Image,
+			Snapshot: &workingset.ServerSnapshot{
+				Server: *catalogServer,
+			},
+		}
+	case "remote":
+		s = Server{
+			Type:     workingset.ServerTypeRemote,
+			Endpoint: catalogServer.Remote.URL,
+			Snapshot: &workingset.ServerSnapshot{
+				Server: *catalogServer,
+			},
+		}
+	case legacycatalog.ServerTypePOCI:
+		s = Server{
+			Type: workingset.ServerTypePOCI,
+			Snapshot: &workingset.ServerSnapshot{
+				Server: *catalogServer,
+			},
+		}
+	default:
+		return Server{}, false
+	}
+	s.AddedFrom = SourcePrefixRegistry + registryRef
+	return s, true
+}
+
+// suffixedServerName returns the first name-<n>, from 2, that isn't taken.
+func suffixedServerName(name string, taken map[string]bool) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", name, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
 func totalSkipped(skippedByType map[string]int) int {
 	total := 0
 	for _, count := range skippedByType {
@@ -397,6 +681,9 @@ func printCommunityRegistryResult(refStr string, result communityRegistryResult,
 		fmt.Fprintf(os.Stderr, "    npm (stdio):     %d\n", result.serversNPM)
 	}
 	fmt.Fprintf(os.Stderr, "    Remote:          %d\n", result.serversRemote)
+	if result.serversPOCI > 0 {
+		fmt.Fprintf(os.Stderr, "    POCI:            %d\n", result.serversPOCI)
+	}
 	fmt.Fprintf(os.Stderr, "  Skipped:           %d\n", result.serversSkipped)
 
 	if len(result.skippedByType) > 0 {
@@ -419,6 +706,13 @@ func printCommunityRegistryResult(refStr string, result communityRegistryResult,
 			fmt.Fprintf(os.Stderr, "    %-17s%d\n", label+":", tc.count)
 		}
 	}
+
+	if len(result.nameCollisions) > 0 {
+		fmt.Fprintf(os.Stderr, "  Name collisions:\n")
+		for _, name := range slices.Sorted(maps.Keys(result.nameCollisions)) {
+			fmt.Fprintf(os.Stderr, "    %s: %s\n", name, strings.Join(result.nameCollisions[name], ", "))
+		}
+	}
 }
 
 func appendIfMissing(slice []string, val string) []string {
@@ -0,0 +1,262 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// Severities of lint findings.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+// Lint rules.
+const (
+	// LintRuleNoTools flags servers that don't list their tools.
+	LintRuleNoTools = "no-tools"
+	// LintRuleMutableTag flags images using the latest tag instead of a digest.
+	LintRuleMutableTag = "mutable-tag"
+	// LintRuleInsecureRemote flags remote servers over plain http.
+	LintRuleInsecureRemote = "insecure-remote"
+	// LintRuleUnusedSecret flags secrets that no environment variable or
+	// header receives.
+	LintRuleUnusedSecret = "unused-secret"
+	// LintRuleDanglingPlaceholder flags {{...}} placeholders that don't match
+	// any declared config property.
+	LintRuleDanglingPlaceholder = "dangling-placeholder"
+)
+
+type LintFinding struct {
+	Server   string `yaml:"server" json:"server"`
+	Rule     string `yaml:"rule" json:"rule"`
+	Severity string `yaml:"severity" json:"severity"`
+	Message  string `yaml:"message" json:"message"`
+}
+
+var placeholderPattern = regexp.MustCompile(`{{(.*?)}}`)
+
+// Lint checks the servers of a catalog for common authoring mistakes and
+// returns the findings, server by server.
+func Lint(ctx context.Context, dao db.DAO, refStr string) ([]LintFinding, error) {
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCatalog, err := dao.GetCatalog(ctx, refStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("catalog %s not found", refStr)
+		}
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return nil, err
+	}
+
+	return lintCatalog(NewFromDb(dbCatalog).Catalog), nil
+}
+
+// PrintLintFindings prints the findings of Lint in the given format.
+func PrintLintFindings(findings []LintFinding, format workingset.OutputFormat) error {
+	var data []byte
+	var err error
+	switch format {
+	case workingset.OutputFormatHumanReadable:
+		data = []byte(printLintFindingsHumanReadable(findings))
+	case workingset.OutputFormatJSON:
+		data, err = json.MarshalIndent(findings, "", "  ")
+	case workingset.OutputFormatYAML:
+		data, err = yaml.Marshal(findings)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal lint findings: %w", err)
+	}
+
+	return workingset.Output{}.Print(data)
+}
+
+// LintErrors returns the number of findings with the error severity.
+func LintErrors(findings []LintFinding) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == LintSeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+func lintCatalog(catalog Catalog) []LintFinding {
+	findings := []LintFinding{}
+	for _, server := range catalog.Servers {
+		findings = append(findings, lintServer(server)...)
+	}
+	return findings
+}
+
+func lintServer(server Server) []LintFinding {
+	serverName := serverDisplayName(server)
+	var findings []LintFinding
+	add := func(rule, severity, format string, a ...any) {
+		findings = append(findings, LintFinding{
+			Server:   serverName,
+			Rule:     rule,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	if server.Snapshot == nil || len(server.Snapshot.Server.Tools) == 0 {
+		add(LintRuleNoTools, LintSeverityWarning, "no tools listed, clients can't know what the server does before starting it")
+	}
+
+	if server.Type == workingset.ServerTypeImage {
+		if ref, err := name.ParseReference(server.Image); err == nil && !oci.HasDigest(ref) && ref.Identifier() == "latest" {
+			add(LintRuleMutableTag, LintSeverityWarning, "image %s uses the mutable latest tag, pin it to a digest", server.Image)
+		}
+	}
+
+	if server.Type == workingset.ServerTypeRemote {
+		if u, err := url.Parse(server.Endpoint); err == nil && u.Scheme == "http" {
+			add(LintRuleInsecureRemote, LintSeverityError, "remote endpoint %s doesn't use https", server.Endpoint)
+		}
+	}
+
+	if server.Snapshot == nil {
+		return findings
+	}
+	snapshot := server.Snapshot.Server
+
+	for _, secret := range unusedSecrets(snapshot) {
+		add(LintRuleUnusedSecret, LintSeverityWarning, "secret %s is declared but no environment variable or header receives it", secret)
+	}
+	for _, placeholder := range danglingPlaceholders(snapshot) {
+		add(LintRuleDanglingPlaceholder, LintSeverityError, "placeholder {{%s}} doesn't match any declared config property", placeholder)
+	}
+
+	return findings
+}
+
+func serverDisplayName(server Server) string {
+	if server.Snapshot != nil && server.Snapshot.Server.Name != "" {
+		return server.Snapshot.Server.Name
+	}
+	if server.Type == workingset.ServerTypeRemote {
+		return server.Endpoint
+	}
+	return server.Image
+}
+
+// unusedSecrets returns the secrets that are not exposed to the server.
+// Containers receive their secrets as environment variables, while remote
+// servers only receive those referenced by headers, e.g. "Bearer ${TOKEN}",
+// or by an OAuth provider.
+func unusedSecrets(server legacycatalog.Server) []string {
+	referenced := map[string]bool{}
+	if server.Type == "remote" {
+		for _, value := range server.Remote.Headers {
+			os.Expand(value, func(env string) string {
+				referenced[env] = true
+				return ""
+			})
+		}
+		if server.OAuth != nil {
+			for _, provider := range server.OAuth.Providers {
+				referenced[provider.Env] = true
+			}
+		}
+	}
+
+	var unused []string
+	for _, secret := range server.Secrets {
+		if secret.Env == "" || (server.Type == "remote" && !referenced[secret.Env]) {
+			unused = append(unused, secret.Name)
+		}
+	}
+	return unused
+}
+
+// danglingPlaceholders returns the {{server.property}} placeholders that
+// don't match a declared config property, without duplicates. The gateway
+// gives the values of all the config items of a server under the server's
+// name, so any of their properties can be referenced with it.
+func danglingPlaceholders(server legacycatalog.Server) []string {
+	serverName := oci.CanonicalizeServerName(server.Name)
+	properties := map[string]bool{}
+	anyProperty := false
+	for _, item := range server.Config {
+		config, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		itemProperties, _ := config["properties"].(map[string]any)
+		if itemProperties == nil {
+			anyProperty = true
+		}
+		for property := range itemProperties {
+			properties[property] = true
+		}
+	}
+	declared := len(properties) > 0 || anyProperty
+
+	values := append([]string{server.User, server.Remote.URL}, server.Command...)
+	values = append(values, server.Volumes...)
+	for _, env := range server.Env {
+		values = append(values, env.Value)
+	}
+	for _, header := range slices.Sorted(maps.Keys(server.Remote.Headers)) {
+		values = append(values, server.Remote.Headers[header])
+	}
+
+	var dangling []string
+	for _, value := range values {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+			path, _, _ := strings.Cut(match[1], "|")
+			configName, property, hasProperty := strings.Cut(strings.TrimSpace(path), ".")
+			found := declared && strings.TrimSpace(configName) == serverName
+			if found && hasProperty && !anyProperty {
+				found = properties[strings.TrimSpace(property)]
+			}
+			if !found && !slices.Contains(dangling, strings.TrimSpace(match[1])) {
+				dangling = append(dangling, strings.TrimSpace(match[1]))
+			}
+		}
+	}
+	return dangling
+}
+
+func printLintFindingsHumanReadable(findings []LintFinding) string {
+	if len(findings) == 0 {
+		return "No issues found"
+	}
+
+	lines := make([]string, 0, len(findings)+1)
+	for _, finding := range findings {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s (%s)", finding.Severity, finding.Server, finding.Message, finding.Rule))
+	}
+	lines = append(lines, fmt.Sprintf("\n%d error(s), %d warning(s)", LintErrors(findings), len(findings)-LintErrors(findings)))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,297 @@
+package catalognext
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func lintTestServer(serverType workingset.ServerType, server catalog.Server) Server {
+	s := statsTestServer(serverType, server)
+	if serverType == workingset.ServerTypeImage {
+		s.Image = "mcp/" + server.Name + "@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	}
+	return s
+}
+
+func TestLintCatalog(t *testing.T) {
+	tools := statsTestTools("search")
+
+	tests := []struct {
+		name     string
+		server   Server
+		expected []LintFinding
+	}{
+		{
+			name: "clean image server",
+			server: lintTestServer(workingset.ServerTypeImage, catalog.Server{
+				Name:    "github",
+				Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}},
+				Env:     []catalog.Env{{Name: "GITHUB_OWNER", Value: "{{github.owner}}"}},
+				Config: []any{map[string]any{
+					"name":       "github",
+					"properties": map[string]any{"owner": map[string]any{"type": "string"}},
+				}},
+				Tools: tools,
+			}),
+		},
+		{
+			name: "clean remote server",
+			server: lintTestServer(workingset.ServerTypeRemote, catalog.Server{
+				Name:    "linear",
+				Type:    "remote",
+				Secrets: []catalog.Secret{{Name: "linear.token", Env: "LINEAR_TOKEN"}},
+				Remote: catalog.Remote{
+					URL:     "https://linear.example.com/mcp",
+					Headers: map[string]string{"Authorization": "Bearer ${LINEAR_TOKEN}"},
+				},
+				Tools: tools,
+			}),
+		},
+		{
+			name:   "no tools",
+			server: lintTestServer(workingset.ServerTypeImage, catalog.Server{Name: "empty"}),
+			expected: []LintFinding{
+				{Server: "empty", Rule: LintRuleNoTools, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name: "no snapshot",
+			server: Server{
+				Type:  workingset.ServerTypeImage,
+				Image: "mcp/nosnapshot@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			expected: []LintFinding{
+				{Server: "mcp/nosnapshot@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", Rule: LintRuleNoTools, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name:   "implicit latest tag",
+			server: statsTestServer(workingset.ServerTypeImage, catalog.Server{Name: "fetch", Tools: tools}),
+			expected: []LintFinding{
+				{Server: "fetch", Rule: LintRuleMutableTag, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name: "explicit latest tag",
+			server: Server{
+				Type:     workingset.ServerTypeImage,
+				Image:    "mcp/fetch:latest",
+				Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch", Tools: tools}},
+			},
+			expected: []LintFinding{
+				{Server: "fetch", Rule: LintRuleMutableTag, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name: "versioned tag",
+			server: Server{
+				Type:     workingset.ServerTypeImage,
+				Image:    "mcp/fetch:1.2.0",
+				Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch", Tools: tools}},
+			},
+		},
+		{
+			name: "insecure remote",
+			server: Server{
+				Type:     workingset.ServerTypeRemote,
+				Endpoint: "http://insecure.example.com/mcp",
+				Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "insecure", Type: "remote", Tools: tools}},
+			},
+			expected: []LintFinding{
+				{Server: "insecure", Rule: LintRuleInsecureRemote, Severity: LintSeverityError},
+			},
+		},
+		{
+			name: "secret without env",
+			server: lintTestServer(workingset.ServerTypeImage, catalog.Server{
+				Name:    "unused",
+				Secrets: []catalog.Secret{{Name: "unused.token"}},
+				Tools:   tools,
+			}),
+			expected: []LintFinding{
+				{Server: "unused", Rule: LintRuleUnusedSecret, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name: "remote secret not in headers",
+			server: lintTestServer(workingset.ServerTypeRemote, catalog.Server{
+				Name:    "notion",
+				Type:    "remote",
+				Secrets: []catalog.Secret{{Name: "notion.token", Env: "NOTION_TOKEN"}, {Name: "notion.oauth", Env: "NOTION_OAUTH"}},
+				OAuth: &catalog.OAuth{
+					Providers: []catalog.OAuthProvider{{Provider: "notion", Secret: "notion.oauth", Env: "NOTION_OAUTH"}},
+				},
+				Remote: catalog.Remote{
+					URL:     "https://notion.example.com/mcp",
+					Headers: map[string]string{"Authorization": "Bearer $OTHER_TOKEN"},
+				},
+				Tools: tools,
+			}),
+			expected: []LintFinding{
+				{Server: "notion", Rule: LintRuleUnusedSecret, Severity: LintSeverityWarning},
+			},
+		},
+		{
+			name: "dangling placeholders",
+			server: lintTestServer(workingset.ServerTypeImage, catalog.Server{
+				Name: "dangling",
+				Env: []catalog.Env{
+					{Name: "PATHS", Value: "{{dangling.paths|volume|into}}"},
+					{Name: "MISSING_PROPERTY", Value: "{{dangling.missing}}"},
+					{Name: "MISSING_CONFIG", Value: "{{other.paths}}"},
+				},
+				Command: []string{"--paths={{ dangling.paths }}", "--again={{dangling.missing}}"},
+				Config: []any{map[string]any{
+					"name":       "dangling",
+					"properties": map[string]any{"paths": map[string]any{"type": "array"}},
+				}},
+				Tools: tools,
+			}),
+			expected: []LintFinding{
+				{Server: "dangling", Rule: LintRuleDanglingPlaceholder, Severity: LintSeverityError},
+				{Server: "dangling", Rule: LintRuleDanglingPlaceholder, Severity: LintSeverityError},
+			},
+		},
+		{
+			name: "placeholders of several config items",
+			server: lintTestServer(workingset.ServerTypeImage, catalog.Server{
+				Name: "db",
+				Env: []catalog.Env{
+					{Name: "HOST", Value: "{{db.host}}"},
+					{Name: "READONLY", Value: "{{db.readonly}}"},
+					{Name: "BY_ITEM_NAME", Value: "{{db.options.readonly}}"},
+					{Name: "ITEM", Value: "{{options.readonly}}"},
+				},
+				Config: []any{
+					map[string]any{
+						"name":       "db",
+						"properties": map[string]any{"host": map[string]any{"type": "string"}},
+					},
+					map[string]any{
+						"name":       "options",
+						"properties": map[string]any{"readonly": map[string]any{"type": "boolean"}},
+					},
+				},
+				Tools: tools,
+			}),
+			expected: []LintFinding{
+				{Server: "db", Rule: LintRuleDanglingPlaceholder, Severity: LintSeverityError},
+				{Server: "db", Rule: LintRuleDanglingPlaceholder, Severity: LintSeverityError},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := lintCatalog(Catalog{CatalogArtifact: CatalogArtifact{Servers: []Server{tc.server}}})
+
+			// Compare everything but the messages, checked separately.
+			for i := range findings {
+				assert.NotEmpty(t, findings[i].Message)
+				findings[i].Message = ""
+			}
+			if tc.expected == nil {
+				tc.expected = []LintFinding{}
+			}
+			assert.Equal(t, tc.expected, findings)
+		})
+	}
+}
+
+func TestLintMessages(t *testing.T) {
+	findings := lintCatalog(Catalog{CatalogArtifact: CatalogArtifact{Servers: []Server{
+		statsTestServer(workingset.ServerTypeImage, catalog.Server{
+			Name:    "fetch",
+			Secrets: []catalog.Secret{{Name: "fetch.token", Env: "FETCH_TOKEN"}},
+			Env:     []catalog.Env{{Name: "FETCH_URL", Value: "{{fetch.url}}"}},
+			Tools:   statsTestTools("fetch"),
+		}),
+	}}})
+
+	require.Len(t, findings, 2)
+	assert.Contains(t, findings[0].Message, "mcp/fetch")
+	assert.Contains(t, findings[0].Message, "digest")
+	assert.Contains(t, findings[1].Message, "{{fetch.url}}")
+}
+
+func TestLint(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	cat := Catalog{
+		Ref: "test/lint:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "lint",
+			Servers: []Server{
+				lintTestServer(workingset.ServerTypeImage, catalog.Server{Name: "clean", Tools: statsTestTools("search")}),
+				{
+					Type:     workingset.ServerTypeRemote,
+					Endpoint: "http://insecure.example.com/mcp",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "insecure", Type: "remote"}},
+				},
+			},
+		},
+	}
+	dbCat, err := cat.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	findings, err := Lint(ctx, dao, "test/lint")
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, LintRuleNoTools, findings[0].Rule)
+	assert.Equal(t, LintRuleInsecureRemote, findings[1].Rule)
+	assert.Equal(t, 1, LintErrors(findings))
+
+	output := captureStdout(t, func() {
+		require.NoError(t, PrintLintFindings(findings, workingset.OutputFormatJSON))
+	})
+	var printed []LintFinding
+	require.NoError(t, json.Unmarshal([]byte(output), &printed))
+	assert.Equal(t, findings, printed)
+
+	output = captureStdout(t, func() {
+		require.NoError(t, PrintLintFindings(findings, workingset.OutputFormatHumanReadable))
+	})
+	assert.Contains(t, output, "error\tinsecure\t")
+	assert.Contains(t, output, "1 error(s), 1 warning(s)")
+
+	output = captureStdout(t, func() {
+		require.NoError(t, PrintLintFindings(nil, workingset.OutputFormatHumanReadable))
+	})
+	assert.Contains(t, output, "No issues found")
+
+	_, err = Lint(ctx, dao, "test/missing:latest")
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestLintIncludesBaseServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	for _, cat := range []Catalog{
+		{Ref: "test/base:latest", CatalogArtifact: CatalogArtifact{Title: "base", Servers: []Server{
+			lintTestServer(workingset.ServerTypeImage, catalog.Server{Name: "notools"}),
+		}}},
+		{Ref: "test/derived:latest", CatalogArtifact: CatalogArtifact{Title: "derived", Base: "test/base:latest", Servers: []Server{
+			lintTestServer(workingset.ServerTypeImage, catalog.Server{Name: "clean", Tools: statsTestTools("search")}),
+		}}},
+	} {
+		dbCat, err := cat.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+
+	findings, err := Lint(ctx, dao, "test/derived:latest")
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "notools", findings[0].Server)
+	assert.Equal(t, LintRuleNoTools, findings[0].Rule)
+}
@@ -22,8 +22,7 @@ func List(ctx context.Context, dao db.DAO, format workingset.OutputFormat) error
 	}
 
 	if len(dbCatalogs) == 0 && format == workingset.OutputFormatHumanReadable {
-		fmt.Println("No catalogs found. Use `docker mcp catalog create` or `docker mcp catalog pull <oci-reference>` to create a catalog.")
-		return nil
+		return workingset.Output{}.Print([]byte("No catalogs found. Use `docker mcp catalog create` or `docker mcp catalog pull <oci-reference>` to create a catalog."))
 	}
 
 	summaries := make([]CatalogSummary, len(dbCatalogs))
@@ -72,9 +71,7 @@ func List(ctx context.Context, dao db.DAO, format workingset.OutputFormat) error
 		return fmt.Errorf("failed to marshal catalogs: %w", err)
 	}
 
-	fmt.Println(string(data))
-
-	return nil
+	return workingset.Output{}.Print(data)
 }
 
 func printListHumanReadable(catalogs []CatalogSummary, showPolicy bool) string {
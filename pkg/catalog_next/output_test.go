@@ -0,0 +1,137 @@
+package catalognext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestCommandsOutputEndWithSingleNewline(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	testCatalog := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/fetch:latest",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "fetch", Title: "Fetch", Description: "Fetches URLs"},
+					},
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/time:latest",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "time", Title: "Time"},
+					},
+				},
+			},
+		},
+	}
+	dbCatalog, err := testCatalog.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCatalog))
+	require.NoError(t, dao.SetCatalogAlias(ctx, db.CatalogAlias{Alias: "test", Ref: "test/catalog:latest"}))
+
+	commands := map[string]func(context.Context, workingset.OutputFormat) error{
+		"catalog ls": func(ctx context.Context, format workingset.OutputFormat) error {
+			return List(ctx, dao, format)
+		},
+		"catalog show": func(ctx context.Context, format workingset.OutputFormat) error {
+			return Show(ctx, dao, getMockOciService(), "test/catalog:latest", format, PullOptionNever, "")
+		},
+		"catalog stats": func(ctx context.Context, format workingset.OutputFormat) error {
+			return Stats(ctx, dao, "test/catalog:latest", format)
+		},
+		"catalog alias ls": func(ctx context.Context, format workingset.OutputFormat) error {
+			return ListAliases(ctx, dao, format)
+		},
+		"catalog server ls": func(ctx context.Context, format workingset.OutputFormat) error {
+			return ListServers(ctx, dao, "test/catalog:latest", nil, format, false, false)
+		},
+		"catalog server ls --all": func(ctx context.Context, format workingset.OutputFormat) error {
+			return ListAllServers(ctx, dao, nil, format, false, false)
+		},
+		"catalog server inspect": func(ctx context.Context, format workingset.OutputFormat) error {
+			return InspectServer(ctx, dao, "test/catalog:latest", "fetch", format, false)
+		},
+	}
+
+	formats := []workingset.OutputFormat{
+		workingset.OutputFormatHumanReadable,
+		workingset.OutputFormatJSON,
+		workingset.OutputFormatYAML,
+	}
+
+	for name, command := range commands {
+		for _, format := range formats {
+			t.Run(name+" "+string(format), func(t *testing.T) {
+				output := captureStdout(t, func() {
+					require.NoError(t, command(ctx, format))
+				})
+				require.NotEmpty(t, output)
+				assert.Regexp(t, `[^\n]\n$`, output)
+			})
+		}
+	}
+}
+
+func TestMutatingCommandsHonorQuiet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	dbCatalog, err := Catalog{
+		Ref:             "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{Title: "Test Catalog"},
+	}.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCatalog))
+
+	// The commands are run in order, once with each output, and only the
+	// quiet output prints nothing.
+	for _, quiet := range []bool{false, true} {
+		tag := fmt.Sprintf("test/catalog:quiet-%t", quiet)
+		commands := []struct {
+			name string
+			run  func(workingset.Output) error
+		}{
+			{"catalog tag", func(output workingset.Output) error {
+				return Tag(ctx, dao, "test/catalog:latest", tag, output)
+			}},
+			{"catalog alias set", func(output workingset.Output) error {
+				return SetAlias(ctx, dao, "test", tag, output)
+			}},
+			{"catalog alias rm", func(output workingset.Output) error {
+				return RemoveAlias(ctx, dao, "test", output)
+			}},
+			{"catalog rm", func(output workingset.Output) error {
+				return Remove(ctx, dao, tag, output)
+			}},
+		}
+
+		for _, command := range commands {
+			t.Run(fmt.Sprintf("%s quiet=%t", command.name, quiet), func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, command.run(workingset.Output{Quiet: quiet, Writer: &buf}))
+				if quiet {
+					assert.Empty(t, buf.String())
+				} else {
+					assert.Regexp(t, `^[^\n]+\n$`, buf.String())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// CatalogOverlay is an environment-specific patch of a catalog, e.g. for
+// staging or production. Servers maps the name of a server of the catalog to
+// the fields of its snapshot to override, in the same format as a server of a
+// legacy catalog. Objects are merged recursively, while lists and other values
+// are replaced.
+type CatalogOverlay struct {
+	Title   string                    `yaml:"title,omitempty" json:"title,omitempty"`
+	Servers map[string]map[string]any `yaml:"servers" json:"servers"`
+}
+
+// Overlay creates the catalog targetRef from the catalog baseRef, including
+// the servers it inherits, with the overlay file applied.
+func Overlay(ctx context.Context, dao db.DAO, baseRef, overlayPath, targetRef string, output workingset.Output) error {
+	baseRef, err := resolveCatalogRef(ctx, dao, baseRef)
+	if err != nil {
+		return err
+	}
+	targetRef, err = oci.NormalizeCatalogRef(targetRef)
+	if err != nil {
+		return err
+	}
+	if baseRef == targetRef {
+		return fmt.Errorf("cannot overlay catalog %s onto itself", baseRef)
+	}
+
+	buf, err := os.ReadFile(overlayPath)
+	if err != nil {
+		return fmt.Errorf("failed to read overlay: %w", err)
+	}
+	var overlay CatalogOverlay
+	if err := yaml.Unmarshal(buf, &overlay); err != nil {
+		return fmt.Errorf("failed to parse overlay %s: %w", overlayPath, err)
+	}
+
+	dbCatalog, err := dao.GetCatalog(ctx, baseRef)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("catalog %s not found", baseRef)
+		}
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return err
+	}
+
+	catalog := NewFromDb(dbCatalog).Catalog
+	if err := applyOverlay(&catalog, overlay); err != nil {
+		return err
+	}
+	catalog.Ref = targetRef
+	catalog.Source = SourcePrefixOverlay + baseRef
+	catalog.Base = ""
+
+	if err := catalog.Validate(); err != nil {
+		return fmt.Errorf("invalid catalog: %w", err)
+	}
+
+	dbCatalogOverlaid, err := catalog.ToDb()
+	if err != nil {
+		return fmt.Errorf("failed to convert catalog to db: %w", err)
+	}
+	if err := dao.UpsertCatalog(ctx, dbCatalogOverlaid); err != nil {
+		return fmt.Errorf("failed to create catalog: %w", err)
+	}
+
+	output.Printf("Catalog %s created from %s with overlay %s\n", targetRef, baseRef, overlayPath)
+	return nil
+}
+
+func applyOverlay(catalog *Catalog, overlay CatalogOverlay) error {
+	if overlay.Title != "" {
+		catalog.Title = overlay.Title
+	}
+
+	var missing []string
+	for name := range overlay.Servers {
+		if catalog.FindServer(name) == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("overlay servers not found in catalog %s: %s", catalog.Ref, strings.Join(missing, ", "))
+	}
+
+	for name, fields := range overlay.Servers {
+		server := catalog.FindServer(name)
+		patched, err := overlayServer(server.Snapshot.Server, fields)
+		if err != nil {
+			return fmt.Errorf("failed to apply overlay to server %s: %w", name, err)
+		}
+		if patched.Name != name {
+			return fmt.Errorf("overlay of server %s can't rename it", name)
+		}
+
+		server.Snapshot.Server = patched
+		switch server.Type {
+		case workingset.ServerTypeImage:
+			server.Image = patched.Image
+		case workingset.ServerTypeRemote:
+			server.Endpoint = patched.Remote.URL
+		}
+	}
+
+	return nil
+}
+
+// overlayServer returns the server with the fields of the overlay merged in.
+func overlayServer(server legacycatalog.Server, fields map[string]any) (legacycatalog.Server, error) {
+	buf, err := yaml.Marshal(server)
+	if err != nil {
+		return legacycatalog.Server{}, err
+	}
+	var merged map[string]any
+	if err := yaml.Unmarshal(buf, &merged); err != nil {
+		return legacycatalog.Server{}, err
+	}
+
+	mergeOverlay(merged, fields)
+
+	buf, err = yaml.Marshal(merged)
+	if err != nil {
+		return legacycatalog.Server{}, err
+	}
+	var patched legacycatalog.Server
+	if err := yaml.UnmarshalWithOptions(buf, &patched, yaml.Strict()); err != nil {
+		return legacycatalog.Server{}, err
+	}
+	return patched, nil
+}
+
+// mergeOverlay merges src into dst, recursively for objects.
+func mergeOverlay(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeOverlay(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,151 @@
+package catalognext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func setupOverlayTestCatalog(t *testing.T) db.DAO {
+	t.Helper()
+	dao := setupTestDB(t)
+
+	cat := Catalog{
+		Ref:    "test/team:latest",
+		Source: SourcePrefixUser + "cli",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Team",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "mcp/github:latest",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{
+						Name:  "github",
+						Type:  "server",
+						Image: "mcp/github:latest",
+						Env: []catalog.Env{
+							{Name: "LOG_LEVEL", Value: "debug"},
+						},
+						Labels: map[string]string{"team": "platform", "tier": "dev"},
+					}},
+				},
+				{
+					Type:     workingset.ServerTypeImage,
+					Image:    "mcp/fetch:latest",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch", Type: "server", Image: "mcp/fetch:latest"}},
+				},
+			},
+		},
+	}
+	dbCat, err := cat.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(t.Context(), dbCat))
+
+	return dao
+}
+
+func writeOverlay(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "overlay.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestOverlay(t *testing.T) {
+	dao := setupOverlayTestCatalog(t)
+	ctx := t.Context()
+
+	overlayPath := writeOverlay(t, `
+title: Team (production)
+servers:
+  github:
+    image: mcp/github:1.4.2
+    env:
+      - name: LOG_LEVEL
+        value: warn
+    labels:
+      tier: prod
+`)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, Overlay(ctx, dao, "test/team:latest", overlayPath, "test/team:prod", workingset.Output{}))
+	})
+	assert.Contains(t, output, "Catalog test/team:prod created from test/team:latest")
+
+	dbCatalog, err := dao.GetCatalog(ctx, "test/team:prod")
+	require.NoError(t, err)
+	overlaid := NewFromDb(dbCatalog)
+	assert.Equal(t, "Team (production)", overlaid.Title)
+	assert.Equal(t, SourcePrefixOverlay+"test/team:latest", overlaid.Source)
+	require.Len(t, overlaid.Servers, 2)
+
+	github := overlaid.FindServer("github")
+	require.NotNil(t, github)
+	assert.Equal(t, "mcp/github:1.4.2", github.Image)
+	assert.Equal(t, "mcp/github:1.4.2", github.Snapshot.Server.Image)
+	assert.Equal(t, []catalog.Env{{Name: "LOG_LEVEL", Value: "warn"}}, github.Snapshot.Server.Env)
+	// Objects are merged.
+	assert.Equal(t, map[string]string{"team": "platform", "tier": "prod"}, github.Snapshot.Server.Labels)
+
+	// Servers without overrides are unchanged.
+	fetch := overlaid.FindServer("fetch")
+	require.NotNil(t, fetch)
+	assert.Equal(t, "mcp/fetch:latest", fetch.Image)
+
+	// The base catalog is unchanged.
+	dbBase, err := dao.GetCatalog(ctx, "test/team:latest")
+	require.NoError(t, err)
+	base := NewFromDb(dbBase)
+	assert.Equal(t, "mcp/github:latest", base.FindServer("github").Image)
+	assert.Equal(t, "debug", base.FindServer("github").Snapshot.Server.Env[0].Value)
+}
+
+func TestOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlay  string
+		expected string
+	}{
+		{
+			name:     "unknown server",
+			overlay:  "servers:\n  slack:\n    image: mcp/slack\n  notion:\n    image: mcp/notion\n",
+			expected: "overlay servers not found in catalog test/team:latest: notion, slack",
+		},
+		{
+			name:     "unknown field",
+			overlay:  "servers:\n  github:\n    imag: mcp/github:1.4.2\n",
+			expected: "failed to apply overlay to server github",
+		},
+		{
+			name:     "rename",
+			overlay:  "servers:\n  github:\n    name: gh\n",
+			expected: "overlay of server github can't rename it",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dao := setupOverlayTestCatalog(t)
+
+			err := Overlay(t.Context(), dao, "test/team:latest", writeOverlay(t, tc.overlay), "test/team:prod", workingset.Output{})
+			require.ErrorContains(t, err, tc.expected)
+
+			_, err = dao.GetCatalog(t.Context(), "test/team:prod")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestOverlayOntoItself(t *testing.T) {
+	dao := setupOverlayTestCatalog(t)
+
+	err := Overlay(t.Context(), dao, "test/team:latest", writeOverlay(t, "servers: {}\n"), "test/team", workingset.Output{})
+	require.ErrorContains(t, err, "cannot overlay catalog test/team:latest onto itself")
+}
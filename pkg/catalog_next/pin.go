@@ -0,0 +1,119 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// PinnedImage is an image server of a catalog rewritten by PinImages.
+type PinnedImage struct {
+	Server string `yaml:"server" json:"server"`
+	From   string `yaml:"from" json:"from"`
+	To     string `yaml:"to" json:"to"`
+}
+
+// PinImages resolves the tag of every image server of a catalog, e.g. latest,
+// to the digest it currently points to in the registry and rewrites the
+// catalog to reference that digest. Images that are already pinned are left
+// unchanged. The images of the servers inherited from a base catalog are
+// pinned too, by overriding these servers. With dryRun, the catalog is not updated. It returns the images
+// that were, or would be, pinned.
+func PinImages(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, dryRun bool) ([]PinnedImage, error) {
+	if err := requireOCICatalogRef(refStr, "pin"); err != nil {
+		return nil, err
+	}
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCatalog, err := dao.GetCatalog(ctx, refStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("catalog %s not found", refStr)
+		}
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	ownServers := map[string]bool{}
+	for _, server := range NewFromDb(dbCatalog).Servers {
+		ownServers[serverDisplayName(server)] = true
+	}
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return nil, err
+	}
+	catalog := NewFromDb(dbCatalog).Catalog
+
+	pinned := []PinnedImage{}
+	for i := range catalog.Servers {
+		server := &catalog.Servers[i]
+		if server.Type != workingset.ServerTypeImage {
+			continue
+		}
+
+		pinnedRef, err := pinImage(ctx, ociService, server.Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pin image of server %s: %w", serverDisplayName(*server), err)
+		}
+		if pinnedRef == server.Image {
+			continue
+		}
+
+		pinned = append(pinned, PinnedImage{Server: serverDisplayName(*server), From: server.Image, To: pinnedRef})
+		server.Image = pinnedRef
+		if server.Snapshot != nil {
+			server.Snapshot.Server.Image = pinnedRef
+		}
+	}
+
+	if dryRun || len(pinned) == 0 {
+		return pinned, nil
+	}
+
+	// Inherited servers are only written to the catalog once pinned, as
+	// overrides of the servers of its base.
+	catalog.Servers = slices.DeleteFunc(catalog.Servers, func(server Server) bool {
+		name := serverDisplayName(server)
+		return !ownServers[name] && !slices.ContainsFunc(pinned, func(p PinnedImage) bool { return p.Server == name })
+	})
+
+	dbCatalogUpdated, err := catalog.ToDb()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert catalog to database format: %w", err)
+	}
+	if err := dao.UpsertCatalog(ctx, dbCatalogUpdated); err != nil {
+		return nil, fmt.Errorf("failed to update catalog: %w", err)
+	}
+
+	return pinned, nil
+}
+
+// pinImage returns the image reference with the digest its tag points to in
+// the registry appended, or the reference unchanged if it already has one.
+// Multi-platform images are pinned to their index so they still run on every
+// platform.
+func pinImage(ctx context.Context, ociService oci.Service, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse reference: %w", err)
+	}
+	if oci.HasDigest(ref) {
+		return image, nil
+	}
+
+	digest, err := ociService.GetRemoteDigest(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote digest: %w", err)
+	}
+
+	return fmt.Sprintf("%s@%s", ref.String(), digest), nil
+}
@@ -0,0 +1,171 @@
+package catalognext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+const (
+	pinTestFetchDigest  = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	pinTestGithubDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func setupPinTestCatalog(t *testing.T) (db.DAO, Catalog) {
+	t.Helper()
+	dao := setupTestDB(t)
+
+	cat := Catalog{
+		Ref: "test/pin:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "pin",
+			Servers: []Server{
+				{
+					Type:     workingset.ServerTypeImage,
+					Image:    "mcp/fetch:latest",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch", Image: "mcp/fetch:latest"}},
+				},
+				{
+					Type:     workingset.ServerTypeImage,
+					Image:    "mcp/github@" + pinTestGithubDigest,
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github", Image: "mcp/github@" + pinTestGithubDigest}},
+				},
+				{
+					Type:     workingset.ServerTypeRemote,
+					Endpoint: "https://notion.example.com/mcp",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "notion", Type: "remote"}},
+				},
+			},
+		},
+	}
+	dbCat, err := cat.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(t.Context(), dbCat))
+
+	return dao, cat
+}
+
+var pinTestFetchImage = mocks.MockImage{Ref: "mcp/fetch:latest", DigestString: pinTestFetchDigest}
+
+func TestPinImages(t *testing.T) {
+	dao, cat := setupPinTestCatalog(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+	ociService := mocks.NewMockOCIService(mocks.WithRemoteImages([]mocks.MockImage{pinTestFetchImage}))
+
+	pinned, err := PinImages(ctx, dao, ociService, cat.Ref, false)
+	require.NoError(t, err)
+	assert.Equal(t, []PinnedImage{{
+		Server: "fetch",
+		From:   "mcp/fetch:latest",
+		To:     "mcp/fetch:latest@" + pinTestFetchDigest,
+	}}, pinned)
+
+	dbCatalog, err := dao.GetCatalog(ctx, cat.Ref)
+	require.NoError(t, err)
+	updated := NewFromDb(dbCatalog)
+	require.Len(t, updated.Servers, 3)
+	assert.Equal(t, "mcp/fetch:latest@"+pinTestFetchDigest, updated.Servers[0].Image)
+	assert.Equal(t, "mcp/fetch:latest@"+pinTestFetchDigest, updated.Servers[0].Snapshot.Server.Image)
+	assert.Equal(t, "mcp/github@"+pinTestGithubDigest, updated.Servers[1].Image)
+	assert.Equal(t, "https://notion.example.com/mcp", updated.Servers[2].Endpoint)
+
+	// Once pinned, there is nothing left to pin.
+	pinned, err = PinImages(ctx, dao, ociService, cat.Ref, false)
+	require.NoError(t, err)
+	assert.Empty(t, pinned)
+}
+
+func TestPinImagesDryRun(t *testing.T) {
+	dao, cat := setupPinTestCatalog(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+	ociService := mocks.NewMockOCIService(mocks.WithRemoteImages([]mocks.MockImage{pinTestFetchImage}))
+
+	before, err := dao.GetCatalog(ctx, cat.Ref)
+	require.NoError(t, err)
+
+	pinned, err := PinImages(ctx, dao, ociService, cat.Ref, true)
+	require.NoError(t, err)
+	require.Len(t, pinned, 1)
+	assert.Equal(t, "mcp/fetch:latest@"+pinTestFetchDigest, pinned[0].To)
+
+	after, err := dao.GetCatalog(ctx, cat.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, before.Digest, after.Digest)
+	assert.Equal(t, "mcp/fetch:latest", NewFromDb(after).Servers[0].Image)
+}
+
+func TestPinImagesErrors(t *testing.T) {
+	dao, cat := setupPinTestCatalog(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	_, err := PinImages(ctx, dao, mocks.NewMockOCIService(), cat.Ref, false)
+	require.ErrorContains(t, err, "failed to pin image of server fetch")
+
+	dbCatalog, err := dao.GetCatalog(ctx, cat.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, "mcp/fetch:latest", NewFromDb(dbCatalog).Servers[0].Image)
+
+	_, err = PinImages(ctx, dao, mocks.NewMockOCIService(), "test/missing:latest", false)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestPinImagesMultiPlatformIndex(t *testing.T) {
+	dao, cat := setupPinTestCatalog(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+	indexDigest := "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	ociService := mocks.NewMockOCIService(mocks.WithRemoteImages([]mocks.MockImage{
+		{Ref: "mcp/fetch:latest", DigestString: pinTestFetchDigest, IndexDigest: indexDigest},
+	}))
+
+	pinned, err := PinImages(ctx, dao, ociService, cat.Ref, false)
+	require.NoError(t, err)
+	require.Len(t, pinned, 1)
+	assert.Equal(t, "mcp/fetch:latest@"+indexDigest, pinned[0].To)
+}
+
+func TestPinImagesOfBaseServers(t *testing.T) {
+	dao, base := setupPinTestCatalog(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+	ociService := mocks.NewMockOCIService(mocks.WithRemoteImages([]mocks.MockImage{pinTestFetchImage}))
+
+	derived := Catalog{
+		Ref: "test/derived:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "derived",
+			Base:  base.Ref,
+			Servers: []Server{{
+				Type:     workingset.ServerTypeImage,
+				Image:    "mcp/time@" + pinTestGithubDigest,
+				Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "time", Image: "mcp/time@" + pinTestGithubDigest}},
+			}},
+		},
+	}
+	dbCat, err := derived.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	pinned, err := PinImages(ctx, dao, ociService, derived.Ref, false)
+	require.NoError(t, err)
+	assert.Equal(t, []PinnedImage{{Server: "fetch", From: "mcp/fetch:latest", To: "mcp/fetch:latest@" + pinTestFetchDigest}}, pinned)
+
+	// The pinned server overrides the one of the base, the other inherited
+	// servers are left to the base.
+	dbCatalog, err := dao.GetCatalog(ctx, derived.Ref)
+	require.NoError(t, err)
+	updated := NewFromDb(dbCatalog)
+	assert.Equal(t, base.Ref, updated.Base)
+	require.Len(t, updated.Servers, 2)
+	assert.Equal(t, "time", updated.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, "mcp/fetch:latest@"+pinTestFetchDigest, updated.Servers[1].Image)
+
+	dbBase, err := dao.GetCatalog(ctx, base.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, "mcp/fetch:latest", NewFromDb(dbBase).Servers[0].Image)
+}
@@ -0,0 +1,58 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// Preload imports a legacy catalog file into the database, unless a catalog
+// with the same reference is already there. The reference is the name of the
+// catalog in the file, or the file name without its extension, e.g.
+// "my-catalog:latest". It returns the reference and whether the catalog was
+// imported.
+//
+// The catalog is created with Create, quietly, so that Preload can run before
+// the gateway serves on stdio.
+func Preload(ctx context.Context, dao db.DAO, path string) (string, bool, error) {
+	legacyCatalog, catalogName, _, err := legacycatalog.ReadOne(ctx, path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to preload catalog %s: %w", path, err)
+	}
+	if len(legacyCatalog.Servers) == 0 {
+		return "", false, fmt.Errorf("failed to preload catalog %s: no servers found", path)
+	}
+
+	if catalogName == "" {
+		catalogName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	ref, err := oci.NormalizeCatalogRef(catalogName)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to preload catalog %s: %q is not a valid catalog reference: %w", path, catalogName, err)
+	}
+
+	_, err = dao.GetCatalog(ctx, ref)
+	if err == nil {
+		return ref, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", false, fmt.Errorf("failed to get catalog %s: %w", ref, err)
+	}
+
+	if err := Create(ctx, dao, nil, nil, ref, CreateOptions{
+		LegacyCatalogURL: path,
+		Output:           workingset.Output{Quiet: true},
+	}); err != nil {
+		return "", false, fmt.Errorf("failed to preload catalog %s: %w", path, err)
+	}
+
+	return ref, true, nil
+}
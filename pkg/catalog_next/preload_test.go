@@ -0,0 +1,88 @@
+package catalognext
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const preloadCatalogYAML = `name: baked-catalog
+displayName: Baked Catalog
+registry:
+  server1:
+    title: "Test Server 1"
+    type: "server"
+    image: "docker/test-server:latest"
+  server2:
+    type: "remote"
+    remote:
+      url: "https://example.com/mcp"
+      transport_type: "streamable-http"
+`
+
+func TestPreloadPopulatesEmptyDatabase(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogFile := trustedLegacyCatalogPath(t)
+	require.NoError(t, os.WriteFile(catalogFile, []byte(preloadCatalogYAML), 0o644))
+
+	var (
+		ref      string
+		imported bool
+		err      error
+	)
+	output := captureStdout(t, func() {
+		ref, imported, err = Preload(ctx, dao, catalogFile)
+	})
+	require.NoError(t, err)
+	assert.True(t, imported)
+	assert.Equal(t, "baked-catalog:latest", ref)
+	assert.Empty(t, output, "stdout is reserved for the stdio transport")
+
+	dbCatalog, err := dao.GetCatalog(ctx, ref)
+	require.NoError(t, err)
+	catalog := NewFromDb(dbCatalog)
+	assert.Equal(t, "Baked Catalog", catalog.Title)
+	assert.Equal(t, "legacy-catalog:baked-catalog", catalog.Source)
+	require.Len(t, catalog.Servers, 2)
+	assert.Equal(t, "server1", catalog.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, "server2", catalog.Servers[1].Snapshot.Server.Name)
+}
+
+func TestPreloadIsNoopWhenCatalogPresent(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogFile := trustedLegacyCatalogPath(t)
+	require.NoError(t, os.WriteFile(catalogFile, []byte(preloadCatalogYAML), 0o644))
+
+	_, imported, err := Preload(ctx, dao, catalogFile)
+	require.NoError(t, err)
+	require.True(t, imported)
+
+	// A catalog that was pulled or edited since must not be overwritten.
+	dbCatalog, err := dao.GetCatalog(ctx, "baked-catalog:latest")
+	require.NoError(t, err)
+	dbCatalog.Title = "Edited"
+	require.NoError(t, dao.UpsertCatalog(ctx, *dbCatalog))
+
+	ref, imported, err := Preload(ctx, dao, catalogFile)
+	require.NoError(t, err)
+	assert.False(t, imported)
+	assert.Equal(t, "baked-catalog:latest", ref)
+
+	catalogs, err := dao.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assert.Equal(t, "Edited", catalogs[0].Title)
+}
+
+func TestPreloadMissingFile(t *testing.T) {
+	dao := setupTestDB(t)
+
+	_, _, err := Preload(t.Context(), dao, trustedLegacyCatalogPath(t))
+	require.ErrorContains(t, err, "no servers found")
+}
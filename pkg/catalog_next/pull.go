@@ -2,6 +2,8 @@ package catalognext
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -14,7 +16,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string) error {
+func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, output workingset.Output) error {
 	telemetry.Init()
 	start := time.Now()
 	var success bool
@@ -22,12 +24,19 @@ func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 		duration := time.Since(start)
 		telemetry.RecordCatalogOperation(ctx, "pull", refStr, float64(duration.Milliseconds()), success)
 	}()
+	refStr, err := db.ResolveCatalogAlias(ctx, dao, refStr)
+	if err != nil {
+		return err
+	}
+	if err := requireOCICatalogRef(refStr, "pull"); err != nil {
+		return err
+	}
 	catalog, err := pullCatalog(ctx, dao, ociService, refStr)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Catalog %s pulled\n", catalog.Ref)
+	output.Printf("Catalog %s pulled\n", catalog.Ref)
 
 	if catalog.Ref == CommunityRegistryCatalogRef {
 		fmt.Fprintf(os.Stderr, "\n⚠️ Community Registry servers are not vetted by Docker.\n")
@@ -75,6 +84,7 @@ func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr
 	if err := catalog.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid catalog: %w", err)
 	}
+	_ = catalog.CheckSecretLikeConfig(false)
 
 	dbCatalog, err := catalog.ToDb()
 	if err != nil {
@@ -91,5 +101,18 @@ func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr
 		return nil, fmt.Errorf("failed to record pull record: %w", err)
 	}
 
+	// Pull the base catalog too, unless it was pulled already. The catalog is
+	// stored first, so a cycle of base catalogs doesn't pull forever.
+	if dbCatalog.Base != "" {
+		_, err := dao.GetCatalog(ctx, dbCatalog.Base)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := pullCatalog(ctx, dao, ociService, dbCatalog.Base); err != nil {
+				return nil, fmt.Errorf("failed to pull base catalog %s: %w", dbCatalog.Base, err)
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get base catalog %s: %w", dbCatalog.Base, err)
+		}
+	}
+
 	return &dbCatalog, nil
 }
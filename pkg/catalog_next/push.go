@@ -12,9 +12,10 @@ import (
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
+	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func Push(ctx context.Context, dao db.DAO, refStr string) error {
+func Push(ctx context.Context, dao db.DAO, refStr string, output workingset.Output) error {
 	telemetry.Init()
 	start := time.Now()
 	var success bool
@@ -22,17 +23,18 @@ func Push(ctx context.Context, dao db.DAO, refStr string) error {
 		duration := time.Since(start)
 		telemetry.RecordCatalogOperation(ctx, "push", refStr, float64(duration.Milliseconds()), success)
 	}()
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
+	if err != nil {
+		return err
+	}
+	if err := requireOCICatalogRef(refStr, "push"); err != nil {
+		return err
+	}
 	ref, err := name.ParseReference(refStr)
 	if err != nil {
 		return fmt.Errorf("failed to parse reference: %w", err)
 	}
 
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference must be a valid OCI reference without a digest")
-	}
-
-	refStr = oci.FullNameWithoutDigest(ref)
-
 	dbCatalog, err := dao.GetCatalog(ctx, refStr)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -48,7 +50,7 @@ func Push(ctx context.Context, dao db.DAO, refStr string) error {
 		return fmt.Errorf("failed to push catalog artifact: %w", err)
 	}
 
-	fmt.Printf("Pushed catalog to %s@sha256:%s\n", oci.FullName(ref), hash)
+	output.Printf("Pushed catalog to %s@sha256:%s\n", oci.FullName(ref), hash)
 
 	success = true
 	return nil
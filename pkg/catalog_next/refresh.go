@@ -0,0 +1,70 @@
+package catalognext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// RefreshDue evaluates the pull option against every catalog that was pulled
+// from an OCI registry and pulls the ones that are due. It returns the refs of
+// the catalogs that were refreshed. A failure to refresh one catalog does not
+// prevent the others from being refreshed; all failures are returned together.
+func RefreshDue(ctx context.Context, dao db.DAO, ociService oci.Service, pullOptionParam string) ([]string, error) {
+	dbCatalogs, err := dao.ListCatalogs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+
+	var refreshed []string
+	var errs []error
+	for i := range dbCatalogs {
+		dbCatalog := &dbCatalogs[i]
+		if !strings.HasPrefix(dbCatalog.Source, SourcePrefixOCI) {
+			// Catalogs created locally have nothing to be refreshed from.
+			continue
+		}
+
+		pulledPreviously, err := dao.CheckPullRecord(ctx, dbCatalog.Ref)
+		if err != nil {
+			return refreshed, fmt.Errorf("failed to check pull record: %w", err)
+		}
+		pullOptionEvaluator, err := NewPullOptionEvaluator(pullOptionParam, pulledPreviously)
+		if err != nil {
+			return refreshed, err
+		}
+		if !pullOptionEvaluator.Evaluate(dbCatalog) {
+			continue
+		}
+
+		if err := refreshCatalog(ctx, dao, ociService, dbCatalog.Ref); err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh catalog %s: %w", dbCatalog.Ref, err))
+			continue
+		}
+		refreshed = append(refreshed, dbCatalog.Ref)
+	}
+
+	return refreshed, errors.Join(errs...)
+}
+
+func refreshCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string) error {
+	start := time.Now()
+	var success bool
+	defer func() {
+		duration := time.Since(start)
+		telemetry.RecordCatalogOperation(ctx, "scheduled-refresh", refStr, float64(duration.Milliseconds()), success)
+	}()
+
+	if _, err := pullCatalog(ctx, dao, ociService, refStr); err != nil {
+		return err
+	}
+
+	success = true
+	return nil
+}
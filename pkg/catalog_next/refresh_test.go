@@ -0,0 +1,64 @@
+package catalognext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestRefreshDueSkipsCatalogsNotDue(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	for _, catalogObj := range []Catalog{
+		{
+			// Just pulled, not due for a refresh yet
+			Ref:    "test/pulled:latest",
+			Source: SourcePrefixOCI + "test/pulled:latest",
+		},
+		{
+			// Not pulled from a registry, never refreshed
+			Ref:    "test/local:latest",
+			Source: SourcePrefixWorkingSet + "my-profile",
+		},
+	} {
+		catalogObj.CatalogArtifact = CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:     workingset.ServerTypeImage,
+					Image:    "docker/server1:v1",
+					Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "my-server"}},
+				},
+			},
+		}
+		dbCat, err := catalogObj.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+
+	refreshed, err := RefreshDue(ctx, dao, nil, "exists@1h")
+	require.NoError(t, err)
+	assert.Empty(t, refreshed)
+}
+
+func TestRefreshDueInvalidPullOption(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogObj := Catalog{
+		Ref:             "test/pulled:latest",
+		Source:          SourcePrefixOCI + "test/pulled:latest",
+		CatalogArtifact: CatalogArtifact{Title: "Test Catalog"},
+	}
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	_, err = RefreshDue(ctx, dao, nil, "sometimes")
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package catalognext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+)
+
+// DefaultRegistryCachePull is the pull option of a cached registry listing
+// when none is given: it's downloaded again after an hour.
+const DefaultRegistryCachePull = "1h"
+
+// registryListing is the listing of the servers of a community registry, as
+// cached on disk. The registry is recorded without its credentials.
+type registryListing struct {
+	Registry  string              `json:"registry"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+	Servers   []v0.ServerResponse `json:"servers"`
+}
+
+// listRegistryServers lists the servers of a community registry. With a
+// cacheDir, the listing is read from the cache unless the pull option says
+// it must be downloaded again, e.g. because it's older than its interval, and
+// a downloaded listing is written to the cache. A cache that can't be read is
+// downloaded again, one that can't be written only logs a warning.
+func listRegistryServers(ctx context.Context, registryClient registryapi.Client, baseURL string, registryRef string, cacheDir string, pullOption string) ([]v0.ServerResponse, error) {
+	if cacheDir == "" {
+		return registryClient.ListServers(ctx, baseURL, "")
+	}
+
+	evaluator, err := NewPullOptionEvaluator(pullOption, false)
+	if err != nil {
+		return nil, err
+	}
+
+	path := registryListingPath(cacheDir, registryRef)
+	cached, found := readRegistryListing(path, registryRef)
+	var fetchedAt *time.Time
+	if found {
+		fetchedAt = &cached.FetchedAt
+	}
+	if !evaluator.evaluate(found, fetchedAt) {
+		if !found {
+			return nil, fmt.Errorf("the servers of %s are not cached in %s, and the pull option %q doesn't download them", registryRef, cacheDir, pullOption)
+		}
+		return cached.Servers, nil
+	}
+
+	servers, err := registryClient.ListServers(ctx, baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeRegistryListing(path, registryListing{
+		Registry:  registryRef,
+		FetchedAt: time.Now(),
+		Servers:   servers,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache the servers of %s: %v\n", registryRef, err)
+	}
+	return servers, nil
+}
+
+func registryListingPath(cacheDir string, registryRef string) string {
+	sum := sha256.Sum256([]byte(registryRef))
+	return filepath.Join(cacheDir, "registry-"+hex.EncodeToString(sum[:])+".json")
+}
+
+// readRegistryListing reads a cached listing. Unreadable listings, or the
+// listings of another registry, are not found.
+func readRegistryListing(path string, registryRef string) (registryListing, bool) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return registryListing{}, false
+	}
+	var listing registryListing
+	if err := json.Unmarshal(buf, &listing); err != nil || listing.Registry != registryRef {
+		return registryListing{}, false
+	}
+	return listing, true
+}
+
+// writeRegistryListing writes a listing to a temporary file first, so that
+// concurrent builds never read a partial listing.
+func writeRegistryListing(path string, listing registryListing) error {
+	buf, err := json.Marshal(listing)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -7,14 +7,12 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/name"
-
 	"github.com/docker/mcp-gateway/pkg/db"
-	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
+	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func Remove(ctx context.Context, dao db.DAO, refStr string) error {
+func Remove(ctx context.Context, dao db.DAO, refStr string, output workingset.Output) error {
 	telemetry.Init()
 	start := time.Now()
 	var success bool
@@ -22,13 +20,11 @@ func Remove(ctx context.Context, dao db.DAO, refStr string) error {
 		duration := time.Since(start)
 		telemetry.RecordCatalogOperation(ctx, "remove", refStr, float64(duration.Milliseconds()), success)
 	}()
-	ref, err := name.ParseReference(refStr)
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
+		return err
 	}
 
-	refStr = oci.FullNameWithoutDigest(ref)
-
 	_, err = dao.GetCatalog(ctx, refStr)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -42,7 +38,7 @@ func Remove(ctx context.Context, dao db.DAO, refStr string) error {
 		return fmt.Errorf("failed to remove catalog: %w", err)
 	}
 
-	fmt.Printf("Removed catalog %s\n", refStr)
+	output.Printf("Removed catalog %s\n", refStr)
 	success = true
 	return nil
 }
@@ -38,7 +38,7 @@ func TestRemove(t *testing.T) {
 
 	// Remove it
 	output := captureStdout(t, func() {
-		err := Remove(ctx, dao, catalog.Ref)
+		err := Remove(ctx, dao, catalog.Ref, workingset.Output{})
 		require.NoError(t, err)
 	})
 
@@ -54,7 +54,7 @@ func TestRemoveNotFound(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
-	err := Remove(ctx, dao, "test/nonexistent:latest")
+	err := Remove(ctx, dao, "test/nonexistent:latest", workingset.Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "catalog test/nonexistent:latest not found")
 }
@@ -102,7 +102,7 @@ func TestRemoveMultipleCatalogs(t *testing.T) {
 
 	// Remove one
 	output := captureStdout(t, func() {
-		err := Remove(ctx, dao, catalogs[1].Ref)
+		err := Remove(ctx, dao, catalogs[1].Ref, workingset.Output{})
 		require.NoError(t, err)
 	})
 
@@ -149,7 +149,7 @@ func TestRemoveOutputFormat(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := Remove(ctx, dao, catalog.Ref)
+		err := Remove(ctx, dao, catalog.Ref, workingset.Output{})
 		require.NoError(t, err)
 	})
 
@@ -2,14 +2,14 @@ package catalognext
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"sort"
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/goccy/go-yaml"
-	"github.com/google/go-containerregistry/pkg/name"
 
+	legacycatalog "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/fetch"
 	"github.com/docker/mcp-gateway/pkg/oci"
@@ -21,7 +21,39 @@ import (
 
 type InspectResult struct {
 	Server        `yaml:",inline"`
+	ID            string `json:"id,omitempty" yaml:"id,omitempty"`
+	Icon          string `json:"icon,omitempty" yaml:"icon,omitempty"`
 	ReadmeContent string `json:"readmeContent,omitempty" yaml:"readmeContent,omitempty"`
+	// Capabilities summarizes what the server advertises, when known.
+	Capabilities *legacycatalog.Capabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+}
+
+// ServerListEntry is a catalog server as rendered by ListServers.
+type ServerListEntry struct {
+	Server `yaml:",inline"`
+	ID     string `json:"id,omitempty" yaml:"id,omitempty"`
+	Icon   string `json:"icon,omitempty" yaml:"icon,omitempty"`
+}
+
+// CatalogServerListEntry is a server as rendered by ListAllServers, with the
+// catalog it belongs to.
+type CatalogServerListEntry struct {
+	ServerListEntry `yaml:",inline"`
+	Catalog         string `json:"catalog" yaml:"catalog"`
+	CatalogSource   string `json:"catalogSource,omitempty" yaml:"catalogSource,omitempty"`
+}
+
+// ServerIcon returns the icon URL of a catalog server. Icons that are not
+// absolute http(s) URLs are dropped so that clients can render them safely.
+func ServerIcon(server Server) string {
+	if server.Snapshot == nil || server.Snapshot.Server.Icon == "" {
+		return ""
+	}
+	u, err := url.Parse(server.Snapshot.Server.Icon)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
 }
 
 type serverFilter struct {
@@ -29,36 +61,62 @@ type serverFilter struct {
 	value string
 }
 
-func InspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string, format workingset.OutputFormat) error {
-	return inspectServer(ctx, dao, catalogRef, serverName, format, fetch.Untrusted)
+// InspectServer prints a server of a catalog. compact prints JSON on a single
+// line.
+func InspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string, format workingset.OutputFormat, compact bool) error {
+	return inspectServer(ctx, dao, catalogRef, serverName, format, compact, fetch.Untrusted)
 }
 
-func inspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string, format workingset.OutputFormat, fetchReadme func(context.Context, string) ([]byte, error)) error {
-	ref, err := name.ParseReference(catalogRef)
+// GetServer returns the definition of a server of a catalog, as snapshotted
+// when it was added to the catalog.
+func GetServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string) (legacycatalog.Server, error) {
+	server, err := findServer(ctx, dao, catalogRef, serverName)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", catalogRef, err)
-	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference %s must be a valid OCI reference without a digest", catalogRef)
+		return legacycatalog.Server{}, err
 	}
+	return server.Snapshot.Server, nil
+}
 
-	catalogRef = oci.FullNameWithoutDigest(ref)
+// findServer finds a server, with a snapshot, in a catalog and its base.
+func findServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string) (*Server, error) {
+	catalogRef, err := resolveCatalogRef(ctx, dao, catalogRef)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get the catalog
 	dbCatalog, err := dao.GetCatalog(ctx, catalogRef)
 	if err != nil {
-		return fmt.Errorf("failed to get catalog %s: %w", catalogRef, err)
+		return nil, fmt.Errorf("failed to get catalog %s: %w", catalogRef, err)
+	}
+
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return nil, err
 	}
 
 	catalog := NewFromDb(dbCatalog)
 
 	server := catalog.FindServer(serverName)
 	if server == nil {
-		return fmt.Errorf("server %s not found in catalog %s", serverName, catalogRef)
+		return nil, fmt.Errorf("server %s not found in catalog %s", serverName, catalogRef)
+	}
+	return server, nil
+}
+
+func inspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverName string, format workingset.OutputFormat, compact bool, fetchReadme func(context.Context, string) ([]byte, error)) error {
+	server, err := findServer(ctx, dao, catalogRef, serverName)
+	if err != nil {
+		return err
 	}
 
 	inspectResult := InspectResult{
 		Server: *server,
+		ID:     server.StableID(),
+		Icon:   ServerIcon(*server),
+	}
+	if server.Snapshot != nil {
+		inspectResult.Capabilities = server.Snapshot.Server.Capabilities
 	}
 
 	if server.Snapshot != nil && server.Snapshot.Server.ReadmeURL != "" {
@@ -73,7 +131,7 @@ func inspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverNam
 
 	switch format {
 	case workingset.OutputFormatJSON:
-		data, err = json.MarshalIndent(inspectResult, "", "  ")
+		data, err = workingset.MarshalJSON(inspectResult, compact)
 	case workingset.OutputFormatYAML, workingset.OutputFormatHumanReadable:
 		data, err = yaml.Marshal(inspectResult)
 	default:
@@ -84,56 +142,158 @@ func inspectServer(ctx context.Context, dao db.DAO, catalogRef string, serverNam
 		return fmt.Errorf("failed to marshal server: %w", err)
 	}
 
-	fmt.Println(string(data))
-
-	return nil
+	return workingset.Output{}.Print(data)
 }
 
-// ListServers lists servers in a catalog with optional filtering
-func ListServers(ctx context.Context, dao db.DAO, catalogRef string, filters []string, format workingset.OutputFormat) error {
+// ListServers lists servers in a catalog with optional filtering. compact
+// prints JSON on a single line. The servers without a snapshot are only listed
+// in JSON and YAML with includeUnsnapshotted, which also lets name filters
+// match them by image, source or endpoint.
+func ListServers(ctx context.Context, dao db.DAO, catalogRef string, filters []string, format workingset.OutputFormat, compact bool, includeUnsnapshotted bool) error {
 	parsedFilters, err := parseFilters(filters)
 	if err != nil {
 		return err
 	}
 
-	ref, err := name.ParseReference(catalogRef)
+	catalogRef, err = resolveCatalogRef(ctx, dao, catalogRef)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", catalogRef, err)
-	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference %s must be a valid OCI reference without a digest", catalogRef)
+		return err
 	}
 
-	catalogRef = oci.FullNameWithoutDigest(ref)
-
 	// Get the catalog
 	dbCatalog, err := dao.GetCatalog(ctx, catalogRef)
 	if err != nil {
 		return fmt.Errorf("failed to get catalog %s: %w", catalogRef, err)
 	}
 
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return err
+	}
+
 	catalog := NewFromDb(dbCatalog)
 
 	policyClient := policycli.ClientForCLI(ctx)
 	showPolicy := policyClient != nil
 	attachCatalogPolicy(ctx, policyClient, catalog.Ref, &catalog, true)
 
-	// Apply name filter
-	var nameFilter string
-	for _, filter := range parsedFilters {
-		switch filter.key {
-		case "name":
-			nameFilter = filter.value
-		default:
-			return fmt.Errorf("unsupported filter key: %s", filter.key)
-		}
+	nameFilter, err := nameFilterOf(parsedFilters)
+	if err != nil {
+		return err
+	}
+
+	if !includeUnsnapshotted && format != workingset.OutputFormatHumanReadable {
+		catalog.Servers = slices.DeleteFunc(catalog.Servers, func(server Server) bool {
+			return snapshotName(server) == ""
+		})
 	}
 
 	// Filter servers
-	servers := filterServers(catalog.Servers, nameFilter)
+	servers := filterServers(catalog.Servers, nameFilter, includeUnsnapshotted)
 
 	// Output results
-	return outputServers(catalog.Ref, catalog.Title, catalog.Policy, servers, format, showPolicy)
+	return outputServers(catalog.Ref, catalog.Title, catalog.Policy, servers, serverListFilters{
+		Applied: appliedFilters(parsedFilters),
+		Total:   len(catalog.Servers),
+	}, format, compact, showPolicy)
+}
+
+// serverListFilters tells the consumers of the JSON and YAML outputs of
+// ListServers whether an empty list comes from an empty catalog or from
+// filters that excluded every server.
+type serverListFilters struct {
+	// Applied maps the keys of the filters to their values.
+	Applied map[string]string
+	// Total is the number of servers before filtering.
+	Total int
+}
+
+func appliedFilters(filters []serverFilter) map[string]string {
+	applied := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		applied[filter.key] = filter.value
+	}
+	return applied
+}
+
+// ListAllServers lists the servers of every catalog, each with the ref and
+// source of its catalog, sorted by server name then catalog. It takes the same
+// filters and options as ListServers. The servers a catalog inherits from its
+// base are listed under the base catalog only.
+func ListAllServers(ctx context.Context, dao db.DAO, filters []string, format workingset.OutputFormat, compact bool, includeUnsnapshotted bool) error {
+	parsedFilters, err := parseFilters(filters)
+	if err != nil {
+		return err
+	}
+	nameFilter, err := nameFilterOf(parsedFilters)
+	if err != nil {
+		return err
+	}
+
+	dbCatalogs, err := dao.ListCatalogs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list catalogs: %w", err)
+	}
+
+	var entries []CatalogServerListEntry
+	for _, dbCatalog := range dbCatalogs {
+		catalog := NewFromDb(&dbCatalog)
+		for _, server := range filterServers(catalog.Servers, nameFilter, includeUnsnapshotted) {
+			if server.Type == "" {
+				// Catalogs without servers are listed with an empty one.
+				continue
+			}
+			if !includeUnsnapshotted && format != workingset.OutputFormatHumanReadable && snapshotName(server) == "" {
+				continue
+			}
+			entries = append(entries, CatalogServerListEntry{
+				ServerListEntry: ServerListEntry{Server: server, ID: server.StableID(), Icon: ServerIcon(server)},
+				Catalog:         catalog.Ref,
+				CatalogSource:   catalog.Source,
+			})
+		}
+	}
+
+	// Sort servers like ListServers, then the same server by catalog
+	slices.SortStableFunc(entries, func(a, b CatalogServerListEntry) int {
+		if c := compareServers(a.Server, b.Server); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Catalog, b.Catalog)
+	})
+
+	var data []byte
+	switch format {
+	case workingset.OutputFormatHumanReadable:
+		return workingset.Output{}.Print([]byte(printAllServersHuman(entries)))
+	case workingset.OutputFormatJSON:
+		data, err = workingset.MarshalJSON(map[string]any{"servers": entries}, compact)
+	case workingset.OutputFormatYAML:
+		data, err = yaml.Marshal(map[string]any{"servers": entries})
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format servers: %w", err)
+	}
+
+	return workingset.Output{}.Print(data)
+}
+
+func printAllServersHuman(entries []CatalogServerListEntry) string {
+	if len(entries) == 0 {
+		return "No servers found"
+	}
+
+	lines := ""
+	for _, entry := range entries {
+		name := snapshotName(entry.Server)
+		if name == "" {
+			name = entry.BasicName() + " (no snapshot)"
+		}
+		lines += fmt.Sprintf("%s\t| %s\t| %s\n", name, entry.Catalog, entry.CatalogSource)
+	}
+	return fmt.Sprintf("Name | Catalog | Source\n%s", strings.TrimSuffix(lines, "\n"))
 }
 
 func parseFilters(filters []string) ([]serverFilter, error) {
@@ -151,7 +311,21 @@ func parseFilters(filters []string) ([]serverFilter, error) {
 	return parsed, nil
 }
 
-func filterServers(servers []Server, nameFilter string) []Server {
+// nameFilterOf returns the value of the name filter, the only supported one.
+func nameFilterOf(filters []serverFilter) (string, error) {
+	var nameFilter string
+	for _, filter := range filters {
+		switch filter.key {
+		case "name":
+			nameFilter = filter.value
+		default:
+			return "", fmt.Errorf("unsupported filter key: %s", filter.key)
+		}
+	}
+	return nameFilter, nil
+}
+
+func filterServers(servers []Server, nameFilter string, includeUnsnapshotted bool) []Server {
 	if nameFilter == "" {
 		return servers
 	}
@@ -160,7 +334,7 @@ func filterServers(servers []Server, nameFilter string) []Server {
 	filtered := make([]Server, 0)
 
 	for _, server := range servers {
-		if matchesNameFilter(server, nameLower) {
+		if matchesNameFilter(server, nameLower, includeUnsnapshotted) {
 			filtered = append(filtered, server)
 		}
 	}
@@ -168,45 +342,72 @@ func filterServers(servers []Server, nameFilter string) []Server {
 	return filtered
 }
 
-func matchesNameFilter(server Server, nameLower string) bool {
-	if server.Snapshot == nil {
-		return false
+func matchesNameFilter(server Server, nameLower string, includeUnsnapshotted bool) bool {
+	serverName := snapshotName(server)
+	if serverName == "" {
+		if !includeUnsnapshotted {
+			return false
+		}
+		serverName = server.BasicName()
 	}
-	serverName := strings.ToLower(server.Snapshot.Server.Name)
-	return strings.Contains(serverName, nameLower)
+	return strings.Contains(strings.ToLower(serverName), nameLower)
 }
 
-func outputServers(catalogRef, catalogTitle string, catalogPolicy *policy.Decision, servers []Server, format workingset.OutputFormat, showPolicy bool) error {
-	// Sort servers by name
-	sort.Slice(servers, func(i, j int) bool {
-		if servers[i].Snapshot == nil || servers[j].Snapshot == nil {
-			return false
+// compareServers orders servers by name, then the servers without a snapshot,
+// which have no name, by image, source or endpoint.
+func compareServers(a, b Server) int {
+	nameA, nameB := snapshotName(a), snapshotName(b)
+	if (nameA == "") != (nameB == "") {
+		if nameA != "" {
+			return -1
 		}
-		return servers[i].Snapshot.Server.Name < servers[j].Snapshot.Server.Name
-	})
+		return 1
+	}
+	if c := strings.Compare(nameA, nameB); c != 0 {
+		return c
+	}
+	return strings.Compare(a.BasicName(), b.BasicName())
+}
+
+func outputServers(catalogRef, catalogTitle string, catalogPolicy *policy.Decision, servers []Server, filters serverListFilters, format workingset.OutputFormat, compact bool, showPolicy bool) error {
+	slices.SortStableFunc(servers, compareServers)
 
 	var data []byte
 	var err error
 
+	entries := make([]ServerListEntry, len(servers))
+	for i, server := range servers {
+		entries[i] = ServerListEntry{
+			Server: server,
+			ID:     server.StableID(),
+			Icon:   ServerIcon(server),
+		}
+	}
+
 	switch format {
 	case workingset.OutputFormatHumanReadable:
-		printServersHuman(catalogRef, catalogTitle, catalogPolicy, servers, showPolicy)
-		return nil
+		return workingset.Output{}.Print([]byte(printServersHuman(catalogRef, catalogTitle, catalogPolicy, servers, showPolicy)))
 	case workingset.OutputFormatJSON:
 		output := map[string]any{
-			"catalog": catalogRef,
-			"title":   catalogTitle,
-			"servers": servers,
+			"catalog":  catalogRef,
+			"title":    catalogTitle,
+			"servers":  entries,
+			"filtered": len(filters.Applied) > 0,
+			"filters":  filters.Applied,
+			"total":    filters.Total,
 		}
 		if showPolicy && catalogPolicy != nil {
 			output["policy"] = catalogPolicy
 		}
-		data, err = json.MarshalIndent(output, "", "  ")
+		data, err = workingset.MarshalJSON(output, compact)
 	case workingset.OutputFormatYAML:
 		output := map[string]any{
-			"catalog": catalogRef,
-			"title":   catalogTitle,
-			"servers": servers,
+			"catalog":  catalogRef,
+			"title":    catalogTitle,
+			"servers":  entries,
+			"filtered": len(filters.Applied) > 0,
+			"filters":  filters.Applied,
+			"total":    filters.Total,
 		}
 		if showPolicy && catalogPolicy != nil {
 			output["policy"] = catalogPolicy
@@ -220,70 +421,87 @@ func outputServers(catalogRef, catalogTitle string, catalogPolicy *policy.Decisi
 		return fmt.Errorf("failed to format servers: %w", err)
 	}
 
-	fmt.Println(string(data))
-	return nil
+	return workingset.Output{}.Print(data)
+}
+
+// snapshotName returns the name of a server, or an empty string if it has no
+// snapshot. Servers stored without a snapshot are read back with an empty one.
+func snapshotName(server Server) string {
+	if server.Snapshot == nil {
+		return ""
+	}
+	return server.Snapshot.Server.Name
 }
 
-func printServersHuman(catalogRef, catalogTitle string, catalogPolicy *policy.Decision, servers []Server, showPolicy bool) {
+func printServersHuman(catalogRef, catalogTitle string, catalogPolicy *policy.Decision, servers []Server, showPolicy bool) string {
 	if len(servers) == 0 {
-		fmt.Println("No servers found")
-		return
+		return "No servers found"
 	}
 
-	fmt.Printf("Catalog: %s\n", catalogRef)
-	fmt.Printf("Title: %s\n", catalogTitle)
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Catalog: %s\n", catalogRef)
+	fmt.Fprintf(&sb, "Title: %s\n", catalogTitle)
 	if showPolicy {
-		fmt.Printf("Policy: %s\n", policycli.StatusMessage(catalogPolicy))
+		fmt.Fprintf(&sb, "Policy: %s\n", policycli.StatusMessage(catalogPolicy))
 	}
-	fmt.Printf("Servers (%d):\n\n", len(servers))
+	fmt.Fprintf(&sb, "Servers (%d):\n\n", len(servers))
 
 	for _, server := range servers {
-		if server.Snapshot == nil {
-			continue
+		var srv legacycatalog.Server
+		if name := snapshotName(server); name != "" {
+			srv = server.Snapshot.Server
+			fmt.Fprintf(&sb, "  %s\n", name)
+		} else {
+			fmt.Fprintf(&sb, "  %s (no snapshot)\n", server.BasicName())
 		}
-		srv := server.Snapshot.Server
-		fmt.Printf("  %s\n", srv.Name)
 		if srv.Title != "" {
-			fmt.Printf("    Title: %s\n", srv.Title)
+			fmt.Fprintf(&sb, "    Title: %s\n", srv.Title)
 		}
 		if srv.Description != "" {
-			fmt.Printf("    Description: %s\n", srv.Description)
+			fmt.Fprintf(&sb, "    Description: %s\n", srv.Description)
+		}
+		if id := server.StableID(); id != "" {
+			fmt.Fprintf(&sb, "    ID: %s\n", id)
 		}
-		fmt.Printf("    Type: %s\n", server.Type)
+		fmt.Fprintf(&sb, "    Type: %s\n", server.Type)
 		switch server.Type {
 		case workingset.ServerTypeImage:
-			fmt.Printf("    Image: %s\n", server.Image)
+			fmt.Fprintf(&sb, "    Image: %s\n", server.Image)
 		case workingset.ServerTypeRegistry:
-			fmt.Printf("    Source: %s\n", server.Source)
+			fmt.Fprintf(&sb, "    Source: %s\n", server.Source)
 		case workingset.ServerTypeRemote:
-			fmt.Printf("    Endpoint: %s\n", server.Endpoint)
+			fmt.Fprintf(&sb, "    Endpoint: %s\n", server.Endpoint)
 		}
 		if showPolicy {
-			fmt.Printf("    Policy: %s\n", policycli.StatusMessage(server.Policy))
+			fmt.Fprintf(&sb, "    Policy: %s\n", policycli.StatusMessage(server.Policy))
 		}
 		if len(srv.Tools) > 0 {
-			fmt.Printf("    Tools: %d\n", allowedToolCount(srv.Tools))
+			fmt.Fprintf(&sb, "    Tools: %d\n", allowedToolCount(srv.Tools))
 		}
-		fmt.Println()
+		sb.WriteString("\n")
 	}
+	return sb.String()
+}
+
+// AddServersResult is the JSON result of AddServers.
+type AddServersResult struct {
+	Catalog  string   `json:"catalog"`
+	Added    []string `json:"added"`
+	Replaced []string `json:"replaced,omitempty"`
 }
 
 // AddServers adds servers to a catalog using various URI schemes
-func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, catalogRef string, serverRefs []string) error {
+func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, catalogRef string, serverRefs []string, output workingset.Output) error {
 	if len(serverRefs) == 0 {
 		return fmt.Errorf("at least one server must be specified")
 	}
 
-	ref, err := name.ParseReference(catalogRef)
+	catalogRef, err := resolveCatalogRef(ctx, dao, catalogRef)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", catalogRef, err)
-	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference %s must be a valid OCI reference without a digest", catalogRef)
+		return err
 	}
 
-	catalogRef = oci.FullNameWithoutDigest(ref)
-
 	// Get the catalog
 	dbCatalog, err := dao.GetCatalog(ctx, catalogRef)
 	if err != nil {
@@ -292,14 +510,19 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 
 	catalog := NewFromDb(dbCatalog)
 
-	// Resolve all server references
+	// Resolve all server references, remembering which one each server was
+	// resolved from
 	allServers := make([]workingset.Server, 0)
+	addedFrom := make([]string, 0)
 	for _, serverRef := range serverRefs {
 		servers, err := workingset.ResolveServersFromString(ctx, registryClient, ociService, dao, serverRef)
 		if err != nil {
 			return fmt.Errorf("failed to resolve server reference %q: %w", serverRef, err)
 		}
 		allServers = append(allServers, servers...)
+		for range servers {
+			addedFrom = append(addedFrom, serverRef)
+		}
 	}
 
 	if len(allServers) == 0 {
@@ -315,12 +538,12 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 	}
 
 	// Remove existing servers that will be replaced (upsert)
-	replacedCount := 0
+	var replaced []string
 	filtered := make([]Server, 0, len(catalog.Servers))
 	for _, existing := range catalog.Servers {
 		if existing.Snapshot != nil && newServerNames[existing.Snapshot.Server.Name] {
-			fmt.Printf("Replaced server %s in catalog %s\n", existing.Snapshot.Server.Name, catalogRef)
-			replacedCount++
+			output.Printf("Replaced server %s in catalog %s\n", existing.Snapshot.Server.Name, catalogRef)
+			replaced = append(replaced, existing.Snapshot.Server.Name)
 		} else {
 			filtered = append(filtered, existing)
 		}
@@ -328,15 +551,17 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 	catalog.Servers = filtered
 
 	// Convert workingset.Server to catalog Server and append
-	addedCount := 0
-	for _, wsServer := range allServers {
+	added := make([]string, 0, len(allServers))
+	for i, wsServer := range allServers {
 		if wsServer.Snapshot == nil {
 			continue
 		}
 
 		catalogServer := Server{
-			Type:     wsServer.Type,
-			Snapshot: wsServer.Snapshot,
+			Type:              wsServer.Type,
+			LongLivedOverride: wsServer.LongLivedOverride,
+			AddedFrom:         addedFrom[i],
+			Snapshot:          wsServer.Snapshot,
 		}
 
 		switch wsServer.Type {
@@ -349,7 +574,7 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 		}
 
 		catalog.Servers = append(catalog.Servers, catalogServer)
-		addedCount++
+		added = append(added, serverDisplayName(catalogServer))
 	}
 
 	// Save the updated catalog
@@ -362,30 +587,32 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 		return fmt.Errorf("failed to update catalog: %w", err)
 	}
 
-	if replacedCount > 0 {
-		fmt.Printf("Added %d server(s) to catalog '%s' (replaced %d)\n", addedCount, catalogRef, replacedCount)
+	if len(replaced) > 0 {
+		output.Printf("Added %d server(s) to catalog '%s' (replaced %d)\n", len(added), catalogRef, len(replaced))
 	} else {
-		fmt.Printf("Added %d server(s) to catalog '%s'\n", addedCount, catalogRef)
+		output.Printf("Added %d server(s) to catalog '%s'\n", len(added), catalogRef)
 	}
-	return nil
+	return output.Result(AddServersResult{
+		Catalog:  catalogRef,
+		Added:    added,
+		Replaced: replaced,
+	})
 }
 
-// RemoveServers removes servers from a catalog by name
-func RemoveServers(ctx context.Context, dao db.DAO, catalogRef string, serverNames []string) error {
+// RemoveServers removes servers from a catalog by name. Names that don't
+// match any server are reported as warnings, unless strict is set, in which
+// case the catalog is left untouched and an error is returned. It errors when
+// no name matches.
+func RemoveServers(ctx context.Context, dao db.DAO, catalogRef string, serverNames []string, strict bool, output workingset.Output) error {
 	if len(serverNames) == 0 {
 		return fmt.Errorf("at least one server name must be specified")
 	}
 
-	ref, err := name.ParseReference(catalogRef)
+	catalogRef, err := resolveCatalogRef(ctx, dao, catalogRef)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", catalogRef, err)
-	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference %s must be a valid OCI reference without a digest", catalogRef)
+		return err
 	}
 
-	catalogRef = oci.FullNameWithoutDigest(ref)
-
 	// Get the catalog
 	dbCatalog, err := dao.GetCatalog(ctx, catalogRef)
 	if err != nil {
@@ -402,10 +629,20 @@ func RemoveServers(ctx context.Context, dao db.DAO, catalogRef string, serverNam
 
 	// Filter out servers to remove
 	originalCount := len(catalog.Servers)
+	matched := make(map[string]bool)
 	filtered := make([]Server, 0, len(catalog.Servers))
 	for _, server := range catalog.Servers {
 		if server.Snapshot == nil || !namesToRemove[server.Snapshot.Server.Name] {
 			filtered = append(filtered, server)
+		} else {
+			matched[server.Snapshot.Server.Name] = true
+		}
+	}
+
+	var unmatched []string
+	for _, name := range serverNames {
+		if !matched[name] && !slices.Contains(unmatched, name) {
+			unmatched = append(unmatched, name)
 		}
 	}
 
@@ -413,6 +650,9 @@ func RemoveServers(ctx context.Context, dao db.DAO, catalogRef string, serverNam
 	if removedCount == 0 {
 		return fmt.Errorf("no matching servers found to remove")
 	}
+	if strict && len(unmatched) > 0 {
+		return fmt.Errorf("server(s) not found in catalog %s: %s", catalogRef, strings.Join(unmatched, ", "))
+	}
 
 	catalog.Servers = filtered
 
@@ -426,6 +666,66 @@ func RemoveServers(ctx context.Context, dao db.DAO, catalogRef string, serverNam
 		return fmt.Errorf("failed to update catalog: %w", err)
 	}
 
-	fmt.Printf("Removed %d server(s) from catalog '%s'\n", removedCount, catalogRef)
+	if len(unmatched) > 0 {
+		output.Printf("Warning: server(s) not found in catalog '%s': %s\n", catalogRef, strings.Join(unmatched, ", "))
+	}
+	output.Printf("Removed %d server(s) from catalog '%s'\n", removedCount, catalogRef)
+	return nil
+}
+
+// MoveServer moves a server, with its snapshot, from one catalog to another.
+// Both catalogs are updated in a single transaction. It errors when the
+// server isn't in the source catalog or is already in the destination one.
+func MoveServer(ctx context.Context, dao db.DAO, srcRef string, dstRef string, serverName string, output workingset.Output) error {
+	srcRef, err := resolveCatalogRef(ctx, dao, srcRef)
+	if err != nil {
+		return err
+	}
+	dstRef, err = resolveCatalogRef(ctx, dao, dstRef)
+	if err != nil {
+		return err
+	}
+	if srcRef == dstRef {
+		return fmt.Errorf("source and destination catalogs are the same: %s", srcRef)
+	}
+
+	dbSrc, err := dao.GetCatalog(ctx, srcRef)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog %s: %w", srcRef, err)
+	}
+	dbDst, err := dao.GetCatalog(ctx, dstRef)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog %s: %w", dstRef, err)
+	}
+	src := NewFromDb(dbSrc)
+	dst := NewFromDb(dbDst)
+
+	server := src.FindServer(serverName)
+	if server == nil {
+		return fmt.Errorf("server %s not found in catalog %s", serverName, srcRef)
+	}
+	if dst.FindServer(serverName) != nil {
+		return fmt.Errorf("server %s already exists in catalog %s", serverName, dstRef)
+	}
+
+	moved := *server
+	src.Servers = slices.DeleteFunc(src.Servers, func(s Server) bool {
+		return s.Snapshot != nil && s.Snapshot.Server.Name == serverName
+	})
+	dst.Servers = append(dst.Servers, moved)
+
+	dbSrcUpdated, err := src.ToDb()
+	if err != nil {
+		return fmt.Errorf("failed to convert catalog to database format: %w", err)
+	}
+	dbDstUpdated, err := dst.ToDb()
+	if err != nil {
+		return fmt.Errorf("failed to convert catalog to database format: %w", err)
+	}
+	if err := dao.UpsertCatalogs(ctx, dbSrcUpdated, dbDstUpdated); err != nil {
+		return fmt.Errorf("failed to update catalogs: %w", err)
+	}
+
+	output.Printf("Moved server %s from catalog '%s' to catalog '%s'\n", serverName, srcRef, dstRef)
 	return nil
 }
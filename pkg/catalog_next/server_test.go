@@ -3,13 +3,17 @@ package catalognext
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/goccy/go-yaml"
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/desktop"
 	"github.com/docker/mcp-gateway/pkg/workingset"
 	"github.com/docker/mcp-gateway/test/mocks"
@@ -56,7 +60,7 @@ func TestInspectServer(t *testing.T) {
 
 	t.Run("JSON format", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON, false)
 			require.NoError(t, err)
 		})
 
@@ -70,7 +74,7 @@ func TestInspectServer(t *testing.T) {
 
 	t.Run("YAML format", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatYAML)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatYAML, false)
 			require.NoError(t, err)
 		})
 
@@ -84,7 +88,7 @@ func TestInspectServer(t *testing.T) {
 
 	t.Run("HumanReadable format (uses YAML)", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatHumanReadable)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatHumanReadable, false)
 			require.NoError(t, err)
 		})
 
@@ -130,7 +134,7 @@ func TestInspectServerWithReadme(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := inspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON, func(_ context.Context, url string) ([]byte, error) {
+		err := inspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON, false, func(_ context.Context, url string) ([]byte, error) {
 			assert.Equal(t, readmeURL, url)
 			return []byte(readmeContent), nil
 		})
@@ -173,16 +177,52 @@ func TestInspectServerNotFound(t *testing.T) {
 	err = dao.UpsertCatalog(ctx, dbCat)
 	require.NoError(t, err)
 
-	err = InspectServer(ctx, dao, catalogObj.Ref, "nonexistent-server", workingset.OutputFormatJSON)
+	err = InspectServer(ctx, dao, catalogObj.Ref, "nonexistent-server", workingset.OutputFormatJSON, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "server nonexistent-server not found in catalog test/catalog:latest")
 }
 
+func TestGetServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/server1:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name:  "my-server",
+							Image: "docker/server1:v1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	server, err := GetServer(ctx, dao, catalogObj.Ref, "my-server")
+	require.NoError(t, err)
+	assert.Equal(t, "my-server", server.Name)
+	assert.Equal(t, "docker/server1:v1", server.Image)
+
+	_, err = GetServer(ctx, dao, catalogObj.Ref, "nonexistent-server")
+	require.EqualError(t, err, "server nonexistent-server not found in catalog test/catalog:latest")
+}
+
 func TestInspectServerCatalogNotFound(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
-	err := InspectServer(ctx, dao, "test/nonexistent:latest", "some-server", workingset.OutputFormatJSON)
+	err := InspectServer(ctx, dao, "test/nonexistent:latest", "some-server", workingset.OutputFormatJSON, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get catalog")
 }
@@ -215,7 +255,7 @@ func TestInspectServerUnsupportedFormat(t *testing.T) {
 	err = dao.UpsertCatalog(ctx, dbCat)
 	require.NoError(t, err)
 
-	err = InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormat("unsupported"))
+	err = InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormat("unsupported"), false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported format: unsupported")
 }
@@ -224,7 +264,7 @@ func TestInspectServerInvalidCatalogRef(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
-	err := InspectServer(ctx, dao, ":::invalid-ref", "some-server", workingset.OutputFormatJSON)
+	err := InspectServer(ctx, dao, ":::invalid-ref", "some-server", workingset.OutputFormatJSON, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse oci-reference")
 }
@@ -283,7 +323,7 @@ func TestInspectServerDifferentServerTypes(t *testing.T) {
 
 	t.Run("inspect image server", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "image-server", workingset.OutputFormatJSON)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "image-server", workingset.OutputFormatJSON, false)
 			require.NoError(t, err)
 		})
 
@@ -298,7 +338,7 @@ func TestInspectServerDifferentServerTypes(t *testing.T) {
 
 	t.Run("inspect remote server", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "remote-server", workingset.OutputFormatJSON)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "remote-server", workingset.OutputFormatJSON, false)
 			require.NoError(t, err)
 		})
 
@@ -313,7 +353,7 @@ func TestInspectServerDifferentServerTypes(t *testing.T) {
 
 	t.Run("inspect registry server", func(t *testing.T) {
 		output := captureStdout(t, func() {
-			err := InspectServer(ctx, dao, catalogObj.Ref, "registry-server", workingset.OutputFormatJSON)
+			err := InspectServer(ctx, dao, catalogObj.Ref, "registry-server", workingset.OutputFormatJSON, false)
 			require.NoError(t, err)
 		})
 
@@ -367,7 +407,7 @@ func TestListServersNoFilters(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -420,7 +460,7 @@ func TestListServersFilterByName(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=my"}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=my"}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -461,7 +501,7 @@ func TestListServersFilterByNameCaseInsensitive(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=myserver"}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=myserver"}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -502,7 +542,7 @@ func TestListServersFilterByNamePartialMatch(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=awesome"}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=awesome"}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -543,7 +583,7 @@ func TestListServersFilterNoMatches(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=nonexistent"}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=nonexistent"}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -555,6 +595,76 @@ func TestListServersFilterNoMatches(t *testing.T) {
 	assert.Empty(t, servers)
 }
 
+func TestListServersDistinguishesEmptyCatalogFromFilteredOut(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	for _, catalogObj := range []Catalog{
+		{
+			Ref:             "test/empty:latest",
+			CatalogArtifact: CatalogArtifact{Title: "Empty Catalog"},
+		},
+		{
+			Ref: "test/catalog:latest",
+			CatalogArtifact: CatalogArtifact{
+				Title: "Test Catalog",
+				Servers: []Server{
+					{
+						Type:  workingset.ServerTypeImage,
+						Image: "docker/server1:v1",
+						Snapshot: &workingset.ServerSnapshot{
+							Server: catalog.Server{Name: "my-server"},
+						},
+					},
+				},
+			},
+		},
+	} {
+		dbCat, err := catalogObj.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+
+	listServers := func(catalogRef string, filters []string) map[string]any {
+		output := captureStdout(t, func() {
+			err := ListServers(ctx, dao, catalogRef, filters, workingset.OutputFormatJSON, false, false)
+			require.NoError(t, err)
+		})
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		return result
+	}
+
+	// The catalog has no servers
+	result := listServers("test/empty:latest", nil)
+	assert.Empty(t, result["servers"])
+	assert.Equal(t, false, result["filtered"])
+	assert.Equal(t, map[string]any{}, result["filters"])
+	assert.InDelta(t, 0, result["total"], 0)
+
+	// The filters excluded every server
+	result = listServers("test/catalog:latest", []string{"name=nonexistent"})
+	assert.Empty(t, result["servers"])
+	assert.Equal(t, true, result["filtered"])
+	assert.Equal(t, map[string]any{"name": "nonexistent"}, result["filters"])
+	assert.InDelta(t, 1, result["total"], 0)
+
+	// The filters matched
+	result = listServers("test/catalog:latest", []string{"name=my"})
+	assert.Len(t, result["servers"], 1)
+	assert.Equal(t, true, result["filtered"])
+	assert.InDelta(t, 1, result["total"], 0)
+
+	// YAML has the same fields
+	output := captureStdout(t, func() {
+		err := ListServers(ctx, dao, "test/catalog:latest", []string{"name=nonexistent"}, workingset.OutputFormatYAML, false, false)
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "filtered: true")
+	assert.Contains(t, output, "name: nonexistent")
+	assert.Contains(t, output, "total: 1")
+}
+
 func TestListServersWithoutSnapshot(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
@@ -579,7 +689,7 @@ func TestListServersWithoutSnapshot(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=test"}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=test"}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -614,7 +724,7 @@ func TestListServersInvalidFilter(t *testing.T) {
 	err = dao.UpsertCatalog(ctx, dbCat)
 	require.NoError(t, err)
 
-	err = ListServers(ctx, dao, catalogObj.Ref, []string{"invalid"}, workingset.OutputFormatJSON)
+	err = ListServers(ctx, dao, catalogObj.Ref, []string{"invalid"}, workingset.OutputFormatJSON, false, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid filter format")
 }
@@ -641,7 +751,7 @@ func TestListServersUnsupportedFilterKey(t *testing.T) {
 	err = dao.UpsertCatalog(ctx, dbCat)
 	require.NoError(t, err)
 
-	err = ListServers(ctx, dao, catalogObj.Ref, []string{"unsupported=value"}, workingset.OutputFormatJSON)
+	err = ListServers(ctx, dao, catalogObj.Ref, []string{"unsupported=value"}, workingset.OutputFormatJSON, false, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported filter key")
 }
@@ -650,7 +760,7 @@ func TestListServersCatalogNotFound(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
 
-	err := ListServers(ctx, dao, "test/nonexistent:latest", []string{}, workingset.OutputFormatJSON)
+	err := ListServers(ctx, dao, "test/nonexistent:latest", []string{}, workingset.OutputFormatJSON, false, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get catalog")
 }
@@ -686,7 +796,7 @@ func TestListServersNormalizesCatalogRef(t *testing.T) {
 	// Query with a non-normalized reference (without :latest tag)
 	// This should still find the catalog because the code normalizes the ref
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, "test/catalog", []string{}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, "test/catalog", []string{}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -726,7 +836,7 @@ func TestListServersYAMLFormat(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatYAML)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatYAML, false, false)
 		require.NoError(t, err)
 	})
 
@@ -738,6 +848,169 @@ func TestListServersYAMLFormat(t *testing.T) {
 	assert.Equal(t, catalogObj.Title, result["title"])
 }
 
+func TestListServersCompactJSON(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/server1:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name:        "server-one",
+							Description: "First server",
+						},
+					},
+				},
+				{
+					Type:     workingset.ServerTypeRemote,
+					Endpoint: "https://example.com/mcp",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name: "server-two",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	err = dao.UpsertCatalog(ctx, dbCat)
+	require.NoError(t, err)
+
+	indented := captureStdout(t, func() {
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON, false, false)
+		require.NoError(t, err)
+	})
+	compact := captureStdout(t, func() {
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON, true, false)
+		require.NoError(t, err)
+	})
+
+	assert.Greater(t, strings.Count(indented, "\n"), 1)
+	assert.NotContains(t, strings.TrimSuffix(compact, "\n"), "\n")
+	assert.JSONEq(t, indented, compact)
+}
+
+func TestInspectServerCapabilities(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	ociService := mocks.NewMockOCIService(mocks.WithLocalImages([]mocks.MockImage{
+		{
+			Ref: "toolsonly:latest",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: tools-only\ntools:\n  - name: search\n",
+			},
+			DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		},
+	}))
+	err := Create(ctx, dao, getMockRegistryClient(), ociService, "test/catalog", CreateOptions{
+		Servers: []string{"docker://toolsonly:latest"},
+		Title:   "Test Catalog",
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		err := InspectServer(ctx, dao, "test/catalog:latest", "tools-only", workingset.OutputFormatJSON, false)
+		require.NoError(t, err)
+	})
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, map[string]any{
+		"tools":             true,
+		"prompts":           false,
+		"resources":         false,
+		"resourceTemplates": false,
+		"sampling":          false,
+	}, result["capabilities"])
+}
+
+func TestInspectServerAnnotations(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	testCatalog := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:        workingset.ServerTypeImage,
+					Image:       "docker/test:latest",
+					Annotations: map[string]string{"owner": "team-a", "ticket": "MCP-42"},
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "test-server"},
+					},
+				},
+			},
+		},
+	}
+	dbCatalog, err := testCatalog.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCatalog))
+
+	output := captureStdout(t, func() {
+		err := InspectServer(ctx, dao, "test/catalog:latest", "test-server", workingset.OutputFormatJSON, false)
+		require.NoError(t, err)
+	})
+
+	var result InspectResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, map[string]string{"owner": "team-a", "ticket": "MCP-42"}, result.Annotations)
+}
+
+func TestInspectServerCompactJSON(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/server1:v1",
+					Tools: []string{"tool1", "tool2"},
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name:        "my-server",
+							Description: "My test server",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	err = dao.UpsertCatalog(ctx, dbCat)
+	require.NoError(t, err)
+
+	indented := captureStdout(t, func() {
+		err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON, false)
+		require.NoError(t, err)
+	})
+	compact := captureStdout(t, func() {
+		err := InspectServer(ctx, dao, catalogObj.Ref, "my-server", workingset.OutputFormatJSON, true)
+		require.NoError(t, err)
+	})
+
+	assert.Greater(t, strings.Count(indented, "\n"), 1)
+	assert.NotContains(t, strings.TrimSuffix(compact, "\n"), "\n")
+	assert.JSONEq(t, indented, compact)
+}
+
 func TestListServersHumanReadableFormat(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
@@ -790,7 +1063,7 @@ func TestListServersHumanReadableFormat(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatHumanReadable)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatHumanReadable, false, false)
 		require.NoError(t, err)
 	})
 
@@ -812,19 +1085,36 @@ func TestListServersHumanReadableFormat(t *testing.T) {
 	assert.Contains(t, output, "Endpoint: https://remote.example.com")
 }
 
-func TestListServersHumanReadableNoServers(t *testing.T) {
+func TestListServersHumanReadableWithoutSnapshots(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
 
 	catalogObj := Catalog{
 		Ref: "test/catalog:latest",
 		CatalogArtifact: CatalogArtifact{
-			Title: "Empty Catalog",
+			Title: "Mixed Catalog",
 			Servers: []Server{
 				{
-					Type:     workingset.ServerTypeImage,
-					Image:    "docker/server1:v1",
-					Snapshot: nil, // No snapshot
+					Type:   workingset.ServerTypeRegistry,
+					Source: "https://example.com/api",
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/beta:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "beta"},
+					},
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/unnamed:v1",
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/alpha:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "alpha"},
+					},
 				},
 			},
 		},
@@ -836,25 +1126,112 @@ func TestListServersHumanReadableNoServers(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=nonexistent"}, workingset.OutputFormatHumanReadable)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatHumanReadable, false, false)
 		require.NoError(t, err)
 	})
 
-	assert.Contains(t, output, "No servers found")
+	assert.Contains(t, output, "Servers (4)")
+
+	// Named servers first, by name, then the servers without a snapshot.
+	var listed []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "    ") {
+			listed = append(listed, strings.TrimSpace(line))
+		}
+	}
+	assert.Equal(t, []string{
+		"alpha",
+		"beta",
+		"docker/unnamed:v1 (no snapshot)",
+		"https://example.com/api (no snapshot)",
+	}, listed)
+	assert.Contains(t, output, "Image: docker/unnamed:v1")
+	assert.Contains(t, output, "Source: https://example.com/api")
 }
 
-func TestListServersUnsupportedFormat(t *testing.T) {
+func TestListServersIncludeUnsnapshotted(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
 
 	catalogObj := Catalog{
 		Ref: "test/catalog:latest",
 		CatalogArtifact: CatalogArtifact{
-			Title: "Test Catalog",
+			Title: "Mixed Catalog",
 			Servers: []Server{
 				{
 					Type:  workingset.ServerTypeImage,
-					Image: "docker/server1:v1",
+					Image: "docker/named:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "named"},
+					},
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/unnamed:v1",
+				},
+				{
+					Type:   workingset.ServerTypeRegistry,
+					Source: "https://example.com/api",
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	listed := func(filters []string, format workingset.OutputFormat, includeUnsnapshotted bool) []map[string]any {
+		output := captureStdout(t, func() {
+			require.NoError(t, ListServers(ctx, dao, catalogObj.Ref, filters, format, false, includeUnsnapshotted))
+		})
+		var result struct {
+			Servers []map[string]any `json:"servers" yaml:"servers"`
+		}
+		if format == workingset.OutputFormatYAML {
+			require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+		} else {
+			require.NoError(t, json.Unmarshal([]byte(output), &result))
+		}
+		return result.Servers
+	}
+
+	servers := listed(nil, workingset.OutputFormatJSON, false)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "docker/named:v1", servers[0]["image"])
+
+	servers = listed(nil, workingset.OutputFormatJSON, true)
+	require.Len(t, servers, 3)
+	assert.Equal(t, "docker/named:v1", servers[0]["image"])
+	assert.Equal(t, "image", servers[1]["type"])
+	assert.Equal(t, "docker/unnamed:v1", servers[1]["image"])
+	assert.Equal(t, "registry", servers[2]["type"])
+	assert.Equal(t, "https://example.com/api", servers[2]["source"])
+
+	assert.Len(t, listed(nil, workingset.OutputFormatYAML, false), 1)
+	assert.Len(t, listed(nil, workingset.OutputFormatYAML, true), 3)
+
+	// Name filters match the image, source or endpoint of servers without a
+	// snapshot, only with the flag.
+	assert.Empty(t, listed([]string{"name=unnamed"}, workingset.OutputFormatJSON, false))
+	servers = listed([]string{"name=unnamed"}, workingset.OutputFormatJSON, true)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "docker/unnamed:v1", servers[0]["image"])
+}
+
+func TestListServersHumanReadableNoServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Empty Catalog",
+			Servers: []Server{
+				{
+					Type:     workingset.ServerTypeImage,
+					Image:    "docker/server1:v1",
+					Snapshot: nil, // No snapshot
 				},
 			},
 		},
@@ -865,12 +1242,15 @@ func TestListServersUnsupportedFormat(t *testing.T) {
 	err = dao.UpsertCatalog(ctx, dbCat)
 	require.NoError(t, err)
 
-	err = ListServers(ctx, dao, catalogObj.Ref, []string{}, "unsupported")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported format")
+	output := captureStdout(t, func() {
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{"name=nonexistent"}, workingset.OutputFormatHumanReadable, false, false)
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, output, "No servers found")
 }
 
-func TestListServersServersSortedByName(t *testing.T) {
+func TestListServersUnsupportedFormat(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := desktop.WithNoDockerDesktop(t.Context())
 
@@ -882,7 +1262,34 @@ func TestListServersServersSortedByName(t *testing.T) {
 				{
 					Type:  workingset.ServerTypeImage,
 					Image: "docker/server1:v1",
-					Snapshot: &workingset.ServerSnapshot{
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	err = dao.UpsertCatalog(ctx, dbCat)
+	require.NoError(t, err)
+
+	err = ListServers(ctx, dao, catalogObj.Ref, []string{}, "unsupported", false, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestListServersServersSortedByName(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/server1:v1",
+					Snapshot: &workingset.ServerSnapshot{
 						Server: catalog.Server{
 							Name: "zebra-server",
 						},
@@ -916,7 +1323,7 @@ func TestListServersServersSortedByName(t *testing.T) {
 	require.NoError(t, err)
 
 	output := captureStdout(t, func() {
-		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON)
+		err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON, false, false)
 		require.NoError(t, err)
 	})
 
@@ -944,6 +1351,155 @@ func TestListServersServersSortedByName(t *testing.T) {
 	assert.Equal(t, "zebra-server", server["name"])
 }
 
+func setupAllServersTestCatalogs(t *testing.T) db.DAO {
+	t.Helper()
+	dao := setupTestDB(t)
+
+	catalogs := []Catalog{
+		{
+			Ref:    "test/team:latest",
+			Source: SourcePrefixUser + "cli",
+			CatalogArtifact: CatalogArtifact{
+				Title: "Team",
+				Servers: []Server{
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "mcp/slack:latest",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "slack"}},
+					},
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "mcp/github:2",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github"}},
+					},
+				},
+			},
+		},
+		{
+			Ref:    "docker/mcp-catalog:latest",
+			Source: SourcePrefixOCI + "docker/mcp-catalog:latest",
+			CatalogArtifact: CatalogArtifact{
+				Title: "Docker",
+				Servers: []Server{
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "mcp/github:1",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github"}},
+					},
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "mcp/fetch:latest",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch"}},
+					},
+					{
+						Type:  workingset.ServerTypeImage,
+						Image: "mcp/unnamed:latest",
+					},
+				},
+			},
+		},
+		{
+			Ref:             "test/empty:latest",
+			CatalogArtifact: CatalogArtifact{Title: "Empty"},
+		},
+	}
+	for _, cat := range catalogs {
+		dbCat, err := cat.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(t.Context(), dbCat))
+	}
+
+	return dao
+}
+
+func TestListAllServers(t *testing.T) {
+	dao := setupAllServersTestCatalogs(t)
+	ctx := t.Context()
+
+	output := captureStdout(t, func() {
+		err := ListAllServers(ctx, dao, []string{}, workingset.OutputFormatJSON, false, false)
+		require.NoError(t, err)
+	})
+
+	var result struct {
+		Servers []CatalogServerListEntry `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+	type row struct{ name, catalog, source, image string }
+	var rows []row
+	for _, entry := range result.Servers {
+		rows = append(rows, row{entry.Snapshot.Server.Name, entry.Catalog, entry.CatalogSource, entry.Image})
+	}
+	// Sorted by server name then catalog, so duplicates are next to each other.
+	assert.Equal(t, []row{
+		{"fetch", "docker/mcp-catalog:latest", "oci:docker/mcp-catalog:latest", "mcp/fetch:latest"},
+		{"github", "docker/mcp-catalog:latest", "oci:docker/mcp-catalog:latest", "mcp/github:1"},
+		{"github", "test/team:latest", "user:cli", "mcp/github:2"},
+		{"slack", "test/team:latest", "user:cli", "mcp/slack:latest"},
+	}, rows)
+}
+
+func TestListAllServersFilterByName(t *testing.T) {
+	dao := setupAllServersTestCatalogs(t)
+
+	output := captureStdout(t, func() {
+		err := ListAllServers(t.Context(), dao, []string{"name=GIT"}, workingset.OutputFormatYAML, false, false)
+		require.NoError(t, err)
+	})
+
+	var result struct {
+		Servers []CatalogServerListEntry `yaml:"servers"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Servers, 2)
+	assert.Equal(t, "docker/mcp-catalog:latest", result.Servers[0].Catalog)
+	assert.Equal(t, "test/team:latest", result.Servers[1].Catalog)
+
+	err := ListAllServers(t.Context(), dao, []string{"tool=search"}, workingset.OutputFormatJSON, false, false)
+	require.ErrorContains(t, err, "unsupported filter key: tool")
+}
+
+func TestListAllServersIncludeUnsnapshotted(t *testing.T) {
+	dao := setupAllServersTestCatalogs(t)
+
+	output := captureStdout(t, func() {
+		err := ListAllServers(t.Context(), dao, []string{"name=unnamed"}, workingset.OutputFormatJSON, false, true)
+		require.NoError(t, err)
+	})
+
+	var result struct {
+		Servers []CatalogServerListEntry `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Servers, 1)
+	assert.Equal(t, "mcp/unnamed:latest", result.Servers[0].Image)
+	assert.Equal(t, "docker/mcp-catalog:latest", result.Servers[0].Catalog)
+}
+
+func TestListAllServersHumanReadable(t *testing.T) {
+	dao := setupAllServersTestCatalogs(t)
+
+	output := captureStdout(t, func() {
+		err := ListAllServers(t.Context(), dao, []string{}, workingset.OutputFormatHumanReadable, false, false)
+		require.NoError(t, err)
+	})
+
+	assert.Equal(t, "Name | Catalog | Source\n"+
+		"fetch\t| docker/mcp-catalog:latest\t| oci:docker/mcp-catalog:latest\n"+
+		"github\t| docker/mcp-catalog:latest\t| oci:docker/mcp-catalog:latest\n"+
+		"github\t| test/team:latest\t| user:cli\n"+
+		"slack\t| test/team:latest\t| user:cli\n"+
+		"mcp/unnamed:latest (no snapshot)\t| docker/mcp-catalog:latest\t| oci:docker/mcp-catalog:latest\n", output)
+
+	empty := setupTestDB(t)
+	output = captureStdout(t, func() {
+		err := ListAllServers(t.Context(), empty, []string{}, workingset.OutputFormatHumanReadable, false, false)
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "No servers found\n", output)
+}
+
 func TestParseFilters(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1030,7 +1586,7 @@ func TestAddServers(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("no servers provided", func(t *testing.T) {
-		err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), catalogObj.Ref, []string{})
+		err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), catalogObj.Ref, []string{}, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "at least one server must be specified")
 	})
@@ -1038,7 +1594,7 @@ func TestAddServers(t *testing.T) {
 	t.Run("invalid catalog reference", func(t *testing.T) {
 		err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), ":::invalid", []string{
 			"docker/test:latest",
-		})
+		}, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to parse oci-reference")
 	})
@@ -1046,7 +1602,7 @@ func TestAddServers(t *testing.T) {
 	t.Run("catalog not found", func(t *testing.T) {
 		err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), "test/nonexistent:latest", []string{
 			"docker/test:latest",
-		})
+		}, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get catalog")
 	})
@@ -1054,7 +1610,7 @@ func TestAddServers(t *testing.T) {
 	t.Run("invalid server reference", func(t *testing.T) {
 		err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), catalogObj.Ref, []string{
 			"invalid://reference",
-		})
+		}, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to resolve server reference")
 	})
@@ -1103,7 +1659,7 @@ func TestAddServersUpsert(t *testing.T) {
 		// Add a server with the same name but different image -- should upsert
 		err = AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mockOci, catalogObj.Ref, []string{
 			"docker://existing-server:v2",
-		})
+		}, workingset.Output{})
 		require.NoError(t, err)
 
 		dbCat2, err := dao.GetCatalog(ctx, catalogObj.Ref)
@@ -1154,7 +1710,7 @@ func TestAddServersUpsert(t *testing.T) {
 		// Upsert only existing-server
 		err = AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mockOci, catalogObj.Ref, []string{
 			"docker://existing-server:v2",
-		})
+		}, workingset.Output{})
 		require.NoError(t, err)
 
 		dbCat2, err := dao.GetCatalog(ctx, catalogObj.Ref)
@@ -1170,6 +1726,159 @@ func TestAddServersUpsert(t *testing.T) {
 	})
 }
 
+func TestAddServersOutput(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	mockOci := mocks.NewMockOCIService(mocks.WithLocalImages([]mocks.MockImage{
+		{
+			Ref: "existing-server:v2",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: existing-server\ntype: server\nimage: existing-server:v2",
+			},
+			DigestString: "sha256:abcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		},
+	}))
+
+	catalogObj := Catalog{
+		Ref: "test/output-catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Output Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "existing-server:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{Name: "existing-server", Type: "server", Image: "existing-server:v1"},
+					},
+				},
+			},
+		},
+	}
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	addServers := func(output workingset.Output) string {
+		return captureStdout(t, func() {
+			err := AddServers(ctx, dao, mocks.NewMockRegistryAPIClient(), mockOci, catalogObj.Ref, []string{
+				"docker://existing-server:v2",
+			}, output)
+			require.NoError(t, err)
+		})
+	}
+
+	assert.Equal(t, "Replaced server existing-server in catalog test/output-catalog:latest\nAdded 1 server(s) to catalog 'test/output-catalog:latest' (replaced 1)\n", addServers(workingset.Output{}))
+	assert.Empty(t, addServers(workingset.Output{Quiet: true}))
+
+	// JSON implies quiet, even without --quiet.
+	for _, output := range []workingset.Output{
+		{Format: workingset.OutputFormatJSON},
+		{Format: workingset.OutputFormatJSON, Quiet: true},
+	} {
+		var result AddServersResult
+		require.NoError(t, json.Unmarshal([]byte(addServers(output)), &result))
+		assert.Equal(t, AddServersResult{
+			Catalog:  "test/output-catalog:latest",
+			Added:    []string{"existing-server"},
+			Replaced: []string{"existing-server"},
+		}, result)
+	}
+}
+
+func TestAddServersRecordsAddedFrom(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	mockOci := mocks.NewMockOCIService(mocks.WithLocalImages([]mocks.MockImage{
+		{
+			Ref: "image-server:v1",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: image-server\ntype: server\nimage: image-server:v1",
+			},
+			DigestString: "sha256:abcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		},
+	}))
+
+	registryServer := v0.ServerResponse{
+		Server: v0.ServerJSON{
+			Name:        "io.example/registry-server",
+			Description: "Registry server",
+			Version:     "1.0.0",
+			Packages: []model.Package{
+				{
+					RegistryType: "oci",
+					Identifier:   "ghcr.io/example/registry-server:1.0.0",
+					Transport:    model.Transport{Type: "stdio"},
+				},
+			},
+		},
+		Meta: v0.ResponseMeta{Official: &v0.RegistryExtensions{IsLatest: true}},
+	}
+	registryRef := "https://example.com/v0/servers/registry-server/versions/1.0.0"
+	registryClient := mocks.NewMockRegistryAPIClient(
+		mocks.WithServerListResponses(map[string]v0.ServerListResponse{
+			"https://example.com/v0/servers/registry-server/versions": {Servers: []v0.ServerResponse{registryServer}},
+		}),
+		mocks.WithServerResponses(map[string]v0.ServerResponse{registryRef: registryServer}),
+	)
+
+	for _, catalogObj := range []Catalog{
+		{
+			Ref: "test/source-catalog:latest",
+			CatalogArtifact: CatalogArtifact{
+				Title: "Source Catalog",
+				Servers: []Server{
+					{
+						Type:  workingset.ServerTypeImage,
+						Image: "catalog-server:v1",
+						Snapshot: &workingset.ServerSnapshot{
+							Server: catalog.Server{Name: "catalog-server", Type: "server", Image: "catalog-server:v1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Ref:             "test/catalog:latest",
+			CatalogArtifact: CatalogArtifact{Title: "Test Catalog", Servers: []Server{}},
+		},
+	} {
+		dbCat, err := catalogObj.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+
+	captureStdout(t, func() {
+		err := AddServers(ctx, dao, registryClient, mockOci, "test/catalog:latest", []string{
+			"docker://image-server:v1",
+			registryRef,
+			"catalog://test/source-catalog:latest/catalog-server",
+		}, workingset.Output{})
+		require.NoError(t, err)
+	})
+
+	dbCat, err := dao.GetCatalog(ctx, "test/catalog:latest")
+	require.NoError(t, err)
+	addedFrom := map[string]string{}
+	for _, server := range NewFromDb(dbCat).Servers {
+		addedFrom[server.Snapshot.Server.Name] = server.AddedFrom
+	}
+	assert.Equal(t, map[string]string{
+		"image-server":               "docker://image-server:v1",
+		"io-example-registry-server": registryRef,
+		"catalog-server":             "catalog://test/source-catalog:latest/catalog-server",
+	}, addedFrom)
+
+	output := captureStdout(t, func() {
+		err := InspectServer(ctx, dao, "test/catalog:latest", "image-server", workingset.OutputFormatJSON, false)
+		require.NoError(t, err)
+	})
+	var result InspectResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "docker://image-server:v1", result.AddedFrom)
+}
+
 func TestRemoveServers(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -1209,7 +1918,7 @@ func TestRemoveServers(t *testing.T) {
 		require.NoError(t, err)
 
 		output := captureStdout(t, func() {
-			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one"})
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one"}, false, workingset.Output{})
 			require.NoError(t, err)
 		})
 
@@ -1268,7 +1977,7 @@ func TestRemoveServers(t *testing.T) {
 		require.NoError(t, err)
 
 		output := captureStdout(t, func() {
-			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one", "server-three"})
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one", "server-three"}, false, workingset.Output{})
 			require.NoError(t, err)
 		})
 
@@ -1309,7 +2018,7 @@ func TestRemoveServers(t *testing.T) {
 		err = dao.UpsertCatalog(ctx, dbCat)
 		require.NoError(t, err)
 
-		err = RemoveServers(ctx, dao, catalogObj.Ref, []string{"nonexistent-server"})
+		err = RemoveServers(ctx, dao, catalogObj.Ref, []string{"nonexistent-server"}, false, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no matching servers found to remove")
 	})
@@ -1335,7 +2044,7 @@ func TestRemoveServers(t *testing.T) {
 		err = dao.UpsertCatalog(ctx, dbCat)
 		require.NoError(t, err)
 
-		err = RemoveServers(ctx, dao, catalogObj.Ref, []string{"some-name"})
+		err = RemoveServers(ctx, dao, catalogObj.Ref, []string{"some-name"}, false, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no matching servers found to remove")
 
@@ -1347,19 +2056,19 @@ func TestRemoveServers(t *testing.T) {
 	})
 
 	t.Run("no server names provided", func(t *testing.T) {
-		err := RemoveServers(ctx, dao, "test/catalog:latest", []string{})
+		err := RemoveServers(ctx, dao, "test/catalog:latest", []string{}, false, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "at least one server name must be specified")
 	})
 
 	t.Run("invalid catalog reference", func(t *testing.T) {
-		err := RemoveServers(ctx, dao, ":::invalid", []string{"server-one"})
+		err := RemoveServers(ctx, dao, ":::invalid", []string{"server-one"}, false, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to parse oci-reference")
 	})
 
 	t.Run("catalog not found", func(t *testing.T) {
-		err := RemoveServers(ctx, dao, "test/nonexistent:latest", []string{"server-one"})
+		err := RemoveServers(ctx, dao, "test/nonexistent:latest", []string{"server-one"}, false, workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get catalog")
 	})
@@ -1390,7 +2099,7 @@ func TestRemoveServers(t *testing.T) {
 		require.NoError(t, err)
 
 		output := captureStdout(t, func() {
-			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"only-server"})
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"only-server"}, false, workingset.Output{})
 			require.NoError(t, err)
 		})
 
@@ -1402,4 +2111,264 @@ func TestRemoveServers(t *testing.T) {
 		cat := NewFromDb(dbCat5)
 		assert.Empty(t, cat.Servers)
 	})
+
+	mixedCatalog := func(t *testing.T, ref string) Catalog {
+		t.Helper()
+		catalogObj := Catalog{
+			Ref: ref,
+			CatalogArtifact: CatalogArtifact{
+				Title: "Test Catalog",
+				Servers: []Server{
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "docker/server1:v1",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "server-one"}},
+					},
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "docker/server2:v1",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "server-two"}},
+					},
+				},
+			},
+		}
+		dbCat, err := catalogObj.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+		return catalogObj
+	}
+
+	t.Run("remove matched and unmatched servers", func(t *testing.T) {
+		catalogObj := mixedCatalog(t, "test/catalog6:latest")
+
+		output := captureStdout(t, func() {
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one", "missing-one", "missing-two"}, false, workingset.Output{})
+			require.NoError(t, err)
+		})
+
+		assert.Contains(t, output, "Warning: server(s) not found in catalog 'test/catalog6:latest': missing-one, missing-two")
+		assert.Contains(t, output, "Removed 1 server(s)")
+
+		dbCat, err := dao.GetCatalog(ctx, catalogObj.Ref)
+		require.NoError(t, err)
+		cat := NewFromDb(dbCat)
+		assert.Nil(t, cat.FindServer("server-one"))
+		assert.NotNil(t, cat.FindServer("server-two"))
+	})
+
+	t.Run("remove matched and unmatched servers with strict", func(t *testing.T) {
+		catalogObj := mixedCatalog(t, "test/catalog7:latest")
+
+		output := captureStdout(t, func() {
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one", "missing-one"}, true, workingset.Output{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "server(s) not found in catalog test/catalog7:latest: missing-one")
+		})
+		assert.NotContains(t, output, "Removed")
+
+		dbCat, err := dao.GetCatalog(ctx, catalogObj.Ref)
+		require.NoError(t, err)
+		cat := NewFromDb(dbCat)
+		assert.Len(t, cat.Servers, 2)
+	})
+
+	t.Run("remove matched servers with strict", func(t *testing.T) {
+		catalogObj := mixedCatalog(t, "test/catalog8:latest")
+
+		output := captureStdout(t, func() {
+			err := RemoveServers(ctx, dao, catalogObj.Ref, []string{"server-one", "server-two"}, true, workingset.Output{})
+			require.NoError(t, err)
+		})
+		assert.NotContains(t, output, "Warning")
+		assert.Contains(t, output, "Removed 2 server(s)")
+	})
+}
+
+func TestMoveServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	longLived := true
+	upsert := func(t *testing.T, ref string, servers ...Server) {
+		t.Helper()
+		dbCat, err := Catalog{Ref: ref, CatalogArtifact: CatalogArtifact{Title: ref, Servers: servers}}.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+	imageServer := func(name string) Server {
+		return Server{
+			Type:     workingset.ServerTypeImage,
+			Image:    "docker/" + name + ":v1",
+			Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: name, Image: "docker/" + name + ":v1"}},
+		}
+	}
+	github := imageServer("github")
+	github.LongLivedOverride = &longLived
+	github.AddedFrom = "docker://docker/github:v1"
+	github.Tools = []string{"search"}
+
+	upsert(t, "test/source:latest", github, imageServer("fetch"))
+	upsert(t, "test/destination:latest", imageServer("notion"))
+
+	t.Run("move server", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, MoveServer(ctx, dao, "test/source:latest", "test/destination:latest", "github", workingset.Output{}))
+		})
+		assert.Contains(t, output, "Moved server github from catalog 'test/source:latest' to catalog 'test/destination:latest'")
+
+		dbSrc, err := dao.GetCatalog(ctx, "test/source:latest")
+		require.NoError(t, err)
+		src := NewFromDb(dbSrc)
+		assert.Nil(t, src.FindServer("github"))
+		assert.NotNil(t, src.FindServer("fetch"))
+
+		dbDst, err := dao.GetCatalog(ctx, "test/destination:latest")
+		require.NoError(t, err)
+		dst := NewFromDb(dbDst)
+		assert.NotNil(t, dst.FindServer("notion"))
+		// The server keeps its stable ID.
+		moved := github
+		moved.ID = github.StableID()
+		assert.Equal(t, &moved, dst.FindServer("github"))
+	})
+
+	t.Run("server not in source", func(t *testing.T) {
+		err := MoveServer(ctx, dao, "test/source:latest", "test/destination:latest", "github", workingset.Output{})
+		require.EqualError(t, err, "server github not found in catalog test/source:latest")
+	})
+
+	t.Run("server already in destination", func(t *testing.T) {
+		captureStdout(t, func() {
+			require.NoError(t, MoveServer(ctx, dao, "test/destination:latest", "test/source:latest", "github", workingset.Output{}))
+		})
+
+		upsert(t, "test/other:latest", imageServer("github"))
+		err := MoveServer(ctx, dao, "test/other:latest", "test/source:latest", "github", workingset.Output{})
+		require.EqualError(t, err, "server github already exists in catalog test/source:latest")
+
+		dbOther, err := dao.GetCatalog(ctx, "test/other:latest")
+		require.NoError(t, err)
+		other := NewFromDb(dbOther)
+		assert.NotNil(t, other.FindServer("github"))
+	})
+
+	t.Run("missing catalog", func(t *testing.T) {
+		err := MoveServer(ctx, dao, "test/source:latest", "test/missing:latest", "fetch", workingset.Output{})
+		require.ErrorContains(t, err, "failed to get catalog test/missing:latest")
+	})
+
+	t.Run("same catalog", func(t *testing.T) {
+		err := MoveServer(ctx, dao, "test/source:latest", "test/source:latest", "fetch", workingset.Output{})
+		require.EqualError(t, err, "source and destination catalogs are the same: test/source:latest")
+	})
+}
+
+func TestServerIcon(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   Server
+		expected string
+	}{
+		{name: "no snapshot", server: Server{Type: workingset.ServerTypeImage}, expected: ""},
+		{name: "no icon", server: Server{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "s"}}}, expected: ""},
+		{name: "https icon", server: Server{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "s", Icon: "https://example.com/icon.png"}}}, expected: "https://example.com/icon.png"},
+		{name: "http icon", server: Server{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "s", Icon: "http://example.com/icon.png"}}}, expected: "http://example.com/icon.png"},
+		{name: "data uri", server: Server{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "s", Icon: "data:image/png;base64,AAAA"}}}, expected: ""},
+		{name: "relative path", server: Server{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "s", Icon: "/icon.png"}}}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ServerIcon(tt.server))
+		})
+	}
+}
+
+func TestListServersIncludesIcon(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogObj := Catalog{
+		Ref: "test/catalog:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "Test Catalog",
+			Servers: []Server{
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/with-icon:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name: "with-icon",
+							Icon: "https://example.com/icon.png",
+						},
+					},
+				},
+				{
+					Type:  workingset.ServerTypeImage,
+					Image: "docker/without-icon:v1",
+					Snapshot: &workingset.ServerSnapshot{
+						Server: catalog.Server{
+							Name: "without-icon",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dbCat, err := catalogObj.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	t.Run("JSON format", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatJSON, false, false)
+			require.NoError(t, err)
+		})
+
+		var result struct {
+			Servers []ServerListEntry `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		require.Len(t, result.Servers, 2)
+		assert.Equal(t, "with-icon", result.Servers[0].Snapshot.Server.Name)
+		assert.Equal(t, "https://example.com/icon.png", result.Servers[0].Icon)
+		assert.Equal(t, "without-icon", result.Servers[1].Snapshot.Server.Name)
+		assert.Empty(t, result.Servers[1].Icon)
+	})
+
+	t.Run("YAML format", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			err := ListServers(ctx, dao, catalogObj.Ref, []string{}, workingset.OutputFormatYAML, false, false)
+			require.NoError(t, err)
+		})
+
+		var result struct {
+			Servers []ServerListEntry `yaml:"servers"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+		require.Len(t, result.Servers, 2)
+		assert.Equal(t, "https://example.com/icon.png", result.Servers[0].Icon)
+		assert.Empty(t, result.Servers[1].Icon)
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			err := InspectServer(ctx, dao, catalogObj.Ref, "with-icon", workingset.OutputFormatJSON, false)
+			require.NoError(t, err)
+		})
+
+		var result InspectResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "https://example.com/icon.png", result.Icon)
+
+		output = captureStdout(t, func() {
+			err := InspectServer(ctx, dao, catalogObj.Ref, "without-icon", workingset.OutputFormatJSON, false)
+			require.NoError(t, err)
+		})
+
+		result = InspectResult{}
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Empty(t, result.Icon)
+	})
 }
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/goccy/go-yaml"
-	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
@@ -19,15 +18,10 @@ import (
 )
 
 func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, format workingset.OutputFormat, pullOptionParam string, yqExpr string) error {
-	ref, err := name.ParseReference(refStr)
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
+		return err
 	}
-	if !oci.IsValidInputReference(ref) {
-		return fmt.Errorf("reference %s must be a valid OCI reference without a digest", refStr)
-	}
-
-	refStr = oci.FullNameWithoutDigest(ref)
 
 	pulledPreviously, err := dao.CheckPullRecord(ctx, refStr)
 	if err != nil {
@@ -85,6 +79,11 @@ func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 		}
 	}
 
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return err
+	}
+
 	catalog := NewFromDb(dbCatalog)
 	policyClient := policycli.ClientForCLI(ctx)
 	attachCatalogPolicy(ctx, policyClient, catalog.Ref, &catalog, true)
@@ -108,7 +107,5 @@ func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 		}
 	}
 
-	fmt.Println(string(data))
-
-	return nil
+	return workingset.Output{}.Print(data)
 }
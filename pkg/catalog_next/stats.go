@@ -0,0 +1,142 @@
+package catalognext
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// largestServersLimit is the number of servers listed in CatalogStats.LargestServers.
+const largestServersLimit = 5
+
+type CatalogStats struct {
+	Ref              string            `yaml:"ref" json:"ref"`
+	Servers          int               `yaml:"servers" json:"servers"`
+	ServersByType    map[string]int    `yaml:"serversByType" json:"serversByType"`
+	RequiringSecrets int               `yaml:"requiringSecrets" json:"requiringSecrets"`
+	RequiringOAuth   int               `yaml:"requiringOAuth" json:"requiringOAuth"`
+	DistinctTools    int               `yaml:"distinctTools" json:"distinctTools"`
+	LargestServers   []ServerToolCount `yaml:"largestServers" json:"largestServers"`
+}
+
+type ServerToolCount struct {
+	Name  string `yaml:"name" json:"name"`
+	Tools int    `yaml:"tools" json:"tools"`
+}
+
+// Stats prints a summary of the servers stored for a catalog.
+func Stats(ctx context.Context, dao db.DAO, refStr string, format workingset.OutputFormat) error {
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
+	if err != nil {
+		return err
+	}
+
+	dbCatalog, err := dao.GetCatalog(ctx, refStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("catalog %s not found", refStr)
+		}
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return err
+	}
+
+	stats := computeStats(NewFromDb(dbCatalog).Catalog)
+
+	var data []byte
+	switch format {
+	case workingset.OutputFormatHumanReadable:
+		data = []byte(printStatsHumanReadable(stats))
+	case workingset.OutputFormatJSON:
+		data, err = json.MarshalIndent(stats, "", "  ")
+	case workingset.OutputFormatYAML:
+		data, err = yaml.Marshal(stats)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog stats: %w", err)
+	}
+
+	return workingset.Output{}.Print(data)
+}
+
+func computeStats(catalog Catalog) CatalogStats {
+	stats := CatalogStats{
+		Ref:            catalog.Ref,
+		Servers:        len(catalog.Servers),
+		ServersByType:  map[string]int{},
+		LargestServers: []ServerToolCount{},
+	}
+
+	tools := map[string]bool{}
+	for _, server := range catalog.Servers {
+		stats.ServersByType[string(server.Type)]++
+
+		if server.Snapshot == nil {
+			continue
+		}
+		snapshot := server.Snapshot.Server
+		if len(snapshot.Secrets) > 0 {
+			stats.RequiringSecrets++
+		}
+		if snapshot.IsOAuthServer() {
+			stats.RequiringOAuth++
+		}
+		for _, tool := range snapshot.Tools {
+			tools[tool.Name] = true
+		}
+		if len(snapshot.Tools) > 0 {
+			stats.LargestServers = append(stats.LargestServers, ServerToolCount{
+				Name:  snapshot.Name,
+				Tools: len(snapshot.Tools),
+			})
+		}
+	}
+	stats.DistinctTools = len(tools)
+
+	slices.SortFunc(stats.LargestServers, func(a, b ServerToolCount) int {
+		return cmp.Or(cmp.Compare(b.Tools, a.Tools), strings.Compare(a.Name, b.Name))
+	})
+	if len(stats.LargestServers) > largestServersLimit {
+		stats.LargestServers = stats.LargestServers[:largestServersLimit]
+	}
+
+	return stats
+}
+
+func printStatsHumanReadable(stats CatalogStats) string {
+	lines := []string{
+		"Catalog: " + stats.Ref,
+		fmt.Sprintf("Servers: %d", stats.Servers),
+	}
+	for _, serverType := range slices.Sorted(maps.Keys(stats.ServersByType)) {
+		lines = append(lines, fmt.Sprintf("  %s: %d", serverType, stats.ServersByType[serverType]))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Requiring secrets: %d", stats.RequiringSecrets),
+		fmt.Sprintf("Requiring OAuth: %d", stats.RequiringOAuth),
+		fmt.Sprintf("Distinct tools: %d", stats.DistinctTools),
+	)
+	if len(stats.LargestServers) > 0 {
+		lines = append(lines, "Largest servers:")
+		for _, server := range stats.LargestServers {
+			lines = append(lines, fmt.Sprintf("  %s\t| %d tools", server.Name, server.Tools))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
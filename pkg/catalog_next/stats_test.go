@@ -0,0 +1,182 @@
+package catalognext
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func statsTestServer(serverType workingset.ServerType, server catalog.Server) Server {
+	s := Server{
+		Type:     serverType,
+		Snapshot: &workingset.ServerSnapshot{Server: server},
+	}
+	switch serverType {
+	case workingset.ServerTypeImage:
+		s.Image = "mcp/" + server.Name
+	case workingset.ServerTypeRemote:
+		s.Endpoint = "https://" + server.Name + ".example.com/mcp"
+	case workingset.ServerTypeRegistry:
+		s.Source = "https://registry.example.com/" + server.Name
+	}
+	return s
+}
+
+func statsTestTools(names ...string) []catalog.Tool {
+	var result []catalog.Tool
+	for _, name := range names {
+		result = append(result, catalog.Tool{Name: name})
+	}
+	return result
+}
+
+func TestStats(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	cat := Catalog{
+		Ref: "test/stats:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "stats",
+			Servers: []Server{
+				statsTestServer(workingset.ServerTypeImage, catalog.Server{
+					Name:    "github",
+					Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}},
+					Tools:   statsTestTools("create_issue", "list_issues", "search"),
+				}),
+				statsTestServer(workingset.ServerTypeImage, catalog.Server{
+					Name:  "fetch",
+					Tools: statsTestTools("fetch"),
+				}),
+				statsTestServer(workingset.ServerTypeImage, catalog.Server{
+					Name:  "duckduckgo",
+					Tools: statsTestTools("search", "fetch_content"),
+				}),
+				statsTestServer(workingset.ServerTypeRemote, catalog.Server{
+					Name: "notion",
+					OAuth: &catalog.OAuth{
+						Providers: []catalog.OAuthProvider{{Provider: "notion", Secret: "notion.token", Env: "NOTION_TOKEN"}},
+					},
+					Secrets: []catalog.Secret{{Name: "notion.token", Env: "NOTION_TOKEN"}},
+					Tools:   statsTestTools("search_pages", "create_page"),
+				}),
+				statsTestServer(workingset.ServerTypeRegistry, catalog.Server{
+					Name: "empty",
+				}),
+			},
+		},
+	}
+	dbCat, err := cat.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	output := captureStdout(t, func() {
+		err := Stats(ctx, dao, cat.Ref, workingset.OutputFormatJSON)
+		require.NoError(t, err)
+	})
+
+	var stats CatalogStats
+	require.NoError(t, json.Unmarshal([]byte(output), &stats))
+
+	assert.Equal(t, "test/stats:latest", stats.Ref)
+	assert.Equal(t, 5, stats.Servers)
+	assert.Equal(t, map[string]int{"image": 3, "remote": 1, "registry": 1}, stats.ServersByType)
+	assert.Equal(t, 2, stats.RequiringSecrets)
+	assert.Equal(t, 1, stats.RequiringOAuth)
+	assert.Equal(t, 7, stats.DistinctTools)
+	assert.Equal(t, []ServerToolCount{
+		{Name: "github", Tools: 3},
+		{Name: "duckduckgo", Tools: 2},
+		{Name: "notion", Tools: 2},
+		{Name: "fetch", Tools: 1},
+	}, stats.LargestServers)
+}
+
+func TestStatsHumanReadable(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	cat := Catalog{
+		Ref: "test/stats:latest",
+		CatalogArtifact: CatalogArtifact{
+			Title: "stats",
+			Servers: []Server{
+				statsTestServer(workingset.ServerTypeImage, catalog.Server{
+					Name:  "fetch",
+					Tools: statsTestTools("fetch"),
+				}),
+			},
+		},
+	}
+	dbCat, err := cat.ToDb()
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+
+	output := captureStdout(t, func() {
+		err := Stats(ctx, dao, cat.Ref, workingset.OutputFormatHumanReadable)
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, output, "Servers: 1")
+	assert.Contains(t, output, "  image: 1")
+	assert.Contains(t, output, "Distinct tools: 1")
+	assert.Contains(t, output, "  fetch\t| 1 tools")
+}
+
+func TestStatsLargestServersLimit(t *testing.T) {
+	var servers []Server
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		servers = append(servers, statsTestServer(workingset.ServerTypeImage, catalog.Server{
+			Name:  name,
+			Tools: statsTestTools(name + "_tool"),
+		}))
+	}
+
+	stats := computeStats(Catalog{CatalogArtifact: CatalogArtifact{Servers: servers}})
+
+	assert.Equal(t, 7, stats.DistinctTools)
+	require.Len(t, stats.LargestServers, largestServersLimit)
+	assert.Equal(t, "a", stats.LargestServers[0].Name)
+}
+
+func TestStatsNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	err := Stats(ctx, dao, "test/nonexistent:latest", workingset.OutputFormatJSON)
+	require.ErrorContains(t, err, "catalog test/nonexistent:latest not found")
+}
+
+func TestStatsIncludesBaseServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	for _, cat := range []Catalog{
+		{Ref: "test/base:latest", CatalogArtifact: CatalogArtifact{Title: "base", Servers: []Server{
+			statsTestServer(workingset.ServerTypeImage, catalog.Server{Name: "fetch", Tools: statsTestTools("fetch")}),
+			statsTestServer(workingset.ServerTypeImage, catalog.Server{Name: "github", Tools: statsTestTools("search")}),
+		}}},
+		{Ref: "test/derived:latest", CatalogArtifact: CatalogArtifact{Title: "derived", Base: "test/base:latest", Servers: []Server{
+			statsTestServer(workingset.ServerTypeImage, catalog.Server{Name: "github", Tools: statsTestTools("search", "create_issue")}),
+		}}},
+	} {
+		dbCat, err := cat.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCat))
+	}
+
+	output := captureStdout(t, func() {
+		require.NoError(t, Stats(ctx, dao, "test/derived:latest", workingset.OutputFormatJSON))
+	})
+
+	var stats CatalogStats
+	require.NoError(t, json.Unmarshal([]byte(output), &stats))
+	assert.Equal(t, 2, stats.Servers)
+	assert.Equal(t, []ServerToolCount{{Name: "github", Tools: 2}, {Name: "fetch", Tools: 1}}, stats.LargestServers)
+}
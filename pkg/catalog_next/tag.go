@@ -6,25 +6,22 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/google/go-containerregistry/pkg/name"
-
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func Tag(ctx context.Context, dao db.DAO, refStr string, tag string) error {
-	ref, err := name.ParseReference(refStr)
+func Tag(ctx context.Context, dao db.DAO, refStr string, tag string, output workingset.Output) error {
+	refStr, err := resolveCatalogRef(ctx, dao, refStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
+		return err
 	}
 
-	refStr = oci.FullNameWithoutDigest(ref)
-
-	tagRef, err := name.ParseReference(tag)
+	tagRef, err := oci.NormalizeCatalogRef(tag)
 	if err != nil {
 		return fmt.Errorf("failed to parse tag %s: %w", tag, err)
 	}
-	tag = oci.FullNameWithoutDigest(tagRef)
+	tag = tagRef
 
 	dbCatalog, err := dao.GetCatalog(ctx, refStr)
 	if err != nil {
@@ -42,6 +39,50 @@ func Tag(ctx context.Context, dao db.DAO, refStr string, tag string) error {
 		return fmt.Errorf("failed to tag catalog: %w", err)
 	}
 
-	fmt.Printf("Tagged catalog %s as %s\n", refStr, tag)
+	output.Printf("Tagged catalog %s as %s\n", refStr, tag)
+	return nil
+}
+
+// Retag stores the catalog at srcRef under dstRef, e.g. to promote
+// mcp/my-catalog:staging to mcp/my-catalog:latest. Unlike Tag, the copy keeps
+// the source of the original catalog, so it is indistinguishable from it, and
+// its digest is unchanged. When removeSource is set, srcRef is removed and
+// its aliases point to dstRef, in the same transaction as the copy.
+func Retag(ctx context.Context, dao db.DAO, srcRef string, dstRef string, removeSource bool, output workingset.Output) error {
+	src, err := resolveCatalogRef(ctx, dao, srcRef)
+	if err != nil {
+		return err
+	}
+	dst, err := oci.NormalizeCatalogRef(dstRef)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("cannot retag catalog %s to itself", src)
+	}
+
+	dbCatalog, err := dao.GetCatalog(ctx, src)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("catalog %s not found", src)
+		}
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	dbCatalog.Ref = dst
+	if removeSource {
+		if err := dao.MoveCatalog(ctx, *dbCatalog, src); err != nil {
+			return fmt.Errorf("failed to move catalog %s to %s: %w", src, dst, err)
+		}
+
+		output.Printf("Moved catalog %s to %s\n", src, dst)
+		return nil
+	}
+
+	if err := dao.UpsertCatalog(ctx, *dbCatalog); err != nil {
+		return fmt.Errorf("failed to retag catalog: %w", err)
+	}
+
+	output.Printf("Tagged catalog %s as %s\n", src, dst)
 	return nil
 }
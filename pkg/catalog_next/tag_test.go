@@ -1,12 +1,15 @@
 package catalognext
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
 func TestTag(t *testing.T) {
@@ -23,7 +26,7 @@ func TestTag(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("tag catalog with new version", func(t *testing.T) {
-		err := Tag(t.Context(), dao, "mcp/test-catalog:v1", "mcp/test-catalog:v2")
+		err := Tag(t.Context(), dao, "mcp/test-catalog:v1", "mcp/test-catalog:v2", workingset.Output{})
 		require.NoError(t, err)
 
 		// Verify the new catalog was created
@@ -34,7 +37,7 @@ func TestTag(t *testing.T) {
 	})
 
 	t.Run("tag catalog with different name", func(t *testing.T) {
-		err := Tag(ctx, dao, "mcp/test-catalog:v1", "mcp/prod-catalog:latest")
+		err := Tag(ctx, dao, "mcp/test-catalog:v1", "mcp/prod-catalog:latest", workingset.Output{})
 		require.NoError(t, err)
 
 		// Verify the new catalog was created
@@ -45,20 +48,120 @@ func TestTag(t *testing.T) {
 	})
 
 	t.Run("tag non-existent catalog fails", func(t *testing.T) {
-		err := Tag(ctx, dao, "mcp/nonexistent:latest", "mcp/new:latest")
+		err := Tag(ctx, dao, "mcp/nonexistent:latest", "mcp/new:latest", workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
 
 	t.Run("tag with invalid source reference fails", func(t *testing.T) {
-		err := Tag(ctx, dao, "invalid reference", "mcp/new:latest")
+		err := Tag(ctx, dao, "invalid reference", "mcp/new:latest", workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to parse oci-reference")
 	})
 
 	t.Run("tag with invalid target reference fails", func(t *testing.T) {
-		err := Tag(ctx, dao, "mcp/test-catalog:v1", "invalid reference")
+		err := Tag(ctx, dao, "mcp/test-catalog:v1", "invalid reference", workingset.Output{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to parse tag")
 	})
 }
+
+func TestRetag(t *testing.T) {
+	ctx := t.Context()
+	dao := setupTestDB(t)
+
+	storeCatalog := func(t *testing.T, ref string) db.Catalog {
+		t.Helper()
+		catalogObj := Catalog{
+			Ref:    ref,
+			Source: SourcePrefixUser + "cli",
+			CatalogArtifact: CatalogArtifact{
+				Title: "Staging Catalog",
+				Servers: []Server{
+					{
+						Type:     workingset.ServerTypeImage,
+						Image:    "mcp/fetch:latest",
+						Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch"}},
+					},
+				},
+			},
+		}
+		dbCatalog, err := catalogObj.ToDb()
+		require.NoError(t, err)
+		require.NoError(t, dao.UpsertCatalog(ctx, dbCatalog))
+		return dbCatalog
+	}
+
+	t.Run("retag keeps the source catalog", func(t *testing.T) {
+		stored := storeCatalog(t, "mcp/keep:staging")
+
+		output := captureStdout(t, func() {
+			require.NoError(t, Retag(ctx, dao, "mcp/keep:staging", "mcp/keep:latest", false, workingset.Output{}))
+		})
+		assert.Contains(t, output, "Tagged catalog mcp/keep:staging as mcp/keep:latest")
+
+		retagged, err := dao.GetCatalog(ctx, "mcp/keep:latest")
+		require.NoError(t, err)
+		assert.Equal(t, stored.Digest, retagged.Digest)
+		assert.Equal(t, stored.Source, retagged.Source)
+		assert.Equal(t, stored.Title, retagged.Title)
+		assert.Len(t, retagged.Servers, 1)
+
+		original, err := dao.GetCatalog(ctx, "mcp/keep:staging")
+		require.NoError(t, err)
+		assert.Equal(t, retagged.Digest, original.Digest)
+	})
+
+	t.Run("retag removes the source catalog", func(t *testing.T) {
+		stored := storeCatalog(t, "mcp/move:staging")
+		require.NoError(t, dao.SetCatalogAlias(ctx, db.CatalogAlias{Alias: "move", Ref: "mcp/move:staging"}))
+
+		output := captureStdout(t, func() {
+			require.NoError(t, Retag(ctx, dao, "mcp/move:staging", "mcp/move:latest", true, workingset.Output{}))
+		})
+		assert.Contains(t, output, "Moved catalog mcp/move:staging to mcp/move:latest")
+
+		retagged, err := dao.GetCatalog(ctx, "mcp/move:latest")
+		require.NoError(t, err)
+		assert.Equal(t, stored.Digest, retagged.Digest)
+
+		_, err = dao.GetCatalog(ctx, "mcp/move:staging")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		// The aliases of the source catalog follow it.
+		alias, err := dao.GetCatalogAlias(ctx, "move")
+		require.NoError(t, err)
+		assert.Equal(t, "mcp/move:latest", alias.Ref)
+	})
+
+	t.Run("retag rejects digest references", func(t *testing.T) {
+		storeCatalog(t, "mcp/digest:staging")
+		digestRef := "mcp/digest@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+		err := Retag(ctx, dao, "mcp/digest:staging", digestRef, false, workingset.Output{})
+		require.ErrorContains(t, err, "without a digest")
+
+		err = Retag(ctx, dao, digestRef, "mcp/digest:latest", false, workingset.Output{})
+		require.ErrorContains(t, err, "without a digest")
+	})
+
+	t.Run("retag rejects invalid references", func(t *testing.T) {
+		err := Retag(ctx, dao, "mcp/keep:staging", "invalid reference", false, workingset.Output{})
+		require.ErrorContains(t, err, "failed to parse oci-reference")
+	})
+
+	t.Run("retag to the same reference fails", func(t *testing.T) {
+		storeCatalog(t, "mcp/same:latest")
+
+		err := Retag(ctx, dao, "mcp/same", "docker.io/mcp/same:latest", true, workingset.Output{})
+		require.ErrorContains(t, err, "to itself")
+
+		_, err = dao.GetCatalog(ctx, "mcp/same:latest")
+		require.NoError(t, err)
+	})
+
+	t.Run("retag non-existent catalog fails", func(t *testing.T) {
+		err := Retag(ctx, dao, "mcp/nonexistent:latest", "mcp/new:latest", false, workingset.Output{})
+		require.ErrorContains(t, err, "catalog mcp/nonexistent:latest not found")
+	})
+}
@@ -8,25 +8,39 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+
 	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
 type CatalogDAO interface {
 	GetCatalog(ctx context.Context, ref string) (*Catalog, error)
 	UpsertCatalog(ctx context.Context, catalog Catalog) error
+	// UpsertCatalogs upserts several catalogs in a single transaction.
+	UpsertCatalogs(ctx context.Context, catalogs ...Catalog) error
 	DeleteCatalog(ctx context.Context, ref string) error
+	// MoveCatalog stores catalog, under its new reference, removes the catalog
+	// at fromRef and repoints the aliases of fromRef to the new reference, in
+	// a single transaction.
+	MoveCatalog(ctx context.Context, catalog Catalog, fromRef string) error
 	ListCatalogs(ctx context.Context) ([]Catalog, error)
 }
 
 type ToolList []string
 
+// Annotations are free-form key/value annotations, stored as a JSON object.
+type Annotations map[string]string
+
 type Catalog struct {
-	Ref         string          `db:"ref"`
-	Digest      string          `db:"digest"`
-	Title       string          `db:"title"`
-	Source      string          `db:"source"`
-	LastUpdated *time.Time      `db:"last_updated"`
-	Servers     []CatalogServer `db:"-"`
+	Ref         string     `db:"ref"`
+	Digest      string     `db:"digest"`
+	Title       string     `db:"title"`
+	Source      string     `db:"source"`
+	LastUpdated *time.Time `db:"last_updated"`
+	// Base is the reference of the catalog whose servers this catalog
+	// inherits. See ResolveCatalogBase.
+	Base    string          `db:"base"`
+	Servers []CatalogServer `db:"-"`
 }
 
 type CatalogServer struct {
@@ -38,9 +52,48 @@ type CatalogServer struct {
 	Endpoint   string   `db:"endpoint" json:"endpoint"`
 	CatalogRef string   `db:"catalog_ref" json:"catalog_ref"`
 
+	// LongLivedOverride overrides the snapshot's longLived flag when set.
+	LongLivedOverride *bool `db:"long_lived_override" json:"long_lived_override"`
+
+	// AddedFrom records where the server was added from.
+	AddedFrom string `db:"added_from" json:"added_from"`
+
+	Annotations Annotations `db:"annotations" json:"annotations"`
+
+	// StableID identifies the server by what it runs rather than by its
+	// name. See DeriveStableID.
+	StableID string `db:"stable_id" json:"stable_id"`
+
 	Snapshot *ServerSnapshot `db:"snapshot" json:"snapshot"`
 }
 
+// DeriveStableID derives the identity of a server from its image digest,
+// registry URL or endpoint, or from its snapshot for the other servers, so
+// that it doesn't change when the server is renamed.
+func (server CatalogServer) DeriveStableID() string {
+	switch server.ServerType {
+	case "image":
+		return catalog.ImageServerID(server.Image)
+	case "registry":
+		return catalog.RegistryServerID(server.Source)
+	case "remote":
+		return catalog.RemoteServerID(server.Endpoint)
+	}
+	if server.Snapshot != nil {
+		return server.Snapshot.Server.StableID()
+	}
+	return ""
+}
+
+// fillStableIDs derives the stable IDs of the servers stored without one.
+func fillStableIDs(servers []CatalogServer) {
+	for i := range servers {
+		if servers[i].StableID == "" {
+			servers[i].StableID = servers[i].DeriveStableID()
+		}
+	}
+}
+
 // FindServerInCatalogs searches all catalogs in the database for a server by name.
 // Returns the matching catalog.Server or an error if not found.
 func FindServerInCatalogs(ctx context.Context, dao DAO, serverName string) (catalog.Server, error) {
@@ -74,8 +127,27 @@ func (tools *ToolList) Scan(value any) error {
 	return json.Unmarshal([]byte(str), tools)
 }
 
+func (annotations Annotations) Value() (driver.Value, error) {
+	if annotations == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(annotations)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (annotations *Annotations) Scan(value any) error {
+	str, ok := value.(string)
+	if !ok {
+		return errors.New("failed to scan annotations")
+	}
+	return json.Unmarshal([]byte(str), annotations)
+}
+
 func (d *dao) GetCatalog(ctx context.Context, ref string) (*Catalog, error) {
-	const query = `SELECT ref, digest, title, source, last_updated FROM catalog WHERE ref = $1`
+	const query = `SELECT ref, digest, title, source, last_updated, base FROM catalog WHERE ref = $1`
 
 	var catalog Catalog
 	err := d.db.GetContext(ctx, &catalog, query, ref)
@@ -83,19 +155,24 @@ func (d *dao) GetCatalog(ctx context.Context, ref string) (*Catalog, error) {
 		return nil, err
 	}
 
-	const serverQuery = `SELECT id, server_type, tools, source, image, endpoint, catalog_ref, snapshot from catalog_server where catalog_ref = $1`
+	const serverQuery = `SELECT id, server_type, tools, source, image, endpoint, catalog_ref, long_lived_override, added_from, annotations, stable_id, snapshot from catalog_server where catalog_ref = $1`
 
 	var servers []CatalogServer
 	err = d.db.SelectContext(ctx, &servers, serverQuery, catalog.Ref)
 	if err != nil {
 		return nil, err
 	}
+	fillStableIDs(servers)
 	catalog.Servers = servers
 
 	return &catalog, nil
 }
 
 func (d *dao) UpsertCatalog(ctx context.Context, catalog Catalog) error {
+	return d.UpsertCatalogs(ctx, catalog)
+}
+
+func (d *dao) UpsertCatalogs(ctx context.Context, catalogs ...Catalog) error {
 	tx, err := d.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -103,16 +180,30 @@ func (d *dao) UpsertCatalog(ctx context.Context, catalog Catalog) error {
 
 	defer txClose(tx, &err)
 
+	for _, catalog := range catalogs {
+		if err = upsertCatalog(ctx, tx, catalog); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func upsertCatalog(ctx context.Context, tx *sqlx.Tx, catalog Catalog) error {
 	const deleteQuery = `DELETE FROM catalog WHERE ref = $1`
 
-	_, err = tx.ExecContext(ctx, deleteQuery, catalog.Ref)
+	_, err := tx.ExecContext(ctx, deleteQuery, catalog.Ref)
 	if err != nil {
 		return err
 	}
 
-	const insertQuery = `INSERT INTO catalog (ref, digest, title, source, last_updated) VALUES ($1, $2, $3, $4, current_timestamp)`
+	const insertQuery = `INSERT INTO catalog (ref, digest, title, source, last_updated, base) VALUES ($1, $2, $3, $4, current_timestamp, $5)`
 
-	_, err = tx.ExecContext(ctx, insertQuery, catalog.Ref, catalog.Digest, catalog.Title, catalog.Source)
+	_, err = tx.ExecContext(ctx, insertQuery, catalog.Ref, catalog.Digest, catalog.Title, catalog.Source, catalog.Base)
 	if err != nil {
 		return err
 	}
@@ -120,20 +211,21 @@ func (d *dao) UpsertCatalog(ctx context.Context, catalog Catalog) error {
 	for i := range catalog.Servers {
 		catalog.Servers[i].CatalogRef = catalog.Ref
 	}
+	fillStableIDs(catalog.Servers)
 
 	if len(catalog.Servers) > 0 {
 		const serverQuery = `INSERT INTO catalog_server (
-		server_type, tools, source, image, endpoint, catalog_ref, snapshot
-	) VALUES (:server_type, :tools, :source, :image, :endpoint, :catalog_ref, :snapshot)`
+		server_type, tools, source, image, endpoint, catalog_ref, long_lived_override, added_from, annotations, stable_id, snapshot
+	) VALUES (:server_type, :tools, :source, :image, :endpoint, :catalog_ref, :long_lived_override, :added_from, :annotations, :stable_id, :snapshot)`
 
 		// Insert in batches. A slice passed to NamedExecContext expands into a
 		// single multi-row INSERT with one bound parameter per column per row,
 		// and SQLite caps the number of variables per statement at 32766
-		// (SQLITE_MAX_VARIABLE_NUMBER). With 7 columns per server, large
+		// (SQLITE_MAX_VARIABLE_NUMBER). With 11 columns per server, large
 		// catalogs (e.g. the community registry's thousands of servers) exceed
 		// that limit and fail with "too many SQL variables".
-		const columnsPerServer = 7
-		const batchSize = 32766 / columnsPerServer // 4680 servers per statement
+		const columnsPerServer = 11
+		const batchSize = 32766 / columnsPerServer // 2978 servers per statement
 		for start := 0; start < len(catalog.Servers); start += batchSize {
 			end := min(start+batchSize, len(catalog.Servers))
 			if _, err = tx.NamedExecContext(ctx, serverQuery, catalog.Servers[start:end]); err != nil {
@@ -142,10 +234,6 @@ func (d *dao) UpsertCatalog(ctx context.Context, catalog Catalog) error {
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -159,15 +247,42 @@ func (d *dao) DeleteCatalog(ctx context.Context, ref string) error {
 	return nil
 }
 
+func (d *dao) MoveCatalog(ctx context.Context, catalog Catalog, fromRef string) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer txClose(tx, &err)
+
+	if err = upsertCatalog(ctx, tx, catalog); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM catalog WHERE ref = $1`, fromRef); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE catalog_alias SET ref = $1 WHERE ref = $2`, catalog.Ref, fromRef); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (d *dao) ListCatalogs(ctx context.Context) ([]Catalog, error) {
 	type catalogRow struct {
 		Catalog
 		ServerJSON string `db:"server_json"`
 	}
 
-	const query = `SELECT c.ref, c.digest, c.title, c.source, c.last_updated,
+	const query = `SELECT c.ref, c.digest, c.title, c.source, c.last_updated, c.base,
 	COALESCE(
-		json_group_array(json_object('id', s.id, 'server_type', s.server_type, 'tools', json(s.tools), 'source', s.source, 'image', s.image, 'endpoint', s.endpoint, 'snapshot', json(s.snapshot))),
+		json_group_array(json_object('id', s.id, 'server_type', s.server_type, 'tools', json(s.tools), 'source', s.source, 'image', s.image, 'endpoint', s.endpoint, 'long_lived_override', CASE s.long_lived_override WHEN 1 THEN json('true') WHEN 0 THEN json('false') END, 'added_from', s.added_from, 'annotations', json(s.annotations), 'stable_id', s.stable_id, 'snapshot', json(s.snapshot))),
 		'[]'
 	) AS server_json
 	FROM catalog c
@@ -185,6 +300,7 @@ func (d *dao) ListCatalogs(ctx context.Context) ([]Catalog, error) {
 		if err := json.Unmarshal([]byte(row.ServerJSON), &row.Servers); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal servers: %w", err)
 		}
+		fillStableIDs(row.Servers)
 		catalogs[i] = row.Catalog
 	}
 
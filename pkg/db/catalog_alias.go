@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var catalogAliasPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type CatalogAliasDAO interface {
+	GetCatalogAlias(ctx context.Context, alias string) (*CatalogAlias, error)
+	SetCatalogAlias(ctx context.Context, alias CatalogAlias) error
+	DeleteCatalogAlias(ctx context.Context, alias string) error
+	ListCatalogAliases(ctx context.Context) ([]CatalogAlias, error)
+}
+
+type CatalogAlias struct {
+	Alias string `db:"alias"`
+	Ref   string `db:"ref"`
+}
+
+// ValidCatalogAlias reports whether alias can be used as a catalog alias.
+// Aliases can't contain '/' or ':', so they never look like a full catalog
+// reference.
+func ValidCatalogAlias(alias string) bool {
+	return catalogAliasPattern.MatchString(alias)
+}
+
+// ResolveCatalogAlias returns the catalog reference registered for ref if it
+// is an alias, or ref unchanged otherwise. Every command looking up a catalog
+// by a reference given by the user resolves it with this function first.
+func ResolveCatalogAlias(ctx context.Context, dao CatalogAliasDAO, ref string) (string, error) {
+	if !ValidCatalogAlias(ref) {
+		return ref, nil
+	}
+
+	alias, err := dao.GetCatalogAlias(ctx, ref)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ref, nil
+		}
+		return "", fmt.Errorf("failed to resolve alias %s: %w", ref, err)
+	}
+	return alias.Ref, nil
+}
+
+func (d *dao) GetCatalogAlias(ctx context.Context, alias string) (*CatalogAlias, error) {
+	const query = `SELECT alias, ref FROM catalog_alias WHERE alias = $1`
+
+	var catalogAlias CatalogAlias
+	err := d.db.GetContext(ctx, &catalogAlias, query, alias)
+	if err != nil {
+		return nil, err
+	}
+	return &catalogAlias, nil
+}
+
+func (d *dao) SetCatalogAlias(ctx context.Context, alias CatalogAlias) error {
+	const query = `INSERT INTO catalog_alias (alias, ref) VALUES ($1, $2) ON CONFLICT(alias) DO UPDATE SET ref = excluded.ref`
+
+	_, err := d.db.ExecContext(ctx, query, alias.Alias, alias.Ref)
+	return err
+}
+
+func (d *dao) DeleteCatalogAlias(ctx context.Context, alias string) error {
+	const query = `DELETE FROM catalog_alias WHERE alias = $1`
+
+	_, err := d.db.ExecContext(ctx, query, alias)
+	return err
+}
+
+func (d *dao) ListCatalogAliases(ctx context.Context) ([]CatalogAlias, error) {
+	const query = `SELECT alias, ref FROM catalog_alias ORDER BY alias`
+
+	var aliases []CatalogAlias
+	err := d.db.SelectContext(ctx, &aliases, query)
+	if err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogAlias(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	_, err := dao.GetCatalogAlias(ctx, "community")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = dao.SetCatalogAlias(ctx, CatalogAlias{Alias: "community", Ref: "docker.io/mcp/community-registry:latest"})
+	require.NoError(t, err)
+
+	alias, err := dao.GetCatalogAlias(ctx, "community")
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/mcp/community-registry:latest", alias.Ref)
+
+	// Setting an existing alias replaces its target
+	err = dao.SetCatalogAlias(ctx, CatalogAlias{Alias: "community", Ref: "docker.io/mcp/community-registry:v2"})
+	require.NoError(t, err)
+	err = dao.SetCatalogAlias(ctx, CatalogAlias{Alias: "base", Ref: "docker.io/myorg/base:latest"})
+	require.NoError(t, err)
+
+	aliases, err := dao.ListCatalogAliases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogAlias{
+		{Alias: "base", Ref: "docker.io/myorg/base:latest"},
+		{Alias: "community", Ref: "docker.io/mcp/community-registry:v2"},
+	}, aliases)
+
+	err = dao.DeleteCatalogAlias(ctx, "community")
+	require.NoError(t, err)
+
+	_, err = dao.GetCatalogAlias(ctx, "community")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResolveCatalogBase returns the catalog with the servers it inherits from its
+// base catalog, and from the base of its base, and so on. The servers of a
+// derived catalog override the servers of its base with the same name.
+// Catalogs without a base are returned as-is.
+func ResolveCatalogBase(ctx context.Context, dao CatalogDAO, catalog *Catalog) (*Catalog, error) {
+	if catalog.Base == "" {
+		return catalog, nil
+	}
+
+	chain := []string{catalog.Ref}
+	resolved := *catalog
+	resolved.Servers = slices.Clone(catalog.Servers)
+
+	for base := catalog.Base; base != ""; {
+		if slices.Contains(chain, base) {
+			return nil, fmt.Errorf("catalog %s has a cycle in its base catalogs: %s -> %s", catalog.Ref, strings.Join(chain, " -> "), base)
+		}
+		chain = append(chain, base)
+
+		baseCatalog, err := dao.GetCatalog(ctx, base)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("base catalog %s of %s not found: pull it first", base, chain[len(chain)-2])
+			}
+			return nil, fmt.Errorf("failed to get base catalog %s: %w", base, err)
+		}
+
+		resolved.Servers = inheritServers(resolved.Servers, baseCatalog.Servers)
+		base = baseCatalog.Base
+	}
+
+	return &resolved, nil
+}
+
+// inheritServers appends the servers of a base catalog that aren't overridden
+// by a server with the same name.
+func inheritServers(servers []CatalogServer, baseServers []CatalogServer) []CatalogServer {
+	names := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		if server.Snapshot != nil {
+			names[server.Snapshot.Server.Name] = true
+		}
+	}
+
+	for _, server := range baseServers {
+		if server.Snapshot != nil && names[server.Snapshot.Server.Name] {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
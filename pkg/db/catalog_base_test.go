@@ -0,0 +1,141 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func baseTestServer(name, image string) CatalogServer {
+	return CatalogServer{
+		ServerType: "image",
+		Image:      image,
+		Snapshot:   &ServerSnapshot{Server: catalog.Server{Name: name, Image: image}},
+	}
+}
+
+func serverImages(catalog *Catalog) map[string]string {
+	images := make(map[string]string)
+	for _, server := range catalog.Servers {
+		images[server.Snapshot.Server.Name] = server.Image
+	}
+	return images
+}
+
+func TestResolveCatalogBase(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref:   "docker.io/myorg/base:latest",
+		Title: "Base",
+		Servers: []CatalogServer{
+			baseTestServer("fetch", "mcp/fetch:1"),
+			baseTestServer("time", "mcp/time:1"),
+		},
+	}))
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref:   "docker.io/myorg/derived:latest",
+		Title: "Derived",
+		Base:  "docker.io/myorg/base:latest",
+		Servers: []CatalogServer{
+			baseTestServer("fetch", "mcp/fetch:2"),
+			baseTestServer("github", "mcp/github:1"),
+		},
+	}))
+
+	derived, err := dao.GetCatalog(ctx, "docker.io/myorg/derived:latest")
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/myorg/base:latest", derived.Base)
+	assert.Len(t, derived.Servers, 2)
+
+	resolved, err := ResolveCatalogBase(ctx, dao, derived)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"fetch":  "mcp/fetch:2",
+		"github": "mcp/github:1",
+		"time":   "mcp/time:1",
+	}, serverImages(resolved))
+	// Own servers come first.
+	assert.Equal(t, "fetch", resolved.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, "github", resolved.Servers[1].Snapshot.Server.Name)
+
+	// The stored catalog is left untouched.
+	assert.Len(t, derived.Servers, 2)
+}
+
+func TestResolveCatalogBaseChain(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref:     "docker.io/myorg/root:latest",
+		Servers: []CatalogServer{baseTestServer("time", "mcp/time:1"), baseTestServer("fetch", "mcp/fetch:1")},
+	}))
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref:     "docker.io/myorg/base:latest",
+		Base:    "docker.io/myorg/root:latest",
+		Servers: []CatalogServer{baseTestServer("time", "mcp/time:2")},
+	}))
+	derived := &Catalog{
+		Ref:     "docker.io/myorg/derived:latest",
+		Base:    "docker.io/myorg/base:latest",
+		Servers: []CatalogServer{baseTestServer("github", "mcp/github:1")},
+	}
+
+	resolved, err := ResolveCatalogBase(ctx, dao, derived)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"fetch":  "mcp/fetch:1",
+		"github": "mcp/github:1",
+		"time":   "mcp/time:2",
+	}, serverImages(resolved))
+}
+
+func TestResolveCatalogBaseCycle(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/myorg/a:latest", Base: "docker.io/myorg/b:latest"}))
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/myorg/b:latest", Base: "docker.io/myorg/a:latest"}))
+
+	a, err := dao.GetCatalog(ctx, "docker.io/myorg/a:latest")
+	require.NoError(t, err)
+
+	_, err = ResolveCatalogBase(ctx, dao, a)
+	require.ErrorContains(t, err, "cycle in its base catalogs: docker.io/myorg/a:latest -> docker.io/myorg/b:latest -> docker.io/myorg/a:latest")
+
+	// A catalog can't be its own base either.
+	_, err = ResolveCatalogBase(ctx, dao, &Catalog{Ref: "docker.io/myorg/c:latest", Base: "docker.io/myorg/c:latest"})
+	require.ErrorContains(t, err, "cycle in its base catalogs")
+}
+
+func TestResolveCatalogBaseNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	_, err := ResolveCatalogBase(t.Context(), dao, &Catalog{Ref: "docker.io/myorg/derived:latest", Base: "docker.io/myorg/base:latest"})
+	require.ErrorContains(t, err, "base catalog docker.io/myorg/base:latest of docker.io/myorg/derived:latest not found")
+}
+
+func TestListCatalogsBase(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/myorg/base:latest"}))
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/myorg/derived:latest", Base: "docker.io/myorg/base:latest"}))
+
+	catalogs, err := dao.ListCatalogs(ctx)
+	require.NoError(t, err)
+
+	bases := make(map[string]string)
+	for _, catalog := range catalogs {
+		bases[catalog.Ref] = catalog.Base
+	}
+	assert.Equal(t, map[string]string{
+		"docker.io/myorg/base:latest":    "",
+		"docker.io/myorg/derived:latest": "docker.io/myorg/base:latest",
+	}, bases)
+}
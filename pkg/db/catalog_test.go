@@ -49,8 +49,8 @@ func TestCreateCatalogWithManyServers(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
-	// More servers than fit in a single SQLite statement: with 7 bound
-	// parameters per server, anything over 32766/7 = 4680 servers would
+	// More servers than fit in a single SQLite statement: with 11 bound
+	// parameters per server, anything over 32766/11 = 2978 servers would
 	// overflow SQLITE_MAX_VARIABLE_NUMBER if inserted in one statement.
 	const serverCount = 10000
 	servers := make([]CatalogServer, serverCount)
@@ -133,6 +133,54 @@ func TestUpsertCatalogReplaceExisting(t *testing.T) {
 	assert.Equal(t, "https://example.com/second", retrieved.Source)
 }
 
+func TestUpsertCatalogs(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := CatalogServer{ServerType: "image", Image: "docker/test:latest"}
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/test/source:latest", Title: "Source", Servers: []CatalogServer{server}}))
+
+	err := dao.UpsertCatalogs(ctx,
+		Catalog{Ref: "docker.io/test/source:latest", Title: "Source", Servers: []CatalogServer{}},
+		Catalog{Ref: "docker.io/test/destination:latest", Title: "Destination", Servers: []CatalogServer{server}},
+	)
+	require.NoError(t, err)
+
+	source, err := dao.GetCatalog(ctx, "docker.io/test/source:latest")
+	require.NoError(t, err)
+	assert.Empty(t, source.Servers)
+	destination, err := dao.GetCatalog(ctx, "docker.io/test/destination:latest")
+	require.NoError(t, err)
+	require.Len(t, destination.Servers, 1)
+	assert.Equal(t, "docker/test:latest", destination.Servers[0].Image)
+}
+
+func TestMoveCatalog(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := CatalogServer{ServerType: "image", Image: "docker/test:latest"}
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{Ref: "docker.io/test/move:staging", Title: "Move", Servers: []CatalogServer{server}}))
+	require.NoError(t, dao.SetCatalogAlias(ctx, CatalogAlias{Alias: "staging", Ref: "docker.io/test/move:staging"}))
+	require.NoError(t, dao.SetCatalogAlias(ctx, CatalogAlias{Alias: "other", Ref: "docker.io/test/other:latest"}))
+
+	err := dao.MoveCatalog(ctx, Catalog{Ref: "docker.io/test/move:latest", Title: "Move", Servers: []CatalogServer{server}}, "docker.io/test/move:staging")
+	require.NoError(t, err)
+
+	moved, err := dao.GetCatalog(ctx, "docker.io/test/move:latest")
+	require.NoError(t, err)
+	require.Len(t, moved.Servers, 1)
+	_, err = dao.GetCatalog(ctx, "docker.io/test/move:staging")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	aliases, err := dao.ListCatalogAliases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogAlias{
+		{Alias: "other", Ref: "docker.io/test/other:latest"},
+		{Alias: "staging", Ref: "docker.io/test/move:latest"},
+	}, aliases)
+}
+
 func TestGetCatalogNotFound(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -229,6 +277,122 @@ func TestListCatalogs(t *testing.T) {
 	assert.True(t, digests["list3"])
 }
 
+func TestCatalogServerLongLivedOverride(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	longLived := true
+	ephemeral := false
+	err := dao.UpsertCatalog(ctx, Catalog{
+		Ref:    "docker.io/test/overrides:latest",
+		Digest: "overrides",
+		Title:  "Overrides",
+		Servers: []CatalogServer{
+			{ServerType: "image", Image: "mcp/fetch", LongLivedOverride: &longLived, Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "fetch"}}},
+			{ServerType: "image", Image: "mcp/github", LongLivedOverride: &ephemeral, Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "github"}}},
+			{ServerType: "image", Image: "mcp/time", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "time"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	assertOverrides := func(servers []CatalogServer) {
+		t.Helper()
+		require.Len(t, servers, 3)
+		overrides := map[string]*bool{}
+		for _, server := range servers {
+			overrides[server.Snapshot.Server.Name] = server.LongLivedOverride
+		}
+		assert.Equal(t, &longLived, overrides["fetch"])
+		assert.Equal(t, &ephemeral, overrides["github"])
+		assert.Nil(t, overrides["time"])
+	}
+
+	retrieved, err := dao.GetCatalog(ctx, "docker.io/test/overrides:latest")
+	require.NoError(t, err)
+	assertOverrides(retrieved.Servers)
+
+	catalogs, err := dao.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assertOverrides(catalogs[0].Servers)
+}
+
+func TestCatalogServerAnnotations(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.UpsertCatalog(ctx, Catalog{
+		Ref:    "docker.io/test/annotations:latest",
+		Digest: "annotations",
+		Title:  "Annotations",
+		Servers: []CatalogServer{
+			{ServerType: "image", Image: "mcp/fetch", Annotations: Annotations{"owner": "team-a", "ticket": "MCP-42"}, Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "fetch"}}},
+			{ServerType: "image", Image: "mcp/time", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "time"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	assertAnnotations := func(servers []CatalogServer) {
+		t.Helper()
+		require.Len(t, servers, 2)
+		annotations := map[string]Annotations{}
+		for _, server := range servers {
+			annotations[server.Snapshot.Server.Name] = server.Annotations
+		}
+		assert.Equal(t, Annotations{"owner": "team-a", "ticket": "MCP-42"}, annotations["fetch"])
+		assert.Empty(t, annotations["time"])
+	}
+
+	retrieved, err := dao.GetCatalog(ctx, "docker.io/test/annotations:latest")
+	require.NoError(t, err)
+	assertAnnotations(retrieved.Servers)
+
+	catalogs, err := dao.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assertAnnotations(catalogs[0].Servers)
+}
+
+func TestCatalogServerStableID(t *testing.T) {
+	d := setupTestDB(t)
+	ctx := t.Context()
+
+	err := d.UpsertCatalog(ctx, Catalog{
+		Ref:    "docker.io/test/stable-id:latest",
+		Digest: "stable-id",
+		Title:  "Stable ID",
+		Servers: []CatalogServer{
+			{ServerType: "image", Image: "mcp/fetch@sha256:0123456789abcdef", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "fetch"}}},
+			{ServerType: "remote", Endpoint: "https://docs.example.com/mcp", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "docs"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Servers stored before stable IDs get theirs when they're read.
+	_, err = d.(*dao).db.ExecContext(ctx, `UPDATE catalog_server SET stable_id = '' WHERE endpoint != ''`)
+	require.NoError(t, err)
+
+	assertStableIDs := func(servers []CatalogServer) {
+		t.Helper()
+		require.Len(t, servers, 2)
+		ids := map[string]string{}
+		for _, server := range servers {
+			ids[server.Snapshot.Server.Name] = server.StableID
+		}
+		assert.Equal(t, catalog.ImageServerID("mcp/fetch@sha256:0123456789abcdef"), ids["fetch"])
+		assert.Equal(t, catalog.RemoteServerID("https://docs.example.com/mcp"), ids["docs"])
+	}
+
+	retrieved, err := d.GetCatalog(ctx, "docker.io/test/stable-id:latest")
+	require.NoError(t, err)
+	assertStableIDs(retrieved.Servers)
+
+	catalogs, err := d.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assertStableIDs(catalogs[0].Servers)
+}
+
 func TestListCatalogsEmpty(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -27,6 +27,7 @@ import (
 type DAO interface {
 	WorkingSetDAO
 	CatalogDAO
+	CatalogAliasDAO
 	MigrationStatusDAO
 	PullRecordDAO
 
@@ -45,6 +46,7 @@ type options struct {
 	dbFile         string
 	migrationsFS   fs.FS
 	migrationsPath string
+	readOnly       bool
 }
 
 type Option func(o *options) error
@@ -56,6 +58,16 @@ func WithDatabaseFile(dbFile string) Option {
 	}
 }
 
+// WithReadOnly opens an existing database without writing to it: it's neither
+// created nor migrated, and New fails with an error wrapping os.ErrNotExist
+// when it doesn't exist.
+func WithReadOnly() Option {
+	return func(o *options) error {
+		o.readOnly = true
+		return nil
+	}
+}
+
 func WithMigrations(filesystem fs.FS, path string) Option {
 	return func(o *options) error {
 		o.migrationsFS = filesystem
@@ -80,6 +92,10 @@ func New(opts ...Option) (DAO, error) {
 		o.dbFile = dbFile
 	}
 
+	if o.readOnly {
+		return openReadOnly(o.dbFile)
+	}
+
 	ensureDirectoryExists(o.dbFile)
 
 	db, err := sql.Open("sqlite", "file:"+o.dbFile+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)")
@@ -111,6 +127,20 @@ func New(opts ...Option) (DAO, error) {
 	return &dao{db: sqlxDb}, nil
 }
 
+func openReadOnly(dbFile string) (DAO, error) {
+	if _, err := os.Stat(dbFile); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+dbFile+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	return &dao{db: sqlx.NewDb(db, "sqlite")}, nil
+}
+
 func (d *dao) Close() error {
 	return d.db.Close()
 }
@@ -31,3 +31,30 @@ func TestNewCreatesDirectoryWhenNotExists(t *testing.T) {
 	require.NoError(t, err, "Directory should exist after database creation")
 	assert.True(t, stat.IsDir(), "Created path should be a directory")
 }
+
+func TestNewReadOnly(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "nested", "test.db")
+
+	// A missing database is neither created nor its directory.
+	_, err := New(WithDatabaseFile(dbFile), WithReadOnly())
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoDirExists(t, filepath.Dir(dbFile))
+
+	dao, err := New(WithDatabaseFile(dbFile))
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(t.Context(), Catalog{Ref: "test/catalog:latest", Title: "Test"}))
+	require.NoError(t, dao.Close())
+
+	dao, err = New(WithDatabaseFile(dbFile), WithReadOnly())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	catalogs, err := dao.ListCatalogs(t.Context())
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assert.Equal(t, "test/catalog:latest", catalogs[0].Ref)
+
+	require.Error(t, dao.UpsertCatalog(t.Context(), Catalog{Ref: "test/other:latest", Title: "Other"}))
+}
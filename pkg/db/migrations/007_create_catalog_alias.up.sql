@@ -0,0 +1,4 @@
+create table catalog_alias (
+  alias text primary key,
+  ref text not null
+);
@@ -0,0 +1,2 @@
+-- Optional override of the snapshot's longLived flag. NULL keeps the snapshot value.
+ALTER TABLE catalog_server ADD COLUMN long_lived_override integer;
@@ -0,0 +1,2 @@
+-- Where the server was added from, e.g. docker://mcp/fetch or a registry URL. Empty when unknown.
+ALTER TABLE catalog_server ADD COLUMN added_from text NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Reference of the catalog whose servers a catalog inherits. Empty when none.
+ALTER TABLE catalog ADD COLUMN base text NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Free-form annotations for tooling, e.g. owner or ticket, as a JSON object.
+ALTER TABLE catalog_server ADD COLUMN annotations text NOT NULL DEFAULT '{}';
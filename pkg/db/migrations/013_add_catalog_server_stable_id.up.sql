@@ -0,0 +1,3 @@
+-- Identity of the server derived from what it runs, e.g. its image digest,
+-- rather than from its name. Rows inserted before are derived when read.
+ALTER TABLE catalog_server ADD COLUMN stable_id text NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Where a profile comes from: empty when it was created locally, "pulled"
+-- or "imported" otherwise.
+ALTER TABLE working_set ADD COLUMN origin text NOT NULL DEFAULT '';
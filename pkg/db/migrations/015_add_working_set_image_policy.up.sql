@@ -0,0 +1,3 @@
+-- Images the servers of a profile may use, as given when the profile was
+-- created. NULL when the profile has no image policy.
+ALTER TABLE working_set ADD COLUMN image_policy text;
@@ -14,6 +14,8 @@ type WorkingSetDAO interface {
 	ListWorkingSets(ctx context.Context) ([]WorkingSet, error)
 	CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error
 	UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error
+	UpdateWorkingSetServers(ctx context.Context, id string, update func(servers ServerList) ServerList) error
+	ImportWorkingSet(ctx context.Context, workingSet WorkingSet, catalogs ...Catalog) error
 	RemoveWorkingSet(ctx context.Context, id string) error
 	SearchWorkingSets(ctx context.Context, query string, workingSetID string) ([]WorkingSet, error)
 }
@@ -27,6 +29,12 @@ type WorkingSet struct {
 	Name    string     `db:"name"`
 	Servers ServerList `db:"servers"`
 	Secrets SecretMap  `db:"secrets"`
+	// Origin is empty for profiles created locally, or tells whether the
+	// profile was pulled or imported.
+	Origin string `db:"origin"`
+	// ImagePolicy restricts the images the servers of the working set may
+	// use. Nil when there's no restriction.
+	ImagePolicy *ImagePolicy `db:"image_policy"`
 }
 
 type Server struct {
@@ -37,10 +45,19 @@ type Server struct {
 	Source   string         `json:"source,omitempty"`
 	Image    string         `json:"image,omitempty"`
 	Endpoint string         `json:"endpoint,omitempty"`
+	Exec     string         `json:"exec,omitempty"`
+	Args     []string       `json:"args,omitempty"`
 
 	// CatalogRef is the catalog reference that this server was sourced from.
 	CatalogRef string `json:"catalog_ref,omitempty"`
 
+	// LongLivedOverride overrides the snapshot's longLived flag when set.
+	LongLivedOverride *bool `json:"long_lived_override,omitempty"`
+
+	// ToolResultTransforms are the expressions applied to the structured
+	// results of the server's tools, by tool name.
+	ToolResultTransforms map[string]string `json:"tool_result_transforms,omitempty"`
+
 	// Optional snapshot of the server schema
 	Snapshot *ServerSnapshot `json:"snapshot,omitempty"`
 }
@@ -49,6 +66,11 @@ type Secret struct {
 	Provider string `json:"provider"`
 }
 
+type ImagePolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
 type ServerSnapshot struct {
 	// TODO(cody): hacky reference to the same type that we use elsewhere
 	Server catalog.Server `json:"server"`
@@ -103,8 +125,28 @@ func (secrets *SecretMap) Scan(value any) error {
 	return json.Unmarshal([]byte(str), secrets)
 }
 
+// Used as a column in working_set
+func (policy *ImagePolicy) Value() (driver.Value, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (policy *ImagePolicy) Scan(value any) error {
+	str, ok := value.(string)
+	if !ok {
+		return errors.New("failed to scan image policy")
+	}
+	return json.Unmarshal([]byte(str), policy)
+}
+
 func (d *dao) GetWorkingSet(ctx context.Context, id string) (*WorkingSet, error) {
-	const query = `SELECT id, name, servers, secrets FROM working_set WHERE id = $1`
+	const query = `SELECT id, name, servers, secrets, origin, image_policy FROM working_set WHERE id = $1`
 
 	var workingSet WorkingSet
 	err := d.db.GetContext(ctx, &workingSet, query, id)
@@ -125,9 +167,9 @@ func (d *dao) RemoveWorkingSet(ctx context.Context, id string) error {
 }
 
 func (d *dao) CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
-	const query = `INSERT INTO working_set (id, name, servers, secrets) VALUES ($1, $2, $3, $4)`
+	const query = `INSERT INTO working_set (id, name, servers, secrets, origin, image_policy) VALUES ($1, $2, $3, $4, $5, $6)`
 
-	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets)
+	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets, workingSet.Origin, workingSet.ImagePolicy)
 	if err != nil {
 		return err
 	}
@@ -135,17 +177,76 @@ func (d *dao) CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error
 }
 
 func (d *dao) UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
-	const query = `UPDATE working_set SET name = $2, servers = $3, secrets = $4 WHERE id = $1`
+	const query = `UPDATE working_set SET name = $2, servers = $3, secrets = $4, origin = $5, image_policy = $6 WHERE id = $1`
 
-	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets)
+	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets, workingSet.Origin, workingSet.ImagePolicy)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// UpdateWorkingSetServers reads the servers of a working set, applies update
+// to them and writes them back in a single transaction, leaving the other
+// columns untouched.
+func (d *dao) UpdateWorkingSetServers(ctx context.Context, id string, update func(servers ServerList) ServerList) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txClose(tx, &err)
+
+	// Take the write lock before reading, so that no other writer can
+	// update the row between the SELECT and the UPDATE.
+	// https://github.com/mattn/go-sqlite3/issues/400#issuecomment-598953685
+	_, err = tx.ExecContext(ctx, "ROLLBACK; BEGIN IMMEDIATE")
+	if err != nil {
+		return err
+	}
+
+	var servers ServerList
+	err = tx.GetContext(ctx, &servers, `SELECT servers FROM working_set WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE working_set SET servers = $2 WHERE id = $1`, id, update(servers))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ImportWorkingSet stores the catalogs, replacing the ones with the same
+// references, and creates the working set or replaces the one with the same
+// ID, in a single transaction: either all of them are stored or none.
+func (d *dao) ImportWorkingSet(ctx context.Context, workingSet WorkingSet, catalogs ...Catalog) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txClose(tx, &err)
+
+	for _, catalog := range catalogs {
+		if err = upsertCatalog(ctx, tx, catalog); err != nil {
+			return err
+		}
+	}
+
+	const query = `INSERT INTO working_set (id, name, servers, secrets, origin, image_policy) VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(id) DO UPDATE SET name = excluded.name, servers = excluded.servers, secrets = excluded.secrets, origin = excluded.origin, image_policy = excluded.image_policy`
+
+	_, err = tx.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets, workingSet.Origin, workingSet.ImagePolicy)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (d *dao) ListWorkingSets(ctx context.Context) ([]WorkingSet, error) {
-	const query = `SELECT id, name, servers, secrets FROM working_set`
+	const query = `SELECT id, name, servers, secrets, origin, image_policy FROM working_set`
 
 	var workingSets []WorkingSet
 	err := d.db.SelectContext(ctx, &workingSets, query)
@@ -157,7 +258,7 @@ func (d *dao) ListWorkingSets(ctx context.Context) ([]WorkingSet, error) {
 
 func (d *dao) SearchWorkingSets(ctx context.Context, query string, workingSetID string) ([]WorkingSet, error) {
 	sqlQuery := `
-		SELECT id, name, servers, secrets
+		SELECT id, name, servers, secrets, origin, image_policy
 		FROM working_set
 		WHERE ($1 = '' OR id = $1)
 		  AND ($2 = '' OR EXISTS (
@@ -772,3 +772,45 @@ func TestSearchWorkingSetsWithEmptyServersList(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, results, 1)
 }
+
+func TestImportWorkingSet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.ImportWorkingSet(ctx, WorkingSet{ID: "dev", Name: "Dev", Servers: ServerList{}, Secrets: SecretMap{}, Origin: "imported"},
+		Catalog{Ref: "acme/tools:latest", Title: "Acme Tools"})
+	require.NoError(t, err)
+
+	// Importing again replaces the working set and the catalogs.
+	err = dao.ImportWorkingSet(ctx, WorkingSet{ID: "dev", Name: "Renamed", Servers: ServerList{}, Secrets: SecretMap{}, Origin: "imported"},
+		Catalog{Ref: "acme/tools:latest", Title: "Acme Tools v2"})
+	require.NoError(t, err)
+
+	workingSet, err := dao.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", workingSet.Name)
+	assert.Equal(t, "imported", workingSet.Origin)
+
+	catalog, err := dao.GetCatalog(ctx, "acme/tools:latest")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Tools v2", catalog.Title)
+}
+
+func TestWorkingSetImagePolicy(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, WorkingSet{ID: "none", Name: "None", Servers: ServerList{}, Secrets: SecretMap{}})
+	require.NoError(t, err)
+	workingSet, err := dao.GetWorkingSet(ctx, "none")
+	require.NoError(t, err)
+	assert.Nil(t, workingSet.ImagePolicy)
+
+	policy := &ImagePolicy{Allow: []string{"mcp/*"}, Deny: []string{"mcp/untrusted"}}
+	workingSet.ImagePolicy = policy
+	err = dao.UpdateWorkingSet(ctx, *workingSet)
+	require.NoError(t, err)
+	workingSet, err = dao.GetWorkingSet(ctx, "none")
+	require.NoError(t, err)
+	assert.Equal(t, policy, workingSet.ImagePolicy)
+}
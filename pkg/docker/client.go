@@ -34,6 +34,7 @@ type Client interface {
 	RemoveNetwork(ctx context.Context, name string) error
 	ConnectNetwork(ctx context.Context, networkName, container, hostname string) error
 	InspectVolume(ctx context.Context, name string) (volume.Volume, error)
+	Ping(ctx context.Context) error
 }
 
 type dockerClient struct {
@@ -57,6 +58,12 @@ func NewClient(cli command.Cli) Client {
 	}
 }
 
+// Ping checks that the Docker daemon can be reached.
+func (c *dockerClient) Ping(ctx context.Context) error {
+	_, err := c.apiClient().Ping(ctx)
+	return err
+}
+
 func RunningInDockerCE(ctx context.Context, dockerCli command.Cli) (bool, error) {
 	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
 		return false, nil
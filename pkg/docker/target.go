@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/opts"
+)
+
+// NewCliForTarget returns a Docker CLI that targets the given Docker host
+// (e.g. tcp://10.0.0.2:2375 or ssh://user@host) or named docker context
+// instead of the ones configured for the docker command. Only one of host and
+// contextName can be set.
+func NewCliForTarget(host, contextName string) (command.Cli, error) {
+	if host != "" && contextName != "" {
+		return nil, errors.New("cannot use both a Docker host and a Docker context")
+	}
+
+	options := cliflags.NewClientOptions()
+	if host != "" {
+		parsed, err := opts.ParseHost(false, host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Docker host %q: %w", host, err)
+		}
+		options.Hosts = []string{parsed}
+	}
+	options.Context = contextName
+
+	cli, err := command.NewDockerCli()
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.Initialize(options); err != nil {
+		return nil, err
+	}
+
+	if contextName != "" {
+		if _, err := cli.ContextStore().GetMetadata(contextName); err != nil {
+			return nil, fmt.Errorf("invalid Docker context %q: %w", contextName, err)
+		}
+	}
+
+	return cli, nil
+}
@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCliForTargetUsesHost(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	cli, err := NewCliForTarget("tcp://10.0.0.2:2375", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "tcp://10.0.0.2:2375", cli.DockerEndpoint().Host)
+	assert.Equal(t, "tcp://10.0.0.2:2375", cli.Client().DaemonHost())
+}
+
+func TestNewCliForTargetValidation(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	_, err := NewCliForTarget("tcp://10.0.0.2:2375", "remote")
+	require.ErrorContains(t, err, "cannot use both")
+
+	_, err = NewCliForTarget("http://10.0.0.2:2375", "")
+	require.ErrorContains(t, err, `invalid Docker host "http://10.0.0.2:2375"`)
+
+	_, err = NewCliForTarget("", "does-not-exist")
+	require.ErrorContains(t, err, `invalid Docker context "does-not-exist"`)
+}
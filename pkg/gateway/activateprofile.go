@@ -53,12 +53,14 @@ func (g *Gateway) convertWorkingSetToConfiguration(ctx context.Context, ws worki
 	toolsConfig := config.ToolsConfig{ServerTools: make(map[string][]string)}
 	serverNames := make([]string, 0)
 	servers := make(map[string]catalog.Server)
+	toolResultTransforms := make(map[string]map[string]string)
 
 	for _, server := range ws.Servers {
-		// Skip non-image/remote/registry servers
+		// Skip non-image/remote/registry/poci servers
 		if server.Type != workingset.ServerTypeImage &&
 			server.Type != workingset.ServerTypeRemote &&
-			server.Type != workingset.ServerTypeRegistry {
+			server.Type != workingset.ServerTypeRegistry &&
+			server.Type != workingset.ServerTypePOCI {
 			continue
 		}
 
@@ -70,7 +72,7 @@ func (g *Gateway) convertWorkingSetToConfiguration(ctx context.Context, ws worki
 		// Build secrets configs
 		namespace := ""
 		configs = append(configs, ServerSecretConfig{
-			Secrets:   server.Snapshot.Server.Secrets,
+			Secrets:   server.Snapshot.Server.StoredSecrets(),
 			OAuth:     server.Snapshot.Server.OAuth,
 			Namespace: namespace,
 		})
@@ -79,16 +81,26 @@ func (g *Gateway) convertWorkingSetToConfiguration(ctx context.Context, ws worki
 		if server.Tools != nil {
 			toolsConfig.ServerTools[serverName] = server.Tools
 		}
+
+		if len(server.ToolResultTransforms) > 0 {
+			if err := validateToolResultTransforms(serverName, server.ToolResultTransforms); err != nil {
+				return Configuration{}, err
+			}
+			toolResultTransforms[serverName] = server.ToolResultTransforms
+		}
 	}
 
 	secrets := BuildSecretsURIs(ctx, configs)
 
 	return Configuration{
-		serverNames: serverNames,
-		servers:     servers,
-		config:      cfg,
-		tools:       toolsConfig,
-		secrets:     secrets,
+		serverNames:          serverNames,
+		servers:              servers,
+		config:               cfg,
+		tools:                toolsConfig,
+		secrets:              secrets,
+		toolResultTransforms: toolResultTransforms,
+		workingSet:           ws.ID,
+		profileOrigin:        ws.Origin,
 	}, nil
 }
 
@@ -102,6 +114,9 @@ func (g *Gateway) ActivateProfile(ctx context.Context, ws workingset.WorkingSet)
 	if err != nil {
 		return fmt.Errorf("failed to convert profile '%s': %w", ws.Name, err)
 	}
+	if err := profileConfig.resolveConfigFiles(g.ConfigFileDirs); err != nil {
+		return fmt.Errorf("failed to read the config files of profile '%s': %w", ws.Name, err)
+	}
 
 	// Filter servers: only activate servers that are not already active
 	var serversToActivate []string
@@ -138,11 +153,9 @@ func (g *Gateway) ActivateProfile(ctx context.Context, ws workingset.WorkingSet)
 		validation := serverValidation{serverName: serverName}
 
 		// Check if all required secrets are set
-		for _, secret := range serverConfig.Secrets {
-			if value, exists := profileConfig.secrets[secret.Name]; !exists || value == "" {
-				validation.missingSecrets = append(validation.missingSecrets, secret.Name)
-			}
-		}
+		validation.missingSecrets = unsetSecrets(&serverConfig, profileConfig.secrets, map[string]any{
+			oci.CanonicalizeServerName(serverName): profileConfig.config[serverName],
+		})
 
 		// Check if all required config values are set and validate against schema
 		if len(serverConfig.Config) > 0 {
@@ -204,6 +217,14 @@ func (g *Gateway) ActivateProfile(ctx context.Context, ws workingset.WorkingSet)
 			g.configuration.tools.ServerTools[serverName] = tools
 		}
 
+		// Merge tool result transforms
+		if transforms, exists := profileConfig.toolResultTransforms[serverName]; exists {
+			if g.configuration.toolResultTransforms == nil {
+				g.configuration.toolResultTransforms = make(map[string]map[string]string)
+			}
+			g.configuration.toolResultTransforms[serverName] = transforms
+		}
+
 		// Reload server capabilities
 		oldCaps, err := g.reloadServerCapabilities(ctx, serverName, nil)
 		if err != nil {
@@ -49,32 +49,17 @@ func getOrGenerateAuthToken() (string, bool, error) {
 // authenticationMiddleware creates an HTTP middleware that validates requests using
 // Bearer token in the Authorization header.
 //
-// The /health endpoint is excluded from authentication.
+// The /health and /healthz endpoints are excluded from authentication. /healthz
+// only reports the liveness of the gateway to unauthenticated requests.
 func authenticationMiddleware(authToken string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip authentication for health check endpoint
-		if r.URL.Path == "/health" {
+		// Skip authentication for health check endpoints
+		if r.URL.Path == "/health" || r.URL.Path == "/healthz" {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		authenticated := false
-
-		// Check for Bearer token in Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			// Extract Bearer token from "Bearer <token>" format
-			const bearerPrefix = "Bearer "
-			if len(authHeader) > len(bearerPrefix) && authHeader[:len(bearerPrefix)] == bearerPrefix {
-				bearerToken := authHeader[len(bearerPrefix):]
-				// Use constant-time comparison to prevent timing attacks
-				if subtle.ConstantTimeCompare([]byte(bearerToken), []byte(authToken)) == 1 {
-					authenticated = true
-				}
-			}
-		}
-
-		if !authenticated {
+		if !isAuthenticated(r, authToken) {
 			// Return 401 Unauthorized with WWW-Authenticate header
 			w.Header().Set("WWW-Authenticate", `Bearer realm="MCP Gateway"`)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -86,6 +71,25 @@ func authenticationMiddleware(authToken string, next http.Handler) http.Handler
 	})
 }
 
+// isAuthenticated reports whether a request has the Bearer token in its
+// Authorization header.
+func isAuthenticated(r *http.Request, authToken string) bool {
+	// Check for Bearer token in Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return false
+	}
+
+	// Extract Bearer token from "Bearer <token>" format
+	const bearerPrefix = "Bearer "
+	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
+		return false
+	}
+	bearerToken := authHeader[len(bearerPrefix):]
+	// Use constant-time comparison to prevent timing attacks
+	return subtle.ConstantTimeCompare([]byte(bearerToken), []byte(authToken)) == 1
+}
+
 // formatGatewayURL formats the gateway URL without authentication info
 func formatGatewayURL(port int, endpoint string) string {
 	return fmt.Sprintf("http://localhost:%d%s", port, endpoint)
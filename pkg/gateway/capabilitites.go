@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"slices"
@@ -30,10 +31,15 @@ type ToolRegistration struct {
 	Handler    mcp.ToolHandler
 }
 
+// CompletionHandler completes the arguments of a prompt or resource template.
+type CompletionHandler func(context.Context, *mcp.CompleteRequest) (*mcp.CompleteResult, error)
+
 type PromptRegistration struct {
 	ServerName string
 	Prompt     *mcp.Prompt
 	Handler    mcp.PromptHandler
+	// Complete completes the arguments of the prompt, if not nil.
+	Complete CompletionHandler
 }
 
 type ResourceRegistration struct {
@@ -46,6 +52,8 @@ type ResourceTemplateRegistration struct {
 	ServerName       string
 	ResourceTemplate mcp.ResourceTemplate
 	Handler          mcp.ResourceHandler
+	// Complete completes the arguments of the URI template, if not nil.
+	Complete CompletionHandler
 }
 
 func filterCapabilitiesByAllowedTools(caps *Capabilities, toolAllowed []bool) *Capabilities {
@@ -136,110 +144,36 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 
 	errs, ctx := errgroup.WithContext(ctx)
 	errs.SetLimit(runtime.NumCPU())
-	for _, serverName := range serverNames {
+
+	// Servers with a higher priority are up before the others start.
+	var (
+		tier         sync.WaitGroup
+		tierPriority int
+	)
+	for i, serverName := range g.byStartPriority(serverNames) {
 		serverConfig, toolGroup, found := g.configuration.Find(serverName)
+		if priority := g.configuration.startPriority(serverName); i == 0 || priority != tierPriority {
+			tier.Wait()
+			tierPriority = priority
+		}
 
 		switch {
 		case !found:
 			log.Log("  - MCP server not found:", serverName)
+			g.setServerFailed(serverName, true)
+			g.recordServerError(serverName, errors.New("not found in any catalog"))
 
 		// It's an MCP Server
 		case serverConfig != nil:
+			tier.Add(1)
 			errs.Go(func() error {
-				client, err := g.clientPool.AcquireClient(ctx, serverConfig, clientConfig)
-				if err != nil {
-					log.Logf("  > Can't start %s: %s", serverConfig.Name, err)
-					return nil
-				}
-				defer g.clientPool.ReleaseClient(client)
-
-				var capabilities Capabilities
-
-				tools, err := client.Session().ListTools(ctx, &mcp.ListToolsParams{})
-				if err != nil {
-					log.Logf("  > Can't list tools %s: %s", serverConfig.Name, err)
-				} else {
-					// Record the number of tools discovered from this server
-					telemetry.RecordToolList(ctx, serverConfig.Name, len(tools.Tools))
-
-					// Determine the prefix to use for this server's tools
-					prefix := g.getToolNamePrefix(serverConfig)
-
-					for _, tool := range tools.Tools {
-						if !isToolEnabled(g.configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
-							continue
-						}
-
-						// Create a copy of the tool and apply prefix to its name
-						prefixedTool := *tool
-						prefixedTool.Name = prefixToolName(prefix, tool.Name)
-
-						capabilities.Tools = append(capabilities.Tools, ToolRegistration{
-							ServerName: serverConfig.Name,
-							Tool:       &prefixedTool,
-							Handler:    g.mcpServerToolHandler(serverConfig.Name, g.mcpServer, tool.Annotations, tool.Name),
-						})
-					}
-				}
+				defer tier.Done()
 
-				prompts, err := client.Session().ListPrompts(ctx, &mcp.ListPromptsParams{})
-				if err == nil {
-					// Record the number of prompts discovered from this server
-					telemetry.RecordPromptList(ctx, serverConfig.Name, len(prompts.Prompts))
-
-					for _, prompt := range prompts.Prompts {
-						capabilities.Prompts = append(capabilities.Prompts, PromptRegistration{
-							ServerName: serverConfig.Name,
-							Prompt:     prompt,
-							Handler:    g.mcpServerPromptHandler(serverConfig.Name, g.mcpServer),
-						})
-					}
-				}
-
-				resources, err := client.Session().ListResources(ctx, &mcp.ListResourcesParams{})
-				if err == nil {
-					// Record the number of resources discovered from this server
-					telemetry.RecordResourceList(ctx, serverConfig.Name, len(resources.Resources))
-
-					for _, resource := range resources.Resources {
-						capabilities.Resources = append(capabilities.Resources, ResourceRegistration{
-							ServerName: serverConfig.Name,
-							Resource:   resource,
-							Handler:    g.mcpServerResourceHandler(serverConfig.Name, g.mcpServer),
-						})
-					}
-				}
-
-				resourceTemplates, err := client.Session().ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
-				if err == nil {
-					// Record the number of resource templates discovered from this server
-					telemetry.RecordResourceTemplateList(ctx, serverConfig.Name, len(resourceTemplates.ResourceTemplates))
-
-					for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
-						capabilities.ResourceTemplates = append(capabilities.ResourceTemplates, ResourceTemplateRegistration{
-							ServerName:       serverConfig.Name,
-							ResourceTemplate: *resourceTemplate,
-							Handler:          g.mcpServerResourceHandler(serverConfig.Name, g.mcpServer),
-						})
-					}
-				}
-
-				var logMsg string
-				if len(capabilities.Tools) > 0 {
-					logMsg += fmt.Sprintf(" (%d tools)", len(capabilities.Tools))
-				}
-				if len(capabilities.Prompts) > 0 {
-					logMsg += fmt.Sprintf(" (%d prompts)", len(capabilities.Prompts))
-				}
-				if len(capabilities.Resources) > 0 {
-					logMsg += fmt.Sprintf(" (%d resources)", len(capabilities.Resources))
-				}
-				if len(capabilities.ResourceTemplates) > 0 {
-					logMsg += fmt.Sprintf(" (%d resourceTemplates)", len(capabilities.ResourceTemplates))
-				}
-				if logMsg != "" {
-					log.Logf("  > %s:%s", serverConfig.Name, logMsg)
+				capabilities, ok := g.discoverServerCapabilities(ctx, serverConfig, clientConfig)
+				if !ok {
+					return nil
 				}
+				g.setServerFailed(serverConfig.Name, false)
 
 				lock.Lock()
 				allCapabilities = append(allCapabilities, capabilities)
@@ -319,6 +253,163 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 	}, nil
 }
 
+// discoverServerCapabilities lists the capabilities of a server within the
+// discovery timeout. A server that doesn't initialize or list its capabilities
+// in time is marked as failed, rather than holding back the gateway's startup.
+func (g *Gateway) discoverServerCapabilities(ctx context.Context, serverConfig *catalog.ServerConfig, clientConfig *clientConfig) (Capabilities, bool) {
+	if g.DiscoveryTimeout <= 0 {
+		return g.listServerCapabilities(ctx, serverConfig, clientConfig)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.DiscoveryTimeout)
+	defer cancel()
+
+	type discovery struct {
+		capabilities Capabilities
+		ok           bool
+	}
+	done := make(chan discovery, 1)
+	go func() {
+		capabilities, ok := g.listServerCapabilities(ctx, serverConfig, clientConfig)
+		done <- discovery{capabilities: capabilities, ok: ok}
+	}()
+
+	select {
+	case result := <-done:
+		// Capabilities listed after the deadline may be missing some.
+		if !result.ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result.capabilities, result.ok
+		}
+	case <-ctx.Done():
+	}
+
+	err := fmt.Errorf("no response within the discovery timeout of %s", g.DiscoveryTimeout)
+	log.Logf("  > Can't start %s: %s", serverConfig.Name, err)
+	g.setServerFailed(serverConfig.Name, true)
+	g.recordServerError(serverConfig.Name, err)
+	return Capabilities{}, false
+}
+
+// listServerCapabilities starts a server, or reuses a running one, and lists
+// its capabilities. It returns false if the server couldn't be started.
+func (g *Gateway) listServerCapabilities(ctx context.Context, serverConfig *catalog.ServerConfig, clientConfig *clientConfig) (Capabilities, bool) {
+	client, err := g.clientPool.AcquireClient(ctx, serverConfig, clientConfig)
+	if err != nil {
+		log.Logf("  > Can't start %s: %s", serverConfig.Name, err)
+		g.setServerFailed(serverConfig.Name, true)
+		g.recordServerError(serverConfig.Name, err)
+		return Capabilities{}, false
+	}
+	defer g.clientPool.ReleaseClient(client)
+	g.recordServerExperimental(serverConfig.Name, client.Session())
+
+	var capabilities Capabilities
+
+	tools, err := listAllTools(ctx, client.Session())
+	if err != nil {
+		log.Logf("  > Can't list tools %s: %s", serverConfig.Name, err)
+	} else {
+		// Record the number of tools discovered from this server
+		telemetry.RecordToolList(ctx, serverConfig.Name, len(tools))
+
+		// Determine the prefix to use for this server's tools
+		prefix := g.getToolNamePrefix(serverConfig)
+
+		for _, tool := range tools {
+			if !isToolEnabled(g.configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
+				continue
+			}
+
+			// Create a copy of the tool and apply prefix to its name
+			prefixedTool := *tool
+			prefixedTool.Name = prefixToolName(prefix, tool.Name)
+			// Transformed results no longer match the tool's output schema.
+			if _, ok := g.toolResultTransform(serverConfig.Name, tool.Name); ok {
+				prefixedTool.OutputSchema = nil
+			}
+
+			capabilities.Tools = append(capabilities.Tools, ToolRegistration{
+				ServerName: serverConfig.Name,
+				Tool:       &prefixedTool,
+				Handler:    g.mcpServerToolHandler(serverConfig.Name, g.mcpServer, tool.Annotations, tool.Name),
+			})
+		}
+	}
+
+	if !g.capabilityDisabled(CapabilityPrompts) {
+		prompts, err := client.Session().ListPrompts(ctx, &mcp.ListPromptsParams{})
+		if err == nil {
+			// Record the number of prompts discovered from this server
+			telemetry.RecordPromptList(ctx, serverConfig.Name, len(prompts.Prompts))
+
+			for _, prompt := range prompts.Prompts {
+				capabilities.Prompts = append(capabilities.Prompts, PromptRegistration{
+					ServerName: serverConfig.Name,
+					Prompt:     prompt,
+					Handler:    g.mcpServerPromptHandler(serverConfig.Name, g.mcpServer),
+					Complete:   g.mcpServerCompletionHandler(serverConfig.Name, g.mcpServer),
+				})
+			}
+		}
+	}
+
+	if !g.capabilityDisabled(CapabilityResources) {
+		resources, truncated, err := listAllResources(ctx, client.Session(), g.MaxResourcesPerServer)
+		if err == nil {
+			if truncated {
+				log.Logf("  > Warning: %s lists more than %d resources, only the first %d are exposed", serverConfig.Name, g.MaxResourcesPerServer, g.MaxResourcesPerServer)
+			}
+
+			// Record the number of resources discovered from this server
+			telemetry.RecordResourceList(ctx, serverConfig.Name, len(resources))
+
+			for _, resource := range resources {
+				capabilities.Resources = append(capabilities.Resources, ResourceRegistration{
+					ServerName: serverConfig.Name,
+					Resource:   resource,
+					Handler:    g.mcpServerResourceHandler(serverConfig.Name, g.mcpServer),
+				})
+			}
+		}
+	}
+
+	if !g.capabilityDisabled(CapabilityResourceTemplates) {
+		resourceTemplates, err := client.Session().ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
+		if err == nil {
+			// Record the number of resource templates discovered from this server
+			telemetry.RecordResourceTemplateList(ctx, serverConfig.Name, len(resourceTemplates.ResourceTemplates))
+
+			for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
+				capabilities.ResourceTemplates = append(capabilities.ResourceTemplates, ResourceTemplateRegistration{
+					ServerName:       serverConfig.Name,
+					ResourceTemplate: *resourceTemplate,
+					Handler:          g.mcpServerResourceHandler(serverConfig.Name, g.mcpServer),
+					Complete:         g.mcpServerCompletionHandler(serverConfig.Name, g.mcpServer),
+				})
+			}
+		}
+	}
+
+	var logMsg string
+	if len(capabilities.Tools) > 0 {
+		logMsg += fmt.Sprintf(" (%d tools)", len(capabilities.Tools))
+	}
+	if len(capabilities.Prompts) > 0 {
+		logMsg += fmt.Sprintf(" (%d prompts)", len(capabilities.Prompts))
+	}
+	if len(capabilities.Resources) > 0 {
+		logMsg += fmt.Sprintf(" (%d resources)", len(capabilities.Resources))
+	}
+	if len(capabilities.ResourceTemplates) > 0 {
+		logMsg += fmt.Sprintf(" (%d resourceTemplates)", len(capabilities.ResourceTemplates))
+	}
+	if logMsg != "" {
+		log.Logf("  > %s:%s", serverConfig.Name, logMsg)
+	}
+
+	return capabilities, true
+}
+
 func (caps *Capabilities) ToolNames() []string {
 	var names []string
 	for _, tool := range caps.Tools {
@@ -366,3 +457,13 @@ func isToolEnabled(configuration Configuration, serverName, serverImage, toolNam
 
 	return false
 }
+
+// byStartPriority returns the servers sorted by the priority they start with,
+// highest first. Servers with the same priority keep their order.
+func (g *Gateway) byStartPriority(serverNames []string) []string {
+	sorted := slices.Clone(serverNames)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		return g.configuration.startPriority(b) - g.configuration.startPriority(a)
+	})
+	return sorted
+}
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// startedServer returns a client for an in-memory server named name, which
+// takes delay to list its tools and is then appended to started.
+func startedServer(t *testing.T, name string, delay time.Duration, mu *sync.Mutex, started *[]string) *sessionClient {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: name, Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "tools/list" {
+				time.Sleep(delay)
+				mu.Lock()
+				*started = append(*started, name)
+				mu.Unlock()
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}
+}
+
+func TestServersStartByPriority(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		started []string
+	)
+
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"low", "default", "critical", "high"},
+			servers: map[string]catalog.Server{
+				"low":      {Name: "low", Image: "mcp/low", Priority: -1},
+				"default":  {Name: "default", Image: "mcp/default"},
+				"critical": {Name: "critical", Image: "mcp/critical", Priority: 10},
+				"high":     {Name: "high", Image: "mcp/high", Priority: 5},
+			},
+		},
+		clientPool: &clientPool{keptClients: map[clientKey]keptClient{}},
+	}
+	// The servers with a higher priority are the slowest to start, so they
+	// would be up last if all the servers started at once.
+	delays := map[string]time.Duration{
+		"critical": 60 * time.Millisecond,
+		"high":     40 * time.Millisecond,
+		"default":  20 * time.Millisecond,
+		"low":      0,
+	}
+	for name, delay := range delays {
+		getter := &clientGetter{client: startedServer(t, name, delay, &mu, &started)}
+		getter.once.Do(func() {}) // mark as created
+		g.clientPool.keptClients[clientKey{serverName: name}] = keptClient{Name: name, Getter: getter}
+	}
+
+	_, err := g.listCapabilities(t.Context(), g.configuration.serverNames, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"critical", "high", "default", "low"}, started)
+}
+
+func TestByStartPriority(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			servers: map[string]catalog.Server{
+				"a": {Name: "a"},
+				"b": {Name: "b", Priority: 1},
+				"c": {Name: "c"},
+				"d": {Name: "d", Priority: 1},
+			},
+		},
+	}
+
+	// Servers with the same priority, or not found, keep their order.
+	assert.Equal(t, []string{"b", "d", "c", "missing", "a"}, g.byStartPriority([]string{"c", "b", "missing", "a", "d"}))
+}
+
+// hangingClientGetter returns a client getter stuck creating its client, as
+// for a server that never answers initialize, until the test ends.
+func hangingClientGetter(t *testing.T) *clientGetter {
+	t.Helper()
+
+	getter := &clientGetter{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go getter.once.Do(func() {
+		close(started)
+		<-release
+		getter.err = errors.New("stopped")
+	})
+	<-started
+	t.Cleanup(func() { close(release) })
+	return getter
+}
+
+func TestListCapabilitiesSkipsServersPastDiscoveryTimeout(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{DiscoveryTimeout: 100 * time.Millisecond})
+	g.configuration.serverNames = append(g.configuration.serverNames, "hanging")
+	g.configuration.servers["hanging"] = catalog.Server{Name: "hanging", Image: "mcp/hanging"}
+	g.clientPool.keptClients[clientKey{serverName: "hanging"}] = keptClient{Name: "hanging", Getter: hangingClientGetter(t)}
+
+	start := time.Now()
+	caps, err := g.listCapabilities(t.Context(), g.configuration.serverNames, nil)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The other servers are still served.
+	require.Len(t, caps.Tools, 1)
+	assert.Equal(t, "upstream", caps.Tools[0].ServerName)
+
+	assert.True(t, g.failedServers["hanging"])
+	assert.False(t, g.failedServers["upstream"])
+	assert.Contains(t, g.serverLastErrors["hanging"], "no response within the discovery timeout of 100ms")
+}
+
+func TestRunRejectsNegativeDiscoveryTimeout(t *testing.T) {
+	g := NewGateway(Config{Options: Options{DiscoveryTimeout: -time.Second}}, nil)
+
+	err := g.Run(t.Context())
+
+	assert.EqualError(t, err, "--discovery-timeout must not be negative")
+}
@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+func validateDisabledCapabilities(values []string) error {
+	for _, value := range values {
+		switch strings.TrimSpace(value) {
+		case CapabilityPrompts, CapabilityResources, CapabilityResourceTemplates:
+		default:
+			return fmt.Errorf("unknown --disable value %q, expected '%s', '%s' or '%s'", value, CapabilityPrompts, CapabilityResources, CapabilityResourceTemplates)
+		}
+	}
+	return nil
+}
+
+// capabilityDisabled reports whether the given capability type was disabled
+// with --disable, in which case servers are never asked to list it.
+func (g *Gateway) capabilityDisabled(capability string) bool {
+	return slices.ContainsFunc(g.DisabledCapabilities, func(value string) bool {
+		return strings.TrimSpace(value) == capability
+	})
+}
+
+// resourcesDisabled reports whether both resources and resource templates are
+// disabled. They share the resources capability in the initialize response.
+func (g *Gateway) resourcesDisabled() bool {
+	return g.capabilityDisabled(CapabilityResources) && g.capabilityDisabled(CapabilityResourceTemplates)
+}
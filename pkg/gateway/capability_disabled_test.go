@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// sessionClient is an mcpclient.Client wrapping an already connected session.
+type sessionClient struct {
+	session *mcp.ClientSession
+}
+
+func (c *sessionClient) Initialize(context.Context, *mcp.InitializeParams, bool, *mcp.ServerSession, *mcp.Server, mcpclient.CapabilityRefresher) error {
+	return nil
+}
+
+func (c *sessionClient) Session() *mcp.ClientSession { return c.session }
+
+func (c *sessionClient) GetClient() *mcp.Client { return nil }
+
+func (c *sessionClient) AddRoots([]*mcp.Root) {}
+
+// upstreamServer starts an in-memory MCP server exposing one of each
+// capability type and records the methods it receives.
+func upstreamServer(t *testing.T) (*sessionClient, func() []string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			mu.Lock()
+			methods = append(methods, method)
+			mu.Unlock()
+			return next(ctx, method, req)
+		}
+	})
+	server.AddTool(&mcp.Tool{Name: "tool", InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	server.AddPrompt(&mcp.Prompt{Name: "prompt"}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	server.AddResource(&mcp.Resource{Name: "resource", URI: "file:///resource"}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{}, nil
+	})
+	server.AddResourceTemplate(&mcp.ResourceTemplate{Name: "template", URITemplate: "file:///{name}"}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(methods)
+	}
+}
+
+func gatewayWithUpstream(t *testing.T, options Options) (*Gateway, func() []string) {
+	t.Helper()
+
+	client, methods := upstreamServer(t)
+
+	getter := &clientGetter{client: client}
+	getter.once.Do(func() {}) // mark as created
+
+	g := &Gateway{
+		Options: options,
+		configuration: Configuration{
+			serverNames: []string{"upstream"},
+			servers: map[string]catalog.Server{
+				"upstream": {Name: "upstream", Image: "mcp/upstream"},
+			},
+		},
+		clientPool: &clientPool{
+			keptClients: map[clientKey]keptClient{
+				{serverName: "upstream"}: {Name: "upstream", Getter: getter},
+			},
+		},
+	}
+	return g, methods
+}
+
+func TestDisabledCapabilitiesAreNotDiscovered(t *testing.T) {
+	g, methods := gatewayWithUpstream(t, Options{
+		DisabledCapabilities: []string{CapabilityPrompts, CapabilityResourceTemplates},
+	})
+
+	caps, err := g.listCapabilities(t.Context(), []string{"upstream"}, nil)
+	require.NoError(t, err)
+
+	assert.Len(t, caps.Tools, 1)
+	assert.Empty(t, caps.Prompts)
+	assert.Len(t, caps.Resources, 1)
+	assert.Empty(t, caps.ResourceTemplates)
+
+	assert.Contains(t, methods(), "resources/list")
+	assert.NotContains(t, methods(), "prompts/list")
+	assert.NotContains(t, methods(), "resources/templates/list")
+}
+
+func TestNoCapabilitiesDisabledByDefault(t *testing.T) {
+	g, methods := gatewayWithUpstream(t, Options{})
+
+	caps, err := g.listCapabilities(t.Context(), []string{"upstream"}, nil)
+	require.NoError(t, err)
+
+	assert.Len(t, caps.Tools, 1)
+	assert.Len(t, caps.Prompts, 1)
+	assert.Len(t, caps.Resources, 1)
+	assert.Len(t, caps.ResourceTemplates, 1)
+	assert.Subset(t, methods(), []string{"tools/list", "prompts/list", "resources/list", "resources/templates/list"})
+}
+
+func TestDisabledCapabilitiesAreNotAnnounced(t *testing.T) {
+	g := &Gateway{Options: Options{
+		DisabledCapabilities: []string{CapabilityPrompts, CapabilityResources, CapabilityResourceTemplates},
+	}}
+
+	result := initializeResultFor(t, g, registerToolOnly)
+
+	require.NotNil(t, result.Capabilities)
+	assert.NotNil(t, result.Capabilities.Tools)
+	assert.Nil(t, result.Capabilities.Prompts)
+	assert.Nil(t, result.Capabilities.Resources)
+}
+
+func TestValidateDisabledCapabilities(t *testing.T) {
+	require.NoError(t, validateDisabledCapabilities(nil))
+	require.NoError(t, validateDisabledCapabilities([]string{CapabilityPrompts, CapabilityResources, CapabilityResourceTemplates}))
+	require.Error(t, validateDisabledCapabilities([]string{"tools"}))
+	require.Error(t, validateDisabledCapabilities([]string{"prompt"}))
+}
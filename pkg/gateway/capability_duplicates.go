@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+func validateDuplicateCapabilities(value string) error {
+	switch value {
+	case "", DuplicateCapabilitiesError, DuplicateCapabilitiesPrefix, DuplicateCapabilitiesFirstWins:
+		return nil
+	default:
+		return fmt.Errorf("unknown --duplicate-capabilities value %q, expected '%s', '%s' or '%s'", value, DuplicateCapabilitiesError, DuplicateCapabilitiesPrefix, DuplicateCapabilitiesFirstWins)
+	}
+}
+
+// resolveDuplicateCapabilities applies the configured duplicate handling to
+// the prompts, resources and resource templates in caps. Capabilities already
+// registered by other servers (existing) always take precedence. Registrations
+// are considered in server name order so that the outcome is deterministic.
+//
+// With DuplicateCapabilitiesError, caps is returned unchanged and the
+// collision validation reports the duplicates.
+func resolveDuplicateCapabilities(caps *Capabilities, existing capabilityNameIndexes, mode string) *Capabilities {
+	if caps == nil || mode == "" || mode == DuplicateCapabilitiesError {
+		return caps
+	}
+
+	return &Capabilities{
+		Tools:             caps.Tools,
+		Prompts:           resolveDuplicatePrompts(caps.Prompts, existing.Prompts, mode),
+		Resources:         resolveDuplicateResources(caps.Resources, existing.Resources),
+		ResourceTemplates: resolveDuplicateResourceTemplates(caps.ResourceTemplates, existing.ResourceTemplates),
+	}
+}
+
+func resolveDuplicatePrompts(prompts []PromptRegistration, existing map[string]string, mode string) []PromptRegistration {
+	sorted := append([]PromptRegistration(nil), prompts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ServerName < sorted[j].ServerName
+	})
+
+	counts := make(map[string]int, len(sorted))
+	for _, prompt := range sorted {
+		if prompt.Prompt != nil {
+			counts[prompt.Prompt.Name]++
+		}
+	}
+
+	resolved := make([]PromptRegistration, 0, len(sorted))
+	seen := make(map[string]string, len(sorted))
+	for _, prompt := range sorted {
+		if prompt.Prompt == nil {
+			continue
+		}
+		promptName := prompt.Prompt.Name
+		previous, registered := existing[promptName]
+		duplicated := registered || counts[promptName] > 1
+
+		switch {
+		case !duplicated:
+			resolved = append(resolved, prompt)
+		case mode == DuplicateCapabilitiesPrefix && prompt.ServerName != "":
+			resolved = append(resolved, prefixPromptRegistration(prompt))
+		default:
+			if !registered {
+				previous, registered = seen[promptName]
+			}
+			if registered {
+				log.Logf("  - Skipping prompt %q from %s: already provided by %s", promptName, capabilityOwner(prompt.ServerName), capabilityOwner(previous))
+				continue
+			}
+			seen[promptName] = prompt.ServerName
+			resolved = append(resolved, prompt)
+		}
+	}
+
+	return resolved
+}
+
+// prefixPromptRegistration exposes a prompt as <server>__<prompt> while still
+// requesting the original prompt name from the server.
+func prefixPromptRegistration(registration PromptRegistration) PromptRegistration {
+	originalName := registration.Prompt.Name
+	prefixedPrompt := *registration.Prompt
+	prefixedPrompt.Name = prefixToolName(registration.ServerName, originalName)
+
+	handler := registration.Handler
+	return PromptRegistration{
+		ServerName: registration.ServerName,
+		Prompt:     &prefixedPrompt,
+		Handler: func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			forwarded := *req
+			if req.Params != nil {
+				params := *req.Params
+				params.Name = originalName
+				forwarded.Params = &params
+			}
+			return handler(ctx, &forwarded)
+		},
+		Complete: prefixPromptCompletion(registration.Complete, originalName),
+	}
+}
+
+// Resource URIs identify the underlying data, so they are never rewritten:
+// duplicates are resolved with first-wins under both prefix and first-wins.
+func resolveDuplicateResources(resources []ResourceRegistration, existing map[string]string) []ResourceRegistration {
+	sorted := append([]ResourceRegistration(nil), resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ServerName < sorted[j].ServerName
+	})
+
+	resolved := make([]ResourceRegistration, 0, len(sorted))
+	seen := make(map[string]string, len(sorted))
+	for _, resource := range sorted {
+		if resource.Resource == nil {
+			continue
+		}
+		uri := resource.Resource.URI
+		previous, registered := existing[uri]
+		if !registered {
+			previous, registered = seen[uri]
+		}
+		if registered {
+			log.Logf("  - Skipping resource %q from %s: already provided by %s", uri, capabilityOwner(resource.ServerName), capabilityOwner(previous))
+			continue
+		}
+		seen[uri] = resource.ServerName
+		resolved = append(resolved, resource)
+	}
+
+	return resolved
+}
+
+func resolveDuplicateResourceTemplates(templates []ResourceTemplateRegistration, existing map[string]string) []ResourceTemplateRegistration {
+	sorted := append([]ResourceTemplateRegistration(nil), templates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ServerName < sorted[j].ServerName
+	})
+
+	resolved := make([]ResourceTemplateRegistration, 0, len(sorted))
+	seen := make(map[string]string, len(sorted))
+	for _, template := range sorted {
+		uriTemplate := template.ResourceTemplate.URITemplate
+		previous, registered := existing[uriTemplate]
+		if !registered {
+			previous, registered = seen[uriTemplate]
+		}
+		if registered {
+			log.Logf("  - Skipping resource template %q from %s: already provided by %s", uriTemplate, capabilityOwner(template.ServerName), capabilityOwner(previous))
+			continue
+		}
+		seen[uriTemplate] = template.ServerName
+		resolved = append(resolved, template)
+	}
+
+	return resolved
+}
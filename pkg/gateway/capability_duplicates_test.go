@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingPromptHandler(serverName string, calls *[]string) mcp.PromptHandler {
+	return func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		*calls = append(*calls, serverName+":"+req.Params.Name)
+		return &mcp.GetPromptResult{}, nil
+	}
+}
+
+func TestResolveDuplicatePromptsWithPrefix(t *testing.T) {
+	var calls []string
+	caps := &Capabilities{
+		Prompts: []PromptRegistration{
+			{ServerName: "server-b", Prompt: &mcp.Prompt{Name: "summarize"}, Handler: recordingPromptHandler("server-b", &calls)},
+			{ServerName: "server-a", Prompt: &mcp.Prompt{Name: "summarize"}, Handler: recordingPromptHandler("server-a", &calls)},
+			{ServerName: "server-a", Prompt: &mcp.Prompt{Name: "unique"}, Handler: recordingPromptHandler("server-a", &calls)},
+		},
+	}
+
+	resolved := resolveDuplicateCapabilities(caps, capabilityNameIndexes{}, DuplicateCapabilitiesPrefix)
+
+	assert.Equal(t, []string{"server-a__summarize", "unique", "server-b__summarize"}, resolved.PromptNames())
+	require.NoError(t, validateExternalCapabilityNameCollisions(resolved, capabilityNameIndexes{}, false))
+
+	// The prefixed prompt still requests the original name from its server.
+	prompt, err := resolved.getPromptByName("server-b__summarize")
+	require.NoError(t, err)
+	_, err = prompt.Handler(t.Context(), &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "server-b__summarize"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server-b:summarize"}, calls)
+
+	// The original registrations are left untouched.
+	assert.Equal(t, "summarize", caps.Prompts[0].Prompt.Name)
+}
+
+func TestResolveDuplicatePromptsWithPrefixAgainstExisting(t *testing.T) {
+	caps := &Capabilities{
+		Prompts: []PromptRegistration{
+			{ServerName: "server-b", Prompt: &mcp.Prompt{Name: "summarize"}},
+		},
+	}
+	existing := capabilityNameIndexes{Prompts: map[string]string{"summarize": "server-a"}}
+
+	resolved := resolveDuplicateCapabilities(caps, existing, DuplicateCapabilitiesPrefix)
+
+	assert.Equal(t, []string{"server-b__summarize"}, resolved.PromptNames())
+	require.NoError(t, validateExternalCapabilityNameCollisions(resolved, existing, false))
+}
+
+func TestResolveDuplicateResourcesFirstWins(t *testing.T) {
+	caps := &Capabilities{
+		Prompts: []PromptRegistration{
+			{ServerName: "server-b", Prompt: &mcp.Prompt{Name: "summarize"}},
+			{ServerName: "server-a", Prompt: &mcp.Prompt{Name: "summarize"}},
+		},
+		Resources: []ResourceRegistration{
+			{ServerName: "server-b", Resource: &mcp.Resource{URI: "file:///shared.txt"}},
+			{ServerName: "server-a", Resource: &mcp.Resource{URI: "file:///shared.txt"}},
+			{ServerName: "server-b", Resource: &mcp.Resource{URI: "file:///only-b.txt"}},
+		},
+		ResourceTemplates: []ResourceTemplateRegistration{
+			{ServerName: "server-b", ResourceTemplate: mcp.ResourceTemplate{URITemplate: "file:///{path}"}},
+			{ServerName: "server-a", ResourceTemplate: mcp.ResourceTemplate{URITemplate: "file:///{path}"}},
+		},
+	}
+
+	resolved := resolveDuplicateCapabilities(caps, capabilityNameIndexes{}, DuplicateCapabilitiesFirstWins)
+
+	require.Len(t, resolved.Prompts, 1)
+	assert.Equal(t, "server-a", resolved.Prompts[0].ServerName)
+
+	require.Len(t, resolved.Resources, 2)
+	assert.Equal(t, "server-a", resolved.Resources[0].ServerName)
+	assert.Equal(t, "file:///shared.txt", resolved.Resources[0].Resource.URI)
+	assert.Equal(t, "server-b", resolved.Resources[1].ServerName)
+	assert.Equal(t, "file:///only-b.txt", resolved.Resources[1].Resource.URI)
+
+	require.Len(t, resolved.ResourceTemplates, 1)
+	assert.Equal(t, "server-a", resolved.ResourceTemplates[0].ServerName)
+
+	require.NoError(t, validateExternalCapabilityNameCollisions(resolved, capabilityNameIndexes{}, false))
+}
+
+func TestResolveDuplicateResourcesFirstWinsAgainstExisting(t *testing.T) {
+	caps := &Capabilities{
+		Resources: []ResourceRegistration{
+			{ServerName: "server-a", Resource: &mcp.Resource{URI: "file:///shared.txt"}},
+		},
+	}
+	existing := capabilityNameIndexes{Resources: map[string]string{"file:///shared.txt": "server-b"}}
+
+	resolved := resolveDuplicateCapabilities(caps, existing, DuplicateCapabilitiesFirstWins)
+
+	assert.Empty(t, resolved.Resources)
+}
+
+func TestResolveDuplicateCapabilitiesError(t *testing.T) {
+	caps := &Capabilities{
+		Prompts: []PromptRegistration{
+			{ServerName: "server-a", Prompt: &mcp.Prompt{Name: "summarize"}},
+			{ServerName: "server-b", Prompt: &mcp.Prompt{Name: "summarize"}},
+		},
+	}
+
+	for _, mode := range []string{"", DuplicateCapabilitiesError} {
+		resolved := resolveDuplicateCapabilities(caps, capabilityNameIndexes{}, mode)
+
+		assert.Same(t, caps, resolved)
+		err := validateExternalCapabilityNameCollisions(resolved, capabilityNameIndexes{}, false)
+		require.ErrorIs(t, err, errCapabilityNameCollision)
+	}
+}
+
+func TestValidateDuplicateCapabilities(t *testing.T) {
+	require.NoError(t, validateDuplicateCapabilities(""))
+	require.NoError(t, validateDuplicateCapabilities(DuplicateCapabilitiesError))
+	require.NoError(t, validateDuplicateCapabilities(DuplicateCapabilitiesPrefix))
+	require.NoError(t, validateDuplicateCapabilities(DuplicateCapabilitiesFirstWins))
+	require.Error(t, validateDuplicateCapabilities("last-wins"))
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// catalogRefreshCheckInterval is how often the catalogs' pull options are
+// evaluated. Pulls only happen when a catalog is due.
+const catalogRefreshCheckInterval = time.Minute
+
+// catalogRefreshScheduler periodically refreshes the catalogs that are due and
+// reloads the gateway configuration when at least one of them changed.
+type catalogRefreshScheduler struct {
+	interval time.Duration
+	refresh  func(ctx context.Context) ([]string, error)
+	reload   func(ctx context.Context) error
+}
+
+func (s *catalogRefreshScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *catalogRefreshScheduler) tick(ctx context.Context) {
+	refreshed, err := s.refresh(ctx)
+	if err != nil {
+		log.Logf("! Scheduled catalog refresh failed: %v", err)
+	}
+	if len(refreshed) == 0 {
+		return
+	}
+
+	log.Logf("> Catalogs refreshed: %s", strings.Join(refreshed, ", "))
+	if err := s.reload(ctx); err != nil {
+		log.Logf("! Unable to reload configuration after catalog refresh: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogRefreshSchedulerReloadsAfterRefresh(t *testing.T) {
+	var refreshes atomic.Int32
+	reloaded := make(chan struct{}, 1)
+
+	scheduler := &catalogRefreshScheduler{
+		interval: 10 * time.Millisecond,
+		refresh: func(context.Context) ([]string, error) {
+			refreshes.Add(1)
+			return []string{"docker.io/test/catalog:latest"}, nil
+		},
+		reload: func(context.Context) error {
+			select {
+			case reloaded <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go scheduler.run(ctx)
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload after a scheduled catalog refresh")
+	}
+	assert.Positive(t, refreshes.Load())
+}
+
+func TestCatalogRefreshSchedulerSkipsReloadWhenNothingRefreshed(t *testing.T) {
+	var reloads int
+	scheduler := &catalogRefreshScheduler{
+		interval: time.Millisecond,
+		refresh: func(context.Context) ([]string, error) {
+			return nil, errors.New("registry unavailable")
+		},
+		reload: func(context.Context) error {
+			reloads++
+			return nil
+		},
+	}
+
+	scheduler.tick(t.Context())
+	scheduler.tick(t.Context())
+
+	assert.Zero(t, reloads)
+}
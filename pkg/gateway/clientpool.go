@@ -2,9 +2,11 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,6 +14,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/eval"
 	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
@@ -38,6 +41,12 @@ type clientPool struct {
 	networks    []string
 	docker      docker.Client
 	gateway     *Gateway
+	// remoteTransports pools the connections to remote servers. nil when
+	// pooling is disabled.
+	remoteTransports *mcpclient.RemoteTransports
+	// restarts delays restarting the servers that fail to start. nil when
+	// disabled.
+	restarts *restartBackoff
 }
 
 type clientConfig struct {
@@ -46,11 +55,17 @@ type clientConfig struct {
 }
 
 func newClientPool(options Options, docker docker.Client, gateway *Gateway) *clientPool {
+	var remoteTransports *mcpclient.RemoteTransports
+	if options.RemoteMaxIdleConns > 0 {
+		remoteTransports = mcpclient.NewRemoteTransports(options.RemoteMaxIdleConns)
+	}
 	return &clientPool{
-		Options:     options,
-		docker:      docker,
-		gateway:     gateway,
-		keptClients: make(map[clientKey]keptClient),
+		Options:          options,
+		docker:           docker,
+		gateway:          gateway,
+		keptClients:      make(map[clientKey]keptClient),
+		remoteTransports: remoteTransports,
+		restarts:         newRestartBackoff(options.RestartBackoff, options.MaxRestartFailures),
 	}
 }
 
@@ -74,6 +89,11 @@ func (cp *clientPool) longLived(serverConfig *catalog.ServerConfig, config *clie
 		return false
 	}
 
+	// The profile or catalog can force the lifecycle of a server
+	if serverConfig.LongLivedOverride != nil {
+		return *serverConfig.LongLivedOverride
+	}
+
 	// Remote servers are always treated as long-lived since no container is involved
 	if serverConfig.IsRemote() {
 		return true
@@ -100,7 +120,13 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 	cp.clientLock.RUnlock()
 
 	// No client found, create a new one
+	created := false
 	if getter == nil {
+		if err := cp.restarts.allow(serverConfig.Name); err != nil {
+			return nil, err
+		}
+		created = true
+
 		// If the client is long running, save it for later
 		if cp.longLived(serverConfig, config) {
 			// Double-checked locking: re-check under write lock to avoid duplicate containers
@@ -109,6 +135,7 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 			if kc, exists := cp.keptClients[key]; exists {
 				// Lost the race; reuse the existing getter
 				getter = kc.Getter
+				created = false
 			} else {
 				getter = newClientGetter(serverConfig, cp, config)
 				cp.keptClients[key] = keptClient{
@@ -126,6 +153,10 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 
 	client, err := getter.GetClient(c) // first time creates the client, can take some time
 	if err != nil {
+		if created {
+			cp.serverStartFailed(ctx, serverConfig, err)
+		}
+
 		cp.clientLock.Lock()
 		defer cp.clientLock.Unlock()
 
@@ -136,6 +167,9 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 
 		return nil, err
 	}
+	if created {
+		cp.restarts.reset(serverConfig.Name)
+	}
 
 	return client, nil
 }
@@ -153,7 +187,7 @@ func (cp *clientPool) ReleaseClient(client mcpclient.Client) {
 
 	// Client was not kept, close it
 	if !foundKept {
-		client.Session().Close()
+		closeClient(client)
 		return
 	}
 }
@@ -168,9 +202,13 @@ func (cp *clientPool) Close() {
 	for _, keptClient := range existingMap {
 		client, err := keptClient.Getter.GetClient(context.TODO()) // should be cached
 		if err == nil {
-			client.Session().Close()
+			closeClient(client)
 		}
 	}
+
+	if cp.remoteTransports != nil {
+		cp.remoteTransports.Close()
+	}
 }
 
 func (cp *clientPool) SetNetworks(networks []string) {
@@ -195,7 +233,7 @@ func (cp *clientPool) ReleaseClientsForSession(session *mcp.ServerSession) {
 		}
 		client, err := kc.Getter.GetClient(context.Background()) // should be cached
 		if err == nil {
-			client.Session().Close()
+			closeClient(client)
 		}
 	}
 }
@@ -219,7 +257,7 @@ func (cp *clientPool) InvalidateOAuthClients(provider string) {
 			// Close the connection
 			client, err := keptClient.Getter.GetClient(context.TODO())
 			if err == nil {
-				client.Session().Close()
+				closeClient(client)
 				log.Log(fmt.Sprintf("ClientPool: Successfully closed connection for %s", keptClient.Config.Name))
 			} else {
 				log.Log(fmt.Sprintf("ClientPool: Warning - failed to get client for %s during invalidation: %v", keptClient.Config.Name, err))
@@ -242,8 +280,52 @@ func (cp *clientPool) InvalidateOAuthClients(provider string) {
 	}
 }
 
+// InvalidateServerClients closes and removes all kept client connections for the specified server.
+// The server is started again, with its current configuration, on the next request.
+func (cp *clientPool) InvalidateServerClients(serverName string) int {
+	cp.clientLock.Lock()
+	defer cp.clientLock.Unlock()
+
+	var invalidatedKeys []clientKey
+	for key, keptClient := range cp.keptClients {
+		if keptClient.Config.Name != serverName {
+			continue
+		}
+
+		client, err := keptClient.Getter.GetClient(context.TODO())
+		if err == nil {
+			closeClient(client)
+		} else {
+			log.Log(fmt.Sprintf("ClientPool: Warning - failed to get client for %s during invalidation: %v", serverName, err))
+		}
+
+		invalidatedKeys = append(invalidatedKeys, key)
+	}
+
+	for _, key := range invalidatedKeys {
+		delete(cp.keptClients, key)
+	}
+
+	if cp.remoteTransports != nil {
+		cp.remoteTransports.Forget(serverName)
+	}
+	cp.restarts.reset(serverName)
+
+	return len(invalidatedKeys)
+}
+
+// remoteClient returns a client for a remote server, with the headers
+// configured for it, whose connections are pooled unless pooling is disabled.
+func (cp *clientPool) remoteClient(serverConfig *catalog.ServerConfig) mcpclient.Client {
+	serverConfig = cp.withRemoteHeaders(serverConfig)
+	if cp.remoteTransports == nil {
+		return mcpclient.NewRemoteMCPClient(serverConfig)
+	}
+	return mcpclient.NewPooledRemoteMCPClient(serverConfig, cp.remoteTransports)
+}
+
 func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
-	args := cp.baseArgs(tool.Name)
+	args := cp.baseArgs(tool.Name, nil, catalognext.PullOptionNever)
 
 	// Attach the MCP servers to the same network as the gateway.
 	for _, network := range cp.networks {
@@ -290,6 +372,10 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 		}
 	}
 
+	// Env shared by all servers
+	serverEnvArgs, serverEnv := cp.serverEnvArgs(nil)
+	args = append(args, serverEnvArgs...)
+
 	// Image
 	args = append(args, tool.Container.Image)
 
@@ -300,6 +386,9 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 	log.Log("  - Running container", tool.Container.Image, "with args", args)
 
 	cmd := exec.CommandContext(ctx, "docker", args...)
+	if len(serverEnv) > 0 {
+		cmd.Env = append(os.Environ(), serverEnv...)
+	}
 	if cp.Verbose {
 		cmd.Stderr = os.Stderr
 	}
@@ -321,17 +410,23 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 	}, nil
 }
 
-func (cp *clientPool) baseArgs(name string) []string {
-	args := []string{"run"}
+// baseArgs returns the docker run arguments shared by all the containers,
+// with the given --pull policy.
+func (cp *clientPool) baseArgs(name string, serverLabels map[string]string, pullPolicy string) []string {
+	args := cp.dockerTargetArgs()
+	args = append(args, "run")
 
 	args = append(args, "--rm", "-i", "--init", "--security-opt", "no-new-privileges")
+	if cp.HardenContainers {
+		args = append(args, "--cap-drop", "ALL", "--read-only", "--tmpfs", "/tmp")
+	}
 	if cp.Cpus > 0 {
 		args = append(args, "--cpus", fmt.Sprintf("%d", cp.Cpus))
 	}
 	if cp.Memory != "" {
 		args = append(args, "--memory", cp.Memory)
 	}
-	args = append(args, "--pull", "never")
+	args = append(args, "--pull", pullPolicy)
 
 	if os.Getenv("DOCKER_MCP_IN_DIND") == "1" {
 		args = append(args, "--privileged")
@@ -345,12 +440,43 @@ func (cp *clientPool) baseArgs(name string) []string {
 		"-l", "docker-mcp-name="+name,
 		"-l", "docker-mcp-transport=stdio",
 	)
+	args = append(args, cp.customLabelArgs(name, serverLabels)...)
 
 	return args
 }
 
-func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, targetConfig proxies.TargetConfig) ([]string, []string, error) {
-	args := cp.baseArgs(serverConfig.Name)
+// dockerTargetArgs returns the global docker flags that point the docker
+// command at the daemon selected with --docker-host or --docker-context.
+func (cp *clientPool) dockerTargetArgs() []string {
+	switch {
+	case cp.DockerHost != "":
+		return []string{"--host", cp.DockerHost}
+	case cp.DockerContext != "":
+		return []string{"--context", cp.DockerContext}
+	default:
+		return nil
+	}
+}
+
+// runArgs returns the arguments of the docker command that starts the
+// container of a server, image and command included, and its env.
+func (cp *clientPool) runArgs(ctx context.Context, serverConfig *catalog.ServerConfig, targetConfig proxies.TargetConfig) ([]string, []string, error) {
+	args, env, err := cp.argsAndEnv(ctx, serverConfig, targetConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = append(args, serverConfig.Spec.Image)
+	args = append(args, expandEnvList(eval.EvaluateList(serverConfig.Spec.Command, serverConfig.Config), env)...)
+	return args, env, nil
+}
+
+func (cp *clientPool) argsAndEnv(ctx context.Context, serverConfig *catalog.ServerConfig, targetConfig proxies.TargetConfig) ([]string, []string, error) {
+	pullPolicy, err := containerPullPolicy(serverConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	args := cp.baseArgs(serverConfig.Name, serverConfig.Spec.Labels, pullPolicy)
 	var env []string
 
 	// Security options
@@ -391,6 +517,13 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, targetConfi
 		}
 	}
 
+	// Secrets composed from several values
+	templateEnv := mcpclient.SecretTemplateEnv(ctx, serverConfig, cp.Verbose)
+	for _, template := range serverConfig.Spec.SecretTemplates {
+		args = append(args, "-e", template.Env)
+		env = append(env, fmt.Sprintf("%s=%s", template.Env, templateEnv[template.Env]))
+	}
+
 	// Env
 	for _, e := range serverConfig.Spec.Env {
 		var value string
@@ -406,6 +539,11 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, targetConfi
 		}
 	}
 
+	// Env shared by all servers, unless set above
+	serverEnvArgs, serverEnv := cp.serverEnvArgs(slices.Concat(env, targetConfig.Env))
+	args = append(args, serverEnvArgs...)
+	env = append(env, serverEnv...)
+
 	// Volumes
 	for _, mount := range eval.EvaluateList(serverConfig.Spec.Volumes, serverConfig.Config) {
 		if mount == "" {
@@ -525,37 +663,46 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 
 			// Deprecated: Use Remote instead
 			if cg.serverConfig.Spec.SSEEndpoint != "" {
-				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
+				client = cg.cp.remoteClient(cg.serverConfig)
 			} else if cg.serverConfig.Spec.Remote.URL != "" {
-				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
+				client = cg.cp.remoteClient(cg.serverConfig)
+			} else if cg.serverConfig.Spec.Type == catalog.ServerTypeCommand {
+				var err error
+				if client, err = cg.cp.localCommandClient(ctx, cg.serverConfig); err != nil {
+					return nil, err
+				}
 			} else if cg.cp.Static {
 				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "socat", nil, "STDIO", fmt.Sprintf("TCP:mcp-%s:4444", cg.serverConfig.Name))
 			} else {
+				if err := cg.cp.runHook(ctx, cg.serverConfig, hookPreStart); err != nil {
+					return nil, err
+				}
+				cleanup = func(ctx context.Context) error {
+					return cg.cp.runHook(ctx, cg.serverConfig, hookPostStop)
+				}
+
 				var targetConfig proxies.TargetConfig
 				if cg.cp.BlockNetwork && len(cg.serverConfig.Spec.AllowHosts) > 0 {
-					var err error
-					if targetConfig, cleanup, err = cg.cp.runProxies(ctx, cg.serverConfig.Spec.AllowHosts, cg.serverConfig.Spec.LongLived); err != nil {
-						return nil, err
+					longRunning := cg.serverConfig.Spec.LongLived
+					if cg.serverConfig.LongLivedOverride != nil {
+						longRunning = *cg.serverConfig.LongLivedOverride
+					}
+					proxiesTargetConfig, proxiesCleanup, err := cg.cp.runProxies(ctx, cg.serverConfig.Spec.AllowHosts, longRunning)
+					if err != nil {
+						return nil, errors.Join(err, cleanup(ctx))
+					}
+					targetConfig = proxiesTargetConfig
+					postStop := cleanup
+					cleanup = func(ctx context.Context) error {
+						return errors.Join(proxiesCleanup(ctx), postStop(ctx))
 					}
 				}
 
-				image := cg.serverConfig.Spec.Image
-				args, env, err := cg.cp.argsAndEnv(cg.serverConfig, targetConfig)
+				runArgs, env, err := cg.cp.runArgs(ctx, cg.serverConfig, targetConfig)
 				if err != nil {
-					return nil, err
-				}
-
-				command := expandEnvList(eval.EvaluateList(cg.serverConfig.Spec.Command, cg.serverConfig.Config), env)
-				if len(command) == 0 {
-					log.Log("  - Running", imageBaseName(image), "with", args)
-				} else {
-					log.Log("  - Running", imageBaseName(image), "with", args, "and command", command)
+					return nil, errors.Join(err, cleanup(ctx))
 				}
-
-				var runArgs []string
-				runArgs = append(runArgs, args...)
-				runArgs = append(runArgs, image)
-				runArgs = append(runArgs, command...)
+				log.Log("  - Running", imageBaseName(cg.serverConfig.Spec.Image), "with", runArgs)
 
 				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "docker", env, runArgs...)
 			}
@@ -573,13 +720,22 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			if cg.clientConfig != nil {
 				ss = cg.clientConfig.serverSession
 				server = cg.clientConfig.server
+				initParams.Capabilities = forwardedClientCapabilities(ss)
 			}
 			// ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 			// defer cancel()
 
 			// TODO add initial roots
-			if err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway); err != nil {
-				return nil, err
+			initialize := func() error {
+				return client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway)
+			}
+			if cg.serverConfig.Spec.SSEEndpoint != "" || cg.serverConfig.Spec.Remote.URL != "" {
+				if err := cg.cp.retryRemoteConnect(ctx, cg.serverConfig.Name, initialize); err != nil {
+					return nil, err
+				}
+			} else if err := initialize(); err != nil {
+				// The container never started, or stopped right away.
+				return nil, errors.Join(err, cleanup(ctx))
 			}
 
 			return newClientWithCleanup(client, cleanup), nil
@@ -34,14 +34,14 @@ func (a *serverToolSetAdapter) Tools(ctx context.Context) ([]*codemode.ToolWithH
 	}
 
 	// List tools from the server
-	listResult, err := client.Session().ListTools(ctx, &mcp.ListToolsParams{})
+	tools, err := listAllTools(ctx, client.Session())
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools from server %s: %w", a.serverName, err)
 	}
 
 	// Convert MCP tools to ToolWithHandler
 	var result []*codemode.ToolWithHandler
-	for _, tool := range listResult.Tools {
+	for _, tool := range tools {
 		// Create a handler that calls the tool on the remote server
 		handler := func(tool *mcp.Tool) mcp.ToolHandler {
 			return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
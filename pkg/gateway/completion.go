@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Reference types of completion requests.
+const (
+	completionRefPrompt   = "ref/prompt"
+	completionRefResource = "ref/resource"
+)
+
+// completionHandler routes completion/complete requests to the server
+// providing the referenced prompt or resource template, and returns its
+// suggestions.
+func (g *Gateway) completionHandler(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	if req.Params == nil || req.Params.Ref == nil {
+		return nil, fmt.Errorf("missing completion reference")
+	}
+	ref := req.Params.Ref
+
+	var complete CompletionHandler
+	g.capabilitiesMu.RLock()
+	switch ref.Type {
+	case completionRefPrompt:
+		registration, ok := g.promptRegistrations[ref.Name]
+		if !ok {
+			g.capabilitiesMu.RUnlock()
+			return nil, fmt.Errorf("unknown prompt %q", ref.Name)
+		}
+		complete = registration.Complete
+	case completionRefResource:
+		registration, ok := g.resourceTemplateRegistrations[ref.URI]
+		if !ok {
+			g.capabilitiesMu.RUnlock()
+			return nil, fmt.Errorf("unknown resource template %q", ref.URI)
+		}
+		complete = registration.Complete
+	default:
+		g.capabilitiesMu.RUnlock()
+		return nil, fmt.Errorf("unsupported completion reference type %q", ref.Type)
+	}
+	g.capabilitiesMu.RUnlock()
+
+	if complete == nil {
+		return emptyCompletion(), nil
+	}
+	return complete(ctx, req)
+}
+
+// mcpServerCompletionHandler forwards completion requests to a server. A
+// server that doesn't support completions gets no request, and no
+// suggestions are returned.
+func (g *Gateway) mcpServerCompletionHandler(serverName string, server *mcp.Server) CompletionHandler {
+	return func(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+		serverConfig, _, ok := g.configuration.Find(serverName)
+		if !ok {
+			return nil, fmt.Errorf("server %q not found in configuration", serverName)
+		}
+
+		client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(req.Session, server))
+		if err != nil {
+			return nil, err
+		}
+		defer g.clientPool.ReleaseClient(client)
+
+		if result := client.Session().InitializeResult(); result == nil || result.Capabilities == nil || result.Capabilities.Completions == nil {
+			return emptyCompletion(), nil
+		}
+		return client.Session().Complete(ctx, req.Params)
+	}
+}
+
+// prefixPromptCompletion completes the arguments of a prompt exposed as
+// <server>__<prompt>, by requesting completions for the original prompt name
+// from the server.
+func prefixPromptCompletion(complete CompletionHandler, originalName string) CompletionHandler {
+	if complete == nil {
+		return nil
+	}
+	return func(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+		forwarded := *req
+		if req.Params != nil && req.Params.Ref != nil {
+			params := *req.Params
+			ref := *req.Params.Ref
+			ref.Name = originalName
+			params.Ref = &ref
+			forwarded.Params = &params
+		}
+		return complete(ctx, &forwarded)
+	}
+}
+
+// trackPrompt remembers a registered prompt, to route completion requests.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) trackPrompt(registration PromptRegistration) {
+	if g.promptRegistrations == nil {
+		g.promptRegistrations = make(map[string]PromptRegistration)
+	}
+	g.promptRegistrations[registration.Prompt.Name] = registration
+}
+
+// trackResourceTemplate remembers a registered resource template, to route
+// completion requests. This function expects g.capabilitiesMu to be locked by
+// the caller.
+func (g *Gateway) trackResourceTemplate(registration ResourceTemplateRegistration) {
+	if g.resourceTemplateRegistrations == nil {
+		g.resourceTemplateRegistrations = make(map[string]ResourceTemplateRegistration)
+	}
+	g.resourceTemplateRegistrations[registration.ResourceTemplate.URITemplate] = registration
+}
+
+func emptyCompletion() *mcp.CompleteResult {
+	return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}
+}
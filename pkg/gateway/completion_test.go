@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// completingServer serves a mock MCP server with a review prompt and a
+// resource template, whose arguments complete to the given values starting
+// with the typed value. Without values, the server doesn't support
+// completions.
+func completingServer(t *testing.T, name string, values []string) catalog.Server {
+	t.Helper()
+
+	var options *mcp.ServerOptions
+	if values != nil {
+		options = &mcp.ServerOptions{
+			CompletionHandler: func(_ context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+				if req.Params.Ref.Type == completionRefPrompt && req.Params.Ref.Name != "review" {
+					return nil, fmt.Errorf("unknown prompt %q", req.Params.Ref.Name)
+				}
+				var suggestions []string
+				for _, value := range values {
+					if strings.HasPrefix(value, req.Params.Argument.Value) {
+						suggestions = append(suggestions, name+":"+req.Params.Argument.Name+"="+value)
+					}
+				}
+				return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: suggestions, Total: len(suggestions)}}, nil
+			},
+		}
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: name, Version: "1.0.0"}, options)
+	server.AddPrompt(&mcp.Prompt{Name: "review", Arguments: []*mcp.PromptArgument{{Name: "language"}}}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	server.AddResourceTemplate(&mcp.ResourceTemplate{Name: "file", URITemplate: "file:///" + name + "/{path}"}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{}, nil
+	})
+
+	remote := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(remote.Close)
+
+	return catalog.Server{
+		Name:   name,
+		Type:   "remote",
+		Remote: catalog.Remote{URL: remote.URL, Transport: "streamable-http"},
+	}
+}
+
+// completingGateway starts a gateway for the given servers and connects a
+// client to it.
+func completingGateway(t *testing.T, options Options, servers ...catalog.Server) *mcp.ClientSession {
+	t.Helper()
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+	telemetry.Init()
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	g := &Gateway{
+		Options: options,
+		configuration: Configuration{
+			servers: map[string]catalog.Server{},
+		},
+		serverAvailableCapabilities: make(map[string]*Capabilities),
+	}
+	for _, server := range servers {
+		g.configuration.serverNames = append(g.configuration.serverNames, server.Name)
+		g.configuration.servers[server.Name] = server
+	}
+	g.clientPool = newClientPool(options, nil, g)
+	t.Cleanup(g.clientPool.Close)
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, &mcp.ServerOptions{
+		CompletionHandler: g.completionHandler,
+	})
+	require.NoError(t, g.reloadConfiguration(ctx, g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func complete(t *testing.T, session *mcp.ClientSession, ref *mcp.CompleteReference, argument, value string) []string {
+	t.Helper()
+	result, err := session.Complete(t.Context(), &mcp.CompleteParams{
+		Ref:      ref,
+		Argument: mcp.CompleteParamsArgument{Name: argument, Value: value},
+	})
+	require.NoError(t, err)
+	return result.Completion.Values
+}
+
+func TestCompletionIsRoutedToTheServerOfThePrompt(t *testing.T) {
+	session := completingGateway(t, Options{},
+		completingServer(t, "languages", []string{"go", "golang", "rust"}),
+	)
+	require.NotNil(t, session.InitializeResult().Capabilities.Completions)
+
+	assert.Equal(t, []string{"languages:language=go", "languages:language=golang"},
+		complete(t, session, &mcp.CompleteReference{Type: completionRefPrompt, Name: "review"}, "language", "go"))
+	assert.Equal(t, []string{"languages:path=rust"},
+		complete(t, session, &mcp.CompleteReference{Type: completionRefResource, URI: "file:///languages/{path}"}, "path", "r"))
+
+	_, err := session.Complete(t.Context(), &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: completionRefPrompt, Name: "missing"},
+		Argument: mcp.CompleteParamsArgument{Name: "language", Value: "go"},
+	})
+	require.ErrorContains(t, err, `unknown prompt "missing"`)
+}
+
+func TestCompletionOfPrefixedDuplicatePrompts(t *testing.T) {
+	session := completingGateway(t, Options{DuplicateCapabilities: DuplicateCapabilitiesPrefix},
+		completingServer(t, "alpha", []string{"go"}),
+		completingServer(t, "beta", []string{"go"}),
+	)
+
+	// Each server is asked for the completions of its own review prompt.
+	assert.Equal(t, []string{"alpha:language=go"},
+		complete(t, session, &mcp.CompleteReference{Type: completionRefPrompt, Name: "alpha__review"}, "language", "g"))
+	assert.Equal(t, []string{"beta:language=go"},
+		complete(t, session, &mcp.CompleteReference{Type: completionRefPrompt, Name: "beta__review"}, "language", "g"))
+}
+
+func TestCompletionOfServerWithoutCompletions(t *testing.T) {
+	session := completingGateway(t, Options{}, completingServer(t, "plain", nil))
+
+	assert.Empty(t, complete(t, session, &mcp.CompleteReference{Type: completionRefPrompt, Name: "review"}, "language", "go"))
+}
@@ -1,6 +1,10 @@
 package gateway
 
-import "github.com/docker/mcp-gateway/pkg/catalog"
+import (
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
 
 type Config struct {
 	Options
@@ -29,6 +33,9 @@ type Options struct {
 	BlockNetwork            bool
 	VerifySignatures        bool
 	DryRun                  bool
+	PrintToolSchemas        bool
+	StartupSummary          bool
+	FailOnEmpty             bool
 	Watch                   bool
 	Cpus                    int
 	Memory                  string
@@ -41,4 +48,132 @@ type Options struct {
 	UseEmbeddings           bool
 	UseProfiles             bool
 	AllowUnauthenticated    bool
+	AnnounceCapabilities    string
+	DuplicateCapabilities   string
+	ServerConcurrency       []string
+	// DisabledCapabilities are capability types (prompts, resources,
+	// resource-templates) that are neither discovered nor advertised.
+	DisabledCapabilities []string
+	// ContainerLabels are <key>=<value> labels added to every server container.
+	ContainerLabels []string
+	// ServerEnv are <key>=<value> environment variables set in every server
+	// container, unless the server sets them itself.
+	ServerEnv []string
+	// RemoteHeaders are <server>:<name>=<value> headers added to the requests
+	// to remote servers, e.g. tenant IDs. See clientPool.withRemoteHeaders.
+	RemoteHeaders []string
+	// ToolResultTransforms are <server>:<tool>=<expression> YQ expressions
+	// applied to the structured results of tools. See transformToolResult.
+	ToolResultTransforms []string
+	// ConfigFileDirs are the directories file://<path> config values can be
+	// read from.
+	ConfigFileDirs []string
+	// RedactPatterns are regular expressions whose matches are redacted from
+	// the text content of tool results. See interceptors.RedactMiddleware.
+	RedactPatterns []string
+	// AllowImages and DenyImages are the image patterns of the servers the
+	// gateway may run. See imagepolicy.Policy.
+	AllowImages []string
+	DenyImages  []string
+	// HookCommands are the commands, executable and arguments, that servers'
+	// preStart and postStop hooks are allowed to run. See runHook.
+	HookCommands []string
+	// GatewayTools exposes the gateway__* tools that describe and manage the
+	// gateway itself. See addGatewayTools.
+	GatewayTools bool
+	// PageSize is the maximum number of tools, prompts, resources or resource
+	// templates returned per page of list responses. 0 means the MCP SDK
+	// default.
+	PageSize int
+	// MaxResourcesPerServer caps the number of resources listed from each
+	// server, with a warning for the servers that have more. 0 means no cap.
+	MaxResourcesPerServer int
+	// SafeMode disables mutating dynamic tools and turns on the hardening
+	// options below. See Options.applySafeMode.
+	SafeMode bool
+	// HardenContainers drops all capabilities and makes the root filesystem
+	// of server containers read-only.
+	HardenContainers bool
+	// MaxToolResponseBytes caps the text content of tool results, set with
+	// --truncate-results. 0 means no cap.
+	MaxToolResponseBytes int
+	// ToolTimeout bounds the duration of tool calls. 0 means no timeout.
+	ToolTimeout time.Duration
+	// DiscoveryTimeout bounds the time a server has to initialize and list its
+	// capabilities, after which it's marked as failed. 0 means no timeout.
+	DiscoveryTimeout time.Duration
+	// CatalogRefresh is a catalog pull option (e.g. "exists@6h") evaluated
+	// periodically to refresh catalogs while the gateway runs. Empty disables it.
+	CatalogRefresh string
+	// PreloadCatalog is a legacy catalog file imported into the database
+	// before serving, unless the database already has that catalog.
+	PreloadCatalog string
+	// SnapshotWarmup resolves the snapshots of the profile servers that don't
+	// have one in the background after startup, instead of before serving.
+	SnapshotWarmup bool
+	// RemoteRetries is the number of times connecting to a remote server is
+	// retried after a network failure. Authentication failures aren't retried.
+	RemoteRetries int
+	// RemoteRetryBackoff is the delay before the first retry. It doubles with
+	// every attempt.
+	RemoteRetryBackoff time.Duration
+	// RestartBackoff is the delay before a server that failed to start is
+	// started again. It doubles with every rapid failure. 0 disables it.
+	RestartBackoff time.Duration
+	// MaxRestartFailures is the number of rapid failures to start a server
+	// after which it's marked unavailable. 0 means no limit.
+	MaxRestartFailures int
+	// ListInactiveServers also reports the catalog servers that are not
+	// enabled, without starting them, in gateway__list-servers, /servers and
+	// gateway__status, e.g. for discovery UIs.
+	ListInactiveServers bool
+	// RemoteMaxIdleConns is the number of idle connections kept open to each
+	// remote server, to be reused by the next tool calls. 0 disables pooling.
+	RemoteMaxIdleConns int
+	// DockerHost and DockerContext target a Docker daemon other than the one
+	// of the current docker context. At most one of them is set.
+	DockerHost    string
+	DockerContext string
+	// RecordCallsPath is a JSON Lines file every tool call is appended to,
+	// with secrets redacted. See recordCallsMiddleware.
+	RecordCallsPath string
+	// ReplayCallsPath is a file of calls recorded with RecordCallsPath that
+	// are replayed against the gateway, instead of serving clients.
+	ReplayCallsPath string
+	// MetricsSnapshotPath is a JSON file the value of every metric is written
+	// to when the gateway exits.
+	MetricsSnapshotPath string
+	// ExplainServer is the name of a server whose resolution is printed once
+	// the configuration is read, instead of serving clients.
+	ExplainServer string
 }
+
+// Values accepted by Options.AnnounceCapabilities.
+const (
+	// AnnounceCapabilitiesAll advertises tools, prompts and resources even
+	// when no active server provides them.
+	AnnounceCapabilitiesAll = "all"
+	// AnnounceCapabilitiesPresent only advertises the capabilities for which
+	// at least one tool, prompt, resource or resource template is registered.
+	AnnounceCapabilitiesPresent = "present"
+)
+
+// Capability types accepted by Options.DisabledCapabilities.
+const (
+	CapabilityPrompts           = "prompts"
+	CapabilityResources         = "resources"
+	CapabilityResourceTemplates = "resource-templates"
+)
+
+// Values accepted by Options.DuplicateCapabilities. They control how prompts
+// and resources exposed by more than one server are aggregated.
+const (
+	// DuplicateCapabilitiesError refuses to load colliding prompts or resources.
+	DuplicateCapabilitiesError = "error"
+	// DuplicateCapabilitiesPrefix exposes colliding prompts as <server>__<prompt>.
+	// Resource URIs are kept as-is and resolved with first-wins.
+	DuplicateCapabilitiesPrefix = "prefix"
+	// DuplicateCapabilitiesFirstWins keeps the first registration, in server
+	// name order, and drops the others.
+	DuplicateCapabilitiesFirstWins = "first-wins"
+)
@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// configFilePrefix marks the config values read from a file, e.g. a PEM or a
+// large JSON document, rather than given inline.
+const configFilePrefix = "file://"
+
+// maxConfigFileSize caps the size of a file read as a config value.
+const maxConfigFileSize = 1 << 20
+
+// configFileResolver reads the file://<path> config values of a
+// configuration. Files are only read from the allowed directories, and never
+// for the config of a profile that was pulled or imported: its values weren't
+// set locally and could point at any file of the host.
+type configFileResolver struct {
+	allowedDirs []string
+	// untrustedProfile is the name of the pulled or imported profile the
+	// config comes from, if any.
+	untrustedProfile string
+}
+
+// resolveConfigFiles replaces the file://<path> config values of the enabled
+// servers with the content of the file. A missing or oversized file is an
+// error, rather than a server started with the path as its value.
+func (c *Configuration) resolveConfigFiles(allowedDirs []string) error {
+	for _, serverName := range c.serverNames {
+		if err := c.resolveServerConfigFiles(serverName, allowedDirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveServerConfigFiles replaces the file://<path> config values of a
+// single server, e.g. one added with mcp-add.
+func (c *Configuration) resolveServerConfigFiles(serverName string, allowedDirs []string) error {
+	canonical := oci.CanonicalizeServerName(serverName)
+	serverConfig, ok := c.config[canonical]
+	if !ok {
+		return nil
+	}
+
+	resolver := configFileResolver{allowedDirs: allowedDirs}
+	if c.profileOrigin != "" {
+		resolver.untrustedProfile = c.workingSet
+	}
+	resolved, err := resolver.resolve(serverConfig, serverName)
+	if err != nil {
+		return err
+	}
+	c.config[canonical] = resolved.(map[string]any)
+	return nil
+}
+
+// resolve resolves the file://<path> values found in value, which is left
+// intact. key is the dotted path of the value, for errors.
+func (r configFileResolver) resolve(value any, key string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		path, ok := strings.CutPrefix(v, configFilePrefix)
+		if !ok {
+			return v, nil
+		}
+		content, err := r.read(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config %s: %w", key, err)
+		}
+		return content, nil
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for k, item := range v {
+			resolvedItem, err := r.resolve(item, key+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = resolvedItem
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			resolvedItem, err := r.resolve(item, fmt.Sprintf("%s[%d]", key, i))
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = resolvedItem
+		}
+		return resolved, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r configFileResolver) read(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("missing file path after " + configFilePrefix)
+	}
+	if r.untrustedProfile != "" {
+		return "", fmt.Errorf("profile %s was pulled or imported, its %s values are not read", r.untrustedProfile, configFilePrefix)
+	}
+	if slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "..") {
+		return "", fmt.Errorf("path %s must not contain ..", path)
+	}
+
+	realPath, err := r.allowedPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(realPath)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory, not a file", path)
+	}
+	if info.Size() > maxConfigFileSize {
+		return "", fmt.Errorf("file %s is %d bytes, more than the limit of %d bytes", path, info.Size(), maxConfigFileSize)
+	}
+
+	content, err := os.ReadFile(realPath)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// allowedPath returns the path of the file, with its symlinks resolved, if
+// it's in one of the allowed directories.
+func (r configFileResolver) allowedPath(path string) (string, error) {
+	if len(r.allowedDirs) == 0 {
+		return "", fmt.Errorf("%s values are only read from the directories allowed with --config-file-dir", configFilePrefix)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := filepath.EvalSymlinks(absPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("file %s does not exist", path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range r.allowedDirs {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		realDir, err := filepath.EvalSymlinks(absDir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(realDir, realPath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return realPath, nil
+		}
+	}
+	return "", fmt.Errorf("file %s is not in a directory allowed with --config-file-dir", path)
+}
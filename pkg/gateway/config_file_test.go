@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(certPath, []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"), 0o600))
+	settingsPath := filepath.Join(dir, "settings.json")
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"debug":true}`), 0o600))
+
+	configuration := Configuration{
+		serverNames: []string{"tls"},
+		config: map[string]map[string]any{
+			"tls": {
+				"cert":     "file://" + certPath,
+				"host":     "example.com",
+				"advanced": map[string]any{"settings": "file://" + settingsPath},
+				"extra":    []any{"file://" + settingsPath, 42},
+			},
+			"disabled": {"cert": "file://" + filepath.Join(dir, "missing.pem")},
+		},
+	}
+
+	require.NoError(t, configuration.resolveConfigFiles([]string{dir}))
+	assert.Equal(t, map[string]any{
+		"cert":     "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		"host":     "example.com",
+		"advanced": map[string]any{"settings": `{"debug":true}`},
+		"extra":    []any{`{"debug":true}`, 42},
+	}, configuration.config["tls"])
+	// Only enabled servers are resolved.
+	assert.Equal(t, "file://"+filepath.Join(dir, "missing.pem"), configuration.config["disabled"]["cert"])
+}
+
+func TestResolveConfigFilesWithMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.pem")
+	configuration := Configuration{
+		serverNames: []string{"tls"},
+		config:      map[string]map[string]any{"tls": {"cert": "file://" + missing}},
+	}
+
+	err := configuration.resolveConfigFiles([]string{dir})
+	require.EqualError(t, err, "reading config tls.cert: file "+missing+" does not exist")
+	assert.Equal(t, "file://"+missing, configuration.config["tls"]["cert"])
+}
+
+func TestResolveConfigFilesWithOversizedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "large.json")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("a", maxConfigFileSize+1)), 0o600))
+	configuration := Configuration{
+		serverNames: []string{"svc"},
+		config:      map[string]map[string]any{"svc": {"data": "file://" + path}},
+	}
+
+	err := configuration.resolveConfigFiles([]string{dir})
+	require.ErrorContains(t, err, "reading config svc.data: file "+path+" is 1048577 bytes, more than the limit of 1048576 bytes")
+}
+
+func TestResolveConfigFilesOutsideAllowedDirs(t *testing.T) {
+	allowed := t.TempDir()
+	outside := t.TempDir()
+	secretPath := filepath.Join(outside, "id_rsa")
+	require.NoError(t, os.WriteFile(secretPath, []byte("PRIVATE KEY"), 0o600))
+	require.NoError(t, os.Symlink(secretPath, filepath.Join(allowed, "link")))
+	require.NoError(t, os.WriteFile(filepath.Join(allowed, "ok.txt"), []byte("ok"), 0o600))
+
+	tests := []struct {
+		name   string
+		dirs   []string
+		path   string
+		errMsg string
+	}{
+		{name: "no allowed directory", path: filepath.Join(allowed, "ok.txt"), errMsg: "only read from the directories allowed with --config-file-dir"},
+		{name: "outside", dirs: []string{allowed}, path: secretPath, errMsg: "is not in a directory allowed with --config-file-dir"},
+		{name: "dot dot", dirs: []string{allowed}, path: allowed + "/../" + filepath.Base(outside) + "/id_rsa", errMsg: "must not contain .."},
+		{name: "symlink escape", dirs: []string{allowed}, path: filepath.Join(allowed, "link"), errMsg: "is not in a directory allowed with --config-file-dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuration := Configuration{
+				serverNames: []string{"svc"},
+				config:      map[string]map[string]any{"svc": {"key": "file://" + tt.path}},
+			}
+
+			err := configuration.resolveConfigFiles(tt.dirs)
+			require.ErrorContains(t, err, tt.errMsg)
+			assert.Equal(t, "file://"+tt.path, configuration.config["svc"]["key"])
+		})
+	}
+}
+
+func TestResolveConfigFilesOfPulledProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(path, []byte("cert"), 0o600))
+	configuration := Configuration{
+		serverNames:   []string{"tls"},
+		config:        map[string]map[string]any{"tls": {"cert": "file://" + path}},
+		workingSet:    "shared",
+		profileOrigin: "pulled",
+	}
+
+	err := configuration.resolveConfigFiles([]string{dir})
+	require.EqualError(t, err, "reading config tls.cert: profile shared was pulled or imported, its file:// values are not read")
+}
+
+func TestResolveServerConfigFilesOfAddedServer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(path, []byte("cert"), 0o600))
+	configuration := Configuration{
+		config: map[string]map[string]any{"tls": {"cert": "file://" + path}},
+	}
+
+	require.NoError(t, configuration.resolveServerConfigFiles("tls", []string{dir}))
+	assert.Equal(t, "cert", configuration.config["tls"]["cert"])
+}
@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// ServerConfigSchema is what a server needs to be configured, as reported by
+// /servers/config-schema: the JSON schema of its config and its secrets.
+type ServerConfigSchema struct {
+	Config  map[string]any   `json:"config"`
+	Secrets []catalog.Secret `json:"secrets"`
+}
+
+// ConfigSchemas returns the config schema and the secrets of every enabled
+// server, indexed by server name, e.g. for clients to render a combined
+// settings page. Servers that are not in any catalog are left out.
+func (g *Gateway) ConfigSchemas() map[string]ServerConfigSchema {
+	g.configurationMu.Lock()
+	defer g.configurationMu.Unlock()
+
+	schemas := make(map[string]ServerConfigSchema, len(g.configuration.serverNames))
+	for _, serverName := range g.configuration.serverNames {
+		server, ok := g.configuration.servers[serverName]
+		if !ok {
+			continue
+		}
+
+		secrets := server.StoredSecrets()
+		if secrets == nil {
+			secrets = []catalog.Secret{}
+		}
+		schemas[serverName] = ServerConfigSchema{
+			Config:  serverConfigSchema(server),
+			Secrets: secrets,
+		}
+	}
+	return schemas
+}
+
+// serverConfigSchema merges the config items of a server into a single JSON
+// schema of type object, with the properties and required properties of all
+// the items, as they are validated by validateServerConfig.
+func serverConfigSchema(server catalog.Server) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for _, configItem := range server.Config {
+		schemaMap, ok := configItem.(map[string]any)
+		if !ok {
+			continue
+		}
+		itemProperties, ok := schemaMap["properties"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		maps.Copy(properties, itemProperties)
+		for propName := range requiredConfigProperties(schemaMap) {
+			if _, ok := itemProperties[propName]; ok {
+				required = append(required, propName)
+			}
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		slices.Sort(required)
+		schema["required"] = slices.Compact(required)
+	}
+	return schema
+}
+
+func (g *Gateway) configSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g.ConfigSchemas())
+	}
+}
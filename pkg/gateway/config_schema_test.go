@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestConfigSchemaEndpoint(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"github", "fetch", "jira", "missing"},
+			servers: map[string]catalog.Server{
+				"github": {
+					Name:    "github",
+					Secrets: []catalog.Secret{{Name: "github.personal_access_token", Env: "GITHUB_PERSONAL_ACCESS_TOKEN"}},
+					Config: []any{
+						map[string]any{
+							"name": "github",
+							"type": "object",
+							"properties": map[string]any{
+								"owner":   map[string]any{"type": "string"},
+								"toolset": map[string]any{"type": "string", "default": "all"},
+							},
+							"required": []any{"owner"},
+						},
+					},
+				},
+				"fetch": {Name: "fetch"},
+				"jira": {
+					Name:            "jira",
+					Secrets:         []catalog.Secret{{Name: "jira.token", Env: "JIRA_TOKEN"}},
+					SecretTemplates: []catalog.SecretTemplate{{Env: "JIRA_AUTH", Template: "{{jira.user}}:{{jira.token}}"}},
+				},
+				// Not enabled.
+				"slack": {Name: "slack"},
+			},
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+	g.configSchemaHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/servers/config-schema", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var schemas map[string]ServerConfigSchema
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &schemas))
+	require.Len(t, schemas, 3)
+
+	github := schemas["github"]
+	assert.Equal(t, "object", github.Config["type"])
+	assert.Equal(t, []any{"owner"}, github.Config["required"])
+	properties, ok := github.Config["properties"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, properties, "owner")
+	assert.Contains(t, properties, "toolset")
+	assert.Equal(t, []catalog.Secret{{Name: "github.personal_access_token", Env: "GITHUB_PERSONAL_ACCESS_TOKEN"}}, github.Secrets)
+
+	fetch := schemas["fetch"]
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, fetch.Config)
+	assert.Empty(t, fetch.Secrets)
+
+	// The parts of secret templates are secrets too.
+	assert.Equal(t, []catalog.Secret{{Name: "jira.token", Env: "JIRA_TOKEN"}, {Name: "jira.user"}}, schemas["jira"].Secrets)
+}
+
+func TestServerConfigSchemaMergesConfigItems(t *testing.T) {
+	schema := serverConfigSchema(catalog.Server{
+		Config: []any{
+			map[string]any{
+				"name": "db.connection",
+				"properties": map[string]any{
+					"host": map[string]any{"type": "string"},
+					"port": map[string]any{"type": "integer"},
+				},
+				"required": []string{"host"},
+			},
+			map[string]any{
+				"name": "db.options",
+				"properties": map[string]any{
+					"readonly": map[string]any{"type": "boolean"},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"host"}, schema["required"])
+	assert.Len(t, schema["properties"], 3)
+}
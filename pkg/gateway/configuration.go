@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/policy"
@@ -34,10 +37,32 @@ type Configuration struct {
 	secrets     map[string]string
 	// serverCatalogs maps server names to catalog identifiers.
 	serverCatalogs map[string]string
+	// serverCatalogSources maps server names to the catalog their definition
+	// comes from: the catalog that defines them or, for the servers of a
+	// profile, the catalog they were added from. Unlike serverCatalogs, it's
+	// not given to policies.
+	serverCatalogSources map[string]string
 	// serverSourceTypeOverrides maps server names to explicit source type identifiers.
 	serverSourceTypeOverrides map[string]string
+	// shadowedCatalogs maps server names to the catalogs whose definitions of
+	// the server were overridden by a later catalog or by the profile, in the
+	// order they were read.
+	shadowedCatalogs map[string][]string
+	// longLivedOverrides maps server names to the lifecycle forced by the
+	// profile or catalog, regardless of the server's longLived flag.
+	longLivedOverrides map[string]bool
+	// toolResultTransforms maps server names to the expressions applied to
+	// the structured results of their tools, by tool name, as set in the
+	// profile.
+	toolResultTransforms map[string]map[string]string
 	// workingSet is the profile identifier for this configuration.
 	workingSet string
+	// profileOrigin is the origin of the profile, set when it was pulled or
+	// imported rather than created locally.
+	profileOrigin string
+	// imagePolicy restricts the images of the servers, as stored with the
+	// profile.
+	imagePolicy imagepolicy.Policy
 }
 
 func (c *Configuration) ServerNames() []string {
@@ -81,6 +106,11 @@ func (c *Configuration) DockerImages() []string {
 	return dockerImages
 }
 
+// startPriority returns the priority a server starts with, 0 if it's not found.
+func (c *Configuration) startPriority(serverName string) int {
+	return c.servers[strings.TrimSpace(serverName)].Priority
+}
+
 func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[string]catalog.Tool, bool) {
 	serverName = strings.TrimSpace(serverName)
 
@@ -90,24 +120,36 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 		return nil, nil, false
 	}
 
+	// Local commands run on the host, so they are only trusted when a
+	// profile created locally declares them, never when they come from a
+	// catalog or from a pulled or imported profile.
+	if server.Type == catalog.ServerTypeCommand && (c.serverSourceTypeOverrides[serverName] != catalog.ServerTypeCommand || c.profileOrigin != "") {
+		return nil, nil, false
+	}
+
 	// Is it an MCP Server?
-	if server.Image != "" || server.SSEEndpoint != "" || server.Remote.URL != "" {
+	if server.Type != catalog.ServerTypePOCI && (server.Image != "" || server.SSEEndpoint != "" || server.Remote.URL != "" || server.Type == catalog.ServerTypeCommand) {
 		// Scope secrets to only the keys declared by this server so that a
 		// compromised or malicious server cannot access another server's secrets.
-		scopedSecrets := make(map[string]string, len(server.Secrets))
-		for _, s := range server.Secrets {
+		storedSecrets := server.StoredSecrets()
+		scopedSecrets := make(map[string]string, len(storedSecrets))
+		for _, s := range storedSecrets {
 			if v, ok := c.secrets[s.Name]; ok {
 				scopedSecrets[s.Name] = v
 			}
 		}
-		return &catalog.ServerConfig{
+		serverConfig := &catalog.ServerConfig{
 			Name: serverName,
 			Spec: server,
 			Config: map[string]any{
 				oci.CanonicalizeServerName(serverName): c.config[oci.CanonicalizeServerName(serverName)],
 			},
 			Secrets: scopedSecrets,
-		}, nil, true
+		}
+		if longLived, ok := c.longLivedOverrides[serverName]; ok {
+			serverConfig.LongLivedOverride = &longLived
+		}
+		return serverConfig, nil, true
 	}
 
 	// Then it's a POCI?
@@ -447,7 +489,7 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 	}
 
 	// read local catalog files
-	mcpCatalog, catalogRefs, err := c.readCatalog(ctx)
+	mcpCatalog, catalogRefs, shadowedCatalogs, err := c.readCatalog(ctx)
 	if err != nil {
 		return Configuration{}, fmt.Errorf("reading catalog: %w", err)
 	}
@@ -460,6 +502,10 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 	}
 
 	servers := mcpCatalog.Servers
+	serverNames, err = resolveServerIDs(serverNames, servers)
+	if err != nil {
+		return Configuration{}, err
+	}
 
 	// Read servers from OCI references if any are provided
 	ociServers, ociCatalogRefs, err := c.readServersFromOci(ctx)
@@ -471,6 +517,9 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 	for serverName, server := range ociServers {
 		if _, exists := servers[serverName]; exists {
 			log.Log(fmt.Sprintf("Warning: server '%s' from OCI reference overwrites server from catalog", serverName))
+			if catalogRef := catalogRefs[serverName]; catalogRef != "" {
+				shadowedCatalogs[serverName] = append(shadowedCatalogs[serverName], catalogRef)
+			}
 		}
 		servers[serverName] = server
 		if catalogRef := ociCatalogRefs[serverName]; catalogRef != "" {
@@ -544,13 +593,36 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		return Configuration{}, fmt.Errorf("reading tools: %w", err)
 	}
 
+	secrets := c.readSecrets(ctx, serverNames, servers)
+
+	log.Log("- Configuration read in", time.Since(start))
+	return Configuration{
+		serverNames:               serverNames,
+		servers:                   servers,
+		config:                    serversConfig,
+		tools:                     serverToolsConfig,
+		secrets:                   secrets,
+		serverCatalogs:            serverCatalogs,
+		serverCatalogSources:      maps.Clone(serverCatalogs),
+		serverSourceTypeOverrides: serverSourceTypeOverrides,
+		shadowedCatalogs:          shadowedCatalogs,
+		workingSet:                "",
+	}, nil
+}
+
+// ReadSecrets re-reads the secrets of the servers in the configuration.
+func (c *FileBasedConfiguration) ReadSecrets(ctx context.Context, configuration Configuration) (map[string]string, error) {
+	return c.readSecrets(ctx, configuration.serverNames, configuration.servers), nil
+}
+
+func (c *FileBasedConfiguration) readSecrets(ctx context.Context, serverNames []string, servers map[string]catalog.Server) map[string]string {
 	// Build se:// URIs for secrets using shared function
 	buildSecretsURIs := func() map[string]string {
 		configs := make([]ServerSecretConfig, 0, len(serverNames))
 		for _, serverName := range serverNames {
 			server := servers[serverName]
 			configs = append(configs, ServerSecretConfig{
-				Secrets: server.Secrets,
+				Secrets: server.StoredSecrets(),
 				OAuth:   server.OAuth,
 			})
 		}
@@ -571,6 +643,7 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 				secrets = buildSecretsURIs()
 				break
 			}
+			var err error
 			secrets, err = c.readSecretsFromFile(ctx, secretPath)
 			if err == nil {
 				break
@@ -578,24 +651,15 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		}
 	}
 
-	log.Log("- Configuration read in", time.Since(start))
-	return Configuration{
-		serverNames:               serverNames,
-		servers:                   servers,
-		config:                    serversConfig,
-		tools:                     serverToolsConfig,
-		secrets:                   secrets,
-		serverCatalogs:            serverCatalogs,
-		serverSourceTypeOverrides: serverSourceTypeOverrides,
-		workingSet:                "",
-	}, nil
+	return secrets
 }
 
-func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catalog, map[string]string, error) {
+func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catalog, map[string]string, map[string][]string, error) {
 	log.Log("  - Reading catalog from", c.CatalogPath)
 
 	mergedServers := map[string]catalog.Server{}
 	serverCatalogs := map[string]string{}
+	shadowedCatalogs := map[string][]string{}
 
 	for _, catalogPath := range c.CatalogPath {
 		if catalogPath == "" {
@@ -603,7 +667,7 @@ func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catal
 		}
 		cat, name, _, err := catalog.ReadOne(ctx, catalogPath)
 		if err != nil {
-			return catalog.Catalog{}, nil, err
+			return catalog.Catalog{}, nil, nil, err
 		}
 		catalogID := name
 		if catalogID == "" {
@@ -612,13 +676,14 @@ func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catal
 		for key, server := range cat.Servers {
 			if _, exists := mergedServers[key]; exists {
 				log.Log(fmt.Sprintf("Warning: overlapping key '%s' found in catalog '%s', overwriting previous value", key, catalogPath))
+				shadowedCatalogs[key] = append(shadowedCatalogs[key], serverCatalogs[key])
 			}
 			mergedServers[key] = server
 			serverCatalogs[key] = catalogID
 		}
 	}
 
-	return catalog.Catalog{Servers: mergedServers}, serverCatalogs, nil
+	return catalog.Catalog{Servers: mergedServers}, serverCatalogs, shadowedCatalogs, nil
 }
 
 func (c *FileBasedConfiguration) readRegistry(ctx context.Context) (config.Registry, error) {
@@ -813,3 +878,31 @@ func (c *FileBasedConfiguration) readServersFromOci(_ context.Context) (map[stri
 
 	return ociServers, ociCatalogs, nil
 }
+
+// resolveServerIDs replaces the stable IDs in serverNames, e.g. from --servers,
+// with the names of the catalog servers they identify. Other names are kept
+// as they are. An ID shared by several servers, e.g. running the same image,
+// is an error rather than an arbitrary pick.
+func resolveServerIDs(serverNames []string, servers map[string]catalog.Server) ([]string, error) {
+	ids := make(map[string][]string)
+	for name, server := range servers {
+		if id := server.StableID(); id != "" {
+			ids[id] = append(ids[id], name)
+		}
+	}
+
+	resolved := make([]string, 0, len(serverNames))
+	for _, serverName := range serverNames {
+		if _, exists := servers[serverName]; !exists {
+			if names, ok := ids[serverName]; ok {
+				if len(names) > 1 {
+					slices.Sort(names)
+					return nil, fmt.Errorf("server ID %s is ambiguous, it identifies the servers %s: select one by name", serverName, strings.Join(names, ", "))
+				}
+				serverName = names[0]
+			}
+		}
+		resolved = append(resolved, serverName)
+	}
+	return resolved, nil
+}
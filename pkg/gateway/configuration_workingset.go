@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/docker"
@@ -41,15 +43,74 @@ func (c *WorkingSetConfiguration) Read(ctx context.Context) (Configuration, chan
 	// Do migration from legacy files
 	migrate.MigrateConfig(ctx, c.docker, dao)
 
+	if c.config.PreloadCatalog != "" {
+		ref, imported, err := catalognext.Preload(ctx, dao, c.config.PreloadCatalog)
+		if err != nil {
+			return Configuration{}, nil, nil, err
+		}
+		if imported {
+			log.Logf("- Preloaded catalog %s from %s", ref, c.config.PreloadCatalog)
+		}
+	}
+
+	if c.config.CatalogRefresh != "" {
+		if _, err := catalognext.NewPullOptionEvaluator(c.config.CatalogRefresh, false); err != nil {
+			return Configuration{}, nil, nil, fmt.Errorf("invalid catalog refresh option: %w", err)
+		}
+	}
+
 	configuration, err := c.readOnce(ctx, dao)
 	if err != nil {
 		return Configuration{}, nil, nil, err
 	}
 
-	// TODO(cody): Stub for now
 	updates := make(chan Configuration)
+	if c.config.CatalogRefresh == "" && !c.config.SnapshotWarmup {
+		return configuration, updates, func() error { return nil }, nil
+	}
+
+	// Push the new configuration through the updates channel so that the
+	// gateway hot-reloads its servers.
+	backgroundCtx, cancel := context.WithCancel(ctx)
+	reload := func(ctx context.Context) error {
+		configuration, err := c.readOnce(ctx, dao)
+		if err != nil {
+			return err
+		}
+		select {
+		case updates <- configuration:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	if c.config.SnapshotWarmup {
+		warmup := &snapshotWarmup{
+			resolve: func(ctx context.Context) ([]string, error) {
+				return resolveWorkingSetSnapshots(ctx, dao, c.ociService, c.config.WorkingSet)
+			},
+			reload: reload,
+		}
+		go warmup.run(backgroundCtx)
+	}
 
-	return configuration, updates, func() error { return nil }, nil
+	if c.config.CatalogRefresh != "" {
+		// Refresh the catalogs in the background.
+		scheduler := &catalogRefreshScheduler{
+			interval: catalogRefreshCheckInterval,
+			refresh: func(ctx context.Context) ([]string, error) {
+				return catalognext.RefreshDue(ctx, dao, c.ociService, c.config.CatalogRefresh)
+			},
+			reload: reload,
+		}
+		log.Logf("- Refreshing catalogs in the background (%s)", c.config.CatalogRefresh)
+		go scheduler.run(backgroundCtx)
+	}
+
+	return configuration, updates, func() error {
+		cancel()
+		return nil
+	}, nil
 }
 
 func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Configuration, error) {
@@ -71,7 +132,10 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 
 	workingSet := workingset.NewFromDb(dbWorkingSet)
 
-	if err := workingSet.EnsureSnapshotsResolved(ctx, c.ociService); err != nil {
+	if c.config.SnapshotWarmup {
+		// Servers without a snapshot are added once the warmup resolved it.
+		workingSet.Servers = withSnapshots(workingSet.Servers)
+	} else if err := workingSet.EnsureSnapshotsResolved(ctx, c.ociService); err != nil {
 		return Configuration{}, fmt.Errorf("failed to resolve snapshots: %w", err)
 	}
 	if validationErrors := validateWorkingSetServerConfigs(workingSet); len(validationErrors) > 0 {
@@ -90,7 +154,7 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 		// 	namespace = server.Secrets + "_"
 		// }
 		configs = append(configs, ServerSecretConfig{
-			Secrets:   server.Snapshot.Server.Secrets,
+			Secrets:   server.Snapshot.Server.StoredSecrets(),
 			OAuth:     server.Snapshot.Server.OAuth,
 			Namespace: namespace,
 		})
@@ -102,9 +166,10 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 	// TODO(cody): Finish making the gateway fully compatible with working sets
 	serverNames := make([]string, 0)
 	servers := make(map[string]catalog.Server)
+	toolResultTransforms := make(map[string]map[string]string)
 
 	// Load all catalogs to populate servers for dynamic tools
-	allCatalogServers, catalogRefs, err := c.readAllCatalogServers(ctx, dao)
+	allCatalogServers, catalogRefs, longLivedOverrides, shadowedCatalogs, err := c.readAllCatalogServers(ctx, dao)
 	if err != nil {
 		return Configuration{}, fmt.Errorf("failed to read all catalog servers: %w", err)
 	}
@@ -112,6 +177,7 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 	serverCatalogs := make(map[string]string)
 	serverSourceTypeOverrides := make(map[string]string)
 	maps.Copy(serverCatalogs, catalogRefs)
+	serverCatalogSources := maps.Clone(serverCatalogs)
 	for name := range serverCatalogs {
 		// Registry indicates the server definition came from a catalog source.
 		serverSourceTypeOverrides[name] = "registry"
@@ -119,16 +185,47 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 
 	for _, server := range workingSet.Servers {
 		// Skip registry servers for now
-		if server.Type != workingset.ServerTypeImage && server.Type != workingset.ServerTypeRemote && server.Type != workingset.ServerTypeRegistry {
+		if server.Type != workingset.ServerTypeImage && server.Type != workingset.ServerTypeRemote && server.Type != workingset.ServerTypeRegistry && server.Type != workingset.ServerTypeCommand && server.Type != workingset.ServerTypePOCI {
 			continue
 		}
 
 		serverName := server.Snapshot.Server.Name
 
-		servers[serverName] = server.Snapshot.Server
+		spec := server.Snapshot.Server
+		if server.Type == workingset.ServerTypeCommand {
+			// Commands run on the host, unsandboxed: only the profiles
+			// created locally are trusted to declare them.
+			if workingSet.Origin != "" {
+				return Configuration{}, fmt.Errorf("profile %s was %s, its command server %s is not run", workingSet.ID, workingSet.Origin, serverName)
+			}
+			spec.Type = catalog.ServerTypeCommand
+			spec.Command = append([]string{server.Exec}, server.Args...)
+		}
+		servers[serverName] = spec
 		serverNames = append(serverNames, serverName)
 		// Working set types map directly to policy source types.
 		serverSourceTypeOverrides[serverName] = string(server.Type)
+		if catalogRef, ok := catalogRefs[serverName]; ok {
+			// The profile's definition wins over the one of the catalogs.
+			// serverCatalogs is left as is: it's what policies see.
+			shadowedCatalogs[serverName] = append(shadowedCatalogs[serverName], catalogRef)
+		}
+		delete(serverCatalogSources, serverName)
+		if server.CatalogRef != "" {
+			serverCatalogSources[serverName] = server.CatalogRef
+		}
+		if server.LongLivedOverride != nil {
+			longLivedOverrides[serverName] = *server.LongLivedOverride
+		} else {
+			delete(longLivedOverrides, serverName)
+		}
+
+		if len(server.ToolResultTransforms) > 0 {
+			if err := validateToolResultTransforms(serverName, server.ToolResultTransforms); err != nil {
+				return Configuration{}, err
+			}
+			toolResultTransforms[serverName] = server.ToolResultTransforms
+		}
 
 		cfg[serverName] = server.Config
 
@@ -149,14 +246,33 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 		tools:                     toolsConfig,
 		secrets:                   secrets,
 		serverCatalogs:            serverCatalogs,
+		serverCatalogSources:      serverCatalogSources,
 		serverSourceTypeOverrides: serverSourceTypeOverrides,
+		shadowedCatalogs:          shadowedCatalogs,
+		longLivedOverrides:        longLivedOverrides,
+		toolResultTransforms:      toolResultTransforms,
 		workingSet:                c.config.WorkingSet,
+		profileOrigin:             workingSet.Origin,
+		imagePolicy:               profileImagePolicy(workingSet),
 	}, nil
 }
 
+// ReadSecrets re-reads the secrets of the servers in the configuration.
+func (c *WorkingSetConfiguration) ReadSecrets(ctx context.Context, configuration Configuration) (map[string]string, error) {
+	configs := make([]ServerSecretConfig, 0, len(configuration.serverNames))
+	for _, serverName := range configuration.serverNames {
+		server := configuration.servers[serverName]
+		configs = append(configs, ServerSecretConfig{
+			Secrets: server.StoredSecrets(),
+			OAuth:   server.OAuth,
+		})
+	}
+	return BuildSecretsURIs(ctx, configs), nil
+}
+
 func (c *WorkingSetConfiguration) emptyConfiguration(ctx context.Context, dao db.DAO) (Configuration, error) {
 	// Load all catalogs to populate servers for dynamic tools
-	allCatalogServers, catalogRefs, err := c.readAllCatalogServers(ctx, dao)
+	allCatalogServers, catalogRefs, longLivedOverrides, shadowedCatalogs, err := c.readAllCatalogServers(ctx, dao)
 	if err != nil {
 		return Configuration{}, fmt.Errorf("failed to read all catalog servers: %w", err)
 	}
@@ -175,38 +291,83 @@ func (c *WorkingSetConfiguration) emptyConfiguration(ctx context.Context, dao db
 		},
 		secrets:                   make(map[string]string),
 		serverCatalogs:            catalogRefs,
+		serverCatalogSources:      maps.Clone(catalogRefs),
 		serverSourceTypeOverrides: serverSourceTypeOverrides,
+		shadowedCatalogs:          shadowedCatalogs,
+		longLivedOverrides:        longLivedOverrides,
 		workingSet:                c.config.WorkingSet,
 	}, nil
 }
 
-func (c *WorkingSetConfiguration) readAllCatalogServers(ctx context.Context, dao db.DAO) (map[string]catalog.Server, map[string]string, error) {
+func (c *WorkingSetConfiguration) readAllCatalogServers(ctx context.Context, dao db.DAO) (map[string]catalog.Server, map[string]string, map[string]bool, map[string][]string, error) {
 	servers := make(map[string]catalog.Server)
 	serverCatalogs := make(map[string]string)
+	longLivedOverrides := make(map[string]bool)
+	shadowedCatalogs := make(map[string][]string)
 	if c.config.DynamicTools {
 		allCatalogs, err := dao.ListCatalogs(ctx)
 		if err != nil {
-			return servers, nil, fmt.Errorf("failed to list catalogs: %w", err)
+			return servers, nil, nil, nil, fmt.Errorf("failed to list catalogs: %w", err)
 		}
 
 		if len(allCatalogs) == 0 {
 			log.Log("  - No catalogs found, dynamic tools will be limited to profile servers. Run `docker mcp catalog pull mcp/docker-mcp-catalog:latest` and restart the gateway to add Docker MCP catalog servers to dynamic tools.")
 		} else {
 			log.Log(fmt.Sprintf("  - Loading %d catalog(s) for dynamic tools", len(allCatalogs)))
+
+			// Catalogs are read after their base catalogs, so that the
+			// servers they override win over the ones they inherit.
+			bases := catalogBases(allCatalogs)
+			slices.SortStableFunc(allCatalogs, func(a, b db.Catalog) int {
+				return len(bases[a.Ref]) - len(bases[b.Ref])
+			})
 			for _, cat := range allCatalogs {
-				log.Log(fmt.Sprintf("    - Processing catalog '%s' with %d servers", cat.Ref, len(cat.Servers)))
-				for _, server := range cat.Servers {
+				resolved, err := db.ResolveCatalogBase(ctx, dao, &cat)
+				if err != nil {
+					log.Log(fmt.Sprintf("    - Warning: %v, only the servers of catalog '%s' itself are loaded", err, cat.Ref))
+					resolved = &cat
+				}
+				log.Log(fmt.Sprintf("    - Processing catalog '%s' with %d servers", cat.Ref, len(resolved.Servers)))
+				for _, server := range resolved.Servers {
 					if server.Snapshot != nil { // should always be true
 						name := server.Snapshot.Server.Name
+						// Servers inherited from a base catalog don't shadow it.
+						if previous, ok := serverCatalogs[name]; ok && !slices.Contains(bases[cat.Ref], previous) {
+							shadowedCatalogs[name] = append(shadowedCatalogs[name], previous)
+						}
 						servers[name] = server.Snapshot.Server
 						serverCatalogs[name] = cat.Ref
+						if server.LongLivedOverride != nil {
+							longLivedOverrides[name] = *server.LongLivedOverride
+						} else {
+							delete(longLivedOverrides, name)
+						}
 					}
 				}
 			}
 			log.Log(fmt.Sprintf("  - Total servers loaded from all catalogs: %d", len(servers)))
 		}
 	}
-	return servers, serverCatalogs, nil
+	return servers, serverCatalogs, longLivedOverrides, shadowedCatalogs, nil
+}
+
+// catalogBases returns the chain of base catalogs of each catalog, nearest
+// first. Chains stop at missing catalogs and cycles.
+func catalogBases(catalogs []db.Catalog) map[string][]string {
+	baseOf := make(map[string]string, len(catalogs))
+	for _, cat := range catalogs {
+		baseOf[cat.Ref] = cat.Base
+	}
+
+	bases := make(map[string][]string, len(catalogs))
+	for _, cat := range catalogs {
+		var chain []string
+		for base := cat.Base; base != "" && base != cat.Ref && !slices.Contains(chain, base); base = baseOf[base] {
+			chain = append(chain, base)
+		}
+		bases[cat.Ref] = chain
+	}
+	return bases
 }
 
 func (c *WorkingSetConfiguration) readTools(workingSet workingset.WorkingSet) config.ToolsConfig {
@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// parseContainerLabels parses labels of the form <key>=<value>.
+func parseContainerLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, labelValue, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid container label %q: expected <key>=<value>", value)
+		}
+		if isReservedContainerLabel(key) {
+			return nil, fmt.Errorf("invalid container label %q: %s is reserved for the gateway", value, key)
+		}
+		labels[key] = labelValue
+	}
+	return labels, nil
+}
+
+// isReservedContainerLabel reports whether key is one of the labels the
+// gateway uses to identify the containers it starts.
+func isReservedContainerLabel(key string) bool {
+	return key == "docker-mcp" || strings.HasPrefix(key, "docker-mcp-")
+}
+
+// customLabelArgs returns the docker run arguments for the labels configured
+// with --container-label, overridden by the server's own labels.
+func (cp *clientPool) customLabelArgs(name string, serverLabels map[string]string) []string {
+	labels, err := parseContainerLabels(cp.ContainerLabels)
+	if err != nil {
+		// Already validated when the gateway starts.
+		labels = map[string]string{}
+	}
+	for key, value := range serverLabels {
+		if isReservedContainerLabel(key) {
+			log.Logf("Warning: server '%s' sets reserved container label %q, skipping", name, key)
+			continue
+		}
+		labels[key] = value
+	}
+
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		args = append(args, "-l", key+"="+labels[key])
+	}
+	return args
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
+)
+
+func TestParseContainerLabels(t *testing.T) {
+	labels, err := parseContainerLabels([]string{"team=platform", "env=", "note=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform", "env": "", "note": "a=b"}, labels)
+
+	for _, value := range []string{"team", "=platform", "docker-mcp=false", "docker-mcp-name=other"} {
+		_, err := parseContainerLabels([]string{value})
+		require.Error(t, err, value)
+	}
+}
+
+func TestContainerLabelsAreMerged(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+
+	// A docker that records its arguments, one per line, then exits before
+	// initialization.
+	fakeDocker := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$@\" > %s\nexit 1\n", argsFile)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(fakeDocker), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cp := &clientPool{
+		Options: Options{
+			Cpus:            1,
+			Memory:          "2Gb",
+			ContainerLabels: []string{"team=platform", "env=dev"},
+		},
+	}
+	serverConfig := &catalog.ServerConfig{
+		Name: "github",
+		Spec: catalog.Server{
+			Image: "mcp/github",
+			Labels: map[string]string{
+				"env":             "prod",
+				"cost-center":     "42",
+				"docker-mcp-name": "other",
+			},
+		},
+	}
+
+	_, err := newClientGetter(serverConfig, cp, nil).GetClient(t.Context())
+	require.Error(t, err)
+
+	buf, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	args := strings.Split(strings.TrimSpace(string(buf)), "\n")
+
+	assert.Equal(t, "run", args[0])
+	assert.Equal(t, []string{
+		"docker-mcp=true",
+		"docker-mcp-tool-type=mcp",
+		"docker-mcp-name=github",
+		"docker-mcp-transport=stdio",
+		"cost-center=42",
+		"env=prod",
+		"team=platform",
+	}, labelValues(args))
+}
+
+func TestContainerLabelsDefault(t *testing.T) {
+	cp := &clientPool{}
+
+	assert.Equal(t, []string{
+		"docker-mcp=true",
+		"docker-mcp-tool-type=mcp",
+		"docker-mcp-name=server",
+		"docker-mcp-transport=stdio",
+	}, labelValues(cp.baseArgs("server", nil, catalognext.PullOptionNever)))
+}
+
+func labelValues(args []string) []string {
+	var labels []string
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-l" {
+			labels = append(labels, args[i+1])
+		}
+	}
+	return labels
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/gateway/project"
 	"github.com/docker/mcp-gateway/pkg/log"
@@ -64,7 +65,10 @@ func createProfileHandler(g *Gateway) mcp.ToolHandler {
 			var serverType workingset.ServerType
 			var image, endpoint string
 
-			if catalogServer.Remote.URL != "" {
+			if catalogServer.Type == catalog.ServerTypePOCI {
+				// Tools running their own containers, described by the snapshot
+				serverType = workingset.ServerTypePOCI
+			} else if catalogServer.Remote.URL != "" {
 				// Remote server
 				serverType = workingset.ServerTypeRemote
 				endpoint = catalogServer.Remote.URL
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// dockerPingTimeout bounds how long the gateway waits for the Docker daemon
+// at startup, so that an unreachable socket doesn't hang the gateway.
+const dockerPingTimeout = 5 * time.Second
+
+// checkDockerAvailable makes sure the Docker daemon can be reached when the
+// configuration runs servers in containers. Configurations that only use
+// remote servers can run without Docker.
+func (g *Gateway) checkDockerAvailable(ctx context.Context, configuration Configuration) error {
+	pingCtx, cancel := context.WithTimeout(ctx, dockerPingTimeout)
+	defer cancel()
+
+	err := g.docker.Ping(pingCtx)
+	if err == nil {
+		return nil
+	}
+
+	containerServers := serversRequiringDocker(configuration)
+	if len(containerServers) == 0 {
+		log.Logf("Warning: the Docker daemon is not available (%v), only remote servers can be used", err)
+		return nil
+	}
+
+	return fmt.Errorf("cannot connect to the Docker daemon, which is required to run %s: %w\n"+
+		"Make sure Docker is running and that DOCKER_HOST or the current docker context (docker context ls) points to a reachable Docker socket, or only enable remote servers",
+		strings.Join(containerServers, ", "), err)
+}
+
+// serversRequiringDocker returns the enabled servers that run in containers.
+func serversRequiringDocker(configuration Configuration) []string {
+	var serverNames []string
+	for _, serverName := range configuration.serverNames {
+		serverConfig, tools, found := configuration.Find(serverName)
+		switch {
+		case !found:
+		case serverConfig != nil && serverConfig.Spec.Image != "":
+			serverNames = append(serverNames, serverName)
+		case tools != nil:
+			serverNames = append(serverNames, serverName)
+		}
+	}
+	return serverNames
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+var errNoDockerSocket = errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?")
+
+func TestCheckDockerAvailableRemoteOnly(t *testing.T) {
+	g := &Gateway{docker: &recordingDockerClient{pingErr: errNoDockerSocket}}
+
+	err := g.checkDockerAvailable(context.Background(), Configuration{
+		serverNames: []string{"notion"},
+		servers: map[string]catalog.Server{
+			"notion": {Name: "notion", Type: "remote", Remote: catalog.Remote{URL: "https://mcp.notion.com/mcp"}},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestCheckDockerAvailableImageServers(t *testing.T) {
+	g := &Gateway{docker: &recordingDockerClient{pingErr: errNoDockerSocket}}
+
+	err := g.checkDockerAvailable(context.Background(), Configuration{
+		serverNames: []string{"github", "notion"},
+		servers: map[string]catalog.Server{
+			"github": {Name: "github", Image: "mcp/github"},
+			"notion": {Name: "notion", Type: "remote", Remote: catalog.Remote{URL: "https://mcp.notion.com/mcp"}},
+		},
+	})
+	require.ErrorIs(t, err, errNoDockerSocket)
+	assert.ErrorContains(t, err, "cannot connect to the Docker daemon, which is required to run github:")
+	assert.ErrorContains(t, err, "DOCKER_HOST")
+}
+
+func TestCheckDockerAvailableWithDocker(t *testing.T) {
+	g := &Gateway{docker: &recordingDockerClient{}}
+
+	err := g.checkDockerAvailable(context.Background(), Configuration{
+		serverNames: []string{"github"},
+		servers: map[string]catalog.Server{
+			"github": {Name: "github", Image: "mcp/github"},
+		},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,254 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// DoctorStatus is the outcome of a single doctor check.
+type DoctorStatus string
+
+const (
+	DoctorPass DoctorStatus = "pass"
+	DoctorWarn DoctorStatus = "warn"
+	DoctorFail DoctorStatus = "fail"
+)
+
+// DoctorCheck is the result of one of the checks run by Doctor.
+type DoctorCheck struct {
+	Name   string       `json:"name"`
+	Status DoctorStatus `json:"status"`
+	Detail string       `json:"detail"`
+	// Hint tells the user how to fix a warning or a failure.
+	Hint string `json:"hint,omitempty"`
+}
+
+// DoctorOptions holds what Doctor checks. The functions are replaced in tests.
+type DoctorOptions struct {
+	Docker docker.Client
+	// DesktopRunning returns an error when the Docker Desktop backend, used
+	// for OAuth and secrets, can't be reached.
+	DesktopRunning func(ctx context.Context) error
+	// OpenDB opens the database holding profiles and catalogs, read-only. It
+	// returns an error wrapping os.ErrNotExist when there's no database yet.
+	OpenDB func() (db.DAO, error)
+	// UseProfiles selects where catalogs are looked up: in the database when
+	// true, in CatalogPaths otherwise.
+	UseProfiles  bool
+	CatalogPaths []string
+	// RegistryURL is the base URL of the MCP registry to check.
+	RegistryURL string
+	// HTTPClient reaches the registry. It connects directly, without going
+	// through HTTP_PROXY or HTTPS_PROXY, like the registry client does.
+	HTTPClient *http.Client
+}
+
+// doctorCheckTimeout bounds each check so that a single unreachable
+// dependency doesn't hang the report.
+const doctorCheckTimeout = 10 * time.Second
+
+// Doctor diagnoses the environment the gateway runs in and reports one check
+// per dependency, in a stable order.
+func Doctor(ctx context.Context, options DoctorOptions) []DoctorCheck {
+	dao, databaseCheck := checkDatabase(options)
+	if dao != nil {
+		defer dao.Close()
+	}
+
+	return []DoctorCheck{
+		withDoctorTimeout(ctx, func(ctx context.Context) DoctorCheck { return checkDockerDaemon(ctx, options) }),
+		withDoctorTimeout(ctx, func(ctx context.Context) DoctorCheck { return checkDockerDesktop(ctx, options) }),
+		databaseCheck,
+		withDoctorTimeout(ctx, func(ctx context.Context) DoctorCheck { return checkCatalogs(ctx, options, dao, databaseCheck) }),
+		withDoctorTimeout(ctx, func(ctx context.Context) DoctorCheck { return checkRegistry(ctx, options) }),
+	}
+}
+
+func withDoctorTimeout(ctx context.Context, check func(context.Context) DoctorCheck) DoctorCheck {
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	return check(ctx)
+}
+
+// DoctorFailed reports whether any of the checks failed.
+func DoctorFailed(checks []DoctorCheck) bool {
+	for _, check := range checks {
+		if check.Status == DoctorFail {
+			return true
+		}
+	}
+	return false
+}
+
+// DoctorReport is the JSON report of the checks, written with --report so
+// that CI can gate on it.
+type DoctorReport struct {
+	// Passed is false when any of the checks failed. Warnings don't count.
+	Passed bool          `json:"passed"`
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// WriteDoctorReport writes the checks to path as a JSON report.
+func WriteDoctorReport(path string, checks []DoctorCheck) error {
+	return writeReport(path, DoctorReport{Passed: !DoctorFailed(checks), Checks: checks})
+}
+
+// writeReport writes a JSON report, e.g. of the doctor or of a server test,
+// to path.
+func writeReport(path string, report any) error {
+	buf, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+func checkDockerDaemon(ctx context.Context, options DoctorOptions) DoctorCheck {
+	check := DoctorCheck{Name: "Docker daemon"}
+	if err := options.Docker.Ping(ctx); err != nil {
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+		check.Hint = "Start Docker, or point DOCKER_HOST, --docker-host or --docker-context at a reachable daemon. Only remote servers can run without Docker."
+		return check
+	}
+	check.Status = DoctorPass
+	check.Detail = "reachable"
+	return check
+}
+
+func checkDockerDesktop(ctx context.Context, options DoctorOptions) DoctorCheck {
+	check := DoctorCheck{Name: "Docker Desktop backend"}
+	if err := options.DesktopRunning(ctx); err != nil {
+		check.Status = DoctorWarn
+		check.Detail = err.Error()
+		check.Hint = "OAuth and Docker Desktop secrets are unavailable. Start Docker Desktop, or use --secrets with a .env file."
+		return check
+	}
+	check.Status = DoctorPass
+	check.Detail = "running"
+	return check
+}
+
+func checkDatabase(options DoctorOptions) (db.DAO, DoctorCheck) {
+	check := DoctorCheck{Name: "Database"}
+	dao, err := options.OpenDB()
+	if errors.Is(err, os.ErrNotExist) {
+		check.Status = DoctorWarn
+		check.Detail = "not created yet"
+		check.Hint = "It's created by the first profile or catalog command, e.g. `docker mcp catalog pull mcp/docker-mcp-catalog:latest`."
+		return nil, check
+	}
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+		check.Hint = "Check that ~/.docker/mcp is writable and not used by another process."
+		return nil, check
+	}
+
+	check.Status = DoctorPass
+	check.Detail = "accessible"
+	return dao, check
+}
+
+// checkCatalogs looks for catalogs in the database, or in the catalog files
+// when profiles are not used. dao is nil when the database doesn't exist or is
+// not accessible, as told by databaseCheck.
+func checkCatalogs(ctx context.Context, options DoctorOptions, dao db.DAO, databaseCheck DoctorCheck) DoctorCheck {
+	check := DoctorCheck{Name: "Catalog"}
+
+	if !options.UseProfiles {
+		mcpCatalog, err := catalog.ReadFrom(ctx, options.CatalogPaths)
+		switch {
+		case err != nil:
+			check.Status = DoctorFail
+			check.Detail = err.Error()
+			check.Hint = "Run `docker mcp catalog init` or fix --catalog."
+		case len(mcpCatalog.Servers) == 0:
+			check.Status = DoctorWarn
+			check.Detail = fmt.Sprintf("no servers in %s", strings.Join(options.CatalogPaths, ", "))
+			check.Hint = "Run `docker mcp catalog init` or fix --catalog."
+		default:
+			check.Status = DoctorPass
+			check.Detail = fmt.Sprintf("%d servers", len(mcpCatalog.Servers))
+		}
+		return check
+	}
+
+	if dao == nil && databaseCheck.Status == DoctorWarn {
+		check.Status = DoctorWarn
+		check.Detail = "no catalog pulled"
+		check.Hint = "Run `docker mcp catalog pull mcp/docker-mcp-catalog:latest`."
+		return check
+	}
+	if dao == nil {
+		check.Status = DoctorFail
+		check.Detail = "the database is not accessible"
+		check.Hint = "Fix the database first."
+		return check
+	}
+
+	catalogs, err := dao.ListCatalogs(ctx)
+	switch {
+	case err != nil:
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+	case len(catalogs) == 0:
+		check.Status = DoctorWarn
+		check.Detail = "no catalog pulled"
+		check.Hint = "Run `docker mcp catalog pull mcp/docker-mcp-catalog:latest`."
+	default:
+		var refs []string
+		for _, c := range catalogs {
+			refs = append(refs, c.Ref)
+		}
+		check.Status = DoctorPass
+		check.Detail = strings.Join(refs, ", ")
+	}
+	return check
+}
+
+func checkRegistry(ctx context.Context, options DoctorOptions) DoctorCheck {
+	check := DoctorCheck{Name: "MCP registry"}
+	hint := fmt.Sprintf("Check your network connection to %s. The registry is reached directly: HTTP_PROXY and HTTPS_PROXY are not used.", options.RegistryURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(options.RegistryURL, "/")+"/v0/servers?limit=1", nil)
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+		return check
+	}
+
+	resp, err := options.HTTPClient.Do(req)
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+		check.Hint = hint
+		return check
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		check.Status = DoctorWarn
+		check.Detail = fmt.Sprintf("%s answered %s", options.RegistryURL, resp.Status)
+		check.Hint = hint
+		return check
+	}
+
+	check.Status = DoctorPass
+	check.Detail = options.RegistryURL + " reachable"
+	return check
+}
@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func doctorOptions(t *testing.T) DoctorOptions {
+	t.Helper()
+
+	dbFile := filepath.Join(t.TempDir(), "mcp.db")
+	dao, err := db.New(db.WithDatabaseFile(dbFile))
+	require.NoError(t, err)
+	require.NoError(t, dao.UpsertCatalog(t.Context(), db.Catalog{Ref: "mcp/docker-mcp-catalog:latest", Title: "Docker MCP Catalog"}))
+	require.NoError(t, dao.Close())
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/servers", r.URL.Path)
+		_, _ = w.Write([]byte(`{"servers":[]}`))
+	}))
+	t.Cleanup(registry.Close)
+
+	return DoctorOptions{
+		Docker:         &recordingDockerClient{},
+		DesktopRunning: func(context.Context) error { return nil },
+		OpenDB:         func() (db.DAO, error) { return db.New(db.WithDatabaseFile(dbFile), db.WithReadOnly()) },
+		UseProfiles:    true,
+		RegistryURL:    registry.URL,
+		HTTPClient:     registry.Client(),
+	}
+}
+
+func doctorStatuses(checks []DoctorCheck) map[string]DoctorStatus {
+	statuses := map[string]DoctorStatus{}
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestDoctorHealthy(t *testing.T) {
+	checks := Doctor(t.Context(), doctorOptions(t))
+
+	assert.Equal(t, map[string]DoctorStatus{
+		"Docker daemon":          DoctorPass,
+		"Docker Desktop backend": DoctorPass,
+		"Database":               DoctorPass,
+		"Catalog":                DoctorPass,
+		"MCP registry":           DoctorPass,
+	}, doctorStatuses(checks))
+	assert.False(t, DoctorFailed(checks))
+	for _, check := range checks {
+		assert.Empty(t, check.Hint, check.Name)
+	}
+}
+
+func TestDoctorWithoutDocker(t *testing.T) {
+	options := doctorOptions(t)
+	options.Docker = &recordingDockerClient{pingErr: errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock")}
+	options.DesktopRunning = func(context.Context) error { return errors.New("Docker Desktop is not running") }
+
+	checks := Doctor(t.Context(), options)
+
+	assert.Equal(t, map[string]DoctorStatus{
+		"Docker daemon":          DoctorFail,
+		"Docker Desktop backend": DoctorWarn,
+		"Database":               DoctorPass,
+		"Catalog":                DoctorPass,
+		"MCP registry":           DoctorPass,
+	}, doctorStatuses(checks))
+	assert.True(t, DoctorFailed(checks))
+	assert.Contains(t, checks[0].Detail, "Cannot connect to the Docker daemon")
+	assert.Contains(t, checks[0].Hint, "--docker-host")
+	assert.Contains(t, checks[1].Hint, "OAuth")
+}
+
+func TestDoctorWithoutCatalogOrDatabase(t *testing.T) {
+	options := doctorOptions(t)
+	emptyFile := filepath.Join(t.TempDir(), "empty.db")
+	empty, err := db.New(db.WithDatabaseFile(emptyFile))
+	require.NoError(t, err)
+	require.NoError(t, empty.Close())
+	options.OpenDB = func() (db.DAO, error) { return db.New(db.WithDatabaseFile(emptyFile), db.WithReadOnly()) }
+
+	checks := Doctor(t.Context(), options)
+	assert.Equal(t, DoctorPass, doctorStatuses(checks)["Database"])
+	assert.Equal(t, DoctorWarn, doctorStatuses(checks)["Catalog"])
+	assert.Contains(t, checks[3].Hint, "docker mcp catalog pull")
+
+	// A missing database is reported, not created.
+	missingFile := filepath.Join(t.TempDir(), "mcp", "missing.db")
+	options.OpenDB = func() (db.DAO, error) { return db.New(db.WithDatabaseFile(missingFile), db.WithReadOnly()) }
+
+	checks = Doctor(t.Context(), options)
+	assert.Equal(t, DoctorWarn, doctorStatuses(checks)["Database"])
+	assert.Equal(t, DoctorWarn, doctorStatuses(checks)["Catalog"])
+	assert.False(t, DoctorFailed(checks))
+	assert.NoDirExists(t, filepath.Dir(missingFile))
+
+	options.OpenDB = func() (db.DAO, error) { return nil, errors.New("database is locked") }
+
+	checks = Doctor(t.Context(), options)
+	assert.Equal(t, DoctorFail, doctorStatuses(checks)["Database"])
+	assert.Equal(t, DoctorFail, doctorStatuses(checks)["Catalog"])
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	options := doctorOptions(t)
+	options.Docker = &recordingDockerClient{pingErr: errors.New("Cannot connect to the Docker daemon")}
+	options.DesktopRunning = func(context.Context) error { return errors.New("Docker Desktop is not running") }
+	checks := Doctor(t.Context(), options)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, WriteDoctorReport(path, checks))
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var report DoctorReport
+	require.NoError(t, json.Unmarshal(buf, &report))
+
+	assert.False(t, report.Passed)
+	assert.Equal(t, map[string]DoctorStatus{
+		"Docker daemon":          DoctorFail,
+		"Docker Desktop backend": DoctorWarn,
+		"Database":               DoctorPass,
+		"Catalog":                DoctorPass,
+		"MCP registry":           DoctorPass,
+	}, doctorStatuses(report.Checks))
+	assert.Equal(t, "Cannot connect to the Docker daemon", report.Checks[0].Detail)
+	assert.Contains(t, report.Checks[0].Hint, "--docker-host")
+	assert.Contains(t, string(buf), `"status": "fail"`)
+}
+
+func TestWriteDoctorReportPassesWithWarnings(t *testing.T) {
+	options := doctorOptions(t)
+	options.DesktopRunning = func(context.Context) error { return errors.New("Docker Desktop is not running") }
+	checks := Doctor(t.Context(), options)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, WriteDoctorReport(path, checks))
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var report DoctorReport
+	require.NoError(t, json.Unmarshal(buf, &report))
+	assert.True(t, report.Passed)
+	assert.Len(t, report.Checks, 5)
+}
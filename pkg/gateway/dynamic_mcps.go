@@ -11,7 +11,6 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/metric"
 
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
@@ -301,7 +300,7 @@ func withToolTelemetry(toolName string, handler mcp.ToolHandler) mcp.ToolHandler
 
 		// Record tool call counter
 		telemetry.ToolCallCounter.Add(ctx, 1,
-			metric.WithAttributes(
+			telemetry.WithMetricAttributes(
 				attribute.String("mcp.server.name", serverName),
 				attribute.String("mcp.server.type", serverType),
 				attribute.String("mcp.tool.name", toolName),
@@ -315,7 +314,7 @@ func withToolTelemetry(toolName string, handler mcp.ToolHandler) mcp.ToolHandler
 		// Record duration
 		duration := time.Since(startTime).Milliseconds()
 		telemetry.ToolCallDuration.Record(ctx, float64(duration),
-			metric.WithAttributes(
+			telemetry.WithMetricAttributes(
 				attribute.String("mcp.server.name", serverName),
 				attribute.String("mcp.server.type", serverType),
 				attribute.String("mcp.tool.name", toolName),
@@ -325,7 +324,7 @@ func withToolTelemetry(toolName string, handler mcp.ToolHandler) mcp.ToolHandler
 
 		if err != nil {
 			// Record error in telemetry
-			telemetry.RecordToolError(ctx, span, serverName, serverType, toolName)
+			telemetry.RecordToolError(ctx, span, serverName, serverType, toolName, classifyToolError(err))
 			span.SetStatus(codes.Error, "Tool execution failed")
 			return nil, err
 		}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// recordServerExperimental remembers the experimental capabilities a server
+// advertised when it was initialized, to advertise them to clients.
+func (g *Gateway) recordServerExperimental(serverName string, session *mcp.ClientSession) {
+	var experimental map[string]any
+	if result := session.InitializeResult(); result != nil && result.Capabilities != nil {
+		experimental = result.Capabilities.Experimental
+	}
+
+	g.experimentalMu.Lock()
+	defer g.experimentalMu.Unlock()
+
+	if len(experimental) == 0 {
+		delete(g.serverExperimental, serverName)
+		return
+	}
+	if g.serverExperimental == nil {
+		g.serverExperimental = make(map[string]map[string]any)
+	}
+	g.serverExperimental[serverName] = maps.Clone(experimental)
+}
+
+// experimentalCapabilities returns the experimental capabilities of the
+// enabled servers, namespaced as their tools are: <server>__<capability>.
+func (g *Gateway) experimentalCapabilities() map[string]any {
+	g.experimentalMu.Lock()
+	defer g.experimentalMu.Unlock()
+
+	capabilities := map[string]any{}
+	for _, serverName := range g.configuration.serverNames {
+		for name, value := range g.serverExperimental[serverName] {
+			capabilities[prefixToolName(serverName, name)] = value
+		}
+	}
+	return capabilities
+}
+
+// experimentalCapabilitiesMiddleware adds the experimental capabilities of
+// the servers to the result of initialize, rather than dropping them.
+func (g *Gateway) experimentalCapabilitiesMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil || method != "initialize" {
+				return result, err
+			}
+
+			initializeResult, ok := result.(*mcp.InitializeResult)
+			if !ok {
+				return result, nil
+			}
+			experimental := g.experimentalCapabilities()
+			if len(experimental) == 0 {
+				return result, nil
+			}
+			if initializeResult.Capabilities == nil {
+				initializeResult.Capabilities = &mcp.ServerCapabilities{}
+			}
+			if initializeResult.Capabilities.Experimental == nil {
+				initializeResult.Capabilities.Experimental = map[string]any{}
+			}
+			maps.Copy(initializeResult.Capabilities.Experimental, experimental)
+			return result, nil
+		}
+	}
+}
+
+// forwardedClientCapabilities returns the experimental capabilities of the
+// client of a session, to forward them to the servers started for it.
+func forwardedClientCapabilities(ss *mcp.ServerSession) *mcp.ClientCapabilities {
+	if ss == nil {
+		return nil
+	}
+	params := ss.InitializeParams()
+	if params == nil || params.Capabilities == nil || len(params.Capabilities.Experimental) == 0 {
+		return nil
+	}
+	return &mcp.ClientCapabilities{Experimental: maps.Clone(params.Capabilities.Experimental)}
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+func TestExperimentalCapabilitiesRoundTrip(t *testing.T) {
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+	telemetry.Init()
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	// The experimental capabilities sent by the gateway when it initializes
+	// the upstream server, every time it connects to it.
+	var (
+		mu       sync.Mutex
+		received []map[string]any
+	)
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "1.0.0"}, &mcp.ServerOptions{
+		Capabilities: &mcp.ServerCapabilities{
+			Experimental: map[string]any{"streamingResults": map[string]any{"chunked": true}},
+		},
+		InitializedHandler: func(_ context.Context, req *mcp.InitializedRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			var experimental map[string]any
+			if capabilities := req.Session.InitializeParams().Capabilities; capabilities != nil {
+				experimental = capabilities.Experimental
+			}
+			received = append(received, experimental)
+		},
+	})
+	upstream.AddTool(&mcp.Tool{Name: "tool", InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	remote := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(remote.Close)
+
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"upstream"},
+			servers: map[string]catalog.Server{
+				"upstream": {
+					Name:   "upstream",
+					Type:   "remote",
+					Remote: catalog.Remote{URL: remote.URL, Transport: "streamable-http"},
+				},
+			},
+		},
+		serverAvailableCapabilities: make(map[string]*Capabilities),
+	}
+	g.clientPool = newClientPool(Options{}, nil, g)
+	t.Cleanup(g.clientPool.Close)
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	g.mcpServer.AddReceivingMiddleware(g.experimentalCapabilitiesMiddleware())
+	require.NoError(t, g.reloadConfiguration(ctx, g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		Capabilities: &mcp.ClientCapabilities{
+			Experimental: map[string]any{"richPrompts": map[string]any{"version": "2"}},
+		},
+	})
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	// The server's capabilities are advertised to the client, namespaced.
+	assert.Equal(t, map[string]any{
+		"upstream__streamingResults": map[string]any{"chunked": true},
+	}, session.InitializeResult().Capabilities.Experimental)
+
+	// The client's capabilities are forwarded to the server started for it.
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "tool"})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, received, map[string]any{"richPrompts": map[string]any{"version": "2"}})
+}
+
+func TestExperimentalCapabilitiesOfDisabledServersAreDropped(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{serverNames: []string{"enabled"}},
+		serverExperimental: map[string]map[string]any{
+			"enabled":  {"feature": true},
+			"disabled": {"feature": true},
+		},
+	}
+
+	assert.Equal(t, map[string]any{"enabled__feature": true}, g.experimentalCapabilities())
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// emptyServersError explains why none of the enabled servers can be used, for
+// --fail-on-empty. enabled is the number of servers that were enabled before
+// being filtered by policy. It returns nil if at least one server is usable.
+func (g *Gateway) emptyServersError(enabled int) error {
+	configuration := &g.configuration
+	if len(configuration.serverNames) == 0 {
+		switch {
+		case enabled > 0:
+			return fmt.Errorf("all %d enabled servers were filtered out by policy", enabled)
+		case len(configuration.servers) == 0:
+			return errors.New("no servers are enabled and no catalog was loaded")
+		default:
+			return errors.New("no servers are enabled")
+		}
+	}
+
+	g.failedServersMu.Lock()
+	defer g.failedServersMu.Unlock()
+	g.serverActivityMu.Lock()
+	defer g.serverActivityMu.Unlock()
+
+	var reasons []string
+	for _, serverName := range configuration.serverNames {
+		if _, _, found := configuration.Find(serverName); !found {
+			reasons = append(reasons, fmt.Sprintf("%s is not in any catalog", serverName))
+			continue
+		}
+		if !g.failedServers[serverName] {
+			return nil
+		}
+		if lastError := g.serverLastErrors[serverName]; lastError != "" {
+			reasons = append(reasons, fmt.Sprintf("%s failed to start: %s", serverName, lastError))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s failed to start", serverName))
+		}
+	}
+	return fmt.Errorf("none of the enabled servers can be used: %s", strings.Join(reasons, "; "))
+}
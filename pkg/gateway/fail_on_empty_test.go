@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
+)
+
+func TestEmptyServersErrorWhenAllFiltered(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"fetch", "untrusted"},
+			servers: map[string]catalog.Server{
+				"fetch":     {Name: "fetch", Type: "server", Image: "ghcr.io/example/fetch:latest"},
+				"untrusted": {Name: "untrusted", Type: "server", Image: "ghcr.io/example/untrusted:latest"},
+			},
+		},
+	}
+	enabled := len(g.configuration.serverNames)
+	g.configuration.filterByImagePolicy(imagepolicy.Policy{Allow: []string{"mcp/*"}})
+
+	err := g.emptyServersError(enabled)
+	require.EqualError(t, err, "all 2 enabled servers were filtered out by policy")
+}
+
+func TestEmptyServersErrorWithoutCatalog(t *testing.T) {
+	g := &Gateway{}
+
+	require.EqualError(t, g.emptyServersError(0), "no servers are enabled and no catalog was loaded")
+}
+
+func TestEmptyServersErrorWhenAllUnusable(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"upstream", "missing"},
+			servers: map[string]catalog.Server{
+				"upstream": {Name: "upstream", Image: "mcp/upstream"},
+			},
+		},
+	}
+	g.setServerFailed("upstream", true)
+	g.recordServerError("upstream", errors.New("image not found"))
+
+	err := g.emptyServersError(2)
+	require.EqualError(t, err, "none of the enabled servers can be used: upstream failed to start: image not found; missing is not in any catalog")
+}
+
+func TestEmptyServersErrorWithUsableServers(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{FailOnEmpty: true})
+	g.configuration.serverNames = append(g.configuration.serverNames, "missing")
+
+	assert.NoError(t, g.emptyServersError(2))
+}
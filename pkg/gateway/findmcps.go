@@ -313,9 +313,9 @@ func bm25Strategy(g *Gateway) mcp.ToolHandler {
 				serverInfo["description"] = s.doc.server.Description
 			}
 
-			if len(s.doc.server.Secrets) > 0 {
+			if storedSecrets := s.doc.server.StoredSecrets(); len(storedSecrets) > 0 {
 				var secrets []string
-				for _, secret := range s.doc.server.Secrets {
+				for _, secret := range storedSecrets {
 					secrets = append(secrets, secret.Name)
 				}
 				serverInfo["required_secrets"] = secrets
@@ -456,9 +456,9 @@ func (g *Gateway) findServersByEmbedding(ctx context.Context, query string, limi
 			serverInfo["description"] = server.Spec.Description
 		}
 
-		if len(server.Spec.Secrets) > 0 {
+		if storedSecrets := server.Spec.StoredSecrets(); len(storedSecrets) > 0 {
 			var secrets []string
-			for _, secret := range server.Spec.Secrets {
+			for _, secret := range storedSecrets {
 				secrets = append(secrets, secret.Name)
 			}
 			serverInfo["required_secrets"] = secrets
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// gatewayStatus is the status of the gateway process, as reported by
+// gateway__status and /healthz.
+type gatewayStatus struct {
+	Healthy   bool      `json:"healthy"`
+	StartedAt time.Time `json:"startedAt"`
+	// Uptime is the time elapsed since StartedAt, in seconds.
+	Uptime    float64             `json:"uptimeSeconds"`
+	Transport string              `json:"transport"`
+	Servers   gatewayServerCounts `json:"servers"`
+}
+
+// gatewayServerCounts counts the enabled servers, by state, and the inactive
+// catalog servers with --list-inactive-servers.
+type gatewayServerCounts struct {
+	Enabled     int `json:"enabled"`
+	Running     int `json:"running"`
+	Stopped     int `json:"stopped"`
+	Failed      int `json:"failed"`
+	Unavailable int `json:"unavailable"`
+	Inactive    int `json:"inactive,omitempty"`
+}
+
+// status returns the status of the gateway. The uptime is computed on each
+// call, from the time the gateway started.
+func (g *Gateway) status() gatewayStatus {
+	status := gatewayStatus{
+		Healthy:   g.health.IsHealthy(),
+		StartedAt: g.startedAt,
+		Uptime:    time.Since(g.startedAt).Seconds(),
+		Transport: g.Transport,
+	}
+	for _, server := range g.serverStatuses() {
+		if server.State == ServerStateInactive {
+			status.Servers.Inactive++
+			continue
+		}
+		status.Servers.Enabled++
+		switch server.State {
+		case ServerStateRunning:
+			status.Servers.Running++
+		case ServerStateStopped:
+			status.Servers.Stopped++
+		case ServerStateFailed:
+			status.Servers.Failed++
+		case ServerStateUnavailable:
+			status.Servers.Unavailable++
+		}
+	}
+	return status
+}
+
+// gatewayLiveness is what /healthz reports to unauthenticated requests.
+type gatewayLiveness struct {
+	Healthy bool `json:"healthy"`
+}
+
+// statusHandler reports the status of the gateway as JSON, for dashboards.
+// Like /health, it answers 503 until the gateway is ready. Since /healthz
+// isn't authenticated, requests without the Bearer token of the gateway only
+// get its liveness, when the gateway has a token.
+func (g *Gateway) statusHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := g.status()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if g.authToken != "" && !isAuthenticated(r, g.authToken) {
+			_ = json.NewEncoder(w).Encode(gatewayLiveness{Healthy: status.Healthy})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	}
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthzEndpoint(t *testing.T) {
+	g := &Gateway{
+		Options:   Options{Transport: "sse"},
+		startedAt: time.Now(),
+	}
+
+	getStatus := func() (int, gatewayStatus) {
+		recorder := httptest.NewRecorder()
+		g.statusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		var status gatewayStatus
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+		return recorder.Code, status
+	}
+
+	code, first := getStatus()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, first.Healthy)
+	assert.Equal(t, "sse", first.Transport)
+	assert.Equal(t, gatewayServerCounts{}, first.Servers)
+
+	g.health.SetHealthy()
+	time.Sleep(10 * time.Millisecond)
+	code, second := getStatus()
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, second.Healthy)
+	assert.True(t, first.StartedAt.Equal(second.StartedAt))
+	assert.Greater(t, second.Uptime, first.Uptime)
+}
+
+func TestHealthzSkipsAuthentication(t *testing.T) {
+	handler := authenticationMiddleware("secret", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestHealthzOnlyReportsLivenessWithoutToken(t *testing.T) {
+	g := &Gateway{
+		Options:   Options{Transport: "streaming"},
+		startedAt: time.Now(),
+		authToken: "secret",
+	}
+	g.health.SetHealthy()
+	handler := authenticationMiddleware(g.authToken, g.statusHandler())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"healthy":true}`, recorder.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.JSONEq(t, `{"healthy":true}`, recorder.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var status gatewayStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, "streaming", status.Transport)
+	assert.False(t, status.StartedAt.IsZero())
+}
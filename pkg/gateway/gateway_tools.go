@@ -0,0 +1,287 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/version"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// gatewayToolsPrefix namespaces the tools describing and managing the gateway
+// itself, as if they came from a server named "gateway".
+const gatewayToolsPrefix = "gateway"
+
+var (
+	gatewayListServersToolName = prefixToolName(gatewayToolsPrefix, "list-servers")
+	gatewayListToolsToolName   = prefixToolName(gatewayToolsPrefix, "list-tools")
+	gatewayReloadToolName      = prefixToolName(gatewayToolsPrefix, "reload")
+	gatewayVersionToolName     = prefixToolName(gatewayToolsPrefix, "version")
+	gatewayStatusToolName      = prefixToolName(gatewayToolsPrefix, "status")
+)
+
+// gatewayToolInfo is a tool, as reported by gateway__list-tools.
+type gatewayToolInfo struct {
+	Name        string `json:"name"`
+	Server      string `json:"server,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// gatewayBuildInfo is the build of the running gateway, as reported by
+// gateway__version.
+type gatewayBuildInfo struct {
+	Version string `json:"version"`
+	// Commit is the revision the gateway was built from, when the build
+	// recorded it.
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"goVersion"`
+	// Features are the feature flags enabled for the gateway.
+	Features []string `json:"features"`
+}
+
+// addGatewayTools registers the gateway__* tools.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) addGatewayTools() {
+	log.Log("- Adding gateway tools")
+	for _, registration := range []*ToolRegistration{
+		g.createGatewayListServersTool(),
+		g.createGatewayListToolsTool(),
+		g.createGatewayReloadTool(),
+		g.createGatewayVersionTool(),
+		g.createGatewayStatusTool(),
+	} {
+		log.Log("  >", registration.Tool.Name)
+		g.mcpServer.AddTool(registration.Tool, registration.Handler)
+		g.toolRegistrations[registration.Tool.Name] = *registration
+	}
+}
+
+func (g *Gateway) createGatewayListServersTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        gatewayListServersToolName,
+		Description: "List the MCP servers enabled in the gateway, with their state (running, stopped, failed or unavailable), last error, number of tools and last tool call. Catalog servers that are not enabled may be listed as inactive.",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	return &ToolRegistration{
+		Tool: tool,
+		Handler: withToolTelemetry(gatewayListServersToolName, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonToolResult(g.serverStatuses())
+		}),
+	}
+}
+
+func (g *Gateway) createGatewayListToolsTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        gatewayListToolsToolName,
+		Description: "List the tools exposed by the gateway, with the server providing each of them.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Only list the tools of this MCP server",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	return &ToolRegistration{
+		Tool: tool,
+		Handler: withToolTelemetry(gatewayListToolsToolName, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var params struct {
+				Server string `json:"server"`
+			}
+			if err := parseGatewayToolArguments(req, &params); err != nil {
+				return nil, err
+			}
+
+			g.capabilitiesMu.RLock()
+			tools := []gatewayToolInfo{}
+			for name, registration := range g.toolRegistrations {
+				if params.Server != "" && registration.ServerName != params.Server {
+					continue
+				}
+				tools = append(tools, gatewayToolInfo{
+					Name:        name,
+					Server:      registration.ServerName,
+					Description: registration.Tool.Description,
+				})
+			}
+			g.capabilitiesMu.RUnlock()
+
+			sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+			return jsonToolResult(tools)
+		}),
+	}
+}
+
+func (g *Gateway) createGatewayReloadTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        gatewayReloadToolName,
+		Description: "Reload the capabilities (tools, prompts and resources) of an MCP server, or of all the enabled servers, e.g. after a server failed to start.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server to reload. Reloads all the enabled servers if empty",
+				},
+			},
+		},
+	}
+
+	return &ToolRegistration{
+		Tool: tool,
+		Handler: withToolTelemetry(gatewayReloadToolName, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var params struct {
+				Server string `json:"server"`
+			}
+			if err := parseGatewayToolArguments(req, &params); err != nil {
+				return nil, err
+			}
+
+			serverNames := slices.Clone(g.configuration.serverNames)
+			if params.Server != "" {
+				if !slices.Contains(serverNames, params.Server) {
+					return nil, fmt.Errorf("server %q is not enabled", params.Server)
+				}
+				serverNames = []string{params.Server}
+			}
+
+			result := struct {
+				Reloaded []string          `json:"reloaded"`
+				Failed   map[string]string `json:"failed,omitempty"`
+			}{Reloaded: []string{}}
+			for _, serverName := range serverNames {
+				if err := g.reloadServer(ctx, serverName); err != nil {
+					if result.Failed == nil {
+						result.Failed = map[string]string{}
+					}
+					result.Failed[serverName] = err.Error()
+					continue
+				}
+				result.Reloaded = append(result.Reloaded, serverName)
+			}
+			return jsonToolResult(result)
+		}),
+	}
+}
+
+func (g *Gateway) createGatewayVersionTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        gatewayVersionToolName,
+		Description: "Show the version of the gateway, the commit and Go version it was built with, and its enabled feature flags.",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	return &ToolRegistration{
+		Tool: tool,
+		Handler: withToolTelemetry(gatewayVersionToolName, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonToolResult(g.buildInfo())
+		}),
+	}
+}
+
+func (g *Gateway) createGatewayStatusTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        gatewayStatusToolName,
+		Description: "Show the status of the gateway: whether it is healthy, when it started and its uptime, its transport and the number of enabled servers by state.",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	return &ToolRegistration{
+		Tool: tool,
+		Handler: withToolTelemetry(gatewayStatusToolName, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonToolResult(g.status())
+		}),
+	}
+}
+
+func (g *Gateway) buildInfo() gatewayBuildInfo {
+	info := gatewayBuildInfo{
+		Version:   version.Version,
+		GoVersion: runtime.Version(),
+		Features:  []string{},
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+
+	for _, feature := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"profiles", g.UseProfiles},
+		{"dynamic-tools", g.DynamicTools},
+		{"tool-name-prefix", g.ToolNamePrefix},
+		{"use-embeddings", g.UseEmbeddings},
+		{"oauth-interceptor", g.OAuthInterceptorEnabled},
+		{"mcp-oauth-dcr", g.McpOAuthDcrEnabled},
+	} {
+		if feature.enabled {
+			info.Features = append(info.Features, feature.name)
+		}
+	}
+	return info
+}
+
+// reloadServer lists the capabilities of a server again and updates the ones
+// exposed by the gateway. A server that's crash looping, or waiting to be
+// restarted, is started right away.
+func (g *Gateway) reloadServer(ctx context.Context, serverName string) error {
+	g.clientPool.restarts.reset(serverName)
+	oldCaps, err := g.reloadServerCapabilities(ctx, serverName, nil)
+	if err != nil {
+		return err
+	}
+
+	g.capabilitiesMu.Lock()
+	defer g.capabilitiesMu.Unlock()
+	return g.updateServerCapabilities(serverName, oldCaps, g.allCapabilities(serverName), nil)
+}
+
+func parseGatewayToolArguments(req *mcp.CallToolRequest, params any) error {
+	if len(req.Params.Arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params.Arguments, params); err != nil {
+		return fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	return nil
+}
+
+func jsonToolResult(v any) (*mcp.CallToolResult, error) {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(buf)}},
+	}, nil
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/version"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+func gatewayToolsSession(t *testing.T, options Options) (*Gateway, *mcp.ClientSession) {
+	t.Helper()
+	telemetry.Init()
+
+	g, _ := gatewayWithUpstream(t, options)
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	g.serverAvailableCapabilities = make(map[string]*Capabilities)
+	require.NoError(t, g.reloadConfiguration(t.Context(), g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return g, session
+}
+
+func listedToolNames(t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func callGatewayTool(t *testing.T, session *mcp.ClientSession, name string, arguments map[string]any, v any) {
+	t.Helper()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: name, Arguments: arguments})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%s failed: %v", name, result.Content)
+	require.Len(t, result.Content, 1)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), v))
+}
+
+func TestGatewayTools(t *testing.T) {
+	_, session := gatewayToolsSession(t, Options{GatewayTools: true})
+
+	assert.ElementsMatch(t, []string{
+		"tool",
+		"gateway__list-servers",
+		"gateway__list-tools",
+		"gateway__reload",
+		"gateway__version",
+		"gateway__status",
+	}, listedToolNames(t, session))
+
+	var statuses []serverStatus
+	callGatewayTool(t, session, "gateway__list-servers", nil, &statuses)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "upstream", statuses[0].Name)
+	assert.Equal(t, ServerStateRunning, statuses[0].State)
+	assert.Equal(t, 1, statuses[0].Tools)
+
+	var tools []gatewayToolInfo
+	callGatewayTool(t, session, "gateway__list-tools", map[string]any{"server": "upstream"}, &tools)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "tool", tools[0].Name)
+	assert.Equal(t, "upstream", tools[0].Server)
+
+	callGatewayTool(t, session, "gateway__list-tools", nil, &tools)
+	assert.Len(t, tools, 6)
+
+	var reloaded struct {
+		Reloaded []string          `json:"reloaded"`
+		Failed   map[string]string `json:"failed"`
+	}
+	callGatewayTool(t, session, "gateway__reload", map[string]any{"server": "upstream"}, &reloaded)
+	assert.Equal(t, []string{"upstream"}, reloaded.Reloaded)
+	assert.Empty(t, reloaded.Failed)
+	assert.Contains(t, listedToolNames(t, session), "tool")
+
+	_, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "gateway__reload", Arguments: map[string]any{"server": "missing"}})
+	require.ErrorContains(t, err, `server "missing" is not enabled`)
+}
+
+func TestGatewayVersionTool(t *testing.T) {
+	_, session := gatewayToolsSession(t, Options{GatewayTools: true, UseProfiles: true, DynamicTools: true})
+
+	var info map[string]any
+	callGatewayTool(t, session, "gateway__version", nil, &info)
+	assert.Equal(t, version.Version, info["version"])
+	assert.Equal(t, runtime.Version(), info["goVersion"])
+	assert.Equal(t, []any{"profiles", "dynamic-tools"}, info["features"])
+	// Test binaries don't record the commit they were built from.
+	assert.NotContains(t, info, "commit")
+}
+
+func TestGatewayStatusTool(t *testing.T) {
+	g, session := gatewayToolsSession(t, Options{GatewayTools: true, Transport: "streaming"})
+	g.startedAt = time.Now().Add(-time.Minute)
+	g.health.SetHealthy()
+
+	var first, second gatewayStatus
+	callGatewayTool(t, session, "gateway__status", nil, &first)
+	time.Sleep(10 * time.Millisecond)
+	callGatewayTool(t, session, "gateway__status", nil, &second)
+
+	assert.True(t, first.Healthy)
+	assert.Equal(t, "streaming", first.Transport)
+	assert.Equal(t, gatewayServerCounts{Enabled: 1, Running: 1}, first.Servers)
+	assert.GreaterOrEqual(t, first.Uptime, time.Minute.Seconds())
+	assert.True(t, first.StartedAt.Equal(second.StartedAt))
+	assert.Greater(t, second.Uptime, first.Uptime)
+}
+
+func TestGatewayToolsDisabledByDefault(t *testing.T) {
+	_, session := gatewayToolsSession(t, Options{})
+
+	assert.Equal(t, []string{"tool"}, listedToolNames(t, session))
+}
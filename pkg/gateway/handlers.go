@@ -10,9 +10,9 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/metric"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/policy"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
@@ -59,6 +59,14 @@ func (g *Gateway) mcpToolHandler(tool catalog.Tool) mcp.ToolHandler {
 	}
 }
 
+// serverCatalogSource returns the catalog the definition of a server comes
+// from, if any.
+func (g *Gateway) serverCatalogSource(serverName string) string {
+	g.configurationMu.Lock()
+	defer g.configurationMu.Unlock()
+	return g.configuration.serverCatalogSources[serverName]
+}
+
 func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _ *mcp.ToolAnnotations, originalToolName string) mcp.ToolHandler {
 	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Look up server configuration
@@ -77,7 +85,7 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _
 			event := buildAuditEvent(policyReq, decision, err, auditClientInfoFromSession(req.Session))
 			submitAuditEvent(g.policyClient, event)
 			if err != nil {
-				telemetry.RecordToolError(ctx, nil, serverConfig.Name, inferServerTransportType(serverConfig), req.Params.Name)
+				telemetry.RecordToolError(ctx, nil, serverConfig.Name, inferServerTransportType(serverConfig), req.Params.Name, classifyToolError(err))
 				return nil, fmt.Errorf("policy check failed for %s/%s: %w", serverConfig.Name, originalToolName, err)
 			}
 			if !decision.Allowed {
@@ -93,12 +101,17 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _
 		// Start telemetry span for tool call
 		startTime := time.Now()
 		serverTransportType := inferServerTransportType(serverConfig)
+		catalogSource := g.serverCatalogSource(serverConfig.Name)
+		g.recordServerCall(serverConfig.Name, startTime)
 
 		// Build span attributes
 		spanAttrs := []attribute.KeyValue{
 			attribute.String("mcp.server.name", serverConfig.Name),
 			attribute.String("mcp.server.type", serverTransportType),
 		}
+		if catalogSource != "" {
+			spanAttrs = append(spanAttrs, attribute.String("mcp.server.catalog", catalogSource))
+		}
 
 		// Add additional server-specific attributes
 		if serverConfig.Spec.Image != "" {
@@ -114,20 +127,36 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _
 		defer span.End()
 
 		// Record tool call counter with server attribution
-		telemetry.ToolCallCounter.Add(ctx, 1,
-			metric.WithAttributes(
-				attribute.String("mcp.server.name", serverConfig.Name),
-				attribute.String("mcp.server.type", serverTransportType),
-				attribute.String("mcp.tool.name", req.Params.Name),
-				attribute.String("mcp.client.name", req.Session.InitializeParams().ClientInfo.Name),
-			),
-		)
+		metricAttrs := []attribute.KeyValue{
+			attribute.String("mcp.server.name", serverConfig.Name),
+			attribute.String("mcp.server.type", serverTransportType),
+			attribute.String("mcp.tool.name", req.Params.Name),
+			attribute.String("mcp.client.name", req.Session.InitializeParams().ClientInfo.Name),
+		}
+		if catalogSource != "" {
+			metricAttrs = append(metricAttrs, attribute.String("mcp.server.catalog", catalogSource))
+		}
+		telemetry.ToolCallCounter.Add(ctx, 1, telemetry.WithMetricAttributes(metricAttrs...))
+
+		releaseSlot, err := g.concurrencyLimiter.acquire(ctx, serverConfig.Name, req.Session)
+		if err != nil {
+			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name, classifyToolError(err))
+			span.SetStatus(codes.Error, "Failed to acquire server slot")
+			return nil, err
+		}
+		defer releaseSlot()
 
 		client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(req.Session, server))
 		if err != nil {
-			// Record error in telemetry
-			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name)
+			// Record error in telemetry. Unless it timed out, the server
+			// failed to start.
+			errorType := classifyToolError(err)
+			if errorType == toolErrorServerError {
+				errorType = toolErrorServerCrash
+			}
+			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name, errorType)
 			span.SetStatus(codes.Error, "Failed to acquire client")
+			g.recordServerError(serverConfig.Name, err)
 			return nil, err
 		}
 		defer g.clientPool.ReleaseClient(client)
@@ -136,7 +165,7 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _
 		var args any
 		if len(req.Params.Arguments) > 0 {
 			if jsonErr := json.Unmarshal(req.Params.Arguments, &args); jsonErr != nil {
-				telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name)
+				telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name, toolErrorInvalidArgs)
 				span.SetStatus(codes.Error, "Failed to unmarshal arguments")
 				return nil, fmt.Errorf("failed to unmarshal arguments: %w", jsonErr)
 			}
@@ -152,22 +181,25 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, _
 
 		// Record duration
 		duration := time.Since(startTime).Milliseconds()
-		telemetry.ToolCallDuration.Record(ctx, float64(duration),
-			metric.WithAttributes(
-				attribute.String("mcp.server.name", serverConfig.Name),
-				attribute.String("mcp.server.type", serverTransportType),
-				attribute.String("mcp.tool.name", req.Params.Name),
-				attribute.String("mcp.client.name", req.Session.InitializeParams().ClientInfo.Name),
-			),
-		)
+		telemetry.ToolCallDuration.Record(ctx, float64(duration), telemetry.WithMetricAttributes(metricAttrs...))
 
 		if err != nil {
 			// Record error in telemetry
-			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name)
+			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverTransportType, req.Params.Name, classifyToolError(err))
 			span.SetStatus(codes.Error, "Tool execution failed")
+			g.recordServerError(serverConfig.Name, err)
 			return nil, err
 		}
 
+		if expression, ok := g.toolResultTransform(serverConfig.Name, originalToolName); ok {
+			transformed, err := transformToolResult(result, expression)
+			if err != nil {
+				log.Logf("Failed to transform the result of tool %s on server %s: %v", originalToolName, serverConfig.Name, err)
+			} else {
+				result = transformed
+			}
+		}
+
 		span.SetStatus(codes.Ok, "")
 		return result, nil
 	}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
@@ -245,7 +246,7 @@ func TestTelemetryErrorRecording(t *testing.T) {
 	)
 
 	// Record an error
-	telemetry.RecordToolError(ctx, span, serverName, serverType, toolName)
+	telemetry.RecordToolError(ctx, span, serverName, serverType, toolName, "server-error")
 	span.SetStatus(otelcodes.Error, "tool execution failed")
 	span.End()
 
@@ -351,3 +352,72 @@ func assertMetricAttribute(t *testing.T, set attribute.Set, key string, expected
 	assert.True(t, found, "Attribute %s not found", key)
 	assert.Equal(t, expectedValue, value.AsString())
 }
+
+// TestToolCallRecordsCatalogSource tests that tool calls are attributed to the
+// catalog the server came from
+func TestToolCallRecordsCatalogSource(t *testing.T) {
+	spanRecorder, metricReader := setupTestTelemetry(t)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	// Keep the only slot busy so that the call stops before starting the server.
+	concurrencyLimiter, err := newServerConcurrencyLimiter([]string{"github:1"})
+	require.NoError(t, err)
+	_, err = concurrencyLimiter.acquire(t.Context(), "github", serverSession)
+	require.NoError(t, err)
+
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Name: "github", Image: "mcp/github"},
+			},
+			serverCatalogSources: map[string]string{
+				"github": "mcp/docker-mcp-catalog:latest",
+			},
+		},
+		concurrencyLimiter: concurrencyLimiter,
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	handler := g.mcpServerToolHandler("github", server, nil, "search")
+	_, err = handler(ctx, &mcp.CallToolRequest{
+		Session: serverSession,
+		Params:  &mcp.CallToolParamsRaw{Name: "search"},
+	})
+	require.Error(t, err)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assertAttribute(t, spans[0].Attributes(), "mcp.server.catalog", "mcp/docker-mcp-catalog:latest")
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, metricReader.Collect(t.Context(), rm))
+
+	var foundCounter bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "mcp.tool.calls" {
+				continue
+			}
+			foundCounter = true
+			data, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			require.Len(t, data.DataPoints, 1)
+			attrs := data.DataPoints[0].Attributes
+			assertMetricAttribute(t, attrs, "mcp.server.name", "github")
+			assertMetricAttribute(t, attrs, "mcp.server.catalog", "mcp/docker-mcp-catalog:latest")
+			assertMetricAttribute(t, attrs, "mcp.client.name", "test-client")
+		}
+	}
+	assert.True(t, foundCounter, "Tool call counter not found")
+}
@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/shlex"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+const (
+	hookPreStart = "preStart"
+	hookPostStop = "postStop"
+
+	hookTimeout = 30 * time.Second
+)
+
+// runHook runs a preStart or postStop hook of a server, if it has one. A
+// failing hook returns an error, unless its failure policy is to ignore it.
+func (cp *clientPool) runHook(ctx context.Context, serverConfig *catalog.ServerConfig, stage string) error {
+	hook := serverHook(serverConfig, stage)
+	if hook == nil {
+		return nil
+	}
+
+	log.Log("  - Running", stage, "hook of", serverConfig.Name)
+	if err := cp.execHook(ctx, serverConfig, hook); err != nil {
+		err = fmt.Errorf("%s hook of server %s failed: %w", stage, serverConfig.Name, err)
+		if hook.OnFailure == catalog.HookOnFailureIgnore {
+			log.Log("  - Ignoring:", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// execHook runs the command of a hook. The command is run without a shell and
+// doesn't inherit the gateway's environment: it only gets PATH and the name
+// of the server in MCP_SERVER_NAME. Only the commands allowed with
+// --hook-command, executable and arguments, can be run.
+func (cp *clientPool) execHook(ctx context.Context, serverConfig *catalog.ServerConfig, hook *catalog.Hook) error {
+	command := eval.EvaluateList(hook.Command, serverConfig.Config)
+	if len(command) == 0 || command[0] == "" {
+		return errors.New("no command")
+	}
+	if !isAllowedHookCommand(cp.HookCommands, command) {
+		return fmt.Errorf("%s is not allowed, use --hook-command '%s' to allow it", command[0], quoteCommand(command))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"MCP_SERVER_NAME=" + serverConfig.Name,
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
+
+// isAllowedHookCommand reports whether the command, once its templates are
+// evaluated, is one of the allowed commands. The whole command is compared,
+// not only its executable, so that a hook can't pass other arguments to an
+// allowed executable, e.g. sh -c.
+func isAllowedHookCommand(allowed []string, command []string) bool {
+	return slices.ContainsFunc(allowed, func(allowedCommand string) bool {
+		args, err := shlex.Split(allowedCommand)
+		return err == nil && slices.Equal(args, command)
+	})
+}
+
+// validateHookCommands checks that the --hook-command values can be parsed.
+func validateHookCommands(commands []string) error {
+	for _, command := range commands {
+		args, err := shlex.Split(command)
+		if err != nil {
+			return fmt.Errorf("invalid --hook-command %q: %w", command, err)
+		}
+		if len(args) == 0 {
+			return errors.New("--hook-command must not be empty")
+		}
+	}
+	return nil
+}
+
+// quoteCommand formats a command as a --hook-command value.
+func quoteCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\#") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+func serverHook(serverConfig *catalog.ServerConfig, stage string) *catalog.Hook {
+	hooks := serverConfig.Spec.Hooks
+	if hooks == nil {
+		return nil
+	}
+	switch stage {
+	case hookPreStart:
+		return hooks.PreStart
+	case hookPostStop:
+		return hooks.PostStop
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// hookTestServer returns a server whose hooks append their stage to logFile.
+func hookTestServer(t *testing.T, logFile, preStart string) *catalog.ServerConfig {
+	t.Helper()
+	return &catalog.ServerConfig{
+		Name: "seeded",
+		Spec: parseSpec(t, fmt.Sprintf(`
+image: mcp/seeded
+hooks:
+  preStart:
+    command: [sh, -c, '%s']
+  postStop:
+    command: [sh, -c, 'echo "postStop $MCP_SERVER_NAME" >> %s']
+`, preStart, logFile)),
+	}
+}
+
+// allowedHooks returns the --hook-command values allowing the hooks of a
+// server.
+func allowedHooks(serverConfig *catalog.ServerConfig) []string {
+	var allowed []string
+	for _, hook := range []*catalog.Hook{serverConfig.Spec.Hooks.PreStart, serverConfig.Spec.Hooks.PostStop} {
+		if hook != nil {
+			allowed = append(allowed, quoteCommand(hook.Command))
+		}
+	}
+	return allowed
+}
+
+func readHookLog(t *testing.T, logFile string) []string {
+	t.Helper()
+	buf, err := os.ReadFile(logFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(buf)), "\n")
+}
+
+func TestHooksRunAroundContainer(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "hooks.log")
+
+	// A docker that records it was run, then exits before initialization.
+	fakeDocker := fmt.Sprintf("#!/bin/sh\necho docker >> %s\nexit 1\n", logFile)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(fakeDocker), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	serverConfig := hookTestServer(t, logFile, `echo "preStart $MCP_SERVER_NAME" >> `+logFile)
+	cp := &clientPool{Options: Options{HookCommands: allowedHooks(serverConfig)}}
+
+	_, err := newClientGetter(serverConfig, cp, nil).GetClient(t.Context())
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"preStart seeded", "docker", "postStop seeded"}, readHookLog(t, logFile))
+}
+
+func TestFailingPreStartAbortsStart(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "hooks.log")
+
+	fakeDocker := fmt.Sprintf("#!/bin/sh\necho docker >> %s\nexit 1\n", logFile)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(fakeDocker), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	serverConfig := hookTestServer(t, logFile, "echo network exists >&2; exit 3")
+	cp := &clientPool{Options: Options{HookCommands: allowedHooks(serverConfig)}}
+
+	_, err := newClientGetter(serverConfig, cp, nil).GetClient(t.Context())
+	require.ErrorContains(t, err, "preStart hook of server seeded failed")
+	require.ErrorContains(t, err, "network exists")
+
+	// Neither the container nor the postStop hook ran.
+	assert.Empty(t, readHookLog(t, logFile))
+}
+
+func TestRunHookFailurePolicy(t *testing.T) {
+	serverConfig := &catalog.ServerConfig{
+		Name: "seeded",
+		Spec: catalog.Server{Hooks: &catalog.Hooks{
+			PreStart: &catalog.Hook{Command: []string{"false"}},
+		}},
+	}
+	cp := &clientPool{Options: Options{HookCommands: []string{"false"}}}
+
+	err := cp.runHook(t.Context(), serverConfig, hookPreStart)
+	require.ErrorContains(t, err, "preStart hook of server seeded failed")
+
+	serverConfig.Spec.Hooks.PreStart.OnFailure = catalog.HookOnFailureIgnore
+	require.NoError(t, cp.runHook(t.Context(), serverConfig, hookPreStart))
+
+	// No hook, nothing to run.
+	require.NoError(t, cp.runHook(t.Context(), serverConfig, hookPostStop))
+}
+
+func TestRunHookRequiresAllowedCommand(t *testing.T) {
+	serverConfig := &catalog.ServerConfig{
+		Name: "seeded",
+		Spec: catalog.Server{Hooks: &catalog.Hooks{
+			PreStart: &catalog.Hook{Command: []string{"true"}},
+		}},
+	}
+
+	cp := &clientPool{}
+	err := cp.runHook(t.Context(), serverConfig, hookPreStart)
+	require.ErrorContains(t, err, "true is not allowed, use --hook-command 'true' to allow it")
+
+	cp = &clientPool{Options: Options{HookCommands: []string{"true"}}}
+	require.NoError(t, cp.runHook(t.Context(), serverConfig, hookPreStart))
+
+	serverConfig.Spec.Hooks.PreStart.Command = nil
+	require.ErrorContains(t, cp.runHook(t.Context(), serverConfig, hookPreStart), "no command")
+}
+
+func TestRunHookRefusesOtherArguments(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "injected.log")
+	serverConfig := &catalog.ServerConfig{
+		Name: "seeded",
+		Spec: catalog.Server{Hooks: &catalog.Hooks{
+			PreStart: &catalog.Hook{Command: []string{"sh", "-c", "echo injected > " + logFile}},
+			PostStop: &catalog.Hook{Command: []string{"true", "{{seeded.flag}}"}},
+		}},
+		Config: map[string]any{"seeded": map[string]any{"flag": "--injected"}},
+	}
+
+	// Allowing the executable doesn't allow any argument.
+	cp := &clientPool{Options: Options{HookCommands: []string{"sh", "sh -c 'echo seeded'", "true"}}}
+	err := cp.runHook(t.Context(), serverConfig, hookPreStart)
+	require.ErrorContains(t, err, `sh is not allowed, use --hook-command 'sh -c "echo injected > `)
+	assert.NoFileExists(t, logFile)
+
+	// Neither do the values of the config.
+	err = cp.runHook(t.Context(), serverConfig, hookPostStop)
+	require.ErrorContains(t, err, "true is not allowed, use --hook-command 'true --injected' to allow it")
+
+	cp = &clientPool{Options: Options{HookCommands: []string{"true --injected"}}}
+	require.NoError(t, cp.runHook(t.Context(), serverConfig, hookPostStop))
+}
+
+func TestValidateHookCommands(t *testing.T) {
+	require.NoError(t, validateHookCommands([]string{"/usr/local/bin/seed-db", `sh -c "echo seeded"`}))
+	require.ErrorContains(t, validateHookCommands([]string{`sh -c "echo`}), "invalid --hook-command")
+	require.EqualError(t, validateHookCommands([]string{" "}), "--hook-command must not be empty")
+}
+
+func TestRunHookDoesNotInheritEnvironment(t *testing.T) {
+	t.Setenv("GATEWAY_SECRET", "secret")
+	logFile := filepath.Join(t.TempDir(), "env.log")
+
+	serverConfig := &catalog.ServerConfig{
+		Name: "seeded",
+		Spec: catalog.Server{Hooks: &catalog.Hooks{
+			PostStop: &catalog.Hook{Command: []string{"sh", "-c", "env > " + logFile}},
+		}},
+	}
+	cp := &clientPool{Options: Options{HookCommands: allowedHooks(serverConfig)}}
+	require.NoError(t, cp.runHook(t.Context(), serverConfig, hookPostStop))
+
+	env := readHookLog(t, logFile)
+	assert.Contains(t, env, "MCP_SERVER_NAME=seeded")
+	assert.NotContains(t, env, "GATEWAY_SECRET=secret")
+}
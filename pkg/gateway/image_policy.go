@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func (g *Gateway) imagePolicy() imagepolicy.Policy {
+	return imagepolicy.Policy{Allow: g.AllowImages, Deny: g.DenyImages}
+}
+
+// profileImagePolicy returns the image policy stored with the profile, if any.
+func profileImagePolicy(workingSet workingset.WorkingSet) imagepolicy.Policy {
+	if workingSet.ImagePolicy == nil {
+		return imagepolicy.Policy{}
+	}
+	return *workingSet.ImagePolicy
+}
+
+// filterByImagePolicy removes the servers whose image, or the image of one of
+// their POCI tools, isn't allowed by the policy, both the enabled ones and the
+// catalog ones that could be added later on.
+func (c *Configuration) filterByImagePolicy(policy imagepolicy.Policy) {
+	if policy.IsEmpty() {
+		return
+	}
+
+	enabled := make(map[string]bool, len(c.serverNames))
+	for _, serverName := range c.serverNames {
+		enabled[serverName] = true
+	}
+
+	rejected := make(map[string]bool)
+	for _, serverName := range slices.Sorted(maps.Keys(c.servers)) {
+		for _, image := range workingset.ServerImages(c.servers[serverName]) {
+			if err := policy.Check(image); err != nil {
+				if enabled[serverName] {
+					log.Logf("  - Server %s rejected: %v", serverName, err)
+				}
+				rejected[serverName] = true
+				delete(c.servers, serverName)
+				break
+			}
+		}
+	}
+
+	serverNames := make([]string, 0, len(c.serverNames))
+	for _, serverName := range c.serverNames {
+		if !rejected[serverName] {
+			serverNames = append(serverNames, serverName)
+		}
+	}
+	c.serverNames = serverNames
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func TestFilterByImagePolicy(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"fetch", "untrusted", "notion"},
+		servers: map[string]catalog.Server{
+			"fetch":     {Name: "fetch", Type: "server", Image: "mcp/fetch@sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+			"untrusted": {Name: "untrusted", Type: "server", Image: "ghcr.io/example/untrusted:latest"},
+			"notion":    {Name: "notion", Type: "remote", Remote: catalog.Remote{URL: "https://mcp.notion.com/mcp"}},
+			// Not enabled, but could be added from the catalog.
+			"internal": {Name: "internal", Type: "server", Image: "registry.internal/team/internal:1.0"},
+			"other":    {Name: "other", Type: "server", Image: "ghcr.io/example/other:latest"},
+		},
+	}
+
+	configuration.filterByImagePolicy(imagepolicy.Policy{Allow: []string{"mcp/*", "registry.internal/*"}})
+
+	assert.Equal(t, []string{"fetch", "notion"}, configuration.serverNames)
+	assert.Contains(t, configuration.servers, "fetch")
+	assert.Contains(t, configuration.servers, "notion")
+	assert.Contains(t, configuration.servers, "internal")
+	assert.NotContains(t, configuration.servers, "untrusted")
+	assert.NotContains(t, configuration.servers, "other")
+}
+
+func TestFilterByImagePolicyChecksPOCIToolImages(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"allowed-tools", "untrusted-tools"},
+		servers: map[string]catalog.Server{
+			"allowed-tools": {
+				Name:  "allowed-tools",
+				Type:  catalog.ServerTypePOCI,
+				Image: "ghcr.io/example/builder:latest",
+				Tools: []catalog.Tool{{Name: "fetch", Container: catalog.Container{Image: "mcp/fetch:latest"}}},
+			},
+			"untrusted-tools": {
+				Name: "untrusted-tools",
+				Type: catalog.ServerTypePOCI,
+				Tools: []catalog.Tool{
+					{Name: "fetch", Container: catalog.Container{Image: "mcp/fetch:latest"}},
+					{Name: "curl", Container: catalog.Container{Image: "ghcr.io/example/curl:latest"}},
+				},
+			},
+		},
+	}
+
+	configuration.filterByImagePolicy(imagepolicy.Policy{Allow: []string{"mcp/*"}})
+
+	assert.Equal(t, []string{"allowed-tools"}, configuration.serverNames)
+	assert.NotContains(t, configuration.servers, "untrusted-tools")
+}
+
+func TestRunRejectsInvalidImagePattern(t *testing.T) {
+	g := NewGateway(Config{Options: Options{AllowImages: []string{"mcp/["}}}, nil)
+
+	err := g.Run(t.Context())
+
+	assert.EqualError(t, err, `invalid image pattern "mcp/["`)
+}
+
+func TestFilterByProfileImagePolicy(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	imageServer := func(name, image string) db.Server {
+		return db.Server{
+			Type:     string(workingset.ServerTypeImage),
+			Image:    image,
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: name, Type: "server", Image: image}},
+		}
+	}
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:          "dev",
+		Name:        "dev",
+		Servers:     db.ServerList{imageServer("fetch", "mcp/fetch:latest"), imageServer("untrusted", "ghcr.io/example/untrusted:latest")},
+		Secrets:     db.SecretMap{},
+		ImagePolicy: &db.ImagePolicy{Allow: []string{"mcp/*"}},
+	}))
+
+	cfg := NewWorkingSetConfiguration(Config{WorkingSet: "dev"}, mocks.NewMockOCIService(), nil)
+	configuration, err := cfg.readOnce(t.Context(), dao)
+	require.NoError(t, err)
+	assert.Equal(t, imagepolicy.Policy{Allow: []string{"mcp/*"}}, configuration.imagePolicy)
+
+	configuration.filterByImagePolicy(configuration.imagePolicy)
+
+	assert.Equal(t, []string{"fetch"}, configuration.serverNames)
+	assert.NotContains(t, configuration.servers, "untrusted")
+}
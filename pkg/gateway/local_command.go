@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	"github.com/docker/mcp-gateway/pkg/log"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// localCommandClient returns a client for a server that runs a local command
+// over stdio. The process doesn't inherit the gateway's environment: it only
+// gets PATH, the server's env and its declared secrets. Network restrictions
+// can't be enforced outside a container, so such servers are refused when
+// they are requested.
+func (cp *clientPool) localCommandClient(ctx context.Context, serverConfig *catalog.ServerConfig) (mcpclient.Client, error) {
+	if cp.BlockNetwork || serverConfig.Spec.DisableNetwork || len(serverConfig.Spec.AllowHosts) > 0 {
+		return nil, fmt.Errorf("cannot start local command server %s: network restrictions can only be enforced for containers", serverConfig.Name)
+	}
+
+	command, env, err := cp.localCommand(ctx, serverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Log("  - Running local command", command)
+	return mcpclient.NewStdioCmdClient(serverConfig.Name, command[0], env, command[1:]...), nil
+}
+
+// localCommand returns the command run on the host for a command server, with
+// its templates evaluated, and its env.
+func (cp *clientPool) localCommand(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+	// Only the environment applies, the docker run arguments are ignored.
+	_, env, err := cp.argsAndEnv(ctx, serverConfig, proxies.TargetConfig{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	command := expandEnvList(eval.EvaluateList(serverConfig.Spec.Command, serverConfig.Config), env)
+	if len(command) == 0 || command[0] == "" {
+		return nil, nil, fmt.Errorf("local command server %s has no command", serverConfig.Name)
+	}
+	return command, env, nil
+}
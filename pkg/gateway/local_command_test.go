@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func localCommandConfiguration(sourceType string) Configuration {
+	return Configuration{
+		serverNames: []string{"dev"},
+		servers: map[string]catalog.Server{
+			"dev": {
+				Name:    "dev",
+				Type:    catalog.ServerTypeCommand,
+				Command: []string{"./bin/my-mcp", "--stdio"},
+				Secrets: []catalog.Secret{{Name: "dev.token", Env: "TOKEN"}},
+			},
+		},
+		secrets: map[string]string{
+			"dev.token":   "secret",
+			"other.token": "other",
+		},
+		serverSourceTypeOverrides: map[string]string{
+			"dev": sourceType,
+		},
+	}
+}
+
+func TestFindLocalCommandServerFromProfile(t *testing.T) {
+	configuration := localCommandConfiguration(catalog.ServerTypeCommand)
+
+	serverConfig, tools, found := configuration.Find("dev")
+	require.True(t, found)
+	assert.Nil(t, tools)
+	require.NotNil(t, serverConfig)
+	assert.Equal(t, []string{"./bin/my-mcp", "--stdio"}, serverConfig.Spec.Command)
+	assert.Equal(t, map[string]string{"dev.token": "secret"}, serverConfig.Secrets)
+}
+
+func TestFindLocalCommandServerFromCatalogIsIgnored(t *testing.T) {
+	configuration := localCommandConfiguration("registry")
+
+	serverConfig, tools, found := configuration.Find("dev")
+	assert.False(t, found)
+	assert.Nil(t, serverConfig)
+	assert.Nil(t, tools)
+}
+
+func TestLocalCommandClientRefusesNetworkRestrictions(t *testing.T) {
+	configuration := localCommandConfiguration(catalog.ServerTypeCommand)
+	serverConfig, _, found := configuration.Find("dev")
+	require.True(t, found)
+
+	cp := &clientPool{Options: Options{BlockNetwork: true}}
+	_, err := cp.localCommandClient(t.Context(), serverConfig)
+	require.ErrorContains(t, err, "network restrictions can only be enforced for containers")
+
+	cp = &clientPool{}
+	serverConfig.Spec.DisableNetwork = true
+	_, err = cp.localCommandClient(t.Context(), serverConfig)
+	require.ErrorContains(t, err, "network restrictions can only be enforced for containers")
+}
+
+func TestLocalCommandClient(t *testing.T) {
+	configuration := localCommandConfiguration(catalog.ServerTypeCommand)
+	serverConfig, _, found := configuration.Find("dev")
+	require.True(t, found)
+
+	cp := &clientPool{}
+	client, err := cp.localCommandClient(t.Context(), serverConfig)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+
+	serverConfig.Spec.Command = nil
+	_, err = cp.localCommandClient(t.Context(), serverConfig)
+	require.ErrorContains(t, err, "has no command")
+}
+
+func TestFindLocalCommandServerFromPulledProfileIsIgnored(t *testing.T) {
+	configuration := localCommandConfiguration(catalog.ServerTypeCommand)
+	configuration.profileOrigin = workingset.OriginPulled
+
+	serverConfig, tools, found := configuration.Find("dev")
+	assert.False(t, found)
+	assert.Nil(t, serverConfig)
+	assert.Nil(t, tools)
+}
+
+func TestReadProfileWithLocalCommandServer(t *testing.T) {
+	for _, origin := range []string{"", workingset.OriginPulled, workingset.OriginImported} {
+		t.Run("origin="+origin, func(t *testing.T) {
+			dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, dao.Close())
+			})
+			require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+				ID:   "dev",
+				Name: "dev",
+				Servers: db.ServerList{{
+					Type:     string(workingset.ServerTypeCommand),
+					Exec:     "./bin/my-mcp",
+					Args:     []string{"--stdio"},
+					Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "my-mcp", Type: catalog.ServerTypeCommand}},
+				}},
+				Secrets: db.SecretMap{},
+				Origin:  origin,
+			}))
+
+			cfg := NewWorkingSetConfiguration(Config{WorkingSet: "dev"}, mocks.NewMockOCIService(), nil)
+			configuration, err := cfg.readOnce(t.Context(), dao)
+			if origin != "" {
+				require.EqualError(t, err, "profile dev was "+origin+", its command server my-mcp is not run")
+				return
+			}
+			require.NoError(t, err)
+			serverConfig, _, found := configuration.Find("my-mcp")
+			require.True(t, found)
+			assert.Equal(t, []string{"./bin/my-mcp", "--stdio"}, serverConfig.Spec.Command)
+		})
+	}
+}
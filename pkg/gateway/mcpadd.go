@@ -99,20 +99,14 @@ func addServerHandler(g *Gateway, clientConfig *clientConfig) mcp.ToolHandler {
 		// Check if all required secrets are set
 		var missingSecrets []string
 		var availableSecrets map[string]string
-		if serverConfig != nil && len(serverConfig.Spec.Secrets) > 0 {
+		if serverConfig != nil && len(serverConfig.Spec.StoredSecrets()) > 0 {
 			// BuildSecretsURIs only includes secrets that exist in Secrets Engine
 			configs := []ServerSecretConfig{{
-				Secrets: serverConfig.Spec.Secrets,
+				Secrets: serverConfig.Spec.StoredSecrets(),
 				OAuth:   serverConfig.Spec.OAuth,
 			}}
 			availableSecrets = BuildSecretsURIs(ctx, configs)
-
-			// Check which secrets are missing
-			for _, secret := range serverConfig.Spec.Secrets {
-				if _, exists := availableSecrets[secret.Name]; !exists {
-					missingSecrets = append(missingSecrets, secret.Name)
-				}
-			}
+			missingSecrets = unsetSecrets(&serverConfig.Spec, availableSecrets, serverConfig.Config)
 		}
 
 		// Check if all required config values are set and validate against schema
@@ -220,6 +214,21 @@ func addServerHandler(g *Gateway, clientConfig *clientConfig) mcp.ToolHandler {
 			g.configuration.AddSecrets(availableSecrets)
 		}
 
+		// Read the file:// config values of the server, as for the servers
+		// enabled when the gateway started.
+		if err := g.configuration.resolveServerConfigFiles(serverName, g.ConfigFileDirs); err != nil {
+			if !alreadyEnabled {
+				g.configuration.serverNames = slices.DeleteFunc(g.configuration.serverNames, func(name string) bool {
+					return name == serverName
+				})
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Cannot add server '%s'. %s\n\nThe server was not added.", serverName, err),
+				}},
+			}, nil
+		}
+
 		// Pull the Docker image before trying to use the server
 		if serverConfig.Spec.Image != "" {
 			log.Log(fmt.Sprintf("Pulling image for server '%s': %s", serverName, serverConfig.Spec.Image))
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// listAllTools lists the tools of a server, following the cursors of servers
+// that return their tools in several pages.
+func listAllTools(ctx context.Context, session *mcp.ClientSession) ([]*mcp.Tool, error) {
+	var tools []*mcp.Tool
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// listAllResources lists the resources of a server, following the cursors of
+// servers that return their resources in several pages. With a limit above 0,
+// it stops after limit resources and reports whether the server had more.
+func listAllResources(ctx context.Context, session *mcp.ClientSession, limit int) ([]*mcp.Resource, bool, error) {
+	var resources []*mcp.Resource
+	for resource, err := range session.Resources(ctx, nil) {
+		if err != nil {
+			return nil, false, err
+		}
+		if limit > 0 && len(resources) == limit {
+			return resources, true, nil
+		}
+		resources = append(resources, resource)
+	}
+	return resources, false, nil
+}
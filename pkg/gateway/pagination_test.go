@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// manyToolsServer starts an in-memory MCP server exposing count tools, which
+// it lists in pages of pageSize tools.
+func manyToolsServer(t *testing.T, prefix string, count, pageSize int) *sessionClient {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: prefix, Version: "1.0.0"}, &mcp.ServerOptions{PageSize: pageSize})
+	for i := range count {
+		server.AddTool(&mcp.Tool{Name: fmt.Sprintf("%s_%03d", prefix, i), InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{}, nil
+		})
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}
+}
+
+// paginatedGatewaySession starts a gateway serving the given servers and
+// returns the session of a client connected to it.
+func paginatedGatewaySession(t *testing.T, options Options, clients map[string]*sessionClient) *mcp.ClientSession {
+	t.Helper()
+	telemetry.Init()
+
+	g := &Gateway{
+		Options: options,
+		configuration: Configuration{
+			servers: map[string]catalog.Server{},
+		},
+		clientPool: &clientPool{keptClients: map[clientKey]keptClient{}},
+	}
+	for _, serverName := range slices.Sorted(maps.Keys(clients)) {
+		g.configuration.serverNames = append(g.configuration.serverNames, serverName)
+		g.configuration.servers[serverName] = catalog.Server{Name: serverName, Image: "mcp/" + serverName}
+
+		getter := &clientGetter{client: clients[serverName]}
+		getter.once.Do(func() {}) // mark as created
+		g.clientPool.keptClients[clientKey{serverName: serverName}] = keptClient{Name: serverName, Getter: getter}
+	}
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, &mcp.ServerOptions{PageSize: g.PageSize})
+	g.serverAvailableCapabilities = make(map[string]*Capabilities)
+	require.NoError(t, g.reloadConfiguration(t.Context(), g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session
+}
+
+func TestListToolsPagination(t *testing.T) {
+	session := paginatedGatewaySession(t, Options{PageSize: 25}, map[string]*sessionClient{
+		"alpha": manyToolsServer(t, "alpha", 70, 10),
+		"beta":  manyToolsServer(t, "beta", 50, 7),
+	})
+
+	listPages := func() [][]string {
+		var pages [][]string
+		params := &mcp.ListToolsParams{}
+		for {
+			result, err := session.ListTools(t.Context(), params)
+			require.NoError(t, err)
+
+			var page []string
+			for _, tool := range result.Tools {
+				page = append(page, tool.Name)
+			}
+			pages = append(pages, page)
+
+			if result.NextCursor == "" {
+				return pages
+			}
+			params.Cursor = result.NextCursor
+		}
+	}
+
+	pages := listPages()
+	require.Len(t, pages, 5)
+	for _, page := range pages[:4] {
+		assert.Len(t, page, 25)
+	}
+	assert.Len(t, pages[4], 20)
+
+	var expected []string
+	for i := range 70 {
+		expected = append(expected, fmt.Sprintf("alpha_%03d", i))
+	}
+	for i := range 50 {
+		expected = append(expected, fmt.Sprintf("beta_%03d", i))
+	}
+	assert.Equal(t, expected, slices.Concat(pages...))
+
+	// Paging again from the start returns the same pages.
+	assert.Equal(t, pages, listPages())
+
+	_, err := session.ListTools(t.Context(), &mcp.ListToolsParams{Cursor: "not-a-cursor"})
+	require.Error(t, err)
+}
+
+// manyResourcesServer starts an in-memory MCP server exposing count
+// resources, which it lists in pages of pageSize resources.
+func manyResourcesServer(t *testing.T, prefix string, count, pageSize int) *sessionClient {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: prefix, Version: "1.0.0"}, &mcp.ServerOptions{PageSize: pageSize})
+	for i := range count {
+		name := fmt.Sprintf("%s_%03d", prefix, i)
+		server.AddResource(&mcp.Resource{Name: name, URI: "file:///" + name}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{}, nil
+		})
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}
+}
+
+func TestListResourcesPaginationWithLimit(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetLogWriter(&logs)
+	defer log.SetLogWriter(os.Stderr)
+
+	session := paginatedGatewaySession(t, Options{PageSize: 25, MaxResourcesPerServer: 60}, map[string]*sessionClient{
+		"alpha": manyResourcesServer(t, "alpha", 70, 10),
+		"beta":  manyResourcesServer(t, "beta", 30, 7),
+	})
+
+	var pages [][]string
+	params := &mcp.ListResourcesParams{}
+	for {
+		result, err := session.ListResources(t.Context(), params)
+		require.NoError(t, err)
+
+		var page []string
+		for _, resource := range result.Resources {
+			page = append(page, resource.Name)
+		}
+		pages = append(pages, page)
+
+		if result.NextCursor == "" {
+			break
+		}
+		params.Cursor = result.NextCursor
+	}
+
+	require.Len(t, pages, 4)
+	for _, page := range pages[:3] {
+		assert.Len(t, page, 25)
+	}
+	assert.Len(t, pages[3], 15)
+
+	// All the resources of beta, but only the first 60 of alpha.
+	names := slices.Concat(pages...)
+	assert.Len(t, names, 90)
+	assert.Contains(t, names, "alpha_059")
+	assert.NotContains(t, names, "alpha_060")
+	assert.Contains(t, names, "beta_029")
+
+	assert.Contains(t, logs.String(), "Warning: alpha lists more than 60 resources, only the first 60 are exposed")
+	assert.NotContains(t, logs.String(), "Warning: beta")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/mcp"
 )
 
@@ -33,6 +34,18 @@ func (c *clientWithCleanup) Close() error {
 	return errors.Join(c.Client.Session().Close(), c.cleanup(context.TODO()))
 }
 
+// closeClient closes the session of a client and runs its cleanup, e.g. to
+// remove its network proxies or run its postStop hook.
+func closeClient(client mcp.Client) {
+	if c, ok := client.(*clientWithCleanup); ok {
+		if err := c.Close(); err != nil {
+			log.Log("Warning - failed to close client:", err)
+		}
+		return
+	}
+	client.Session().Close()
+}
+
 type clientWithCleanup struct {
 	mcp.Client
 	cleanup func(context.Context) error
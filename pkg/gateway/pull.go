@@ -3,17 +3,64 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/distribution/reference"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/signatures"
 )
 
 var verifyDockerImageSignatures = signatures.Verify
 
+// serverPullPolicies are the pull options that servers can use as their
+// pullPolicy.
+var serverPullPolicies = []string{catalognext.PullOptionMissing, catalognext.PullOptionNever, catalognext.PullOptionAlways}
+
+// containerPullPolicy returns the --pull flag value of the server's container.
+// Without a pull policy, the image was pulled when the gateway started.
+func containerPullPolicy(serverConfig *catalog.ServerConfig) (string, error) {
+	pullPolicy := serverConfig.Spec.PullPolicy
+	if pullPolicy == "" {
+		return catalognext.PullOptionNever, nil
+	}
+	if !slices.Contains(serverPullPolicies, pullPolicy) {
+		return "", fmt.Errorf("invalid pull policy %q for server %s: must be one of %s", pullPolicy, serverConfig.Name, strings.Join(serverPullPolicies, ", "))
+	}
+	return pullPolicy, nil
+}
+
+// neverPulledImages returns the images that are only run by servers with the
+// never pull policy, and thus aren't pulled when the gateway starts.
+func neverPulledImages(configuration Configuration) map[string]bool {
+	never := map[string]bool{}
+	pulled := map[string]bool{}
+	for _, serverName := range configuration.serverNames {
+		serverConfig, tools, found := configuration.Find(serverName)
+		switch {
+		case !found:
+		case serverConfig != nil && serverConfig.Spec.Image != "":
+			if serverConfig.Spec.PullPolicy == catalognext.PullOptionNever {
+				never[serverConfig.Spec.Image] = true
+			} else {
+				pulled[serverConfig.Spec.Image] = true
+			}
+		case tools != nil:
+			for _, tool := range *tools {
+				pulled[tool.Container.Image] = true
+			}
+		}
+	}
+	for image := range pulled {
+		delete(never, image)
+	}
+	return never
+}
+
 func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration) error {
 	dockerImages := configuration.DockerImages()
 	if len(dockerImages) == 0 {
@@ -22,6 +69,8 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 
 	log.Log("- Using images:")
 
+	// Images of servers that are never pulled are still verified: the pull
+	// policy doesn't exempt them from signatures or digest pins.
 	var verifiableImages []string
 	for _, image := range dockerImages {
 		log.Log("  - " + image)
@@ -34,11 +83,15 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 		return err
 	}
 
-	if err := g.pullImages(ctx, dockerImages); err != nil {
-		return err
+	never := neverPulledImages(configuration)
+	pulledImages := slices.DeleteFunc(dockerImages, func(image string) bool {
+		return never[image]
+	})
+	if len(pulledImages) == 0 {
+		return nil
 	}
 
-	return nil
+	return g.pullImages(ctx, pulledImages)
 }
 
 func (g *Gateway) pullAndVerifyImage(ctx context.Context, image string) error {
@@ -61,6 +61,64 @@ func TestPullAndVerifyVerifiesMCPImagesBeforePull(t *testing.T) {
 	}, events)
 }
 
+func TestPullAndVerifySkipsImagesOfServersThatNeverPull(t *testing.T) {
+	docker := &recordingDockerClient{}
+	g := &Gateway{docker: docker}
+
+	err := g.pullAndVerify(context.Background(), Configuration{
+		serverNames: []string{"local", "always", "missing", "shared-never", "shared-default"},
+		servers: map[string]catalog.Server{
+			"local":          {Image: "acme/local:dev", PullPolicy: "never"},
+			"always":         {Image: "acme/always:latest", PullPolicy: "always"},
+			"missing":        {Image: "acme/missing:latest", PullPolicy: "missing"},
+			"shared-never":   {Image: "acme/shared:latest", PullPolicy: "never"},
+			"shared-default": {Image: "acme/shared:latest"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme/always:latest", "acme/missing:latest", "acme/shared:latest"}, docker.pulledImages)
+}
+
+func TestPullAndVerifyVerifiesImagesOfServersThatNeverPull(t *testing.T) {
+	oldVerify := verifyDockerImageSignatures
+	defer func() {
+		verifyDockerImageSignatures = oldVerify
+	}()
+
+	var verified []string
+	verifyDockerImageSignatures = func(_ context.Context, images []string) error {
+		verified = append(verified, images...)
+		return nil
+	}
+
+	docker := &recordingDockerClient{}
+	g := &Gateway{
+		Options: Options{VerifySignatures: true},
+		docker:  docker,
+	}
+
+	err := g.pullAndVerify(context.Background(), Configuration{
+		serverNames: []string{"time"},
+		servers: map[string]catalog.Server{
+			"time": {Image: "mcp/time@sha256:9c46a918633fb474bf8035e3ee90ebac6bcf2b18ccb00679ac4c179cba0ebfcf", PullPolicy: "never"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"mcp/time@sha256:9c46a918633fb474bf8035e3ee90ebac6bcf2b18ccb00679ac4c179cba0ebfcf"}, verified)
+	require.Empty(t, docker.pulledImages)
+
+	// The digest pin is still required.
+	err = g.pullAndVerify(context.Background(), Configuration{
+		serverNames: []string{"time"},
+		servers: map[string]catalog.Server{
+			"time": {Image: "mcp/time:latest", PullPolicy: "never"},
+		},
+	})
+	require.ErrorContains(t, err, "must be referenced by digest")
+}
+
 func TestPullAndVerifyImageRejectsMutableMCPReference(t *testing.T) {
 	oldVerify := verifyDockerImageSignatures
 	defer func() {
@@ -160,6 +218,7 @@ func TestPullAndVerifyImageWarnsWhenVerificationDisabled(t *testing.T) {
 type recordingDockerClient struct {
 	pulledImages []string
 	pullImages   func(context.Context, ...string) error
+	pingErr      error
 }
 
 func (c *recordingDockerClient) ContainerExists(context.Context, string) (bool, container.InspectResponse, error) {
@@ -230,3 +289,7 @@ func (c *recordingDockerClient) ConnectNetwork(context.Context, string, string,
 func (c *recordingDockerClient) InspectVolume(context.Context, string) (volume.Volume, error) {
 	return volume.Volume{}, nil
 }
+
+func (c *recordingDockerClient) Ping(context.Context) error {
+	return c.pingErr
+}
@@ -0,0 +1,322 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/secret"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// RecordedCall is a tool call recorded with --record-calls, one per line of
+// the JSON Lines file. Secrets in the arguments are replaced with
+// {{secret:<name>}} placeholders.
+type RecordedCall struct {
+	Time      string          `json:"time"`
+	Server    string          `json:"server,omitempty"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+var secretPlaceholderPattern = regexp.MustCompile(`{{secret:([^}]+)}}`)
+
+// getStoredSecrets reads the secrets of the secrets engine, to resolve the
+// se:// references of the configuration.
+var getStoredSecrets = secret.GetSecrets
+
+type callRecorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (r *callRecorder) record(call RecordedCall) error {
+	buf, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.w.Write(append(buf, '\n'))
+	return err
+}
+
+// recordCallsMiddleware appends every tool call to the --record-calls file,
+// before it is executed, so that failing calls are recorded too.
+func (g *Gateway) recordCallsMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" || g.callRecorder == nil {
+				return next(ctx, method, req)
+			}
+
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || params == nil {
+				return next(ctx, method, req)
+			}
+
+			g.capabilitiesMu.RLock()
+			serverName := g.toolRegistrations[params.Name].ServerName
+			g.capabilitiesMu.RUnlock()
+
+			// Secrets are never written to the file: when they can't be
+			// redacted, the call isn't recorded.
+			if err := g.recordCall(ctx, serverName, params); err != nil {
+				log.Logf("! Unable to record call to %s: %v", params.Name, err)
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+func (g *Gateway) recordCall(ctx context.Context, serverName string, params *mcp.CallToolParamsRaw) error {
+	secrets, err := g.secretValues(ctx)
+	if err != nil {
+		return err
+	}
+	arguments, err := redactSecrets(params.Arguments, secrets)
+	if err != nil {
+		return err
+	}
+
+	return g.callRecorder.record(RecordedCall{
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Server:    serverName,
+		Tool:      params.Name,
+		Arguments: arguments,
+	})
+}
+
+// secretValues returns the values of the secrets of the configuration, by
+// name. The configuration holds se:// references to the secrets engine, which
+// are resolved, or the values themselves, e.g. read from --secrets files.
+func (g *Gateway) secretValues(ctx context.Context) (map[string]string, error) {
+	g.configurationMu.Lock()
+	secrets := maps.Clone(g.configuration.secrets)
+	g.configurationMu.Unlock()
+
+	var stored map[string]string
+	values := make(map[string]string, len(secrets))
+	for name, value := range secrets {
+		id, ok := strings.CutPrefix(value, "se://")
+		if !ok {
+			values[name] = value
+			continue
+		}
+		if stored == nil {
+			envelopes, err := getStoredSecrets(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read secrets: %w", err)
+			}
+			stored = make(map[string]string, len(envelopes))
+			for _, envelope := range envelopes {
+				stored[envelope.ID.String()] = string(envelope.Value)
+			}
+		}
+		if v, ok := stored[id]; ok {
+			values[name] = v
+		}
+	}
+	return values, nil
+}
+
+// redactSecrets replaces the values of the secrets found in the arguments of
+// a tool call with {{secret:<name>}} placeholders. Arguments that can't be
+// decoded are an error, rather than recorded as they are.
+func redactSecrets(arguments json.RawMessage, secrets map[string]string) (json.RawMessage, error) {
+	if len(arguments) == 0 {
+		return arguments, nil
+	}
+
+	// Replace longer values first, in case a secret contains another one.
+	names := make([]string, 0, len(secrets))
+	for name, value := range secrets {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(secrets[names[i]]) != len(secrets[names[j]]) {
+			return len(secrets[names[i]]) > len(secrets[names[j]])
+		}
+		return names[i] < names[j]
+	})
+
+	var decoded any
+	if err := json.Unmarshal(arguments, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	return json.Marshal(mapStrings(decoded, func(s string) string {
+		for _, name := range names {
+			s = strings.ReplaceAll(s, secrets[name], "{{secret:"+name+"}}")
+		}
+		return s
+	}))
+}
+
+// resolveSecrets replaces the {{secret:<name>}} placeholders in the arguments
+// of a recorded tool call with the values of the secrets.
+func resolveSecrets(arguments json.RawMessage, secrets map[string]string) (json.RawMessage, error) {
+	if len(arguments) == 0 {
+		return arguments, nil
+	}
+
+	var decoded any
+	if err := json.Unmarshal(arguments, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	var missing []string
+	resolved := mapStrings(decoded, func(s string) string {
+		return secretPlaceholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+			name := secretPlaceholderPattern.FindStringSubmatch(placeholder)[1]
+			value, ok := secrets[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("secrets not available: %s", strings.Join(missing, ", "))
+	}
+
+	return json.Marshal(resolved)
+}
+
+// mapStrings applies f to every string of a decoded JSON value.
+func mapStrings(v any, f func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return f(v)
+	case []any:
+		for i := range v {
+			v[i] = mapStrings(v[i], f)
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = mapStrings(v[key], f)
+		}
+	}
+	return v
+}
+
+// ReadRecordedCalls reads the tool calls recorded with --record-calls.
+func ReadRecordedCalls(r io.Reader) ([]RecordedCall, error) {
+	var calls []RecordedCall
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var call RecordedCall
+		if err := json.Unmarshal(scanner.Bytes(), &call); err != nil {
+			return nil, fmt.Errorf("invalid recorded call on line %d: %w", line, err)
+		}
+		calls = append(calls, call)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return calls, nil
+}
+
+// ReplayCall issues a recorded tool call again, with its secrets resolved,
+// through the given session.
+func ReplayCall(ctx context.Context, session *mcp.ClientSession, call RecordedCall, secrets map[string]string) (*mcp.CallToolResult, error) {
+	arguments, err := resolveSecrets(call.Arguments, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &mcp.CallToolParams{Name: call.Tool}
+	if len(arguments) > 0 {
+		params.Arguments = arguments
+	}
+	return session.CallTool(ctx, params)
+}
+
+// replayRecordedCalls replays the tool calls recorded in a file against the
+// current gateway, and writes their results to out.
+func (g *Gateway) replayRecordedCalls(ctx context.Context, path string, out io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open recorded calls: %w", err)
+	}
+	defer f.Close()
+
+	calls, err := ReadRecordedCalls(f)
+	if err != nil {
+		return err
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return err
+	}
+	defer serverSession.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "docker-mcp-replay", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	secrets, err := g.secretValues(ctx)
+	if err != nil {
+		return err
+	}
+
+	return replayCalls(ctx, session, calls, secrets, out)
+}
+
+// replayCalls replays recorded tool calls through the given session, and
+// writes their results to out. It returns the errors of the failing calls.
+func replayCalls(ctx context.Context, session *mcp.ClientSession, calls []RecordedCall, secrets map[string]string, out io.Writer) error {
+	var errs []error
+	for i, call := range calls {
+		fmt.Fprintf(out, "> Replaying call %d/%d to %s (recorded at %s)\n", i+1, len(calls), call.Tool, call.Time)
+
+		result, err := ReplayCall(ctx, session, call, secrets)
+		if err == nil && result.IsError {
+			err = errors.New(toolResultText(result))
+		}
+		if err != nil {
+			fmt.Fprintf(out, "  Error: %v\n", err)
+			errs = append(errs, fmt.Errorf("call %d to %s: %w", i+1, call.Tool, err))
+			continue
+		}
+		fmt.Fprintln(out, toolResultText(result))
+	}
+
+	return errors.Join(errs...)
+}
+
+func toolResultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
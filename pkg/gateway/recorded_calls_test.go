@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	seclient "github.com/docker/secrets-engine/client"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// echoServer starts an in-memory MCP server with a search tool that records
+// the arguments it's called with.
+func echoServer(t *testing.T) (*sessionClient, func() []string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	server := mcp.NewServer(&mcp.Implementation{Name: "search", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "search", InputSchema: &jsonschema.Schema{Type: "object"}}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		received = append(received, string(req.Params.Arguments))
+		mu.Unlock()
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "found 3 results"}}}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+// recordingGateway returns a gateway in front of the search server that
+// records tool calls to w.
+func recordingGateway(t *testing.T, client *sessionClient, secrets map[string]string, w *bytes.Buffer) *Gateway {
+	t.Helper()
+
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"search"},
+			servers: map[string]catalog.Server{
+				"search": {Name: "search", Image: "mcp/search"},
+			},
+			secrets: secrets,
+		},
+		clientPool:   &clientPool{keptClients: map[clientKey]keptClient{}},
+		callRecorder: &callRecorder{w: w},
+	}
+	getter := &clientGetter{client: client}
+	getter.once.Do(func() {}) // mark as created
+	g.clientPool.keptClients[clientKey{serverName: "search"}] = keptClient{Name: "search", Getter: getter}
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	g.mcpServer.AddReceivingMiddleware(g.recordCallsMiddleware())
+	g.serverAvailableCapabilities = make(map[string]*Capabilities)
+	require.NoError(t, g.reloadConfiguration(t.Context(), g.configuration, nil, nil))
+
+	return g
+}
+
+// connectToGateway connects a client to the gateway, through which calls to
+// the search server are made.
+func connectToGateway(t *testing.T, g *Gateway) *mcp.ClientSession {
+	t.Helper()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	// Tool calls look up the client kept for the session they come from.
+	getter := g.clientPool.keptClients[clientKey{serverName: "search"}].Getter
+	g.clientPool.keptClients[clientKey{serverName: "search", session: serverSession}] = keptClient{Name: "search", Getter: getter}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session
+}
+
+// storedSecrets replaces the secrets engine with the given secrets, by ID.
+func storedSecrets(t *testing.T, secrets map[string]string, err error) {
+	t.Helper()
+
+	oldGetStoredSecrets := getStoredSecrets
+	t.Cleanup(func() {
+		getStoredSecrets = oldGetStoredSecrets
+	})
+	getStoredSecrets = func(context.Context) ([]seclient.Envelope, error) {
+		var envelopes []seclient.Envelope
+		for id, value := range secrets {
+			envelopes = append(envelopes, seclient.Envelope{ID: seclient.MustParseID(id), Value: []byte(value)})
+		}
+		return envelopes, err
+	}
+}
+
+func TestRecordAndReplayCall(t *testing.T) {
+	client, received := echoServer(t)
+	// The configuration only has references to the secrets engine.
+	secrets := map[string]string{"search.api_key": "se://docker/mcp/generic/search.api_key"}
+	storedSecrets(t, map[string]string{"docker/mcp/generic/search.api_key": "sk-12345"}, nil)
+
+	var recorded bytes.Buffer
+	g := recordingGateway(t, client, secrets, &recorded)
+	session := connectToGateway(t, g)
+
+	_, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      "search",
+		Arguments: map[string]any{"query": "mcp", "headers": []any{"Authorization: Bearer sk-12345"}},
+	})
+	require.NoError(t, err)
+
+	// The secret isn't stored in the log.
+	assert.NotContains(t, recorded.String(), "sk-12345")
+
+	calls, err := ReadRecordedCalls(&recorded)
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "search", calls[0].Server)
+	assert.Equal(t, "search", calls[0].Tool)
+	assert.JSONEq(t, `{"query": "mcp", "headers": ["Authorization: Bearer {{secret:search.api_key}}"]}`, string(calls[0].Arguments))
+
+	// The secret is resolved at replay.
+	values, err := g.secretValues(t.Context())
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, replayCalls(t.Context(), connectToGateway(t, g), calls, values, &out))
+	assert.Contains(t, out.String(), "found 3 results")
+
+	require.Len(t, received(), 2)
+	assert.JSONEq(t, received()[0], received()[1])
+	assert.Contains(t, received()[1], "sk-12345")
+}
+
+func TestCallNotRecordedWhenSecretsCantBeRead(t *testing.T) {
+	client, received := echoServer(t)
+	secrets := map[string]string{"search.api_key": "se://docker/mcp/generic/search.api_key"}
+	storedSecrets(t, nil, errors.New("secrets engine unavailable"))
+
+	var recorded bytes.Buffer
+	g := recordingGateway(t, client, secrets, &recorded)
+	session := connectToGateway(t, g)
+
+	_, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      "search",
+		Arguments: map[string]any{"query": "sk-12345"},
+	})
+	require.NoError(t, err)
+
+	// The call is made, but not recorded.
+	assert.Len(t, received(), 1)
+	assert.Empty(t, recorded.String())
+}
+
+func TestReplayCallWithMissingSecret(t *testing.T) {
+	client, received := echoServer(t)
+	g := recordingGateway(t, client, nil, &bytes.Buffer{})
+
+	call := RecordedCall{
+		Tool:      "search",
+		Arguments: json.RawMessage(`{"token": "{{secret:search.api_key}}"}`),
+	}
+	_, err := ReplayCall(t.Context(), connectToGateway(t, g), call, map[string]string{})
+	require.ErrorContains(t, err, "secrets not available: search.api_key")
+
+	assert.Empty(t, received())
+}
+
+func TestRedactSecrets(t *testing.T) {
+	secrets := map[string]string{
+		"short": "abc",
+		"long":  "abcdef",
+		"empty": "",
+	}
+
+	redacted, err := redactSecrets(json.RawMessage(`{"a": "abcdef", "b": ["xabcx", 42], "c": {"d": "none"}}`), secrets)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": "{{secret:long}}", "b": ["x{{secret:short}}x", 42], "c": {"d": "none"}}`, string(redacted))
+
+	resolved, err := resolveSecrets(redacted, secrets)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": "abcdef", "b": ["xabcx", 42], "c": {"d": "none"}}`, string(resolved))
+
+	// Arguments that can't be decoded aren't returned as they are.
+	redacted, err = redactSecrets(json.RawMessage(`{"a": "abcdef"`), secrets)
+	require.ErrorContains(t, err, "invalid arguments")
+	assert.Nil(t, redacted)
+}
+
+func TestReadRecordedCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	lines := []string{
+		`{"time": "2026-01-02T03:04:05Z", "server": "search", "tool": "search", "arguments": {"query": "mcp"}}`,
+		``,
+		`{"time": "2026-01-02T03:04:06Z", "server": "search", "tool": "search"}`,
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	calls, err := ReadRecordedCalls(f)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "2026-01-02T03:04:06Z", calls[1].Time)
+
+	_, err = ReadRecordedCalls(strings.NewReader(fmt.Sprintf("%s\nnot json\n", lines[0])))
+	require.ErrorContains(t, err, "invalid recorded call on line 2")
+}
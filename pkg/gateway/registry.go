@@ -145,9 +145,9 @@ func registryImportHandler(g *Gateway, configuration Configuration) mcp.ToolHand
 			}
 
 			// List required secrets
-			if len(server.Secrets) > 0 {
+			if storedSecrets := server.StoredSecrets(); len(storedSecrets) > 0 {
 				var secretNames []string
-				for _, secret := range server.Secrets {
+				for _, secret := range storedSecrets {
 					secretNames = append(secretNames, secret.Name)
 				}
 				summary += fmt.Sprintf("\n  Required Secrets: %s", strings.Join(secretNames, ", "))
@@ -37,6 +37,7 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 
 	capabilities = g.filterToolCapabilitiesByPolicy(ctx, configuration, capabilities, "tool")
 	capabilities = g.filterPromptCapabilitiesByPolicy(ctx, configuration, capabilities)
+	capabilities = resolveDuplicateCapabilities(capabilities, capabilityNameIndexes{}, g.DuplicateCapabilities)
 
 	if err := validateExternalToolNameCollisions(capabilities.Tools, nil); err != nil {
 		return err
@@ -71,6 +72,8 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 	// Clear the tracking maps - we'll rebuild them
 	g.serverCapabilities = make(map[string]*ServerCapabilities)
 	g.toolRegistrations = make(map[string]ToolRegistration)
+	g.promptRegistrations = make(map[string]PromptRegistration)
+	g.resourceTemplateRegistrations = make(map[string]ResourceTemplateRegistration)
 
 	// Add new capabilities and track them per server
 	for _, tool := range capabilities.Tools {
@@ -168,8 +171,17 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		log.Log("  > mcp-discover: prompt for learning about dynamic server management")
 	}
 
+	if g.GatewayTools {
+		g.addGatewayTools()
+	}
+
+	if g.SafeMode {
+		g.disableMutatingDynamicTools()
+	}
+
 	for _, prompt := range capabilities.Prompts {
 		g.mcpServer.AddPrompt(prompt.Prompt, prompt.Handler)
+		g.trackPrompt(prompt)
 
 		// Track by server
 		if g.serverCapabilities[prompt.ServerName] == nil {
@@ -204,6 +216,7 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 			MIMEType:    template.ResourceTemplate.MIMEType,
 		}
 		g.mcpServer.AddResourceTemplate(resource, template.Handler)
+		g.trackResourceTemplate(template)
 
 		// Track by server
 		if g.serverCapabilities[template.ServerName] == nil {
@@ -411,6 +424,7 @@ func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName strin
 
 	allowedServerCaps := g.filterToolCapabilitiesByPolicy(ctx, g.configuration, newServerCaps, "dynamic tool")
 	allowedServerCaps = g.filterPromptCapabilitiesByPolicy(ctx, g.configuration, allowedServerCaps)
+	allowedServerCaps = resolveDuplicateCapabilities(allowedServerCaps, g.registeredCapabilityNameIndexes(serverName), g.DuplicateCapabilities)
 
 	existingToolRegistrations := make(map[string]ToolRegistration, len(g.toolRegistrations))
 	for toolName, registration := range g.toolRegistrations {
@@ -523,6 +537,9 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 
 	if len(removedPrompts) > 0 {
 		g.mcpServer.RemovePrompts(removedPrompts...)
+		for _, promptName := range removedPrompts {
+			delete(g.promptRegistrations, promptName)
+		}
 		log.Log("  - Removed", len(removedPrompts), "prompts for", serverName)
 	}
 
@@ -533,6 +550,9 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 
 	if len(removedTemplates) > 0 {
 		g.mcpServer.RemoveResourceTemplates(removedTemplates...)
+		for _, uriTemplate := range removedTemplates {
+			delete(g.resourceTemplateRegistrations, uriTemplate)
+		}
 		log.Log("  - Removed", len(removedTemplates), "resource templates for", serverName)
 	}
 
@@ -561,6 +581,7 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 	for _, prompt := range addedPrompts {
 		if registration, err := newServerCaps.getPromptByName(prompt); err == nil {
 			g.mcpServer.AddPrompt(registration.Prompt, registration.Handler)
+			g.trackPrompt(registration)
 		}
 	}
 	if len(addedPrompts) > 0 {
@@ -579,6 +600,7 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 	for _, template := range addedTemplates {
 		if registration, err := newServerCaps.getResourceTemplateByURITemplate(template); err == nil {
 			g.mcpServer.AddResourceTemplate(&registration.ResourceTemplate, registration.Handler)
+			g.trackResourceTemplate(registration)
 		}
 	}
 	if len(addedTemplates) > 0 {
@@ -625,6 +647,9 @@ func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string
 	if len(oldCaps.PromptNames) > 0 {
 		g.mcpServer.RemovePrompts(oldCaps.PromptNames...)
 		log.Log("  - Removed", len(oldCaps.PromptNames), "prompts for", serverName)
+		for _, promptName := range oldCaps.PromptNames {
+			delete(g.promptRegistrations, promptName)
+		}
 	}
 
 	if len(oldCaps.ResourceURIs) > 0 {
@@ -635,6 +660,9 @@ func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string
 	if len(oldCaps.ResourceTemplateURIs) > 0 {
 		g.mcpServer.RemoveResourceTemplates(oldCaps.ResourceTemplateURIs...)
 		log.Log("  - Removed", len(oldCaps.ResourceTemplateURIs), "resource templates for", serverName)
+		for _, uriTemplate := range oldCaps.ResourceTemplateURIs {
+			delete(g.resourceTemplateRegistrations, uriTemplate)
+		}
 	}
 
 	// Update tracking with new capabilities
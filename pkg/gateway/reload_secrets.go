@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// secretsReader is implemented by configurators that can re-read secret values
+// without reloading the whole configuration.
+type secretsReader interface {
+	ReadSecrets(ctx context.Context, configuration Configuration) (map[string]string, error)
+}
+
+// ReloadSecrets re-reads the secrets from the secrets provider and applies them
+// to the current configuration. Servers get their secrets when they are started,
+// so running servers whose secrets changed are stopped and will be started again,
+// with the new values, on their next use. The names of those servers are returned.
+func (g *Gateway) ReloadSecrets(ctx context.Context) ([]string, error) {
+	reader, ok := g.configurator.(secretsReader)
+	if !ok {
+		return nil, errors.New("reloading secrets is not supported by this configuration")
+	}
+
+	g.configurationMu.Lock()
+	configuration := g.configuration
+	g.configurationMu.Unlock()
+
+	secrets, err := reader.ReadSecrets(ctx, configuration)
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
+
+	g.configurationMu.Lock()
+	previous := g.configuration.secrets
+	merged := make(map[string]string, len(previous)+len(secrets))
+	maps.Copy(merged, previous)
+	maps.Copy(merged, secrets)
+	g.configuration.secrets = merged
+	servers := g.configuration.servers
+	serverNames := g.configuration.serverNames
+	g.configurationMu.Unlock()
+
+	var restarted []string
+	for _, serverName := range serverNames {
+		server := servers[serverName]
+		if !secretsChanged(server.StoredSecrets(), previous, merged) {
+			continue
+		}
+		if g.clientPool.InvalidateServerClients(serverName) > 0 {
+			restarted = append(restarted, serverName)
+		}
+	}
+	slices.Sort(restarted)
+
+	log.Logf("- Secrets reloaded, %d server(s) restarted", len(restarted))
+	return restarted, nil
+}
+
+// secretsChanged reports whether any of the declared secrets has a new value.
+// se:// references are resolved by the secrets engine when the server starts,
+// so a server using them always has to be restarted to pick up rotated values.
+func secretsChanged(declared []catalog.Secret, previous, current map[string]string) bool {
+	for _, s := range declared {
+		value, ok := current[s.Name]
+		if !ok {
+			continue
+		}
+		if previous[s.Name] != value || strings.HasPrefix(value, "se://") {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Gateway) reloadSecretsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		restarted, err := g.ReloadSecrets(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if restarted == nil {
+			restarted = []string{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string][]string{"restarted": restarted})
+	}
+}
@@ -0,0 +1,152 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+)
+
+type fakeSecretsConfigurator struct {
+	secrets map[string]string
+}
+
+func (c *fakeSecretsConfigurator) Read(context.Context) (Configuration, chan Configuration, func() error, error) {
+	return Configuration{}, nil, nil, errors.New("not implemented")
+}
+
+func (c *fakeSecretsConfigurator) ReadSecrets(context.Context, Configuration) (map[string]string, error) {
+	return maps.Clone(c.secrets), nil
+}
+
+type staticConfigurator struct{}
+
+func (c *staticConfigurator) Read(context.Context) (Configuration, chan Configuration, func() error, error) {
+	return Configuration{}, nil, func() error { return nil }, nil
+}
+
+func keepFakeClient(cp *clientPool, serverConfig *catalog.ServerConfig) {
+	getter := &clientGetter{}
+	getter.once.Do(func() {})
+	getter.err = errors.New("mock: no real client")
+
+	cp.keptClients[clientKey{serverName: serverConfig.Name}] = keptClient{
+		Name:   serverConfig.Name,
+		Getter: getter,
+		Config: serverConfig,
+	}
+}
+
+func newReloadSecretsGateway(t *testing.T, secrets map[string]string) (*Gateway, *fakeSecretsConfigurator) {
+	t.Helper()
+
+	configurator := &fakeSecretsConfigurator{secrets: maps.Clone(secrets)}
+	g := &Gateway{
+		configurator: configurator,
+		configuration: Configuration{
+			serverNames: []string{"fetch", "github"},
+			servers: map[string]catalog.Server{
+				"github": {
+					Image:   "mcp/github",
+					Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}},
+				},
+				"fetch": {
+					Image:   "mcp/fetch",
+					Secrets: []catalog.Secret{{Name: "fetch.key", Env: "FETCH_KEY"}},
+				},
+			},
+			secrets: secrets,
+		},
+		clientPool: &clientPool{
+			Options:     Options{Cpus: 1, Memory: "2Gb"},
+			keptClients: make(map[clientKey]keptClient),
+		},
+	}
+
+	for _, serverName := range g.configuration.serverNames {
+		serverConfig, _, found := g.configuration.Find(serverName)
+		require.True(t, found)
+		keepFakeClient(g.clientPool, serverConfig)
+	}
+
+	return g, configurator
+}
+
+func TestReloadSecretsRestartsServerWithRotatedSecret(t *testing.T) {
+	g, configurator := newReloadSecretsGateway(t, map[string]string{
+		"github.token": "old-token",
+		"fetch.key":    "fetch-key",
+	})
+
+	configurator.secrets["github.token"] = "new-token"
+
+	restarted, err := g.ReloadSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github"}, restarted)
+
+	assert.NotContains(t, g.clientPool.keptClients, clientKey{serverName: "github"})
+	assert.Contains(t, g.clientPool.keptClients, clientKey{serverName: "fetch"})
+
+	// The server is started again with the new value.
+	serverConfig, _, found := g.configuration.Find("github")
+	require.True(t, found)
+	_, env, err := g.clientPool.argsAndEnv(t.Context(), serverConfig, proxies.TargetConfig{})
+	require.NoError(t, err)
+	assert.Contains(t, env, "GITHUB_TOKEN=new-token")
+	assert.NotContains(t, env, "GITHUB_TOKEN=old-token")
+}
+
+func TestReloadSecretsRestartsServersUsingSecretReferences(t *testing.T) {
+	g, _ := newReloadSecretsGateway(t, map[string]string{
+		"github.token": "se://docker/mcp/github.token",
+		"fetch.key":    "fetch-key",
+	})
+
+	restarted, err := g.ReloadSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github"}, restarted)
+}
+
+func TestReloadSecretsUnchanged(t *testing.T) {
+	g, _ := newReloadSecretsGateway(t, map[string]string{
+		"github.token": "token",
+		"fetch.key":    "fetch-key",
+	})
+
+	restarted, err := g.ReloadSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, restarted)
+	assert.Len(t, g.clientPool.keptClients, 2)
+}
+
+func TestReloadSecretsUnsupportedConfigurator(t *testing.T) {
+	g := &Gateway{configurator: &staticConfigurator{}}
+
+	_, err := g.ReloadSecrets(t.Context())
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestReloadSecretsHandler(t *testing.T) {
+	g, configurator := newReloadSecretsGateway(t, map[string]string{
+		"github.token": "token",
+		"fetch.key":    "old-key",
+	})
+	configurator.secrets["fetch.key"] = "new-key"
+
+	rec := httptest.NewRecorder()
+	g.reloadSecretsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reload-secrets", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string][]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []string{"fetch"}, body["restarted"])
+}
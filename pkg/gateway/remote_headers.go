@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+)
+
+var configTemplate = regexp.MustCompile(`{{.*?}}`)
+
+// parseRemoteHeaders parses headers of the form <server>:<name>=<value>,
+// indexed by server then header name.
+func parseRemoteHeaders(values []string) (map[string]map[string]string, error) {
+	headers := make(map[string]map[string]string)
+	for _, value := range values {
+		server, header, _ := strings.Cut(value, ":")
+		name, headerValue, ok := strings.Cut(header, "=")
+		server = strings.TrimSpace(server)
+		name = strings.TrimSpace(name)
+		if !ok || server == "" || name == "" || strings.ContainsAny(name, " \t:") {
+			return nil, fmt.Errorf("invalid remote header %q: expected <server>:<name>=<value>", value)
+		}
+		if headers[server] == nil {
+			headers[server] = make(map[string]string)
+		}
+		headers[server][name] = headerValue
+	}
+	return headers, nil
+}
+
+// withRemoteHeaders returns the configuration of a remote server with the
+// headers configured for it with --remote-header. The headers declared by the
+// server take precedence. {{...}} templates are evaluated against the config
+// of the server, while $VAR references to the env of its secrets are left to
+// the remote client, as for the server's own headers.
+func (cp *clientPool) withRemoteHeaders(serverConfig *catalog.ServerConfig) *catalog.ServerConfig {
+	allHeaders, err := parseRemoteHeaders(cp.RemoteHeaders)
+	if err != nil {
+		// Already validated when the gateway starts.
+		return serverConfig
+	}
+	configured := allHeaders[serverConfig.Name]
+	if len(configured) == 0 {
+		return serverConfig
+	}
+
+	headers := maps.Clone(serverConfig.Spec.Remote.Headers)
+	if headers == nil {
+		headers = make(map[string]string, len(configured))
+	}
+	for name, value := range configured {
+		if hasHeader(serverConfig.Spec.Remote.Headers, name) {
+			continue
+		}
+		headers[name] = configTemplate.ReplaceAllStringFunc(value, func(term string) string {
+			return fmt.Sprintf("%v", eval.Evaluate(term, serverConfig.Config))
+		})
+	}
+
+	withHeaders := *serverConfig
+	withHeaders.Spec.Remote.Headers = headers
+	return &withHeaders
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// headersRemoteServer starts a remote MCP server that records the headers of
+// the requests it receives.
+func headersRemoteServer(t *testing.T) (string, func() http.Header) {
+	t.Helper()
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "remote", Version: "1.0.0"}, nil)
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	var (
+		mu      sync.Mutex
+		headers http.Header
+	)
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = r.Header.Clone()
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(remote.Close)
+
+	return remote.URL, func() http.Header {
+		mu.Lock()
+		defer mu.Unlock()
+		return headers
+	}
+}
+
+func TestRemoteHeadersAreInjected(t *testing.T) {
+	url, headers := headersRemoteServer(t)
+	serverConfig := &catalog.ServerConfig{
+		Name: "remote",
+		Spec: catalog.Server{
+			Name:    "remote",
+			Type:    "remote",
+			Remote:  catalog.Remote{URL: url, Transport: "streamable-http", Headers: map[string]string{"X-Api-Version": "2"}},
+			Secrets: []catalog.Secret{{Name: "remote.token", Env: "REMOTE_TOKEN"}},
+		},
+		Config:  map[string]any{"remote": map[string]any{"tenant": "acme"}},
+		Secrets: map[string]string{"remote.token": "s3cret"},
+	}
+	cp := newClientPool(Options{RemoteHeaders: []string{
+		"remote:X-Tenant-ID={{remote.tenant}}",
+		"remote:X-Trace-Source=gateway",
+		"remote:X-Token=Bearer ${REMOTE_TOKEN}",
+		"remote:x-api-version=1",
+		"other:X-Other=other",
+	}}, nil, nil)
+
+	client, err := newClientGetter(serverConfig, cp, nil).GetClient(desktop.WithNoDockerDesktop(t.Context()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Session().Close() })
+
+	_, err = client.Session().ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+
+	received := headers()
+	assert.Equal(t, "acme", received.Get("X-Tenant-ID"))
+	assert.Equal(t, "gateway", received.Get("X-Trace-Source"))
+	assert.Equal(t, "Bearer s3cret", received.Get("X-Token"))
+	// The server's own headers take precedence.
+	assert.Equal(t, "2", received.Get("X-Api-Version"))
+	assert.Empty(t, received.Get("X-Other"))
+
+	// The server's configuration is left untouched.
+	assert.Equal(t, map[string]string{"X-Api-Version": "2"}, serverConfig.Spec.Remote.Headers)
+}
+
+func TestParseRemoteHeaders(t *testing.T) {
+	headers, err := parseRemoteHeaders([]string{"notion:X-Tenant-ID=acme", "notion:X-Empty=", "linear:X-Trace=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"notion": {"X-Tenant-ID": "acme", "X-Empty": ""},
+		"linear": {"X-Trace": "a=b"},
+	}, headers)
+
+	for _, invalid := range []string{"X-Tenant-ID=acme", "notion:X-Tenant-ID", ":X-Tenant-ID=acme", "notion:=acme", "notion:X Tenant=acme"} {
+		_, err := parseRemoteHeaders([]string{invalid})
+		require.ErrorContains(t, err, "expected <server>:<name>=<value>", invalid)
+	}
+}
@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// retryRemoteConnect calls connect until it succeeds, retrying network
+// failures up to RemoteRetries times with an exponential backoff starting at
+// RemoteRetryBackoff. Authentication failures are returned right away.
+func (cp *clientPool) retryRemoteConnect(ctx context.Context, serverName string, connect func() error) error {
+	backoff := cp.RemoteRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := connect()
+		if err == nil {
+			return nil
+		}
+
+		var connectErr *mcpclient.RemoteConnectError
+		if attempt >= cp.RemoteRetries || !errors.As(err, &connectErr) || !connectErr.Retryable() {
+			return err
+		}
+
+		log.Logf("  - Connecting to %s failed, retrying in %s (%d/%d): %v", serverName, backoff, attempt+1, cp.RemoteRetries, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
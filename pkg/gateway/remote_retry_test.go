@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// flakyRemoteServer serves MCP over streamable HTTP but answers the first
+// failures connection attempts with failure instead. It returns the number of
+// connection attempts.
+func flakyRemoteServer(t *testing.T, failures int, failure func(http.ResponseWriter)) (*catalog.ServerConfig, *atomic.Int32) {
+	t.Helper()
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "remote", Version: "1.0.0"}, nil)
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	// Connection attempts start with an initialize request, sent without a session.
+	var attempts atomic.Int32
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Header.Get("Mcp-Session-Id") == "" && attempts.Add(1) <= int32(failures) {
+			failure(w)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(remote.Close)
+
+	return &catalog.ServerConfig{
+		Name: "remote",
+		Spec: catalog.Server{
+			Name:   "remote",
+			Type:   "remote",
+			Remote: catalog.Remote{URL: remote.URL, Transport: "streamable-http"},
+		},
+	}, &attempts
+}
+
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		_ = conn.Close()
+	}
+}
+
+func TestRemoteConnectRetriesNetworkFailures(t *testing.T) {
+	serverConfig, attempts := flakyRemoteServer(t, 2, dropConnection)
+	cp := newClientPool(Options{RemoteRetries: 2, RemoteRetryBackoff: time.Millisecond}, nil, nil)
+
+	client, err := newClientGetter(serverConfig, cp, nil).GetClient(desktop.WithNoDockerDesktop(t.Context()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Session().Close() })
+
+	_, err = client.Session().ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRemoteConnectGivesUpAfterRetries(t *testing.T) {
+	serverConfig, attempts := flakyRemoteServer(t, 100, dropConnection)
+	cp := newClientPool(Options{RemoteRetries: 2, RemoteRetryBackoff: time.Millisecond}, nil, nil)
+
+	_, err := newClientGetter(serverConfig, cp, nil).GetClient(desktop.WithNoDockerDesktop(t.Context()))
+	require.ErrorContains(t, err, "failed to connect")
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRemoteConnectDoesNotRetryAuthFailures(t *testing.T) {
+	serverConfig, attempts := flakyRemoteServer(t, 100, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	cp := newClientPool(Options{RemoteRetries: 2, RemoteRetryBackoff: time.Millisecond}, nil, nil)
+
+	_, err := newClientGetter(serverConfig, cp, nil).GetClient(desktop.WithNoDockerDesktop(t.Context()))
+	var connectErr *mcpclient.RemoteConnectError
+	require.ErrorAs(t, err, &connectErr)
+	assert.True(t, connectErr.Unauthorized)
+	assert.Equal(t, int32(1), attempts.Load())
+}
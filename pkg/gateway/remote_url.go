@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// resolveRemoteURLs resolves the placeholders in the URLs of the enabled
+// remote servers, Remote.URL or the deprecated SSEEndpoint: {{...}}
+// templates against the config of the server and ${VAR} references against
+// the env of its secrets, as for its headers. The ${VAR} references are only
+// checked here, and expanded when connecting, so that the values of secrets
+// don't end up in logs or traces. A placeholder that resolves to nothing is
+// an error, rather than a request sent to the wrong URL.
+func (c *Configuration) resolveRemoteURLs(ctx context.Context) error {
+	for _, serverName := range c.serverNames {
+		server, ok := c.servers[serverName]
+		if !ok || !strings.ContainsAny(server.SSEEndpoint+server.Remote.URL, "{$") {
+			continue
+		}
+		serverConfig, _, found := c.Find(serverName)
+		if !found || serverConfig == nil {
+			continue
+		}
+
+		for _, url := range []*string{&server.SSEEndpoint, &server.Remote.URL} {
+			resolved, err := resolveRemoteURL(ctx, serverConfig, *url)
+			if err != nil {
+				return fmt.Errorf("unresolved placeholders in the URL of remote server %s: %w", serverName, err)
+			}
+			*url = resolved
+		}
+		c.servers[serverName] = server
+	}
+	return nil
+}
+
+// resolveRemoteURL resolves the {{...}} templates of a remote URL and checks
+// that its ${VAR} references, left as is, resolve.
+func resolveRemoteURL(ctx context.Context, serverConfig *catalog.ServerConfig, url string) (string, error) {
+	var unresolved []string
+	if strings.Contains(url, "$") {
+		env := mcpclient.RemoteSecretEnv(ctx, serverConfig, false)
+		os.Expand(url, func(name string) string {
+			if env[name] == "" {
+				unresolved = append(unresolved, "${"+name+"}")
+			}
+			return ""
+		})
+	}
+	url = configTemplate.ReplaceAllStringFunc(url, func(term string) string {
+		value := fmt.Sprintf("%v", eval.Evaluate(term, serverConfig.Config))
+		if value == "" {
+			unresolved = append(unresolved, term)
+		}
+		return value
+	})
+	if len(unresolved) > 0 {
+		return "", errors.New(strings.Join(unresolved, ", "))
+	}
+	return url, nil
+}
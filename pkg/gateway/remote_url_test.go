@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestResolveRemoteURLs(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"notion"},
+		servers: map[string]catalog.Server{
+			"notion": {
+				Type:    "remote",
+				Remote:  catalog.Remote{URL: "https://{{notion.region}}.mcp.example.com/${TENANT}/mcp"},
+				Secrets: []catalog.Secret{{Name: "notion.tenant", Env: "TENANT"}},
+			},
+			"disabled": {
+				Type:   "remote",
+				Remote: catalog.Remote{URL: "https://{{disabled.region}}.example.com/mcp"},
+			},
+		},
+		config:  map[string]map[string]any{"notion": {"region": "eu"}},
+		secrets: map[string]string{"notion.tenant": "acme"},
+	}
+
+	require.NoError(t, configuration.resolveRemoteURLs(t.Context()))
+	// The references to secrets are only expanded when connecting, so that
+	// they don't end up in logs.
+	assert.Equal(t, "https://eu.mcp.example.com/${TENANT}/mcp", configuration.servers["notion"].Remote.URL)
+	// Only enabled servers are resolved.
+	assert.Equal(t, "https://{{disabled.region}}.example.com/mcp", configuration.servers["disabled"].Remote.URL)
+}
+
+func TestResolveRemoteURLsOfSSEEndpoint(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"legacy"},
+		servers: map[string]catalog.Server{
+			"legacy": {
+				Type:        "remote",
+				SSEEndpoint: "https://{{legacy.host}}/sse",
+			},
+		},
+		config: map[string]map[string]any{"legacy": {"host": "mcp.example.com"}},
+	}
+
+	require.NoError(t, configuration.resolveRemoteURLs(t.Context()))
+	assert.Equal(t, "https://mcp.example.com/sse", configuration.servers["legacy"].SSEEndpoint)
+
+	configuration.servers["legacy"] = catalog.Server{Type: "remote", SSEEndpoint: "https://{{legacy.region}}.example.com/sse"}
+	err := configuration.resolveRemoteURLs(t.Context())
+	require.EqualError(t, err, "unresolved placeholders in the URL of remote server legacy: {{legacy.region}}")
+}
+
+func TestResolveRemoteURLsWithUnresolvedPlaceholder(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"notion"},
+		servers: map[string]catalog.Server{
+			"notion": {
+				Type:   "remote",
+				Remote: catalog.Remote{URL: "https://{{notion.region}}.mcp.example.com/mcp"},
+			},
+		},
+		config: map[string]map[string]any{"notion": {}},
+	}
+
+	err := configuration.resolveRemoteURLs(t.Context())
+	require.EqualError(t, err, "unresolved placeholders in the URL of remote server notion: {{notion.region}}")
+	assert.Equal(t, "https://{{notion.region}}.mcp.example.com/mcp", configuration.servers["notion"].Remote.URL)
+}
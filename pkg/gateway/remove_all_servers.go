@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// RemoveAllConfirmationError is returned by RemoveAllServers when the
+// confirmation token is missing or doesn't match the enabled servers.
+type RemoveAllConfirmationError struct {
+	// Token is the confirmation token for removing Servers.
+	Token   string
+	Servers []string
+}
+
+func (e *RemoveAllConfirmationError) Error() string {
+	return fmt.Sprintf("removing all servers (%s) requires the confirmation token %s", strings.Join(e.Servers, ", "), e.Token)
+}
+
+// removeAllServersToken returns the confirmation token for removing the
+// servers. It depends on the servers, so that a token is only valid for the
+// servers it was given for.
+func removeAllServersToken(serverNames []string) string {
+	sorted := slices.Sorted(slices.Values(serverNames))
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// RemoveAllServers disables all the servers of the gateway and stops the
+// running ones, e.g. to reset its configuration. To avoid accidental wipes,
+// token must be the confirmation token of the enabled servers, which is
+// returned in a *RemoveAllConfirmationError otherwise. When the gateway runs a
+// profile, the servers are also removed from the profile, so that they don't
+// come back on the next reload. Clients are notified that the lists of tools,
+// prompts and resources changed. The names of the removed servers are
+// returned.
+func (g *Gateway) RemoveAllServers(ctx context.Context, token string) ([]string, error) {
+	// The servers are checked against the token and removed under the same
+	// lock, so that a server enabled in between isn't removed unconfirmed.
+	g.configurationMu.Lock()
+	serverNames := slices.Sorted(slices.Values(g.configuration.serverNames))
+	if expected := removeAllServersToken(serverNames); token != expected {
+		g.configurationMu.Unlock()
+		return nil, &RemoveAllConfirmationError{Token: expected, Servers: serverNames}
+	}
+	if err := removeProfileServers(ctx, g.configuration.workingSet, serverNames); err != nil {
+		g.configurationMu.Unlock()
+		return nil, err
+	}
+	g.configuration.serverNames = nil
+	g.configurationMu.Unlock()
+
+	for _, serverName := range serverNames {
+		if g.McpOAuthDcrEnabled {
+			g.stopProvider(serverName)
+		}
+		if err := g.removeServerConfiguration(ctx, serverName); err != nil {
+			log.Logf("  - Can't remove capabilities of %s: %s", serverName, err)
+		}
+		g.clientPool.InvalidateServerClients(serverName)
+	}
+
+	log.Logf("- All servers removed: %s", strings.Join(serverNames, ", "))
+	return serverNames, nil
+}
+
+// removeProfileServers removes the servers from the profile, if any. Servers
+// added to the profile in the meantime are kept.
+func removeProfileServers(ctx context.Context, profile string, serverNames []string) error {
+	if profile == "" || len(serverNames) == 0 {
+		return nil
+	}
+
+	dao, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to create database client: %w", err)
+	}
+	defer dao.Close()
+
+	err = dao.UpdateWorkingSetServers(ctx, profile, func(servers db.ServerList) db.ServerList {
+		return slices.DeleteFunc(servers, func(server db.Server) bool {
+			return server.Snapshot != nil && slices.Contains(serverNames, server.Snapshot.Server.Name)
+		})
+	})
+	// The default profile is used even when it doesn't exist.
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to remove the servers from profile %s: %w", profile, err)
+	}
+	return nil
+}
+
+func (g *Gateway) removeAllServersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Confirm string `json:"confirm"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		removed, err := g.RemoveAllServers(r.Context(), params.Confirm)
+		w.Header().Set("Content-Type", "application/json")
+
+		var confirmationErr *RemoveAllConfirmationError
+		if errors.As(err, &confirmationErr) {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error":   "confirmation required: send the request again with this confirm token",
+				"confirm": confirmationErr.Token,
+				"servers": confirmationErr.Servers,
+			})
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if removed == nil {
+			removed = []string{}
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"removed": removed})
+	}
+}
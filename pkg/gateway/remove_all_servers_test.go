@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// removeAllGateway returns a gateway with the search server enabled, and a
+// channel notified when the tools of a connected client change.
+func removeAllGateway(t *testing.T) (*Gateway, *mcp.ClientSession, chan struct{}) {
+	t.Helper()
+
+	client, _ := echoServer(t)
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"search"},
+			servers: map[string]catalog.Server{
+				"search": {Name: "search", Image: "mcp/search"},
+			},
+		},
+		clientPool: &clientPool{keptClients: map[clientKey]keptClient{}},
+	}
+	getter := &clientGetter{client: client}
+	getter.once.Do(func() {}) // mark as created
+	g.clientPool.keptClients[clientKey{serverName: "search"}] = keptClient{Name: "search", Getter: getter, Config: &catalog.ServerConfig{Name: "search"}}
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	g.serverAvailableCapabilities = make(map[string]*Capabilities)
+	require.NoError(t, g.reloadConfiguration(t.Context(), g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	toolsChanged := make(chan struct{}, 1)
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			select {
+			case toolsChanged <- struct{}{}:
+			default:
+			}
+		},
+	})
+	session, err := mcpClient.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return g, session, toolsChanged
+}
+
+func removeAll(t *testing.T, g *Gateway, body string) (int, map[string]any) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	g.removeAllServersHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/servers/remove-all", strings.NewReader(body)))
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return recorder.Code, response
+}
+
+func TestRemoveAllServersRequiresConfirmation(t *testing.T) {
+	g, session, _ := removeAllGateway(t)
+
+	code, response := removeAll(t, g, "")
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, []any{"search"}, response["servers"])
+	assert.Equal(t, removeAllServersToken([]string{"search"}), response["confirm"])
+
+	code, _ = removeAll(t, g, `{"confirm": "wrong"}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Nothing is removed.
+	assert.Equal(t, []string{"search"}, g.configuration.ServerNames())
+	tools, err := session.ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	assert.Len(t, tools.Tools, 1)
+}
+
+func TestRemoveAllServers(t *testing.T) {
+	g, session, toolsChanged := removeAllGateway(t)
+
+	_, response := removeAll(t, g, "")
+	token := response["confirm"].(string)
+
+	code, response := removeAll(t, g, `{"confirm": "`+token+`"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []any{"search"}, response["removed"])
+
+	assert.Empty(t, g.configuration.ServerNames())
+	assert.Empty(t, g.clientPool.keptClients)
+
+	select {
+	case <-toolsChanged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tools list changed notification not received")
+	}
+	tools, err := session.ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	assert.Empty(t, tools.Tools)
+
+	// The token was for the servers that were removed.
+	code, _ = removeAll(t, g, `{"confirm": "`+token+`"}`)
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+func TestRemoveAllServersToken(t *testing.T) {
+	assert.Equal(t, removeAllServersToken([]string{"a", "b"}), removeAllServersToken([]string{"b", "a"}))
+	assert.NotEqual(t, removeAllServersToken([]string{"a", "b"}), removeAllServersToken([]string{"a"}))
+}
+
+func TestRemoveAllServersRemovesThemFromProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dao, err := db.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+	server := func(name string) db.Server {
+		return db.Server{
+			Type:     string(workingset.ServerTypeImage),
+			Image:    "mcp/" + name,
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: name, Type: "server", Image: "mcp/" + name}},
+		}
+	}
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:      "dev",
+		Name:    "dev",
+		Servers: db.ServerList{server("search"), server("added-since")},
+		Secrets: db.SecretMap{},
+	}))
+
+	g, _, _ := removeAllGateway(t)
+	g.configuration.workingSet = "dev"
+
+	removed, err := g.RemoveAllServers(t.Context(), removeAllServersToken([]string{"search"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search"}, removed)
+
+	// Only the removed servers are removed from the profile.
+	workingSet, err := dao.GetWorkingSet(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Len(t, workingSet.Servers, 1)
+	assert.Equal(t, "added-since", workingSet.Servers[0].Snapshot.Server.Name)
+}
+
+func TestRemoveAllServersEnabledSinceConfirmation(t *testing.T) {
+	g, _, _ := removeAllGateway(t)
+	token := removeAllServersToken([]string{"search"})
+
+	g.configurationMu.Lock()
+	g.configuration.serverNames = append(g.configuration.serverNames, "fetch")
+	g.configurationMu.Unlock()
+
+	_, err := g.RemoveAllServers(t.Context(), token)
+	var confirmationErr *RemoveAllConfirmationError
+	require.ErrorAs(t, err, &confirmationErr)
+	assert.Equal(t, []string{"fetch", "search"}, confirmationErr.Servers)
+	assert.Equal(t, []string{"search", "fetch"}, g.configuration.ServerNames())
+}
@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+const (
+	// maxRestartBackoff caps the delay before a server that keeps failing to
+	// start is started again.
+	maxRestartBackoff = time.Minute
+	// crashLoopWindow is the time after which a failure to start is no longer
+	// counted as rapid, so that a server failing once in a while is never
+	// considered crash looping.
+	crashLoopWindow = 5 * time.Minute
+)
+
+// restartState tracks the consecutive failures to start a server.
+type restartState struct {
+	failures    int
+	lastFailure time.Time
+	retryAt     time.Time
+	crashLoop   bool
+}
+
+// restartBackoff delays starting a server that just failed to start, with a
+// backoff starting at RestartBackoff and doubled on every rapid failure, so
+// that a server crashing on start isn't restarted in a tight loop. After
+// MaxRestartFailures rapid failures, the server is crash looping and isn't
+// started again until it's reset. A nil restartBackoff never delays anything.
+type restartBackoff struct {
+	backoff     time.Duration
+	maxFailures int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	servers map[string]*restartState
+}
+
+func newRestartBackoff(backoff time.Duration, maxFailures int) *restartBackoff {
+	if backoff <= 0 && maxFailures <= 0 {
+		return nil
+	}
+	return &restartBackoff{
+		backoff:     backoff,
+		maxFailures: maxFailures,
+		now:         time.Now,
+		servers:     make(map[string]*restartState),
+	}
+}
+
+// allow returns an error if a server can't be started yet, because it's
+// crash looping or its backoff isn't over.
+func (rb *restartBackoff) allow(serverName string) error {
+	if rb == nil {
+		return nil
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	state, ok := rb.servers[serverName]
+	switch {
+	case !ok:
+		return nil
+	case state.crashLoop:
+		return crashLoopError(serverName, state.failures)
+	case rb.now().Before(state.retryAt):
+		return fmt.Errorf("server %s failed to start, restarting it in %s at the earliest", serverName, state.retryAt.Sub(rb.now()).Round(time.Millisecond))
+	}
+	return nil
+}
+
+// failed records a failure to start a server. It returns the number of rapid
+// failures and whether the server is now crash looping.
+func (rb *restartBackoff) failed(serverName string) (int, bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	now := rb.now()
+	state, ok := rb.servers[serverName]
+	if !ok || now.Sub(state.lastFailure) > crashLoopWindow {
+		state = &restartState{}
+		rb.servers[serverName] = state
+	}
+	state.failures++
+	state.lastFailure = now
+
+	if rb.maxFailures > 0 && state.failures >= rb.maxFailures {
+		state.crashLoop = true
+		return state.failures, true
+	}
+
+	delay := rb.backoff
+	for i := 1; i < state.failures && delay < maxRestartBackoff; i++ {
+		delay *= 2
+	}
+	state.retryAt = now.Add(min(delay, maxRestartBackoff))
+	return state.failures, false
+}
+
+// reset forgets the failures of a server, e.g. once it started fine or its
+// configuration changed.
+func (rb *restartBackoff) reset(serverName string) {
+	if rb == nil {
+		return
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	delete(rb.servers, serverName)
+}
+
+// crashLooping returns the crash looping servers, with the reason why.
+func (rb *restartBackoff) crashLooping() map[string]string {
+	crashLooping := map[string]string{}
+	if rb == nil {
+		return crashLooping
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	for serverName, state := range rb.servers {
+		if state.crashLoop {
+			crashLooping[serverName] = crashLoopError(serverName, state.failures).Error()
+		}
+	}
+	return crashLooping
+}
+
+func crashLoopError(serverName string, failures int) error {
+	return fmt.Errorf("server %s is crash looping: it failed to start %d times in a row", serverName, failures)
+}
+
+// serverStartFailed records that a server failed to start, unless the caller
+// gave up on it, and reports the servers that start crash looping. Only the
+// servers the gateway starts itself, in a container or as a local command,
+// can crash loop: failing to connect to a remote server, e.g. because of the
+// network or authentication, isn't counted.
+func (cp *clientPool) serverStartFailed(ctx context.Context, serverConfig *catalog.ServerConfig, err error) {
+	if cp.restarts == nil || ctx.Err() != nil || serverConfig.IsRemote() {
+		return
+	}
+
+	serverName := serverConfig.Name
+	failures, crashLoop := cp.restarts.failed(serverName)
+	if crashLoop {
+		log.Logf("  - %s failed to start %d times in a row, it won't be restarted: %v", serverName, failures, err)
+		telemetry.RecordServerCrashLoop(ctx, serverName, failures)
+	}
+}
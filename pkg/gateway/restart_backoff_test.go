@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// crashingGateway returns a gateway with a local command server that exits
+// as soon as it starts, and a function that counts how many times it started.
+func crashingGateway(t *testing.T, options Options) (*Gateway, *catalog.ServerConfig, func() int) {
+	t.Helper()
+
+	starts := filepath.Join(t.TempDir(), "starts")
+	g := &Gateway{
+		Options: options,
+		configuration: Configuration{
+			serverNames: []string{"crashing"},
+			servers: map[string]catalog.Server{
+				"crashing": {
+					Name:    "crashing",
+					Type:    catalog.ServerTypeCommand,
+					Command: []string{"sh", "-c", "echo start >> " + starts + "; exit 1"},
+				},
+			},
+			serverSourceTypeOverrides: map[string]string{"crashing": catalog.ServerTypeCommand},
+		},
+	}
+	g.clientPool = newClientPool(options, nil, g)
+
+	serverConfig, _, found := g.configuration.Find("crashing")
+	require.True(t, found)
+
+	return g, serverConfig, func() int {
+		content, err := os.ReadFile(starts)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		require.NoError(t, err)
+		return strings.Count(string(content), "start")
+	}
+}
+
+func TestRestartBackoffMarksCrashLoopingServerUnavailable(t *testing.T) {
+	g, serverConfig, starts := crashingGateway(t, Options{RestartBackoff: time.Second, MaxRestartFailures: 3})
+	now := time.Now()
+	g.clientPool.restarts.now = func() time.Time { return now }
+
+	// The first start fails and the server is given a 1s backoff.
+	_, err := g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.Error(t, err)
+	assert.Equal(t, 1, starts())
+
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.EqualError(t, err, "server crashing failed to start, restarting it in 1s at the earliest")
+	assert.Equal(t, 1, starts())
+
+	// The second failure doubles the backoff.
+	now = now.Add(time.Second)
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.Error(t, err)
+	assert.Equal(t, 2, starts())
+
+	now = now.Add(time.Second)
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.EqualError(t, err, "server crashing failed to start, restarting it in 1s at the earliest")
+	assert.Equal(t, 2, starts())
+	assert.Equal(t, ServerStateStopped, g.serverStatuses()[0].State)
+
+	// The third failure is one too many.
+	now = now.Add(time.Second)
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.Error(t, err)
+	assert.Equal(t, 3, starts())
+
+	now = now.Add(time.Hour)
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.EqualError(t, err, "server crashing is crash looping: it failed to start 3 times in a row")
+	assert.Equal(t, 3, starts())
+
+	status := g.serverStatuses()[0]
+	assert.Equal(t, ServerStateUnavailable, status.State)
+	assert.Equal(t, "server crashing is crash looping: it failed to start 3 times in a row", status.LastError)
+	assert.Equal(t, 1, g.status().Servers.Unavailable)
+
+	// Changing the server's configuration gives it another chance.
+	g.clientPool.InvalidateServerClients("crashing")
+	_, err = g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "crash looping")
+	assert.Equal(t, 4, starts())
+	assert.Equal(t, ServerStateStopped, g.serverStatuses()[0].State)
+}
+
+func TestRestartBackoffIsCapped(t *testing.T) {
+	rb := newRestartBackoff(time.Second, 0)
+	now := time.Now()
+	rb.now = func() time.Time { return now }
+
+	for i := range 10 {
+		failures, crashLoop := rb.failed("crashing")
+		assert.Equal(t, i+1, failures)
+		assert.False(t, crashLoop)
+	}
+	assert.Equal(t, now.Add(maxRestartBackoff), rb.servers["crashing"].retryAt)
+}
+
+func TestRestartBackoffForgetsOldFailures(t *testing.T) {
+	rb := newRestartBackoff(time.Second, 2)
+	now := time.Now()
+	rb.now = func() time.Time { return now }
+
+	_, crashLoop := rb.failed("flaky")
+	assert.False(t, crashLoop)
+
+	now = now.Add(crashLoopWindow + time.Second)
+	failures, crashLoop := rb.failed("flaky")
+	assert.Equal(t, 1, failures)
+	assert.False(t, crashLoop)
+	require.NoError(t, rb.allow("other"))
+}
+
+func TestRestartBackoffDisabled(t *testing.T) {
+	g, serverConfig, starts := crashingGateway(t, Options{})
+	require.Nil(t, g.clientPool.restarts)
+
+	for range 3 {
+		_, err := g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+		require.Error(t, err)
+	}
+	assert.Equal(t, 3, starts())
+	assert.Equal(t, ServerStateStopped, g.serverStatuses()[0].State)
+}
+
+func TestRestartBackoffIgnoresRemoteServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	g := &Gateway{
+		Options: Options{RestartBackoff: time.Second, MaxRestartFailures: 1},
+		configuration: Configuration{
+			serverNames: []string{"remote"},
+			servers: map[string]catalog.Server{
+				"remote": {
+					Name:   "remote",
+					Type:   "remote",
+					Remote: catalog.Remote{URL: server.URL, Transport: "streamable-http"},
+				},
+			},
+		},
+	}
+	g.clientPool = newClientPool(g.Options, nil, g)
+
+	serverConfig, _, found := g.configuration.Find("remote")
+	require.True(t, found)
+
+	// Failing to connect to a remote server never delays the next attempt.
+	for range 3 {
+		_, err := g.clientPool.AcquireClient(t.Context(), serverConfig, nil)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "crash looping")
+		assert.NotContains(t, err.Error(), "restarting it")
+	}
+	assert.Empty(t, g.clientPool.restarts.crashLooping())
+	assert.Empty(t, g.clientPool.restarts.servers)
+}
@@ -65,6 +65,7 @@ type Gateway struct {
 	mcpServer      *mcp.Server
 	policyClient   policy.Client
 	health         health.State
+	startedAt      time.Time
 	oauthProviders map[string]*oauth.Provider
 	providersMu    sync.RWMutex
 	// subsChannel  chan SubsMessage
@@ -80,6 +81,33 @@ type Gateway struct {
 	// Track all tool registrations for mcp-exec
 	toolRegistrations map[string]ToolRegistration
 
+	// Track the registered prompts and resource templates, by exposed name
+	// and URI template, to route completion requests
+	promptRegistrations           map[string]PromptRegistration
+	resourceTemplateRegistrations map[string]ResourceTemplateRegistration
+
+	// Track servers that could not be started during the last capability listing
+	failedServersMu sync.Mutex
+	failedServers   map[string]bool
+
+	// Track the last error and tool call of each server, for /servers
+	serverActivityMu sync.Mutex
+	serverLastErrors map[string]string
+	serverLastCalls  map[string]time.Time
+
+	// Limit concurrent tool calls per server
+	concurrencyLimiter *serverConcurrencyLimiter
+
+	// Experimental capabilities advertised by each server, for initialize
+	experimentalMu     sync.Mutex
+	serverExperimental map[string]map[string]any
+
+	// Transforms of tool results, by server then tool name
+	toolResultTransforms map[string]map[string]string
+
+	// Record tool calls, for --record-calls
+	callRecorder *callRecorder
+
 	// Track ongoing refresh operations per server to prevent concurrent/recursive refreshes
 	refreshMu         sync.Mutex
 	refreshingServers map[string]bool
@@ -97,6 +125,10 @@ type Gateway struct {
 }
 
 func NewGateway(config Config, docker docker.Client) *Gateway {
+	if config.SafeMode {
+		config.applySafeMode()
+	}
+
 	var configurator Configurator
 	if config.WorkingSet != "" {
 		configurator = NewWorkingSetConfiguration(config, oci.NewService(), docker)
@@ -133,13 +165,85 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 }
 
 func (g *Gateway) filterByPolicy(ctx context.Context, cfg *Configuration) {
+	cfg.filterByImagePolicy(g.imagePolicy())
+	cfg.filterByImagePolicy(cfg.imagePolicy)
 	if err := cfg.FilterByPolicy(ctx, g.policyClient); err != nil {
 		log.Log("policy filtering failed:", err)
 	}
 }
 
 func (g *Gateway) Run(ctx context.Context) error {
+	g.startedAt = time.Now()
+
+	if err := validateAnnounceCapabilities(g.AnnounceCapabilities); err != nil {
+		return err
+	}
+	if err := validateDuplicateCapabilities(g.DuplicateCapabilities); err != nil {
+		return err
+	}
+	if err := validateDisabledCapabilities(g.DisabledCapabilities); err != nil {
+		return err
+	}
+	if err := validateHookCommands(g.HookCommands); err != nil {
+		return err
+	}
+	if g.RemoteRetries < 0 || g.RemoteRetryBackoff < 0 {
+		return fmt.Errorf("--remote-retries and --remote-retry-backoff must not be negative")
+	}
+	if g.RestartBackoff < 0 || g.MaxRestartFailures < 0 {
+		return fmt.Errorf("--restart-backoff and --max-restart-failures must not be negative")
+	}
+	if g.DiscoveryTimeout < 0 {
+		return fmt.Errorf("--discovery-timeout must not be negative")
+	}
+	if g.RemoteMaxIdleConns < 0 {
+		return fmt.Errorf("--remote-max-idle-conns must not be negative")
+	}
+	concurrencyLimiter, err := newServerConcurrencyLimiter(g.ServerConcurrency)
+	if err != nil {
+		return err
+	}
+	g.concurrencyLimiter = concurrencyLimiter
+	if _, err := parseContainerLabels(g.ContainerLabels); err != nil {
+		return err
+	}
+	if _, err := parseServerEnv(g.ServerEnv); err != nil {
+		return err
+	}
+	if _, err := parseRemoteHeaders(g.RemoteHeaders); err != nil {
+		return err
+	}
+	toolResultTransforms, err := parseToolResultTransforms(g.ToolResultTransforms)
+	if err != nil {
+		return err
+	}
+	g.toolResultTransforms = toolResultTransforms
+	redactPatterns, err := interceptors.ParseRedactPatterns(g.RedactPatterns)
+	if err != nil {
+		return err
+	}
+	if err := g.imagePolicy().Validate(); err != nil {
+		return err
+	}
+	if g.MaxToolResponseBytes < 0 {
+		return fmt.Errorf("invalid --truncate-results %d: must be positive", g.MaxToolResponseBytes)
+	}
+	if g.PageSize < 0 {
+		return fmt.Errorf("invalid page size %d: must be positive", g.PageSize)
+	}
+	if g.MaxResourcesPerServer < 0 {
+		return fmt.Errorf("--max-resources-per-server must not be negative")
+	}
+
 	// Initialize telemetry
+	if g.MetricsSnapshotPath != "" {
+		snapshotter := telemetry.NewMetricsSnapshotter()
+		defer func() {
+			if err := snapshotter.WriteFile(context.WithoutCancel(ctx), g.MetricsSnapshotPath); err != nil {
+				log.Logf("Warning: %v", err)
+			}
+		}()
+	}
 	telemetry.Init()
 
 	if g.policyClient == nil {
@@ -159,6 +263,17 @@ func (g *Gateway) Run(ctx context.Context) error {
 		log.SetLogWriter(multiWriter)
 	}
 
+	// Recorded calls might contain sensitive data, even with secrets redacted.
+	if g.RecordCallsPath != "" {
+		recordFile, err := os.OpenFile(g.RecordCallsPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open recorded calls file %s: %w", g.RecordCallsPath, err)
+		}
+		defer recordFile.Close()
+
+		g.callRecorder = &callRecorder{w: recordFile}
+	}
+
 	// Initialize embeddings client if feature is enabled and OPENAI_API_KEY is set
 	if g.UseEmbeddings {
 		if os.Getenv("OPENAI_API_KEY") == "" {
@@ -239,10 +354,21 @@ func (g *Gateway) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	enabledServers := len(configuration.serverNames)
 	g.filterByPolicy(ctx, &configuration)
+	if err := configuration.resolveConfigFiles(g.ConfigFileDirs); err != nil {
+		return err
+	}
+	if err := configuration.resolveRemoteURLs(ctx); err != nil {
+		return err
+	}
 	g.configuration = configuration
 	defer func() { _ = stopConfigWatcher() }()
 
+	if g.ExplainServer != "" {
+		return g.printServerResolution(os.Stdout, g.ExplainServer)
+	}
+
 	// Parse interceptors
 	var parsedInterceptors []interceptors.Interceptor
 	if len(g.Interceptors) > 0 {
@@ -258,6 +384,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 		Name:    "Docker AI MCP Gateway",
 		Version: "2.0.1",
 	}, &mcp.ServerOptions{
+		PageSize: g.PageSize,
 		SubscribeHandler: func(_ context.Context, req *mcp.SubscribeRequest) error {
 			log.Log("- Client subscribed to URI:", req.Params.URI)
 			// The MCP SDK doesn't provide ServerSession in SubscribeHandler because it already
@@ -277,7 +404,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 			// We can't get the ServerSession from the request anymore, so we'll need to handle this differently
 			_, _ = req.Session.ListRoots(ctx, &mcp.ListRootsParams{})
 		},
-		CompletionHandler: nil,
+		CompletionHandler: g.completionHandler,
 		InitializedHandler: func(_ context.Context, req *mcp.InitializedRequest) {
 			clientInfo := req.Session.InitializeParams().ClientInfo
 			log.Log(fmt.Sprintf("- Client initialized %s@%s %s", clientInfo.Name, clientInfo.Version, clientInfo.Title))
@@ -304,13 +431,22 @@ func (g *Gateway) Run(ctx context.Context) error {
 				g.RemoveSessionCache(ss)
 			}()
 		},
-		HasPrompts:   true,
-		HasResources: true,
-		HasTools:     true,
+		HasPrompts:   g.announceAllCapabilities() && !g.capabilityDisabled(CapabilityPrompts),
+		HasResources: g.announceAllCapabilities() && !g.resourcesDisabled(),
+		HasTools:     g.announceAllCapabilities(),
 	})
 
 	// Add interceptor middleware to the server (includes telemetry)
 	middlewares := interceptors.Callbacks(g.LogCalls, g.BlockSecrets, g.OAuthInterceptorEnabled, parsedInterceptors)
+	if len(redactPatterns) > 0 {
+		middlewares = append(middlewares, interceptors.RedactMiddleware(redactPatterns))
+	}
+
+	// Answer calls to unknown tools with a tool error result instead of a protocol error
+	middlewares = append(middlewares, g.unknownToolMiddleware())
+	middlewares = append(middlewares, g.toolCallLimitsMiddleware())
+	middlewares = append(middlewares, g.recordCallsMiddleware())
+	middlewares = append(middlewares, g.experimentalCapabilitiesMiddleware())
 
 	// Add profile loading middleware for initialize method
 	if g.UseProfiles {
@@ -324,6 +460,9 @@ func (g *Gateway) Run(ctx context.Context) error {
 	// Which docker images are used?
 	// Pull them and verify them if possible.
 	if !g.Static {
+		if err := g.checkDockerAvailable(ctx, configuration); err != nil {
+			return err
+		}
 		if err := g.pullAndVerify(ctx, configuration); err != nil {
 			return err
 		}
@@ -342,6 +481,19 @@ func (g *Gateway) Run(ctx context.Context) error {
 		return fmt.Errorf("loading configuration: %w", err)
 	}
 
+	if g.FailOnEmpty {
+		if err := g.emptyServersError(enabledServers); err != nil {
+			return fmt.Errorf("refusing to start with --fail-on-empty: %w", err)
+		}
+	}
+
+	if g.PrintToolSchemas {
+		// stdout is reserved for the stdio transport.
+		if err := g.printToolSchemas(os.Stderr); err != nil {
+			return fmt.Errorf("printing tool schemas: %w", err)
+		}
+	}
+
 	// When running in Container mode, disable OAuth notification monitoring.
 	inContainer := os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1"
 
@@ -421,6 +573,16 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 					g.filterByPolicy(ctx, &configuration)
 
+					if err := configuration.resolveConfigFiles(g.ConfigFileDirs); err != nil {
+						log.Logf("> Unable to read config files: %s", err)
+						continue
+					}
+
+					if err := configuration.resolveRemoteURLs(ctx); err != nil {
+						log.Logf("> Unable to resolve remote URLs: %s", err)
+						continue
+					}
+
 					if err := g.pullAndVerify(ctx, configuration); err != nil {
 						log.Logf("> Unable to pull and verify images: %s", err)
 						continue
@@ -437,6 +599,20 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}
 
 	log.Log("> Initialized in", time.Since(start))
+	if g.StartupSummary {
+		// stdout is reserved for the stdio transport.
+		out := os.Stdout
+		if strings.EqualFold(g.Transport, "stdio") {
+			out = os.Stderr
+		}
+		if err := writeStartupSummary(out, g.startupSummary()); err != nil {
+			log.Logf("! Unable to write startup summary: %v", err)
+		}
+	}
+	if g.ReplayCallsPath != "" {
+		log.Log("> Replaying calls from", g.ReplayCallsPath)
+		return g.replayRecordedCalls(ctx, g.ReplayCallsPath, os.Stdout)
+	}
 	if g.DryRun {
 		log.Log("Dry run mode enabled, not starting the server.")
 		return nil
@@ -491,6 +667,23 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}
 }
 
+func validateAnnounceCapabilities(value string) error {
+	switch value {
+	case "", AnnounceCapabilitiesAll, AnnounceCapabilitiesPresent:
+		return nil
+	default:
+		return fmt.Errorf("unknown --announce-capabilities value %q, expected '%s' or '%s'", value, AnnounceCapabilitiesAll, AnnounceCapabilitiesPresent)
+	}
+}
+
+// announceAllCapabilities reports whether the initialize response should
+// advertise every capability regardless of what the active servers expose.
+// Otherwise the MCP SDK derives the capabilities from the registered tools,
+// prompts, resources and resource templates.
+func (g *Gateway) announceAllCapabilities() bool {
+	return g.AnnounceCapabilities != AnnounceCapabilitiesPresent
+}
+
 func (g *Gateway) initializeHTTPAuth() error {
 	if !isHTTPTransport(g.Transport) {
 		return nil
@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+)
+
+// ContainerRunArgs returns the arguments of the docker command a gateway
+// started with the given options runs for the container of a server, image
+// and command included, and its env. The networks of the gateway's own
+// container and the proxies of servers with allowed hosts are only known
+// once the gateway runs, so they are left out.
+func ContainerRunArgs(ctx context.Context, options Options, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+	cp := &clientPool{Options: options}
+	return cp.runArgs(ctx, serverConfig, proxies.TargetConfig{})
+}
+
+// LocalCommandRunArgs returns the command a gateway started with the given
+// options runs on the host for a command server, and its env.
+func LocalCommandRunArgs(ctx context.Context, options Options, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+	cp := &clientPool{Options: options}
+	return cp.localCommand(ctx, serverConfig)
+}
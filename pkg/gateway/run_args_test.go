@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func TestRunPlanMatchesArgsAndEnv(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	spec := catalog.Server{
+		Name:            "fs",
+		Type:            "server",
+		Image:           "mcp/fs@sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		Secrets:         []catalog.Secret{{Name: "fs.token", Env: "FS_TOKEN"}},
+		SecretTemplates: []catalog.SecretTemplate{{Env: "AUTH", Template: "Bearer {{fs.token}}"}},
+		Env: []catalog.Env{
+			{Name: "LOG_LEVEL", Value: "{{fs.level}}"},
+			{Name: "UNSET", Value: "{{fs.missing}}"},
+		},
+		Volumes: []string{"{{fs.paths|volume|into}}"},
+		Command: []string{"--root", "/data"},
+		Labels:  map[string]string{"team": "files"},
+	}
+	serverConfig := map[string]any{"paths": []any{"/tmp/docs"}, "level": "debug"}
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "run-set",
+		Name: "Run Set",
+		Servers: db.ServerList{
+			{
+				Type:     string(workingset.ServerTypeImage),
+				Image:    spec.Image,
+				Config:   serverConfig,
+				Snapshot: &db.ServerSnapshot{Server: spec},
+			},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	options := Options{
+		Cpus:            2,
+		Memory:          "1Gb",
+		ContainerLabels: []string{"owner=platform"},
+		ServerEnv:       []string{"HTTPS_PROXY=http://proxy:3128"},
+	}
+	runArgs := workingset.RunArgs{
+		Container: func(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+			return ContainerRunArgs(ctx, options, serverConfig)
+		},
+	}
+	plan, err := workingset.ResolveRunPlan(t.Context(), dao, mocks.NewMockOCIService(), "run-set", runArgs)
+	require.NoError(t, err)
+	require.Len(t, plan.Servers, 1)
+
+	cp := &clientPool{Options: options}
+	args, env, err := cp.argsAndEnv(t.Context(), &catalog.ServerConfig{
+		Name:    "fs",
+		Spec:    spec,
+		Config:  map[string]any{"fs": serverConfig},
+		Secrets: map[string]string{"fs.token": "<secret:fs.token>"},
+	}, proxies.TargetConfig{})
+	require.NoError(t, err)
+
+	expected := append([]string{"docker"}, args...)
+	expected = append(expected, spec.Image, "--root", "/data")
+	assert.Equal(t, expected, plan.Servers[0].Command)
+	assert.Contains(t, plan.Servers[0].Command, "--pull")
+	assert.Contains(t, plan.Servers[0].Command, "--cpus")
+	assert.Contains(t, plan.Servers[0].Command, "owner=platform")
+	assert.Len(t, plan.Servers[0].Env, len(env))
+	assert.Equal(t, map[string]string{
+		"FS_TOKEN":    "<secret:fs.token>",
+		"AUTH":        "Bearer <secret:fs.token>",
+		"LOG_LEVEL":   "debug",
+		"HTTPS_PROXY": "http://proxy:3128",
+	}, plan.Servers[0].Env)
+}
+
+func TestRunPlanOfCommandServerMatchesLocalCommand(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	spec := catalog.Server{
+		Name:    "my-mcp",
+		Type:    catalog.ServerTypeCommand,
+		Secrets: []catalog.Secret{{Name: "my-mcp.token", Env: "TOKEN"}},
+		Env:     []catalog.Env{{Name: "LOG_LEVEL", Value: "{{my-mcp.level}}"}},
+	}
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{{
+			Type:     string(workingset.ServerTypeCommand),
+			Exec:     "./bin/my-mcp",
+			Args:     []string{"--log-level", "{{my-mcp.level}}"},
+			Config:   map[string]any{"level": "debug"},
+			Snapshot: &db.ServerSnapshot{Server: spec},
+		}},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	runArgs := workingset.RunArgs{
+		LocalCommand: func(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+			return LocalCommandRunArgs(ctx, Options{}, serverConfig)
+		},
+	}
+	plan, err := workingset.ResolveRunPlan(t.Context(), dao, mocks.NewMockOCIService(), "dev", runArgs)
+	require.NoError(t, err)
+	require.Len(t, plan.Servers, 1)
+
+	assert.Equal(t, []string{"./bin/my-mcp", "--log-level", "debug"}, plan.Servers[0].Command)
+	assert.Equal(t, "<secret:my-mcp.token>", plan.Servers[0].Env["TOKEN"])
+	assert.Equal(t, "debug", plan.Servers[0].Env["LOG_LEVEL"])
+	assert.Empty(t, plan.Servers[0].Image)
+}
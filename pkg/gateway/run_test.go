@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initializeResultFor(t *testing.T, g *Gateway, register func(*mcp.Server)) *mcp.InitializeResult {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, &mcp.ServerOptions{
+		HasPrompts:   g.announceAllCapabilities() && !g.capabilityDisabled(CapabilityPrompts),
+		HasResources: g.announceAllCapabilities() && !g.resourcesDisabled(),
+		HasTools:     g.announceAllCapabilities(),
+	})
+	register(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return clientSession.InitializeResult()
+}
+
+func registerToolOnly(server *mcp.Server) {
+	server.AddTool(&mcp.Tool{
+		Name:        "only-tool",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+}
+
+func TestAnnounceCapabilitiesPresent(t *testing.T) {
+	g := &Gateway{Options: Options{AnnounceCapabilities: AnnounceCapabilitiesPresent}}
+
+	result := initializeResultFor(t, g, registerToolOnly)
+
+	require.NotNil(t, result.Capabilities)
+	assert.NotNil(t, result.Capabilities.Tools)
+	assert.Nil(t, result.Capabilities.Prompts)
+	assert.Nil(t, result.Capabilities.Resources)
+}
+
+func TestAnnounceCapabilitiesAll(t *testing.T) {
+	for _, value := range []string{"", AnnounceCapabilitiesAll} {
+		g := &Gateway{Options: Options{AnnounceCapabilities: value}}
+
+		result := initializeResultFor(t, g, registerToolOnly)
+
+		require.NotNil(t, result.Capabilities)
+		assert.NotNil(t, result.Capabilities.Tools)
+		assert.NotNil(t, result.Capabilities.Prompts)
+		assert.NotNil(t, result.Capabilities.Resources)
+	}
+}
+
+func TestValidateAnnounceCapabilities(t *testing.T) {
+	require.NoError(t, validateAnnounceCapabilities(""))
+	require.NoError(t, validateAnnounceCapabilities(AnnounceCapabilitiesAll))
+	require.NoError(t, validateAnnounceCapabilities(AnnounceCapabilitiesPresent))
+	require.Error(t, validateAnnounceCapabilities("some"))
+}
+
+// runStreamingGateway runs the gateway, without any server, on a local port
+// and returns the initialize result a client gets from it.
+func runStreamingGateway(t *testing.T, options Options) *mcp.InitializeResult {
+	t.Helper()
+
+	lc := &net.ListenConfig{}
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+
+	options.Transport = "streaming"
+	options.Host = "127.0.0.1"
+	options.Port = port
+	options.Static = true
+	options.AllowUnauthenticated = true
+	g := NewGateway(Config{Options: options}, nil)
+	g.configurator = &staticConfigurator{}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	runErr := make(chan error, 1)
+	go func() { runErr <- g.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-runErr
+	})
+
+	require.Eventually(t, func() bool {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d/health", port), nil)
+		if err != nil {
+			return false
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 10*time.Second, 20*time.Millisecond)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{Endpoint: fmt.Sprintf("http://127.0.0.1:%d/mcp", port)}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session.InitializeResult()
+}
+
+func TestRunAnnouncesPresentCapabilities(t *testing.T) {
+	result := runStreamingGateway(t, Options{AnnounceCapabilities: AnnounceCapabilitiesPresent})
+
+	require.NotNil(t, result.Capabilities)
+	assert.Nil(t, result.Capabilities.Prompts)
+	assert.Nil(t, result.Capabilities.Resources)
+}
+
+func TestRunAnnouncesAllCapabilities(t *testing.T) {
+	result := runStreamingGateway(t, Options{})
+
+	require.NotNil(t, result.Capabilities)
+	assert.NotNil(t, result.Capabilities.Tools)
+	assert.NotNil(t, result.Capabilities.Prompts)
+	assert.NotNil(t, result.Capabilities.Resources)
+}
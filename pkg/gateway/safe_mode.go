@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// Values used by --safe-mode. Keep docs/security.md in sync.
+const (
+	safeModeCpus                 = 1
+	safeModeMemory               = "1Gb"
+	safeModeMaxToolResponseBytes = 1024 * 1024
+	safeModeToolTimeout          = 2 * time.Minute
+)
+
+// mutatingDynamicTools are the internal tools that change the set of servers,
+// their configuration, profiles or the exposed tools. They are not registered
+// in safe mode.
+var mutatingDynamicTools = []string{
+	"mcp-add",
+	"mcp-remove",
+	"mcp-config-set",
+	"code-mode",
+	"mcp-create-profile",
+	"mcp-activate-profile",
+}
+
+// applySafeMode overrides the options toggled by --safe-mode:
+//   - mutating dynamic tools are disabled (see mutatingDynamicTools),
+//   - containers drop all capabilities and run with a read-only root filesystem,
+//     limited to 1 CPU and 1Gb of memory unless --cpus or --memory are set,
+//   - network access is restricted (--block-network) and secrets are blocked
+//     (--block-secrets),
+//   - tool responses are capped to 1MiB of text, unless --truncate-results is
+//     lower, and tool calls time out after 2m.
+//
+// Cpus and Memory are only defaulted when they are zero: the caller clears
+// the values that weren't set explicitly.
+func (o *Options) applySafeMode() {
+	o.HardenContainers = true
+	if o.Cpus <= 0 {
+		o.Cpus = safeModeCpus
+	}
+	if o.Memory == "" {
+		o.Memory = safeModeMemory
+	}
+	o.BlockNetwork = true
+	o.BlockSecrets = true
+	if o.MaxToolResponseBytes <= 0 || o.MaxToolResponseBytes > safeModeMaxToolResponseBytes {
+		o.MaxToolResponseBytes = safeModeMaxToolResponseBytes
+	}
+	o.ToolTimeout = safeModeToolTimeout
+}
+
+func isMutatingDynamicTool(toolName string) bool {
+	return slices.Contains(mutatingDynamicTools, toolName)
+}
+
+// disableMutatingDynamicTools unregisters the mutating internal tools.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) disableMutatingDynamicTools() {
+	var disabled []string
+	for _, toolName := range mutatingDynamicTools {
+		if _, ok := g.toolRegistrations[toolName]; ok {
+			delete(g.toolRegistrations, toolName)
+			disabled = append(disabled, toolName)
+		}
+	}
+	if len(disabled) > 0 {
+		g.mcpServer.RemoveTools(disabled...)
+		log.Log("  - Safe mode: disabled", disabled)
+	}
+}
+
+// toolCallLimitsMiddleware applies the tool call timeout and the response size
+// cap configured in the options.
+func (g *Gateway) toolCallLimitsMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			if g.ToolTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, g.ToolTimeout)
+				defer cancel()
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+
+			if toolResult, ok := result.(*mcp.CallToolResult); ok && g.MaxToolResponseBytes > 0 {
+				return truncateToolResult(toolResult, g.MaxToolResponseBytes), nil
+			}
+			return result, nil
+		}
+	}
+}
+
+// truncateToolResult caps the total size of the text content of a tool result
+// and appends a marker when it cut anything. Other content and the structured
+// content are left intact.
+func truncateToolResult(result *mcp.CallToolResult, maxBytes int) *mcp.CallToolResult {
+	remaining := maxBytes
+	truncated := false
+	content := make([]mcp.Content, 0, len(result.Content))
+	for _, c := range result.Content {
+		text, ok := c.(*mcp.TextContent)
+		if !ok {
+			content = append(content, c)
+			continue
+		}
+		if len(text.Text) <= remaining {
+			remaining -= len(text.Text)
+			content = append(content, c)
+			continue
+		}
+
+		truncated = true
+		// Don't cut a multi-byte character in half.
+		for remaining > 0 && !utf8.RuneStart(text.Text[remaining]) {
+			remaining--
+		}
+		if remaining > 0 {
+			content = append(content, &mcp.TextContent{Text: text.Text[:remaining], Meta: text.Meta, Annotations: text.Annotations})
+			remaining = 0
+		}
+	}
+	if !truncated {
+		return result
+	}
+
+	content = append(content, &mcp.TextContent{
+		Text: fmt.Sprintf("[response truncated to %d bytes]", maxBytes),
+	})
+	capped := *result
+	capped.Content = content
+	return &capped
+}
@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
+)
+
+func TestNewGatewaySafeMode(t *testing.T) {
+	g := NewGateway(Config{Options: Options{
+		SafeMode:     true,
+		DynamicTools: true,
+	}}, nil)
+
+	assert.True(t, g.HardenContainers)
+	assert.Equal(t, 1, g.Cpus)
+	assert.Equal(t, "1Gb", g.Memory)
+	assert.True(t, g.BlockNetwork)
+	assert.True(t, g.BlockSecrets)
+	assert.Equal(t, 1024*1024, g.MaxToolResponseBytes)
+	assert.Equal(t, 2*time.Minute, g.ToolTimeout)
+	assert.True(t, g.DynamicTools, "read-only dynamic tools stay available")
+
+	// The client pool starts containers with the same options.
+	args := strings.Join(g.clientPool.baseArgs("server", nil, catalognext.PullOptionNever), " ")
+	assert.Contains(t, args, "--cap-drop ALL --read-only --tmpfs /tmp")
+	assert.Contains(t, args, "--cpus 1")
+	assert.Contains(t, args, "--memory 1Gb")
+}
+
+func TestNewGatewaySafeModeKeepsExplicitLimits(t *testing.T) {
+	g := NewGateway(Config{Options: Options{
+		SafeMode: true,
+		Cpus:     4,
+		Memory:   "8Gb",
+	}}, nil)
+
+	assert.True(t, g.HardenContainers)
+	assert.Equal(t, 4, g.Cpus)
+	assert.Equal(t, "8Gb", g.Memory)
+	assert.Equal(t, 2*time.Minute, g.ToolTimeout)
+}
+
+func TestNewGatewaySafeModeKeepsLowerTruncation(t *testing.T) {
+	g := NewGateway(Config{Options: Options{SafeMode: true, MaxToolResponseBytes: 1000}}, nil)
+	assert.Equal(t, 1000, g.MaxToolResponseBytes)
+
+	g = NewGateway(Config{Options: Options{SafeMode: true, MaxToolResponseBytes: 10 * 1024 * 1024}}, nil)
+	assert.Equal(t, 1024*1024, g.MaxToolResponseBytes)
+}
+
+func TestNewGatewayWithoutSafeMode(t *testing.T) {
+	g := NewGateway(Config{Options: Options{Cpus: 4, Memory: "8Gb"}}, nil)
+
+	assert.False(t, g.HardenContainers)
+	assert.Equal(t, 4, g.Cpus)
+	assert.Zero(t, g.MaxToolResponseBytes)
+	assert.Zero(t, g.ToolTimeout)
+	assert.NotContains(t, g.clientPool.baseArgs("server", nil, catalognext.PullOptionNever), "--cap-drop")
+}
+
+func TestSafeModeRejectsMutatingDynamicTool(t *testing.T) {
+	g := &Gateway{
+		Options:           Options{SafeMode: true},
+		toolRegistrations: make(map[string]ToolRegistration),
+		mcpServer:         mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+	for _, name := range []string{"mcp-find", "mcp-add"} {
+		registration := ToolRegistration{
+			Tool: &mcp.Tool{Name: name, InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+			},
+		}
+		g.mcpServer.AddTool(registration.Tool, registration.Handler)
+		g.toolRegistrations[name] = registration
+	}
+	g.disableMutatingDynamicTools()
+	g.mcpServer.AddReceivingMiddleware(g.unknownToolMiddleware())
+
+	assert.Contains(t, g.toolRegistrations, "mcp-find")
+	assert.NotContains(t, g.toolRegistrations, "mcp-add")
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "mcp-add"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool 'mcp-add' is disabled in safe mode.", result.Content[0].(*mcp.TextContent).Text)
+
+	result, err = session.CallTool(t.Context(), &mcp.CallToolParams{Name: "mcp-find"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
+
+func TestTruncateToolResult(t *testing.T) {
+	result := &mcp.CallToolResult{Content: []mcp.Content{
+		&mcp.TextContent{Text: "hello"},
+		&mcp.ImageContent{MIMEType: "image/png"},
+		&mcp.TextContent{Text: "wörld"},
+	}}
+
+	assert.Same(t, result, truncateToolResult(result, 100))
+
+	truncated := truncateToolResult(result, 7)
+	require.Len(t, truncated.Content, 4)
+	assert.Equal(t, "hello", truncated.Content[0].(*mcp.TextContent).Text)
+	assert.IsType(t, &mcp.ImageContent{}, truncated.Content[1])
+	// "ö" is two bytes and doesn't fit in the remaining budget.
+	assert.Equal(t, "w", truncated.Content[2].(*mcp.TextContent).Text)
+	assert.Equal(t, "[response truncated to 7 bytes]", truncated.Content[3].(*mcp.TextContent).Text)
+	assert.Len(t, result.Content, 3, "the original result is left untouched")
+}
+
+func TestToolCallLimitsMiddlewareTimeout(t *testing.T) {
+	g := &Gateway{Options: Options{ToolTimeout: 10 * time.Millisecond}}
+
+	handler := g.toolCallLimitsMiddleware()(func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	_, err := handler(t.Context(), "tools/call", &mcp.CallToolRequest{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestToolCallLimitsMiddlewareTruncatesResults(t *testing.T) {
+	g := &Gateway{Options: Options{MaxToolResponseBytes: 100000}}
+
+	structured := map[string]any{"rows": 3}
+	handler := g.toolCallLimitsMiddleware()(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{&mcp.TextContent{Text: strings.Repeat("x", 150000)}},
+			StructuredContent: structured,
+		}, nil
+	})
+
+	result, err := handler(t.Context(), "tools/call", &mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	toolResult := result.(*mcp.CallToolResult)
+	require.Len(t, toolResult.Content, 2)
+	assert.Len(t, toolResult.Content[0].(*mcp.TextContent).Text, 100000)
+	assert.Equal(t, "[response truncated to 100000 bytes]", toolResult.Content[1].(*mcp.TextContent).Text)
+	assert.Equal(t, structured, toolResult.StructuredContent)
+}
@@ -2,11 +2,13 @@ package gateway
 
 import (
 	"context"
+	"fmt"
 
 	seclient "github.com/docker/secrets-engine/client"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/secret"
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
 	"github.com/docker/mcp-gateway/pkg/log"
 )
 
@@ -40,6 +42,23 @@ func BuildSecretsURIs(ctx context.Context, configs []ServerSecretConfig) map[str
 	return buildVerifiedURIs(configs, availableSecrets)
 }
 
+// unsetSecrets returns the secrets a server reads from the secret store that
+// have no value. The parts of its secret templates can also be set with the
+// server's config.
+func unsetSecrets(server *catalog.Server, secrets map[string]string, config map[string]any) []string {
+	var missing []string
+	for _, s := range server.StoredSecrets() {
+		if secrets[s.Name] != "" {
+			continue
+		}
+		if s.Env == "" && fmt.Sprintf("%v", eval.Evaluate("{{"+s.Name+"}}", config)) != "" {
+			continue
+		}
+		missing = append(missing, s.Name)
+	}
+	return missing
+}
+
 // buildFallbackURIs generates se:// URIs for all declared secrets without checking
 // existence. Used when the secrets engine is unreachable. OAuth URIs are preferred
 // when configured (matching the normal priority order).
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestUnsetSecrets(t *testing.T) {
+	server := &catalog.Server{
+		Name:    "jira",
+		Secrets: []catalog.Secret{{Name: "jira.token", Env: "JIRA_TOKEN"}},
+		SecretTemplates: []catalog.SecretTemplate{
+			{Env: "JIRA_AUTH", Template: "{{jira.user}}:{{jira.token}}"},
+			{Env: "JIRA_URL", Template: "https://{{jira.host}}"},
+		},
+	}
+
+	t.Run("nothing set", func(t *testing.T) {
+		assert.Equal(t, []string{"jira.token", "jira.user", "jira.host"}, unsetSecrets(server, nil, nil))
+	})
+
+	t.Run("template parts from the secret store or the config", func(t *testing.T) {
+		secrets := map[string]string{"jira.token": "se://docker/mcp/jira.token", "jira.user": "se://docker/mcp/jira.user"}
+		config := map[string]any{"jira": map[string]any{"host": "jira.example.com"}}
+		assert.Empty(t, unsetSecrets(server, secrets, config))
+	})
+
+	t.Run("declared secrets are not read from the config", func(t *testing.T) {
+		config := map[string]any{"jira": map[string]any{"token": "from-config", "user": "me", "host": "jira.example.com"}}
+		assert.Equal(t, []string{"jira.token"}, unsetSecrets(server, map[string]string{}, config))
+	})
+}
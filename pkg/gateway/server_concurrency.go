@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// serverConcurrencyLimiter bounds the number of concurrent tool calls per
+// server. Servers without a limit are not throttled.
+type serverConcurrencyLimiter struct {
+	queues map[string]*fairCallQueue
+}
+
+// fairCallQueue dispatches the tool calls waiting for a server round-robin
+// across clients, rather than in arrival order, so that a busy client can't
+// monopolize the server's slots.
+type fairCallQueue struct {
+	limit int
+
+	mu       sync.Mutex
+	inFlight int
+	// waiting holds the calls of each client, in arrival order.
+	waiting map[any][]*queuedCall
+	// clients is the round-robin order of the clients with waiting calls.
+	clients []any
+}
+
+type queuedCall struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// parseServerConcurrency parses limits of the form <server>:<max-concurrent-calls>.
+func parseServerConcurrency(values []string) (map[string]int, error) {
+	limits := make(map[string]int, len(values))
+	for _, value := range values {
+		serverName, limitStr, ok := strings.Cut(value, ":")
+		serverName = strings.TrimSpace(serverName)
+		if !ok || serverName == "" {
+			return nil, fmt.Errorf("invalid server concurrency %q: expected <server>:<limit>", value)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid server concurrency %q: limit must be a positive integer", value)
+		}
+		limits[serverName] = limit
+	}
+	return limits, nil
+}
+
+func newServerConcurrencyLimiter(values []string) (*serverConcurrencyLimiter, error) {
+	limits, err := parseServerConcurrency(values)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make(map[string]*fairCallQueue, len(limits))
+	for serverName, limit := range limits {
+		queues[serverName] = &fairCallQueue{
+			limit:   limit,
+			waiting: make(map[any][]*queuedCall),
+		}
+	}
+	return &serverConcurrencyLimiter{queues: queues}, nil
+}
+
+// acquire blocks until a call from client to serverName is allowed to proceed
+// or ctx is done. client identifies the session the call comes from. The
+// returned function must be called once the call completes.
+func (l *serverConcurrencyLimiter) acquire(ctx context.Context, serverName string, client any) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	queue, ok := l.queues[serverName]
+	if !ok {
+		return func() {}, nil
+	}
+
+	call := queue.enqueue(client)
+	select {
+	case <-call.ready:
+		return queue.release, nil
+	case <-ctx.Done():
+		queue.cancel(client, call)
+		return nil, fmt.Errorf("waiting for a free slot on server %s: %w", serverName, ctx.Err())
+	}
+}
+
+// enqueue returns a call that is ready right away if a slot is free and no
+// other call is waiting.
+func (q *fairCallQueue) enqueue(client any) *queuedCall {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	call := &queuedCall{ready: make(chan struct{})}
+	if q.inFlight < q.limit && len(q.clients) == 0 {
+		q.inFlight++
+		call.granted = true
+		close(call.ready)
+		return call
+	}
+
+	if len(q.waiting[client]) == 0 {
+		q.clients = append(q.clients, client)
+	}
+	q.waiting[client] = append(q.waiting[client], call)
+	return call
+}
+
+// release frees a slot and hands it to the oldest call of the next client in
+// turn.
+func (q *fairCallQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.inFlight--
+	q.dispatch()
+}
+
+// cancel gives up on a call whose context is done, releasing its slot if it
+// was granted meanwhile.
+func (q *fairCallQueue) cancel(client any, call *queuedCall) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if call.granted {
+		q.inFlight--
+		q.dispatch()
+		return
+	}
+
+	calls := slices.DeleteFunc(q.waiting[client], func(c *queuedCall) bool { return c == call })
+	if len(calls) > 0 {
+		q.waiting[client] = calls
+		return
+	}
+	delete(q.waiting, client)
+	q.clients = slices.DeleteFunc(q.clients, func(c any) bool { return c == client })
+}
+
+// dispatch grants free slots to waiting calls. Must be called with q.mu held.
+func (q *fairCallQueue) dispatch() {
+	for q.inFlight < q.limit && len(q.clients) > 0 {
+		client := q.clients[0]
+		q.clients = q.clients[1:]
+
+		calls := q.waiting[client]
+		call := calls[0]
+		if len(calls) > 1 {
+			q.waiting[client] = calls[1:]
+			q.clients = append(q.clients, client)
+		} else {
+			delete(q.waiting, client)
+		}
+
+		q.inFlight++
+		call.granted = true
+		close(call.ready)
+	}
+}
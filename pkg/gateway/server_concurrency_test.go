@@ -0,0 +1,154 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServerConcurrency(t *testing.T) {
+	limits, err := parseServerConcurrency([]string{"postgres:1", " sqlite : 2 "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"postgres": 1, "sqlite": 2}, limits)
+
+	for _, value := range []string{"postgres", ":1", "postgres:0", "postgres:-1", "postgres:many"} {
+		_, err := parseServerConcurrency([]string{value})
+		require.Error(t, err, value)
+	}
+}
+
+// trackInFlight runs a fake tool call while recording how many calls to the
+// same server are in flight. It runs in its own goroutine, so it returns the
+// error for the test goroutine to check.
+func trackInFlight(ctx context.Context, limiter *serverConcurrencyLimiter, serverName string, inFlight, maxInFlight *atomic.Int32) error {
+	release, err := limiter.acquire(ctx, serverName, "client")
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	current := inFlight.Add(1)
+	for {
+		previous := maxInFlight.Load()
+		if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	inFlight.Add(-1)
+	return nil
+}
+
+func TestServerConcurrencyLimiter(t *testing.T) {
+	limiter, err := newServerConcurrencyLimiter([]string{"postgres:1"})
+	require.NoError(t, err)
+
+	var postgresInFlight, postgresMax atomic.Int32
+	var otherInFlight, otherMax atomic.Int32
+
+	errs := make(chan error, 8)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- trackInFlight(t.Context(), limiter, "postgres", &postgresInFlight, &postgresMax)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- trackInFlight(t.Context(), limiter, "other", &otherInFlight, &otherMax)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), postgresMax.Load(), "calls to a concurrency-1 server must be serialized")
+	assert.Greater(t, otherMax.Load(), int32(1), "calls to an unlimited server must run concurrently")
+}
+
+// waitingCalls returns the number of calls waiting for a slot on serverName.
+func waitingCalls(limiter *serverConcurrencyLimiter, serverName string) int {
+	queue := limiter.queues[serverName]
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	var count int
+	for _, calls := range queue.waiting {
+		count += len(calls)
+	}
+	return count
+}
+
+func TestServerConcurrencyLimiterIsFairAcrossClients(t *testing.T) {
+	limiter, err := newServerConcurrencyLimiter([]string{"postgres:1"})
+	require.NoError(t, err)
+
+	// Hold the only slot while both clients queue up, the busy one first.
+	release, err := limiter.acquire(t.Context(), "postgres", "busy")
+	require.NoError(t, err)
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	clients := []string{"busy", "busy", "busy", "other", "other", "other"}
+	errs := make(chan error, len(clients))
+	queued := 0
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.acquire(t.Context(), "postgres", client)
+			if err != nil {
+				errs <- err
+				return
+			}
+			mu.Lock()
+			order = append(order, client)
+			mu.Unlock()
+			release()
+		}()
+		queued++
+		require.Eventually(t, func() bool { return waitingCalls(limiter, "postgres") == queued }, time.Second, time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"busy", "other", "busy", "other", "busy", "other"}, order)
+}
+
+func TestServerConcurrencyLimiterHonorsContext(t *testing.T) {
+	limiter, err := newServerConcurrencyLimiter([]string{"postgres:1"})
+	require.NoError(t, err)
+
+	release, err := limiter.acquire(t.Context(), "postgres", "client")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "postgres", "client")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNilServerConcurrencyLimiter(t *testing.T) {
+	var limiter *serverConcurrencyLimiter
+
+	release, err := limiter.acquire(t.Context(), "postgres", "client")
+	require.NoError(t, err)
+	release()
+}
@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// parseServerEnv parses environment variables of the form <key>=<value>.
+// Secrets are rejected: they must go through the secrets of each server.
+func parseServerEnv(values []string) (map[string]string, error) {
+	env := make(map[string]string, len(values))
+	for _, value := range values {
+		key, envValue, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid server env %q: expected <key>=<value>", value)
+		}
+		if strings.HasPrefix(envValue, "se://") {
+			return nil, fmt.Errorf("invalid server env %q: secrets can't be passed with --server-env, use the server's secrets instead", value)
+		}
+		env[key] = envValue
+	}
+	return env, nil
+}
+
+// serverEnvArgs returns the docker run arguments and environment for the
+// variables configured with --server-env, minus the ones in env, which the
+// server already sets itself.
+func (cp *clientPool) serverEnvArgs(env []string) ([]string, []string) {
+	serverEnv, err := parseServerEnv(cp.ServerEnv)
+	if err != nil {
+		// Already validated when the gateway starts.
+		return nil, nil
+	}
+
+	var args, values []string
+	for _, key := range slices.Sorted(maps.Keys(serverEnv)) {
+		if slices.ContainsFunc(env, func(e string) bool { return strings.HasPrefix(e, key+"=") }) {
+			continue
+		}
+		args = append(args, "-e", key)
+		values = append(values, key+"="+serverEnv[key])
+	}
+	return args, values
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+)
+
+func TestParseServerEnv(t *testing.T) {
+	env, err := parseServerEnv([]string{"TZ=UTC", "EMPTY=", "OTEL_RESOURCE_ATTRIBUTES=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TZ": "UTC", "EMPTY": "", "OTEL_RESOURCE_ATTRIBUTES": "a=b"}, env)
+
+	for _, value := range []string{"TZ", "=UTC", "MY VAR=1", "TOKEN=se://docker/mcp/token"} {
+		_, err := parseServerEnv([]string{value})
+		require.Error(t, err, value)
+	}
+}
+
+func TestServerEnvReachesEveryServer(t *testing.T) {
+	cp := &clientPool{
+		Options: Options{
+			Cpus:      1,
+			Memory:    "2Gb",
+			ServerEnv: []string{"TZ=Europe/Paris", "LANG=C.UTF-8", "API_TOKEN=global"},
+		},
+	}
+
+	for _, name := range []string{"github", "postgres"} {
+		args, env, err := cp.argsAndEnv(t.Context(), &catalog.ServerConfig{
+			Name: name,
+			Spec: catalog.Server{Image: "mcp/" + name},
+		}, proxies.TargetConfig{})
+		require.NoError(t, err)
+
+		assert.Subset(t, args, []string{"-e", "API_TOKEN", "-e", "LANG", "-e", "TZ"}, name)
+		assert.Equal(t, []string{"API_TOKEN=global", "LANG=C.UTF-8", "TZ=Europe/Paris"}, env, name)
+	}
+}
+
+func TestServerEnvIsOverriddenByServer(t *testing.T) {
+	cp := &clientPool{
+		Options: Options{
+			Cpus:      1,
+			Memory:    "2Gb",
+			ServerEnv: []string{"TZ=Europe/Paris", "LANG=C.UTF-8", "API_TOKEN=global", "http_proxy=global"},
+		},
+	}
+
+	_, env, err := cp.argsAndEnv(t.Context(), &catalog.ServerConfig{
+		Name: "github",
+		Spec: catalog.Server{
+			Image:   "mcp/github",
+			Env:     []catalog.Env{{Name: "TZ", Value: "UTC"}},
+			Secrets: []catalog.Secret{{Name: "github.token", Env: "API_TOKEN"}},
+		},
+		Secrets: map[string]string{"github.token": "secret"},
+	}, proxies.TargetConfig{Env: []string{"http_proxy=proxy:8080"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"API_TOKEN=secret", "TZ=UTC", "LANG=C.UTF-8"}, env)
+}
+
+func TestServerEnvReachesToolContainers(t *testing.T) {
+	dir := t.TempDir()
+
+	// A docker that prints the env it's given for TZ and its arguments.
+	fakeDocker := "#!/bin/sh\necho \"TZ=$TZ\"\necho \"$@\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(fakeDocker), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cp := &clientPool{Options: Options{ServerEnv: []string{"TZ=Europe/Paris"}}}
+
+	result, err := cp.runToolContainer(t.Context(), catalog.Tool{
+		Name:      "clock",
+		Container: catalog.Container{Image: "mcp/clock", Command: []string{"now"}},
+	}, &mcp.CallToolParams{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	output := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, output, "TZ=Europe/Paris\n")
+	assert.Contains(t, output, "-e TZ mcp/clock now")
+}
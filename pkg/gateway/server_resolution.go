@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Where the definition of a server comes from.
+const (
+	ServerSourceProfile = "profile"
+	ServerSourceCatalog = "catalog"
+	// ServerSourceGateway is for servers given to the gateway directly,
+	// e.g. with --mcp-registry.
+	ServerSourceGateway = "gateway"
+)
+
+// ServerResolution explains which definition of a server the gateway uses,
+// e.g. when several catalogs define a server with the same name.
+type ServerResolution struct {
+	Server string `json:"server"`
+	// Enabled is false for servers that are only available to dynamic tools.
+	Enabled bool `json:"enabled"`
+	// Source is where the definition comes from: ServerSourceProfile,
+	// ServerSourceCatalog or ServerSourceGateway.
+	Source  string `json:"source"`
+	Profile string `json:"profile,omitempty"`
+	// Catalog is the catalog the definition comes from, or the profile's
+	// server was added from.
+	Catalog string `json:"catalog,omitempty"`
+	// Shadowed lists the catalogs whose definitions of the server were
+	// overridden, in the order they were read.
+	Shadowed []string `json:"shadowed,omitempty"`
+	Type     string   `json:"type"`
+	Image    string   `json:"image,omitempty"`
+	Endpoint string   `json:"endpoint,omitempty"`
+	Command  []string `json:"command,omitempty"`
+	// Overrides lists what the profile, the catalog or the gateway's flags
+	// change in the server's definition.
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// ResolveServer explains which definition of serverName the gateway uses
+// with its current configuration.
+func (g *Gateway) ResolveServer(serverName string) (ServerResolution, error) {
+	g.configurationMu.Lock()
+	configuration := g.configuration
+	g.configurationMu.Unlock()
+
+	resolution, err := configuration.resolveServer(serverName)
+	if err != nil {
+		return ServerResolution{}, err
+	}
+
+	if resolution.Endpoint != "" {
+		headers, err := parseRemoteHeaders(g.RemoteHeaders)
+		if err != nil {
+			return ServerResolution{}, err
+		}
+		spec := configuration.servers[resolution.Server]
+		for _, name := range slices.Sorted(maps.Keys(headers[resolution.Server])) {
+			if !hasHeader(spec.Remote.Headers, name) {
+				resolution.Overrides = append(resolution.Overrides, fmt.Sprintf("header %s (--remote-header)", name))
+			}
+		}
+	}
+
+	return resolution, nil
+}
+
+func (c *Configuration) resolveServer(serverName string) (ServerResolution, error) {
+	serverName = strings.TrimSpace(serverName)
+	server, ok := c.servers[serverName]
+	if !ok {
+		return ServerResolution{}, fmt.Errorf("server %s is not defined by the profile or any catalog", serverName)
+	}
+
+	enabled := slices.Contains(c.serverNames, serverName)
+	resolution := ServerResolution{
+		Server:   serverName,
+		Enabled:  enabled,
+		Catalog:  c.serverCatalogSources[serverName],
+		Shadowed: c.shadowedCatalogs[serverName],
+		Type:     server.Type,
+		Image:    server.Image,
+		Command:  server.Command,
+	}
+	switch {
+	case c.workingSet != "" && enabled:
+		resolution.Source = ServerSourceProfile
+		resolution.Profile = c.workingSet
+	case resolution.Catalog != "":
+		resolution.Source = ServerSourceCatalog
+	default:
+		resolution.Source = ServerSourceGateway
+	}
+
+	switch {
+	case server.Remote.URL != "":
+		resolution.Endpoint = server.Remote.URL
+	case server.SSEEndpoint != "":
+		resolution.Endpoint = server.SSEEndpoint
+	}
+
+	if longLived, ok := c.longLivedOverrides[serverName]; ok {
+		resolution.Overrides = append(resolution.Overrides, fmt.Sprintf("longLived=%t", longLived))
+	}
+
+	return resolution, nil
+}
+
+// printServerResolution prints the resolution of serverName as indented JSON.
+func (g *Gateway) printServerResolution(w io.Writer, serverName string) error {
+	resolution, err := g.ResolveServer(serverName)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(resolution, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(buf))
+	return err
+}
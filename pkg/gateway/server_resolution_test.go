@@ -0,0 +1,239 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/policy"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func writeCatalogFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(os.Getenv("HOME"), ".docker", "mcp", "catalogs", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestResolveServerAcrossOverlappingCatalogFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	first := writeCatalogFile(t, "first.yaml", `name: first
+registry:
+  github:
+    type: server
+    image: mcp/github:1.0
+  fetch:
+    type: server
+    image: mcp/fetch:latest
+`)
+	second := writeCatalogFile(t, "second.yaml", `name: second
+registry:
+  github:
+    type: server
+    image: acme/github:2.0
+`)
+
+	cfg := &FileBasedConfiguration{
+		ServerNames: []string{"github", "fetch"},
+		CatalogPath: []string{first, second},
+	}
+	configuration, err := cfg.readOnce(t.Context())
+	require.NoError(t, err)
+
+	resolution, err := configuration.resolveServer("github")
+	require.NoError(t, err)
+	assert.Equal(t, ServerResolution{
+		Server:   "github",
+		Enabled:  true,
+		Source:   ServerSourceCatalog,
+		Catalog:  "second",
+		Shadowed: []string{"first"},
+		Type:     "server",
+		Image:    "acme/github:2.0",
+	}, resolution)
+
+	resolution, err = configuration.resolveServer("fetch")
+	require.NoError(t, err)
+	assert.Equal(t, "first", resolution.Catalog)
+	assert.Empty(t, resolution.Shadowed)
+	assert.Equal(t, "mcp/fetch:latest", resolution.Image)
+
+	_, err = configuration.resolveServer("unknown")
+	require.ErrorContains(t, err, "server unknown is not defined")
+}
+
+func TestResolveServerAcrossCatalogsAndProfile(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	longLived := true
+	catalogServer := func(name, image string) db.CatalogServer {
+		return db.CatalogServer{
+			ServerType: string(workingset.ServerTypeImage),
+			Image:      image,
+			Snapshot:   &db.ServerSnapshot{Server: catalog.Server{Name: name, Type: "server", Image: image}},
+		}
+	}
+	require.NoError(t, dao.UpsertCatalog(t.Context(), db.Catalog{
+		Ref:   "acme/a-catalog:latest",
+		Title: "A",
+		Servers: []db.CatalogServer{
+			catalogServer("github", "mcp/github:1.0"),
+			catalogServer("notion", "mcp/notion:1.0"),
+		},
+	}))
+	bCatalogGitHub := catalogServer("github", "acme/github:2.0")
+	bCatalogGitHub.LongLivedOverride = &longLived
+	require.NoError(t, dao.UpsertCatalog(t.Context(), db.Catalog{
+		Ref:     "acme/b-catalog:latest",
+		Title:   "B",
+		Servers: []db.CatalogServer{bCatalogGitHub, catalogServer("fetch", "mcp/fetch:latest")},
+	}))
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev",
+		Name: "dev",
+		Servers: db.ServerList{{
+			Type:     string(workingset.ServerTypeImage),
+			Image:    "acme/notion:3.0",
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "notion", Type: "server", Image: "acme/notion:3.0"}},
+		}, {
+			Type:       string(workingset.ServerTypeImage),
+			Image:      "mcp/time:latest",
+			CatalogRef: "acme/c-catalog:latest",
+			Snapshot:   &db.ServerSnapshot{Server: catalog.Server{Name: "time", Type: "server", Image: "mcp/time:latest"}},
+		}},
+		Secrets: db.SecretMap{},
+	}))
+
+	cfg := NewWorkingSetConfiguration(Config{WorkingSet: "dev", Options: Options{DynamicTools: true}}, mocks.NewMockOCIService(), nil)
+	configuration, err := cfg.readOnce(t.Context(), dao)
+	require.NoError(t, err)
+
+	// The later catalog wins, with its overrides.
+	resolution, err := configuration.resolveServer("github")
+	require.NoError(t, err)
+	assert.False(t, resolution.Enabled)
+	assert.Equal(t, ServerSourceCatalog, resolution.Source)
+	assert.Equal(t, "acme/b-catalog:latest", resolution.Catalog)
+	assert.Equal(t, []string{"acme/a-catalog:latest"}, resolution.Shadowed)
+	assert.Equal(t, "acme/github:2.0", resolution.Image)
+	assert.Equal(t, []string{"longLived=true"}, resolution.Overrides)
+
+	// The profile wins over every catalog.
+	resolution, err = configuration.resolveServer("notion")
+	require.NoError(t, err)
+	assert.True(t, resolution.Enabled)
+	assert.Equal(t, ServerSourceProfile, resolution.Source)
+	assert.Equal(t, "dev", resolution.Profile)
+	assert.Empty(t, resolution.Catalog)
+	assert.Equal(t, []string{"acme/a-catalog:latest"}, resolution.Shadowed)
+	assert.Equal(t, "acme/notion:3.0", resolution.Image)
+
+	// Shadowing doesn't change what policies see.
+	assert.Equal(t, "acme/a-catalog:latest", configuration.policyRequest("notion", "", policy.ActionLoad).Catalog)
+
+	// The catalog a profile server was added from is its source, but it's
+	// not given to policies.
+	resolution, err = configuration.resolveServer("time")
+	require.NoError(t, err)
+	assert.Equal(t, ServerSourceProfile, resolution.Source)
+	assert.Equal(t, "acme/c-catalog:latest", resolution.Catalog)
+	assert.Empty(t, configuration.policyRequest("time", "", policy.ActionLoad).Catalog)
+}
+
+func TestResolveServerOfDerivedCatalog(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	catalogServer := func(name, image string) db.CatalogServer {
+		return db.CatalogServer{
+			ServerType: string(workingset.ServerTypeImage),
+			Image:      image,
+			Snapshot:   &db.ServerSnapshot{Server: catalog.Server{Name: name, Type: "server", Image: image}},
+		}
+	}
+	// The derived catalog is listed before its base.
+	require.NoError(t, dao.UpsertCatalog(t.Context(), db.Catalog{
+		Ref:     "acme/a-derived:latest",
+		Title:   "Derived",
+		Base:    "acme/z-base:latest",
+		Servers: []db.CatalogServer{catalogServer("github", "acme/github:2.0")},
+	}))
+	require.NoError(t, dao.UpsertCatalog(t.Context(), db.Catalog{
+		Ref:     "acme/z-base:latest",
+		Title:   "Base",
+		Servers: []db.CatalogServer{catalogServer("github", "mcp/github:1.0"), catalogServer("fetch", "mcp/fetch:latest")},
+	}))
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{ID: "dev", Name: "dev", Secrets: db.SecretMap{}}))
+
+	cfg := NewWorkingSetConfiguration(Config{WorkingSet: "dev", Options: Options{DynamicTools: true}}, mocks.NewMockOCIService(), nil)
+	configuration, err := cfg.readOnce(t.Context(), dao)
+	require.NoError(t, err)
+
+	// The derived catalog's override wins over its base.
+	resolution, err := configuration.resolveServer("github")
+	require.NoError(t, err)
+	assert.Equal(t, "acme/a-derived:latest", resolution.Catalog)
+	assert.Empty(t, resolution.Shadowed)
+	assert.Equal(t, "acme/github:2.0", resolution.Image)
+
+	// Inherited servers come with the derived catalog.
+	resolution, err = configuration.resolveServer("fetch")
+	require.NoError(t, err)
+	assert.Equal(t, "acme/a-derived:latest", resolution.Catalog)
+	assert.Empty(t, resolution.Shadowed)
+	assert.Equal(t, "mcp/fetch:latest", resolution.Image)
+}
+
+func TestPrintServerResolutionWithRemoteHeaders(t *testing.T) {
+	g := &Gateway{
+		Options: Options{RemoteHeaders: []string{
+			"notion:X-Tenant-ID=acme",
+			"notion:Authorization=ignored",
+			"other:X-Other=1",
+		}},
+		configuration: Configuration{
+			serverNames: []string{"notion"},
+			servers: map[string]catalog.Server{
+				"notion": {
+					Type:   "remote",
+					Remote: catalog.Remote{URL: "https://mcp.notion.com/mcp", Headers: map[string]string{"authorization": "Bearer ${TOKEN}"}},
+				},
+			},
+			serverCatalogSources: map[string]string{"notion": "mcp/docker-mcp-catalog:latest"},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, g.printServerResolution(&out, "notion"))
+
+	var resolution ServerResolution
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resolution))
+	assert.Equal(t, ServerResolution{
+		Server:    "notion",
+		Enabled:   true,
+		Source:    ServerSourceCatalog,
+		Catalog:   "mcp/docker-mcp-catalog:latest",
+		Type:      "remote",
+		Endpoint:  "https://mcp.notion.com/mcp",
+		Overrides: []string{"header X-Tenant-ID (--remote-header)"},
+	}, resolution)
+}
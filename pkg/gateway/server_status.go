@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Server states reported by the /servers endpoint.
+const (
+	ServerStateRunning = "running"
+	// ServerStateStopped is the state of servers that started fine but are
+	// not running right now, e.g. servers started for each tool call.
+	ServerStateStopped = "stopped"
+	// ServerStateFailed is the state of servers that could not be started.
+	ServerStateFailed = "failed"
+	// ServerStateUnavailable is the state of enabled servers that are not in
+	// any catalog, or that are crash looping and won't be restarted.
+	ServerStateUnavailable = "unavailable"
+	// ServerStateInactive is the state of the catalog servers that are not
+	// enabled, only listed with --list-inactive-servers. They can be enabled
+	// with mcp-add.
+	ServerStateInactive = "inactive"
+)
+
+// serverStatus is the status of one server, as reported by /servers. Type is
+// the type of the server in the catalog, e.g. server or remote. Description is
+// only reported for inactive servers, to help choosing one to enable.
+type serverStatus struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type,omitempty"`
+	Description string     `json:"description,omitempty"`
+	State       string     `json:"state"`
+	LastError   string     `json:"lastError,omitempty"`
+	Tools       int        `json:"tools"`
+	LastCall    *time.Time `json:"lastCall,omitempty"`
+}
+
+// recordServerError remembers the last error seen for a server, when
+// starting it or calling one of its tools.
+func (g *Gateway) recordServerError(serverName string, err error) {
+	g.serverActivityMu.Lock()
+	defer g.serverActivityMu.Unlock()
+
+	if g.serverLastErrors == nil {
+		g.serverLastErrors = make(map[string]string)
+	}
+	g.serverLastErrors[serverName] = err.Error()
+}
+
+func (g *Gateway) recordServerCall(serverName string, at time.Time) {
+	g.serverActivityMu.Lock()
+	defer g.serverActivityMu.Unlock()
+
+	if g.serverLastCalls == nil {
+		g.serverLastCalls = make(map[string]time.Time)
+	}
+	g.serverLastCalls[serverName] = at
+}
+
+// serverStatuses returns the status of every enabled server, in the order
+// they are configured, followed by the inactive catalog servers, sorted by
+// name, with --list-inactive-servers. Inactive servers are never started.
+func (g *Gateway) serverStatuses() []serverStatus {
+	running := map[string]bool{}
+	crashLooping := map[string]string{}
+	if g.clientPool != nil {
+		running = g.clientPool.startedServers()
+		crashLooping = g.clientPool.restarts.crashLooping()
+	}
+
+	g.capabilitiesMu.RLock()
+	tools := map[string]int{}
+	for _, registration := range g.toolRegistrations {
+		tools[registration.ServerName]++
+	}
+	g.capabilitiesMu.RUnlock()
+
+	g.failedServersMu.Lock()
+	failed := map[string]bool{}
+	for serverName := range g.failedServers {
+		failed[serverName] = true
+	}
+	g.failedServersMu.Unlock()
+
+	g.serverActivityMu.Lock()
+	defer g.serverActivityMu.Unlock()
+
+	statuses := []serverStatus{}
+	for _, serverName := range g.configuration.serverNames {
+		status := serverStatus{
+			Name:      serverName,
+			LastError: g.serverLastErrors[serverName],
+			Tools:     tools[serverName],
+		}
+		if lastCall, ok := g.serverLastCalls[serverName]; ok {
+			status.LastCall = &lastCall
+		}
+
+		if server, ok := g.configuration.servers[serverName]; ok {
+			status.Type = server.Type
+		}
+
+		_, _, found := g.configuration.Find(serverName)
+		switch {
+		case !found:
+			status.State = ServerStateUnavailable
+		case crashLooping[serverName] != "":
+			status.State = ServerStateUnavailable
+			status.LastError = crashLooping[serverName]
+		case failed[serverName]:
+			status.State = ServerStateFailed
+		case running[serverName]:
+			status.State = ServerStateRunning
+		default:
+			status.State = ServerStateStopped
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	if g.ListInactiveServers {
+		statuses = append(statuses, g.inactiveServerStatuses()...)
+	}
+	return statuses
+}
+
+// inactiveServerStatuses returns the status of the catalog servers that are
+// not enabled, sorted by name.
+func (g *Gateway) inactiveServerStatuses() []serverStatus {
+	var inactive []serverStatus
+	for serverName, server := range g.configuration.servers {
+		if slices.Contains(g.configuration.serverNames, serverName) {
+			continue
+		}
+		inactive = append(inactive, serverStatus{
+			Name:        serverName,
+			Type:        server.Type,
+			Description: server.Description,
+			State:       ServerStateInactive,
+		})
+	}
+	slices.SortFunc(inactive, func(a, b serverStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return inactive
+}
+
+// startedServers returns the names of the servers with a started client.
+func (cp *clientPool) startedServers() map[string]bool {
+	cp.clientLock.RLock()
+	defer cp.clientLock.RUnlock()
+
+	started := map[string]bool{}
+	for key, kc := range cp.keptClients {
+		if kc.Getter.started.Load() {
+			started[key.serverName] = true
+		}
+	}
+	return started
+}
+
+// serversHandler reports the status of each server as JSON, for dashboards.
+func (g *Gateway) serversHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g.serverStatuses())
+	}
+}
@@ -0,0 +1,126 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+)
+
+func TestServersEndpoint(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{})
+
+	// A remote server that drops every connection, and a server missing from the catalogs.
+	remoteConfig, _ := flakyRemoteServer(t, 100, dropConnection)
+	g.configuration.serverNames = append(g.configuration.serverNames, "remote", "missing")
+	g.configuration.servers["remote"] = remoteConfig.Spec
+	upstreamSpec := g.configuration.servers["upstream"]
+	upstreamSpec.Type = "server"
+	g.configuration.servers["upstream"] = upstreamSpec
+
+	caps, err := g.listCapabilities(desktop.WithNoDockerDesktop(t.Context()), g.configuration.serverNames, nil)
+	require.NoError(t, err)
+	g.toolRegistrations = map[string]ToolRegistration{}
+	for _, tool := range caps.Tools {
+		g.toolRegistrations[tool.Tool.Name] = tool
+	}
+	lastCall := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	g.recordServerCall("upstream", lastCall)
+
+	recorder := httptest.NewRecorder()
+	g.serversHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/servers", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var statuses []serverStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 3)
+
+	upstream := statuses[0]
+	assert.Equal(t, "upstream", upstream.Name)
+	assert.Equal(t, "server", upstream.Type)
+	assert.Equal(t, ServerStateRunning, upstream.State)
+	assert.Empty(t, upstream.LastError)
+	assert.Equal(t, 1, upstream.Tools)
+	require.NotNil(t, upstream.LastCall)
+	assert.True(t, lastCall.Equal(*upstream.LastCall))
+
+	remote := statuses[1]
+	assert.Equal(t, "remote", remote.Name)
+	assert.Equal(t, "remote", remote.Type)
+	assert.Equal(t, ServerStateFailed, remote.State)
+	assert.Contains(t, remote.LastError, "failed to connect")
+	assert.Zero(t, remote.Tools)
+	assert.Nil(t, remote.LastCall)
+
+	missing := statuses[2]
+	assert.Equal(t, "missing", missing.Name)
+	assert.Empty(t, missing.Type)
+	assert.Equal(t, ServerStateUnavailable, missing.State)
+	assert.Equal(t, "not found in any catalog", missing.LastError)
+}
+
+func TestServersEndpointStoppedServer(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{})
+
+	statuses := g.serverStatuses()
+
+	require.Len(t, statuses, 1)
+	assert.Equal(t, ServerStateStopped, statuses[0].State)
+}
+
+func TestServersEndpointInactiveServers(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{ListInactiveServers: true})
+	started := filepath.Join(t.TempDir(), "started")
+	for _, name := range []string{"zeta", "alpha"} {
+		g.configuration.servers[name] = catalog.Server{
+			Name:        name,
+			Type:        catalog.ServerTypeCommand,
+			Description: "The " + name + " server",
+			Command:     []string{"sh", "-c", "touch " + started},
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	g.serversHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/servers", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var statuses []serverStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 3)
+
+	assert.Equal(t, "upstream", statuses[0].Name)
+	assert.Equal(t, ServerStateStopped, statuses[0].State)
+	assert.Empty(t, statuses[0].Description)
+	assert.Equal(t, []serverStatus{
+		{Name: "alpha", Type: catalog.ServerTypeCommand, Description: "The alpha server", State: ServerStateInactive},
+		{Name: "zeta", Type: catalog.ServerTypeCommand, Description: "The zeta server", State: ServerStateInactive},
+	}, statuses[1:])
+
+	counts := g.status().Servers
+	assert.Equal(t, 1, counts.Enabled)
+	assert.Equal(t, 1, counts.Stopped)
+	assert.Equal(t, 2, counts.Inactive)
+
+	assert.NoFileExists(t, started, "inactive servers must not be started")
+	assert.Equal(t, map[string]bool{}, g.clientPool.startedServers())
+}
+
+func TestServersEndpointHidesInactiveServersByDefault(t *testing.T) {
+	g, _ := gatewayWithUpstream(t, Options{})
+	g.configuration.servers["other"] = catalog.Server{Name: "other", Image: "mcp/other"}
+
+	statuses := g.serverStatuses()
+
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "upstream", statuses[0].Name)
+	assert.Zero(t, g.status().Servers.Inactive)
+}
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// ServerToolRun is the outcome of RunServerTool: the tools listed by the
+// server and the result of the tool call.
+type ServerToolRun struct {
+	Tools  []string            `json:"tools"`
+	Result *mcp.CallToolResult `json:"result"`
+}
+
+// ServerToolReport is the JSON report of a server test, written with
+// --report so that CI can gate on it.
+type ServerToolReport struct {
+	// Passed is false when the server failed to start, list its tools or
+	// call the tool, or when the tool returned an error.
+	Passed bool                `json:"passed"`
+	Server string              `json:"server"`
+	Tool   string              `json:"tool"`
+	Tools  []string            `json:"tools,omitempty"`
+	Result *mcp.CallToolResult `json:"result,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// WriteServerToolReport writes the outcome of RunServerTool, its run or the
+// error it returned, to path as a JSON report.
+func WriteServerToolReport(path string, serverName, toolName string, run *ServerToolRun, runErr error) error {
+	report := ServerToolReport{
+		Server: serverName,
+		Tool:   toolName,
+	}
+	switch {
+	case runErr != nil:
+		report.Error = runErr.Error()
+	case run.Result.IsError:
+		report.Tools = run.Tools
+		report.Result = run.Result
+		report.Error = fmt.Sprintf("tool %s returned an error", toolName)
+	default:
+		report.Passed = true
+		report.Tools = run.Tools
+		report.Result = run.Result
+	}
+	return writeReport(path, report)
+}
+
+// NewServerConfig returns the config of a catalog server, with its config
+// values and se:// URIs for its secrets, as the gateway would start it.
+func NewServerConfig(ctx context.Context, server catalog.Server, config map[string]any) *catalog.ServerConfig {
+	serverConfig := &catalog.ServerConfig{
+		Name: server.Name,
+		Spec: server,
+		Config: map[string]any{
+			oci.CanonicalizeServerName(server.Name): config,
+		},
+	}
+	if secrets := server.StoredSecrets(); len(secrets) > 0 {
+		serverConfig.Secrets = BuildSecretsURIs(ctx, []ServerSecretConfig{{
+			Secrets: secrets,
+			OAuth:   server.OAuth,
+		}})
+	}
+	return serverConfig
+}
+
+// RunServerTool starts a single server, the way the gateway starts it, lists
+// its tools, calls one of them with arguments and stops the server, e.g. to
+// check that a catalog server works in isolation. Its image is pulled and
+// verified first, as when the gateway starts. The server is stopped even if
+// listing its tools or calling the tool fails.
+func RunServerTool(ctx context.Context, docker docker.Client, options Options, serverConfig *catalog.ServerConfig, toolName string, arguments map[string]any) (*ServerToolRun, error) {
+	g := &Gateway{
+		Options: options,
+		docker:  docker,
+	}
+
+	// Containers are run with the server's pull policy, --pull never by
+	// default, so the image must be there beforehand.
+	if serverConfig.Spec.Image != "" {
+		if err := g.pullAndVerify(ctx, Configuration{
+			serverNames: []string{serverConfig.Name},
+			servers:     map[string]catalog.Server{serverConfig.Name: serverConfig.Spec},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	g.clientPool = newClientPool(g.Options, docker, g)
+	defer g.clientPool.Close()
+
+	client, err := g.clientPool.AcquireClient(ctx, serverConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting server %s: %w", serverConfig.Name, err)
+	}
+	defer g.clientPool.ReleaseClient(client)
+
+	tools, err := listAllTools(ctx, client.Session())
+	if err != nil {
+		return nil, fmt.Errorf("listing the tools of server %s: %w", serverConfig.Name, err)
+	}
+	run := &ServerToolRun{Tools: []string{}}
+	found := false
+	for _, tool := range tools {
+		run.Tools = append(run.Tools, tool.Name)
+		found = found || tool.Name == toolName
+	}
+	if !found {
+		return nil, fmt.Errorf("server %s has no tool %s, its tools are: %s", serverConfig.Name, toolName, strings.Join(run.Tools, ", "))
+	}
+
+	run.Result, err = client.Session().CallTool(ctx, &mcp.CallToolParams{
+		Name:      toolName,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling tool %s: %w", toolName, err)
+	}
+	return run, nil
+}
@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// noopRemoteServer serves a mock MCP server with a no-op tool that echoes its
+// arguments, and counts the sessions closed by the client.
+func noopRemoteServer(t *testing.T) (*catalog.ServerConfig, *atomic.Int32) {
+	t.Helper()
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mock", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "noop", InputSchema: &jsonschema.Schema{Type: "object"}}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "noop called with " + string(req.Params.Arguments)}}}, nil
+	})
+	server.AddTool(&mcp.Tool{Name: "other", InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	var closed atomic.Int32
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			closed.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(remote.Close)
+
+	return NewServerConfig(t.Context(), catalog.Server{
+		Name:   "mock",
+		Type:   "remote",
+		Remote: catalog.Remote{URL: remote.URL, Transport: "streamable-http"},
+	}, nil), &closed
+}
+
+func TestRunServerTool(t *testing.T) {
+	serverConfig, closed := noopRemoteServer(t)
+
+	run, err := RunServerTool(desktop.WithNoDockerDesktop(t.Context()), nil, Options{}, serverConfig, "noop", map[string]any{"key": "value"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"noop", "other"}, run.Tools)
+	require.Len(t, run.Result.Content, 1)
+	assert.Equal(t, `noop called with {"key":"value"}`, run.Result.Content[0].(*mcp.TextContent).Text)
+	assert.False(t, run.Result.IsError)
+	assert.Equal(t, int32(1), closed.Load(), "the server session must be closed")
+}
+
+func TestRunServerToolUnknownTool(t *testing.T) {
+	serverConfig, closed := noopRemoteServer(t)
+
+	_, err := RunServerTool(desktop.WithNoDockerDesktop(t.Context()), nil, Options{}, serverConfig, "missing", nil)
+	require.EqualError(t, err, "server mock has no tool missing, its tools are: noop, other")
+	assert.Equal(t, int32(1), closed.Load(), "the server session must be closed")
+}
+
+func TestRunServerToolStartFailure(t *testing.T) {
+	serverConfig := NewServerConfig(t.Context(), catalog.Server{
+		Name:    "dev",
+		Type:    catalog.ServerTypeCommand,
+		Command: []string{"sh", "-c", "exit 1"},
+	}, nil)
+
+	_, err := RunServerTool(t.Context(), nil, Options{}, serverConfig, "noop", nil)
+	require.ErrorContains(t, err, "starting server dev")
+}
+
+func TestRunServerToolPullsImageBeforeStarting(t *testing.T) {
+	docker := &recordingDockerClient{
+		pullImages: func(context.Context, ...string) error {
+			return errors.New("pull access denied")
+		},
+	}
+	serverConfig := NewServerConfig(t.Context(), catalog.Server{Name: "custom", Image: "ghcr.io/acme/server:latest"}, nil)
+
+	_, err := RunServerTool(t.Context(), docker, Options{}, serverConfig, "noop", nil)
+	require.EqualError(t, err, "pulling docker images: pull access denied")
+}
+
+func TestRunServerToolVerifiesImage(t *testing.T) {
+	docker := &recordingDockerClient{}
+	serverConfig := NewServerConfig(t.Context(), catalog.Server{Name: "time", Image: "mcp/time:latest"}, nil)
+
+	_, err := RunServerTool(t.Context(), docker, Options{VerifySignatures: true}, serverConfig, "noop", nil)
+	require.ErrorContains(t, err, "image must be referenced by digest")
+	assert.Empty(t, docker.pulledImages)
+}
+
+func TestNewServerConfigWithConfig(t *testing.T) {
+	serverConfig := NewServerConfig(t.Context(), catalog.Server{Name: "my-fs"}, map[string]any{"paths": "/tmp"})
+
+	assert.Equal(t, map[string]any{"my-fs": map[string]any{"paths": "/tmp"}}, serverConfig.Config)
+}
+
+func TestWriteServerToolReport(t *testing.T) {
+	serverConfig, _ := noopRemoteServer(t)
+	ctx := desktop.WithNoDockerDesktop(t.Context())
+
+	readReport := func(t *testing.T, path string) ServerToolReport {
+		t.Helper()
+		buf, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var report ServerToolReport
+		require.NoError(t, json.Unmarshal(buf, &report))
+		return report
+	}
+
+	t.Run("passed", func(t *testing.T) {
+		run, err := RunServerTool(ctx, nil, Options{}, serverConfig, "noop", nil)
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "report.json")
+		require.NoError(t, WriteServerToolReport(path, "mock", "noop", run, err))
+
+		report := readReport(t, path)
+		assert.True(t, report.Passed)
+		assert.Equal(t, "mock", report.Server)
+		assert.Equal(t, "noop", report.Tool)
+		assert.Equal(t, []string{"noop", "other"}, report.Tools)
+		require.NotNil(t, report.Result)
+		assert.Empty(t, report.Error)
+	})
+
+	t.Run("failed to run", func(t *testing.T) {
+		run, err := RunServerTool(ctx, nil, Options{}, serverConfig, "missing", nil)
+		require.Error(t, err)
+
+		path := filepath.Join(t.TempDir(), "report.json")
+		require.NoError(t, WriteServerToolReport(path, "mock", "missing", run, err))
+
+		report := readReport(t, path)
+		assert.False(t, report.Passed)
+		assert.Equal(t, "server mock has no tool missing, its tools are: noop, other", report.Error)
+		assert.Nil(t, report.Result)
+	})
+
+	t.Run("tool error", func(t *testing.T) {
+		run := &ServerToolRun{
+			Tools:  []string{"noop"},
+			Result: &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "boom"}}},
+		}
+
+		path := filepath.Join(t.TempDir(), "report.json")
+		require.NoError(t, WriteServerToolReport(path, "mock", "noop", run, nil))
+
+		report := readReport(t, path)
+		assert.False(t, report.Passed)
+		assert.Equal(t, "tool noop returned an error", report.Error)
+		require.NotNil(t, report.Result)
+		assert.True(t, report.Result.IsError)
+	})
+}
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// snapshotWarmup resolves the snapshots of the profile servers in the
+// background, so that the first tool calls don't wait for them, and reloads
+// the gateway configuration when at least one of them was resolved.
+type snapshotWarmup struct {
+	resolve func(ctx context.Context) ([]string, error)
+	reload  func(ctx context.Context) error
+}
+
+func (w *snapshotWarmup) run(ctx context.Context) {
+	resolved, err := w.resolve(ctx)
+	if err != nil {
+		log.Logf("! Snapshot warmup failed: %v", err)
+	}
+	if len(resolved) == 0 {
+		return
+	}
+
+	log.Logf("> Snapshots resolved: %s", strings.Join(resolved, ", "))
+	if err := w.reload(ctx); err != nil {
+		log.Logf("! Unable to reload configuration after snapshot warmup: %v", err)
+	}
+}
+
+// resolveWorkingSetSnapshots resolves the snapshots of the servers of a
+// profile that don't have one yet and saves them to the profile. Servers whose
+// snapshot can't be resolved are logged and skipped. It returns the servers
+// that were resolved.
+//
+// The snapshots are resolved outside of any transaction, then saved onto the
+// servers of the profile as it is at write time, so that edits made to the
+// profile in the meantime are kept.
+func resolveWorkingSetSnapshots(ctx context.Context, dao db.DAO, ociService oci.Service, id string) ([]string, error) {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	workingSet := workingset.NewFromDb(dbWorkingSet)
+
+	var resolved []string
+	snapshots := map[string]*db.ServerSnapshot{}
+	for i, server := range workingSet.Servers {
+		if server.Snapshot != nil {
+			continue
+		}
+
+		snapshot, err := workingset.ResolveSnapshot(ctx, ociService, server)
+		if err != nil {
+			log.Logf("  - Can't resolve the snapshot of %s: %v", server.BasicName(), err)
+			continue
+		}
+		if snapshot == nil {
+			continue
+		}
+		key, err := snapshotKey(dbWorkingSet.Servers[i])
+		if err != nil {
+			return nil, err
+		}
+		snapshots[key] = &db.ServerSnapshot{Server: snapshot.Server}
+		server.Snapshot = snapshot
+		resolved = append(resolved, server.BasicName())
+	}
+	if len(resolved) == 0 {
+		return nil, nil
+	}
+
+	var keyErr error
+	err = dao.UpdateWorkingSetServers(ctx, id, func(servers db.ServerList) db.ServerList {
+		for i := range servers {
+			if servers[i].Snapshot != nil {
+				continue
+			}
+			key, err := snapshotKey(servers[i])
+			if err != nil {
+				keyErr = err
+				continue
+			}
+			if snapshot, found := snapshots[key]; found {
+				servers[i].Snapshot = snapshot
+			}
+		}
+		return servers
+	})
+	if err == nil {
+		err = keyErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshots: %w", err)
+	}
+	return resolved, nil
+}
+
+// snapshotKey identifies a profile server by everything but its snapshot, so
+// that a resolved snapshot is only saved onto the server it was resolved for.
+func snapshotKey(server db.Server) (string, error) {
+	server.Snapshot = nil
+	b, err := json.Marshal(server)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// withSnapshots returns the servers that have a snapshot.
+func withSnapshots(servers []workingset.Server) []workingset.Server {
+	var kept []workingset.Server
+	for _, server := range servers {
+		if server.Snapshot == nil {
+			log.Logf("  - Resolving the snapshot of %s in the background", server.BasicName())
+			continue
+		}
+		kept = append(kept, server)
+	}
+	return kept
+}
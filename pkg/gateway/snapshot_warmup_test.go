@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func TestSnapshotWarmupResolvesSnapshotsInBackground(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "test",
+		Name: "test",
+		Servers: db.ServerList{
+			{Type: string(workingset.ServerTypeImage), Image: "myimage:latest"},
+			{Type: string(workingset.ServerTypeImage), Image: "missing:latest"},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	ociService := mocks.NewMockOCIService(mocks.WithLocalImages([]mocks.MockImage{
+		{
+			Ref: "myimage:latest",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: my-image\ntype: server\nimage: myimage:latest",
+			},
+			DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		},
+	}))
+	cfg := NewWorkingSetConfiguration(Config{WorkingSet: "test", Options: Options{SnapshotWarmup: true}}, ociService, nil)
+
+	// Servers without a snapshot don't block reading the configuration.
+	configuration, err := cfg.readOnce(t.Context(), dao)
+	require.NoError(t, err)
+	assert.Empty(t, configuration.serverNames)
+
+	reloaded := make(chan Configuration, 1)
+	warmup := &snapshotWarmup{
+		resolve: func(ctx context.Context) ([]string, error) {
+			return resolveWorkingSetSnapshots(ctx, dao, ociService, "test")
+		},
+		reload: func(ctx context.Context) error {
+			configuration, err := cfg.readOnce(ctx, dao)
+			if err != nil {
+				return err
+			}
+			reloaded <- configuration
+			return nil
+		},
+	}
+	go warmup.run(t.Context())
+
+	configuration = <-reloaded
+	assert.Equal(t, []string{"my-image"}, configuration.serverNames)
+
+	// The resolved snapshot is saved, the one that failed is left unresolved.
+	dbWorkingSet, err := dao.GetWorkingSet(t.Context(), "test")
+	require.NoError(t, err)
+	require.NotNil(t, dbWorkingSet.Servers[0].Snapshot)
+	assert.Equal(t, "my-image", dbWorkingSet.Servers[0].Snapshot.Server.Name)
+	assert.Nil(t, dbWorkingSet.Servers[1].Snapshot)
+}
+
+func TestSnapshotWarmupSkipsReloadWhenNothingResolved(t *testing.T) {
+	var reloads int
+	warmup := &snapshotWarmup{
+		resolve: func(context.Context) ([]string, error) {
+			return nil, errors.New("profile not found")
+		},
+		reload: func(context.Context) error {
+			reloads++
+			return nil
+		},
+	}
+
+	warmup.run(t.Context())
+
+	assert.Zero(t, reloads)
+}
+
+// editingDAO edits the profile right before the snapshots are saved, like a
+// concurrent `profile server add` would.
+type editingDAO struct {
+	db.DAO
+	edit func(ctx context.Context) error
+}
+
+func (d *editingDAO) UpdateWorkingSetServers(ctx context.Context, id string, update func(servers db.ServerList) db.ServerList) error {
+	if err := d.edit(ctx); err != nil {
+		return err
+	}
+	return d.DAO.UpdateWorkingSetServers(ctx, id, update)
+}
+
+func TestResolveWorkingSetSnapshotsKeepsConcurrentEdits(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dao.Close())
+	})
+
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "test",
+		Name: "test",
+		Servers: db.ServerList{
+			{Type: string(workingset.ServerTypeImage), Image: "myimage:latest"},
+			{Type: string(workingset.ServerTypeImage), Image: "removed:latest"},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	ociService := mocks.NewMockOCIService(mocks.WithLocalImages([]mocks.MockImage{
+		{
+			Ref: "myimage:latest",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: my-image\ntype: server\nimage: myimage:latest",
+			},
+			DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		},
+		{
+			Ref: "removed:latest",
+			Labels: map[string]string{
+				"io.docker.server.metadata": "name: removed\ntype: server\nimage: removed:latest",
+			},
+			DigestString: "sha256:abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		},
+	}))
+
+	editing := &editingDAO{
+		DAO: dao,
+		edit: func(ctx context.Context) error {
+			return dao.UpdateWorkingSet(ctx, db.WorkingSet{
+				ID:   "test",
+				Name: "renamed",
+				Servers: db.ServerList{
+					{Type: string(workingset.ServerTypeImage), Image: "added:latest"},
+					{Type: string(workingset.ServerTypeImage), Image: "myimage:latest"},
+				},
+				Secrets: db.SecretMap{"default": {Provider: "docker-desktop-store"}},
+			})
+		},
+	}
+
+	resolved, err := resolveWorkingSetSnapshots(t.Context(), editing, ociService, "test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"myimage:latest", "removed:latest"}, resolved)
+
+	// The edit is kept and the snapshot lands on the server it was resolved
+	// for, the removed server isn't brought back.
+	dbWorkingSet, err := dao.GetWorkingSet(t.Context(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", dbWorkingSet.Name)
+	assert.Contains(t, dbWorkingSet.Secrets, "default")
+	require.Len(t, dbWorkingSet.Servers, 2)
+	assert.Equal(t, "added:latest", dbWorkingSet.Servers[0].Image)
+	assert.Nil(t, dbWorkingSet.Servers[0].Snapshot)
+	assert.Equal(t, "myimage:latest", dbWorkingSet.Servers[1].Image)
+	require.NotNil(t, dbWorkingSet.Servers[1].Snapshot)
+	assert.Equal(t, "my-image", dbWorkingSet.Servers[1].Snapshot.Server.Name)
+}
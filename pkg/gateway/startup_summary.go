@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// startupSummary is the machine-readable summary printed with --startup-summary.
+type startupSummary struct {
+	Transport string                `json:"transport"`
+	Port      int                   `json:"port,omitempty"`
+	Profile   string                `json:"profile,omitempty"`
+	Catalogs  []string              `json:"catalogs"`
+	Servers   startupSummaryServers `json:"servers"`
+	Tools     int                   `json:"tools"`
+}
+
+type startupSummaryServers struct {
+	Configured int `json:"configured"`
+	Started    int `json:"started"`
+	Failed     int `json:"failed"`
+}
+
+func (g *Gateway) setServerFailed(serverName string, failed bool) {
+	g.failedServersMu.Lock()
+	defer g.failedServersMu.Unlock()
+
+	if !failed {
+		delete(g.failedServers, serverName)
+		return
+	}
+	if g.failedServers == nil {
+		g.failedServers = make(map[string]bool)
+	}
+	g.failedServers[serverName] = true
+}
+
+func (g *Gateway) startupSummary() startupSummary {
+	transport := g.Transport
+	if transport == "" {
+		transport = "stdio"
+	}
+
+	// Only the catalogs the enabled servers come from.
+	catalogs := []string{}
+	g.configurationMu.Lock()
+	serverNames := slices.Clone(g.configuration.serverNames)
+	for _, serverName := range serverNames {
+		catalogRef := g.configuration.serverCatalogSources[serverName]
+		if catalogRef != "" && !slices.Contains(catalogs, catalogRef) {
+			catalogs = append(catalogs, catalogRef)
+		}
+	}
+	profile := g.configuration.workingSet
+	g.configurationMu.Unlock()
+	slices.Sort(catalogs)
+
+	var servers startupSummaryServers
+	g.failedServersMu.Lock()
+	for _, serverName := range serverNames {
+		servers.Configured++
+		if g.failedServers[serverName] {
+			servers.Failed++
+		} else {
+			servers.Started++
+		}
+	}
+	g.failedServersMu.Unlock()
+
+	g.capabilitiesMu.RLock()
+	tools := len(g.toolRegistrations)
+	g.capabilitiesMu.RUnlock()
+
+	return startupSummary{
+		Transport: transport,
+		Port:      g.Port,
+		Profile:   profile,
+		Catalogs:  catalogs,
+		Servers:   servers,
+		Tools:     tools,
+	}
+}
+
+func writeStartupSummary(w io.Writer, summary startupSummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupSummary(t *testing.T) {
+	g := &Gateway{
+		Options: Options{Transport: "streaming", Port: 8811},
+		configuration: Configuration{
+			serverNames: []string{"github", "postgres", "slack"},
+			serverCatalogSources: map[string]string{
+				"github":   "docker.io/mcp/docker-mcp-catalog:latest",
+				"postgres": "docker.io/mcp/docker-mcp-catalog:latest",
+				"slack":    "docker.io/team/catalog:latest",
+				// Not enabled.
+				"notion": "docker.io/other/catalog:latest",
+			},
+			workingSet: "my-profile",
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"create_issue": {ServerName: "github"},
+			"query":        {ServerName: "postgres"},
+			"mcp-find":     {},
+		},
+	}
+	g.setServerFailed("slack", true)
+	g.setServerFailed("postgres", true)
+	g.setServerFailed("postgres", false)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStartupSummary(&buf, g.startupSummary()))
+
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")), "summary must be a single line")
+
+	var summary map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+	assert.Equal(t, "streaming", summary["transport"])
+	assert.InDelta(t, 8811, summary["port"], 0)
+	assert.Equal(t, "my-profile", summary["profile"])
+	assert.Equal(t, []any{"docker.io/mcp/docker-mcp-catalog:latest", "docker.io/team/catalog:latest"}, summary["catalogs"])
+	assert.Equal(t, map[string]any{"configured": 3.0, "started": 2.0, "failed": 1.0}, summary["servers"])
+	assert.InDelta(t, 3, summary["tools"], 0)
+}
+
+func TestStartupSummaryDefaults(t *testing.T) {
+	g := &Gateway{}
+
+	summary := g.startupSummary()
+
+	assert.Equal(t, "stdio", summary.Transport)
+	assert.Empty(t, summary.Catalogs)
+	assert.Equal(t, startupSummaryServers{}, summary.Servers)
+	assert.Zero(t, summary.Tools)
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Types of tool errors, recorded as the error.type attribute of the tool
+// error telemetry.
+const (
+	toolErrorTimeout     = "timeout"
+	toolErrorServerCrash = "server-crash"
+	toolErrorInvalidArgs = "invalid-args"
+	toolErrorSizeLimit   = "size-limit"
+	toolErrorNotFound    = "not-found"
+	toolErrorServerError = "server-error"
+)
+
+// classifyToolError returns the type of a failed tool call. Errors that don't
+// match a more specific type are server errors.
+func classifyToolError(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return toolErrorTimeout
+	case errors.Is(err, bufio.ErrTooLong),
+		strings.Contains(err.Error(), "too large"), strings.Contains(err.Error(), "too long"):
+		return toolErrorSizeLimit
+	}
+
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		switch {
+		case rpcErr.Code == jsonrpc.CodeMethodNotFound,
+			rpcErr.Code == jsonrpc.CodeInvalidParams && strings.HasPrefix(rpcErr.Message, "unknown tool"):
+			return toolErrorNotFound
+		case rpcErr.Code == jsonrpc.CodeInvalidParams:
+			return toolErrorInvalidArgs
+		}
+		return toolErrorServerError
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return toolErrorInvalidArgs
+	case errors.Is(err, mcp.ErrConnectionClosed), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &exitErr):
+		return toolErrorServerCrash
+	}
+
+	return toolErrorServerError
+}
@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestClassifyToolError(t *testing.T) {
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"deadline", fmt.Errorf("calling tool: %w", context.DeadlineExceeded), toolErrorTimeout},
+		{"io deadline", fmt.Errorf("read: %w", os.ErrDeadlineExceeded), toolErrorTimeout},
+		{"connection closed", fmt.Errorf("%w: calling \"tools/call\": EOF", mcp.ErrConnectionClosed), toolErrorServerCrash},
+		{"unexpected EOF", fmt.Errorf("reading response: %w", io.ErrUnexpectedEOF), toolErrorServerCrash},
+		{"invalid params", &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "n must be an integer"}, toolErrorInvalidArgs},
+		{"invalid json arguments", fmt.Errorf("failed to unmarshal arguments: %w", syntaxErr), toolErrorInvalidArgs},
+		{"unknown tool", &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: `unknown tool "search"`}, toolErrorNotFound},
+		{"method not found", &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found"}, toolErrorNotFound},
+		{"message too large", errors.New("413 request entity too large"), toolErrorSizeLimit},
+		{"internal error", &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: "database is down"}, toolErrorServerError},
+		{"other", errors.New("database is down"), toolErrorServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, classifyToolError(tc.err))
+		})
+	}
+}
+
+// flakyServer starts an in-memory MCP server whose tools fail in different
+// ways.
+func flakyServer(t *testing.T) *sessionClient {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "flaky", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "slow", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	server.AddTool(&mcp.Tool{Name: "strict", InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "n must be an integer"}
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return &sessionClient{session: clientSession}
+}
+
+func TestToolErrorTypes(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+
+	g := &Gateway{
+		Options: Options{ToolTimeout: 50 * time.Millisecond},
+		configuration: Configuration{
+			serverNames: []string{"flaky"},
+			servers: map[string]catalog.Server{
+				"flaky": {Name: "flaky", Image: "mcp/flaky"},
+			},
+		},
+		clientPool: &clientPool{keptClients: map[clientKey]keptClient{}},
+	}
+	getter := &clientGetter{client: flakyServer(t)}
+	getter.once.Do(func() {}) // mark as created
+	// Listing the tools doesn't happen in a session.
+	g.clientPool.keptClients[clientKey{serverName: "flaky"}] = keptClient{Name: "flaky", Getter: getter}
+	g.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	g.mcpServer.AddReceivingMiddleware(g.toolCallLimitsMiddleware())
+	g.serverAvailableCapabilities = make(map[string]*Capabilities)
+	require.NoError(t, g.reloadConfiguration(t.Context(), g.configuration, nil, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	// Tool calls look up the client kept for the session they come from.
+	g.clientPool.keptClients[clientKey{serverName: "flaky", session: serverSession}] = keptClient{Name: "flaky", Getter: getter}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	_, err = session.CallTool(t.Context(), &mcp.CallToolParams{Name: "slow"})
+	require.Error(t, err)
+	_, err = session.CallTool(t.Context(), &mcp.CallToolParams{Name: "strict", Arguments: map[string]any{"n": "one"}})
+	require.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metricReader.Collect(t.Context(), &rm))
+
+	errorTypes := map[string]string{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "mcp.tool.errors" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				toolName, _ := dp.Attributes.Value(attribute.Key("mcp.tool.name"))
+				errorType, _ := dp.Attributes.Value(attribute.Key("error.type"))
+				errorTypes[toolName.AsString()] = errorType.AsString()
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"slow":   toolErrorTimeout,
+		"strict": toolErrorInvalidArgs,
+	}, errorTypes)
+}
@@ -43,16 +43,19 @@ func isCapabilityNameCollision(err error) bool {
 }
 
 var reservedGatewayToolNames = map[string]struct{}{
-	"code-mode":            {},
-	"find-tools":           {},
-	"mcp-activate-profile": {},
-	"mcp-add":              {},
-	"mcp-config-set":       {},
-	"mcp-create-profile":   {},
-	"mcp-exec":             {},
-	"mcp-find":             {},
-	"mcp-registry-import":  {},
-	"mcp-remove":           {},
+	"code-mode":             {},
+	"find-tools":            {},
+	"gateway__list-servers": {},
+	"gateway__list-tools":   {},
+	"gateway__reload":       {},
+	"mcp-activate-profile":  {},
+	"mcp-add":               {},
+	"mcp-config-set":        {},
+	"mcp-create-profile":    {},
+	"mcp-exec":              {},
+	"mcp-find":              {},
+	"mcp-registry-import":   {},
+	"mcp-remove":            {},
 }
 
 var reservedGatewayPromptNames = map[string]struct{}{
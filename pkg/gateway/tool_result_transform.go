@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mikefarah/yq/v4/pkg/yqlib"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/yq"
+)
+
+// parseToolResultTransforms parses transforms of the form
+// <server>:<tool>=<expression>, indexed by server then tool name. The
+// expressions are checked, so that a typo fails when the gateway starts
+// rather than on the first call.
+func parseToolResultTransforms(values []string) (map[string]map[string]string, error) {
+	transforms := make(map[string]map[string]string)
+	for _, value := range values {
+		serverTool, expression, _ := strings.Cut(value, "=")
+		server, tool, ok := strings.Cut(serverTool, ":")
+		server = strings.TrimSpace(server)
+		tool = strings.TrimSpace(tool)
+		expression = strings.TrimSpace(expression)
+		if !ok || server == "" || tool == "" || expression == "" {
+			return nil, fmt.Errorf("invalid tool result transform %q: expected <server>:<tool>=<expression>", value)
+		}
+		if err := yq.Validate(expression); err != nil {
+			return nil, fmt.Errorf("invalid tool result transform %q: %w", value, err)
+		}
+		if transforms[server] == nil {
+			transforms[server] = make(map[string]string)
+		}
+		transforms[server][tool] = expression
+	}
+	return transforms, nil
+}
+
+// validateToolResultTransforms checks the expressions of the tool result
+// transforms of a server set in the profile.
+func validateToolResultTransforms(serverName string, transforms map[string]string) error {
+	for _, tool := range slices.Sorted(maps.Keys(transforms)) {
+		if err := yq.Validate(transforms[tool]); err != nil {
+			return fmt.Errorf("invalid result transform for tool %s of server %s: %w", tool, serverName, err)
+		}
+	}
+	return nil
+}
+
+// toolResultTransform returns the expression applied to the results of a
+// server's tool, if any: the one of --tool-result-transform, or else the one
+// of the profile.
+func (g *Gateway) toolResultTransform(serverName, toolName string) (string, bool) {
+	if expression, ok := g.toolResultTransforms[serverName][toolName]; ok {
+		return expression, true
+	}
+	expression, ok := g.configuration.toolResultTransforms[serverName][toolName]
+	return expression, ok
+}
+
+// transformToolResult applies a YQ expression to the structured content of a
+// tool result. The text content is replaced with the transformed value, as
+// the serialized form of the structured content. Values that aren't objects
+// are only returned as text, since structured content must be an object.
+// Error results and results without structured content are left intact.
+func transformToolResult(result *mcp.CallToolResult, expression string) (*mcp.CallToolResult, error) {
+	if result == nil || result.IsError || result.StructuredContent == nil {
+		return result, nil
+	}
+
+	content, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	transformed, err := yq.Evaluate(expression, content, yqlib.NewJSONDecoder(), yq.NewJSONEncoder())
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(transformed, &value); err != nil {
+		return nil, fmt.Errorf("transform must produce a single JSON value: %w", err)
+	}
+
+	transformedResult := *result
+	transformedResult.Content = []mcp.Content{&mcp.TextContent{Text: string(transformed)}}
+	transformedResult.StructuredContent = nil
+	if object, ok := value.(map[string]any); ok {
+		transformedResult.StructuredContent = object
+	}
+	return &transformedResult, nil
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolResultTransforms(t *testing.T) {
+	transforms, err := parseToolResultTransforms([]string{
+		"github:search_issues=.items | map({\"title\": .title})",
+		" github : get_me = .login ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"github": {
+			"search_issues": ".items | map({\"title\": .title})",
+			"get_me":        ".login",
+		},
+	}, transforms)
+
+	for _, value := range []string{"github", "github:search_issues", ":tool=.a", "github:=.a", "github:search_issues="} {
+		_, err := parseToolResultTransforms([]string{value})
+		require.ErrorContains(t, err, "expected <server>:<tool>=<expression>", value)
+	}
+
+	_, err = parseToolResultTransforms([]string{"github:search_issues=.items | map("})
+	require.ErrorContains(t, err, "invalid YQ expression")
+}
+
+func TestTransformToolResultProjectsStructuredContent(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: `{"total_count":2,"items":[...]}`}},
+		StructuredContent: map[string]any{
+			"total_count": 2,
+			"items": []any{
+				map[string]any{"number": 1, "title": "Crash on start", "body": "Long description", "user": map[string]any{"login": "alice"}},
+				map[string]any{"number": 2, "title": "Typo in docs", "body": "Another long description", "user": map[string]any{"login": "bob"}},
+			},
+		},
+	}
+
+	transformed, err := transformToolResult(result, `{"issues": .items | map({"number": .number, "title": .title})}`)
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"issues": []any{
+			map[string]any{"number": float64(1), "title": "Crash on start"},
+			map[string]any{"number": float64(2), "title": "Typo in docs"},
+		},
+	}
+	assert.Equal(t, expected, transformed.StructuredContent)
+	require.Len(t, transformed.Content, 1)
+	assert.JSONEq(t, `{"issues":[{"number":1,"title":"Crash on start"},{"number":2,"title":"Typo in docs"}]}`, transformed.Content[0].(*mcp.TextContent).Text)
+
+	// The original result is left untouched.
+	assert.Contains(t, result.StructuredContent, "total_count")
+}
+
+func TestTransformToolResultWithNonObjectValue(t *testing.T) {
+	result := &mcp.CallToolResult{
+		StructuredContent: map[string]any{"items": []any{map[string]any{"title": "a"}, map[string]any{"title": "b"}}},
+	}
+
+	transformed, err := transformToolResult(result, `.items | map(.title)`)
+	require.NoError(t, err)
+	assert.Nil(t, transformed.StructuredContent)
+	require.Len(t, transformed.Content, 1)
+	assert.JSONEq(t, `["a","b"]`, transformed.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestTransformToolResultLeavesOtherResultsIntact(t *testing.T) {
+	for _, result := range []*mcp.CallToolResult{
+		{Content: []mcp.Content{&mcp.TextContent{Text: "plain text"}}},
+		{IsError: true, StructuredContent: map[string]any{"error": "boom"}},
+	} {
+		transformed, err := transformToolResult(result, ".items")
+		require.NoError(t, err)
+		assert.Same(t, result, transformed)
+	}
+}
+
+func TestToolResultTransformFromProfile(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			toolResultTransforms: map[string]map[string]string{
+				"github": {"search_issues": ".items", "get_me": ".login"},
+			},
+		},
+		toolResultTransforms: map[string]map[string]string{
+			"github": {"get_me": ".name"},
+		},
+	}
+
+	expression, ok := g.toolResultTransform("github", "search_issues")
+	require.True(t, ok)
+	assert.Equal(t, ".items", expression)
+
+	// --tool-result-transform takes precedence over the profile.
+	expression, ok = g.toolResultTransform("github", "get_me")
+	require.True(t, ok)
+	assert.Equal(t, ".name", expression)
+
+	_, ok = g.toolResultTransform("github", "list_repos")
+	assert.False(t, ok)
+}
+
+func TestValidateToolResultTransforms(t *testing.T) {
+	require.NoError(t, validateToolResultTransforms("github", map[string]string{"search_issues": ".items"}))
+
+	err := validateToolResultTransforms("github", map[string]string{"search_issues": ".items | map("})
+	require.ErrorContains(t, err, "invalid result transform for tool search_issues of server github")
+}
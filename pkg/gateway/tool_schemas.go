@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// printToolSchemas writes the name and JSON input schema of every tool the
+// gateway exposes, sorted by name. It helps debugging clients that reject a
+// tool because of its schema.
+func (g *Gateway) printToolSchemas(w io.Writer) error {
+	g.capabilitiesMu.RLock()
+	registrations := make([]ToolRegistration, 0, len(g.toolRegistrations))
+	for _, registration := range g.toolRegistrations {
+		registrations = append(registrations, registration)
+	}
+	g.capabilitiesMu.RUnlock()
+
+	slices.SortFunc(registrations, func(a, b ToolRegistration) int {
+		return strings.Compare(a.Tool.Name, b.Tool.Name)
+	})
+
+	fmt.Fprintf(w, "- Tool schemas (%d tools):\n", len(registrations))
+	for _, registration := range registrations {
+		schema, err := json.MarshalIndent(registration.Tool.InputSchema, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling input schema of tool %s: %w", registration.Tool.Name, err)
+		}
+
+		if registration.ServerName != "" {
+			fmt.Fprintf(w, "  > %s (server: %s)\n", registration.Tool.Name, registration.ServerName)
+		} else {
+			fmt.Fprintf(w, "  > %s\n", registration.Tool.Name)
+		}
+		fmt.Fprintf(w, "    %s\n", schema)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintToolSchemas(t *testing.T) {
+	// Discover the tools of a mock server the same way the gateway does.
+	mockServer := mcp.NewServer(&mcp.Implementation{Name: "mock", Version: "1.0.0"}, nil)
+	mockServer.AddTool(&mcp.Tool{
+		Name: "search",
+		InputSchema: &jsonschema.Schema{
+			Type:     "object",
+			Required: []string{"query"},
+			Properties: map[string]*jsonschema.Schema{
+				"query": {Type: "string", Description: "Search query"},
+			},
+		},
+	}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	mockServer.AddTool(&mcp.Tool{
+		Name:        "add",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := mockServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	tools, err := clientSession.ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+
+	g := &Gateway{toolRegistrations: make(map[string]ToolRegistration)}
+	for _, tool := range tools.Tools {
+		g.toolRegistrations[tool.Name] = ToolRegistration{ServerName: "mock", Tool: tool}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, g.printToolSchemas(&buf))
+	output := buf.String()
+
+	assert.Contains(t, output, "Tool schemas (2 tools)")
+	assert.Contains(t, output, "> add (server: mock)")
+	assert.Contains(t, output, "> search (server: mock)")
+	assert.Contains(t, output, `"required": [`)
+	assert.Contains(t, output, `"description": "Search query"`)
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("> add")), bytes.Index(buf.Bytes(), []byte("> search")))
+}
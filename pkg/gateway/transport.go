@@ -26,11 +26,16 @@ func (g *Gateway) startSseServer(ctx context.Context, ln net.Listener) error {
 
 	mux := http.NewServeMux()
 	mux.Handle("/health", healthHandler(&g.health))
+	mux.Handle("GET /healthz", g.statusHandler())
 	mux.Handle("/", redirectHandler("/sse"))
 	sseHandler := mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server {
 		return g.mcpServer
 	}, nil)
 	mux.Handle("/sse", originSecurityHandler(sseHandler))
+	mux.Handle("POST /reload-secrets", originSecurityHandler(g.reloadSecretsHandler()))
+	mux.Handle("GET /servers", originSecurityHandler(g.serversHandler()))
+	mux.Handle("POST /servers/remove-all", originSecurityHandler(g.removeAllServersHandler()))
+	mux.Handle("GET /servers/config-schema", originSecurityHandler(g.configSchemaHandler()))
 
 	// Wrap with authentication middleware
 	var handler http.Handler = mux
@@ -55,11 +60,16 @@ func (g *Gateway) startStreamingServer(ctx context.Context, ln net.Listener) err
 
 	mux := http.NewServeMux()
 	mux.Handle("/health", healthHandler(&g.health))
+	mux.Handle("GET /healthz", g.statusHandler())
 	mux.Handle("/", redirectHandler("/mcp"))
 	streamHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
 		return g.mcpServer
 	}, nil)
 	mux.Handle("/mcp", originSecurityHandler(streamHandler))
+	mux.Handle("POST /reload-secrets", originSecurityHandler(g.reloadSecretsHandler()))
+	mux.Handle("GET /servers", originSecurityHandler(g.serversHandler()))
+	mux.Handle("POST /servers/remove-all", originSecurityHandler(g.removeAllServersHandler()))
+	mux.Handle("GET /servers/config-schema", originSecurityHandler(g.configSchemaHandler()))
 
 	// Wrap with authentication middleware
 	var handler http.Handler = mux
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/suggest"
+)
+
+// unknownToolMiddleware turns the JSON-RPC error returned by the MCP server for
+// a call to an unknown or removed tool into a tool error result that clients
+// can show to the model, with a suggestion when a known tool has a close name.
+func (g *Gateway) unknownToolMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err == nil || method != "tools/call" {
+				return result, err
+			}
+
+			var rpcErr *jsonrpc.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams || !strings.HasPrefix(rpcErr.Message, "unknown tool") {
+				return result, err
+			}
+
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || params == nil {
+				return result, err
+			}
+
+			return g.unknownToolResult(params.Name), nil
+		}
+	}
+}
+
+func (g *Gateway) unknownToolResult(toolName string) *mcp.CallToolResult {
+	g.capabilitiesMu.RLock()
+	knownTools := make([]string, 0, len(g.toolRegistrations))
+	for name := range g.toolRegistrations {
+		knownTools = append(knownTools, name)
+	}
+	g.capabilitiesMu.RUnlock()
+
+	if g.SafeMode && isMutatingDynamicTool(toolName) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Tool '%s' is disabled in safe mode.", toolName)}},
+			IsError: true,
+		}
+	}
+
+	text := fmt.Sprintf("Tool '%s' is not available. It may have been removed or its server may not be enabled.", toolName)
+	if suggestion := closestToolName(toolName, knownTools); suggestion != "" {
+		text += fmt.Sprintf(" Did you mean '%s'?", suggestion)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: true,
+	}
+}
+
+// closestToolName returns the known tool name closest to name, if it is close
+// enough to be a likely typo.
+func closestToolName(name string, knownTools []string) string {
+	if closest := suggest.Closest(name, knownTools, 1); len(closest) > 0 {
+		return closest[0]
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clientSessionWithTools(t *testing.T, toolNames ...string) *mcp.ClientSession {
+	t.Helper()
+
+	g := &Gateway{toolRegistrations: make(map[string]ToolRegistration)}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	for _, name := range toolNames {
+		registration := ToolRegistration{
+			ServerName: "mock",
+			Tool:       &mcp.Tool{Name: name, InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+			},
+		}
+		server.AddTool(registration.Tool, registration.Handler)
+		g.toolRegistrations[name] = registration
+	}
+	server.AddReceivingMiddleware(g.unknownToolMiddleware())
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return clientSession
+}
+
+func TestCallUnknownTool(t *testing.T) {
+	session := clientSessionWithTools(t, "search_repositories", "create_issue")
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "delete_everything"})
+	require.NoError(t, err)
+
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Tool 'delete_everything' is not available")
+	assert.NotContains(t, text, "Did you mean")
+}
+
+func TestCallUnknownToolSuggestsClosestName(t *testing.T) {
+	session := clientSessionWithTools(t, "search_repositories", "create_issue")
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search_repository"})
+	require.NoError(t, err)
+
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Did you mean 'search_repositories'?")
+}
+
+func TestCallKnownToolIsUnaffected(t *testing.T) {
+	session := clientSessionWithTools(t, "create_issue")
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "create_issue"})
+	require.NoError(t, err)
+
+	assert.False(t, result.IsError)
+	assert.Equal(t, "ok", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestClosestToolName(t *testing.T) {
+	known := []string{"get_file", "get_files", "list_issues"}
+
+	assert.Equal(t, "get_file", closestToolName("get_fle", known))
+	assert.Equal(t, "list_issues", closestToolName("List_Issue", known))
+	assert.Empty(t, closestToolName("something_else", known))
+	assert.Empty(t, closestToolName("get_file", nil))
+}
@@ -0,0 +1,95 @@
+package imagepolicy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// Policy restricts the images that servers may use. Patterns are globs, as
+// supported by path.Match, matched against the repository of an image,
+// without its tag or digest, or against any of its parent paths. Docker Hub
+// images match in their short form, e.g. mcp/fetch, as well as with the
+// docker.io/ prefix. For example, mcp/* allows mcp/fetch and
+// registry.internal/* allows registry.internal/team/server.
+type Policy struct {
+	// Allow lists the images that are allowed. When empty, all the images
+	// that are not denied are allowed.
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	// Deny lists the images that are denied, even if they are allowed.
+	Deny []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// IsEmpty reports whether the policy allows every image.
+func (p Policy) IsEmpty() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0
+}
+
+// Validate checks the syntax of the patterns.
+func (p Policy) Validate() error {
+	for _, pattern := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid image pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Check returns an error when image is denied, or when the policy has allowed
+// images and image doesn't match any of them.
+func (p Policy) Check(image string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	candidates, err := repositoryPaths(image)
+	if err != nil {
+		return err
+	}
+	if pattern, ok := matchAny(p.Deny, candidates); ok {
+		return fmt.Errorf("image %s is denied by the image policy (%s)", image, pattern)
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	if _, ok := matchAny(p.Allow, candidates); !ok {
+		return fmt.Errorf("image %s is not allowed by the image policy (allowed: %s)", image, strings.Join(p.Allow, ", "))
+	}
+	return nil
+}
+
+// repositoryPaths returns the repository of an image and its parent paths,
+// in every form the image can be matched in.
+func repositoryPaths(image string) ([]string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image %s: %w", image, err)
+	}
+
+	repositories := []string{ref.Context().Name()}
+	if registry := ref.Context().RegistryStr(); registry == name.DefaultRegistry {
+		repository := strings.TrimPrefix(ref.Context().RepositoryStr(), "library/")
+		repositories = []string{repository, "docker.io/" + repository}
+	}
+
+	var paths []string
+	for _, repository := range repositories {
+		for p := repository; p != "." && p != "/"; p = path.Dir(p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+func matchAny(patterns []string, candidates []string) (string, bool) {
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
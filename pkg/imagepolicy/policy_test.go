@@ -0,0 +1,66 @@
+package imagepolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	policy := Policy{
+		Allow: []string{"mcp/*", "registry.internal/*"},
+		Deny:  []string{"mcp/deprecated-*"},
+	}
+
+	tests := []struct {
+		image   string
+		allowed bool
+	}{
+		{image: "mcp/fetch", allowed: true},
+		{image: "mcp/fetch:latest", allowed: true},
+		{image: "docker.io/mcp/fetch@sha256:1111111111111111111111111111111111111111111111111111111111111111", allowed: true},
+		{image: "registry.internal/team/server:1.0", allowed: true},
+		{image: "mcp/deprecated-github", allowed: false},
+		{image: "ghcr.io/example/server", allowed: false},
+		{image: "alpine", allowed: false},
+		{image: "registry.internal.evil.com/server", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			err := policy.Check(tt.image)
+			if tt.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckMessages(t *testing.T) {
+	err := Policy{Deny: []string{"ghcr.io/*"}}.Check("ghcr.io/example/server:1.0")
+	require.EqualError(t, err, "image ghcr.io/example/server:1.0 is denied by the image policy (ghcr.io/*)")
+
+	err = Policy{Allow: []string{"mcp/*"}}.Check("ghcr.io/example/server:1.0")
+	require.EqualError(t, err, "image ghcr.io/example/server:1.0 is not allowed by the image policy (allowed: mcp/*)")
+}
+
+func TestCheckDenyOnly(t *testing.T) {
+	policy := Policy{Deny: []string{"docker.io/*"}}
+
+	require.Error(t, policy.Check("mcp/fetch"))
+	require.Error(t, policy.Check("alpine"))
+	require.NoError(t, policy.Check("ghcr.io/example/server"))
+}
+
+func TestEmptyPolicyAllowsEverything(t *testing.T) {
+	assert.True(t, Policy{}.IsEmpty())
+	require.NoError(t, Policy{}.Check("anything/goes:latest"))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Policy{Allow: []string{"mcp/*"}, Deny: []string{"mcp/[a-c]*"}}.Validate())
+	require.EqualError(t, Policy{Allow: []string{"mcp/["}}.Validate(), `invalid image pattern "mcp/["`)
+	require.Error(t, Policy{Deny: []string{""}}.Validate())
+}
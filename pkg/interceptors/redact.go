@@ -0,0 +1,139 @@
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redactedText replaces the text matched by a redaction pattern.
+const redactedText = "[REDACTED]"
+
+// ParseRedactPatterns compiles redaction patterns, using the RE2 syntax.
+func ParseRedactPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// RedactMiddleware replaces the text matching any of the patterns with
+// [REDACTED] in the text content of tool results, e.g. emails or credit card
+// numbers. Text content holding a JSON document is redacted in its string
+// values only, so that it remains valid JSON. The structured content of the
+// results is left intact.
+func RedactMiddleware(patterns []*regexp.Regexp) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil || method != "tools/call" || len(patterns) == 0 {
+				return result, err
+			}
+
+			toolResult, ok := result.(*mcp.CallToolResult)
+			if !ok || toolResult == nil {
+				return result, err
+			}
+
+			var redactedContent []mcp.Content
+			for i, content := range toolResult.Content {
+				textContent, ok := content.(*mcp.TextContent)
+				if !ok {
+					continue
+				}
+				text, changed := redactText(textContent.Text, patterns)
+				if !changed {
+					continue
+				}
+				if redactedContent == nil {
+					redactedContent = append([]mcp.Content(nil), toolResult.Content...)
+				}
+				redactedTextContent := *textContent
+				redactedTextContent.Text = text
+				redactedContent[i] = &redactedTextContent
+			}
+			if redactedContent == nil {
+				return result, nil
+			}
+
+			redactedResult := *toolResult
+			redactedResult.Content = redactedContent
+			return &redactedResult, nil
+		}
+	}
+}
+
+// redactText redacts the text matching any of the patterns. A JSON object or
+// array is only redacted in its string values, and is re-encoded only if
+// something was redacted. It reports whether the text changed.
+func redactText(text string, patterns []*regexp.Regexp) (string, bool) {
+	var document any
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if json.Valid([]byte(text)) && decoder.Decode(&document) == nil {
+		switch document.(type) {
+		case map[string]any, []any:
+			redacted, changed := redactValue(document, patterns)
+			if !changed {
+				return text, false
+			}
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(redacted); err != nil {
+				return text, false
+			}
+			return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), true
+		}
+	}
+
+	redacted := redactString(text, patterns)
+	return redacted, redacted != text
+}
+
+func redactValue(value any, patterns []*regexp.Regexp) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		redacted := redactString(v, patterns)
+		return redacted, redacted != v
+	case map[string]any:
+		changed := false
+		for key, item := range v {
+			redacted, itemChanged := redactValue(item, patterns)
+			if itemChanged {
+				v[key] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, item := range v {
+			redacted, itemChanged := redactValue(item, patterns)
+			if itemChanged {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	default:
+		return v, false
+	}
+}
+
+func redactString(value string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		value = pattern.ReplaceAllLiteralString(value, redactedText)
+	}
+	return value
+}
@@ -0,0 +1,102 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRedactPatterns = []string{
+	`[\w.+-]+@[\w-]+\.[\w.]+`,
+	`\b(?:\d[ -]?){12,18}\d\b`,
+}
+
+func TestRedactTextCorpus(t *testing.T) {
+	patterns, err := ParseRedactPatterns(testRedactPatterns)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("testdata/redact_corpus.json")
+	require.NoError(t, err)
+	var corpus []struct {
+		Name string `json:"name"`
+		Text string `json:"text"`
+		Want string `json:"want"`
+	}
+	require.NoError(t, json.Unmarshal(content, &corpus))
+	require.NotEmpty(t, corpus)
+
+	for _, tc := range corpus {
+		t.Run(tc.Name, func(t *testing.T) {
+			redacted, changed := redactText(tc.Text, patterns)
+			assert.Equal(t, tc.Want, redacted)
+			assert.Equal(t, tc.Want != tc.Text, changed)
+			if json.Valid([]byte(tc.Text)) {
+				assert.True(t, json.Valid([]byte(redacted)), "redacted JSON must remain valid")
+			}
+		})
+	}
+}
+
+func TestParseRedactPatternsInvalid(t *testing.T) {
+	_, err := ParseRedactPatterns([]string{`[\w]+@`, `(unclosed`})
+	require.ErrorContains(t, err, `invalid redaction pattern "(unclosed"`)
+}
+
+func TestRedactMiddleware(t *testing.T) {
+	patterns, err := ParseRedactPatterns(testRedactPatterns)
+	require.NoError(t, err)
+
+	structured := map[string]any{"owner": "jane@example.com", "id": float64(42)}
+	original := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: "Issue #42 is owned by jane@example.com"},
+			&mcp.TextContent{Text: `{"id":42,"owner":"jane@example.com"}`},
+			&mcp.ImageContent{Data: []byte("jane@example.com"), MIMEType: "image/png"},
+		},
+		StructuredContent: structured,
+	}
+	handler := RedactMiddleware(patterns)(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return original, nil
+	})
+
+	result, err := handler(t.Context(), "tools/call", &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	toolResult, ok := result.(*mcp.CallToolResult)
+	require.True(t, ok)
+
+	require.Len(t, toolResult.Content, 3)
+	assert.Equal(t, "Issue #42 is owned by [REDACTED]", toolResult.Content[0].(*mcp.TextContent).Text)
+	assert.JSONEq(t, `{"id":42,"owner":"[REDACTED]"}`, toolResult.Content[1].(*mcp.TextContent).Text)
+	assert.Same(t, original.Content[2], toolResult.Content[2])
+
+	// Structured fields are left intact, as is the original result.
+	assert.Equal(t, map[string]any{"owner": "jane@example.com", "id": float64(42)}, toolResult.StructuredContent)
+	assert.Equal(t, "Issue #42 is owned by jane@example.com", original.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestRedactMiddlewareOnlyRedactsToolResults(t *testing.T) {
+	patterns, err := ParseRedactPatterns(testRedactPatterns)
+	require.NoError(t, err)
+
+	clean := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "nothing to hide"}}}
+	prompt := &mcp.GetPromptResult{Description: "jane@example.com"}
+	var next mcp.Result
+	handler := RedactMiddleware(patterns)(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return next, nil
+	})
+
+	next = clean
+	result, err := handler(t.Context(), "tools/call", &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Same(t, clean, result)
+
+	next = prompt
+	result, err = handler(t.Context(), "prompts/get", &mcp.GetPromptRequest{})
+	require.NoError(t, err)
+	assert.Same(t, prompt, result)
+}
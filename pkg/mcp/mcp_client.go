@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -64,3 +65,18 @@ func notifications(serverName string, serverSession *mcp.ServerSession, server *
 		},
 	}
 }
+
+// clientCapabilities returns the capabilities the gateway advertises to a
+// server: the SDK defaults, plus the experimental capabilities of params,
+// forwarded from the gateway's own client. It returns nil, for the SDK
+// defaults alone, when there are none to forward.
+func clientCapabilities(params *mcp.InitializeParams) *mcp.ClientCapabilities {
+	if params == nil || params.Capabilities == nil || len(params.Capabilities.Experimental) == 0 {
+		return nil
+	}
+	return &mcp.ClientCapabilities{
+		// Setting capabilities replaces the SDK defaults, so keep them.
+		RootsV2:      &mcp.RootCapabilities{ListChanged: true},
+		Experimental: maps.Clone(params.Capabilities.Experimental),
+	}
+}
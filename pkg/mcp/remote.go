@@ -1,10 +1,13 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync/atomic"
 
@@ -20,7 +23,10 @@ import (
 )
 
 type remoteMCPClient struct {
-	config      *catalog.ServerConfig
+	config *catalog.ServerConfig
+	// transports, when set, provides the HTTP transport shared with the
+	// other clients of the server.
+	transports  *RemoteTransports
 	client      *mcp.Client
 	session     *mcp.ClientSession
 	roots       []*mcp.Root
@@ -33,51 +39,43 @@ func NewRemoteMCPClient(config *catalog.ServerConfig) Client {
 	}
 }
 
-func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, verbose bool, _ *mcp.ServerSession, _ *mcp.Server, _ CapabilityRefresher) error {
+// NewPooledRemoteMCPClient returns a remote client whose connections are
+// pooled with the ones of the other clients of the server.
+func NewPooledRemoteMCPClient(config *catalog.ServerConfig, transports *RemoteTransports) Client {
+	return &remoteMCPClient{
+		config:     config,
+		transports: transports,
+	}
+}
+
+func (c *remoteMCPClient) Initialize(ctx context.Context, params *mcp.InitializeParams, verbose bool, _ *mcp.ServerSession, _ *mcp.Server, _ CapabilityRefresher) error {
 	if c.initialized.Load() {
 		return fmt.Errorf("client already initialized")
 	}
 
 	// Read configuration.
 	var (
-		url       string
+		rawURL    string
 		transport string
 	)
 	if c.config.Spec.SSEEndpoint != "" {
 		// Deprecated
-		url = c.config.Spec.SSEEndpoint
+		rawURL = c.config.Spec.SSEEndpoint
 		transport = "sse"
 	} else {
-		url = c.config.Spec.Remote.URL
+		rawURL = c.config.Spec.Remote.URL
 		transport = c.config.Spec.Remote.Transport
 	}
-	if err := remoteurl.Validate(ctx, url); err != nil {
-		return fmt.Errorf("unsafe remote MCP URL for %s: %w", c.config.Name, err)
-	}
 
 	// Secrets to env
-	env := map[string]string{}
-	for _, s := range c.config.Spec.Secrets {
-		// Remote servers need actual secret values for HTTP headers.
-		// se:// URIs only work for containers (Docker Desktop resolves them at runtime).
-		//
-		// Check if we have an actual value (from --secrets=file.env).
-		// If the value is an se:// URI or missing, query Secrets Engine API directly.
-		if value, ok := c.config.Secrets[s.Name]; ok && value != "" && !strings.HasPrefix(value, "se://") {
-			if verbose {
-				log.Logf("    - %s: %s", s.Env, maskSecret(value))
-			}
-			env[s.Env] = value
-		} else {
-			// Fall back to secrets engine (Docker Desktop direct API)
-			if verbose {
-				log.Logf("    - Fetching secret: %s", s.Name)
-			}
-			env[s.Env] = getSecretValue(ctx, s.Name)
-			if verbose {
-				log.Logf("    - Got secret for: %s (len=%d)", s.Name, len(env[s.Env]))
-			}
-		}
+	env := RemoteSecretEnv(ctx, c.config, verbose)
+
+	// The ${VAR} references to secrets in the URL are only expanded to
+	// connect: logs and errors show the references instead of the values.
+	url := expandEnv(rawURL, env)
+	redact := urlSecretsRedactor(rawURL, env)
+	if err := remoteurl.Validate(ctx, url); err != nil {
+		return fmt.Errorf("unsafe remote MCP URL for %s: %w", c.config.Name, redact(err))
 	}
 
 	// Headers
@@ -116,17 +114,23 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 	var mcpTransport mcp.Transport
 	var err error
 
-	baseTransport := remoteurl.GuardDirectTransport()
-	if proxyDialer := desktop.DockerDesktopProxySocketDialer(ctx); proxyDialer != nil {
-		baseTransport = remoteurl.GuardTrustedProxyDialer(proxyDialer)
+	var baseTransport http.RoundTripper
+	if c.transports != nil {
+		baseTransport = c.transports.Transport(ctx, c.config.Name)
+	} else {
+		baseTransport = remoteurl.GuardDirectTransport()
+		if proxyDialer := desktop.DockerDesktopProxySocketDialer(ctx); proxyDialer != nil {
+			baseTransport = remoteurl.GuardTrustedProxyDialer(proxyDialer)
+		}
 	}
 
 	// Create HTTP client with custom headers
+	roundTripper := &headerRoundTripper{
+		base:    baseTransport,
+		headers: headers,
+	}
 	httpClient := &http.Client{
-		Transport: &headerRoundTripper{
-			base:    baseTransport,
-			headers: headers,
-		},
+		Transport: roundTripper,
 	}
 
 	switch strings.ToLower(transport) {
@@ -147,17 +151,17 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "docker-mcp-gateway",
 		Version: "1.0.0",
-	}, nil)
+	}, &mcp.ClientOptions{Capabilities: clientCapabilities(params)})
 
 	c.client.AddRoots(c.roots...)
 
 	if verbose {
-		log.Logf("    - Connecting to remote server: %s (transport=%s)", url, transport)
+		log.Logf("    - Connecting to remote server: %s (transport=%s)", rawURL, transport)
 	}
 
 	session, err := c.client.Connect(ctx, mcpTransport, nil)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return &RemoteConnectError{Err: redact(err), Unauthorized: roundTripper.unauthorized.Load()}
 	}
 
 	if verbose {
@@ -180,6 +184,37 @@ func (c *remoteMCPClient) AddRoots(roots []*mcp.Root) {
 	c.roots = roots
 }
 
+// RemoteSecretEnv returns the values of the secrets and secret templates of
+// a remote server, indexed by their env name, for the ${VAR} references of
+// its headers and URL.
+func RemoteSecretEnv(ctx context.Context, config *catalog.ServerConfig, verbose bool) map[string]string {
+	env := map[string]string{}
+	for _, s := range config.Spec.Secrets {
+		// Remote servers need actual secret values for HTTP headers.
+		// se:// URIs only work for containers (Docker Desktop resolves them at runtime).
+		//
+		// Check if we have an actual value (from --secrets=file.env).
+		// If the value is an se:// URI or missing, query Secrets Engine API directly.
+		if value, ok := config.Secrets[s.Name]; ok && value != "" && !strings.HasPrefix(value, "se://") {
+			if verbose {
+				log.Logf("    - %s: %s", s.Env, maskSecret(value))
+			}
+			env[s.Env] = value
+		} else {
+			// Fall back to secrets engine (Docker Desktop direct API)
+			if verbose {
+				log.Logf("    - Fetching secret: %s", s.Name)
+			}
+			env[s.Env] = getSecretValue(ctx, s.Name)
+			if verbose {
+				log.Logf("    - Got secret for: %s (len=%d)", s.Name, len(env[s.Env]))
+			}
+		}
+	}
+	maps.Copy(env, SecretTemplateEnv(ctx, config, verbose))
+	return env
+}
+
 func getSecretValue(ctx context.Context, secretName string) string {
 	id, err := seclient.ParseID(secretName)
 	if err != nil {
@@ -204,6 +239,49 @@ func expandEnv(value string, secrets map[string]string) string {
 	})
 }
 
+// urlSecretsRedactor returns a function hiding, from the messages of errors,
+// the values of the secrets referenced with ${VAR} in a remote URL, e.g. in
+// the URL of a failed request. They're replaced with their references.
+func urlSecretsRedactor(rawURL string, env map[string]string) func(error) error {
+	var names []string
+	os.Expand(rawURL, func(name string) string {
+		if env[name] != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		return ""
+	})
+	if len(names) == 0 {
+		return func(err error) error { return err }
+	}
+
+	// Replace longer values first, in case a secret contains another one.
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(env[b]), len(env[a])), strings.Compare(a, b))
+	})
+	oldnew := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		oldnew = append(oldnew, env[name], "${"+name+"}")
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	return func(err error) error {
+		return &redactedError{err: err, message: replacer.Replace(err.Error())}
+	}
+}
+
+// redactedError is an error whose message hides the values of secrets.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
 // maskSecret shows the first few characters of a secret followed by asterisks.
 // se:// URIs are shown in full since they're just references, not actual secrets.
 func maskSecret(value string) string {
@@ -216,10 +294,36 @@ func maskSecret(value string) string {
 	return value[:4] + "****"
 }
 
+// RemoteConnectError is returned by remote clients when the connection to
+// the server can't be established.
+type RemoteConnectError struct {
+	Err error
+	// Unauthorized is set when the server rejected the credentials with an
+	// HTTP 401 or 403.
+	Unauthorized bool
+}
+
+func (e *RemoteConnectError) Error() string {
+	return "failed to connect: " + e.Err.Error()
+}
+
+func (e *RemoteConnectError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether connecting again might succeed. Authentication
+// failures won't fix themselves.
+func (e *RemoteConnectError) Retryable() bool {
+	return !e.Unauthorized
+}
+
 // headerRoundTripper is an http.RoundTripper that adds custom headers to all requests
 type headerRoundTripper struct {
 	base    http.RoundTripper
 	headers map[string]string
+
+	// unauthorized records whether the server ever answered 401 or 403.
+	unauthorized atomic.Bool
 }
 
 func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -233,5 +337,10 @@ func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 		}
 		newReq.Header.Set(key, value)
 	}
-	return h.base.RoundTrip(newReq)
+
+	resp, err := h.base.RoundTrip(newReq)
+	if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		h.unauthorized.Store(true)
+	}
+	return resp, err
 }
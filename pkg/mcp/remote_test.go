@@ -2,7 +2,9 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	neturl "net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -160,3 +162,20 @@ func TestRemoteMCPClientRejectsUnsafeRemoteURL(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsafe remote MCP URL")
 }
+
+func TestURLSecretsRedactor(t *testing.T) {
+	env := map[string]string{"TENANT": "acme", "TOKEN": "s3cr3t-acme", "REGION": "eu"}
+	rawURL := "https://mcp.example.com/${TENANT}/mcp?token=${TOKEN}"
+	url := expandEnv(rawURL, env)
+	require.Equal(t, "https://mcp.example.com/acme/mcp?token=s3cr3t-acme", url)
+
+	cause := &neturl.Error{Op: "Post", URL: url, Err: errors.New("connection refused")}
+	err := urlSecretsRedactor(rawURL, env)(cause)
+
+	assert.Equal(t, `Post "https://mcp.example.com/${TENANT}/mcp?token=${TOKEN}": connection refused`, err.Error())
+	assert.ErrorIs(t, err, cause.Err)
+
+	// Secrets that the URL doesn't reference are left alone.
+	err = urlSecretsRedactor("https://mcp.example.com/mcp", env)(cause)
+	assert.Same(t, cause, err)
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// RemoteTransports shares the HTTP transport of each remote server between
+// its successive clients, so that tool calls reuse the idle keep-alive
+// connections of the previous ones instead of dialing the server again.
+// Transports have no timeout of their own: calls are bounded by their
+// context, as with unpooled clients.
+type RemoteTransports struct {
+	maxIdleConns int
+
+	mu         sync.Mutex
+	transports map[string]http.RoundTripper
+	// newTransport creates the transport of a server. Replaced in tests.
+	newTransport func(ctx context.Context) http.RoundTripper
+}
+
+// NewRemoteTransports returns a pool keeping up to maxIdleConns idle
+// connections per remote server.
+func NewRemoteTransports(maxIdleConns int) *RemoteTransports {
+	p := &RemoteTransports{
+		maxIdleConns: maxIdleConns,
+		transports:   make(map[string]http.RoundTripper),
+	}
+	p.newTransport = p.guardedTransport
+	return p
+}
+
+// Transport returns the transport of a remote server, creating it on first
+// use.
+func (p *RemoteTransports) Transport(ctx context.Context, serverName string) http.RoundTripper {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	transport, ok := p.transports[serverName]
+	if !ok {
+		transport = p.newTransport(ctx)
+		p.transports[serverName] = transport
+	}
+	return transport
+}
+
+// Forget closes the idle connections to a remote server and drops its
+// transport, e.g. when its configuration changed.
+func (p *RemoteTransports) Forget(serverName string) {
+	p.mu.Lock()
+	transport, ok := p.transports[serverName]
+	delete(p.transports, serverName)
+	p.mu.Unlock()
+
+	if ok {
+		closeIdleConnections(transport)
+	}
+}
+
+// Close closes the idle connections to every remote server.
+func (p *RemoteTransports) Close() {
+	p.mu.Lock()
+	transports := p.transports
+	p.transports = make(map[string]http.RoundTripper)
+	p.mu.Unlock()
+
+	for _, transport := range transports {
+		closeIdleConnections(transport)
+	}
+}
+
+func (p *RemoteTransports) guardedTransport(ctx context.Context) http.RoundTripper {
+	if proxyDialer := desktop.DockerDesktopProxySocketDialer(ctx); proxyDialer != nil {
+		return remoteurl.GuardTrustedProxyDialer(proxyDialer)
+	}
+
+	base := remoteurl.DirectTransport()
+	if transport, ok := base.(*http.Transport); ok {
+		transport.MaxIdleConnsPerHost = p.maxIdleConns
+	}
+	return remoteurl.GuardTransport(base)
+}
+
+func closeIdleConnections(transport http.RoundTripper) {
+	(&http.Client{Transport: transport}).CloseIdleConnections()
+}
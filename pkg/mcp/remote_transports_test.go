@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// countingTransports returns a pool whose transports count the connections
+// they dial.
+func countingTransports(t *testing.T) (*RemoteTransports, *atomic.Int32) {
+	t.Helper()
+
+	var dials atomic.Int32
+	transports := NewRemoteTransports(4)
+	transports.newTransport = func(context.Context) http.RoundTripper {
+		var dialer net.Dialer
+		return remoteurl.GuardTransport(&http.Transport{
+			MaxIdleConnsPerHost: transports.maxIdleConns,
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				dials.Add(1)
+				return dialer.DialContext(ctx, network, address)
+			},
+		})
+	}
+	return transports, &dials
+}
+
+func echoRemoteServer(t *testing.T) *catalog.ServerConfig {
+	t.Helper()
+	t.Setenv(remoteurl.AllowInsecureRemoteURLEnv, "1")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "remote", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo"}}}, nil, nil
+	})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, &mcp.StreamableHTTPOptions{Stateless: true})
+	remote := httptest.NewServer(handler)
+	t.Cleanup(remote.Close)
+
+	return &catalog.ServerConfig{
+		Name: "remote",
+		Spec: catalog.Server{
+			Name:   "remote",
+			Type:   "remote",
+			Remote: catalog.Remote{URL: remote.URL, Transport: "streamable-http"},
+		},
+	}
+}
+
+func callRemoteTool(t *testing.T, client Client) {
+	t.Helper()
+
+	require.NoError(t, client.Initialize(t.Context(), nil, false, nil, nil, nil))
+	result, err := client.Session().CallTool(t.Context(), &mcp.CallToolParams{Name: "echo"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	require.NoError(t, client.Session().Close())
+}
+
+func TestPooledRemoteClientsReuseConnections(t *testing.T) {
+	serverConfig := echoRemoteServer(t)
+	transports, dials := countingTransports(t)
+	t.Cleanup(transports.Close)
+
+	for range 3 {
+		callRemoteTool(t, NewPooledRemoteMCPClient(serverConfig, transports))
+	}
+
+	assert.Equal(t, int32(1), dials.Load())
+}
+
+func TestForgottenRemoteTransportDialsAgain(t *testing.T) {
+	serverConfig := echoRemoteServer(t)
+	transports, dials := countingTransports(t)
+	t.Cleanup(transports.Close)
+
+	callRemoteTool(t, NewPooledRemoteMCPClient(serverConfig, transports))
+	transports.Forget("remote")
+	callRemoteTool(t, NewPooledRemoteMCPClient(serverConfig, transports))
+
+	assert.Equal(t, int32(2), dials.Load())
+}
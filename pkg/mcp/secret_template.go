@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// SecretTemplateEnv returns the values of the secret templates of a server,
+// indexed by their env name. Each part is resolved from the server's secrets,
+// querying the secrets engine for se:// URIs, or else from its config.
+// Templates with unresolved parts are set to <UNKNOWN>. The composed values
+// are never logged.
+func SecretTemplateEnv(ctx context.Context, config *catalog.ServerConfig, verbose bool) map[string]string {
+	env := map[string]string{}
+	for _, template := range config.Spec.SecretTemplates {
+		value, missing := template.Expand(func(part string) (string, bool) {
+			return secretTemplatePart(ctx, config, part)
+		})
+		if len(missing) > 0 {
+			log.Logf("Warning: Secret template of %s for server '%s' has unresolved parts %s, setting %s=<UNKNOWN>", template.Env, config.Name, strings.Join(missing, ", "), template.Env)
+			value = "<UNKNOWN>"
+		} else if verbose {
+			log.Logf("    - %s: composed from %s", template.Env, strings.Join(template.Parts(), ", "))
+		}
+		env[template.Env] = value
+	}
+	return env
+}
+
+func secretTemplatePart(ctx context.Context, config *catalog.ServerConfig, part string) (string, bool) {
+	if value, ok := config.Secrets[part]; ok {
+		// se:// URIs are only resolved in the env of containers, not inside
+		// a composed value.
+		if strings.HasPrefix(value, "se://") {
+			value = getSecretValue(ctx, part)
+		}
+		if value != "" {
+			return value, true
+		}
+	}
+	if value := fmt.Sprintf("%v", eval.Evaluate("{{"+part+"}}", config.Config)); value != "" {
+		return value, true
+	}
+	return "", false
+}
@@ -33,7 +33,7 @@ func NewStdioCmdClient(name string, command string, env []string, args ...string
 	}
 }
 
-func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, debug bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
+func (c *stdioMCPClient) Initialize(ctx context.Context, params *mcp.InitializeParams, debug bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
 	if c.initialized.Load() {
 		return fmt.Errorf("client already initialized")
 	}
@@ -46,10 +46,12 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams
 	}
 
 	transport := &mcp.CommandTransport{Command: cmd}
+	options := notifications(c.name, ss, server, refresher)
+	options.Capabilities = clientCapabilities(params)
 	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "docker-mcp-gateway",
 		Version: "1.0.0",
-	}, notifications(c.name, ss, server, refresher))
+	}, options)
 
 	c.client.AddRoots(c.roots...)
 
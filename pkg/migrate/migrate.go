@@ -149,6 +149,8 @@ func createDefaultProfile(ctx context.Context, dao db.DAO, registry *config.Regi
 		case "remote":
 			profileServer.Type = workingset.ServerTypeRemote
 			profileServer.Endpoint = oldServer.Remote.URL
+		case legacycatalog.ServerTypePOCI:
+			profileServer.Type = workingset.ServerTypePOCI
 		default:
 			logs = append(logs, fmt.Sprintf("server %s has an invalid server type: %s, skipping", server, oldServer.Type))
 			continue // Ignore
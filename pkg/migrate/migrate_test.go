@@ -828,6 +828,10 @@ func (m *mockDockerClient) InspectVolume(ctx context.Context, name string) (volu
 	return volume.Volume{}, sql.ErrNoRows
 }
 
+func (m *mockDockerClient) Ping(_ context.Context) error {
+	return nil
+}
+
 func (m *mockDockerClient) ReadSecrets(_ context.Context, _ []string, _ bool) (map[string]string, error) {
 	return nil, nil //nolint:nilnil
 }
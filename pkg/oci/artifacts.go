@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -154,12 +153,12 @@ func uploadBlob(ctx context.Context, ref name.Reference, data []byte, _ digest.D
 	layer := static.NewLayer(data, types.MediaType("application/octet-stream"))
 
 	// Write the layer (blob) to the repository with authentication
-	return remote.WriteLayer(repo, layer, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
+	return remote.WriteLayer(repo, layer, remote.WithAuthFromKeychain(Keychain()), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
 }
 
 func uploadManifest(ctx context.Context, ref name.Reference, manifestBytes []byte) error {
 	// Create a custom image with the manifest
-	return remote.Put(ref, &customManifest{data: manifestBytes}, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
+	return remote.Put(ref, &customManifest{data: manifestBytes}, remote.WithAuthFromKeychain(Keychain()), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
 }
 
 // customManifest implements v1.Image interface for custom manifest
@@ -251,9 +250,9 @@ func ReadArtifact[T any](ociRef string, expectedArtifactType string) (T, error)
 	}
 
 	// Get the image/artifact from the registry
-	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithTransport(desktop.ProxyTransport()))
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(Keychain()), remote.WithTransport(desktop.ProxyTransport()))
 	if err != nil {
-		return *new(T), fmt.Errorf("failed to fetch image/artifact %s: %w", ociRef, err)
+		return *new(T), fmt.Errorf("failed to fetch image/artifact %s: %w", ociRef, WrapAuthError(ref, err))
 	}
 
 	// Get the raw manifest to check if it's an OCI artifact
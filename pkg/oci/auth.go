@@ -0,0 +1,124 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// ErrUnauthorized is returned, wrapped, when a registry refuses to serve a
+// reference without credentials, or with the credentials it was given.
+var ErrUnauthorized = errors.New("registry authentication required")
+
+// registryKeychain resolves the credentials of the registries, from the
+// default Docker config and its credential helpers unless overridden with
+// SetRegistryAuthConfig.
+var registryKeychain authn.Keychain = authn.DefaultKeychain
+
+// Keychain returns the keychain used to authenticate to registries.
+func Keychain() authn.Keychain {
+	return registryKeychain
+}
+
+// SetRegistryAuthConfig makes the pulls and pushes authenticate with the
+// credentials of a Docker config file, e.g. a config.json written by `docker
+// login` in CI, instead of the default one. Its credsStore and credHelpers
+// are honored. path can also be the directory of the config.json. An empty
+// path restores the default Docker config.
+func SetRegistryAuthConfig(path string) error {
+	if path == "" {
+		registryKeychain = authn.DefaultKeychain
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading registry auth config: %w", err)
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, config.ConfigFileName)
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("reading registry auth config: %w", err)
+		}
+	}
+
+	registryKeychain = &configFileKeychain{path: path}
+	return nil
+}
+
+// configFileKeychain resolves credentials from a given Docker config file,
+// the way authn.DefaultKeychain does from the default one.
+type configFileKeychain struct {
+	path string
+}
+
+func (k *configFileKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	f, err := os.Open(k.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading registry auth config: %w", err)
+	}
+	defer f.Close()
+
+	cf, err := config.LoadFromReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing registry auth config %s: %w", k.path, err)
+	}
+
+	var cfg, empty types.AuthConfig
+	for _, key := range []string{target.String(), target.RegistryStr()} {
+		if key == name.DefaultRegistry {
+			key = authn.DefaultAuthKey
+		}
+		cfg, err = cf.GetAuthConfig(key)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ServerAddress = ""
+		if cfg != empty {
+			break
+		}
+	}
+	if cfg == empty {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+		Auth:          cfg.Auth,
+		IdentityToken: cfg.IdentityToken,
+		RegistryToken: cfg.RegistryToken,
+	}), nil
+}
+
+// WrapAuthError turns the errors of a registry refusing to serve ref, which
+// registries also return for private repositories that don't exist, into an
+// ErrUnauthorized telling how to authenticate. Other errors are returned as
+// is.
+func WrapAuthError(ref name.Reference, err error) error {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) || !isAuthError(transportErr) {
+		return err
+	}
+	return fmt.Errorf("%w to pull %s: log in with `docker login %s`, or pass a Docker config with its credentials with --registry-auth: %w", ErrUnauthorized, ref.String(), ref.Context().RegistryStr(), err)
+}
+
+func isAuthError(err *transport.Error) bool {
+	if err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden {
+		return true
+	}
+	for _, diagnostic := range err.Errors {
+		if diagnostic.Code == transport.UnauthorizedErrorCode || diagnostic.Code == transport.DeniedErrorCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package oci
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapAuthError(t *testing.T) {
+	ref, err := name.ParseReference("registry.example.com/private/catalog:latest")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantAuth bool
+	}{
+		{name: "unauthorized", err: &transport.Error{StatusCode: http.StatusUnauthorized}, wantAuth: true},
+		{name: "forbidden", err: &transport.Error{StatusCode: http.StatusForbidden}, wantAuth: true},
+		{name: "denied code", err: &transport.Error{StatusCode: http.StatusNotFound, Errors: []transport.Diagnostic{{Code: transport.DeniedErrorCode}}}, wantAuth: true},
+		{name: "not found", err: &transport.Error{StatusCode: http.StatusNotFound, Errors: []transport.Diagnostic{{Code: transport.ManifestUnknownErrorCode}}}},
+		{name: "other error", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := WrapAuthError(ref, tt.err)
+			if !tt.wantAuth {
+				assert.Equal(t, tt.err, wrapped)
+				return
+			}
+			require.ErrorIs(t, wrapped, ErrUnauthorized)
+			require.ErrorIs(t, wrapped, tt.err)
+			assert.Contains(t, wrapped.Error(), "registry authentication required to pull registry.example.com/private/catalog:latest: log in with `docker login registry.example.com`")
+		})
+	}
+}
+
+func TestSetRegistryAuthConfig(t *testing.T) {
+	t.Cleanup(func() { _ = SetRegistryAuthConfig("") })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{
+  "auths": {
+    "registry.example.com": {"auth": "cmVhZGVyOnMzY3JldA=="},
+    "https://index.docker.io/v1/": {"username": "hub-user", "password": "hub-token"}
+  }
+}`), 0o600))
+
+	resolve := func(ref string) authn.AuthConfig {
+		t.Helper()
+		parsed, err := name.ParseReference(ref)
+		require.NoError(t, err)
+		authenticator, err := Keychain().Resolve(parsed.Context())
+		require.NoError(t, err)
+		cfg, err := authenticator.Authorization()
+		require.NoError(t, err)
+		return *cfg
+	}
+
+	// The directory of the config works as well as the file.
+	for _, path := range []string{dir, filepath.Join(dir, "config.json")} {
+		require.NoError(t, SetRegistryAuthConfig(path))
+
+		assert.Equal(t, authn.AuthConfig{Username: "reader", Password: "s3cret"}, resolve("registry.example.com/private/catalog:latest"))
+		assert.Equal(t, authn.AuthConfig{Username: "hub-user", Password: "hub-token"}, resolve("myorg/private-catalog:latest"))
+		assert.Equal(t, authn.AuthConfig{}, resolve("ghcr.io/acme/catalog:latest"))
+	}
+
+	require.NoError(t, SetRegistryAuthConfig(""))
+	assert.Equal(t, authn.DefaultKeychain, Keychain())
+}
+
+func TestSetRegistryAuthConfigMissing(t *testing.T) {
+	t.Cleanup(func() { _ = SetRegistryAuthConfig("") })
+
+	err := SetRegistryAuthConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "reading registry auth config")
+	assert.Equal(t, authn.DefaultKeychain, Keychain())
+
+	err = SetRegistryAuthConfig(t.TempDir())
+	require.ErrorContains(t, err, "reading registry auth config")
+}
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 
@@ -166,7 +165,7 @@ func Import(registryURL string, ociRepository string, push bool) error {
 	firstRef := ociReferences[0]
 
 	// Verify the reference can be resolved
-	subjectDescriptor, err := remote.Get(firstRef, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithTransport(desktop.ProxyTransport()))
+	subjectDescriptor, err := remote.Get(firstRef, remote.WithAuthFromKeychain(Keychain()), remote.WithTransport(desktop.ProxyTransport()))
 	if err != nil {
 		return fmt.Errorf("failed to resolve reference %s: %w", firstRef.Name(), err)
 	}
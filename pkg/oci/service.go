@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/daemon"
@@ -19,6 +18,7 @@ type Service interface {
 	GetImageLabels(img v1.Image) (map[string]string, error)
 	GetLocalImage(ctx context.Context, ref name.Reference) (v1.Image, error)
 	GetRemoteImage(ctx context.Context, ref name.Reference) (v1.Image, error)
+	GetRemoteDigest(ctx context.Context, ref name.Reference) (string, error)
 }
 
 type service struct{}
@@ -51,7 +51,22 @@ func (s *service) GetLocalImage(ctx context.Context, ref name.Reference) (v1.Ima
 }
 
 func (s *service) GetRemoteImage(ctx context.Context, ref name.Reference) (v1.Image, error) {
-	return remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(Keychain()), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
+	if err != nil {
+		return nil, WrapAuthError(ref, err)
+	}
+	return img, nil
+}
+
+// GetRemoteDigest returns the digest of the manifest a reference points to in
+// the registry. For a multi-platform image, it's the digest of the index, not
+// the one of the image of the current platform.
+func (s *service) GetRemoteDigest(ctx context.Context, ref name.Reference) (string, error) {
+	desc, err := remote.Head(ref, remote.WithAuthFromKeychain(Keychain()), remote.WithContext(ctx), remote.WithTransport(desktop.ProxyTransport()))
+	if err != nil {
+		return "", WrapAuthError(ref, err)
+	}
+	return desc.Digest.String(), nil
 }
 
 func IsNoSuchImageError(err error) bool {
@@ -0,0 +1,50 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRemoteDigestOfMultiPlatformIndex(t *testing.T) {
+	amd64Digest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	arm64Digest := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	index := fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[`+
+		`{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":%q,"size":100,"platform":{"architecture":"amd64","os":"linux"}},`+
+		`{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":%q,"size":100,"platform":{"architecture":"arm64","os":"linux"}}]}`,
+		amd64Digest, arm64Digest)
+	indexDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(index)))
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/mcp/multi/manifests/latest" && strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json"):
+			w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+			w.Header().Set("Docker-Content-Digest", indexDigest)
+			w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(index))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer registry.Close()
+
+	ref, err := name.ParseReference(strings.TrimPrefix(registry.URL, "http://")+"/mcp/multi:latest", name.Insecure)
+	require.NoError(t, err)
+
+	digest, err := NewService().GetRemoteDigest(t.Context(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, indexDigest, digest)
+	assert.NotEqual(t, amd64Digest, digest)
+}
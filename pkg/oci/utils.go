@@ -1,9 +1,12 @@
 package oci
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/docker/mcp-gateway/pkg/registryapi"
 )
 
 func FullName(ref name.Reference) string {
@@ -41,6 +44,36 @@ func FullNameWithoutDigest(ref name.Reference) string {
 	return domain + strings.TrimPrefix(ref.Context().RepositoryStr(), "library/") + ":latest"
 }
 
+// NormalizeCatalogRef parses a catalog reference and returns it in the form
+// used as the catalog key in the database: Docker Hub's domain and "library/"
+// are stripped and the tag defaults to "latest", e.g.
+// docker.io/test/catalog becomes test/catalog:latest. References to other
+// registries keep their domain. Digests are rejected. References to the
+// community registry are not OCI references and are returned unchanged.
+func NormalizeCatalogRef(refStr string) (string, error) {
+	if IsCommunityRegistryRef(refStr) {
+		return refStr, nil
+	}
+	ref, err := name.ParseReference(refStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
+	}
+	if !IsValidInputReference(ref) {
+		return "", fmt.Errorf("reference %s must be a valid OCI reference without a digest", refStr)
+	}
+	return FullNameWithoutDigest(ref), nil
+}
+
+// IsCommunityRegistryRef reports whether refStr is the community MCP
+// registry, e.g. registry.modelcontextprotocol.io, rather than an OCI
+// catalog reference.
+func IsCommunityRegistryRef(refStr string) bool {
+	refStr = strings.TrimPrefix(refStr, "https://")
+	refStr = strings.TrimPrefix(refStr, "http://")
+	refStr = strings.TrimSuffix(refStr, "/")
+	return strings.EqualFold(refStr, strings.TrimPrefix(registryapi.CommunityRegistryBaseURL, "https://"))
+}
+
 func HasDigest(ref name.Reference) bool {
 	if _, ok := ref.(name.Digest); ok {
 		return true
@@ -238,3 +238,57 @@ func TestFullNameWithoutDigestWithMultiplePathComponents(t *testing.T) {
 
 	assert.Equal(t, "myregistry.example.com/org/team/project/image:tag", fullNameStr)
 }
+
+func TestNormalizeCatalogRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+	}{
+		{name: "tagged", ref: "test/catalog:v1", expected: "test/catalog:v1"},
+		{name: "no tag", ref: "test/catalog", expected: "test/catalog:latest"},
+		{name: "docker.io", ref: "docker.io/test/catalog:latest", expected: "test/catalog:latest"},
+		{name: "index.docker.io", ref: "index.docker.io/test/catalog:v1", expected: "test/catalog:v1"},
+		{name: "library", ref: "docker.io/library/catalog", expected: "catalog:latest"},
+		{name: "community registry catalog", ref: "mcp/community-registry:latest", expected: "mcp/community-registry:latest"},
+		{name: "other registry", ref: "registry.example.com/team/catalog", expected: "registry.example.com/team/catalog:latest"},
+		{name: "registry with port", ref: "localhost:5000/catalog:dev", expected: "localhost:5000/catalog:dev"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := NormalizeCatalogRef(tc.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, normalized)
+
+			// Normalizing is idempotent.
+			again, err := NormalizeCatalogRef(normalized)
+			require.NoError(t, err)
+			assert.Equal(t, normalized, again)
+		})
+	}
+}
+
+func TestNormalizeCatalogRefCommunityRegistry(t *testing.T) {
+	for _, ref := range []string{"registry.modelcontextprotocol.io", "https://registry.modelcontextprotocol.io", "https://registry.modelcontextprotocol.io/"} {
+		normalized, err := NormalizeCatalogRef(ref)
+		require.NoError(t, err)
+		assert.Equal(t, ref, normalized)
+	}
+}
+
+func TestIsCommunityRegistryRef(t *testing.T) {
+	assert.True(t, IsCommunityRegistryRef("registry.modelcontextprotocol.io"))
+	assert.True(t, IsCommunityRegistryRef("https://registry.modelcontextprotocol.io/"))
+	assert.True(t, IsCommunityRegistryRef("REGISTRY.modelcontextprotocol.io"))
+	assert.False(t, IsCommunityRegistryRef("mcp/community-registry:latest"))
+	assert.False(t, IsCommunityRegistryRef("registry.modelcontextprotocol.io.example.com"))
+}
+
+func TestNormalizeCatalogRefErrors(t *testing.T) {
+	_, err := NormalizeCatalogRef("Invalid Reference")
+	require.ErrorContains(t, err, "failed to parse oci-reference Invalid Reference")
+
+	_, err = NormalizeCatalogRef("test/catalog@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	require.ErrorContains(t, err, "must be a valid OCI reference without a digest")
+}
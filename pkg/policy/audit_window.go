@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuditWindow selects audit events by their timestamp, e.g. for --since and
+// --until options. A zero bound leaves that side of the window open.
+type AuditWindow struct {
+	// Since is the earliest timestamp included in the window.
+	Since time.Time
+	// Until is the latest timestamp included in the window.
+	Until time.Time
+}
+
+// NewAuditWindow parses since and until with ParseAuditTime. Either can be
+// empty to leave that side of the window open.
+func NewAuditWindow(since, until string, now time.Time) (AuditWindow, error) {
+	var window AuditWindow
+	var err error
+
+	if since != "" {
+		if window.Since, err = ParseAuditTime(since, now); err != nil {
+			return AuditWindow{}, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if until != "" {
+		if window.Until, err = ParseAuditTime(until, now); err != nil {
+			return AuditWindow{}, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	if !window.Since.IsZero() && !window.Until.IsZero() && window.Since.After(window.Until) {
+		return AuditWindow{}, fmt.Errorf("since (%s) is after until (%s)", window.Since.Format(time.RFC3339), window.Until.Format(time.RFC3339))
+	}
+
+	return window, nil
+}
+
+// ParseAuditTime parses an RFC3339 timestamp, e.g. 2025-01-02T15:04:05Z, or a
+// duration relative to now, e.g. 30m or 24h meaning that long ago.
+func ParseAuditTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp (e.g. 2025-01-02T15:04:05Z) nor a duration (e.g. 30m, 24h)", value)
+	}
+	if duration < 0 {
+		return time.Time{}, fmt.Errorf("duration %s must be positive", duration)
+	}
+	return now.Add(-duration), nil
+}
+
+// Contains reports whether the event's timestamp is in the window. Events
+// without a valid RFC3339 timestamp are only in a fully open window.
+func (w AuditWindow) Contains(event AuditEvent) bool {
+	if w.Since.IsZero() && w.Until.IsZero() {
+		return true
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+	if err != nil {
+		return false
+	}
+	if !w.Since.IsZero() && timestamp.Before(w.Since) {
+		return false
+	}
+	if !w.Until.IsZero() && timestamp.After(w.Until) {
+		return false
+	}
+	return true
+}
+
+// FilterAuditEvents returns the events in the window, in their original order.
+func FilterAuditEvents(events []AuditEvent, window AuditWindow) []AuditEvent {
+	var filtered []AuditEvent
+	for _, event := range events {
+		if window.Contains(event) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
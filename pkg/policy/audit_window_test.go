@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func syntheticAuditEvents() []AuditEvent {
+	return []AuditEvent{
+		{ToolName: "two-days-ago", Timestamp: "2025-05-30T12:00:00Z"},
+		{ToolName: "yesterday", Timestamp: "2025-05-31T12:00:00Z"},
+		{ToolName: "two-hours-ago", Timestamp: "2025-06-01T10:00:00Z"},
+		{ToolName: "ten-minutes-ago", Timestamp: "2025-06-01T11:50:00+00:00"},
+		{ToolName: "no-timestamp"},
+	}
+}
+
+func toolNames(events []AuditEvent) []string {
+	var names []string
+	for _, event := range events {
+		names = append(names, event.ToolName)
+	}
+	return names
+}
+
+func TestFilterAuditEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		since    string
+		until    string
+		expected []string
+	}{
+		{
+			name:     "open window",
+			expected: []string{"two-days-ago", "yesterday", "two-hours-ago", "ten-minutes-ago", "no-timestamp"},
+		},
+		{
+			name:     "relative since",
+			since:    "3h",
+			expected: []string{"two-hours-ago", "ten-minutes-ago"},
+		},
+		{
+			name:     "relative until",
+			until:    "1h",
+			expected: []string{"two-days-ago", "yesterday", "two-hours-ago"},
+		},
+		{
+			name:     "RFC3339 window with inclusive bounds",
+			since:    "2025-05-31T12:00:00Z",
+			until:    "2025-06-01T10:00:00Z",
+			expected: []string{"yesterday", "two-hours-ago"},
+		},
+		{
+			name:     "mixed window",
+			since:    "2025-05-31T00:00:00Z",
+			until:    "30m",
+			expected: []string{"yesterday", "two-hours-ago"},
+		},
+		{
+			name:  "empty window",
+			since: "1m",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			window, err := NewAuditWindow(tc.since, tc.until, auditNow)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, toolNames(FilterAuditEvents(syntheticAuditEvents(), window)))
+		})
+	}
+}
+
+func TestNewAuditWindowErrors(t *testing.T) {
+	_, err := NewAuditWindow("yesterday", "", auditNow)
+	require.ErrorContains(t, err, "invalid since")
+
+	_, err = NewAuditWindow("", "-1h", auditNow)
+	require.ErrorContains(t, err, "must be positive")
+
+	_, err = NewAuditWindow("1h", "2h", auditNow)
+	require.ErrorContains(t, err, "is after until")
+}
+
+func TestParseAuditTime(t *testing.T) {
+	parsed, err := ParseAuditTime("2025-05-31T08:30:00+02:00", auditNow)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(time.Date(2025, 5, 31, 6, 30, 0, 0, time.UTC)))
+
+	parsed, err = ParseAuditTime("90m", auditNow)
+	require.NoError(t, err)
+	assert.Equal(t, auditNow.Add(-90*time.Minute), parsed)
+}
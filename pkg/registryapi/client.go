@@ -3,6 +3,7 @@ package registryapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -26,6 +27,9 @@ func BuildServerURL(serverName, version string) string {
 type Client interface {
 	GetServer(ctx context.Context, url *ServerURL) (registryapi.ServerResponse, error)
 	GetServerVersions(ctx context.Context, url *ServerURL) (registryapi.ServerListResponse, error)
+	// ListServers lists the latest version of the servers of the registry at
+	// baseURL. Credentials in the URL are sent as an Authorization header:
+	// user:password with basic auth, a lone token as a bearer token.
 	ListServers(ctx context.Context, baseURL string, cursor string) ([]registryapi.ServerResponse, error)
 }
 
@@ -64,6 +68,11 @@ func (c *client) GetServer(ctx context.Context, url *ServerURL) (registryapi.Ser
 }
 
 func (c *client) ListServers(ctx context.Context, baseURL string, cursor string) ([]registryapi.ServerResponse, error) {
+	baseURL, authorize, err := splitCredentials(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	var all []registryapi.ServerResponse
 	for {
 		u := fmt.Sprintf("%s/v0/servers?version=latest&limit=100", baseURL)
@@ -75,6 +84,7 @@ func (c *client) ListServers(ctx context.Context, baseURL string, cursor string)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
+		authorize(req)
 
 		resp, err := c.client.Do(req)
 		if err != nil {
@@ -103,6 +113,30 @@ func (c *client) ListServers(ctx context.Context, baseURL string, cursor string)
 	return all, nil
 }
 
+// splitCredentials removes the credentials from a registry base URL, which
+// the transport would refuse, and returns a function adding them to requests
+// as an Authorization header instead.
+func splitCredentials(baseURL string) (string, func(*http.Request), error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		// Not wrapped, the URL may have credentials
+		return "", nil, errors.New("failed to parse registry URL")
+	}
+	user := parsedURL.User
+	if user == nil {
+		return baseURL, func(*http.Request) {}, nil
+	}
+
+	parsedURL.User = nil
+	return parsedURL.String(), func(req *http.Request) {
+		if password, ok := user.Password(); ok {
+			req.SetBasicAuth(user.Username(), password)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+user.Username())
+		}
+	}, nil
+}
+
 func (c *client) GetServerVersions(ctx context.Context, url *ServerURL) (registryapi.ServerListResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.VersionsListURL(), nil)
 	if err != nil {
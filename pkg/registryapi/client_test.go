@@ -0,0 +1,78 @@
+package registryapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	registryapi "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/stretchr/testify/require"
+)
+
+// selfHostedRegistry serves the servers of a registry under /custom/base/v0,
+// in pages of one server, and records the Authorization headers it got.
+func selfHostedRegistry(t *testing.T, names ...string) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom/base/v0/servers" {
+			http.NotFound(w, r)
+			return
+		}
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		index := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			index = len(strings.Split(cursor, ","))
+		}
+		response := registryapi.ServerListResponse{
+			Servers: []registryapi.ServerResponse{{Server: registryapi.ServerJSON{Name: names[index]}}},
+		}
+		if index+1 < len(names) {
+			response.Metadata.NextCursor = strings.Join(names[:index+1], ",")
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &authorizations
+}
+
+func TestListServersFromSelfHostedRegistry(t *testing.T) {
+	registry, authorizations := selfHostedRegistry(t, "io.example/a", "io.example/b")
+	c := &client{client: registry.Client()}
+
+	servers, err := c.ListServers(t.Context(), registry.URL+"/custom/base", "")
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	require.Equal(t, "io.example/a", servers[0].Server.Name)
+	require.Equal(t, "io.example/b", servers[1].Server.Name)
+	require.Equal(t, []string{"", ""}, *authorizations)
+}
+
+func TestListServersSendsCredentialsOfBaseURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials string
+		want        string
+	}{
+		{name: "basic auth", credentials: "reader:s3cret@", want: "Basic cmVhZGVyOnMzY3JldA=="},
+		{name: "bearer token", credentials: "t0ken@", want: "Bearer t0ken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, authorizations := selfHostedRegistry(t, "io.example/a", "io.example/b")
+			c := &client{client: registry.Client()}
+
+			baseURL := strings.Replace(registry.URL, "http://", "http://"+tt.credentials, 1) + "/custom/base"
+			servers, err := c.ListServers(t.Context(), baseURL, "")
+			require.NoError(t, err)
+			require.Len(t, servers, 2)
+			require.Equal(t, []string{tt.want, tt.want}, *authorizations)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package registryapi
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -135,3 +136,39 @@ func (r *ServerURL) WithVersion(version string) *ServerURL {
 		RawURL:     r.RawURL,
 	}
 }
+
+// ParseRegistryBaseURL parses the reference of a registry to list servers
+// from: a hostname, e.g. registry.modelcontextprotocol.io, or the base URL of
+// a self-hosted registry serving its API under <base>/v0, e.g.
+// https://mcp.example.com/registry. The URL can carry credentials, see
+// Client.ListServers. It returns the base URL, with its credentials, and the
+// reference without them, to be recorded or printed.
+func ParseRegistryBaseURL(ref string) (baseURL string, redacted string, err error) {
+	if !strings.Contains(ref, "://") {
+		host := strings.TrimSuffix(ref, "/")
+		if host == "" {
+			return "", "", fmt.Errorf("registry reference is empty")
+		}
+		return "https://" + host, host, nil
+	}
+
+	parsedURL, err := url.Parse(strings.TrimSuffix(ref, "/"))
+	if err != nil {
+		// Only the cause, the URL may have credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", "", fmt.Errorf("failed to parse registry URL: %w", err)
+	}
+	withoutCredentials := *parsedURL
+	withoutCredentials.User = nil
+	if err := remoteurl.DefaultValidator().ValidateURLWithoutResolution(&withoutCredentials); err != nil {
+		return "", "", err
+	}
+	if parsedURL.RawQuery != "" || parsedURL.Fragment != "" {
+		return "", "", fmt.Errorf("registry URL must not have a query or a fragment")
+	}
+
+	return parsedURL.String(), withoutCredentials.String(), nil
+}
@@ -371,6 +371,14 @@ func (t *validatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 	return t.base.RoundTrip(req.Clone(ctx))
 }
 
+// CloseIdleConnections closes the idle connections of the base transport, so
+// that http.Client.CloseIdleConnections works through the guard.
+func (t *validatingRoundTripper) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
 func allowInsecureFromEnv() bool {
 	return os.Getenv(AllowInsecureRemoteURLEnv) == "1" ||
 		strings.EqualFold(os.Getenv(AllowInsecureRemoteURLEnv), "true")
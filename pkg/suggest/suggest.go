@@ -0,0 +1,65 @@
+// Package suggest finds likely intended names for misspelled input.
+package suggest
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Closest returns up to limit candidates that are close enough to name to be
+// a likely typo, closest first. Comparison is case-insensitive and ties are
+// broken alphabetically so that suggestions are stable.
+func Closest(name string, candidates []string, limit int) []string {
+	maxDistance := max(2, len(name)/4)
+
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		distance := EditDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance <= maxDistance {
+			matches = append(matches, match{candidate: candidate, distance: distance})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), strings.Compare(a.candidate, b.candidate))
+	})
+
+	closest := make([]string, 0, min(limit, len(matches)))
+	for _, m := range matches {
+		if len(closest) == limit {
+			break
+		}
+		if !slices.Contains(closest, m.candidate) {
+			closest = append(closest, m.candidate)
+		}
+	}
+	return closest
+}
+
+// EditDistance computes the Levenshtein distance between a and b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
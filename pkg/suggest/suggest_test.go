@@ -0,0 +1,25 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"get_file", "get_files", "list_issues", "github", "gitlab"}
+
+	assert.Equal(t, []string{"get_file", "get_files"}, Closest("get_fle", candidates, 3))
+	assert.Equal(t, []string{"get_file"}, Closest("get_fle", candidates, 1))
+	assert.Equal(t, []string{"list_issues"}, Closest("List_Issue", candidates, 3))
+	assert.Equal(t, []string{"github", "gitlab"}, Closest("gitlub", candidates, 3))
+	assert.Empty(t, Closest("something_else", candidates, 3))
+	assert.Empty(t, Closest("get_file", nil, 3))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, EditDistance("tool", "tool"))
+	assert.Equal(t, 3, EditDistance("kitten", "sitting"))
+	assert.Equal(t, 4, EditDistance("", "tool"))
+	assert.Equal(t, 1, EditDistance("wörld", "world"))
+}
@@ -0,0 +1,111 @@
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// MaxToolNamesEnv limits the number of distinct mcp.tool.name values
+	// recorded on metrics. Once the limit is reached, the attribute is dropped
+	// for any tool name that wasn't seen before.
+	MaxToolNamesEnv = "DOCKER_MCP_TELEMETRY_MAX_TOOL_NAMES"
+
+	// HashClientNamesEnv replaces mcp.client.name on metrics with a short hash
+	// of the client name.
+	HashClientNamesEnv = "DOCKER_MCP_TELEMETRY_HASH_CLIENT_NAMES"
+)
+
+const (
+	toolNameKey   = attribute.Key("mcp.tool.name")
+	clientNameKey = attribute.Key("mcp.client.name")
+)
+
+// attributeLimiter bounds the cardinality of the metric attributes that
+// depend on user input.
+type attributeLimiter struct {
+	mu              sync.Mutex
+	maxToolNames    int
+	toolNames       map[string]struct{}
+	hashClientNames bool
+}
+
+// limiter is reset by Init. The zero value doesn't limit anything.
+var limiter = &attributeLimiter{}
+
+func newAttributeLimiterFromEnv() *attributeLimiter {
+	l := &attributeLimiter{
+		toolNames:       map[string]struct{}{},
+		hashClientNames: os.Getenv(HashClientNamesEnv) != "",
+	}
+
+	if value := os.Getenv(MaxToolNamesEnv); value != "" {
+		maxToolNames, err := strconv.Atoi(value)
+		if err != nil || maxToolNames < 0 {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Ignoring invalid %s=%q\n", MaxToolNamesEnv, value)
+		} else {
+			l.maxToolNames = maxToolNames
+		}
+	}
+
+	return l
+}
+
+// WithMetricAttributes is like metric.WithAttributes but drops or hashes the
+// high-cardinality attributes according to DOCKER_MCP_TELEMETRY_MAX_TOOL_NAMES
+// and DOCKER_MCP_TELEMETRY_HASH_CLIENT_NAMES.
+func WithMetricAttributes(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributes(limiter.limit(attrs)...)
+}
+
+func (l *attributeLimiter) limit(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if l.maxToolNames == 0 && !l.hashClientNames {
+		return attrs
+	}
+
+	limited := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch attr.Key {
+		case toolNameKey:
+			if !l.allowToolName(attr.Value.AsString()) {
+				continue
+			}
+		case clientNameKey:
+			if l.hashClientNames {
+				attr = clientNameKey.String(hashName(attr.Value.AsString()))
+			}
+		}
+		limited = append(limited, attr)
+	}
+	return limited
+}
+
+func (l *attributeLimiter) allowToolName(toolName string) bool {
+	if l.maxToolNames == 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, seen := l.toolNames[toolName]; seen {
+		return true
+	}
+	if len(l.toolNames) >= l.maxToolNames {
+		return false
+	}
+	l.toolNames[toolName] = struct{}{}
+	return true
+}
+
+func hashName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])[:12]
+}
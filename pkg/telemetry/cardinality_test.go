@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectSumDataPoints(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data.(metricdata.Sum[int64]).DataPoints
+			}
+		}
+	}
+
+	t.Fatalf("metric %s not found", name)
+	return nil
+}
+
+func TestMaxToolNamesDropsToolNameAttribute(t *testing.T) {
+	t.Setenv(MaxToolNamesEnv, "2")
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	RecordToolError(ctx, nil, "server", "stdio", "tool-a", "server-error")
+	RecordToolError(ctx, nil, "server", "stdio", "tool-b", "server-error")
+	RecordToolError(ctx, nil, "server", "stdio", "tool-c", "server-error")
+	RecordToolError(ctx, nil, "server", "stdio", "tool-a", "server-error")
+
+	dataPoints := collectSumDataPoints(t, metricReader, "mcp.tool.errors")
+	require.Len(t, dataPoints, 3)
+
+	counts := map[string]int64{}
+	for _, dp := range dataPoints {
+		toolName, found := dp.Attributes.Value(attribute.Key("mcp.tool.name"))
+		if !found {
+			counts[""] += dp.Value
+			continue
+		}
+		counts[toolName.AsString()] += dp.Value
+
+		serverName, found := dp.Attributes.Value(attribute.Key("mcp.server.name"))
+		assert.True(t, found)
+		assert.Equal(t, "server", serverName.AsString())
+	}
+	assert.Equal(t, map[string]int64{"tool-a": 2, "tool-b": 1, "": 1}, counts)
+}
+
+func TestHashClientNames(t *testing.T) {
+	t.Setenv(HashClientNamesEnv, "1")
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	RecordListTools(context.Background(), "my-client")
+
+	dataPoints := collectSumDataPoints(t, metricReader, "mcp.list.tools")
+	require.Len(t, dataPoints, 1)
+
+	clientName, found := dataPoints[0].Attributes.Value(attribute.Key("mcp.client.name"))
+	require.True(t, found)
+	assert.NotEqual(t, "my-client", clientName.AsString())
+	assert.Equal(t, hashName("my-client"), clientName.AsString())
+}
+
+func TestNoCardinalityLimitByDefault(t *testing.T) {
+	l := newAttributeLimiterFromEnv()
+	attrs := []attribute.KeyValue{
+		attribute.String("mcp.tool.name", "tool"),
+		attribute.String("mcp.client.name", "client"),
+	}
+
+	assert.Equal(t, attrs, l.limit(attrs))
+}
@@ -0,0 +1,194 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Kinds of metrics in a snapshot.
+const (
+	MetricKindCounter       = "counter"
+	MetricKindUpDownCounter = "upDownCounter"
+	MetricKindGauge         = "gauge"
+	MetricKindHistogram     = "histogram"
+)
+
+// MetricsSnapshot is the value of every metric at a point in time.
+type MetricsSnapshot struct {
+	Time    time.Time        `json:"time"`
+	Metrics []MetricSnapshot `json:"metrics"`
+}
+
+// MetricSnapshot is the value of a metric, with one point per set of
+// attributes it was recorded with.
+type MetricSnapshot struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Unit        string          `json:"unit,omitempty"`
+	Kind        string          `json:"kind"`
+	Points      []PointSnapshot `json:"points"`
+}
+
+// PointSnapshot is the value of a metric for a set of attributes. Counters and
+// gauges have a Value, histograms a Histogram summary.
+type PointSnapshot struct {
+	Attributes map[string]any     `json:"attributes,omitempty"`
+	Value      any                `json:"value,omitempty"`
+	Histogram  *HistogramSnapshot `json:"histogram,omitempty"`
+}
+
+// HistogramSnapshot summarizes the values recorded by a histogram.
+type HistogramSnapshot struct {
+	Count uint64   `json:"count"`
+	Sum   float64  `json:"sum"`
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+}
+
+// MetricsSnapshotter collects the metrics of the gateway in memory so that
+// they can be dumped at once, e.g. on exit of a CI or benchmark run.
+type MetricsSnapshotter struct {
+	reader *sdkmetric.ManualReader
+}
+
+// NewMetricsSnapshotter replaces the global meter provider with one whose
+// metrics are collected by the snapshotter. The metrics are then no longer
+// exported through the provider set by the Docker CLI. It must be called
+// before Init.
+func NewMetricsSnapshotter() *MetricsSnapshotter {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return &MetricsSnapshotter{reader: reader}
+}
+
+// Snapshot returns the current value of every metric, sorted by name.
+func (s *MetricsSnapshotter) Snapshot(ctx context.Context) (MetricsSnapshot, error) {
+	var rm metricdata.ResourceMetrics
+	if err := s.reader.Collect(ctx, &rm); err != nil {
+		return MetricsSnapshot{}, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+
+	snapshot := MetricsSnapshot{
+		Time:    time.Now().UTC(),
+		Metrics: []MetricSnapshot{},
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			kind, points := snapshotPoints(m.Data)
+			if kind == "" {
+				continue
+			}
+			snapshot.Metrics = append(snapshot.Metrics, MetricSnapshot{
+				Name:        m.Name,
+				Description: m.Description,
+				Unit:        m.Unit,
+				Kind:        kind,
+				Points:      points,
+			})
+		}
+	}
+	sort.SliceStable(snapshot.Metrics, func(i, j int) bool {
+		return snapshot.Metrics[i].Name < snapshot.Metrics[j].Name
+	})
+
+	return snapshot, nil
+}
+
+// WriteFile writes the current value of every metric to path as JSON.
+func (s *MetricsSnapshotter) WriteFile(ctx context.Context, path string) error {
+	snapshot, err := s.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+func snapshotPoints(data metricdata.Aggregation) (string, []PointSnapshot) {
+	switch data := data.(type) {
+	case metricdata.Sum[int64]:
+		return sumKind(data.IsMonotonic), valuePoints(data.DataPoints)
+	case metricdata.Sum[float64]:
+		return sumKind(data.IsMonotonic), valuePoints(data.DataPoints)
+	case metricdata.Gauge[int64]:
+		return MetricKindGauge, valuePoints(data.DataPoints)
+	case metricdata.Gauge[float64]:
+		return MetricKindGauge, valuePoints(data.DataPoints)
+	case metricdata.Histogram[int64]:
+		return MetricKindHistogram, histogramPoints(data.DataPoints)
+	case metricdata.Histogram[float64]:
+		return MetricKindHistogram, histogramPoints(data.DataPoints)
+	default:
+		return "", nil
+	}
+}
+
+func sumKind(monotonic bool) string {
+	if monotonic {
+		return MetricKindCounter
+	}
+	return MetricKindUpDownCounter
+}
+
+func valuePoints[N int64 | float64](dataPoints []metricdata.DataPoint[N]) []PointSnapshot {
+	points := make([]PointSnapshot, len(dataPoints))
+	for i, dp := range dataPoints {
+		points[i] = PointSnapshot{
+			Attributes: snapshotAttributes(dp.Attributes),
+			Value:      dp.Value,
+		}
+	}
+	return points
+}
+
+func histogramPoints[N int64 | float64](dataPoints []metricdata.HistogramDataPoint[N]) []PointSnapshot {
+	points := make([]PointSnapshot, len(dataPoints))
+	for i, dp := range dataPoints {
+		histogram := &HistogramSnapshot{
+			Count: dp.Count,
+			Sum:   float64(dp.Sum),
+		}
+		if v, ok := dp.Min.Value(); ok {
+			histogram.Min = ptr(float64(v))
+		}
+		if v, ok := dp.Max.Value(); ok {
+			histogram.Max = ptr(float64(v))
+		}
+		points[i] = PointSnapshot{
+			Attributes: snapshotAttributes(dp.Attributes),
+			Histogram:  histogram,
+		}
+	}
+	return points
+}
+
+func snapshotAttributes(set attribute.Set) map[string]any {
+	if set.Len() == 0 {
+		return nil
+	}
+	attrs := make(map[string]any, set.Len())
+	for _, kv := range set.ToSlice() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return attrs
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
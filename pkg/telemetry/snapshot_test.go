@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestMetricsSnapshot(t *testing.T) {
+	snapshotter := NewMetricsSnapshotter()
+	t.Cleanup(func() {
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider())
+	})
+	Init()
+
+	ctx := t.Context()
+	RecordGatewayStart(ctx, "stdio", "")
+	RecordListTools(ctx, "claude")
+	RecordListTools(ctx, "claude")
+	RecordListTools(ctx, "cursor")
+	RecordToolList(ctx, "github", 12)
+	RecordCatalogOperation(ctx, "pull", "docker-mcp", 100, true)
+	RecordCatalogOperation(ctx, "pull", "docker-mcp", 300, true)
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	require.NoError(t, snapshotter.WriteFile(ctx, path))
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var snapshot MetricsSnapshot
+	require.NoError(t, json.Unmarshal(buf, &snapshot))
+
+	metrics := make(map[string]MetricSnapshot)
+	for _, m := range snapshot.Metrics {
+		metrics[m.Name] = m
+	}
+
+	gatewayStarts := metrics["mcp.gateway.starts"]
+	assert.Equal(t, MetricKindCounter, gatewayStarts.Kind)
+	require.Len(t, gatewayStarts.Points, 1)
+	assert.InDelta(t, 1, gatewayStarts.Points[0].Value, 0)
+	assert.Equal(t, map[string]any{"mcp.gateway.transport": "stdio"}, gatewayStarts.Points[0].Attributes)
+
+	listTools := make(map[any]any)
+	for _, point := range metrics["mcp.list.tools"].Points {
+		listTools[point.Attributes["mcp.client.name"]] = point.Value
+	}
+	assert.Equal(t, map[any]any{"claude": float64(2), "cursor": float64(1)}, listTools)
+
+	toolsDiscovered := metrics["mcp.tools.discovered"]
+	assert.Equal(t, MetricKindGauge, toolsDiscovered.Kind)
+	require.Len(t, toolsDiscovered.Points, 1)
+	assert.InDelta(t, 12, toolsDiscovered.Points[0].Value, 0)
+
+	catalogDuration := metrics["mcp.catalog.operation.duration"]
+	assert.Equal(t, MetricKindHistogram, catalogDuration.Kind)
+	require.Len(t, catalogDuration.Points, 1)
+	histogram := catalogDuration.Points[0].Histogram
+	require.NotNil(t, histogram)
+	assert.Equal(t, uint64(2), histogram.Count)
+	assert.InDelta(t, 400, histogram.Sum, 0)
+	assert.InDelta(t, 100, *histogram.Min, 0)
+	assert.InDelta(t, 300, *histogram.Max, 0)
+}
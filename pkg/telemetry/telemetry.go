@@ -83,6 +83,10 @@ var (
 
 	// Profile template usage metrics
 	TemplateUsageCounter metric.Int64Counter
+
+	// ServerCrashLoopCounter tracks servers marked unavailable after failing
+	// to start too many times in a row
+	ServerCrashLoopCounter metric.Int64Counter
 )
 
 // Init initializes the telemetry package with global providers
@@ -93,6 +97,9 @@ func Init() {
 	// Get meter from global provider (set by Docker CLI)
 	meter = otel.GetMeterProvider().Meter(MeterName)
 
+	// Bound the cardinality of tool and client name attributes on metrics
+	limiter = newAttributeLimiterFromEnv()
+
 	// Debug logging to stderr - remove in production
 	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Init called\n")
@@ -390,6 +397,16 @@ func Init() {
 		}
 	}
 
+	ServerCrashLoopCounter, err = meter.Int64Counter("mcp.server.crash_loops",
+		metric.WithDescription("Number of servers marked unavailable after failing to start too many times in a row"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating server crash loop counter: %v\n", err)
+		}
+	}
+
 	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Metrics created successfully\n")
 	}
@@ -421,8 +438,10 @@ func StartCommandSpan(ctx context.Context, commandPath string, attrs ...attribut
 		trace.WithSpanKind(trace.SpanKindServer))
 }
 
-// RecordToolError records a tool error with appropriate attributes
-func RecordToolError(ctx context.Context, span trace.Span, serverName, serverType, toolName string) {
+// RecordToolError records a tool error with appropriate attributes. The
+// errorType classifies the failure, e.g. timeout or server-crash, and is
+// recorded as the error.type attribute.
+func RecordToolError(ctx context.Context, span trace.Span, serverName, serverType, toolName, errorType string) {
 	if ToolErrorCounter == nil {
 		return // Telemetry not initialized
 	}
@@ -432,14 +451,16 @@ func RecordToolError(ctx context.Context, span trace.Span, serverName, serverTyp
 		span.RecordError(nil, trace.WithAttributes(
 			attribute.String("mcp.server.name", serverName),
 			attribute.String("mcp.server.type", serverType),
+			attribute.String("error.type", errorType),
 		))
 	}
 
 	ToolErrorCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.tool.name", toolName),
 			attribute.String("mcp.server.name", serverName),
 			attribute.String("mcp.server.type", serverType),
+			attribute.String("error.type", errorType),
 		))
 }
 
@@ -545,7 +566,7 @@ func RecordInitialize(ctx context.Context, params *mcp.InitializeParams) {
 	}
 
 	InitializeCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.client.name", params.ClientInfo.Name),
 			attribute.String("mcp.client.version", params.ClientInfo.Version),
 		))
@@ -565,7 +586,7 @@ func RecordListTools(ctx context.Context, clientName string) {
 	}
 
 	ListToolsCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.client.name", clientName),
 		))
 
@@ -660,7 +681,7 @@ func RecordPromptGet(ctx context.Context, promptName string, serverName string,
 	}
 
 	PromptGetCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.prompt.name", promptName),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -679,7 +700,7 @@ func RecordPromptDuration(ctx context.Context, promptName string, serverName str
 	}
 
 	PromptDuration.Record(ctx, durationMs,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.prompt.name", promptName),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -736,7 +757,7 @@ func RecordListPrompts(ctx context.Context, clientName string) {
 	}
 
 	ListPromptsCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.client.name", clientName),
 		))
 
@@ -756,7 +777,7 @@ func RecordListResources(ctx context.Context, clientName string) {
 	}
 
 	ListResourcesCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.client.name", clientName),
 		))
 }
@@ -772,7 +793,7 @@ func RecordResourceRead(ctx context.Context, resourceURI string, serverName stri
 	}
 
 	ResourceReadCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.resource.uri", resourceURI),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -791,7 +812,7 @@ func RecordResourceDuration(ctx context.Context, resourceURI string, serverName
 	}
 
 	ResourceDuration.Record(ctx, durationMs,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.resource.uri", resourceURI),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -845,7 +866,7 @@ func RecordListResourceTemplates(ctx context.Context, clientName string) {
 	}
 
 	ListResourceTemplatesCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.client.name", clientName),
 		))
 }
@@ -861,7 +882,7 @@ func RecordResourceTemplateRead(ctx context.Context, uriTemplate string, serverN
 	}
 
 	ResourceTemplateReadCounter.Add(ctx, 1,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.resource_template.uri", uriTemplate),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -880,7 +901,7 @@ func RecordResourceTemplateDuration(ctx context.Context, uriTemplate string, ser
 	}
 
 	ResourceTemplateDuration.Record(ctx, durationMs,
-		metric.WithAttributes(
+		WithMetricAttributes(
 			attribute.String("mcp.resource_template.uri", uriTemplate),
 			attribute.String("mcp.server.origin", serverName),
 			attribute.String("mcp.client.name", clientName),
@@ -940,3 +961,21 @@ func RecordTemplateUsage(ctx context.Context, templateID string, source string)
 			attribute.String("mcp.template.source", source),
 		))
 }
+
+// RecordServerCrashLoop records a server marked unavailable after failing to
+// start failures times in a row.
+func RecordServerCrashLoop(ctx context.Context, serverName string, failures int) {
+	if ServerCrashLoopCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Server %s is crash looping after %d failures\n", serverName, failures)
+	}
+
+	ServerCrashLoopCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+			attribute.Int("mcp.server.failures", failures),
+		))
+}
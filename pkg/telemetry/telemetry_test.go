@@ -295,7 +295,7 @@ func TestRecordToolError(t *testing.T) {
 	serverType := "docker"
 
 	// Record a tool error (nil span is ok for testing)
-	RecordToolError(ctx, nil, serverName, serverType, toolName)
+	RecordToolError(ctx, nil, serverName, serverType, toolName, "timeout")
 
 	// Collect metrics
 	var rm metricdata.ResourceMetrics
@@ -322,12 +322,44 @@ func TestRecordToolError(t *testing.T) {
 
 				serverTypeAttr, _ := attrs.Value(attribute.Key("mcp.server.type"))
 				assert.Equal(t, serverType, serverTypeAttr.AsString())
+
+				errorTypeAttr, _ := attrs.Value(attribute.Key("error.type"))
+				assert.Equal(t, "timeout", errorTypeAttr.AsString())
 			}
 		}
 	}
 	assert.True(t, found, "tool error should be recorded")
 }
 
+func TestRecordServerCrashLoop(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+	ctx := t.Context()
+
+	RecordServerCrashLoop(ctx, "crashing", 5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metricReader.Collect(ctx, &rm))
+
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "mcp.server.crash_loops" {
+				found = true
+				sum := m.Data.(metricdata.Sum[int64])
+				assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+				attrs := sum.DataPoints[0].Attributes
+				serverNameAttr, _ := attrs.Value(attribute.Key("mcp.server.name"))
+				assert.Equal(t, "crashing", serverNameAttr.AsString())
+				failuresAttr, _ := attrs.Value(attribute.Key("mcp.server.failures"))
+				assert.Equal(t, int64(5), failuresAttr.AsInt64())
+			}
+		}
+	}
+	assert.True(t, found, "crash loop should be recorded")
+}
+
 func TestConcurrentMetricRecording(t *testing.T) {
 	_, metricReader := setupTestTelemetry(t)
 	Init()
@@ -9,6 +9,7 @@ import (
 	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
 // EnsureCatalogExists checks whether the Docker MCP catalog is available
@@ -25,7 +26,7 @@ func EnsureCatalogExists(ctx context.Context, dao db.DAO, ociService oci.Service
 	}
 
 	fmt.Println("Pulling Docker MCP catalog...")
-	if err := catalognext.Pull(ctx, dao, ociService, DefaultCatalogRef); err != nil {
+	if err := catalognext.Pull(ctx, dao, ociService, DefaultCatalogRef, workingset.Output{}); err != nil {
 		return fmt.Errorf("failed to pull catalog %s: %w", DefaultCatalogRef, err)
 	}
 
@@ -0,0 +1,342 @@
+package workingset
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// CurrentBundleVersion is the version of the bundles written by ExportBundle.
+const CurrentBundleVersion = 1
+
+const (
+	bundleManifestFile = "manifest.json"
+	bundleProfileFile  = "profile.json"
+	bundleCatalogsDir  = "catalogs"
+	// maxBundleFileSize bounds the size of each file read from a bundle.
+	maxBundleFileSize = 64 << 20
+)
+
+// BundleManifest describes the content of a bundle.
+type BundleManifest struct {
+	Version  int      `json:"version"`
+	Profile  string   `json:"profile"`
+	Catalogs []string `json:"catalogs,omitempty"`
+	// GatewayArgs are the arguments to run the gateway with, e.g.
+	// --long-lived, after --profile.
+	GatewayArgs []string `json:"gatewayArgs,omitempty"`
+}
+
+// BundleOptions configures what ExportBundle adds to a bundle besides the
+// profile.
+type BundleOptions struct {
+	// Catalogs are the references of the catalogs to add. When empty, the
+	// catalogs the servers of the profile were added from are added. The
+	// base catalogs of the added catalogs are always added too.
+	Catalogs []string
+	// GatewayArgs are the arguments to run the gateway with.
+	GatewayArgs []string
+}
+
+// bundleCatalog is a catalog as stored in a bundle.
+type bundleCatalog struct {
+	Ref     string             `json:"ref"`
+	Digest  string             `json:"digest,omitempty"`
+	Title   string             `json:"title"`
+	Source  string             `json:"source,omitempty"`
+	Base    string             `json:"base,omitempty"`
+	Servers []db.CatalogServer `json:"servers"`
+}
+
+// ExportBundle writes a tar bundle with a profile, its catalogs and the
+// options to run the gateway with, to move a setup to another machine. Config
+// values that look like secrets are left out. Secret values are never part of
+// a profile.
+func ExportBundle(ctx context.Context, dao db.DAO, id string, filename string, opts BundleOptions) error {
+	dbSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+	workingSet := NewFromDb(dbSet)
+	for i := range workingSet.Servers {
+		workingSet.Servers[i].Config = withoutSecretLikeConfig(workingSet.Servers[i].Config)
+	}
+
+	catalogRefs := opts.Catalogs
+	if len(catalogRefs) == 0 {
+		catalogRefs = profileCatalogRefs(ctx, dao, workingSet)
+	}
+	catalogRefs = slices.Clone(catalogRefs)
+	catalogs := make([]bundleCatalog, 0, len(catalogRefs))
+	// The base catalogs are appended to catalogRefs as they are found, so
+	// that the bases of the bases are added too.
+	for i := 0; i < len(catalogRefs); i++ {
+		ref := catalogRefs[i]
+		dbCatalog, err := dao.GetCatalog(ctx, ref)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("catalog %s not found", ref)
+			}
+			return fmt.Errorf("failed to get catalog %s: %w", ref, err)
+		}
+		catalogs = append(catalogs, newBundleCatalog(dbCatalog))
+		if dbCatalog.Base != "" && !slices.Contains(catalogRefs, dbCatalog.Base) {
+			catalogRefs = append(catalogRefs, dbCatalog.Base)
+		}
+	}
+
+	manifest := BundleManifest{
+		Version:     CurrentBundleVersion,
+		Profile:     workingSet.ID,
+		Catalogs:    catalogRefs,
+		GatewayArgs: opts.GatewayArgs,
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	if err := writeBundleFile(tw, bundleManifestFile, manifest); err != nil {
+		return err
+	}
+	if err := writeBundleFile(tw, bundleProfileFile, workingSet); err != nil {
+		return err
+	}
+	for i, bundled := range catalogs {
+		if err := writeBundleFile(tw, bundleCatalogFile(i), bundled); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("Exported profile %s with %d catalog(s) to %s\n", id, len(catalogs), filename)
+
+	return nil
+}
+
+// ImportBundle restores the catalogs and the profile of a bundle written by
+// ExportBundle, all at once or not at all, and prints how to run the gateway
+// with the bundle's options. The profile with the same ID is replaced. A
+// catalog with the same reference as one of the bundle but a different
+// content is only replaced with replaceCatalogs, otherwise the import fails.
+func ImportBundle(ctx context.Context, dao db.DAO, ociService oci.Service, filename string, replaceCatalogs bool) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer f.Close()
+
+	files, err := readBundleFiles(f)
+	if err != nil {
+		return err
+	}
+
+	var manifest BundleManifest
+	if err := readBundleFile(files, bundleManifestFile, &manifest); err != nil {
+		return err
+	}
+	if manifest.Version != CurrentBundleVersion {
+		return fmt.Errorf("unsupported bundle version %d", manifest.Version)
+	}
+	var workingSet WorkingSet
+	if err := readBundleFile(files, bundleProfileFile, &workingSet); err != nil {
+		return err
+	}
+	catalogs := make([]bundleCatalog, len(manifest.Catalogs))
+	for i := range manifest.Catalogs {
+		if err := readBundleFile(files, bundleCatalogFile(i), &catalogs[i]); err != nil {
+			return err
+		}
+	}
+
+	dbCatalogs := make([]db.Catalog, 0, len(catalogs))
+	for _, bundled := range catalogs {
+		if !replaceCatalogs {
+			if err := checkCatalogConflict(ctx, dao, bundled); err != nil {
+				return err
+			}
+		}
+		dbCatalogs = append(dbCatalogs, bundled.toDb())
+	}
+	if err := saveImported(ctx, dao, ociService, workingSet, dbCatalogs...); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported profile %s with %d catalog(s)\n", workingSet.ID, len(catalogs))
+	fmt.Printf("Run the gateway with: %s\n", strings.Join(append([]string{"docker", "mcp", "gateway", "run", "--profile", workingSet.ID}, manifest.GatewayArgs...), " "))
+
+	return nil
+}
+
+// checkCatalogConflict fails when a catalog with the same reference as a
+// bundled catalog but a different content is in the database.
+func checkCatalogConflict(ctx context.Context, dao db.DAO, bundled bundleCatalog) error {
+	existing, err := dao.GetCatalog(ctx, bundled.Ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get catalog %s: %w", bundled.Ref, err)
+	}
+
+	existingBuf, err := json.Marshal(newBundleCatalog(existing))
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog %s: %w", bundled.Ref, err)
+	}
+	bundledBuf, err := json.Marshal(bundled)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog %s: %w", bundled.Ref, err)
+	}
+	if !bytes.Equal(existingBuf, bundledBuf) {
+		return fmt.Errorf("catalog %s already exists with a different content: import with --replace-catalogs to replace it", bundled.Ref)
+	}
+	return nil
+}
+
+// profileCatalogRefs returns the catalogs the servers of a profile were added
+// from, skipping the ones that are no longer in the database.
+func profileCatalogRefs(ctx context.Context, dao db.DAO, workingSet WorkingSet) []string {
+	var refs []string
+	for _, server := range workingSet.Servers {
+		if server.CatalogRef == "" || slices.Contains(refs, server.CatalogRef) {
+			continue
+		}
+		if _, err := dao.GetCatalog(ctx, server.CatalogRef); err != nil {
+			continue
+		}
+		refs = append(refs, server.CatalogRef)
+	}
+	return refs
+}
+
+// withoutSecretLikeConfig returns a copy of config without the values whose
+// names look like secrets, at any depth.
+func withoutSecretLikeConfig(config map[string]any) map[string]any {
+	if config == nil {
+		return nil
+	}
+	result := make(map[string]any, len(config))
+	for key, value := range config {
+		if catalog.LooksLikeSecret(key) {
+			continue
+		}
+		if sub, ok := value.(map[string]any); ok {
+			value = withoutSecretLikeConfig(sub)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func newBundleCatalog(dbCatalog *db.Catalog) bundleCatalog {
+	servers := make([]db.CatalogServer, len(dbCatalog.Servers))
+	for i, server := range dbCatalog.Servers {
+		server.ID = nil
+		server.CatalogRef = ""
+		servers[i] = server
+	}
+	return bundleCatalog{
+		Ref:     dbCatalog.Ref,
+		Digest:  dbCatalog.Digest,
+		Title:   dbCatalog.Title,
+		Source:  dbCatalog.Source,
+		Base:    dbCatalog.Base,
+		Servers: servers,
+	}
+}
+
+func (c bundleCatalog) toDb() db.Catalog {
+	return db.Catalog{
+		Ref:     c.Ref,
+		Digest:  c.Digest,
+		Title:   c.Title,
+		Source:  c.Source,
+		Base:    c.Base,
+		Servers: c.Servers,
+	}
+}
+
+func bundleCatalogFile(i int) string {
+	return path.Join(bundleCatalogsDir, fmt.Sprintf("%d.json", i))
+}
+
+func writeBundleFile(tw *tar.Writer, name string, value any) error {
+	buf, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(buf)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(buf); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// readBundleFiles reads the regular files of a bundle, indexed by name.
+func readBundleFiles(r io.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxBundleFileSize {
+			return nil, fmt.Errorf("file %s of the bundle is too large", header.Name)
+		}
+		buf, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = buf
+	}
+}
+
+func readBundleFile(files map[string][]byte, name string, value any) error {
+	buf, ok := files[name]
+	if !ok {
+		return fmt.Errorf("invalid bundle: %s is missing", name)
+	}
+	if err := json.Unmarshal(buf, value); err != nil {
+		return fmt.Errorf("invalid bundle: failed to unmarshal %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package workingset
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestExportImportBundleRoundTrip(t *testing.T) {
+	source := setupTestDB(t)
+	ctx := t.Context()
+
+	longLived := true
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{
+		Ref:    "acme/tools:latest",
+		Title:  "Acme Tools",
+		Source: "user:cli",
+		Servers: []db.CatalogServer{
+			{
+				ServerType:        string(ServerTypeImage),
+				Image:             "myimage:latest",
+				LongLivedOverride: &longLived,
+				AddedFrom:         "docker://myimage:latest",
+				Tools:             db.ToolList{"search"},
+				Snapshot:          &db.ServerSnapshot{Server: catalog.Server{Name: "my-image", Type: "server", Image: "myimage:latest"}},
+			},
+		},
+	}))
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{Ref: "acme/unrelated:latest", Title: "Unrelated"}))
+	require.NoError(t, source.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{
+			{
+				Type:       string(ServerTypeImage),
+				Image:      "myimage:latest",
+				CatalogRef: "acme/tools:latest",
+				Config:     map[string]any{"url": "https://example.com", "api_key": "s3cret", "auth": map[string]any{"password": "hunter2", "user": "me"}},
+				Secrets:    "default",
+				Tools:      []string{"search"},
+				Snapshot:   &db.ServerSnapshot{Server: catalog.Server{Name: "my-image", Type: "server", Image: "myimage:latest"}},
+			},
+		},
+		Secrets: db.SecretMap{"default": {Provider: string(SecretProviderDockerDesktop)}},
+	}))
+
+	bundle := filepath.Join(t.TempDir(), "setup.tar")
+	captureStdout(func() {
+		require.NoError(t, ExportBundle(ctx, source, "dev", bundle, BundleOptions{GatewayArgs: []string{"--long-lived", "--log-calls"}}))
+	})
+
+	// Secret-like config values don't leave the machine.
+	raw, err := os.ReadFile(bundle)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "s3cret")
+	assert.NotContains(t, string(raw), "hunter2")
+
+	target := setupTestDB(t)
+	output := captureStdout(func() {
+		require.NoError(t, ImportBundle(ctx, target, getMockOciService(), bundle, false))
+	})
+	assert.Contains(t, output, "Imported profile dev with 1 catalog(s)")
+	assert.Contains(t, output, "Run the gateway with: docker mcp gateway run --profile dev --long-lived --log-calls")
+
+	exported, err := source.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	imported, err := target.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	expected := NewFromDb(exported)
+	expected.Servers[0].Config = map[string]any{"url": "https://example.com", "auth": map[string]any{"user": "me"}}
+	expected.Origin = OriginImported
+	assert.Equal(t, expected, NewFromDb(imported))
+
+	catalogs, err := target.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	exportedCatalog, err := source.GetCatalog(ctx, "acme/tools:latest")
+	require.NoError(t, err)
+	importedCatalog, err := target.GetCatalog(ctx, "acme/tools:latest")
+	require.NoError(t, err)
+	for _, c := range []*db.Catalog{exportedCatalog, importedCatalog} {
+		c.LastUpdated = nil
+		for i := range c.Servers {
+			c.Servers[i].ID = nil
+		}
+	}
+	assert.Equal(t, exportedCatalog, importedCatalog)
+}
+
+func TestExportBundleWithMissingCatalog(t *testing.T) {
+	dao := setupTestDB(t)
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{ID: "dev", Name: "Dev", Secrets: db.SecretMap{}}))
+
+	err := ExportBundle(t.Context(), dao, "dev", filepath.Join(t.TempDir(), "setup.tar"), BundleOptions{Catalogs: []string{"acme/missing:latest"}})
+	require.EqualError(t, err, "catalog acme/missing:latest not found")
+}
+
+func TestImportBundleRejectsIncompleteBundle(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "setup.tar")
+	f, err := os.Create(bundle)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	require.NoError(t, writeBundleFile(tw, bundleManifestFile, BundleManifest{Version: CurrentBundleVersion, Profile: "dev", Catalogs: []string{"acme/tools:latest"}}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	dao := setupTestDB(t)
+	err = ImportBundle(t.Context(), dao, getMockOciService(), bundle, false)
+	require.EqualError(t, err, "invalid bundle: profile.json is missing")
+
+	workingSets, err := dao.ListWorkingSets(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, workingSets)
+}
+
+func exportTestBundle(t *testing.T, dao db.DAO, opts BundleOptions) string {
+	t.Helper()
+
+	bundle := filepath.Join(t.TempDir(), "setup.tar")
+	captureStdout(func() {
+		require.NoError(t, ExportBundle(t.Context(), dao, "dev", bundle, opts))
+	})
+	return bundle
+}
+
+func TestExportBundleAddsBaseCatalogs(t *testing.T) {
+	source := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{Ref: "acme/root:latest", Title: "Root"}))
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{Ref: "acme/base:latest", Title: "Base", Base: "acme/root:latest"}))
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{Ref: "acme/team:latest", Title: "Team", Base: "acme/base:latest"}))
+	require.NoError(t, source.CreateWorkingSet(ctx, db.WorkingSet{ID: "dev", Name: "Dev", Secrets: db.SecretMap{}}))
+
+	bundle := exportTestBundle(t, source, BundleOptions{Catalogs: []string{"acme/team:latest"}})
+
+	target := setupTestDB(t)
+	output := captureStdout(func() {
+		require.NoError(t, ImportBundle(ctx, target, getMockOciService(), bundle, false))
+	})
+	assert.Contains(t, output, "Imported profile dev with 3 catalog(s)")
+
+	team, err := target.GetCatalog(ctx, "acme/team:latest")
+	require.NoError(t, err)
+	_, err = db.ResolveCatalogBase(ctx, target, team)
+	require.NoError(t, err)
+}
+
+func TestImportBundleDoesNotOverwriteChangedCatalogs(t *testing.T) {
+	source := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, source.UpsertCatalog(ctx, db.Catalog{Ref: "acme/tools:latest", Title: "Acme Tools"}))
+	require.NoError(t, source.CreateWorkingSet(ctx, db.WorkingSet{ID: "dev", Name: "Dev", Secrets: db.SecretMap{}}))
+	bundle := exportTestBundle(t, source, BundleOptions{Catalogs: []string{"acme/tools:latest"}})
+
+	// Importing the same catalog again is fine.
+	target := setupTestDB(t)
+	captureStdout(func() {
+		require.NoError(t, ImportBundle(ctx, target, getMockOciService(), bundle, false))
+		require.NoError(t, ImportBundle(ctx, target, getMockOciService(), bundle, false))
+	})
+
+	require.NoError(t, target.UpsertCatalog(ctx, db.Catalog{Ref: "acme/tools:latest", Title: "Local Tools"}))
+	require.NoError(t, target.RemoveWorkingSet(ctx, "dev"))
+
+	err := ImportBundle(ctx, target, getMockOciService(), bundle, false)
+	require.EqualError(t, err, "catalog acme/tools:latest already exists with a different content: import with --replace-catalogs to replace it")
+
+	// Nothing was imported.
+	existing, err := target.GetCatalog(ctx, "acme/tools:latest")
+	require.NoError(t, err)
+	assert.Equal(t, "Local Tools", existing.Title)
+	workingSets, err := target.ListWorkingSets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, workingSets)
+
+	captureStdout(func() {
+		require.NoError(t, ImportBundle(ctx, target, getMockOciService(), bundle, true))
+	})
+	replaced, err := target.GetCatalog(ctx, "acme/tools:latest")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Tools", replaced.Title)
+}
+
+func TestImportBundleIsAllOrNothing(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "setup.tar")
+	f, err := os.Create(bundle)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	require.NoError(t, writeBundleFile(tw, bundleManifestFile, BundleManifest{Version: CurrentBundleVersion, Profile: "dev", Catalogs: []string{"acme/tools:latest"}}))
+	// A profile without a name is invalid.
+	require.NoError(t, writeBundleFile(tw, bundleProfileFile, WorkingSet{Version: CurrentWorkingSetVersion, ID: "dev"}))
+	require.NoError(t, writeBundleFile(tw, bundleCatalogFile(0), bundleCatalog{Ref: "acme/tools:latest", Title: "Acme Tools"}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	dao := setupTestDB(t)
+	err = ImportBundle(t.Context(), dao, getMockOciService(), bundle, false)
+	require.ErrorContains(t, err, "invalid profile")
+
+	catalogs, err := dao.ListCatalogs(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, catalogs)
+}
@@ -15,7 +15,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
-func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, name string, servers []string, connectClients []string) error {
+func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, name string, servers []string, connectClients []string, output Output, opts ...ResolveOption) error {
 	telemetry.Init()
 	start := time.Now()
 	var success bool
@@ -60,9 +60,14 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		Servers: make([]Server, 0),
 		Secrets: secrets,
 	}
+	// The policy is stored with the profile, so that it also applies to the
+	// servers added later on.
+	if imagePolicy := newResolveOptions(opts).imagePolicy; !imagePolicy.IsEmpty() {
+		workingSet.ImagePolicy = &imagePolicy
+	}
 
 	for _, server := range servers {
-		ss, err := ResolveServersFromString(ctx, registryClient, ociService, dao, server)
+		ss, err := ResolveServersFromString(ctx, registryClient, ociService, dao, server, opts...)
 		if err != nil {
 			return err
 		}
@@ -88,13 +93,18 @@ func Create(ctx context.Context, dao db.DAO, registryClient registryapi.Client,
 		}
 	}
 
-	fmt.Printf("Created profile %s with %d servers\n", id, len(workingSet.Servers))
+	output.Printf("Created profile %s with %d servers\n", id, len(workingSet.Servers))
 	if len(connectClients) > 0 {
-		fmt.Printf("Connected to clients: %s\n", strings.Join(connectClients, ", "))
+		output.Printf("Connected to clients: %s\n", strings.Join(connectClients, ", "))
 	}
 
 	success = true
-	return nil
+	return output.Result(CreateResult{
+		ID:               id,
+		Name:             name,
+		Servers:          serverNames(workingSet.Servers),
+		ConnectedClients: connectClients,
+	})
 }
 
 func verifySupportedClients(cfg client.Config, clients []string) error {
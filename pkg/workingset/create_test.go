@@ -1,6 +1,7 @@
 package workingset
 
 import (
+	"encoding/json"
 	"testing"
 
 	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/registryapi"
 	"github.com/docker/mcp-gateway/test/mocks"
@@ -97,7 +99,7 @@ func TestCreateWithDockerImages(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "My Test Set", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify the working set was created
@@ -116,6 +118,64 @@ func TestCreateWithDockerImages(t *testing.T) {
 	assert.Equal(t, "anotherimage:v1.0", dbSet.Servers[1].Image)
 }
 
+func TestCreateWithImagePolicy(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	policy := WithImagePolicy(imagepolicy.Policy{Deny: []string{"anotherimage"}})
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "allowed", "Allowed", []string{"docker://myimage:latest"}, []string{}, Output{}, policy)
+	require.NoError(t, err)
+	dbSet, err := dao.GetWorkingSet(ctx, "allowed")
+	require.NoError(t, err)
+	// The policy is stored with the profile.
+	assert.Equal(t, &imagepolicy.Policy{Deny: []string{"anotherimage"}}, NewFromDb(dbSet).ImagePolicy)
+
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "allowed", []string{"docker://anotherimage:v1.0"}, Output{})
+	require.ErrorContains(t, err, "server anotherimage:v1.0: image anotherimage:v1.0 is denied by the image policy (anotherimage)")
+
+	err = Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "denied", "Denied", []string{
+		"docker://myimage:latest",
+		"docker://anotherimage:v1.0",
+	}, []string{}, Output{}, policy)
+	require.ErrorContains(t, err, "server anotherimage:v1.0: image anotherimage:v1.0 is denied by the image policy (anotherimage)")
+	_, err = dao.GetWorkingSet(ctx, "denied")
+	require.Error(t, err)
+}
+
+func TestCreateOutput(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	servers := []string{"docker://myimage:latest", "docker://anotherimage:v1.0"}
+
+	output := captureStdout(func() {
+		err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "human", "Human", servers, []string{}, Output{})
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "Created profile human with 2 servers\n", output)
+
+	output = captureStdout(func() {
+		err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "quiet", "Quiet", servers, []string{}, Output{Quiet: true})
+		require.NoError(t, err)
+	})
+	assert.Empty(t, output)
+
+	// JSON implies quiet, even without --quiet.
+	output = captureStdout(func() {
+		err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "json", "JSON", servers, []string{}, Output{Format: OutputFormatJSON})
+		require.NoError(t, err)
+	})
+	var result CreateResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, CreateResult{ID: "json", Name: "JSON", Servers: []string{"My Image", "Another Image"}}, result)
+
+	output = captureStdout(func() {
+		err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "json-quiet", "JSON", servers, []string{}, Output{Format: OutputFormatJSON, Quiet: true})
+		require.NoError(t, err)
+	})
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "json-quiet", result.ID)
+}
+
 func TestCreateWithRegistryServers(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -123,7 +183,7 @@ func TestCreateWithRegistryServers(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Registry Set", []string{
 		"https://example.com/v0/servers/server1",
 		"https://example.com/v0/servers/server2",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify the working set was created
@@ -147,7 +207,7 @@ func TestCreateWithMixedServers(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Mixed Set", []string{
 		"docker://myimage:latest",
 		"https://example.com/v0/servers/server1",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify the working set was created
@@ -166,7 +226,7 @@ func TestCreateWithCustomId(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "custom-id", "Test Set", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify the working set was created with custom ID
@@ -185,13 +245,13 @@ func TestCreateWithExistingId(t *testing.T) {
 	// Create first working set
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-id", "Test Set 1", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Try to create another with the same ID
 	err = Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-id", "Test Set 2", []string{
 		"docker://anotherimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "already exists")
 }
@@ -203,13 +263,13 @@ func TestCreateErrorsOnDuplicateDerivedID(t *testing.T) {
 	// Create first working set
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Test Set", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Create second with same name should fail
 	err = Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Test Set", []string{
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "already exists")
 }
@@ -220,7 +280,7 @@ func TestCreateWithInvalidServerFormat(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Test Set", []string{
 		"invalid-format",
-	}, []string{})
+	}, []string{}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid server value")
 }
@@ -231,7 +291,7 @@ func TestCreateWithEmptyName(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-id", "", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid profile")
 }
@@ -240,7 +300,7 @@ func TestCreateWithEmptyServers(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
-	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Empty Set", []string{}, []string{})
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Empty Set", []string{}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify the working set was created with no servers
@@ -257,7 +317,7 @@ func TestCreateAddsDefaultSecrets(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", "Test Set", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Verify default secrets were added
@@ -306,7 +366,7 @@ func TestCreateNameWithSpecialCharacters(t *testing.T) {
 
 			err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "", tt.inputName, []string{
 				"docker://myimage:latest",
-			}, []string{})
+			}, []string{}, Output{})
 			require.NoError(t, err)
 
 			// Verify the ID was generated correctly
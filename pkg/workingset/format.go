@@ -1,6 +1,7 @@
 package workingset
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/mikefarah/yq/v4/pkg/yqlib"
@@ -26,6 +27,15 @@ func SupportedFormats() []string {
 	return formats
 }
 
+// MarshalJSON marshals v as JSON indented for humans, or on a single line when
+// compact is set, e.g. to pipe it into other tools.
+func MarshalJSON(v any, compact bool) ([]byte, error) {
+	if compact {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
 func ApplyYqExpression(data []byte, format OutputFormat, yqExpr string) ([]byte, error) {
 	var decoder yqlib.Decoder
 	var encoder yqlib.Encoder
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSupportedFormats(t *testing.T) {
@@ -33,3 +34,15 @@ func TestOutputFormatConstants(t *testing.T) {
 	assert.Equal(t, OutputFormatYAML, OutputFormat("yaml"))
 	assert.Equal(t, OutputFormatHumanReadable, OutputFormat("human"))
 }
+
+func TestMarshalJSON(t *testing.T) {
+	value := map[string]any{"name": "github", "tools": []string{"create_issue"}}
+
+	indented, err := MarshalJSON(value, false)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"github\",\n  \"tools\": [\n    \"create_issue\"\n  ]\n}", string(indented))
+
+	compact, err := MarshalJSON(value, true)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"github","tools":["create_issue"]}`, string(compact))
+}
@@ -2,9 +2,7 @@ package workingset
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -34,6 +32,21 @@ func Import(ctx context.Context, dao db.DAO, ociService oci.Service, filename st
 		return fmt.Errorf("unsupported file extension: %s, must be .yaml or .json", filename)
 	}
 
+	if err := saveImported(ctx, dao, ociService, workingSet); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported profile %s\n", workingSet.ID)
+
+	return nil
+}
+
+// saveImported resolves the missing snapshots of an imported profile, then
+// creates it or replaces the existing profile with the same ID, along with the
+// catalogs imported with it, in a single transaction.
+func saveImported(ctx context.Context, dao db.DAO, ociService oci.Service, workingSet WorkingSet, catalogs ...db.Catalog) error {
+	workingSet.Origin = OriginImported
+
 	// Resolve snapshots for each server before saving
 	for i := range len(workingSet.Servers) {
 		if workingSet.Servers[i].Snapshot == nil {
@@ -45,32 +58,15 @@ func Import(ctx context.Context, dao db.DAO, ociService oci.Service, filename st
 		}
 	}
 
-	RegisterOAuthProvidersForServers(ctx, workingSet.Servers)
-
 	if err := workingSet.Validate(); err != nil {
 		return fmt.Errorf("invalid profile: %w", err)
 	}
 
-	dbSet := workingSet.ToDb()
-
-	_, err = dao.GetWorkingSet(ctx, workingSet.ID)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return fmt.Errorf("failed to get profile: %w", err)
+	if err := dao.ImportWorkingSet(ctx, workingSet.ToDb(), catalogs...); err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
 	}
 
-	if err != nil { // Not found
-		err = dao.CreateWorkingSet(ctx, dbSet)
-		if err != nil {
-			return fmt.Errorf("failed to create profile: %w", err)
-		}
-	} else {
-		err = dao.UpdateWorkingSet(ctx, dbSet)
-		if err != nil {
-			return fmt.Errorf("failed to update profile: %w", err)
-		}
-	}
-
-	fmt.Printf("Imported profile %s\n", workingSet.ID)
+	RegisterOAuthProvidersForServers(ctx, workingSet.Servers)
 
 	return nil
 }
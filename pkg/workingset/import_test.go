@@ -56,6 +56,7 @@ func TestImportYAML(t *testing.T) {
 
 	assert.Equal(t, "test-import", dbSet.ID)
 	assert.Equal(t, "Imported Working Set", dbSet.Name)
+	assert.Equal(t, OriginImported, dbSet.Origin, "imported profiles are not trusted to read local files")
 	assert.Len(t, dbSet.Servers, 1)
 	assert.Equal(t, "registry", dbSet.Servers[0].Type)
 }
@@ -205,6 +206,7 @@ func TestImportUpdatesExistingWorkingSet(t *testing.T) {
 
 	assert.Equal(t, "existing-set", dbSet.ID)
 	assert.Equal(t, "Updated Name", dbSet.Name)
+	assert.Equal(t, OriginImported, dbSet.Origin)
 	assert.Len(t, dbSet.Servers, 1)
 	assert.Equal(t, "myimage:latest", dbSet.Servers[0].Image)
 }
@@ -0,0 +1,55 @@
+package workingset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/remoteurl"
+)
+
+// maxOpenAPIDocumentSize bounds the size of the OpenAPI documents we fetch.
+const maxOpenAPIDocumentSize = 10 << 20
+
+// ResolveOpenAPI resolves an openapi:// reference, e.g.
+// openapi://api.example.com/openapi.json, to a server running
+// catalog.OpenAPIProxyImage for the OpenAPI document served over https at
+// that address.
+func ResolveOpenAPI(ctx context.Context, value string) (Server, error) {
+	specURL := "https://" + value
+	if err := remoteurl.Validate(ctx, specURL); err != nil {
+		return Server{}, fmt.Errorf("invalid OpenAPI document URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return Server{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := remoteurl.NewDirectHTTPClient(30 * time.Second).Do(req)
+	if err != nil {
+		return Server{}, fmt.Errorf("failed to fetch OpenAPI document %s: %w", specURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Server{}, fmt.Errorf("failed to fetch OpenAPI document %s: HTTP %d", specURL, resp.StatusCode)
+	}
+	doc, err := io.ReadAll(io.LimitReader(resp.Body, maxOpenAPIDocumentSize))
+	if err != nil {
+		return Server{}, fmt.Errorf("failed to read OpenAPI document %s: %w", specURL, err)
+	}
+
+	server, err := catalog.FromOpenAPI(doc, specURL)
+	if err != nil {
+		return Server{}, fmt.Errorf("failed to import OpenAPI document %s: %w", specURL, err)
+	}
+
+	return Server{
+		Type:     ServerTypeImage,
+		Image:    server.Image,
+		Secrets:  "default",
+		Snapshot: &ServerSnapshot{Server: server},
+	}, nil
+}
@@ -0,0 +1,99 @@
+package workingset
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Output configures what commands print on stdout.
+type Output struct {
+	// Format is either OutputFormatHumanReadable, the default, to print
+	// prose, or OutputFormatJSON to only print the result of the command.
+	Format OutputFormat
+	// Quiet suppresses the prose. The JSON result is still printed.
+	Quiet bool
+	// Writer is where the output is written, os.Stdout if nil.
+	Writer io.Writer
+}
+
+func (o Output) writer() io.Writer {
+	if o.Writer == nil {
+		return os.Stdout
+	}
+	return o.Writer
+}
+
+// Printf prints prose, unless the output is quiet or in JSON.
+func (o Output) Printf(format string, a ...any) {
+	if o.Quiet || o.Format == OutputFormatJSON {
+		return
+	}
+	fmt.Fprintf(o.writer(), format, a...)
+}
+
+// Print prints the output of a command, e.g. a listing in any format,
+// whether the output is quiet or not. It ends with a single newline: YAML
+// documents end with a newline, while JSON documents and human-readable
+// output may not. The output is flushed before returning, so that it comes
+// before anything printed afterwards.
+func (o Output) Print(data []byte) error {
+	buf := bufio.NewWriter(o.writer())
+	if _, err := buf.Write(bytes.TrimRight(data, "\n")); err != nil {
+		return err
+	}
+	if err := buf.WriteByte('\n'); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// Result prints the result of a command as JSON, when the output is in JSON.
+func (o Output) Result(v any) error {
+	if o.Format != OutputFormatJSON {
+		return nil
+	}
+	data, err := MarshalJSON(v, false)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return o.Print(data)
+}
+
+// CreateResult is the JSON result of Create.
+type CreateResult struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Servers          []string `json:"servers"`
+	ConnectedClients []string `json:"connectedClients,omitempty"`
+}
+
+// AddServersResult is the JSON result of AddServers.
+type AddServersResult struct {
+	Profile  string          `json:"profile"`
+	Added    []string        `json:"added"`
+	Replaced []string        `json:"replaced,omitempty"`
+	Skipped  []SkippedServer `json:"skipped,omitempty"`
+}
+
+// SkippedServer is a server reference that was not added, and why.
+type SkippedServer struct {
+	Ref    string `json:"ref"`
+	Reason string `json:"reason"`
+}
+
+// serverNames returns the names of servers, or what they run when they have
+// no snapshot.
+func serverNames(servers []Server) []string {
+	names := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if server.Snapshot != nil && server.Snapshot.Server.Name != "" {
+			names = append(names, server.Snapshot.Server.Name)
+		} else {
+			names = append(names, server.BasicName())
+		}
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package workingset
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputPrint(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "no trailing newline", data: `{"a": 1}`, want: "{\"a\": 1}\n"},
+		{name: "single trailing newline", data: "a: 1\n", want: "a: 1\n"},
+		{name: "several trailing newlines", data: "Servers:\n\n  fetch\n\n", want: "Servers:\n\n  fetch\n"},
+		{name: "empty", data: "", want: "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Output{Writer: &buf}.Print([]byte(tt.data)))
+			assert.Equal(t, tt.want, buf.String())
+
+			// The output of a command is printed even when quiet.
+			buf.Reset()
+			require.NoError(t, Output{Quiet: true, Writer: &buf}.Print([]byte(tt.data)))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestOutputPrintfAndResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		output Output
+		want   string
+	}{
+		{name: "human", output: Output{}, want: "Created\n"},
+		{name: "quiet", output: Output{Quiet: true}, want: ""},
+		{name: "json", output: Output{Format: OutputFormatJSON}, want: "{\n  \"id\": \"dev\"\n}\n"},
+		{name: "json quiet", output: Output{Format: OutputFormatJSON, Quiet: true}, want: "{\n  \"id\": \"dev\"\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.output.Writer = &buf
+			tt.output.Printf("Created\n")
+			require.NoError(t, tt.output.Result(map[string]string{"id": "dev"}))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
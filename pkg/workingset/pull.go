@@ -30,6 +30,7 @@ func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, ref string) e
 		return fmt.Errorf("failed to create profile id: %w", err)
 	}
 	workingSet.ID = id
+	workingSet.Origin = OriginPulled
 
 	// Resolve snapshots for each server before saving
 	for i := range len(workingSet.Servers) {
@@ -0,0 +1,259 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/eval"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// RunPlan describes how the gateway would run each server of a profile.
+type RunPlan struct {
+	ID      string          `yaml:"id" json:"id"`
+	Name    string          `yaml:"name" json:"name"`
+	Servers []ServerRunPlan `yaml:"servers" json:"servers"`
+}
+
+// ServerRunPlan is the effective configuration of a single server together
+// with the command (image and command servers), endpoint (remote servers) or
+// tools (POCI servers) it resolves to.
+type ServerRunPlan struct {
+	Name    string         `yaml:"name" json:"name"`
+	Type    ServerType     `yaml:"type" json:"type"`
+	Config  map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
+	Secrets []string       `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+
+	// Image servers only
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+
+	// Image and command servers: the docker command that starts the
+	// container, or the command run on the host.
+	Command []string          `yaml:"command,omitempty" json:"command,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// POCI servers only
+	Tools []ToolRunPlan `yaml:"tools,omitempty" json:"tools,omitempty"`
+
+	// Remote servers only
+	Endpoint  string            `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Transport string            `yaml:"transport,omitempty" json:"transport,omitempty"`
+	Headers   map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// ToolRunPlan is a tool of a POCI server, that runs its own container on each
+// call. Its command is only evaluated with the arguments of the call.
+type ToolRunPlan struct {
+	Name    string   `yaml:"name" json:"name"`
+	Image   string   `yaml:"image" json:"image"`
+	Command []string `yaml:"command,omitempty" json:"command,omitempty"`
+}
+
+// RunArgsFunc returns a command and its env, computed for a server.
+type RunArgsFunc func(ctx context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error)
+
+// RunArgs are provided by the gateway so that run plans match what the
+// gateway runs.
+type RunArgs struct {
+	// Container returns the arguments of the docker command that starts the
+	// container of a server, image and command included, and its env.
+	Container RunArgsFunc
+	// LocalCommand returns the command a command server runs on the host,
+	// and its env.
+	LocalCommand RunArgsFunc
+}
+
+func ShowRunPlan(ctx context.Context, dao db.DAO, ociService oci.Service, id string, runArgs RunArgs, format OutputFormat) error {
+	plan, err := ResolveRunPlan(ctx, dao, ociService, id, runArgs)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case OutputFormatHumanReadable:
+		printRunPlanHuman(plan)
+		return nil
+	case OutputFormatJSON:
+		data, err = json.MarshalIndent(plan, "", "  ")
+	case OutputFormatYAML:
+		data, err = yaml.Marshal(plan)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal run plan: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// ResolveRunPlan resolves the snapshots of a profile and computes the run
+// plan of each of its servers. Secret values are never read: they are
+// rendered as placeholders.
+func ResolveRunPlan(ctx context.Context, dao db.DAO, ociService oci.Service, id string, runArgs RunArgs) (RunPlan, error) {
+	dbSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return RunPlan{}, fmt.Errorf("profile %s not found", id)
+		}
+		return RunPlan{}, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbSet)
+	if err := workingSet.EnsureSnapshotsResolved(ctx, ociService); err != nil {
+		return RunPlan{}, fmt.Errorf("failed to resolve snapshots: %w", err)
+	}
+
+	plan := RunPlan{
+		ID:      workingSet.ID,
+		Name:    workingSet.Name,
+		Servers: make([]ServerRunPlan, 0, len(workingSet.Servers)),
+	}
+	for _, server := range workingSet.Servers {
+		if server.Snapshot == nil {
+			continue
+		}
+		serverPlan, err := serverRunPlan(ctx, server, runArgs)
+		if err != nil {
+			return RunPlan{}, err
+		}
+		plan.Servers = append(plan.Servers, serverPlan)
+	}
+
+	return plan, nil
+}
+
+func serverRunPlan(ctx context.Context, server Server, runArgs RunArgs) (ServerRunPlan, error) {
+	spec := server.Snapshot.Server
+	plan := ServerRunPlan{
+		Name:   spec.Name,
+		Type:   server.Type,
+		Config: server.Config,
+	}
+	secrets := make(map[string]string)
+	for _, secret := range spec.StoredSecrets() {
+		plan.Secrets = append(plan.Secrets, secret.Name)
+		secrets[secret.Name] = fmt.Sprintf("<secret:%s>", secret.Name)
+	}
+
+	config := map[string]any{
+		oci.CanonicalizeServerName(spec.Name): server.Config,
+	}
+
+	if spec.Remote.URL != "" || spec.SSEEndpoint != "" {
+		plan.Endpoint = spec.Remote.URL
+		plan.Transport = spec.Remote.Transport
+		if plan.Endpoint == "" {
+			plan.Endpoint = spec.SSEEndpoint
+			plan.Transport = "sse"
+		}
+		if len(spec.Remote.Headers) > 0 {
+			plan.Headers = make(map[string]string, len(spec.Remote.Headers))
+			for name, value := range spec.Remote.Headers {
+				plan.Headers[name] = fmt.Sprintf("%v", eval.Evaluate(value, config))
+			}
+		}
+		return plan, nil
+	}
+
+	if server.Type == ServerTypePOCI {
+		// The gateway runs no container for the server itself.
+		for _, tool := range spec.Tools {
+			plan.Tools = append(plan.Tools, ToolRunPlan{
+				Name:    tool.Name,
+				Image:   tool.Container.Image,
+				Command: tool.Container.Command,
+			})
+		}
+		return plan, nil
+	}
+
+	args := runArgs.Container
+	if server.Type == ServerTypeCommand {
+		// Like the gateway, run the command of the profile on the host.
+		spec.Type = catalog.ServerTypeCommand
+		spec.Command = append([]string{server.Exec}, server.Args...)
+		args = runArgs.LocalCommand
+	} else {
+		plan.Image = spec.Image
+	}
+
+	// Secret values are never read: the gateway is given placeholders.
+	command, env, err := args(ctx, &catalog.ServerConfig{
+		Name:    spec.Name,
+		Spec:    spec,
+		Config:  config,
+		Secrets: secrets,
+	})
+	if err != nil {
+		return ServerRunPlan{}, fmt.Errorf("failed to compute the run command of %s: %w", spec.Name, err)
+	}
+
+	if server.Type == ServerTypeCommand {
+		plan.Command = command
+	} else {
+		plan.Command = append([]string{"docker"}, command...)
+	}
+	if len(env) > 0 {
+		plan.Env = make(map[string]string, len(env))
+		for _, e := range env {
+			name, value, _ := strings.Cut(e, "=")
+			plan.Env[name] = value
+		}
+	}
+	return plan, nil
+}
+
+func printRunPlanHuman(plan RunPlan) {
+	fmt.Printf("Profile: %s (%s)\n", plan.Name, plan.ID)
+	if len(plan.Servers) == 0 {
+		fmt.Println("No servers found")
+		return
+	}
+	fmt.Printf("Servers (%d):\n\n", len(plan.Servers))
+
+	for _, server := range plan.Servers {
+		fmt.Printf("  %s\n", server.Name)
+		fmt.Printf("    Type: %s\n", server.Type)
+		flattened := flattenConfig(server.Config)
+		keys := make([]string, 0, len(flattened))
+		for key := range flattened {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("    Config: %s=%v\n", key, flattened[key])
+		}
+		if len(server.Secrets) > 0 {
+			fmt.Printf("    Secrets: %s\n", strings.Join(server.Secrets, ", "))
+		}
+		if server.Endpoint != "" {
+			fmt.Printf("    Endpoint: %s\n", server.Endpoint)
+			if server.Transport != "" {
+				fmt.Printf("    Transport: %s\n", server.Transport)
+			}
+		}
+		if len(server.Command) > 0 {
+			fmt.Printf("    Run: %s\n", strings.Join(server.Command, " "))
+		}
+		for _, tool := range server.Tools {
+			fmt.Printf("    Tool: %s (%s)", tool.Name, tool.Image)
+			if len(tool.Command) > 0 {
+				fmt.Printf(": %s", strings.Join(tool.Command, " "))
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+}
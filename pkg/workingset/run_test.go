@@ -0,0 +1,249 @@
+package workingset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func createRunPlanWorkingSet(t *testing.T, dao db.DAO) {
+	t.Helper()
+
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "run-set",
+		Name: "Run Set",
+		Servers: db.ServerList{
+			{
+				Type:   "image",
+				Image:  "mcp/fs:latest",
+				Config: map[string]any{"paths": []any{"/home/user/docs"}, "level": "debug"},
+				Snapshot: &db.ServerSnapshot{
+					Server: catalog.Server{
+						Name:    "fs",
+						Type:    "server",
+						Image:   "mcp/fs:latest",
+						Secrets: []catalog.Secret{{Name: "fs.token", Env: "FS_TOKEN"}},
+						Env: []catalog.Env{
+							{Name: "LOG_LEVEL", Value: "{{fs.level}}"},
+							{Name: "UNSET", Value: "{{fs.missing}}"},
+						},
+						Volumes: []string{"{{fs.paths|volume|into}}"},
+						Command: []string{"--root", "/data"},
+					},
+				},
+			},
+			{
+				Type:     "remote",
+				Endpoint: "https://remote.example.com/mcp",
+				Config:   map[string]any{"tenant": "acme"},
+				Snapshot: &db.ServerSnapshot{
+					Server: catalog.Server{
+						Name: "remote",
+						Type: "remote",
+						Remote: catalog.Remote{
+							URL:       "https://remote.example.com/mcp",
+							Transport: "streamable-http",
+							Headers:   map[string]string{"X-Tenant": "{{remote.tenant}}"},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+// fakeContainerArgs stands for the gateway's computation of run commands.
+func fakeContainerArgs(_ context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+	args := []string{"run", "--rm"}
+	var env []string
+	for _, secret := range serverConfig.Spec.Secrets {
+		args = append(args, "-e", secret.Env)
+		env = append(env, secret.Env+"="+serverConfig.Secrets[secret.Name])
+	}
+	args = append(args, serverConfig.Spec.Image)
+	args = append(args, serverConfig.Spec.Command...)
+	return args, env, nil
+}
+
+// fakeLocalCommand stands for the gateway's computation of the commands run
+// on the host.
+func fakeLocalCommand(_ context.Context, serverConfig *catalog.ServerConfig) ([]string, []string, error) {
+	env := []string{"PATH=/usr/bin"}
+	for _, secret := range serverConfig.Spec.Secrets {
+		env = append(env, secret.Env+"="+serverConfig.Secrets[secret.Name])
+	}
+	return serverConfig.Spec.Command, env, nil
+}
+
+var fakeRunArgs = RunArgs{Container: fakeContainerArgs, LocalCommand: fakeLocalCommand}
+
+func TestResolveRunPlan(t *testing.T) {
+	dao := setupTestDB(t)
+	createRunPlanWorkingSet(t, dao)
+
+	plan, err := ResolveRunPlan(t.Context(), dao, getMockOciService(), "run-set", fakeRunArgs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "run-set", plan.ID)
+	assert.Equal(t, "Run Set", plan.Name)
+	require.Len(t, plan.Servers, 2)
+
+	image := plan.Servers[0]
+	assert.Equal(t, "fs", image.Name)
+	assert.Equal(t, ServerTypeImage, image.Type)
+	assert.Equal(t, "mcp/fs:latest", image.Image)
+	assert.Equal(t, []string{"fs.token"}, image.Secrets)
+	assert.Equal(t, map[string]string{"FS_TOKEN": "<secret:fs.token>"}, image.Env)
+	assert.Equal(t, []string{"docker", "run", "--rm", "-e", "FS_TOKEN", "mcp/fs:latest", "--root", "/data"}, image.Command)
+	assert.Empty(t, image.Endpoint)
+
+	remote := plan.Servers[1]
+	assert.Equal(t, "remote", remote.Name)
+	assert.Equal(t, ServerTypeRemote, remote.Type)
+	assert.Equal(t, "https://remote.example.com/mcp", remote.Endpoint)
+	assert.Equal(t, "streamable-http", remote.Transport)
+	assert.Equal(t, map[string]string{"X-Tenant": "acme"}, remote.Headers)
+	assert.Equal(t, map[string]any{"tenant": "acme"}, remote.Config)
+	assert.Empty(t, remote.Command)
+}
+
+func TestResolveRunPlanOfCommandServer(t *testing.T) {
+	dao := setupTestDB(t)
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{{
+			Type: "command",
+			Exec: "./bin/my-mcp",
+			Args: []string{"--stdio"},
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{
+				Name:    "my-mcp",
+				Type:    "command",
+				Secrets: []catalog.Secret{{Name: "my-mcp.token", Env: "TOKEN"}},
+			}},
+		}},
+	})
+	require.NoError(t, err)
+
+	plan, err := ResolveRunPlan(t.Context(), dao, getMockOciService(), "dev", fakeRunArgs)
+	require.NoError(t, err)
+	require.Len(t, plan.Servers, 1)
+
+	// The command runs on the host, not in a container.
+	server := plan.Servers[0]
+	assert.Equal(t, ServerTypeCommand, server.Type)
+	assert.Empty(t, server.Image)
+	assert.Equal(t, []string{"./bin/my-mcp", "--stdio"}, server.Command)
+	assert.Equal(t, map[string]string{"PATH": "/usr/bin", "TOKEN": "<secret:my-mcp.token>"}, server.Env)
+	assert.Empty(t, server.Tools)
+}
+
+func TestResolveRunPlanOfPOCIServer(t *testing.T) {
+	dao := setupTestDB(t)
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "tools",
+		Name: "Tools",
+		Servers: db.ServerList{{
+			Type: "poci",
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{
+				Name:  "my-tools",
+				Type:  "poci",
+				Image: "ghcr.io/example/builder:latest",
+				Tools: []catalog.Tool{{
+					Name:      "curl",
+					Container: catalog.Container{Image: "curlimages/curl:8.10.1", Command: []string{"{{url}}"}},
+				}},
+			}},
+		}},
+	})
+	require.NoError(t, err)
+
+	plan, err := ResolveRunPlan(t.Context(), dao, getMockOciService(), "tools", fakeRunArgs)
+	require.NoError(t, err)
+	require.Len(t, plan.Servers, 1)
+
+	// Only the containers of the tools are run, on each call.
+	server := plan.Servers[0]
+	assert.Equal(t, ServerTypePOCI, server.Type)
+	assert.Empty(t, server.Image)
+	assert.Empty(t, server.Command)
+	assert.Equal(t, []ToolRunPlan{{Name: "curl", Image: "curlimages/curl:8.10.1", Command: []string{"{{url}}"}}}, server.Tools)
+
+	output := captureStdout(func() {
+		require.NoError(t, ShowRunPlan(t.Context(), dao, getMockOciService(), "tools", fakeRunArgs, OutputFormatHumanReadable))
+	})
+	assert.Contains(t, output, "Tool: curl (curlimages/curl:8.10.1): {{url}}")
+	assert.NotContains(t, output, "Run:")
+}
+
+func TestResolveRunPlanNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	_, err := ResolveRunPlan(t.Context(), dao, getMockOciService(), "missing", fakeRunArgs)
+	require.EqualError(t, err, "profile missing not found")
+}
+
+func TestResolveRunPlanContainerArgsError(t *testing.T) {
+	dao := setupTestDB(t)
+	createRunPlanWorkingSet(t, dao)
+
+	failing := func(context.Context, *catalog.ServerConfig) ([]string, []string, error) {
+		return nil, nil, errors.New("unsafe docker volume")
+	}
+	_, err := ResolveRunPlan(t.Context(), dao, getMockOciService(), "run-set", RunArgs{Container: failing})
+	require.EqualError(t, err, "failed to compute the run command of fs: unsafe docker volume")
+}
+
+func TestShowRunPlanFormats(t *testing.T) {
+	dao := setupTestDB(t)
+	createRunPlanWorkingSet(t, dao)
+	ctx := t.Context()
+
+	t.Run("json", func(t *testing.T) {
+		output := captureStdout(func() {
+			require.NoError(t, ShowRunPlan(ctx, dao, getMockOciService(), "run-set", fakeRunArgs, OutputFormatJSON))
+		})
+
+		var plan RunPlan
+		require.NoError(t, json.Unmarshal([]byte(output), &plan))
+		require.Len(t, plan.Servers, 2)
+		assert.Equal(t, "mcp/fs:latest", plan.Servers[0].Image)
+		assert.Equal(t, "https://remote.example.com/mcp", plan.Servers[1].Endpoint)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		output := captureStdout(func() {
+			require.NoError(t, ShowRunPlan(ctx, dao, getMockOciService(), "run-set", fakeRunArgs, OutputFormatYAML))
+		})
+
+		var plan RunPlan
+		require.NoError(t, yaml.Unmarshal([]byte(output), &plan))
+		require.Len(t, plan.Servers, 2)
+		assert.Equal(t, "fs", plan.Servers[0].Name)
+	})
+
+	t.Run("human", func(t *testing.T) {
+		output := captureStdout(func() {
+			require.NoError(t, ShowRunPlan(ctx, dao, getMockOciService(), "run-set", fakeRunArgs, OutputFormatHumanReadable))
+		})
+
+		assert.Contains(t, output, "Profile: Run Set (run-set)")
+		assert.Contains(t, output, "Run: docker run --rm -e FS_TOKEN mcp/fs:latest")
+		assert.Contains(t, output, "Endpoint: https://remote.example.com/mcp")
+		assert.Contains(t, output, "Config: level=debug")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		err := ShowRunPlan(ctx, dao, getMockOciService(), "run-set", fakeRunArgs, OutputFormat("xml"))
+		require.EqualError(t, err, "unsupported format: xml")
+	})
+}
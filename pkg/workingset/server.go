@@ -21,7 +21,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/registryapi"
 )
 
-func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, servers []string) error {
+func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, servers []string, output Output, opts ...ResolveOption) error {
 	if len(servers) == 0 {
 		return fmt.Errorf("at least one server must be specified")
 	}
@@ -43,13 +43,35 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 	}
 
 	newServers := make([]Server, 0)
+	var incompatible []*IncompatibleServerError
 	for _, server := range servers {
-		ss, err := ResolveServersFromString(ctx, registryClient, ociService, dao, server)
+		ss, err := ResolveServersFromString(ctx, registryClient, ociService, dao, server, opts...)
+		var incompatibleErr *IncompatibleServerError
+		if errors.As(err, &incompatibleErr) {
+			// Like community imports, skip servers the gateway can't run
+			// instead of failing the whole add.
+			incompatible = append(incompatible, incompatibleErr)
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("invalid server value: %w", err)
 		}
 		newServers = append(newServers, ss...)
 	}
+	if workingSet.ImagePolicy != nil {
+		if err := checkImagePolicy(*workingSet.ImagePolicy, newServers); err != nil {
+			return fmt.Errorf("invalid server value: %w", err)
+		}
+	}
+
+	result := AddServersResult{Profile: id}
+	for _, server := range incompatible {
+		output.Printf("Skipped incompatible server %s: %s\n", server.Ref, server.Reason)
+		result.Skipped = append(result.Skipped, SkippedServer{Ref: server.Ref, Reason: server.Reason})
+	}
+	if len(newServers) == 0 && len(incompatible) > 0 {
+		return fmt.Errorf("no compatible servers to add to profile %s", id)
+	}
 
 	// Set the secrets on all the new servers to the default secret
 	for i := range newServers {
@@ -71,7 +93,8 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 	filtered := make([]Server, 0, len(workingSet.Servers))
 	for _, existing := range workingSet.Servers {
 		if existing.Snapshot != nil && newServerNames[existing.Snapshot.Server.Name] {
-			fmt.Printf("Replaced server %s in profile %s\n", existing.Snapshot.Server.Name, id)
+			output.Printf("Replaced server %s in profile %s\n", existing.Snapshot.Server.Name, id)
+			result.Replaced = append(result.Replaced, existing.Snapshot.Server.Name)
 			replacedCount++
 		} else {
 			filtered = append(filtered, existing)
@@ -90,13 +113,21 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 		return fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	var details []string
 	if replacedCount > 0 {
-		fmt.Printf("Added %d server(s) to profile %s (replaced %d)\n", len(newServers), id, replacedCount)
+		details = append(details, fmt.Sprintf("replaced %d", replacedCount))
+	}
+	if len(incompatible) > 0 {
+		details = append(details, fmt.Sprintf("skipped %d incompatible", len(incompatible)))
+	}
+	if len(details) > 0 {
+		output.Printf("Added %d server(s) to profile %s (%s)\n", len(newServers), id, strings.Join(details, ", "))
 	} else {
-		fmt.Printf("Added %d server(s) to profile %s\n", len(newServers), id)
+		output.Printf("Added %d server(s) to profile %s\n", len(newServers), id)
 	}
 
-	return nil
+	result.Added = serverNames(newServers)
+	return output.Result(result)
 }
 
 func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []string) error {
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"testing"
 
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+	"github.com/docker/mcp-gateway/test/mocks"
 )
 
 var oneServerError = "at least one server must be specified"
@@ -30,7 +35,7 @@ func TestAddOneServerToWorkingSet(t *testing.T) {
 		"docker://myimage:latest",
 	}
 
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers)
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
@@ -56,7 +61,7 @@ func TestAddMultipleServersToWorkingSet(t *testing.T) {
 		"docker://anotherimage:v1.0",
 	}
 
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers)
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
@@ -66,6 +71,32 @@ func TestAddMultipleServersToWorkingSet(t *testing.T) {
 	assert.Equal(t, "Another Image", dbSet.Servers[1].Snapshot.Server.Name)
 }
 
+func TestAddServersWithImagePolicy(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	policy := WithImagePolicy(imagepolicy.Policy{Allow: []string{"myimage"}})
+
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"docker://myimage:latest"}, Output{}, policy)
+	require.NoError(t, err)
+
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"docker://anotherimage:v1.0"}, Output{}, policy)
+	require.ErrorContains(t, err, "image anotherimage:v1.0 is not allowed by the image policy (allowed: myimage)")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.Len(t, dbSet.Servers, 1)
+	assert.Equal(t, "My Image", dbSet.Servers[0].Snapshot.Server.Name)
+}
+
 func TestAddNoServersToWorkingSet(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -80,11 +111,156 @@ func TestAddNoServersToWorkingSet(t *testing.T) {
 
 	servers := []string{}
 
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers)
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", servers, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), oneServerError)
 }
 
+func getMockRegistryClientWithNPMOnlyServer() registryapi.Client {
+	npmOnly := v0.ServerResponse{
+		Server: v0.ServerJSON{
+			Name:        "io.example/npm-only",
+			Description: "Server only published as an npm package served over HTTP",
+			Version:     "1.0.0",
+			Packages: []model.Package{
+				{
+					RegistryType: "npm",
+					Identifier:   "@example/npm-only",
+					Version:      "1.0.0",
+					Transport: model.Transport{
+						Type: "streamable-http",
+						URL:  "http://localhost:3000/mcp",
+					},
+				},
+			},
+		},
+		Meta: v0.ResponseMeta{
+			Official: &v0.RegistryExtensions{
+				IsLatest: true,
+			},
+		},
+	}
+
+	return mocks.NewMockRegistryAPIClient(mocks.WithServerListResponses(map[string]v0.ServerListResponse{
+		"https://example.com/v0/servers/npm-only/versions": {
+			Servers: []v0.ServerResponse{npmOnly},
+		},
+	}))
+}
+
+func TestAddIncompatibleRegistryServerToWorkingSet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	_, err = ResolveRegistry(ctx, getMockRegistryClientWithNPMOnlyServer(), "https://example.com/v0/servers/npm-only/versions/latest")
+	var incompatibleErr *IncompatibleServerError
+	require.ErrorAs(t, err, &incompatibleErr)
+	require.ErrorIs(t, err, catalog.ErrIncompatibleServer)
+	assert.Equal(t, "https://example.com/v0/servers/npm-only/versions/latest", incompatibleErr.Ref)
+	assert.Contains(t, incompatibleErr.Reason, "npm package over streamable-http")
+
+	output := captureStdout(func() {
+		err = AddServers(ctx, dao, getMockRegistryClientWithNPMOnlyServer(), getMockOciService(), "test-set", []string{
+			"https://example.com/v0/servers/npm-only/versions/latest",
+		}, Output{})
+	})
+	require.ErrorContains(t, err, "no compatible servers to add to profile test-set")
+	assert.Contains(t, output, "Skipped incompatible server https://example.com/v0/servers/npm-only/versions/latest: ")
+	assert.Contains(t, output, "npm package over streamable-http")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Empty(t, dbSet.Servers)
+}
+
+func TestAddServersSkipsIncompatibleRegistryServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err = AddServers(ctx, dao, getMockRegistryClientWithNPMOnlyServer(), getMockOciService(), "test-set", []string{
+			"docker://myimage:latest",
+			"https://example.com/v0/servers/npm-only/versions/latest",
+		}, Output{})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, "Skipped incompatible server https://example.com/v0/servers/npm-only/versions/latest")
+	assert.Contains(t, output, "Added 1 server(s) to profile test-set (skipped 1 incompatible)")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.Len(t, dbSet.Servers, 1)
+	assert.Equal(t, "My Image", dbSet.Servers[0].Snapshot.Server.Name)
+}
+
+func TestAddServersJSONOutput(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{
+		"docker://myimage:latest",
+	}, []string{}, Output{Quiet: true})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err = AddServers(ctx, dao, getMockRegistryClientWithNPMOnlyServer(), getMockOciService(), "test-set", []string{
+			"docker://myimage:latest",
+			"docker://anotherimage:v1.0",
+			"https://example.com/v0/servers/npm-only/versions/latest",
+		}, Output{Format: OutputFormatJSON})
+	})
+	require.NoError(t, err)
+
+	// Nothing but the result is printed, so that the output can be piped.
+	var result AddServersResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, AddServersResult{
+		Profile:  "test-set",
+		Added:    []string{"My Image", "Another Image"},
+		Replaced: []string{"My Image"},
+		Skipped: []SkippedServer{{
+			Ref:    "https://example.com/v0/servers/npm-only/versions/latest",
+			Reason: result.Skipped[0].Reason,
+		}},
+	}, result)
+	assert.NotEmpty(t, result.Skipped[0].Reason)
+}
+
+func TestAddServersQuiet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{
+		"docker://myimage:latest",
+	}, []string{}, Output{Quiet: true})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err = AddServers(ctx, dao, getMockRegistryClientWithNPMOnlyServer(), getMockOciService(), "test-set", []string{
+			"docker://myimage:latest",
+			"https://example.com/v0/servers/npm-only/versions/latest",
+		}, Output{Quiet: true})
+	})
+	require.NoError(t, err)
+	assert.Empty(t, output)
+}
+
 func TestRemoveOneServerFromWorkingSet(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -94,7 +270,7 @@ func TestRemoveOneServerFromWorkingSet(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{
 		serverURI,
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, setID)
@@ -123,7 +299,7 @@ func TestRemoveMultipleServersFromWorkingSet(t *testing.T) {
 		"docker://anotherimage:v1.0",
 	}
 
-	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{})
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, workingSetID)
@@ -149,7 +325,7 @@ func TestRemoveOneOfManyServerFromWorkingSet(t *testing.T) {
 		"docker://anotherimage:v1.0",
 	}
 
-	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{})
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, workingSetID)
@@ -175,7 +351,7 @@ func TestRemoveNoServersFromWorkingSet(t *testing.T) {
 		"docker://myimage:latest",
 	}
 
-	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{})
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{}, Output{})
 	require.NoError(t, err)
 
 	err = RemoveServers(ctx, dao, workingSetID, []string{})
@@ -193,7 +369,7 @@ func TestRemoveServersCombinedNames(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, workingSetID)
@@ -225,7 +401,7 @@ func TestRemoveServersTriggersDCRCleanup(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	// Track which server names are passed to the cleanup function
@@ -469,7 +645,7 @@ func TestAddServersFromCatalog(t *testing.T) {
 			}
 
 			// Add servers from catalog
-			err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/" + serverNamesJoined})
+			err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/" + serverNamesJoined}, Output{})
 			require.NoError(t, err)
 
 			// Verify servers were added
@@ -507,7 +683,7 @@ func TestAddServersMixedDirectAndCatalog(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add both direct servers and catalog servers
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"docker://myimage:latest", "catalog://" + catalog.Ref + "/catalog-server-1"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"docker://myimage:latest", "catalog://" + catalog.Ref + "/catalog-server-1"}, Output{})
 	require.NoError(t, err)
 
 	// Verify both types of servers were added
@@ -548,12 +724,38 @@ func TestAddServersFromCatalogMissingServer(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to add a server that doesn't exist in the catalog
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/catalog-server-1+nonexistent-server"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/catalog-server-1+nonexistent-server"}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "servers matching the following patterns were not found in catalog: [nonexistent-server]")
 	assert.Contains(t, err.Error(), "nonexistent-server")
 }
 
+func TestAddServersFromCatalogMisspelledServerSuggestsNames(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalog := createTestCatalog(t, dao, []testCatalogServer{
+		{name: "github-official", serverType: "image", image: "github:latest"},
+		{name: "gitlab", serverType: "image", image: "gitlab:latest"},
+		{name: "postgres", serverType: "image", image: "postgres:latest"},
+	})
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/github-oficial+postgress+slack*"}, Output{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "servers matching the following patterns were not found in catalog: [github-oficial postgress slack*]")
+	assert.Contains(t, err.Error(), "github-oficial: did you mean github-official?")
+	assert.Contains(t, err.Error(), "postgress: did you mean postgres?")
+	assert.NotContains(t, err.Error(), "slack*:")
+}
+
 func TestAddServersFromCatalogInvalidDigest(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
@@ -568,7 +770,7 @@ func TestAddServersFromCatalogInvalidDigest(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to add servers from a non-existent catalog
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://invalid-name/some-server"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://invalid-name/some-server"}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "catalog invalid-name:latest not found")
 }
@@ -587,7 +789,7 @@ func TestAddServersFromCatalogServersWithoutCatalog(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to add servers from a non-existent catalog
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://some-server"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://some-server"}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid server value: invalid catalog URL: catalog://some-server")
 }
@@ -617,7 +819,7 @@ func TestAddServersFromCatalogWithoutDefaultSecret(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add server from catalog
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/catalog-server-1"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/catalog-server-1"}, Output{})
 	require.NoError(t, err)
 
 	// Verify server was added without default secret
@@ -651,7 +853,7 @@ func TestAddServersFromCatalogEmptyCatalogServers(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to add with catalog ref but empty server list
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://test/catalog:latest"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://test/catalog:latest"}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid server value: catalog test:latest not found")
 }
@@ -748,7 +950,7 @@ func TestAddServersFromCatalogUsingGlobPattern(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add servers using a glob pattern that matches all cloudflare-* servers
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*"}, Output{})
 	require.NoError(t, err)
 
 	// Verify only the cloudflare servers were added (3 of them)
@@ -813,7 +1015,7 @@ func TestAddServersFromCatalogUsingMultipleGlobPatterns(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add servers using multiple glob patterns joined with +
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*+github-*"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*+github-*"}, Output{})
 	require.NoError(t, err)
 
 	// Verify 4 servers were added (2 cloudflare + 2 github)
@@ -869,7 +1071,7 @@ func TestAddServersFromCatalogUsingMultipleGlobPatternsWithDuplicates(t *testing
 	require.NoError(t, err)
 
 	// Add servers using multiple glob patterns joined with +
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*+cloudflare-*"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/cloudflare-*+cloudflare-*"}, Output{})
 	require.NoError(t, err)
 
 	// Verify 2 servers were added
@@ -911,7 +1113,7 @@ func TestAddServersFromCatalogGlobPatternNoMatch(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to add servers using a glob pattern that doesn't match anything
-	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/nonexistent-*"})
+	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://" + catalog.Ref + "/nonexistent-*"}, Output{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "servers matching the following patterns were not found in catalog")
 	assert.Contains(t, err.Error(), "nonexistent-*")
@@ -924,7 +1126,7 @@ func TestListServersNoFilters(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -947,7 +1149,7 @@ func TestListServersFilterByName(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -969,7 +1171,7 @@ func TestListServersFilterByNameCaseInsensitive(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -991,12 +1193,12 @@ func TestListServersFilterByWorkingSet(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	err = Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-2", "Set 2", []string{
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -1019,12 +1221,12 @@ func TestListServersFilterByBothNameAndWorkingSet(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	err = Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-2", "Set 2", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -1047,7 +1249,7 @@ func TestListServersFilterNoMatches(t *testing.T) {
 
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "set-1", "Set 1", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	output := captureStdout(func() {
@@ -1086,7 +1288,7 @@ func TestAddServerUpsertReplacesExisting(t *testing.T) {
 	// Create a profile with one server
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "Test", []string{
 		"docker://myimage:latest",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
@@ -1097,7 +1299,7 @@ func TestAddServerUpsertReplacesExisting(t *testing.T) {
 	// Add the same server again -- should upsert (replace), not error
 	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
 		"docker://myimage:latest",
-	})
+	}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, "test-set")
@@ -1114,7 +1316,7 @@ func TestAddServerUpsertPreservesOtherServers(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "Test", []string{
 		"docker://myimage:latest",
 		"docker://anotherimage:v1.0",
-	}, []string{})
+	}, []string{}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
@@ -1124,7 +1326,7 @@ func TestAddServerUpsertPreservesOtherServers(t *testing.T) {
 	// Upsert only the first server
 	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
 		"docker://myimage:latest",
-	})
+	}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, "test-set")
@@ -1163,7 +1365,7 @@ func TestAddServerUpsertFromCatalog(t *testing.T) {
 
 	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
 		"catalog://" + testCatalog.Ref + "/catalog-server-1",
-	})
+	}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
@@ -1197,7 +1399,7 @@ func TestAddServerUpsertFromCatalog(t *testing.T) {
 	// Upsert the server from the new catalog version
 	err = AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
 		"catalog://" + updatedCatalog.Ref + "/catalog-server-1",
-	})
+	}, Output{})
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, "test-set")
@@ -1207,3 +1409,206 @@ func TestAddServerUpsertFromCatalog(t *testing.T) {
 	// Verify it's the updated version
 	assert.Equal(t, "catalog-image-1:v2", dbSet.Servers[0].Image)
 }
+
+func TestAddServersFromCatalogPinnedDigest(t *testing.T) {
+	const (
+		catalogDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		otherDigest   = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	setup := func(t *testing.T) db.DAO {
+		t.Helper()
+		dao := setupTestDB(t)
+		ctx := t.Context()
+
+		catalog := createTestCatalog(t, dao, []testCatalogServer{
+			{name: "catalog-server-1", serverType: "image", image: "catalog-image-1:latest"},
+			{name: "catalog-server-2", serverType: "image", image: "catalog-image-2:latest"},
+		})
+		catalog.Digest = catalogDigest
+		require.NoError(t, dao.UpsertCatalog(ctx, catalog))
+
+		require.NoError(t, dao.CreateWorkingSet(ctx, db.WorkingSet{
+			ID:      "test-set",
+			Name:    "Test Working Set",
+			Servers: db.ServerList{},
+			Secrets: db.SecretMap{},
+		}))
+		return dao
+	}
+
+	t.Run("matching digest", func(t *testing.T) {
+		dao := setup(t)
+		ctx := t.Context()
+
+		err := AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://test/catalog:latest@" + catalogDigest + "/catalog-server-*"}, Output{})
+		require.NoError(t, err)
+
+		dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+		require.NoError(t, err)
+		require.Len(t, dbSet.Servers, 2)
+		assert.Equal(t, "catalog-server-1", dbSet.Servers[0].Snapshot.Server.Name)
+		assert.Equal(t, "catalog-server-2", dbSet.Servers[1].Snapshot.Server.Name)
+	})
+
+	t.Run("mismatching digest", func(t *testing.T) {
+		dao := setup(t)
+		ctx := t.Context()
+
+		err := AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://test/catalog:latest@" + otherDigest + "/catalog-server-1"}, Output{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "catalog test/catalog:latest has digest "+catalogDigest+", expected "+otherDigest)
+
+		dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+		require.NoError(t, err)
+		assert.Empty(t, dbSet.Servers)
+	})
+
+	t.Run("invalid digest", func(t *testing.T) {
+		dao := setup(t)
+
+		err := AddServers(t.Context(), dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"catalog://test/catalog:latest@sha256:nope/catalog-server-1"}, Output{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid digest sha256:nope for catalog test/catalog:latest")
+	})
+}
+
+func TestAddServersFromCatalogWithExclusions(t *testing.T) {
+	tests := []struct {
+		name     string
+		servers  string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "all but one",
+			servers:  "*+!deprecated-search",
+			expected: []string{"github", "gitlab", "postgres"},
+		},
+		{
+			name:     "only exclusions select everything else",
+			servers:  "!deprecated-*",
+			expected: []string{"github", "gitlab", "postgres"},
+		},
+		{
+			name:     "positive and negative patterns",
+			servers:  "git*+postgres+!gitlab",
+			expected: []string{"github", "postgres"},
+		},
+		{
+			name:     "exclusion matching nothing",
+			servers:  "github+!slack",
+			expected: []string{"github"},
+		},
+		{
+			name:    "positive patterns must still match",
+			servers: "github+slack+!gitlab",
+			errMsg:  "servers matching the following patterns were not found in catalog: [slack]",
+		},
+		{
+			name:    "bad exclusion pattern",
+			servers: "*+![",
+			errMsg:  "bad exclusion pattern for catalog server '['",
+		},
+		{
+			name:    "empty exclusion pattern",
+			servers: "*+!",
+			errMsg:  "empty exclusion pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := setupTestDB(t)
+			ctx := t.Context()
+
+			catalog := createTestCatalog(t, dao, []testCatalogServer{
+				{name: "github", serverType: "image", image: "github:latest"},
+				{name: "gitlab", serverType: "image", image: "gitlab:latest"},
+				{name: "postgres", serverType: "image", image: "postgres:latest"},
+				{name: "deprecated-search", serverType: "image", image: "search:latest"},
+			})
+
+			servers, err := ResolveCatalogServers(ctx, dao, catalog.Ref+"/"+tt.servers)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, len(servers))
+			for i, server := range servers {
+				names[i] = server.Snapshot.Server.Name
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
+
+func TestResolveCatalogServersByAlias(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalog := createTestCatalog(t, dao, []testCatalogServer{
+		{name: "github", serverType: "image", image: "github:latest"},
+	})
+	require.NoError(t, dao.SetCatalogAlias(ctx, db.CatalogAlias{Alias: "team", Ref: catalog.Ref}))
+
+	servers, err := ResolveCatalogServers(ctx, dao, "team/github")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "github", servers[0].Snapshot.Server.Name)
+	assert.Equal(t, catalog.Ref, servers[0].CatalogRef)
+}
+
+func TestResolveCatalogServersByStableID(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalog := createTestCatalog(t, dao, []testCatalogServer{
+		{name: "github", serverType: "image", image: "github@sha256:0123456789abcdef"},
+		{name: "postgres", serverType: "image", image: "postgres:latest"},
+	})
+	id := db.CatalogServer{ServerType: "image", Image: "github@sha256:0123456789abcdef"}.DeriveStableID()
+	require.NotEmpty(t, id)
+
+	servers, err := ResolveCatalogServers(ctx, dao, catalog.Ref+"/"+id)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "github", servers[0].Snapshot.Server.Name)
+
+	servers, err = ResolveCatalogServers(ctx, dao, catalog.Ref+"/*+!"+id)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "postgres", servers[0].Snapshot.Server.Name)
+
+	// The display name changes, but the image digest doesn't.
+	catalog.Servers[0].StableID = ""
+	catalog.Servers[0].Snapshot.Server.Name = "github-official"
+	require.NoError(t, dao.UpsertCatalog(ctx, catalog))
+
+	servers, err = ResolveCatalogServers(ctx, dao, catalog.Ref+"/"+id)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "github-official", servers[0].Snapshot.Server.Name)
+}
+
+func TestResolveCatalogServersByAmbiguousStableID(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalog := createTestCatalog(t, dao, []testCatalogServer{
+		{name: "github", serverType: "image", image: "github@sha256:0123456789abcdef"},
+		{name: "github-mirror", serverType: "image", image: "mirror/github@sha256:0123456789abcdef"},
+	})
+	id := db.CatalogServer{ServerType: "image", Image: "github@sha256:0123456789abcdef"}.DeriveStableID()
+
+	_, err := ResolveCatalogServers(ctx, dao, catalog.Ref+"/"+id)
+	require.EqualError(t, err, "server ID "+id+" is ambiguous, it identifies the servers github, github-mirror: select one by name")
+
+	servers, err := ResolveCatalogServers(ctx, dao, catalog.Ref+"/github-mirror")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "github-mirror", servers[0].Snapshot.Server.Name)
+}
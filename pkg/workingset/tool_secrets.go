@@ -0,0 +1,127 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// ToolSecrets is an enabled tool of a profile, with the secrets of the server
+// providing it: the credentials the tool could use if called.
+type ToolSecrets struct {
+	Tool    string   `yaml:"tool" json:"tool"`
+	Server  string   `yaml:"server" json:"server"`
+	Secrets []string `yaml:"secrets" json:"secrets"`
+}
+
+func ShowToolSecrets(ctx context.Context, dao db.DAO, ociService oci.Service, id string, secretNames []string, format OutputFormat) error {
+	rows, err := ResolveToolSecrets(ctx, dao, ociService, id, secretNames)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case OutputFormatHumanReadable:
+		data = []byte(printToolSecretsHumanReadable(rows))
+	case OutputFormatJSON:
+		data, err = json.MarshalIndent(rows, "", "  ")
+	case OutputFormatYAML:
+		data, err = yaml.Marshal(rows)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool secrets: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// ResolveToolSecrets resolves the snapshots of a profile and lists its
+// enabled tools with the secrets of their servers. With secretNames, only the
+// tools whose server requires one of these secrets are listed.
+func ResolveToolSecrets(ctx context.Context, dao db.DAO, ociService oci.Service, id string, secretNames []string) ([]ToolSecrets, error) {
+	dbSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("profile %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbSet)
+	if err := workingSet.EnsureSnapshotsResolved(ctx, ociService); err != nil {
+		return nil, fmt.Errorf("failed to resolve snapshots: %w", err)
+	}
+
+	return toolSecrets(workingSet, secretNames), nil
+}
+
+// toolSecrets joins the enabled tools of the servers of a profile with the
+// secrets the servers require. Servers without a snapshot are skipped, since
+// neither their tools nor their secrets are known.
+func toolSecrets(workingSet WorkingSet, secretNames []string) []ToolSecrets {
+	rows := []ToolSecrets{}
+	for _, server := range workingSet.Servers {
+		if server.Snapshot == nil {
+			continue
+		}
+		spec := server.Snapshot.Server
+
+		secrets := []string{}
+		for _, secret := range spec.StoredSecrets() {
+			secrets = append(secrets, secret.Name)
+		}
+		if len(secretNames) > 0 && !slices.ContainsFunc(secrets, func(secret string) bool {
+			return slices.Contains(secretNames, secret)
+		}) {
+			continue
+		}
+
+		for _, tool := range enabledToolNames(server) {
+			rows = append(rows, ToolSecrets{
+				Tool:    tool,
+				Server:  spec.Name,
+				Secrets: secrets,
+			})
+		}
+	}
+	return rows
+}
+
+// enabledToolNames returns the names of the enabled tools of a server: all
+// the tools of its snapshot, unless the profile restricts them.
+func enabledToolNames(server Server) []string {
+	if server.Tools != nil {
+		return server.Tools
+	}
+	var names []string
+	for _, tool := range server.Snapshot.Server.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func printToolSecretsHumanReadable(rows []ToolSecrets) string {
+	lines := ""
+	for _, row := range rows {
+		secrets := strings.Join(row.Secrets, ", ")
+		if secrets == "" {
+			secrets = "-"
+		}
+		lines += fmt.Sprintf("%s\t%s\t%s\n", row.Tool, row.Server, secrets)
+	}
+	lines = strings.TrimSuffix(lines, "\n")
+	return fmt.Sprintf("Tool\tServer\tSecrets\n----\t------\t-------\n%s", lines)
+}
@@ -0,0 +1,136 @@
+package workingset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func createToolSecretsWorkingSet(t *testing.T, dao db.DAO) {
+	t.Helper()
+
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "secrets-set",
+		Name: "Secrets Set",
+		Servers: db.ServerList{
+			{
+				Type:  "image",
+				Image: "mcp/github:latest",
+				Snapshot: &db.ServerSnapshot{
+					Server: catalog.Server{
+						Name:  "github",
+						Type:  "server",
+						Image: "mcp/github:latest",
+						Secrets: []catalog.Secret{
+							{Name: "github.personal_access_token", Env: "GITHUB_TOKEN"},
+							{Name: "github.app_key", Env: "GITHUB_APP_KEY"},
+						},
+						Tools: []catalog.Tool{{Name: "create_issue"}, {Name: "search_code"}},
+					},
+				},
+			},
+			{
+				Type:  "image",
+				Image: "mcp/slack:latest",
+				// Only some tools are enabled.
+				Tools: []string{"post_message"},
+				Snapshot: &db.ServerSnapshot{
+					Server: catalog.Server{
+						Name:    "slack",
+						Type:    "server",
+						Image:   "mcp/slack:latest",
+						Secrets: []catalog.Secret{{Name: "slack.bot_token", Env: "SLACK_BOT_TOKEN"}},
+						Tools:   []catalog.Tool{{Name: "post_message"}, {Name: "list_channels"}},
+					},
+				},
+			},
+			{
+				Type:  "image",
+				Image: "mcp/fetch:latest",
+				Snapshot: &db.ServerSnapshot{
+					Server: catalog.Server{
+						Name:  "fetch",
+						Type:  "server",
+						Image: "mcp/fetch:latest",
+						Tools: []catalog.Tool{{Name: "fetch"}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestResolveToolSecrets(t *testing.T) {
+	dao := setupTestDB(t)
+	createToolSecretsWorkingSet(t, dao)
+
+	rows, err := ResolveToolSecrets(t.Context(), dao, getMockOciService(), "secrets-set", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ToolSecrets{
+		{Tool: "create_issue", Server: "github", Secrets: []string{"github.personal_access_token", "github.app_key"}},
+		{Tool: "search_code", Server: "github", Secrets: []string{"github.personal_access_token", "github.app_key"}},
+		{Tool: "post_message", Server: "slack", Secrets: []string{"slack.bot_token"}},
+		{Tool: "fetch", Server: "fetch", Secrets: []string{}},
+	}, rows)
+}
+
+func TestResolveToolSecretsFilteredBySecret(t *testing.T) {
+	dao := setupTestDB(t)
+	createToolSecretsWorkingSet(t, dao)
+
+	rows, err := ResolveToolSecrets(t.Context(), dao, getMockOciService(), "secrets-set", []string{"slack.bot_token"})
+	require.NoError(t, err)
+	assert.Equal(t, []ToolSecrets{
+		{Tool: "post_message", Server: "slack", Secrets: []string{"slack.bot_token"}},
+	}, rows)
+
+	rows, err = ResolveToolSecrets(t.Context(), dao, getMockOciService(), "secrets-set", []string{"unknown.secret"})
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestResolveToolSecretsProfileNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	_, err := ResolveToolSecrets(t.Context(), dao, getMockOciService(), "missing", nil)
+	require.EqualError(t, err, "profile missing not found")
+}
+
+func TestShowToolSecretsFormats(t *testing.T) {
+	dao := setupTestDB(t)
+	createToolSecretsWorkingSet(t, dao)
+	ctx := t.Context()
+
+	t.Run("json", func(t *testing.T) {
+		output := captureStdout(func() {
+			require.NoError(t, ShowToolSecrets(ctx, dao, getMockOciService(), "secrets-set", []string{"github.app_key"}, OutputFormatJSON))
+		})
+
+		var rows []ToolSecrets
+		require.NoError(t, json.Unmarshal([]byte(output), &rows))
+		require.Len(t, rows, 2)
+		assert.Equal(t, "create_issue", rows[0].Tool)
+	})
+
+	t.Run("human", func(t *testing.T) {
+		output := captureStdout(func() {
+			require.NoError(t, ShowToolSecrets(ctx, dao, getMockOciService(), "secrets-set", nil, OutputFormatHumanReadable))
+		})
+
+		assert.Contains(t, output, "Tool\tServer\tSecrets\n")
+		assert.Contains(t, output, "post_message\tslack\tslack.bot_token\n")
+		assert.Contains(t, output, "fetch\tfetch\t-\n")
+		assert.NotContains(t, output, "list_channels")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		err := ShowToolSecrets(ctx, dao, getMockOciService(), "secrets-set", nil, OutputFormat("xml"))
+		require.EqualError(t, err, "unsupported format: xml")
+	})
+}
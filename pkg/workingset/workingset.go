@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
@@ -20,12 +22,15 @@ import (
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/imagepolicy"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/policy"
 	"github.com/docker/mcp-gateway/pkg/registryapi"
 	"github.com/docker/mcp-gateway/pkg/sliceutil"
+	"github.com/docker/mcp-gateway/pkg/suggest"
 	"github.com/docker/mcp-gateway/pkg/validate"
+	"github.com/docker/mcp-gateway/pkg/yq"
 )
 
 const CurrentWorkingSetVersion = 1
@@ -39,19 +44,38 @@ type WorkingSet struct {
 	Secrets map[string]Secret `yaml:"secrets,omitempty" json:"secrets,omitempty" validate:"dive"`
 	// Policy describes the policy decision for this working set.
 	Policy *policy.Decision `yaml:"policy,omitempty" json:"policy,omitempty"`
+	// ImagePolicy restricts the images of the servers that can be added to
+	// the profile, and that the gateway runs from it. It's set with
+	// --allow-image and --deny-image when the profile is created.
+	ImagePolicy *imagepolicy.Policy `yaml:"imagePolicy,omitempty" json:"imagePolicy,omitempty"`
+	// Origin is where the profile comes from: empty when it was created
+	// locally, OriginPulled or OriginImported. It's not part of the profile
+	// that's pushed or exported.
+	Origin string `yaml:"-" json:"-"`
 }
 
+// Origins of the profiles that weren't created locally.
+const (
+	OriginPulled   = "pulled"
+	OriginImported = "imported"
+)
+
 type ServerType string
 
 const (
 	ServerTypeRegistry ServerType = "registry"
 	ServerTypeImage    ServerType = "image"
 	ServerTypeRemote   ServerType = "remote"
+	// ServerTypeCommand runs a local command over stdio, for development.
+	ServerTypeCommand ServerType = "command"
+	// ServerTypePOCI exposes tools that each run their own container, as
+	// described by its snapshot, instead of an MCP server.
+	ServerTypePOCI ServerType = "poci"
 )
 
 // Server represents a server configuration in a working set
 type Server struct {
-	Type    ServerType     `yaml:"type" json:"type" validate:"required,oneof=registry image remote"`
+	Type    ServerType     `yaml:"type" json:"type" validate:"required,oneof=registry image remote command poci"`
 	Config  map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
 	Secrets string         `yaml:"secrets,omitempty" json:"secrets,omitempty"`
 	Tools   ToolList       `yaml:"tools,omitempty" json:"tools"` // See IsZero() below
@@ -67,10 +91,26 @@ type Server struct {
 	// ServerTypeRemote only
 	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required_if=Type remote"`
 
+	// ServerTypeCommand only
+	Exec string   `yaml:"exec,omitempty" json:"exec,omitempty" validate:"required_if=Type command"`
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+
+	// ServerTypePOCI servers have no field of their own: their tools, and the
+	// containers they run, are in the snapshot, which is required.
+
 	// CatalogRef is the catalog reference that this server was sourced from.
 	// Empty if the server was added directly (not from a catalog).
 	CatalogRef string `yaml:"catalog_ref,omitempty" json:"catalogRef,omitempty"`
 
+	// LongLivedOverride forces the server to be long-lived (true) or
+	// ephemeral (false), regardless of the snapshot and of --long-lived.
+	LongLivedOverride *bool `yaml:"longLivedOverride,omitempty" json:"longLivedOverride,omitempty"`
+
+	// ToolResultTransforms are YQ expressions applied by the gateway to the
+	// structured results of the server's tools, by tool name. The gateway's
+	// --tool-result-transform flag takes precedence.
+	ToolResultTransforms map[string]string `yaml:"toolResultTransforms,omitempty" json:"toolResultTransforms,omitempty"`
+
 	// Optional snapshot of the server schema
 	Snapshot *ServerSnapshot `yaml:"snapshot,omitempty" json:"snapshot,omitempty"`
 }
@@ -102,11 +142,13 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 	servers := make([]Server, len(dbSet.Servers))
 	for i, server := range dbSet.Servers {
 		servers[i] = Server{
-			Type:       ServerType(server.Type),
-			Config:     server.Config,
-			Secrets:    server.Secrets,
-			Tools:      server.Tools,
-			CatalogRef: server.CatalogRef,
+			Type:                 ServerType(server.Type),
+			Config:               server.Config,
+			Secrets:              server.Secrets,
+			Tools:                server.Tools,
+			CatalogRef:           server.CatalogRef,
+			LongLivedOverride:    server.LongLivedOverride,
+			ToolResultTransforms: server.ToolResultTransforms,
 		}
 		if server.Type == "registry" {
 			servers[i].Source = server.Source
@@ -117,6 +159,10 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 		if server.Type == "remote" {
 			servers[i].Endpoint = server.Endpoint
 		}
+		if server.Type == "command" {
+			servers[i].Exec = server.Exec
+			servers[i].Args = server.Args
+		}
 
 		if server.Snapshot != nil {
 			servers[i].Snapshot = &ServerSnapshot{
@@ -138,6 +184,13 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 		Name:    dbSet.Name,
 		Servers: servers,
 		Secrets: secrets,
+		Origin:  dbSet.Origin,
+	}
+	if dbSet.ImagePolicy != nil {
+		workingSet.ImagePolicy = &imagepolicy.Policy{
+			Allow: dbSet.ImagePolicy.Allow,
+			Deny:  dbSet.ImagePolicy.Deny,
+		}
 	}
 
 	return workingSet
@@ -147,11 +200,13 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 	dbServers := make(db.ServerList, len(workingSet.Servers))
 	for i, server := range workingSet.Servers {
 		dbServers[i] = db.Server{
-			Type:       string(server.Type),
-			Config:     server.Config,
-			Secrets:    server.Secrets,
-			Tools:      server.Tools,
-			CatalogRef: server.CatalogRef,
+			Type:                 string(server.Type),
+			Config:               server.Config,
+			Secrets:              server.Secrets,
+			Tools:                server.Tools,
+			CatalogRef:           server.CatalogRef,
+			LongLivedOverride:    server.LongLivedOverride,
+			ToolResultTransforms: server.ToolResultTransforms,
 		}
 		if server.Type == ServerTypeRegistry {
 			dbServers[i].Source = server.Source
@@ -162,6 +217,10 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 		if server.Type == ServerTypeRemote {
 			dbServers[i].Endpoint = server.Endpoint
 		}
+		if server.Type == ServerTypeCommand {
+			dbServers[i].Exec = server.Exec
+			dbServers[i].Args = server.Args
+		}
 		if server.Snapshot != nil {
 			dbServers[i].Snapshot = &db.ServerSnapshot{
 				Server: server.Snapshot.Server,
@@ -181,6 +240,13 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 		Name:    workingSet.Name,
 		Servers: dbServers,
 		Secrets: dbSecrets,
+		Origin:  workingSet.Origin,
+	}
+	if workingSet.ImagePolicy != nil {
+		dbSet.ImagePolicy = &db.ImagePolicy{
+			Allow: workingSet.ImagePolicy.Allow,
+			Deny:  workingSet.ImagePolicy.Deny,
+		}
 	}
 
 	return dbSet
@@ -193,9 +259,74 @@ func (workingSet *WorkingSet) Validate() error {
 	if err := workingSet.validateUniqueServerNames(); err != nil {
 		return err
 	}
+	if err := workingSet.validateServerReferences(); err != nil {
+		return err
+	}
+	if err := workingSet.validateToolResultTransforms(); err != nil {
+		return err
+	}
+	if workingSet.ImagePolicy != nil {
+		if err := workingSet.ImagePolicy.Validate(); err != nil {
+			return err
+		}
+	}
 	return workingSet.validateServerSnapshots()
 }
 
+// validateToolResultTransforms checks the expressions of the tool result
+// transforms, so that a typo fails when the profile is stored rather than on
+// the first tool call.
+func (workingSet *WorkingSet) validateToolResultTransforms() error {
+	for i, server := range workingSet.Servers {
+		for _, tool := range slices.Sorted(maps.Keys(server.ToolResultTransforms)) {
+			if err := yq.Validate(server.ToolResultTransforms[tool]); err != nil {
+				return fmt.Errorf("server[%d] has invalid result transform for tool %s: %w", i, tool, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateServerReferences checks that image references and URLs can be
+// parsed, so that malformed values are rejected before they are stored.
+func (workingSet *WorkingSet) validateServerReferences() error {
+	for i, server := range workingSet.Servers {
+		switch server.Type {
+		case ServerTypeImage:
+			if _, err := name.ParseReference(server.Image); err != nil {
+				return fmt.Errorf("server[%d] has invalid image reference %q: %w", i, server.Image, err)
+			}
+		case ServerTypeRegistry:
+			if err := validateServerURL(server.Source); err != nil {
+				return fmt.Errorf("server[%d] has invalid source %q: %w", i, server.Source, err)
+			}
+		case ServerTypeRemote:
+			if err := validateServerURL(server.Endpoint); err != nil {
+				return fmt.Errorf("server[%d] has invalid endpoint %q: %w", i, server.Endpoint, err)
+			}
+		case ServerTypePOCI:
+			if server.Snapshot == nil {
+				return fmt.Errorf("server[%d] is a poci server without a snapshot", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validateServerURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("must be an http or https URL")
+	}
+	if u.Host == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
 func (workingSet *WorkingSet) validateUniqueServerNames() error {
 	seen := make(map[string]bool)
 	for _, server := range workingSet.Servers {
@@ -345,12 +476,57 @@ func (workingSet *WorkingSet) EnsureSnapshotsResolved(ctx context.Context, ociSe
 	return nil
 }
 
+// ImageRef returns the image the server runs, if any, e.g. for image servers
+// and registry servers backed by an OCI package.
+func (s *Server) ImageRef() string {
+	if s.Image != "" {
+		return s.Image
+	}
+	if s.Snapshot != nil && s.Snapshot.Server.Type != "remote" {
+		return s.Snapshot.Server.Image
+	}
+	return ""
+}
+
+// ToolImages returns the images the tools of a poci server run.
+func (s *Server) ToolImages() []string {
+	if s.Type != ServerTypePOCI || s.Snapshot == nil {
+		return nil
+	}
+	return ServerImages(s.Snapshot.Server)
+}
+
+// ServerImages returns the images a catalog server runs: its own image, or
+// the images of its tools for a poci server, whose own image is only the one
+// they were built from. Remote servers run no image.
+func ServerImages(server catalog.Server) []string {
+	if server.Type == catalog.ServerTypePOCI {
+		var images []string
+		for _, tool := range server.Tools {
+			if tool.Container.Image != "" {
+				images = append(images, tool.Container.Image)
+			}
+		}
+		return images
+	}
+	if server.Image == "" || server.Type == "remote" {
+		return nil
+	}
+	return []string{server.Image}
+}
+
 func (s *Server) BasicName() string {
 	switch s.Type {
 	case ServerTypeImage:
 		return s.Image
 	case ServerTypeRegistry:
 		return s.Source
+	case ServerTypeCommand:
+		return s.Exec
+	case ServerTypePOCI:
+		if s.Snapshot != nil {
+			return s.Snapshot.Server.Name
+		}
 	}
 	return "unknown"
 }
@@ -372,13 +548,75 @@ func createWorkingSetID(ctx context.Context, name string, dao db.DAO) (string, e
 	return baseName, nil
 }
 
-func ResolveServersFromString(ctx context.Context, registryClient registryapi.Client, ociService oci.Service, dao db.DAO, value string) ([]Server, error) {
+// ResolveOption configures how server references are resolved.
+type ResolveOption func(*resolveOptions)
+
+type resolveOptions struct {
+	localOnly   bool
+	imagePolicy imagepolicy.Policy
+}
+
+// WithLocalOnly controls whether docker:// references are only resolved against
+// local images, e.g. in offline or air-gapped environments. By default, images
+// missing locally are fetched from their registry.
+func WithLocalOnly(localOnly bool) ResolveOption {
+	return func(o *resolveOptions) {
+		o.localOnly = localOnly
+	}
+}
+
+// WithImagePolicy rejects the servers whose image isn't allowed by policy.
+func WithImagePolicy(policy imagepolicy.Policy) ResolveOption {
+	return func(o *resolveOptions) {
+		o.imagePolicy = policy
+	}
+}
+
+func newResolveOptions(opts []ResolveOption) resolveOptions {
+	var options resolveOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func ResolveServersFromString(ctx context.Context, registryClient registryapi.Client, ociService oci.Service, dao db.DAO, value string, opts ...ResolveOption) ([]Server, error) {
+	servers, err := resolveServersFromString(ctx, registryClient, ociService, dao, value, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkImagePolicy(newResolveOptions(opts).imagePolicy, servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// checkImagePolicy returns an error when the image of one of the servers, or
+// of one of their POCI tools, isn't allowed by policy.
+func checkImagePolicy(policy imagepolicy.Policy, servers []Server) error {
+	for _, server := range servers {
+		if image := server.ImageRef(); image != "" {
+			if err := policy.Check(image); err != nil {
+				return fmt.Errorf("server %s: %w", server.BasicName(), err)
+			}
+		}
+		for _, image := range server.ToolImages() {
+			if err := policy.Check(image); err != nil {
+				return fmt.Errorf("server %s: %w", server.BasicName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func resolveServersFromString(ctx context.Context, registryClient registryapi.Client, ociService oci.Service, dao db.DAO, value string, opts ...ResolveOption) ([]Server, error) {
 	if v, ok := strings.CutPrefix(value, "docker://"); ok {
-		fullRef, err := ResolveImageRef(ctx, ociService, v)
+		fullRef, err := ResolveImageRef(ctx, ociService, v, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve image ref: %w", err)
 		}
-		serverSnapshot, err := ResolveImageSnapshot(ctx, ociService, fullRef)
+		serverSnapshot, err := ResolveImageSnapshot(ctx, ociService, fullRef, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve image snapshot: %w", err)
 		}
@@ -396,6 +634,12 @@ func ResolveServersFromString(ctx context.Context, registryClient registryapi.Cl
 			return nil, fmt.Errorf("failed to resolve registry: %w", err)
 		}
 		return []Server{server}, nil
+	} else if v, ok := strings.CutPrefix(value, "openapi://"); ok {
+		server, err := ResolveOpenAPI(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		return []Server{server}, nil
 	} else if path, ok := strings.CutPrefix(value, "file://"); ok {
 		path, err := parseLocalFileReference(path, value)
 		if err != nil {
@@ -443,6 +687,35 @@ func isV0ServerJSON(buf []byte) bool {
 	return false
 }
 
+type serverFileFormat string
+
+const (
+	serverFileYAML serverFileFormat = "yaml"
+	serverFileJSON serverFileFormat = "json"
+)
+
+// detectServerFileFormat tells whether a server file is YAML or JSON, from
+// its extension when it's known, or from its content otherwise. JSON is tried
+// first since any JSON document is also valid YAML.
+func detectServerFileFormat(path string, buf []byte) (serverFileFormat, error) {
+	switch filepath.Ext(strings.ToLower(path)) {
+	case ".yaml", ".yml":
+		return serverFileYAML, nil
+	case ".json":
+		return serverFileJSON, nil
+	}
+
+	if json.Valid(buf) {
+		return serverFileJSON, nil
+	}
+	var document map[string]any
+	if err := yaml.Unmarshal(buf, &document); err == nil && len(document) > 0 {
+		return serverFileYAML, nil
+	}
+
+	return "", fmt.Errorf("unsupported file format: %s is neither YAML nor JSON", filepath.Base(path))
+}
+
 func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 	path, err := catalog.ResolveLocalCatalogPath(value)
 	if err != nil {
@@ -460,9 +733,14 @@ func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 		Registry map[string]catalog.Server `yaml:"registry,omitempty" json:"registry,omitempty"`
 	}
 
+	format, err := detectServerFileFormat(path, buf)
+	if err != nil {
+		return nil, err
+	}
+
 	var servers []catalog.Server
-	switch filepath.Ext(strings.ToLower(path)) {
-	case ".yaml", ".yml":
+	switch format {
+	case serverFileYAML:
 		if err := yaml.Unmarshal(buf, &probe); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal server: %w", err)
 		}
@@ -474,7 +752,7 @@ func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 			}
 			servers = []catalog.Server{server}
 		}
-	case ".json":
+	case serverFileJSON:
 		if err := json.Unmarshal(buf, &probe); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal server: %w", err)
 		}
@@ -516,8 +794,6 @@ func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 				servers = []catalog.Server{server}
 			}
 		}
-	default:
-		return nil, fmt.Errorf("unsupported file extension: must be .yaml, .yml, or .json")
 	}
 
 	if probe.Registry != nil {
@@ -529,7 +805,16 @@ func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 
 	serversResolved := make([]Server, len(servers))
 	for i, server := range servers {
-		if (server.Type == "server" || server.Type == "poci") && server.Image != "" {
+		if server.Type == catalog.ServerTypePOCI {
+			if len(ServerImages(server)) == 0 {
+				return nil, fmt.Errorf("poci server %s has no tool with a container image", server.Name)
+			}
+			serversResolved[i] = Server{
+				Type:     ServerTypePOCI,
+				Secrets:  "default",
+				Snapshot: &ServerSnapshot{Server: server},
+			}
+		} else if server.Type == "server" && server.Image != "" {
 			serversResolved[i] = Server{
 				Type:     ServerTypeImage,
 				Image:    server.Image,
@@ -543,6 +828,17 @@ func ResolveFile(ctx context.Context, value string) ([]Server, error) {
 				Secrets:  "default",
 				Snapshot: &ServerSnapshot{Server: server},
 			}
+		} else if server.Type == "command" {
+			if len(server.Command) == 0 || server.Command[0] == "" {
+				return nil, fmt.Errorf("command server %s has no command", server.Name)
+			}
+			serversResolved[i] = Server{
+				Type:     ServerTypeCommand,
+				Exec:     server.Command[0],
+				Args:     server.Command[1:],
+				Secrets:  "default",
+				Snapshot: &ServerSnapshot{Server: server},
+			}
 		} else {
 			return nil, fmt.Errorf("unsupported server type: %s", server.Type)
 		}
@@ -559,17 +855,46 @@ func ResolveCatalogServers(ctx context.Context, dao db.DAO, value string) ([]Ser
 	catalogRef := strings.Join(parts[:len(parts)-1], "/")
 	serverList := parts[len(parts)-1]
 
-	serverNames := strings.Split(serverList, "+")
+	// Patterns prefixed with "!" exclude the servers they match from the selection.
+	var serverNames, excludedPatterns []string
+	for _, pattern := range strings.Split(serverList, "+") {
+		if excluded, ok := strings.CutPrefix(pattern, "!"); ok {
+			if excluded == "" {
+				return nil, fmt.Errorf("empty exclusion pattern in catalog URL: catalog://%s", value)
+			}
+			if _, err := path.Match(excluded, ""); err != nil {
+				return nil, fmt.Errorf("bad exclusion pattern for catalog server '%s': %w", excluded, err)
+			}
+			excludedPatterns = append(excludedPatterns, excluded)
+			continue
+		}
+		serverNames = append(serverNames, pattern)
+	}
+	if len(serverNames) == 0 && len(excludedPatterns) > 0 {
+		// Only exclusions, e.g. catalog://ref/!deprecated-*, select everything else.
+		serverNames = []string{"*"}
+	}
 
 	if len(serverNames) == 0 || len(serverList) == 0 {
 		return nil, fmt.Errorf("no servers specified in catalog URL: catalog://%s", value)
 	}
 
-	ref, err := name.ParseReference(catalogRef)
+	// A catalog can be pinned to a digest with catalog://<ref>@sha256:<hex>/<servers>
+	catalogRef, pinnedDigest, pinned := strings.Cut(catalogRef, "@")
+	if pinned {
+		if _, err := v1.NewHash(pinnedDigest); err != nil {
+			return nil, fmt.Errorf("invalid digest %s for catalog %s: %w", pinnedDigest, catalogRef, err)
+		}
+	}
+
+	catalogRef, err := db.ResolveCatalogAlias(ctx, dao, catalogRef)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse catalog reference %s: %w", catalogRef, err)
+		return nil, err
+	}
+	catalogRef, err = oci.NormalizeCatalogRef(catalogRef)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog reference: %w", err)
 	}
-	catalogRef = oci.FullNameWithoutDigest(ref)
 
 	dbCatalog, err := dao.GetCatalog(ctx, catalogRef)
 	if err != nil {
@@ -579,13 +904,35 @@ func ResolveCatalogServers(ctx context.Context, dao db.DAO, value string) ([]Ser
 		return nil, fmt.Errorf("failed to get catalog: %w", err)
 	}
 
+	if pinned && dbCatalog.Digest != pinnedDigest {
+		return nil, fmt.Errorf("catalog %s has digest %s, expected %s: pull the pinned version of the catalog or update the digest", catalogRef, dbCatalog.Digest, pinnedDigest)
+	}
+
+	dbCatalog, err = db.ResolveCatalogBase(ctx, dao, dbCatalog)
+	if err != nil {
+		return nil, err
+	}
+
+	// An ID shared by several servers, e.g. running the same image, is an
+	// error rather than a selection of all of them.
+	for _, name := range serverNames {
+		var matches []string
+		for _, server := range dbCatalog.Servers {
+			if server.StableID != "" && server.StableID == name {
+				matches = append(matches, server.Snapshot.Server.Name)
+			}
+		}
+		if len(matches) > 1 {
+			return nil, fmt.Errorf("server ID %s is ambiguous, it identifies the servers %s: select one by name", name, strings.Join(matches, ", "))
+		}
+	}
+
 	filteredServers := make([]db.CatalogServer, 0, len(dbCatalog.Servers))
 	foundPatterns := make(map[string]bool)
 	foundServers := make(map[string]bool) // avoid duplicates
 	for _, name := range serverNames {
 		for _, server := range dbCatalog.Servers {
-			// Glob support for selecting servers by name
-			matched, err := path.Match(name, server.Snapshot.Server.Name)
+			matched, err := matchCatalogServer(name, server)
 			if err != nil {
 				return nil, fmt.Errorf("bad pattern for catalog server '%s': %w", name, err)
 			}
@@ -600,6 +947,16 @@ func ResolveCatalogServers(ctx context.Context, dao db.DAO, value string) ([]Ser
 		}
 	}
 
+	if len(excludedPatterns) > 0 {
+		filteredServers = slices.DeleteFunc(filteredServers, func(server db.CatalogServer) bool {
+			return slices.ContainsFunc(excludedPatterns, func(pattern string) bool {
+				// Patterns were validated above
+				matched, _ := matchCatalogServer(pattern, server)
+				return matched
+			})
+		})
+	}
+
 	uniqueServerNames := make(map[string]bool)
 	for _, serverName := range serverNames {
 		uniqueServerNames[serverName] = true
@@ -611,13 +968,49 @@ func ResolveCatalogServers(ctx context.Context, dao db.DAO, value string) ([]Ser
 			f = append(f, pattern)
 		}
 		missingPatterns := sliceutil.Difference(serverNames, f)
-		return nil, fmt.Errorf("servers matching the following patterns were not found in catalog: %v", missingPatterns)
+		return nil, fmt.Errorf("servers matching the following patterns were not found in catalog: %v%s", missingPatterns, catalogServerSuggestions(missingPatterns, dbCatalog.Servers))
 	}
 
 	return mapCatalogServersToWorkingSetServers(filteredServers, "default"), nil
 }
 
-func ResolveImageRef(ctx context.Context, ociService oci.Service, value string) (string, error) {
+// matchCatalogServer tells whether a catalog server is selected by a glob
+// pattern on its name, or by its stable ID, which doesn't change when the
+// server is renamed.
+func matchCatalogServer(pattern string, server db.CatalogServer) (bool, error) {
+	if server.StableID != "" && pattern == server.StableID {
+		return true, nil
+	}
+	return path.Match(pattern, server.Snapshot.Server.Name)
+}
+
+// catalogServerSuggestions lists the catalog servers closest to each missing
+// server name, to help recovering from typos. Glob patterns are skipped.
+func catalogServerSuggestions(missingPatterns []string, servers []db.CatalogServer) string {
+	catalogServerNames := make([]string, 0, len(servers))
+	for _, server := range servers {
+		catalogServerNames = append(catalogServerNames, server.Snapshot.Server.Name)
+	}
+
+	var hints []string
+	for _, pattern := range missingPatterns {
+		if strings.ContainsAny(pattern, "*?[\\") {
+			continue
+		}
+		if closest := suggest.Closest(pattern, catalogServerNames, 3); len(closest) > 0 {
+			hints = append(hints, fmt.Sprintf("%s: did you mean %s?", pattern, strings.Join(closest, ", ")))
+		}
+	}
+	if len(hints) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(hints, "; ") + ")"
+}
+
+// ResolveImageRef resolves an image reference, falling back to the registry
+// when the image is missing locally, unless WithLocalOnly is set.
+func ResolveImageRef(ctx context.Context, ociService oci.Service, value string, opts ...ResolveOption) (string, error) {
+	options := newResolveOptions(opts)
 	ref, err := name.ParseReference(value)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse reference: %w", err)
@@ -625,6 +1018,9 @@ func ResolveImageRef(ctx context.Context, ociService oci.Service, value string)
 	isRemote := false
 	img, err := ociService.GetLocalImage(ctx, ref)
 	if oci.IsNoSuchImageError(err) {
+		if options.localOnly {
+			return "", fmt.Errorf("image %s not found locally", ref.String())
+		}
 		img, err = ociService.GetRemoteImage(ctx, ref)
 		isRemote = true
 	}
@@ -691,6 +1087,9 @@ func ResolveRegistry(ctx context.Context, registryClient registryapi.Client, val
 
 	// Check for OCI packages and convert to catalog format
 	catalogServer, _, err := ConvertRegistryServerToCatalog(ctx, serverResp, catalog.WithPyPIResolver(catalog.DefaultPyPIVersionResolver()), catalog.WithNPMResolver(catalog.DefaultNPMVersionResolver()))
+	if errors.Is(err, catalog.ErrIncompatibleServer) {
+		return Server{}, &IncompatibleServerError{Ref: value, Reason: incompatibleReason(serverResp.Server)}
+	}
 	if err != nil {
 		return Server{}, fmt.Errorf("failed to convert registry server: %w", err)
 	}
@@ -703,6 +1102,34 @@ func ResolveRegistry(ctx context.Context, registryClient registryapi.Client, val
 	}, nil
 }
 
+// IncompatibleServerError is returned when a server reference resolves to a
+// registry entry that has no package type or remote the gateway can run.
+type IncompatibleServerError struct {
+	Ref    string
+	Reason string
+}
+
+func (e *IncompatibleServerError) Error() string {
+	return fmt.Sprintf("server %s is incompatible: %s", e.Ref, e.Reason)
+}
+
+func (e *IncompatibleServerError) Unwrap() error {
+	return catalog.ErrIncompatibleServer
+}
+
+// incompatibleReason describes why TransformToDocker rejected a registry server.
+func incompatibleReason(server v0.ServerJSON) string {
+	if len(server.Packages) == 0 {
+		return "no packages or remotes"
+	}
+
+	var packages []string
+	for _, pkg := range server.Packages {
+		packages = append(packages, fmt.Sprintf("%s package over %s", pkg.RegistryType, pkg.Transport.Type))
+	}
+	return "no stdio oci, pypi or npm package and no remote (found " + strings.Join(packages, ", ") + ")"
+}
+
 func ResolveSnapshot(ctx context.Context, ociService oci.Service, server Server) (*ServerSnapshot, error) {
 	switch server.Type {
 	case ServerTypeImage:
@@ -713,19 +1140,23 @@ func ResolveSnapshot(ctx context.Context, ociService oci.Service, server Server)
 	case ServerTypeRemote:
 		// TODO(bobby): add snapshot when you can add remotes directly from URL
 		return nil, nil //nolint:nilnil
+	case ServerTypeCommand, ServerTypePOCI:
+		// Snapshots for command and poci servers are resolved during ResolveFile
+		return nil, nil //nolint:nilnil
 	}
 	return nil, fmt.Errorf("unsupported server type: %s", server.Type)
 }
 
-func ResolveImageSnapshot(ctx context.Context, ociService oci.Service, image string) (*ServerSnapshot, error) {
+func ResolveImageSnapshot(ctx context.Context, ociService oci.Service, image string, opts ...ResolveOption) (*ServerSnapshot, error) {
+	options := newResolveOptions(opts)
 	ref, err := name.ParseReference(image)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse reference: %w", err)
 	}
 
 	var img v1.Image
-	// Anything with a digest should be a remote image
-	if oci.HasDigest(ref) {
+	// Anything with a digest should be a remote image, unless only local images are allowed
+	if oci.HasDigest(ref) && !options.localOnly {
 		img, err = ociService.GetRemoteImage(ctx, ref)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get remote image: %w", err)
@@ -783,13 +1214,14 @@ func mapCatalogServersToWorkingSetServers(dbServers []db.CatalogServer, secrets
 	servers := make([]Server, len(dbServers))
 	for i, server := range dbServers {
 		servers[i] = Server{
-			Type:       ServerType(server.ServerType),
-			Tools:      ToolList(server.Tools),
-			Config:     map[string]any{},
-			Source:     server.Source,
-			Image:      server.Image,
-			Endpoint:   server.Endpoint,
-			CatalogRef: server.CatalogRef,
+			Type:              ServerType(server.ServerType),
+			Tools:             ToolList(server.Tools),
+			Config:            map[string]any{},
+			Source:            server.Source,
+			Image:             server.Image,
+			Endpoint:          server.Endpoint,
+			CatalogRef:        server.CatalogRef,
+			LongLivedOverride: server.LongLivedOverride,
 			Snapshot: &ServerSnapshot{
 				Server: server.Snapshot.Server,
 			},
@@ -1,12 +1,15 @@
 package workingset
 
 import (
+	"context"
 	"embed"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -151,6 +154,50 @@ func TestWorkingSetRoundTrip(t *testing.T) {
 	assert.Equal(t, original.Secrets, roundTripped.Secrets)
 }
 
+func TestLongLivedOverrideRoundTrip(t *testing.T) {
+	ephemeral := false
+	workingSet := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "test-id",
+		Name:    "Test Working Set",
+		Servers: []Server{
+			{Type: ServerTypeImage, Image: "mcp/github", LongLivedOverride: &ephemeral},
+			{Type: ServerTypeImage, Image: "mcp/fetch"},
+		},
+		Secrets: map[string]Secret{},
+	}
+
+	dbSet := workingSet.ToDb()
+	require.NotNil(t, dbSet.Servers[0].LongLivedOverride)
+	assert.False(t, *dbSet.Servers[0].LongLivedOverride)
+	assert.Nil(t, dbSet.Servers[1].LongLivedOverride)
+
+	roundTripped := NewFromDb(&dbSet)
+	assert.Equal(t, &ephemeral, roundTripped.Servers[0].LongLivedOverride)
+	assert.Nil(t, roundTripped.Servers[1].LongLivedOverride)
+}
+
+func TestToolResultTransformsRoundTrip(t *testing.T) {
+	workingSet := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "test-id",
+		Name:    "Test Working Set",
+		Servers: []Server{
+			{Type: ServerTypeImage, Image: "mcp/github", ToolResultTransforms: map[string]string{"search_issues": ".items"}},
+			{Type: ServerTypeImage, Image: "mcp/fetch"},
+		},
+		Secrets: map[string]Secret{},
+	}
+
+	dbSet := workingSet.ToDb()
+	assert.Equal(t, map[string]string{"search_issues": ".items"}, dbSet.Servers[0].ToolResultTransforms)
+	assert.Nil(t, dbSet.Servers[1].ToolResultTransforms)
+
+	roundTripped := NewFromDb(&dbSet)
+	assert.Equal(t, map[string]string{"search_issues": ".items"}, roundTripped.Servers[0].ToolResultTransforms)
+	assert.Nil(t, roundTripped.Servers[1].ToolResultTransforms)
+}
+
 func TestNewFromDbWithRemoteServer(t *testing.T) {
 	dbSet := &db.WorkingSet{
 		ID:   "test-remote-id",
@@ -232,6 +279,32 @@ func TestWorkingSetRoundTripWithRemoteServer(t *testing.T) {
 	assert.Equal(t, original.Secrets, roundTripped.Secrets)
 }
 
+func TestWorkingSetRoundTripWithCommandServer(t *testing.T) {
+	original := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "test-command-id",
+		Name:    "Test Command Working Set",
+		Servers: []Server{
+			{
+				Type:  ServerTypeCommand,
+				Exec:  "./bin/my-mcp",
+				Args:  []string{"--stdio", "--verbose"},
+				Tools: []string{"tool1"},
+			},
+		},
+		Secrets: map[string]Secret{},
+	}
+
+	dbSet := original.ToDb()
+	assert.Equal(t, "command", dbSet.Servers[0].Type)
+	assert.Equal(t, "./bin/my-mcp", dbSet.Servers[0].Exec)
+	assert.Equal(t, []string{"--stdio", "--verbose"}, dbSet.Servers[0].Args)
+
+	roundTripped := NewFromDb(&dbSet)
+	assert.Equal(t, original.Servers, roundTripped.Servers)
+	assert.Equal(t, "./bin/my-mcp", roundTripped.Servers[0].BasicName())
+}
+
 func TestWorkingSetWithMixedServerTypes(t *testing.T) {
 	workingSet := WorkingSet{
 		Version: CurrentWorkingSetVersion,
@@ -335,6 +408,22 @@ func TestWorkingSetValidate(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "valid command server",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type: ServerTypeCommand,
+						Exec: "./bin/my-mcp",
+						Args: []string{"--stdio"},
+					},
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "missing version",
 			ws: WorkingSet{
@@ -404,6 +493,96 @@ func TestWorkingSetValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "image server with malformed image reference",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type:  ServerTypeImage,
+						Image: "docker/Test::latest",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "registry server with invalid source URL",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type:   ServerTypeRegistry,
+						Source: "not a url",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "remote server with non-http endpoint",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type:     ServerTypeRemote,
+						Endpoint: "ftp://mcp.example.com/sse",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "remote server with endpoint missing host",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type:     ServerTypeRemote,
+						Endpoint: "https:///sse",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "image server with digest reference",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type:  ServerTypeImage,
+						Image: "mcp/fetch@sha256:68eb20db6109f5c312a695fc5ec3386ad15d93ffb765a0b4eb1baf4328dec14f",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "command server missing exec",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Servers: []Server{
+					{
+						Type: ServerTypeCommand,
+						Args: []string{"--stdio"},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name: "invalid server type",
 			ws: WorkingSet{
@@ -482,6 +661,37 @@ func TestWorkingSetValidate(t *testing.T) {
 	}
 }
 
+func TestWorkingSetValidateReportsBadReference(t *testing.T) {
+	ws := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "test-id",
+		Name:    "Test",
+		Servers: []Server{
+			{Type: ServerTypeImage, Image: "docker/test:latest"},
+			{Type: ServerTypeImage, Image: "docker/Test::latest"},
+		},
+	}
+
+	err := ws.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `server[1] has invalid image reference "docker/Test::latest"`)
+}
+
+func TestWorkingSetValidateReportsBadToolResultTransform(t *testing.T) {
+	ws := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "test-id",
+		Name:    "Test",
+		Servers: []Server{
+			{Type: ServerTypeImage, Image: "docker/test:latest", ToolResultTransforms: map[string]string{"search_issues": ".items | map("}},
+		},
+	}
+
+	err := ws.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server[0] has invalid result transform for tool search_issues")
+}
+
 func TestValidateServerSnapshot(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1265,6 +1475,83 @@ func TestResolveFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "valid command yaml file",
+			file:  "command.yaml",
+			input: "file://testdata/command.yaml",
+			expected: []Server{
+				{
+					Type:    ServerTypeCommand,
+					Exec:    "./bin/my-mcp",
+					Args:    []string{"--stdio"},
+					Secrets: "default",
+					Snapshot: &ServerSnapshot{
+						Server: catalog.Server{
+							Name:        "my-dev-mcp",
+							Type:        "command",
+							Command:     []string{"./bin/my-mcp", "--stdio"},
+							Description: "Server that runs my MCP code locally",
+							Title:       "My Dev MCP",
+							Env: []catalog.Env{
+								{
+									Name:  "MODE",
+									Value: "{{my-dev-mcp.mode}}",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "command yaml file without command",
+			file:        "command-missing.yaml",
+			input:       "file://testdata/command-missing.yaml",
+			expectedErr: "command server my-dev-mcp has no command",
+		},
+		{
+			name:  "valid poci yaml file",
+			file:  "poci.yaml",
+			input: "file://testdata/poci.yaml",
+			expected: []Server{
+				{
+					Type:    ServerTypePOCI,
+					Secrets: "default",
+					Snapshot: &ServerSnapshot{
+						Server: catalog.Server{
+							Name:        "my-tools",
+							Type:        "poci",
+							Image:       "my-tools-builder:latest",
+							Description: "Tools that each run their own container",
+							Title:       "My Tools",
+							Tools: []catalog.Tool{
+								{
+									Name:        "curl",
+									Description: "Fetch a URL",
+									Container: catalog.Container{
+										Image:   "curlimages/curl:8.10.1",
+										Command: []string{"{{url}}"},
+									},
+									Parameters: catalog.Parameters{
+										Type: "object",
+										Properties: catalog.Properties{
+											"url": {Type: "string", Description: "The URL to fetch"},
+										},
+										Required: []string{"url"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "poci yaml file without tool containers",
+			file:        "poci-missing-container.yaml",
+			input:       "file://testdata/poci-missing-container.yaml",
+			expectedErr: "poci server my-tools has no tool with a container image",
+		},
 		{
 			name:        "invalid yaml file",
 			file:        "invalid-yaml.yaml",
@@ -1305,6 +1592,53 @@ func TestResolveFile(t *testing.T) {
 	}
 }
 
+func TestResolveFileSniffsContentWithoutKnownExtension(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		reference string
+	}{
+		{name: "extensionless json file", file: "server-json", reference: "server.json"},
+		{name: "extensionless yaml file", file: "server-yaml", reference: "server.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempHome := t.TempDir()
+			t.Setenv("HOME", tempHome)
+
+			for _, file := range []string{tt.file, tt.reference} {
+				content, err := testData.ReadFile("testdata/" + file)
+				require.NoError(t, err)
+				tempFile := filepath.Join(tempHome, ".docker", "mcp", "catalogs", "testdata", file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(tempFile), 0o755))
+				require.NoError(t, os.WriteFile(tempFile, content, 0o644))
+			}
+
+			expected, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), setupTestDB(t), "file://testdata/"+tt.reference)
+			require.NoError(t, err)
+
+			servers, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), setupTestDB(t), "file://testdata/"+tt.file)
+			require.NoError(t, err)
+			require.Equal(t, expected, servers)
+		})
+	}
+}
+
+func TestResolveFileRejectsUnknownContent(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+
+	content, err := testData.ReadFile("testdata/notes.txt")
+	require.NoError(t, err)
+	tempFile := filepath.Join(tempHome, ".docker", "mcp", "catalogs", "testdata", "notes.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(tempFile), 0o755))
+	require.NoError(t, os.WriteFile(tempFile, content, 0o644))
+
+	_, err = ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), mocks.NewMockOCIService(), setupTestDB(t), "file://testdata/notes.txt")
+	require.ErrorContains(t, err, "unsupported file format: notes.txt is neither YAML nor JSON")
+}
+
 func TestResolveFileDecodesEscapedLocalReference(t *testing.T) {
 	tempHome := t.TempDir()
 	t.Setenv("HOME", tempHome)
@@ -1432,6 +1766,92 @@ func TestResolveServerFromStringResolvesLatestVersion(t *testing.T) {
 	assert.Equal(t, "https://example.com/v0/servers/my-server/versions/0.2.0", server[0].Source)
 }
 
+// remoteCountingOCIService counts the images fetched from a registry.
+type remoteCountingOCIService struct {
+	oci.Service
+	remoteCalls int
+}
+
+func (s *remoteCountingOCIService) GetRemoteImage(ctx context.Context, ref name.Reference) (v1.Image, error) {
+	s.remoteCalls++
+	return s.Service.GetRemoteImage(ctx, ref)
+}
+
+func TestResolveServersFromStringLocalOnly(t *testing.T) {
+	ociService := &remoteCountingOCIService{
+		Service: mocks.NewMockOCIService(
+			mocks.WithLocalImages([]mocks.MockImage{
+				{
+					Ref: "myimage:latest",
+					Labels: map[string]string{
+						"io.docker.server.metadata": "name: My Image\ntype: server\nimage: myimage:latest",
+					},
+					DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+				},
+			}),
+			mocks.WithRemoteImages([]mocks.MockImage{
+				{
+					Ref: "remoteimage:latest",
+					Labels: map[string]string{
+						"io.docker.server.metadata": "name: Remote Image\ntype: server\nimage: remoteimage:latest",
+					},
+					DigestString: "sha256:abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				},
+			}),
+		),
+	}
+
+	t.Run("local image", func(t *testing.T) {
+		servers, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), ociService, setupTestDB(t), "docker://myimage:latest", WithLocalOnly(true))
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		assert.Equal(t, "myimage:latest", servers[0].Image)
+		assert.Equal(t, "My Image", servers[0].Snapshot.Server.Name)
+		assert.Zero(t, ociService.remoteCalls)
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		_, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), ociService, setupTestDB(t), "docker://remoteimage:latest", WithLocalOnly(true))
+		require.ErrorContains(t, err, "not found locally")
+		assert.Zero(t, ociService.remoteCalls)
+	})
+
+	t.Run("missing image without local only", func(t *testing.T) {
+		_, err := ResolveImageRef(t.Context(), ociService, "remoteimage:latest")
+		require.NoError(t, err)
+		assert.Equal(t, 1, ociService.remoteCalls)
+	})
+}
+
+func TestResolveServersFromStringPrivateImage(t *testing.T) {
+	privateImage := mocks.MockImage{
+		Ref: "registry.example.com/private/server:latest",
+		Labels: map[string]string{
+			"io.docker.server.metadata": "name: Private Server\ntype: server\nimage: registry.example.com/private/server:latest",
+		},
+		DigestString: "sha256:abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+	}
+
+	t.Run("without credentials", func(t *testing.T) {
+		ociService := mocks.NewMockOCIService(mocks.WithPrivateRemoteImages([]mocks.MockImage{privateImage}))
+
+		_, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), ociService, setupTestDB(t), "docker://registry.example.com/private/server:latest")
+		require.ErrorIs(t, err, oci.ErrUnauthorized)
+		require.ErrorContains(t, err, "registry authentication required to pull registry.example.com/private/server:latest: log in with `docker login registry.example.com`, or pass a Docker config with its credentials with --registry-auth")
+		assert.NotContains(t, err.Error(), "no such image")
+	})
+
+	t.Run("with credentials", func(t *testing.T) {
+		ociService := mocks.NewMockOCIService(mocks.WithPrivateRemoteImages([]mocks.MockImage{privateImage}), mocks.WithRegistryCredentials())
+
+		servers, err := ResolveServersFromString(t.Context(), mocks.NewMockRegistryAPIClient(), ociService, setupTestDB(t), "docker://registry.example.com/private/server:latest")
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		assert.Equal(t, "registry.example.com/private/server:latest@"+privateImage.DigestString, servers[0].Image)
+		assert.Equal(t, "Private Server", servers[0].Snapshot.Server.Name)
+	})
+}
+
 func TestResolveSnapshot(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1694,3 +2114,21 @@ func TestIsV0ServerJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestServerImages(t *testing.T) {
+	assert.Equal(t, []string{"mcp/server"}, ServerImages(catalog.Server{Type: "server", Image: "mcp/server"}))
+	assert.Nil(t, ServerImages(catalog.Server{Type: "remote", Image: "mcp/server"}))
+	assert.Nil(t, ServerImages(catalog.Server{Type: "server"}))
+
+	// A poci server runs the images of its tools, not the one it was built from.
+	poci := catalog.Server{
+		Type:  catalog.ServerTypePOCI,
+		Image: "mcp/base",
+		Tools: []catalog.Tool{
+			{Name: "one", Container: catalog.Container{Image: "mcp/one"}},
+			{Name: "builtin"},
+			{Name: "two", Container: catalog.Container{Image: "mcp/two"}},
+		},
+	}
+	assert.Equal(t, []string{"mcp/one", "mcp/two"}, ServerImages(poci))
+}
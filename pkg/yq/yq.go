@@ -63,3 +63,14 @@ func Evaluate(yqExpr string, content []byte, decoder yqlib.Decoder, encoder yqli
 	result = strings.TrimSpace(result)
 	return []byte(result), nil
 }
+
+// Validate checks the syntax of a YQ expression without evaluating it.
+func Validate(yqExpr string) error {
+	yqlib.GetLogger().SetBackend(logBackend{})
+	yqlib.InitExpressionParser()
+
+	if _, err := yqlib.ExpressionParser.ParseExpression(yqExpr); err != nil {
+		return fmt.Errorf("invalid YQ expression '%s': %w", yqExpr, err)
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/opencontainers/go-digest"
 
@@ -15,8 +17,10 @@ import (
 type MockOCIServiceOption func(*MockOCIServiceOptions)
 
 type MockOCIServiceOptions struct {
-	localImages  []MockImage
-	remoteImages []MockImage
+	localImages   []MockImage
+	remoteImages  []MockImage
+	privateImages []MockImage
+	authorized    bool
 }
 
 func WithLocalImages(localImages []MockImage) MockOCIServiceOption {
@@ -32,6 +36,23 @@ func WithRemoteImages(remoteImages []MockImage) MockOCIServiceOption {
 	}
 }
 
+// WithPrivateRemoteImages adds remote-only images whose registry requires
+// credentials: GetRemoteImage fails with an oci.ErrUnauthorized unless
+// WithRegistryCredentials is set.
+func WithPrivateRemoteImages(privateImages []MockImage) MockOCIServiceOption {
+	return func(o *MockOCIServiceOptions) {
+		o.privateImages = privateImages
+	}
+}
+
+// WithRegistryCredentials simulates credentials granting access to the
+// private remote images.
+func WithRegistryCredentials() MockOCIServiceOption {
+	return func(o *MockOCIServiceOptions) {
+		o.authorized = true
+	}
+}
+
 type mockOCIService struct {
 	options MockOCIServiceOptions
 }
@@ -86,15 +107,41 @@ func (s *mockOCIService) GetRemoteImage(_ context.Context, ref name.Reference) (
 			return &img, nil
 		}
 	}
+	for _, img := range s.options.privateImages {
+		if img.Ref == refStr || img.Ref+"@"+img.DigestString == refStr {
+			if !s.options.authorized {
+				return nil, oci.WrapAuthError(ref, &transport.Error{
+					StatusCode: http.StatusUnauthorized,
+					Errors:     []transport.Diagnostic{{Code: transport.UnauthorizedErrorCode, Message: "authentication required"}},
+				})
+			}
+			return &img, nil
+		}
+	}
 
 	return nil, fmt.Errorf("no such image: %s", refStr)
 }
 
+func (s *mockOCIService) GetRemoteDigest(ctx context.Context, ref name.Reference) (string, error) {
+	img, err := s.GetRemoteImage(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	mockImg := img.(*MockImage)
+	if mockImg.IndexDigest != "" {
+		return mockImg.IndexDigest, nil
+	}
+	return mockImg.DigestString, nil
+}
+
 // MockImage is a minimal implementation of v1.Image for testing
 type MockImage struct {
 	Ref          string
 	Labels       map[string]string
 	DigestString string
+	// IndexDigest is the digest of the index of a multi-platform image,
+	// DigestString being the one of the image of the current platform.
+	IndexDigest string
 }
 
 var _ v1.Image = &MockImage{}